@@ -2,6 +2,7 @@ import Turbolinks from "turbolinks";
 import { init } from "./core";
 import { setupIntlTelInput } from "./intlTelInput";
 import { setupPasswordPolicy } from "./password-policy";
+import { setupPasskey } from "./passkey";
 import {
   clickLinkSubmitForm,
   autoSubmitForm,
@@ -23,6 +24,8 @@ window.api.onLoad(setupPasswordPolicy);
 
 window.api.onLoad(setupIntlTelInput);
 
+window.api.onLoad(setupPasskey);
+
 window.api.onLoad(setupSelectEmptyValue);
 window.api.onLoad(setupGenderSelect);
 
@@ -0,0 +1,150 @@
+// Passkey registration and authentication.
+// The server renders the options of navigator.credentials.create() and
+// navigator.credentials.get() as JSON in a data attribute of the submit button.
+// Binary fields are encoded in base64url in both directions.
+
+function base64URLToArrayBuffer(s: string): ArrayBuffer {
+  const base64 = s.replace(/-/g, "+").replace(/_/g, "/");
+  const padded = base64 + "===".slice((base64.length + 3) % 4);
+  const binary = atob(padded);
+  const bytes = new Uint8Array(binary.length);
+  for (let i = 0; i < binary.length; i++) {
+    bytes[i] = binary.charCodeAt(i);
+  }
+  return bytes.buffer;
+}
+
+function arrayBufferToBase64URL(buf: ArrayBuffer): string {
+  const bytes = new Uint8Array(buf);
+  let binary = "";
+  for (let i = 0; i < bytes.length; i++) {
+    binary += String.fromCharCode(bytes[i]);
+  }
+  return btoa(binary)
+    .replace(/\+/g, "-")
+    .replace(/\//g, "_")
+    .replace(/=+$/, "");
+}
+
+function decodeCredentialDescriptors(
+  descriptors: any[] | undefined
+): PublicKeyCredentialDescriptor[] | undefined {
+  if (descriptors == null) {
+    return undefined;
+  }
+  return descriptors.map((d) => ({
+    type: d.type,
+    id: base64URLToArrayBuffer(d.id),
+  }));
+}
+
+async function createPasskey(optionsJSON: string): Promise<string> {
+  const options = JSON.parse(optionsJSON);
+  const publicKey: PublicKeyCredentialCreationOptions = {
+    ...options,
+    challenge: base64URLToArrayBuffer(options.challenge),
+    user: {
+      ...options.user,
+      id: base64URLToArrayBuffer(options.user.id),
+    },
+    excludeCredentials: decodeCredentialDescriptors(
+      options.excludeCredentials
+    ),
+  };
+  const credential = (await navigator.credentials.create({
+    publicKey,
+  })) as PublicKeyCredential;
+  const response = credential.response as AuthenticatorAttestationResponse;
+  return JSON.stringify({
+    id: credential.id,
+    rawId: arrayBufferToBase64URL(credential.rawId),
+    type: credential.type,
+    response: {
+      clientDataJSON: arrayBufferToBase64URL(response.clientDataJSON),
+      attestationObject: arrayBufferToBase64URL(response.attestationObject),
+    },
+  });
+}
+
+async function getPasskey(optionsJSON: string): Promise<string> {
+  const options = JSON.parse(optionsJSON);
+  const publicKey: PublicKeyCredentialRequestOptions = {
+    ...options,
+    challenge: base64URLToArrayBuffer(options.challenge),
+    allowCredentials: decodeCredentialDescriptors(options.allowCredentials),
+  };
+  const credential = (await navigator.credentials.get({
+    publicKey,
+  })) as PublicKeyCredential;
+  const response = credential.response as AuthenticatorAssertionResponse;
+  return JSON.stringify({
+    id: credential.id,
+    rawId: arrayBufferToBase64URL(credential.rawId),
+    type: credential.type,
+    response: {
+      clientDataJSON: arrayBufferToBase64URL(response.clientDataJSON),
+      authenticatorData: arrayBufferToBase64URL(response.authenticatorData),
+      signature: arrayBufferToBase64URL(response.signature),
+      userHandle:
+        response.userHandle != null
+          ? arrayBufferToBase64URL(response.userHandle)
+          : undefined,
+    },
+  });
+}
+
+// setupPasskey intercepts the click of the submit button,
+// performs the ceremony, fills in the response and then submits the form.
+export function setupPasskey(): () => void {
+  const disposers: Array<() => void> = [];
+
+  const ceremonies: Array<[string, (optionsJSON: string) => Promise<string>]> =
+    [
+      ["data-passkey-creation-options", createPasskey],
+      ["data-passkey-request-options", getPasskey],
+    ];
+
+  for (const [attr, ceremony] of ceremonies) {
+    const elems = document.querySelectorAll(`[${attr}]`);
+    for (let i = 0; i < elems.length; i++) {
+      const button = elems[i] as HTMLButtonElement;
+      const input = document.querySelector(
+        `[name="${button.getAttribute("data-passkey-response-input")}"]`
+      );
+      if (!(input instanceof HTMLInputElement)) {
+        continue;
+      }
+
+      const onClick = (e: Event) => {
+        if (input.value !== "") {
+          // The ceremony is done. Let the form submit.
+          return;
+        }
+        e.preventDefault();
+        e.stopPropagation();
+
+        const optionsJSON = button.getAttribute(attr) ?? "";
+        ceremony(optionsJSON)
+          .then((response) => {
+            input.value = response;
+            button.click();
+          })
+          .catch(() => {
+            // The user cancelled, or the authenticator failed.
+            // Do nothing so that the user can retry.
+          });
+      };
+
+      button.addEventListener("click", onClick);
+      disposers.push(() => {
+        button.removeEventListener("click", onClick);
+      });
+    }
+  }
+
+  return () => {
+    for (const disposer of disposers) {
+      disposer();
+    }
+  };
+}
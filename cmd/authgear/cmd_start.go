@@ -9,7 +9,7 @@ import (
 )
 
 var cmdStart = &cobra.Command{
-	Use:   "start [main|resolver|admin]...",
+	Use:   "start [main|resolver|admin|worker]...",
 	Short: "Start specified servers",
 	Run: func(cmd *cobra.Command, args []string) {
 		ctrl := &server.Controller{}
@@ -27,6 +27,10 @@ var cmdStart = &cobra.Command{
 				ctrl.ServeResolver = true
 			case "admin":
 				ctrl.ServeAdmin = true
+			case "worker":
+				// The worker runs the periodic tasks,
+				// it is not started by default.
+				ctrl.RunWorker = true
 			default:
 				log.Fatalf("unknown server type: %s", typ)
 			}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	cmduserimport "github.com/authgear/authgear-server/cmd/authgear/userimport"
+)
+
+var cmdUsers = &cobra.Command{
+	Use:   "users",
+	Short: "User commands",
+}
+
+var cmdUsersImport = &cobra.Command{
+	Use:   "import app-id file",
+	Short: "Import users of a given app from a NDJSON file",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		appID := args[0]
+		filePath := args[1]
+
+		jobID, err := cmd.Flags().GetString("resume")
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(filePath)
+		if err != nil {
+			return fmt.Errorf("cannot open input file: %w", err)
+		}
+		defer f.Close()
+
+		return cmduserimport.Import(context.Background(), appID, jobID, f, os.Stdout)
+	},
+}
+
+func init() {
+	cmdUsers.AddCommand(cmdUsersImport)
+	_ = cmdUsersImport.Flags().String("resume", "", "ID of the user import job to resume")
+}
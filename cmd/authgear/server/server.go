@@ -19,6 +19,7 @@ type Controller struct {
 	ServeMain     bool
 	ServeResolver bool
 	ServeAdmin    bool
+	RunWorker     bool
 
 	logger *log.Logger
 }
@@ -62,6 +63,13 @@ func (c *Controller) Start() {
 	}
 	defer configSrcController.Close()
 
+	if c.RunWorker {
+		c.logger.Info("starting worker scheduler")
+		schedulerDone := make(chan struct{})
+		defer close(schedulerDone)
+		go worker.NewScheduler(p, context.Background(), wrk, configSrcController).Run(schedulerDone)
+	}
+
 	var specs []server.Spec
 
 	if c.ServeMain {
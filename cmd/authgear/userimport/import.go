@@ -0,0 +1,100 @@
+package userimport
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/authgear/authgear-server/cmd/authgear/server"
+	"github.com/authgear/authgear-server/pkg/lib/deps"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
+	"github.com/authgear/authgear-server/pkg/worker"
+)
+
+// NoSearchService skips reindexing imported users, because the in-process tasks would be lost
+// when the command exits. The app is reindexed after importing instead.
+type NoSearchService struct{}
+
+func (NoSearchService) ReindexUser(userID string, isDelete bool) error {
+	return nil
+}
+
+type Importer struct {
+	Database *appdb.Handle
+	Jobs     *userimport.Service
+	Runner   *userimport.Runner
+}
+
+// Import imports the records of input into the app.
+// If jobID is empty, a new job is created. Otherwise, the job is resumed,
+// and input must be the same as the input of the previous runs.
+func Import(ctx context.Context, appID string, jobID string, input io.Reader, output io.Writer) error {
+	cfg, err := server.LoadConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	var wrk *worker.Worker
+	taskQueueFactory := deps.TaskQueueFactory(func(provider *deps.AppProvider) task.Queue {
+		return newInProcessQueue(provider, wrk.Executor)
+	})
+
+	p, err := deps.NewRootProvider(
+		cfg.EnvironmentConfig,
+		cfg.ConfigSource,
+		cfg.BuiltinResourceDirectory,
+		cfg.CustomResourceDirectory,
+		taskQueueFactory,
+	)
+	if err != nil {
+		return err
+	}
+
+	wrk = worker.NewWorker(p)
+
+	configSrcController := newConfigSourceController(p, ctx)
+	err = configSrcController.Open()
+	if err != nil {
+		return err
+	}
+	defer configSrcController.Close()
+
+	appCtx, err := configSrcController.GetConfigSource().ContextResolver.ResolveContext(appID)
+	if err != nil {
+		return err
+	}
+
+	importer := newImporter(p.NewAppProvider(ctx, appCtx).NewTaskProvider(ctx))
+	return importer.Import(jobID, input, output)
+}
+
+func (i *Importer) Import(jobID string, input io.Reader, output io.Writer) error {
+	if jobID == "" {
+		var job *userimport.Job
+		err := i.Database.WithTx(func() (err error) {
+			job, err = i.Jobs.CreateJob()
+			return
+		})
+		if err != nil {
+			return err
+		}
+
+		jobID = job.ID
+		fmt.Fprintf(output, "Created user import job (%s)\n", jobID)
+	} else {
+		fmt.Fprintf(output, "Resuming user import job (%s)\n", jobID)
+	}
+
+	job, err := i.Runner.Run(jobID, input, func(e *userimport.RecordError) {
+		fmt.Fprintf(output, "line %d: %s\n", e.Line, e.Message)
+	})
+	if err != nil {
+		return fmt.Errorf("user import job (%s) stopped; resume it with --resume %s: %w", jobID, jobID, err)
+	}
+
+	fmt.Fprintf(output, "Imported %d users; %d records failed\n", job.ImportedCount, job.FailedCount)
+	fmt.Fprintf(output, "Run `authgear internal elasticsearch reindex <app-id>` to index the imported users for search\n")
+	return nil
+}
@@ -0,0 +1,47 @@
+//go:build wireinject
+// +build wireinject
+
+package userimport
+
+import (
+	"context"
+
+	"github.com/google/wire"
+
+	"github.com/authgear/authgear-server/pkg/lib/config/configsource"
+	"github.com/authgear/authgear-server/pkg/lib/deps"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task/executor"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task/queue"
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
+)
+
+func newConfigSourceController(p *deps.RootProvider, c context.Context) *configsource.Controller {
+	panic(wire.Build(
+		deps.RootDependencySet,
+		wire.FieldsOf(new(*deps.RootProvider),
+			"DatabasePool",
+		),
+	))
+}
+
+func newInProcessQueue(p *deps.AppProvider, e *executor.InProcessExecutor) *queue.InProcessQueue {
+	panic(wire.Build(
+		deps.RootDependencySet,
+		wire.FieldsOf(new(*deps.AppProvider),
+			"Config",
+			"AppDatabase",
+		),
+		queue.DependencySet,
+		wire.Bind(new(queue.Executor), new(*executor.InProcessExecutor)),
+	))
+}
+
+func newImporter(p *deps.TaskProvider) *Importer {
+	panic(wire.Build(
+		deps.TaskDependencySet,
+		deps.CommonDependencySet,
+		wire.Struct(new(Importer), "*"),
+		wire.Struct(new(NoSearchService)),
+		wire.Bind(new(userimport.SearchService), new(*NoSearchService)),
+	))
+}
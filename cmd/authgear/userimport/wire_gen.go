@@ -0,0 +1,259 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package userimport
+
+import (
+	"context"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity/anonymous"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity/biometric"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity/loginid"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity/service"
+	"github.com/authgear/authgear-server/pkg/lib/authn/user"
+	"github.com/authgear/authgear-server/pkg/lib/config/configsource"
+	"github.com/authgear/authgear-server/pkg/lib/deps"
+	"github.com/authgear/authgear-server/pkg/lib/feature/customattrs"
+	"github.com/authgear/authgear-server/pkg/lib/feature/stdattrs"
+	"github.com/authgear/authgear-server/pkg/lib/feature/verification"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/globaldb"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task/executor"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task/queue"
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+// Injectors from wire.go:
+
+func newConfigSourceController(p *deps.RootProvider, c context.Context) *configsource.Controller {
+	config := p.ConfigSourceConfig
+	factory := p.LoggerFactory
+	localFSLogger := configsource.NewLocalFSLogger(factory)
+	manager := p.BaseResources
+	localFS := &configsource.LocalFS{
+		Logger:        localFSLogger,
+		BaseResources: manager,
+		Config:        config,
+	}
+	databaseLogger := configsource.NewDatabaseLogger(factory)
+	environmentConfig := p.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	clock := _wireSystemClockValue
+	databaseEnvironmentConfig := &environmentConfig.Database
+	sqlBuilder := globaldb.NewSQLBuilder(databaseEnvironmentConfig)
+	pool := p.DatabasePool
+	handle := globaldb.NewHandle(c, pool, databaseEnvironmentConfig, factory)
+	sqlExecutor := globaldb.NewSQLExecutor(c, handle)
+	store := &configsource.Store{
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	database := &configsource.Database{
+		Logger:         databaseLogger,
+		BaseResources:  manager,
+		TrustProxy:     trustProxy,
+		Config:         config,
+		Clock:          clock,
+		Store:          store,
+		Database:       handle,
+		DatabaseConfig: databaseEnvironmentConfig,
+	}
+	controller := configsource.NewController(config, localFS, database)
+	return controller
+}
+
+var (
+	_wireSystemClockValue = clock.NewSystemClock()
+)
+
+func newInProcessQueue(p *deps.AppProvider, e *executor.InProcessExecutor) *queue.InProcessQueue {
+	handle := p.AppDatabase
+	config := p.Config
+	captureTaskContext := deps.ProvideCaptureTaskContext(config)
+	inProcessQueue := &queue.InProcessQueue{
+		Database:       handle,
+		CaptureContext: captureTaskContext,
+		Executor:       e,
+	}
+	return inProcessQueue
+}
+
+func newImporter(p *deps.TaskProvider) *Importer {
+	appProvider := p.AppProvider
+	handle := appProvider.AppDatabase
+	clockClock := _wireSystemClockValue
+	config := appProvider.Config
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	contextContext := p.Context
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	store := &userimport.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	taskQueue := appProvider.TaskQueue
+	userimportService := &userimport.Service{
+		Clock:     clockClock,
+		Store:     store,
+		TaskQueue: taskQueue,
+	}
+	identityConfig := appConfig.Identity
+	loginIDConfig := identityConfig.LoginID
+	oAuthSSOConfig := identityConfig.OAuth
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	authenticationConfig := appConfig.Authentication
+	featureConfig := config.FeatureConfig
+	identityFeatureConfig := featureConfig.Identity
+	serviceStore := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 serviceStore,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	factory := appProvider.LoggerFactory
+	logger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, logger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          logger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	userProfileConfig := appConfig.UserProfile
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	importer := &userimport.Importer{
+		LoginIDConfig:      loginIDConfig,
+		OAuthConfig:        oAuthSSOConfig,
+		Clock:              clockClock,
+		Users:              userStore,
+		Identities:         serviceService,
+		Passwords:          passwordProvider,
+		VerifiedClaims:     storePQ,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	noSearchService := &NoSearchService{}
+	runnerLogger := userimport.NewRunnerLogger(factory)
+	runner := &userimport.Runner{
+		Database:      handle,
+		Store:         store,
+		Importer:      importer,
+		SearchService: noSearchService,
+		Clock:         clockClock,
+		Logger:        runnerLogger,
+	}
+	userimportImporter := &Importer{
+		Database: handle,
+		Jobs:     userimportService,
+		Runner:   runner,
+	}
+	return userimportImporter
+}
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE _auth_authenticator_passkey
+(
+    id            text PRIMARY KEY REFERENCES _auth_authenticator (id),
+    app_id        text   NOT NULL,
+    credential_id text   NOT NULL,
+    public_key    text   NOT NULL,
+    sign_count    bigint NOT NULL,
+    display_name  text   NOT NULL
+);
+ALTER TABLE _auth_authenticator_passkey
+    ADD CONSTRAINT _auth_authenticator_passkey_credential_id UNIQUE (app_id, credential_id);
+
+-- +migrate Down
+DROP TABLE _auth_authenticator_passkey;
+DELETE FROM _auth_authenticator WHERE "type" = 'passkey';
@@ -0,0 +1,37 @@
+-- +migrate Up
+CREATE TABLE _auth_webhook_delivery
+(
+    id                text PRIMARY KEY,
+    app_id            text                        NOT NULL,
+    created_at        timestamp without time zone NOT NULL,
+    updated_at        timestamp without time zone NOT NULL,
+    event_id          text                        NOT NULL,
+    event_type        text                        NOT NULL,
+    url               text                        NOT NULL,
+    payload           jsonb                       NOT NULL,
+    status            text                        NOT NULL,
+    attempt_count     integer                     NOT NULL,
+    next_attempt_at   timestamp without time zone,
+    last_attempted_at timestamp without time zone,
+    claimed_until     timestamp without time zone
+);
+CREATE INDEX _auth_webhook_delivery_idx_created_at ON _auth_webhook_delivery (app_id, created_at);
+CREATE INDEX _auth_webhook_delivery_idx_next_attempt_at ON _auth_webhook_delivery (app_id, status, next_attempt_at);
+CREATE INDEX _auth_webhook_delivery_idx_pending ON _auth_webhook_delivery (next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE _auth_webhook_delivery_attempt
+(
+    id               text PRIMARY KEY,
+    app_id           text                        NOT NULL,
+    delivery_id      text                        NOT NULL REFERENCES _auth_webhook_delivery (id),
+    created_at       timestamp without time zone NOT NULL,
+    status_code      integer,
+    latency_ms       bigint                      NOT NULL,
+    response_excerpt text                        NOT NULL,
+    error            text                        NOT NULL
+);
+CREATE INDEX _auth_webhook_delivery_attempt_idx_delivery_id ON _auth_webhook_delivery_attempt (delivery_id);
+
+-- +migrate Down
+DROP TABLE _auth_webhook_delivery_attempt;
+DROP TABLE _auth_webhook_delivery;
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE _auth_event_stream
+(
+    id         text PRIMARY KEY,
+    app_id     text                        NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    seq        bigint                      NOT NULL,
+    event_type text                        NOT NULL,
+    payload    jsonb                       NOT NULL
+);
+CREATE INDEX _auth_event_stream_idx_seq ON _auth_event_stream (app_id, seq);
+
+CREATE TABLE _auth_event_stream_publisher
+(
+    app_id          text PRIMARY KEY,
+    attempts        integer NOT NULL,
+    next_attempt_at timestamp without time zone,
+    claimed_until   timestamp without time zone
+);
+
+-- +migrate Down
+DROP TABLE _auth_event_stream_publisher;
+DROP TABLE _auth_event_stream;
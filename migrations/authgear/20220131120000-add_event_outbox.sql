@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE _auth_event_outbox
+(
+    id               text PRIMARY KEY,
+    app_id           text                        NOT NULL,
+    created_at       timestamp without time zone NOT NULL,
+    position         bigserial,
+    seq              bigint,
+    event_type       text                        NOT NULL,
+    for_webhook      boolean                     NOT NULL,
+    for_audit        boolean                     NOT NULL,
+    payload          jsonb                       NOT NULL,
+    deliveries       jsonb                       NOT NULL DEFAULT '{}'::jsonb,
+    dead_lettered_at timestamp without time zone
+);
+CREATE INDEX _auth_event_outbox_idx_seq_position ON _auth_event_outbox (app_id, seq, position);
+
+-- +migrate Down
+DROP TABLE _auth_event_outbox;
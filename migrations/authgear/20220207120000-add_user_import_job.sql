@@ -0,0 +1,38 @@
+-- +migrate Up
+CREATE TABLE _auth_user_import_job
+(
+    id              text PRIMARY KEY,
+    app_id          text                        NOT NULL,
+    created_at      timestamp without time zone NOT NULL,
+    updated_at      timestamp without time zone NOT NULL,
+    status          text                        NOT NULL,
+    processed_count integer                     NOT NULL,
+    imported_count  integer                     NOT NULL,
+    failed_count    integer                     NOT NULL
+);
+CREATE INDEX _auth_user_import_job_idx_created_at ON _auth_user_import_job (app_id, created_at);
+
+CREATE TABLE _auth_user_import_chunk
+(
+    id     text PRIMARY KEY,
+    app_id text    NOT NULL,
+    job_id text    NOT NULL REFERENCES _auth_user_import_job (id),
+    seq    integer NOT NULL,
+    data   text    NOT NULL
+);
+CREATE UNIQUE INDEX _auth_user_import_chunk_idx_job_id ON _auth_user_import_chunk (job_id, seq);
+
+CREATE TABLE _auth_user_import_error
+(
+    id      text PRIMARY KEY,
+    app_id  text    NOT NULL,
+    job_id  text    NOT NULL REFERENCES _auth_user_import_job (id),
+    line    integer NOT NULL,
+    message text    NOT NULL
+);
+CREATE INDEX _auth_user_import_error_idx_job_id ON _auth_user_import_error (job_id, line);
+
+-- +migrate Down
+DROP TABLE _auth_user_import_error;
+DROP TABLE _auth_user_import_chunk;
+DROP TABLE _auth_user_import_job;
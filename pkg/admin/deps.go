@@ -10,8 +10,10 @@ import (
 	"github.com/authgear/authgear-server/pkg/admin/transport"
 	adminauthz "github.com/authgear/authgear-server/pkg/lib/admin/authz"
 	"github.com/authgear/authgear-server/pkg/lib/audit"
+	authenticatoroob "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
 	authenticatorservice "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/service"
 	identityservice "github.com/authgear/authgear-server/pkg/lib/authn/identity/service"
+	"github.com/authgear/authgear-server/pkg/lib/authn/lockout"
 	"github.com/authgear/authgear-server/pkg/lib/authn/otp"
 	"github.com/authgear/authgear-server/pkg/lib/authn/sso"
 	"github.com/authgear/authgear-server/pkg/lib/authn/user"
@@ -23,10 +25,13 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/feature/forgotpassword"
 	featurestdattrs "github.com/authgear/authgear-server/pkg/lib/feature/stdattrs"
 	"github.com/authgear/authgear-server/pkg/lib/feature/verification"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
 	"github.com/authgear/authgear-server/pkg/lib/infra/middleware"
 	"github.com/authgear/authgear-server/pkg/lib/interaction"
 	"github.com/authgear/authgear-server/pkg/lib/nonce"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
 	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
 )
 
 var DependencySet = wire.NewSet(
@@ -43,6 +48,7 @@ var DependencySet = wire.NewSet(
 	wire.Bind(new(loader.IdentityLoaderIdentityService), new(*identityservice.Service)),
 	wire.Bind(new(loader.AuthenticatorLoaderAuthenticatorService), new(*authenticatorservice.Service)),
 	wire.Bind(new(loader.AuditLogQuery), new(*audit.Query)),
+	wire.Bind(new(loader.WebhookDeliveryQuery), new(*hook.DeliveryService)),
 
 	facade.DependencySet,
 	wire.Bind(new(facade.UserService), new(*libfacade.UserFacade)),
@@ -54,21 +60,29 @@ var DependencySet = wire.NewSet(
 	wire.Bind(new(facade.StandardAttributesService), new(*featurestdattrs.ServiceNoEvent)),
 	wire.Bind(new(facade.CustomAttributesService), new(*featurecustomattrs.ServiceNoEvent)),
 	wire.Bind(new(facade.SessionManager), new(*session.Manager)),
+	wire.Bind(new(facade.AuthorizationService), new(*oauth.AuthorizationService)),
 	wire.Bind(new(facade.AuditLogQuery), new(*audit.Query)),
+	wire.Bind(new(facade.WebhookDeliveryService), new(*hook.DeliveryService)),
 	wire.Bind(new(facade.EventService), new(*event.Service)),
+	wire.Bind(new(facade.LockoutService), new(*lockout.Service)),
+	wire.Bind(new(facade.UserImportService), new(*userimport.Service)),
 
 	graphql.DependencySet,
 	wire.Bind(new(graphql.UserLoader), new(*loader.UserLoader)),
 	wire.Bind(new(graphql.IdentityLoader), new(*loader.IdentityLoader)),
 	wire.Bind(new(graphql.AuthenticatorLoader), new(*loader.AuthenticatorLoader)),
 	wire.Bind(new(graphql.AuditLogLoader), new(*loader.AuditLogLoader)),
+	wire.Bind(new(graphql.WebhookDeliveryLoader), new(*loader.WebhookDeliveryLoader)),
 	wire.Bind(new(graphql.UserFacade), new(*facade.UserFacade)),
 	wire.Bind(new(graphql.IdentityFacade), new(*facade.IdentityFacade)),
 	wire.Bind(new(graphql.AuthenticatorFacade), new(*facade.AuthenticatorFacade)),
 	wire.Bind(new(graphql.VerificationFacade), new(*facade.VerificationFacade)),
 	wire.Bind(new(graphql.SessionFacade), new(*facade.SessionFacade)),
+	wire.Bind(new(graphql.AuthorizationFacade), new(*facade.AuthorizationFacade)),
 	wire.Bind(new(graphql.AuditLogFacade), new(*facade.AuditLogFacade)),
 	wire.Bind(new(graphql.UserProfileFacade), new(*facade.UserProfileFacade)),
+	wire.Bind(new(graphql.WebhookDeliveryFacade), new(*facade.WebhookDeliveryFacade)),
+	wire.Bind(new(graphql.UserImportFacade), new(*facade.UserImportFacade)),
 
 	service.DependencySet,
 	wire.Bind(new(service.InteractionGraphService), new(*interaction.Service)),
@@ -76,8 +90,10 @@ var DependencySet = wire.NewSet(
 	wire.Struct(new(WebEndpoints), "*"),
 	wire.Bind(new(sso.EndpointsProvider), new(*WebEndpoints)),
 	wire.Bind(new(sso.RedirectURLProvider), new(*WebEndpoints)),
+	wire.Bind(new(sso.SAMLURLProvider), new(*WebEndpoints)),
 	wire.Bind(new(otp.EndpointsProvider), new(*WebEndpoints)),
 	wire.Bind(new(verification.WebAppURLProvider), new(*WebEndpoints)),
+	wire.Bind(new(authenticatoroob.WebAppURLProvider), new(*WebEndpoints)),
 	wire.Bind(new(forgotpassword.URLProvider), new(*WebEndpoints)),
 	wire.Bind(new(sso.WechatURLProvider), new(*WebEndpoints)),
 
@@ -0,0 +1,27 @@
+package facade
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+type AuthorizationService interface {
+	Get(id string) (*model.Authorization, error)
+	List(userID string) ([]*model.Authorization, error)
+	Delete(id string, isAdminAPI bool) error
+}
+
+type AuthorizationFacade struct {
+	Authorizations AuthorizationService
+}
+
+func (f *AuthorizationFacade) Get(id string) (*model.Authorization, error) {
+	return f.Authorizations.Get(id)
+}
+
+func (f *AuthorizationFacade) List(userID string) ([]*model.Authorization, error) {
+	return f.Authorizations.List(userID)
+}
+
+func (f *AuthorizationFacade) Delete(id string) error {
+	return f.Authorizations.Delete(id, true)
+}
@@ -8,6 +8,9 @@ var DependencySet = wire.NewSet(
 	wire.Struct(new(AuthenticatorFacade), "*"),
 	wire.Struct(new(VerificationFacade), "*"),
 	wire.Struct(new(SessionFacade), "*"),
+	wire.Struct(new(AuthorizationFacade), "*"),
 	wire.Struct(new(AuditLogFacade), "*"),
+	wire.Struct(new(WebhookDeliveryFacade), "*"),
 	wire.Struct(new(UserProfileFacade), "*"),
+	wire.Struct(new(UserImportFacade), "*"),
 )
@@ -15,6 +15,7 @@ import (
 type IdentityService interface {
 	Get(userID string, typ apimodel.IdentityType, id string) (*identity.Info, error)
 	ListRefsByUsers(userIDs []string) ([]*apimodel.IdentityRef, error)
+	ListByUser(userID string) ([]*identity.Info, error)
 }
 
 type IdentityFacade struct {
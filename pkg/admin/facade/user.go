@@ -5,7 +5,10 @@ import (
 
 	"github.com/authgear/authgear-server/pkg/admin/model"
 	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
 	apimodel "github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn/lockout"
 	"github.com/authgear/authgear-server/pkg/lib/authn/user"
 	libes "github.com/authgear/authgear-server/pkg/lib/elasticsearch"
 	"github.com/authgear/authgear-server/pkg/lib/interaction"
@@ -27,11 +30,18 @@ type UserSearchService interface {
 	QueryUser(searchKeyword string, sortOption user.SortOption, pageArgs graphqlutil.PageArgs) ([]apimodel.PageItemRef, *libes.Stats, error)
 }
 
+type LockoutService interface {
+	Get(userID string) (*lockout.State, error)
+	ClearAttempts(userID string) error
+}
+
 type UserFacade struct {
 	UserSearchService  UserSearchService
 	Users              UserService
 	StandardAttributes StandardAttributesService
 	Interaction        InteractionService
+	Events             EventService
+	Lockout            LockoutService
 }
 
 func (f *UserFacade) ListPage(sortOption user.SortOption, pageArgs graphqlutil.PageArgs) ([]apimodel.PageItemRef, *graphqlutil.PageResult, error) {
@@ -101,10 +111,42 @@ func (f *UserFacade) ResetPassword(id string, password string) error {
 }
 
 func (f *UserFacade) SetDisabled(id string, isDisabled bool, reason *string) error {
-	err := f.Users.UpdateDisabledStatus(id, isDisabled, reason)
+	u, err := f.Users.GetRaw(id)
+	if err != nil {
+		return err
+	}
+
+	err = f.Users.UpdateDisabledStatus(id, isDisabled, reason)
 	if err != nil {
 		return err
 	}
+
+	if u.IsDisabled != isDisabled {
+		userRef := apimodel.UserRef{
+			Meta: apimodel.Meta{
+				ID: id,
+			},
+		}
+
+		var e event.Payload
+		if isDisabled {
+			e = &nonblocking.UserDisabledEventPayload{
+				UserRef:  userRef,
+				AdminAPI: true,
+			}
+		} else {
+			e = &nonblocking.UserReenabledEventPayload{
+				UserRef:  userRef,
+				AdminAPI: true,
+			}
+		}
+
+		err = f.Events.DispatchEvent(e)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = f.UserSearchService.ReindexUser(id, false)
 	if err != nil {
 		return err
@@ -112,8 +154,39 @@ func (f *UserFacade) SetDisabled(id string, isDisabled bool, reason *string) err
 	return nil
 }
 
+func (f *UserFacade) GetLockout(id string) (*apimodel.AuthenticationLockout, error) {
+	state, err := f.Lockout.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return state.ToModel(), nil
+}
+
+func (f *UserFacade) Unlock(id string) error {
+	// Ensure the user exists.
+	_, err := f.Users.GetRaw(id)
+	if err != nil {
+		return err
+	}
+
+	return f.Lockout.ClearAttempts(id)
+}
+
 func (f *UserFacade) Delete(id string) error {
-	err := f.Users.Delete(id)
+	// Dispatch the event before deletion, so that the user can be resolved.
+	err := f.Events.DispatchEvent(&nonblocking.UserDeletedEventPayload{
+		UserRef: apimodel.UserRef{
+			Meta: apimodel.Meta{
+				ID: id,
+			},
+		},
+		AdminAPI: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = f.Users.Delete(id)
 	if err != nil {
 		return err
 	}
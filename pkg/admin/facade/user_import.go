@@ -0,0 +1,42 @@
+package facade
+
+import (
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
+)
+
+type UserImportService interface {
+	Get(id string) (*userimport.Job, error)
+	CreateUploadJob(records string) (*userimport.Job, error)
+	AddRecords(jobID string, records string) (*userimport.Job, error)
+	SubmitJob(jobID string) (*userimport.Job, error)
+	Resume(id string) (*userimport.Job, error)
+	ListRecordErrors(jobID string) ([]*userimport.RecordError, error)
+}
+
+type UserImportFacade struct {
+	UserImports UserImportService
+}
+
+func (f *UserImportFacade) Get(id string) (*userimport.Job, error) {
+	return f.UserImports.Get(id)
+}
+
+func (f *UserImportFacade) Create(records string) (*userimport.Job, error) {
+	return f.UserImports.CreateUploadJob(records)
+}
+
+func (f *UserImportFacade) AddRecords(id string, records string) (*userimport.Job, error) {
+	return f.UserImports.AddRecords(id, records)
+}
+
+func (f *UserImportFacade) Submit(id string) (*userimport.Job, error) {
+	return f.UserImports.SubmitJob(id)
+}
+
+func (f *UserImportFacade) Resume(id string) (*userimport.Job, error) {
+	return f.UserImports.Resume(id)
+}
+
+func (f *UserImportFacade) ListRecordErrors(jobID string) ([]*userimport.RecordError, error) {
+	return f.UserImports.ListRecordErrors(jobID)
+}
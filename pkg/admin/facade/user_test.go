@@ -0,0 +1,113 @@
+package facade_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/admin/facade"
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn/user"
+)
+
+type fakeUserService struct {
+	facade.UserService
+	users map[string]*user.User
+}
+
+func (s *fakeUserService) GetRaw(id string) (*user.User, error) {
+	u, ok := s.users[id]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	uu := *u
+	return &uu, nil
+}
+
+func (s *fakeUserService) UpdateDisabledStatus(userID string, isDisabled bool, reason *string) error {
+	s.users[userID].IsDisabled = isDisabled
+	return nil
+}
+
+func (s *fakeUserService) Delete(userID string) error {
+	delete(s.users, userID)
+	return nil
+}
+
+type fakeUserSearchService struct {
+	facade.UserSearchService
+}
+
+func (s *fakeUserSearchService) ReindexUser(userID string, isDelete bool) error {
+	return nil
+}
+
+type fakeEventService struct {
+	payloads []event.Payload
+}
+
+func (s *fakeEventService) DispatchEvent(payload event.Payload) error {
+	s.payloads = append(s.payloads, payload)
+	return nil
+}
+
+func TestUserFacade(t *testing.T) {
+	Convey("UserFacade", t, func() {
+		users := &fakeUserService{
+			users: map[string]*user.User{
+				"user-id": {ID: "user-id"},
+			},
+		}
+		events := &fakeEventService{}
+		f := &facade.UserFacade{
+			UserSearchService: &fakeUserSearchService{},
+			Users:             users,
+			Events:            events,
+		}
+		userRef := model.UserRef{
+			Meta: model.Meta{
+				ID: "user-id",
+			},
+		}
+
+		Convey("should dispatch user.disabled and user.reenabled", func() {
+			reason := "spam"
+			err := f.SetDisabled("user-id", true, &reason)
+			So(err, ShouldBeNil)
+
+			err = f.SetDisabled("user-id", false, nil)
+			So(err, ShouldBeNil)
+
+			So(events.payloads, ShouldResemble, []event.Payload{
+				&nonblocking.UserDisabledEventPayload{
+					UserRef:  userRef,
+					AdminAPI: true,
+				},
+				&nonblocking.UserReenabledEventPayload{
+					UserRef:  userRef,
+					AdminAPI: true,
+				},
+			})
+		})
+
+		Convey("should not dispatch event if disabled status is unchanged", func() {
+			err := f.SetDisabled("user-id", false, nil)
+			So(err, ShouldBeNil)
+			So(events.payloads, ShouldBeEmpty)
+		})
+
+		Convey("should dispatch user.deleted", func() {
+			err := f.Delete("user-id")
+			So(err, ShouldBeNil)
+			So(users.users, ShouldBeEmpty)
+			So(events.payloads, ShouldResemble, []event.Payload{
+				&nonblocking.UserDeletedEventPayload{
+					UserRef:  userRef,
+					AdminAPI: true,
+				},
+			})
+		})
+	})
+}
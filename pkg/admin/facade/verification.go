@@ -2,6 +2,8 @@ package facade
 
 import (
 	"github.com/authgear/authgear-server/pkg/admin/model"
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	apimodel "github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/feature/verification"
 )
 
@@ -14,6 +16,8 @@ type VerificationService interface {
 
 type VerificationFacade struct {
 	Verification VerificationService
+	Identities   IdentityService
+	Events       EventService
 }
 
 func (f *VerificationFacade) Get(userID string) ([]model.Claim, error) {
@@ -52,6 +56,11 @@ func (f *VerificationFacade) SetVerified(userID string, claimName string, claimV
 			return err
 		}
 
+		err = f.dispatchIdentityVerified(userID, claimName, claimValue)
+		if err != nil {
+			return err
+		}
+
 	} else {
 		var claim *verification.Claim
 		for _, c := range claims {
@@ -72,3 +81,31 @@ func (f *VerificationFacade) SetVerified(userID string, claimName string, claimV
 
 	return nil
 }
+
+func (f *VerificationFacade) dispatchIdentityVerified(userID string, claimName string, claimValue string) error {
+	is, err := f.Identities.ListByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range is {
+		if i.StandardClaims()[apimodel.ClaimName(claimName)] != claimValue {
+			continue
+		}
+
+		err = f.Events.DispatchEvent(&nonblocking.IdentityVerifiedEventPayload{
+			UserRef: apimodel.UserRef{
+				Meta: apimodel.Meta{
+					ID: userID,
+				},
+			},
+			Identity: i.ToModel(),
+			AdminAPI: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
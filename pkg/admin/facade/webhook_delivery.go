@@ -0,0 +1,37 @@
+package facade
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
+	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
+)
+
+type WebhookDeliveryService interface {
+	Count(opts hook.DeliveryQueryPageOptions) (uint64, error)
+	QueryPage(opts hook.DeliveryQueryPageOptions, pageArgs graphqlutil.PageArgs) ([]model.PageItemRef, error)
+	ListAttempts(id string) ([]*hook.DeliveryAttempt, error)
+	Redeliver(id string) (*hook.Delivery, error)
+}
+
+type WebhookDeliveryFacade struct {
+	WebhookDeliveries WebhookDeliveryService
+}
+
+func (f *WebhookDeliveryFacade) QueryPage(opts hook.DeliveryQueryPageOptions, pageArgs graphqlutil.PageArgs) ([]model.PageItemRef, *graphqlutil.PageResult, error) {
+	refs, err := f.WebhookDeliveries.QueryPage(opts, pageArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return refs, graphqlutil.NewPageResult(pageArgs, len(refs), graphqlutil.NewLazy(func() (interface{}, error) {
+		return f.WebhookDeliveries.Count(opts)
+	})), nil
+}
+
+func (f *WebhookDeliveryFacade) ListAttempts(id string) ([]*hook.DeliveryAttempt, error) {
+	return f.WebhookDeliveries.ListAttempts(id)
+}
+
+func (f *WebhookDeliveryFacade) Redeliver(id string) (*hook.Delivery, error) {
+	return f.WebhookDeliveries.Redeliver(id)
+}
@@ -23,6 +23,39 @@ var auditLogActivityType = graphql.NewEnum(graphql.EnumConfig{
 		"USER_SIGNED_OUT": &graphql.EnumValueConfig{
 			Value: "user.signed_out",
 		},
+		"USER_PASSWORD_CHANGED": &graphql.EnumValueConfig{
+			Value: "user.password.changed",
+		},
+		"USER_PASSWORD_RESET": &graphql.EnumValueConfig{
+			Value: "user.password.reset",
+		},
+		"USER_RECOVERY_CODES_REGENERATED": &graphql.EnumValueConfig{
+			Value: "user.recovery_codes.regenerated",
+		},
+		"USER_DISABLED": &graphql.EnumValueConfig{
+			Value: "user.disabled",
+		},
+		"USER_REENABLED": &graphql.EnumValueConfig{
+			Value: "user.reenabled",
+		},
+		"USER_DELETED": &graphql.EnumValueConfig{
+			Value: "user.deleted",
+		},
+		"AUTHENTICATOR_CREATED": &graphql.EnumValueConfig{
+			Value: "authenticator.created",
+		},
+		"AUTHENTICATOR_DELETED": &graphql.EnumValueConfig{
+			Value: "authenticator.deleted",
+		},
+		"IDENTITY_VERIFIED": &graphql.EnumValueConfig{
+			Value: "identity.verified",
+		},
+		"SESSION_CREATED": &graphql.EnumValueConfig{
+			Value: "session.created",
+		},
+		"SESSION_REVOKED": &graphql.EnumValueConfig{
+			Value: "session.revoked",
+		},
 		"USER_ANONYMOUS_PROMOTED": &graphql.EnumValueConfig{
 			Value: "user.anonymous.promoted",
 		},
@@ -44,6 +77,9 @@ var auditLogActivityType = graphql.NewEnum(graphql.EnumConfig{
 		"AUTHENTICATION_PRIMARY_OOB_OTP_SMS_FAILED": &graphql.EnumValueConfig{
 			Value: "authentication.primary.oob_otp_sms.failed",
 		},
+		"AUTHENTICATION_PRIMARY_PASSKEY_FAILED": &graphql.EnumValueConfig{
+			Value: "authentication.primary.passkey.failed",
+		},
 		"AUTHENTICATION_SECONDARY_PASSWORD_FAILED": &graphql.EnumValueConfig{
 			Value: "authentication.secondary.password.failed",
 		},
@@ -56,6 +92,9 @@ var auditLogActivityType = graphql.NewEnum(graphql.EnumConfig{
 		"AUTHENTICATION_SECONDARY_OOB_OTP_SMS_FAILED": &graphql.EnumValueConfig{
 			Value: "authentication.secondary.oob_otp_sms.failed",
 		},
+		"AUTHENTICATION_SECONDARY_PASSKEY_FAILED": &graphql.EnumValueConfig{
+			Value: "authentication.secondary.passkey.failed",
+		},
 		"AUTHENTICATION_SECONDARY_RECOVERY_CODE_FAILED": &graphql.EnumValueConfig{
 			Value: "authentication.secondary.recovery_code.failed",
 		},
@@ -28,6 +28,9 @@ var authenticatorType = graphql.NewEnum(graphql.EnumConfig{
 		"OOB_OTP_SMS": &graphql.EnumValueConfig{
 			Value: "oob_otp_sms",
 		},
+		"PASSKEY": &graphql.EnumValueConfig{
+			Value: "passkey",
+		},
 	},
 })
 
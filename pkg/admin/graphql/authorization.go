@@ -0,0 +1,41 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
+)
+
+const typeAuthorization = "Authorization"
+
+var nodeAuthorization = node(
+	graphql.NewObject(graphql.ObjectConfig{
+		Name:        typeAuthorization,
+		Description: "OAuth client authorization granted by the user",
+		Interfaces: []*graphql.Interface{
+			nodeDefs.NodeInterface,
+			entityInterface,
+		},
+		Fields: graphql.Fields{
+			"id":        entityIDField(typeAuthorization, nil),
+			"createdAt": entityCreatedAtField(nil),
+			"updatedAt": entityUpdatedAtField(nil),
+			"clientID": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"scopes": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			},
+			"lastUsedAt": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+		},
+	}),
+	&model.Authorization{},
+	func(ctx *Context, id string) (interface{}, error) {
+		return ctx.AuthorizationFacade.Get(id)
+	},
+)
+
+var connAuthorization = graphqlutil.NewConnectionDef(nodeAuthorization)
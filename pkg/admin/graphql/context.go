@@ -10,7 +10,9 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity"
 	libuser "github.com/authgear/authgear-server/pkg/lib/authn/user"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
 	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
 	"github.com/authgear/authgear-server/pkg/util/accesscontrol"
 	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
 	"github.com/authgear/authgear-server/pkg/util/log"
@@ -36,12 +38,24 @@ type AuditLogFacade interface {
 	QueryPage(opts audit.QueryPageOptions, pageArgs graphqlutil.PageArgs) ([]apimodel.PageItemRef, *graphqlutil.PageResult, error)
 }
 
+type WebhookDeliveryLoader interface {
+	graphqlutil.DataLoaderInterface
+}
+
+type WebhookDeliveryFacade interface {
+	QueryPage(opts hook.DeliveryQueryPageOptions, pageArgs graphqlutil.PageArgs) ([]apimodel.PageItemRef, *graphqlutil.PageResult, error)
+	ListAttempts(id string) ([]*hook.DeliveryAttempt, error)
+	Redeliver(id string) (*hook.Delivery, error)
+}
+
 type UserFacade interface {
 	ListPage(sortOption libuser.SortOption, args graphqlutil.PageArgs) ([]apimodel.PageItemRef, *graphqlutil.PageResult, error)
 	SearchPage(searchKeyword string, sortOption libuser.SortOption, args graphqlutil.PageArgs) ([]apimodel.PageItemRef, *graphqlutil.PageResult, error)
 	Create(identityDef model.IdentityDef, password string) (string, error)
 	ResetPassword(id string, password string) error
 	SetDisabled(id string, isDisabled bool, reason *string) error
+	GetLockout(id string) (*apimodel.AuthenticationLockout, error)
+	Unlock(id string) error
 	Delete(id string) error
 }
 
@@ -74,6 +88,21 @@ type UserProfileFacade interface {
 	) error
 }
 
+type AuthorizationFacade interface {
+	Get(id string) (*apimodel.Authorization, error)
+	List(userID string) ([]*apimodel.Authorization, error)
+	Delete(id string) error
+}
+
+type UserImportFacade interface {
+	Get(id string) (*userimport.Job, error)
+	Create(records string) (*userimport.Job, error)
+	AddRecords(id string, records string) (*userimport.Job, error)
+	Submit(id string) (*userimport.Job, error)
+	Resume(id string) (*userimport.Job, error)
+	ListRecordErrors(jobID string) ([]*userimport.RecordError, error)
+}
+
 type SessionFacade interface {
 	List(userID string) ([]session.Session, error)
 	Get(id string) (session.Session, error)
@@ -88,18 +117,22 @@ func NewLogger(lf *log.Factory) Logger { return Logger{lf.New("admin-graphql")}
 type Context struct {
 	GQLLogger Logger
 
-	Users          UserLoader
-	Identities     IdentityLoader
-	Authenticators AuthenticatorLoader
-	AuditLogs      AuditLogLoader
-
-	UserFacade          UserFacade
-	AuditLogFacade      AuditLogFacade
-	IdentityFacade      IdentityFacade
-	AuthenticatorFacade AuthenticatorFacade
-	VerificationFacade  VerificationFacade
-	SessionFacade       SessionFacade
-	UserProfileFacade   UserProfileFacade
+	Users             UserLoader
+	Identities        IdentityLoader
+	Authenticators    AuthenticatorLoader
+	AuditLogs         AuditLogLoader
+	WebhookDeliveries WebhookDeliveryLoader
+
+	UserFacade            UserFacade
+	AuditLogFacade        AuditLogFacade
+	IdentityFacade        IdentityFacade
+	AuthenticatorFacade   AuthenticatorFacade
+	VerificationFacade    VerificationFacade
+	SessionFacade         SessionFacade
+	AuthorizationFacade   AuthorizationFacade
+	UserProfileFacade     UserProfileFacade
+	WebhookDeliveryFacade WebhookDeliveryFacade
+	UserImportFacade      UserImportFacade
 }
 
 func (c *Context) Logger() *log.Logger {
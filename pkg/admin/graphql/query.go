@@ -9,6 +9,7 @@ import (
 	apimodel "github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/audit"
 	libuser "github.com/authgear/authgear-server/pkg/lib/authn/user"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
 	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
 )
 
@@ -150,6 +151,48 @@ var query = graphql.NewObject(graphql.ObjectConfig{
 					})
 				}
 
+				return graphqlutil.NewConnectionFromResult(lazyItems, result)
+			},
+		},
+		"webhookDeliveries": &graphql.Field{
+			Description: "Deliveries of non-blocking events to webhook handlers",
+			Type:        connWebhookDelivery.ConnectionType,
+			Args: relay.NewConnectionArgs(graphql.FieldConfigArgument{
+				"statuses": &graphql.ArgumentConfig{
+					Type: graphql.NewList(graphql.NewNonNull(webhookDeliveryStatus)),
+				},
+			}),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				gqlCtx := GQLContext(p.Context)
+
+				pageArgs := graphqlutil.NewPageArgs(relay.NewConnectionArguments(p.Args))
+
+				var statuses []hook.DeliveryStatus
+				if arr, ok := p.Args["statuses"].([]interface{}); ok {
+					for _, v := range arr {
+						if s, ok := v.(hook.DeliveryStatus); ok {
+							statuses = append(statuses, s)
+						}
+					}
+				}
+
+				queryOptions := hook.DeliveryQueryPageOptions{
+					Statuses: statuses,
+				}
+
+				refs, result, err := gqlCtx.WebhookDeliveryFacade.QueryPage(queryOptions, pageArgs)
+				if err != nil {
+					return nil, err
+				}
+
+				var lazyItems []graphqlutil.LazyItem
+				for _, ref := range refs {
+					lazyItems = append(lazyItems, graphqlutil.LazyItem{
+						Lazy:   gqlCtx.WebhookDeliveries.Load(ref.ID),
+						Cursor: graphqlutil.Cursor(ref.Cursor),
+					})
+				}
+
 				return graphqlutil.NewConnectionFromResult(lazyItems, result)
 			},
 		},
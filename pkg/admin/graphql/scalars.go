@@ -17,6 +17,11 @@ var AuditLogData = graphqlutil.NewJSONObjectScalar(
 	"The `AuditLogData` scalar type represents the data of the audit log",
 )
 
+var WebhookDeliveryPayload = graphqlutil.NewJSONObjectScalar(
+	"WebhookDeliveryPayload",
+	"The `WebhookDeliveryPayload` scalar type represents the event payload of the webhook delivery",
+)
+
 var UserStandardAttributes = graphqlutil.NewJSONObjectScalar(
 	"UserStandardAttributes",
 	"The `UserStandardAttributes` scalar type represents the standard attributes of the user",
@@ -11,6 +11,23 @@ import (
 
 const typeUser = "User"
 
+var authenticationLockout = graphql.NewObject(graphql.ObjectConfig{
+	Name: "AuthenticationLockout",
+	Fields: graphql.Fields{
+		"failedAttempts": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of failed authentication attempts within the history duration.",
+		},
+		"isLocked": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Boolean),
+		},
+		"lockedUntil": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "The time when the user is unlocked automatically.",
+		},
+	},
+})
+
 var nodeUser = node(
 	graphql.NewObject(graphql.ObjectConfig{
 		Name:        typeUser,
@@ -101,6 +118,42 @@ var nodeUser = node(
 					return graphqlutil.NewConnectionFromArray(sessions, args), nil
 				},
 			},
+			"authorizations": &graphql.Field{
+				Type: connAuthorization.ConnectionType,
+				Args: relay.ConnectionArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					source := p.Source.(*user.User)
+					gqlCtx := GQLContext(p.Context)
+					authzs, err := gqlCtx.AuthorizationFacade.List(source.ID)
+					if err != nil {
+						return nil, err
+					}
+
+					var authorizations []interface{}
+					for _, a := range authzs {
+						authorizations = append(authorizations, a)
+					}
+					args := relay.NewConnectionArguments(p.Args)
+					return graphqlutil.NewConnectionFromArray(authorizations, args), nil
+				},
+			},
+			"authenticationLockout": &graphql.Field{
+				Type:        graphql.NewNonNull(authenticationLockout),
+				Description: "The lockout state of the user caused by failed authentication attempts",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					source := p.Source.(*user.User)
+					gqlCtx := GQLContext(p.Context)
+					l, err := gqlCtx.UserFacade.GetLockout(source.ID)
+					if err != nil {
+						return nil, err
+					}
+					return map[string]interface{}{
+						"failedAttempts": l.FailedAttempts,
+						"isLocked":       l.IsLocked,
+						"lockedUntil":    l.LockedUntil,
+					}, nil
+				},
+			},
 			"isDisabled": &graphql.Field{
 				Type: graphql.NewNonNull(graphql.Boolean),
 			},
@@ -0,0 +1,89 @@
+package graphql
+
+import (
+	relay "github.com/authgear/graphql-go-relay"
+	"github.com/graphql-go/graphql"
+
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
+)
+
+var userImportJobStatus = graphql.NewEnum(graphql.EnumConfig{
+	Name: "UserImportJobStatus",
+	Values: graphql.EnumValueConfigMap{
+		"UPLOADING": &graphql.EnumValueConfig{
+			Value: userimport.JobStatusUploading,
+		},
+		"PENDING": &graphql.EnumValueConfig{
+			Value: userimport.JobStatusPending,
+		},
+		"RUNNING": &graphql.EnumValueConfig{
+			Value: userimport.JobStatusRunning,
+		},
+		"COMPLETED": &graphql.EnumValueConfig{
+			Value: userimport.JobStatusCompleted,
+		},
+		"FAILED": &graphql.EnumValueConfig{
+			Value: userimport.JobStatusFailed,
+		},
+	},
+})
+
+var userImportRecordError = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "UserImportRecordError",
+	Description: "Error of importing a record of user import job",
+	Fields: graphql.Fields{
+		"line": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"message": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+	},
+})
+
+const typeUserImportJob = "UserImportJob"
+
+var nodeUserImportJob = node(
+	graphql.NewObject(graphql.ObjectConfig{
+		Name:        typeUserImportJob,
+		Description: "Import of users from NDJSON records",
+		Interfaces: []*graphql.Interface{
+			nodeDefs.NodeInterface,
+		},
+		Fields: graphql.Fields{
+			"id": relay.GlobalIDField(typeUserImportJob, nil),
+			"createdAt": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+			"updatedAt": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+			"status": &graphql.Field{
+				Type: graphql.NewNonNull(userImportJobStatus),
+			},
+			"processedCount": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Number of processed lines, including blank lines.",
+			},
+			"importedCount": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"failedCount": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"errors": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userImportRecordError))),
+				Description: "The earliest record errors in the order of line.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					source := p.Source.(*userimport.Job)
+					gqlCtx := GQLContext(p.Context)
+					return gqlCtx.UserImportFacade.ListRecordErrors(source.ID)
+				},
+			},
+		},
+	}),
+	&userimport.Job{},
+	func(ctx *Context, id string) (interface{}, error) {
+		return ctx.UserImportFacade.Get(id)
+	},
+)
@@ -0,0 +1,217 @@
+package graphql
+
+import (
+	"github.com/authgear/graphql-go-relay"
+	"github.com/graphql-go/graphql"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
+)
+
+var createUserImportJobInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CreateUserImportJobInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"records": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The first batch of users to import in NDJSON, one record per line.",
+		},
+	},
+})
+
+var createUserImportJobPayload = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CreateUserImportJobPayload",
+	Fields: graphql.Fields{
+		"userImportJob": &graphql.Field{
+			Type: graphql.NewNonNull(nodeUserImportJob),
+		},
+	},
+})
+
+var _ = registerMutationField(
+	"createUserImportJob",
+	&graphql.Field{
+		Description: "Create user import job with the first batch of records",
+		Type:        graphql.NewNonNull(createUserImportJobPayload),
+		Args: graphql.FieldConfigArgument{
+			"input": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(createUserImportJobInput),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			input := p.Args["input"].(map[string]interface{})
+			records := input["records"].(string)
+
+			gqlCtx := GQLContext(p.Context)
+
+			job, err := gqlCtx.UserImportFacade.Create(records)
+			if err != nil {
+				return nil, err
+			}
+
+			return graphqlutil.NewLazyValue(map[string]interface{}{
+				"userImportJob": job,
+			}).Value, nil
+		},
+	},
+)
+
+var addUserImportJobRecordsInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "AddUserImportJobRecordsInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"userImportJobID": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "Target user import job ID.",
+		},
+		"records": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The next batch of users to import in NDJSON, one record per line.",
+		},
+	},
+})
+
+var addUserImportJobRecordsPayload = graphql.NewObject(graphql.ObjectConfig{
+	Name: "AddUserImportJobRecordsPayload",
+	Fields: graphql.Fields{
+		"userImportJob": &graphql.Field{
+			Type: graphql.NewNonNull(nodeUserImportJob),
+		},
+	},
+})
+
+var _ = registerMutationField(
+	"addUserImportJobRecords",
+	&graphql.Field{
+		Description: "Add a batch of records to user import job",
+		Type:        graphql.NewNonNull(addUserImportJobRecordsPayload),
+		Args: graphql.FieldConfigArgument{
+			"input": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(addUserImportJobRecordsInput),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			input := p.Args["input"].(map[string]interface{})
+			userImportJobID := input["userImportJobID"].(string)
+			records := input["records"].(string)
+
+			resolvedNodeID := relay.FromGlobalID(userImportJobID)
+			if resolvedNodeID == nil || resolvedNodeID.Type != typeUserImportJob {
+				return nil, apierrors.NewInvalid("invalid user import job ID")
+			}
+
+			gqlCtx := GQLContext(p.Context)
+
+			job, err := gqlCtx.UserImportFacade.AddRecords(resolvedNodeID.ID, records)
+			if err != nil {
+				return nil, err
+			}
+
+			return graphqlutil.NewLazyValue(map[string]interface{}{
+				"userImportJob": job,
+			}).Value, nil
+		},
+	},
+)
+
+var submitUserImportJobInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "SubmitUserImportJobInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"userImportJobID": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "Target user import job ID.",
+		},
+	},
+})
+
+var submitUserImportJobPayload = graphql.NewObject(graphql.ObjectConfig{
+	Name: "SubmitUserImportJobPayload",
+	Fields: graphql.Fields{
+		"userImportJob": &graphql.Field{
+			Type: graphql.NewNonNull(nodeUserImportJob),
+		},
+	},
+})
+
+var _ = registerMutationField(
+	"submitUserImportJob",
+	&graphql.Field{
+		Description: "Import the records of user import job in background",
+		Type:        graphql.NewNonNull(submitUserImportJobPayload),
+		Args: graphql.FieldConfigArgument{
+			"input": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(submitUserImportJobInput),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			input := p.Args["input"].(map[string]interface{})
+			userImportJobID := input["userImportJobID"].(string)
+
+			resolvedNodeID := relay.FromGlobalID(userImportJobID)
+			if resolvedNodeID == nil || resolvedNodeID.Type != typeUserImportJob {
+				return nil, apierrors.NewInvalid("invalid user import job ID")
+			}
+
+			gqlCtx := GQLContext(p.Context)
+
+			job, err := gqlCtx.UserImportFacade.Submit(resolvedNodeID.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			return graphqlutil.NewLazyValue(map[string]interface{}{
+				"userImportJob": job,
+			}).Value, nil
+		},
+	},
+)
+
+var resumeUserImportJobInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "ResumeUserImportJobInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"userImportJobID": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "Target user import job ID.",
+		},
+	},
+})
+
+var resumeUserImportJobPayload = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ResumeUserImportJobPayload",
+	Fields: graphql.Fields{
+		"userImportJob": &graphql.Field{
+			Type: graphql.NewNonNull(nodeUserImportJob),
+		},
+	},
+})
+
+var _ = registerMutationField(
+	"resumeUserImportJob",
+	&graphql.Field{
+		Description: "Resume user import job from the first unprocessed record",
+		Type:        graphql.NewNonNull(resumeUserImportJobPayload),
+		Args: graphql.FieldConfigArgument{
+			"input": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(resumeUserImportJobInput),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			input := p.Args["input"].(map[string]interface{})
+			userImportJobID := input["userImportJobID"].(string)
+
+			resolvedNodeID := relay.FromGlobalID(userImportJobID)
+			if resolvedNodeID == nil || resolvedNodeID.Type != typeUserImportJob {
+				return nil, apierrors.NewInvalid("invalid user import job ID")
+			}
+
+			gqlCtx := GQLContext(p.Context)
+
+			job, err := gqlCtx.UserImportFacade.Resume(resolvedNodeID.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			return graphqlutil.NewLazyValue(map[string]interface{}{
+				"userImportJob": job,
+			}).Value, nil
+		},
+	},
+)
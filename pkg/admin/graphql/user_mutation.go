@@ -263,6 +263,59 @@ var _ = registerMutationField(
 	},
 )
 
+var unlockUserInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UnlockUserInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"userID": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "Target user ID.",
+		},
+	},
+})
+
+var unlockUserPayload = graphql.NewObject(graphql.ObjectConfig{
+	Name: "UnlockUserPayload",
+	Fields: graphql.Fields{
+		"user": &graphql.Field{
+			Type: graphql.NewNonNull(nodeUser),
+		},
+	},
+})
+
+var _ = registerMutationField(
+	"unlockUser",
+	&graphql.Field{
+		Description: "Unlock user locked due to failed authentication attempts",
+		Type:        graphql.NewNonNull(unlockUserPayload),
+		Args: graphql.FieldConfigArgument{
+			"input": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(unlockUserInput),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			input := p.Args["input"].(map[string]interface{})
+
+			userNodeID := input["userID"].(string)
+			resolvedNodeID := relay.FromGlobalID(userNodeID)
+			if resolvedNodeID == nil || resolvedNodeID.Type != typeUser {
+				return nil, apierrors.NewInvalid("invalid user ID")
+			}
+			userID := resolvedNodeID.ID
+
+			gqlCtx := GQLContext(p.Context)
+
+			err := gqlCtx.UserFacade.Unlock(userID)
+			if err != nil {
+				return nil, err
+			}
+
+			return graphqlutil.NewLazyValue(map[string]interface{}{
+				"user": gqlCtx.Users.Load(userID),
+			}).Value, nil
+		},
+	},
+)
+
 var updateUserInput = graphql.NewInputObject(graphql.InputObjectConfig{
 	Name: "UpdateUserInput",
 	Fields: graphql.InputObjectConfigFieldMap{
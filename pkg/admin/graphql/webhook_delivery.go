@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"encoding/json"
+
+	relay "github.com/authgear/graphql-go-relay"
+	"github.com/graphql-go/graphql"
+
+	"github.com/authgear/authgear-server/pkg/lib/hook"
+	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
+)
+
+var webhookDeliveryStatus = graphql.NewEnum(graphql.EnumConfig{
+	Name: "WebhookDeliveryStatus",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value: hook.DeliveryStatusPending,
+		},
+		"SUCCEEDED": &graphql.EnumValueConfig{
+			Value: hook.DeliveryStatusSucceeded,
+		},
+		"FAILED": &graphql.EnumValueConfig{
+			Value: hook.DeliveryStatusFailed,
+		},
+	},
+})
+
+var webhookDeliveryAttempt = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "WebhookDeliveryAttempt",
+	Description: "Attempt of webhook delivery",
+	Fields: graphql.Fields{
+		"createdAt": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.DateTime),
+		},
+		"statusCode": &graphql.Field{
+			Type: graphql.Int,
+		},
+		"latencyMilliseconds": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				source := p.Source.(*hook.DeliveryAttempt)
+				return source.Latency.Milliseconds(), nil
+			},
+		},
+		"responseExcerpt": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"error": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+	},
+})
+
+const typeWebhookDelivery = "WebhookDelivery"
+
+var nodeWebhookDelivery = node(
+	graphql.NewObject(graphql.ObjectConfig{
+		Name:        typeWebhookDelivery,
+		Description: "Delivery of non-blocking event to webhook handler",
+		Interfaces: []*graphql.Interface{
+			nodeDefs.NodeInterface,
+		},
+		Fields: graphql.Fields{
+			"id": relay.GlobalIDField(typeWebhookDelivery, nil),
+			"createdAt": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+			"updatedAt": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.DateTime),
+			},
+			"eventID": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"eventType": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"url": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+			"status": &graphql.Field{
+				Type: graphql.NewNonNull(webhookDeliveryStatus),
+			},
+			"attemptCount": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					source := p.Source.(*hook.Delivery)
+					return source.Attempts, nil
+				},
+			},
+			"nextAttemptAt": &graphql.Field{
+				Type: graphql.DateTime,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					source := p.Source.(*hook.Delivery)
+					return source.NextAttemptAt, nil
+				},
+			},
+			"lastAttemptedAt": &graphql.Field{
+				Type: graphql.DateTime,
+			},
+			"payload": &graphql.Field{
+				Type: graphql.NewNonNull(WebhookDeliveryPayload),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					source := p.Source.(*hook.Delivery)
+					var payload map[string]interface{}
+					err := json.Unmarshal(source.Payload, &payload)
+					if err != nil {
+						return nil, err
+					}
+					return payload, nil
+				},
+			},
+			"attempts": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(webhookDeliveryAttempt))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					source := p.Source.(*hook.Delivery)
+					gqlCtx := GQLContext(p.Context)
+					return gqlCtx.WebhookDeliveryFacade.ListAttempts(source.ID)
+				},
+			},
+		},
+	}),
+	&hook.Delivery{},
+	func(ctx *Context, id string) (interface{}, error) {
+		return ctx.WebhookDeliveries.Load(id).Value, nil
+	},
+)
+
+var connWebhookDelivery = graphqlutil.NewConnectionDef(nodeWebhookDelivery)
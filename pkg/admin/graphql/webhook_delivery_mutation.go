@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"github.com/authgear/graphql-go-relay"
+	"github.com/graphql-go/graphql"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
+)
+
+var redeliverWebhookDeliveryInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "RedeliverWebhookDeliveryInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"webhookDeliveryID": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "Target webhook delivery ID.",
+		},
+	},
+})
+
+var redeliverWebhookDeliveryPayload = graphql.NewObject(graphql.ObjectConfig{
+	Name: "RedeliverWebhookDeliveryPayload",
+	Fields: graphql.Fields{
+		"webhookDelivery": &graphql.Field{
+			Type: graphql.NewNonNull(nodeWebhookDelivery),
+		},
+	},
+})
+
+var _ = registerMutationField(
+	"redeliverWebhookDelivery",
+	&graphql.Field{
+		Description: "Re-deliver webhook delivery immediately",
+		Type:        graphql.NewNonNull(redeliverWebhookDeliveryPayload),
+		Args: graphql.FieldConfigArgument{
+			"input": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(redeliverWebhookDeliveryInput),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			input := p.Args["input"].(map[string]interface{})
+			webhookDeliveryID := input["webhookDeliveryID"].(string)
+
+			resolvedNodeID := relay.FromGlobalID(webhookDeliveryID)
+			if resolvedNodeID == nil || resolvedNodeID.Type != typeWebhookDelivery {
+				return nil, apierrors.NewInvalid("invalid webhook delivery ID")
+			}
+
+			gqlCtx := GQLContext(p.Context)
+
+			delivery, err := gqlCtx.WebhookDeliveryFacade.Redeliver(resolvedNodeID.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			return graphqlutil.NewLazyValue(map[string]interface{}{
+				"webhookDelivery": delivery,
+			}).Value, nil
+		},
+	},
+)
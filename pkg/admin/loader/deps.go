@@ -7,4 +7,5 @@ var DependencySet = wire.NewSet(
 	NewIdentityLoader,
 	NewAuthenticatorLoader,
 	NewAuditLogLoader,
+	NewWebhookDeliveryLoader,
 )
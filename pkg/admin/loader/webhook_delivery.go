@@ -0,0 +1,57 @@
+package loader
+
+import (
+	"github.com/authgear/authgear-server/pkg/lib/hook"
+	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
+)
+
+type WebhookDeliveryQuery interface {
+	GetByIDs(ids []string) ([]*hook.Delivery, error)
+}
+
+type WebhookDeliveryLoader struct {
+	*graphqlutil.DataLoader `wire:"-"`
+
+	Query WebhookDeliveryQuery
+}
+
+func NewWebhookDeliveryLoader(query WebhookDeliveryQuery) *WebhookDeliveryLoader {
+	l := &WebhookDeliveryLoader{
+		Query: query,
+	}
+	l.DataLoader = graphqlutil.NewDataLoader(l.LoadFunc)
+	return l
+}
+
+func (l *WebhookDeliveryLoader) LoadFunc(keys []interface{}) ([]interface{}, error) {
+	// Prepare IDs.
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = key.(string)
+	}
+
+	// Get entities.
+	entities, err := l.Query.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create map.
+	entityMap := make(map[string]*hook.Delivery)
+	for _, entity := range entities {
+		entityMap[entity.ID] = entity
+	}
+
+	// Ensure output is in correct order.
+	out := make([]interface{}, len(keys))
+	for i, id := range ids {
+		entity := entityMap[id]
+		if err != nil {
+			out[i] = nil
+		} else {
+			out[i] = entity
+		}
+	}
+
+	return out, nil
+}
@@ -20,6 +20,10 @@ func (WebEndpoints) VerifyIdentityURL(code string, webStateID string) *url.URL {
 	panic("not implemented")
 }
 
+func (WebEndpoints) MagicLinkURL(authenticatorID string, token string) *url.URL {
+	panic("not implemented")
+}
+
 func (WebEndpoints) ResetPasswordURL(code string) *url.URL {
 	panic("not implemented")
 }
@@ -28,6 +32,14 @@ func (WebEndpoints) SSOCallbackURL(providerConfig config.OAuthSSOProviderConfig)
 	panic("not implemented")
 }
 
+func (WebEndpoints) SAMLMetadataURL(alias string) *url.URL {
+	panic("not implemented")
+}
+
+func (WebEndpoints) SAMLACSURL(alias string) *url.URL {
+	panic("not implemented")
+}
+
 func (WebEndpoints) AuthorizeEndpointURL(config.OAuthSSOProviderConfig) *url.URL {
 	// WechatURLProvider
 	panic("not implemented")
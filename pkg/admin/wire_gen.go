@@ -17,6 +17,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/audit"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/passkey"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
 	service2 "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/service"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/totp"
@@ -26,6 +27,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/loginid"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/oauth"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/service"
+	"github.com/authgear/authgear-server/pkg/lib/authn/lockout"
 	"github.com/authgear/authgear-server/pkg/lib/authn/mfa"
 	"github.com/authgear/authgear-server/pkg/lib/authn/otp"
 	"github.com/authgear/authgear-server/pkg/lib/authn/sso"
@@ -35,6 +37,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/deps"
 	"github.com/authgear/authgear-server/pkg/lib/elasticsearch"
 	"github.com/authgear/authgear-server/pkg/lib/event"
+	"github.com/authgear/authgear-server/pkg/lib/eventstream"
 	"github.com/authgear/authgear-server/pkg/lib/facade"
 	"github.com/authgear/authgear-server/pkg/lib/feature/customattrs"
 	"github.com/authgear/authgear-server/pkg/lib/feature/forgotpassword"
@@ -57,6 +60,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/session/access"
 	"github.com/authgear/authgear-server/pkg/lib/session/idpsession"
 	"github.com/authgear/authgear-server/pkg/lib/translation"
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
 	"github.com/authgear/authgear-server/pkg/lib/web"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/httproute"
@@ -238,7 +242,9 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -252,6 +258,7 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -283,22 +290,56 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	httpConfig := appConfig.HTTP
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service4 := &service2.Service{
 		Store:       store2,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -363,9 +404,28 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		Store:    readStore,
 	}
 	auditLogLoader := loader.NewAuditLogLoader(query)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	queue := appProvider.TaskQueue
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	webhookDeliveryLoader := loader.NewWebhookDeliveryLoader(deliveryService)
 	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
 	client := elasticsearch.NewClient(elasticsearchCredentials)
-	queue := appProvider.TaskQueue
 	elasticsearchService := &elasticsearch.Service{
 		AppID:     appID,
 		Client:    client,
@@ -384,7 +444,6 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 	engine := &template.Engine{
 		Resolver: resolver,
 	}
-	httpConfig := appConfig.HTTP
 	localizationConfig := appConfig.Localization
 	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
 	staticAssetResolver := &web.StaticAssetResolver{
@@ -403,6 +462,7 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -411,17 +471,17 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 	}
 	hookLogger := hook.NewLogger(factory)
 	hookConfig := appConfig.Hook
-	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
-	asyncHTTPClient := hook.NewAsyncHTTPClient()
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -439,7 +499,19 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -480,6 +552,7 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	stdattrsService := &stdattrs.Service{
 		UserProfileConfig: userProfileConfig,
@@ -560,8 +633,10 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       webEndpoints,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -572,6 +647,8 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  webEndpoints,
 		Clock:                        clockClock,
 		WechatURLProvider:            webEndpoints,
+		SAMLURLProvider:              webEndpoints,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -651,6 +728,7 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -659,6 +737,7 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -689,6 +768,8 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		Users:              userFacade,
 		StandardAttributes: serviceNoEvent,
 		Interaction:        serviceInteractionService,
+		Events:             eventService,
+		Lockout:            lockoutService,
 	}
 	auditLogFeatureConfig := featureConfig.AuditLog
 	auditLogFacade := &facade2.AuditLogFacade{
@@ -706,6 +787,8 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 	}
 	verificationFacade := &facade2.VerificationFacade{
 		Verification: verificationService,
+		Identities:   serviceService,
+		Events:       eventService,
 	}
 	manager2 := &session.Manager{
 		IDPSessions:         idpsessionManager,
@@ -715,24 +798,51 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 	sessionFacade := &facade2.SessionFacade{
 		Sessions: manager2,
 	}
+	authorizationService := &oauth2.AuthorizationService{
+		Store:          authorizationStore,
+		OfflineGrants:  redisStore,
+		SessionManager: manager2,
+	}
+	authorizationFacade := &facade2.AuthorizationFacade{
+		Authorizations: authorizationService,
+	}
 	userProfileFacade := &facade2.UserProfileFacade{
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
 		Events:             eventService,
 	}
+	webhookDeliveryFacade := &facade2.WebhookDeliveryFacade{
+		WebhookDeliveries: deliveryService,
+	}
+	userimportStore := &userimport.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	userimportService := &userimport.Service{
+		Clock:     clockClock,
+		Store:     userimportStore,
+		TaskQueue: queue,
+	}
+	userImportFacade := &facade2.UserImportFacade{
+		UserImports: userimportService,
+	}
 	graphqlContext := &graphql.Context{
-		GQLLogger:           logger,
-		Users:               userLoader,
-		Identities:          identityLoader,
-		Authenticators:      authenticatorLoader,
-		AuditLogs:           auditLogLoader,
-		UserFacade:          facadeUserFacade,
-		AuditLogFacade:      auditLogFacade,
-		IdentityFacade:      facadeIdentityFacade,
-		AuthenticatorFacade: facadeAuthenticatorFacade,
-		VerificationFacade:  verificationFacade,
-		SessionFacade:       sessionFacade,
-		UserProfileFacade:   userProfileFacade,
+		GQLLogger:             logger,
+		Users:                 userLoader,
+		Identities:            identityLoader,
+		Authenticators:        authenticatorLoader,
+		AuditLogs:             auditLogLoader,
+		WebhookDeliveries:     webhookDeliveryLoader,
+		UserFacade:            facadeUserFacade,
+		AuditLogFacade:        auditLogFacade,
+		IdentityFacade:        facadeIdentityFacade,
+		AuthenticatorFacade:   facadeAuthenticatorFacade,
+		VerificationFacade:    verificationFacade,
+		SessionFacade:         sessionFacade,
+		AuthorizationFacade:   authorizationFacade,
+		UserProfileFacade:     userProfileFacade,
+		WebhookDeliveryFacade: webhookDeliveryFacade,
+		UserImportFacade:      userImportFacade,
 	}
 	graphQLHandler := &transport.GraphQLHandler{
 		GraphQLContext: graphqlContext,
@@ -0,0 +1,49 @@
+package blocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	IdentityPreCreate event.Type = "identity.pre_create"
+)
+
+type IdentityPreCreateBlockingEventPayload struct {
+	UserRef   model.UserRef  `json:"-" resolve:"user"`
+	UserModel model.User     `json:"user"`
+	Identity  model.Identity `json:"identity"`
+	AdminAPI  bool           `json:"-"`
+}
+
+func (e *IdentityPreCreateBlockingEventPayload) BlockingEventType() event.Type {
+	return IdentityPreCreate
+}
+
+func (e *IdentityPreCreateBlockingEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *IdentityPreCreateBlockingEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *IdentityPreCreateBlockingEventPayload) FillContext(ctx *event.Context) {
+}
+
+func (e *IdentityPreCreateBlockingEventPayload) ApplyMutations(mutations event.Mutations) (event.BlockingPayload, bool) {
+	user, mutated := ApplyMutations(e.UserModel, mutations)
+	if mutated {
+		copied := *e
+		copied.UserModel = user
+		return &copied, true
+	}
+
+	return e, false
+}
+
+func (e *IdentityPreCreateBlockingEventPayload) GenerateFullMutations() event.Mutations {
+	return GenerateFullMutations(e.UserModel)
+}
+
+var _ event.BlockingPayload = &IdentityPreCreateBlockingEventPayload{}
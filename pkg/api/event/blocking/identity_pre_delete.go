@@ -0,0 +1,44 @@
+package blocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	IdentityPreDelete event.Type = "identity.pre_delete"
+)
+
+type IdentityPreDeleteBlockingEventPayload struct {
+	UserRef   model.UserRef  `json:"-" resolve:"user"`
+	UserModel model.User     `json:"user"`
+	Identity  model.Identity `json:"identity"`
+	AdminAPI  bool           `json:"-"`
+}
+
+func (e *IdentityPreDeleteBlockingEventPayload) BlockingEventType() event.Type {
+	return IdentityPreDelete
+}
+
+func (e *IdentityPreDeleteBlockingEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *IdentityPreDeleteBlockingEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *IdentityPreDeleteBlockingEventPayload) FillContext(ctx *event.Context) {
+}
+
+// ApplyMutations ignores the mutations.
+// The event is for allowing or disallowing the operation only.
+func (e *IdentityPreDeleteBlockingEventPayload) ApplyMutations(mutations event.Mutations) (event.BlockingPayload, bool) {
+	return e, false
+}
+
+func (e *IdentityPreDeleteBlockingEventPayload) GenerateFullMutations() event.Mutations {
+	return event.Mutations{}
+}
+
+var _ event.BlockingPayload = &IdentityPreDeleteBlockingEventPayload{}
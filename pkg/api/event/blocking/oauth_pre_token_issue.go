@@ -0,0 +1,48 @@
+package blocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	OAuthPreTokenIssue event.Type = "oauth.pre_token_issue"
+)
+
+type OAuthPreTokenIssueBlockingEventPayload struct {
+	UserRef model.UserRef `json:"-" resolve:"user"`
+	// UserModel is nil if the tokens are not issued to a user,
+	// i.e. client_credentials grant.
+	UserModel *model.User `json:"user,omitempty"`
+	ClientID  string      `json:"client_id"`
+	GrantType string      `json:"grant_type"`
+	Scopes    []string    `json:"scopes"`
+	AdminAPI  bool        `json:"-"`
+}
+
+func (e *OAuthPreTokenIssueBlockingEventPayload) BlockingEventType() event.Type {
+	return OAuthPreTokenIssue
+}
+
+func (e *OAuthPreTokenIssueBlockingEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *OAuthPreTokenIssueBlockingEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *OAuthPreTokenIssueBlockingEventPayload) FillContext(ctx *event.Context) {
+}
+
+// ApplyMutations ignores the mutations.
+// The event is for allowing or disallowing the operation only.
+func (e *OAuthPreTokenIssueBlockingEventPayload) ApplyMutations(mutations event.Mutations) (event.BlockingPayload, bool) {
+	return e, false
+}
+
+func (e *OAuthPreTokenIssueBlockingEventPayload) GenerateFullMutations() event.Mutations {
+	return event.Mutations{}
+}
+
+var _ event.BlockingPayload = &OAuthPreTokenIssueBlockingEventPayload{}
@@ -0,0 +1,60 @@
+package blocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	OIDCJWTPreCreate event.Type = "oidc.jwt.pre_create"
+)
+
+type OIDCJWT struct {
+	// Payload is the custom claims to be added to the JWT.
+	Payload map[string]interface{} `json:"payload"`
+}
+
+type OIDCJWTPreCreateBlockingEventPayload struct {
+	UserRef   model.UserRef `json:"-" resolve:"user"`
+	UserModel model.User    `json:"user"`
+	ClientID  string        `json:"client_id"`
+	Scopes    []string      `json:"scopes"`
+	JWT       OIDCJWT       `json:"jwt"`
+	AdminAPI  bool          `json:"-"`
+}
+
+func (e *OIDCJWTPreCreateBlockingEventPayload) BlockingEventType() event.Type {
+	return OIDCJWTPreCreate
+}
+
+func (e *OIDCJWTPreCreateBlockingEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *OIDCJWTPreCreateBlockingEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *OIDCJWTPreCreateBlockingEventPayload) FillContext(ctx *event.Context) {
+}
+
+// ApplyMutations mutates the payload in place,
+// so that the dispatcher can read the resulting custom claims.
+func (e *OIDCJWTPreCreateBlockingEventPayload) ApplyMutations(mutations event.Mutations) (event.BlockingPayload, bool) {
+	if mutations.JWT.Payload != nil {
+		e.JWT.Payload = mutations.JWT.Payload
+		return e, true
+	}
+
+	return e, false
+}
+
+func (e *OIDCJWTPreCreateBlockingEventPayload) GenerateFullMutations() event.Mutations {
+	return event.Mutations{
+		JWT: event.JWTMutations{
+			Payload: e.JWT.Payload,
+		},
+	}
+}
+
+var _ event.BlockingPayload = &OIDCJWTPreCreateBlockingEventPayload{}
@@ -0,0 +1,44 @@
+package blocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	UserPreAuthenticate event.Type = "user.pre_authenticate"
+)
+
+type UserPreAuthenticateBlockingEventPayload struct {
+	UserRef   model.UserRef `json:"-" resolve:"user"`
+	UserModel model.User    `json:"user"`
+	AMR       []string      `json:"amr"`
+	AdminAPI  bool          `json:"-"`
+}
+
+func (e *UserPreAuthenticateBlockingEventPayload) BlockingEventType() event.Type {
+	return UserPreAuthenticate
+}
+
+func (e *UserPreAuthenticateBlockingEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *UserPreAuthenticateBlockingEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *UserPreAuthenticateBlockingEventPayload) FillContext(ctx *event.Context) {
+}
+
+// ApplyMutations ignores the mutations.
+// The event is for allowing or disallowing the operation only.
+func (e *UserPreAuthenticateBlockingEventPayload) ApplyMutations(mutations event.Mutations) (event.BlockingPayload, bool) {
+	return e, false
+}
+
+func (e *UserPreAuthenticateBlockingEventPayload) GenerateFullMutations() event.Mutations {
+	return event.Mutations{}
+}
+
+var _ event.BlockingPayload = &UserPreAuthenticateBlockingEventPayload{}
@@ -42,6 +42,14 @@ var HookResponseSchema = validation.NewSimpleSchema(`
 									"type": "object"
 								}
 							}
+						},
+						"jwt": {
+							"type": "object",
+							"properties": {
+								"payload": {
+									"type": "object"
+								}
+							}
 						}
 					}
 				}
@@ -71,6 +79,7 @@ type HookResponse struct {
 
 type Mutations struct {
 	User UserMutations `json:"user"`
+	JWT  JWTMutations  `json:"jwt"`
 }
 
 type UserMutations struct {
@@ -78,6 +87,10 @@ type UserMutations struct {
 	CustomAttributes   map[string]interface{} `json:"custom_attributes,omitempty"`
 }
 
+type JWTMutations struct {
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
 func ParseHookResponse(r io.Reader) (*HookResponse, error) {
 	var resp HookResponse
 	if err := HookResponseSchema.Validator().Parse(r, &resp); err != nil {
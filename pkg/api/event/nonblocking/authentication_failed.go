@@ -16,6 +16,9 @@ type AuthenticationFailedEventPayload struct {
 	UserModel           model.User    `json:"user"`
 	AuthenticationStage string        `json:"authentication_stage"`
 	AuthenticationType  string        `json:"authenticator_type"`
+	// Lockout is the lockout state of the user after the failed attempt,
+	// if the attempt is counted towards the lockout.
+	Lockout *model.AuthenticationLockout `json:"lockout,omitempty"`
 }
 
 func (e *AuthenticationFailedEventPayload) NonBlockingEventType() event.Type {
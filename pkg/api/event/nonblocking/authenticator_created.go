@@ -0,0 +1,42 @@
+package nonblocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	AuthenticatorCreated event.Type = "authenticator.created"
+)
+
+type AuthenticatorCreatedEventPayload struct {
+	UserRef       model.UserRef       `json:"-" resolve:"user"`
+	UserModel     model.User          `json:"user"`
+	Authenticator model.Authenticator `json:"authenticator"`
+	AdminAPI      bool                `json:"-"`
+}
+
+func (e *AuthenticatorCreatedEventPayload) NonBlockingEventType() event.Type {
+	return AuthenticatorCreated
+}
+
+func (e *AuthenticatorCreatedEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *AuthenticatorCreatedEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *AuthenticatorCreatedEventPayload) FillContext(ctx *event.Context) {
+}
+
+func (e *AuthenticatorCreatedEventPayload) ForWebHook() bool {
+	return true
+}
+
+func (e *AuthenticatorCreatedEventPayload) ForAudit() bool {
+	return true
+}
+
+var _ event.NonBlockingPayload = &AuthenticatorCreatedEventPayload{}
@@ -0,0 +1,42 @@
+package nonblocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	AuthenticatorDeleted event.Type = "authenticator.deleted"
+)
+
+type AuthenticatorDeletedEventPayload struct {
+	UserRef       model.UserRef       `json:"-" resolve:"user"`
+	UserModel     model.User          `json:"user"`
+	Authenticator model.Authenticator `json:"authenticator"`
+	AdminAPI      bool                `json:"-"`
+}
+
+func (e *AuthenticatorDeletedEventPayload) NonBlockingEventType() event.Type {
+	return AuthenticatorDeleted
+}
+
+func (e *AuthenticatorDeletedEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *AuthenticatorDeletedEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *AuthenticatorDeletedEventPayload) FillContext(ctx *event.Context) {
+}
+
+func (e *AuthenticatorDeletedEventPayload) ForWebHook() bool {
+	return true
+}
+
+func (e *AuthenticatorDeletedEventPayload) ForAudit() bool {
+	return true
+}
+
+var _ event.NonBlockingPayload = &AuthenticatorDeletedEventPayload{}
@@ -0,0 +1,42 @@
+package nonblocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	IdentityVerified event.Type = "identity.verified"
+)
+
+type IdentityVerifiedEventPayload struct {
+	UserRef   model.UserRef  `json:"-" resolve:"user"`
+	UserModel model.User     `json:"user"`
+	Identity  model.Identity `json:"identity"`
+	AdminAPI  bool           `json:"-"`
+}
+
+func (e *IdentityVerifiedEventPayload) NonBlockingEventType() event.Type {
+	return IdentityVerified
+}
+
+func (e *IdentityVerifiedEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *IdentityVerifiedEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *IdentityVerifiedEventPayload) FillContext(ctx *event.Context) {
+}
+
+func (e *IdentityVerifiedEventPayload) ForWebHook() bool {
+	return true
+}
+
+func (e *IdentityVerifiedEventPayload) ForAudit() bool {
+	return true
+}
+
+var _ event.NonBlockingPayload = &IdentityVerifiedEventPayload{}
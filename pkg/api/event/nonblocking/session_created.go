@@ -0,0 +1,42 @@
+package nonblocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	SessionCreated event.Type = "session.created"
+)
+
+type SessionCreatedEventPayload struct {
+	UserRef   model.UserRef `json:"-" resolve:"user"`
+	UserModel model.User    `json:"user"`
+	Session   model.Session `json:"session"`
+	AdminAPI  bool          `json:"-"`
+}
+
+func (e *SessionCreatedEventPayload) NonBlockingEventType() event.Type {
+	return SessionCreated
+}
+
+func (e *SessionCreatedEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *SessionCreatedEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *SessionCreatedEventPayload) FillContext(ctx *event.Context) {
+}
+
+func (e *SessionCreatedEventPayload) ForWebHook() bool {
+	return true
+}
+
+func (e *SessionCreatedEventPayload) ForAudit() bool {
+	return true
+}
+
+var _ event.NonBlockingPayload = &SessionCreatedEventPayload{}
@@ -0,0 +1,42 @@
+package nonblocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	SessionRevoked event.Type = "session.revoked"
+)
+
+type SessionRevokedEventPayload struct {
+	UserRef   model.UserRef `json:"-" resolve:"user"`
+	UserModel model.User    `json:"user"`
+	Session   model.Session `json:"session"`
+	AdminAPI  bool          `json:"-"`
+}
+
+func (e *SessionRevokedEventPayload) NonBlockingEventType() event.Type {
+	return SessionRevoked
+}
+
+func (e *SessionRevokedEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *SessionRevokedEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *SessionRevokedEventPayload) FillContext(ctx *event.Context) {
+}
+
+func (e *SessionRevokedEventPayload) ForWebHook() bool {
+	return true
+}
+
+func (e *SessionRevokedEventPayload) ForAudit() bool {
+	return true
+}
+
+var _ event.NonBlockingPayload = &SessionRevokedEventPayload{}
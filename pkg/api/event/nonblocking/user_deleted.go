@@ -0,0 +1,41 @@
+package nonblocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	UserDeleted event.Type = "user.deleted"
+)
+
+type UserDeletedEventPayload struct {
+	UserRef   model.UserRef `json:"-" resolve:"user"`
+	UserModel model.User    `json:"user"`
+	AdminAPI  bool          `json:"-"`
+}
+
+func (e *UserDeletedEventPayload) NonBlockingEventType() event.Type {
+	return UserDeleted
+}
+
+func (e *UserDeletedEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *UserDeletedEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *UserDeletedEventPayload) FillContext(ctx *event.Context) {
+}
+
+func (e *UserDeletedEventPayload) ForWebHook() bool {
+	return true
+}
+
+func (e *UserDeletedEventPayload) ForAudit() bool {
+	return true
+}
+
+var _ event.NonBlockingPayload = &UserDeletedEventPayload{}
@@ -0,0 +1,41 @@
+package nonblocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	UserPasswordChanged event.Type = "user.password.changed"
+)
+
+type UserPasswordChangedEventPayload struct {
+	UserRef   model.UserRef `json:"-" resolve:"user"`
+	UserModel model.User    `json:"user"`
+	AdminAPI  bool          `json:"-"`
+}
+
+func (e *UserPasswordChangedEventPayload) NonBlockingEventType() event.Type {
+	return UserPasswordChanged
+}
+
+func (e *UserPasswordChangedEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *UserPasswordChangedEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *UserPasswordChangedEventPayload) FillContext(ctx *event.Context) {
+}
+
+func (e *UserPasswordChangedEventPayload) ForWebHook() bool {
+	return true
+}
+
+func (e *UserPasswordChangedEventPayload) ForAudit() bool {
+	return true
+}
+
+var _ event.NonBlockingPayload = &UserPasswordChangedEventPayload{}
@@ -0,0 +1,41 @@
+package nonblocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	UserPasswordReset event.Type = "user.password.reset"
+)
+
+type UserPasswordResetEventPayload struct {
+	UserRef   model.UserRef `json:"-" resolve:"user"`
+	UserModel model.User    `json:"user"`
+	AdminAPI  bool          `json:"-"`
+}
+
+func (e *UserPasswordResetEventPayload) NonBlockingEventType() event.Type {
+	return UserPasswordReset
+}
+
+func (e *UserPasswordResetEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *UserPasswordResetEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *UserPasswordResetEventPayload) FillContext(ctx *event.Context) {
+}
+
+func (e *UserPasswordResetEventPayload) ForWebHook() bool {
+	return true
+}
+
+func (e *UserPasswordResetEventPayload) ForAudit() bool {
+	return true
+}
+
+var _ event.NonBlockingPayload = &UserPasswordResetEventPayload{}
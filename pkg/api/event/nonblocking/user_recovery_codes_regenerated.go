@@ -0,0 +1,41 @@
+package nonblocking
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+)
+
+const (
+	UserRecoveryCodesRegenerated event.Type = "user.recovery_codes.regenerated"
+)
+
+type UserRecoveryCodesRegeneratedEventPayload struct {
+	UserRef   model.UserRef `json:"-" resolve:"user"`
+	UserModel model.User    `json:"user"`
+	AdminAPI  bool          `json:"-"`
+}
+
+func (e *UserRecoveryCodesRegeneratedEventPayload) NonBlockingEventType() event.Type {
+	return UserRecoveryCodesRegenerated
+}
+
+func (e *UserRecoveryCodesRegeneratedEventPayload) UserID() string {
+	return e.UserRef.ID
+}
+
+func (e *UserRecoveryCodesRegeneratedEventPayload) IsAdminAPI() bool {
+	return e.AdminAPI
+}
+
+func (e *UserRecoveryCodesRegeneratedEventPayload) FillContext(ctx *event.Context) {
+}
+
+func (e *UserRecoveryCodesRegeneratedEventPayload) ForWebHook() bool {
+	return true
+}
+
+func (e *UserRecoveryCodesRegeneratedEventPayload) ForAudit() bool {
+	return true
+}
+
+var _ event.NonBlockingPayload = &UserRecoveryCodesRegeneratedEventPayload{}
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"time"
+)
+
+// AuthenticationLockout is the lockout state of a user,
+// caused by failed attempts to authenticate.
+type AuthenticationLockout struct {
+	FailedAttempts int        `json:"failed_attempts"`
+	IsLocked       bool       `json:"is_locked"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+}
@@ -11,6 +11,7 @@ const (
 	AuthenticatorTypeTOTP     AuthenticatorType = "totp"
 	AuthenticatorTypeOOBEmail AuthenticatorType = "oob_otp_email"
 	AuthenticatorTypeOOBSMS   AuthenticatorType = "oob_otp_sms"
+	AuthenticatorTypePasskey  AuthenticatorType = "passkey"
 )
 
 type AuthenticatorOOBChannel string
@@ -30,3 +31,12 @@ func GetOOBAuthenticatorType(channel AuthenticatorOOBChannel) (AuthenticatorType
 		return "", errors.New("invalid oob channel")
 	}
 }
+
+type Authenticator struct {
+	Meta
+	UserID    string                 `json:"user_id"`
+	Type      AuthenticatorType      `json:"type"`
+	IsDefault bool                   `json:"is_default"`
+	Kind      string                 `json:"kind"`
+	Claims    map[string]interface{} `json:"claims"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+type Authorization struct {
+	Meta
+
+	UserID   string   `json:"userID"`
+	ClientID string   `json:"clientID"`
+	Scopes   []string `json:"scopes"`
+
+	// LastUsedAt is the last time the client accessed on behalf of the user,
+	// or the time the authorization was last updated if the client never did.
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
@@ -0,0 +1,157 @@
+// Package webhook provides helpers for webhook handlers to verify
+// the signature of webhook requests sent by Authgear.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderSignature is the header carrying the signature of the request.
+//
+// The value is a comma-separated list of key-value pairs, e.g.
+//
+//	t=1643000000,v1=key-1:5257a8...,v1=key-2:6ffbb5...
+//
+// t is the Unix timestamp at which the request is signed.
+// Each v1 is a signature of scheme v1, in the form of <key ID>:<hex-encoded signature>.
+// There is one signature for each active key, so that keys can be rotated without downtime.
+const HeaderSignature = "x-authgear-signature"
+
+// SchemeV1 signs "<timestamp>.<body>" with HMAC-SHA256.
+const SchemeV1 = "v1"
+
+// DefaultTolerance is the recommended maximum age of a request to be accepted.
+const DefaultTolerance = 5 * time.Minute
+
+var ErrInvalidSignatureHeader = errors.New("webhook: invalid signature header")
+var ErrTimestampOutsideTolerance = errors.New("webhook: timestamp outside tolerance")
+var ErrNoValidSignature = errors.New("webhook: no valid signature")
+
+// Key is a webhook signing key.
+type Key struct {
+	// ID is the key ID ("kid") of the key.
+	ID string
+	// Secret is the octet key.
+	Secret []byte
+}
+
+// Sign returns the value of HeaderSignature for body signed at timestamp by keys.
+func Sign(keys []Key, timestamp time.Time, body []byte) string {
+	t := timestamp.Unix()
+	parts := []string{fmt.Sprintf("t=%d", t)}
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s:%s", SchemeV1, key.ID, hex.EncodeToString(signV1(key.Secret, t, body))))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Verify verifies header, the value of HeaderSignature, against body.
+// The request is accepted if it was signed within tolerance before now,
+// and any of its signatures is made by one of keys.
+// If a key has an empty ID, it matches signatures of any key ID.
+func Verify(header string, body []byte, keys []Key, now time.Time, tolerance time.Duration) error {
+	t, signatures, err := parseHeader(header)
+	if err != nil {
+		return err
+	}
+
+	age := now.Sub(time.Unix(t, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrTimestampOutsideTolerance
+	}
+
+	for _, sig := range signatures {
+		for _, key := range keys {
+			if key.ID != "" && key.ID != sig.keyID {
+				continue
+			}
+			if hmac.Equal(signV1(key.Secret, t, body), sig.mac) {
+				return nil
+			}
+		}
+	}
+
+	return ErrNoValidSignature
+}
+
+// VerifyRequest verifies the request r with Verify using the current time.
+// The body of r is read and returned, and r.Body is replaced so that it can be read again.
+func VerifyRequest(r *http.Request, keys []Key, tolerance time.Duration) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	err = Verify(r.Header.Get(HeaderSignature), body, keys, time.Now(), tolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+type signature struct {
+	keyID string
+	mac   []byte
+}
+
+func parseHeader(header string) (t int64, signatures []signature, err error) {
+	hasTimestamp := false
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			err = ErrInvalidSignatureHeader
+			return
+		}
+
+		switch kv[0] {
+		case "t":
+			t, err = strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				err = ErrInvalidSignatureHeader
+				return
+			}
+			hasTimestamp = true
+		case SchemeV1:
+			i := strings.LastIndex(kv[1], ":")
+			if i < 0 {
+				err = ErrInvalidSignatureHeader
+				return
+			}
+			var mac []byte
+			mac, err = hex.DecodeString(kv[1][i+1:])
+			if err != nil {
+				err = ErrInvalidSignatureHeader
+				return
+			}
+			signatures = append(signatures, signature{keyID: kv[1][:i], mac: mac})
+		default:
+			// Ignore unknown schemes for forward compatibility.
+		}
+	}
+
+	if !hasTimestamp || len(signatures) == 0 {
+		err = ErrInvalidSignatureHeader
+		return
+	}
+
+	return
+}
+
+func signV1(secret []byte, t int64, body []byte) []byte {
+	hasher := hmac.New(sha256.New, secret)
+	_, _ = fmt.Fprintf(hasher, "%d.", t)
+	_, _ = hasher.Write(body)
+	return hasher.Sum(nil)
+}
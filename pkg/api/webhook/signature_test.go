@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSignature(t *testing.T) {
+	Convey("Signature", t, func() {
+		now := time.Date(2022, 1, 24, 0, 0, 0, 0, time.UTC)
+		body := []byte(`{"type":"user.created"}`)
+		key1 := Key{ID: "key-1", Secret: []byte("secret1")}
+		key2 := Key{ID: "key-2", Secret: []byte("secret2")}
+
+		Convey("should sign with all keys", func() {
+			header := Sign([]Key{key1, key2}, now, body)
+			So(header, ShouldStartWith, "t=1642982400,v1=key-1:")
+			So(header, ShouldContainSubstring, ",v1=key-2:")
+
+			So(Verify(header, body, []Key{key1}, now, DefaultTolerance), ShouldBeNil)
+			So(Verify(header, body, []Key{key2}, now, DefaultTolerance), ShouldBeNil)
+		})
+
+		Convey("should verify with key without ID", func() {
+			header := Sign([]Key{key1}, now, body)
+			So(Verify(header, body, []Key{{Secret: []byte("secret1")}}, now, DefaultTolerance), ShouldBeNil)
+		})
+
+		Convey("should reject unknown keys", func() {
+			header := Sign([]Key{key1}, now, body)
+			So(Verify(header, body, []Key{key2}, now, DefaultTolerance), ShouldEqual, ErrNoValidSignature)
+			So(Verify(header, body, []Key{{ID: "key-2", Secret: []byte("secret1")}}, now, DefaultTolerance), ShouldEqual, ErrNoValidSignature)
+		})
+
+		Convey("should reject tampered body", func() {
+			header := Sign([]Key{key1}, now, body)
+			So(Verify(header, []byte(`{"type":"user.deleted"}`), []Key{key1}, now, DefaultTolerance), ShouldEqual, ErrNoValidSignature)
+		})
+
+		Convey("should reject tampered timestamp", func() {
+			header := Sign([]Key{key1}, now, body)
+			header = strings.Replace(header, "t=1642982400", "t=1642982401", 1)
+			So(Verify(header, body, []Key{key1}, now, DefaultTolerance), ShouldEqual, ErrNoValidSignature)
+		})
+
+		Convey("should reject replayed requests", func() {
+			header := Sign([]Key{key1}, now, body)
+			So(Verify(header, body, []Key{key1}, now.Add(DefaultTolerance), DefaultTolerance), ShouldBeNil)
+			So(Verify(header, body, []Key{key1}, now.Add(DefaultTolerance+time.Second), DefaultTolerance), ShouldEqual, ErrTimestampOutsideTolerance)
+			So(Verify(header, body, []Key{key1}, now.Add(-DefaultTolerance-time.Second), DefaultTolerance), ShouldEqual, ErrTimestampOutsideTolerance)
+		})
+
+		Convey("should reject invalid headers", func() {
+			for _, header := range []string{
+				"",
+				"t=1642982400",
+				"v1=key-1:abcd",
+				"t=abc,v1=key-1:abcd",
+				"t=1642982400,v1=abcd",
+				"t=1642982400,v1=key-1:xyz",
+				"t=1642982400,v1",
+			} {
+				So(Verify(header, body, []Key{key1}, now, DefaultTolerance), ShouldEqual, ErrInvalidSignatureHeader)
+			}
+		})
+
+		Convey("should ignore unknown schemes", func() {
+			header := Sign([]Key{key1}, now, body) + ",v0=abcd"
+			So(Verify(header, body, []Key{key1}, now, DefaultTolerance), ShouldBeNil)
+		})
+
+		Convey("should verify request", func() {
+			r, err := http.NewRequest("POST", "https://example.com", strings.NewReader(string(body)))
+			So(err, ShouldBeNil)
+			r.Header.Set(HeaderSignature, Sign([]Key{key1}, time.Now(), body))
+
+			b, err := VerifyRequest(r, []Key{key1}, DefaultTolerance)
+			So(err, ShouldBeNil)
+			So(b, ShouldResemble, body)
+		})
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/auth/webapp"
 	"github.com/authgear/authgear-server/pkg/lib/analytic"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
+	authenticatoroob "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
 	authenticatorservice "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/service"
 	"github.com/authgear/authgear-server/pkg/lib/authn/challenge"
@@ -78,8 +79,10 @@ var DependencySet = wire.NewSet(
 	wire.Bind(new(oauthhandler.LoginHintHandler), new(*webapp.LoginHintHandler)),
 	wire.Bind(new(oidchandler.WebAppURLsProvider), new(*webapp.URLProvider)),
 	wire.Bind(new(sso.RedirectURLProvider), new(*webapp.URLProvider)),
+	wire.Bind(new(sso.SAMLURLProvider), new(*webapp.URLProvider)),
 	wire.Bind(new(forgotpassword.URLProvider), new(*webapp.URLProvider)),
 	wire.Bind(new(verification.WebAppURLProvider), new(*webapp.URLProvider)),
+	wire.Bind(new(authenticatoroob.WebAppURLProvider), new(*webapp.URLProvider)),
 	wire.Bind(new(sso.WechatURLProvider), new(*webapp.WechatURLProvider)),
 
 	wire.Bind(new(webapp.AnonymousIdentityProvider), new(*identityanonymous.Provider)),
@@ -93,6 +96,9 @@ var DependencySet = wire.NewSet(
 	wire.Bind(new(handleroauth.ProtocolFromWebAppHandler), new(*oauthhandler.AuthorizationHandler)),
 	wire.Bind(new(handleroauth.ProtocolTokenHandler), new(*oauthhandler.TokenHandler)),
 	wire.Bind(new(handleroauth.ProtocolRevokeHandler), new(*oauthhandler.RevokeHandler)),
+	wire.Bind(new(handleroauth.ProtocolIntrospectionHandler), new(*oauthhandler.IntrospectionHandler)),
+	wire.Bind(new(handleroauth.ProtocolDeviceAuthorizationHandler), new(*oauthhandler.DeviceAuthorizationHandler)),
+	wire.Bind(new(handleroauth.ProtocolPushedAuthorizationHandler), new(*oauthhandler.PushedAuthorizationHandler)),
 	wire.Bind(new(handleroauth.ProtocolEndSessionHandler), new(*oidchandler.EndSessionHandler)),
 	wire.Bind(new(handleroauth.ProtocolUserInfoProvider), new(*oidc.IDTokenIssuer)),
 	wire.Bind(new(handleroauth.JWSSource), new(*oidc.IDTokenIssuer)),
@@ -138,4 +144,9 @@ var DependencySet = wire.NewSet(
 	wire.Bind(new(handlerwebapp.SelectAccountIdentityService), new(*identityservice.Service)),
 	wire.Bind(new(handlerwebapp.SelectAccountUserService), new(*user.Queries)),
 	wire.Bind(new(handlerwebapp.AnalyticService), new(*analytic.Service)),
+	wire.Bind(new(handlerwebapp.DeviceVerificationService), new(*oauthhandler.DeviceAuthorizationHandler)),
+	wire.Bind(new(handlerwebapp.ConsentService), new(*oauthhandler.AuthorizationHandler)),
+	wire.Bind(new(handlerwebapp.SettingsAuthorizationService), new(*oauth.AuthorizationService)),
+	wire.Bind(new(handlerwebapp.SAMLMetadataProviderFactory), new(*sso.OAuthProviderFactory)),
+	wire.Bind(new(handlerwebapp.MagicLinkOOBCodeProvider), new(*authenticatoroob.Provider)),
 )
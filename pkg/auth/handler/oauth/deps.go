@@ -13,6 +13,12 @@ var DependencySet = wire.NewSet(
 	wire.Struct(new(TokenHandler), "*"),
 	NewRevokeHandlerLogger,
 	wire.Struct(new(RevokeHandler), "*"),
+	NewIntrospectionHandlerLogger,
+	wire.Struct(new(IntrospectionHandler), "*"),
+	NewDeviceAuthorizationHandlerLogger,
+	wire.Struct(new(DeviceAuthorizationHandler), "*"),
+	NewPushedAuthorizationRequestHandlerLogger,
+	wire.Struct(new(PushedAuthorizationRequestHandler), "*"),
 	wire.Struct(new(MetadataHandler), "*"),
 	NewJWKSHandlerLogger,
 	wire.Struct(new(JWKSHandler), "*"),
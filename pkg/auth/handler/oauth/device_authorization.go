@@ -0,0 +1,63 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+func ConfigureDeviceAuthorizationRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("POST", "OPTIONS").
+		WithPathPattern("/oauth2/device_authorization")
+}
+
+type ProtocolDeviceAuthorizationHandler interface {
+	Handle(req *http.Request, r protocol.DeviceAuthorizationRequest) httputil.Result
+}
+
+type DeviceAuthorizationHandlerLogger struct{ *log.Logger }
+
+func NewDeviceAuthorizationHandlerLogger(lf *log.Factory) DeviceAuthorizationHandlerLogger {
+	return DeviceAuthorizationHandlerLogger{lf.New("handler-device-authz")}
+}
+
+type DeviceAuthorizationHandler struct {
+	Logger   DeviceAuthorizationHandlerLogger
+	Database *appdb.Handle
+	Handler  ProtocolDeviceAuthorizationHandler
+}
+
+func (h *DeviceAuthorizationHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(rw, err.Error(), 400)
+		return
+	}
+
+	req := protocol.DeviceAuthorizationRequest{}
+	for name, values := range r.Form {
+		req[name] = values[0]
+	}
+
+	var result httputil.Result
+	err = h.Database.WithTx(func() error {
+		result = h.Handler.Handle(r, req)
+		if result.IsInternalError() {
+			return errAuthzInternalError
+		}
+		return nil
+	})
+
+	if err == nil || errors.Is(err, errAuthzInternalError) {
+		result.WriteResponse(rw, r)
+	} else {
+		h.Logger.WithError(err).Error("oauth device authorization handler failed")
+		http.Error(rw, "Internal Server Error", 500)
+	}
+}
@@ -0,0 +1,63 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+func ConfigureIntrospectionRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("POST", "OPTIONS").
+		WithPathPattern("/oauth2/introspect")
+}
+
+type ProtocolIntrospectionHandler interface {
+	Handle(req *http.Request, r protocol.IntrospectionRequest) httputil.Result
+}
+
+type IntrospectionHandlerLogger struct{ *log.Logger }
+
+func NewIntrospectionHandlerLogger(lf *log.Factory) IntrospectionHandlerLogger {
+	return IntrospectionHandlerLogger{lf.New("handler-introspect")}
+}
+
+type IntrospectionHandler struct {
+	Logger   IntrospectionHandlerLogger
+	Database *appdb.Handle
+	Handler  ProtocolIntrospectionHandler
+}
+
+func (h *IntrospectionHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(rw, err.Error(), 400)
+		return
+	}
+
+	req := protocol.IntrospectionRequest{}
+	for name, values := range r.Form {
+		req[name] = values[0]
+	}
+
+	var result httputil.Result
+	err = h.Database.WithTx(func() error {
+		result = h.Handler.Handle(r, req)
+		if result.IsInternalError() {
+			return errAuthzInternalError
+		}
+		return nil
+	})
+
+	if err == nil || errors.Is(err, errAuthzInternalError) {
+		result.WriteResponse(rw, r)
+	} else {
+		h.Logger.WithError(err).Error("oauth introspection handler failed")
+		http.Error(rw, "Internal Server Error", 500)
+	}
+}
@@ -0,0 +1,65 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+func ConfigurePushedAuthorizationRequestRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("POST", "OPTIONS").
+		WithPathPattern("/oauth2/par")
+}
+
+type ProtocolPushedAuthorizationHandler interface {
+	Handle(req *http.Request, r protocol.AuthorizationRequest) httputil.Result
+}
+
+type PushedAuthorizationRequestHandlerLogger struct{ *log.Logger }
+
+func NewPushedAuthorizationRequestHandlerLogger(lf *log.Factory) PushedAuthorizationRequestHandlerLogger {
+	return PushedAuthorizationRequestHandlerLogger{lf.New("handler-par")}
+}
+
+type PushedAuthorizationRequestHandler struct {
+	Logger   PushedAuthorizationRequestHandlerLogger
+	Database *appdb.Handle
+	Handler  ProtocolPushedAuthorizationHandler
+}
+
+func (h *PushedAuthorizationRequestHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(rw, err.Error(), 400)
+		return
+	}
+
+	req := protocol.AuthorizationRequest{}
+	// Only the parameters in the request body are accepted.
+	// See RFC9126 section 2.1.
+	for name, values := range r.PostForm {
+		req[name] = values[0]
+	}
+
+	var result httputil.Result
+	err = h.Database.WithTx(func() error {
+		result = h.Handler.Handle(r, req)
+		if result.IsInternalError() {
+			return errAuthzInternalError
+		}
+		return nil
+	})
+
+	if err == nil || errors.Is(err, errAuthzInternalError) {
+		result.WriteResponse(rw, r)
+	} else {
+		h.Logger.WithError(err).Error("oauth pushed authorization request handler failed")
+		http.Error(rw, "Internal Server Error", 500)
+	}
+}
@@ -31,6 +31,20 @@ func handleAlternativeSteps(ctrl *Controller) {
 			choiceStep = webapp.SessionStepCreateAuthenticator
 			inputFn = nil
 
+		case webapp.SessionStepEnterPasskey:
+			// Issue passkey challenge.
+			choiceStep = webapp.SessionStepAuthenticate
+			inputFn = func() (interface{}, error) {
+				return &InputTriggerPasskey{}, nil
+			}
+
+		case webapp.SessionStepSetupPasskey:
+			// Issue passkey challenge.
+			choiceStep = webapp.SessionStepCreateAuthenticator
+			inputFn = func() (interface{}, error) {
+				return &InputSelectPasskey{}, nil
+			}
+
 		case webapp.SessionStepSetupTOTP:
 			// Generate TOTP secret.
 			choiceStep = webapp.SessionStepCreateAuthenticator
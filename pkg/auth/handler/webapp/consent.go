@@ -0,0 +1,101 @@
+package webapp
+
+import (
+	"net/http"
+
+	"github.com/authgear/authgear-server/pkg/auth/handler/webapp/viewmodels"
+	oauthhandler "github.com/authgear/authgear-server/pkg/lib/oauth/handler"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/template"
+)
+
+var TemplateWebConsentHTML = template.RegisterHTML(
+	"web/consent.html",
+	components...,
+)
+
+func ConfigureConsentRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("OPTIONS", "POST", "GET").
+		WithPathPattern("/consent")
+}
+
+type ConsentService interface {
+	GetConsent(code string) (*oauthhandler.Consent, error)
+	HandleConsent(code string, granted bool) (httputil.Result, error)
+}
+
+type ConsentViewModel struct {
+	ClientName    string
+	ClientURI     string
+	NewScopes     []string
+	GrantedScopes []string
+}
+
+type ConsentHandler struct {
+	ControllerFactory ControllerFactory
+	BaseViewModel     *viewmodels.BaseViewModeler
+	Renderer          Renderer
+	Consents          ConsentService
+}
+
+func (h *ConsentHandler) GetData(r *http.Request, rw http.ResponseWriter, consent *oauthhandler.Consent) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	baseViewModel := h.BaseViewModel.ViewModel(r, rw)
+	viewModel := ConsentViewModel{
+		ClientName:    consent.ClientName,
+		ClientURI:     consent.ClientURI,
+		NewScopes:     consent.NewScopes,
+		GrantedScopes: consent.GrantedScopes,
+	}
+	viewmodels.Embed(data, baseViewModel)
+	viewmodels.Embed(data, viewModel)
+	return data, nil
+}
+
+func (h *ConsentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctrl, err := h.ControllerFactory.New(r, w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ctrl.Serve()
+
+	code := r.Form.Get("code")
+
+	ctrl.Get(func() error {
+		consent, err := h.Consents.GetConsent(code)
+		if err != nil {
+			return err
+		}
+
+		data, err := h.GetData(r, w, consent)
+		if err != nil {
+			return err
+		}
+
+		h.Renderer.RenderHTML(w, r, TemplateWebConsentHTML, data)
+		return nil
+	})
+
+	ctrl.PostAction("allow", func() error {
+		result, err := h.Consents.HandleConsent(code, true)
+		if err != nil {
+			return err
+		}
+
+		result.WriteResponse(w, r)
+		return nil
+	})
+
+	ctrl.PostAction("deny", func() error {
+		result, err := h.Consents.HandleConsent(code, false)
+		if err != nil {
+			return err
+		}
+
+		result.WriteResponse(w, r)
+		return nil
+	})
+}
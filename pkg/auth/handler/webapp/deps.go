@@ -25,11 +25,16 @@ var DependencySet = wire.NewSet(
 	wire.Struct(new(PromoteHandler), "*"),
 	wire.Struct(new(SelectAccountHandler), "*"),
 	wire.Struct(new(SSOCallbackHandler), "*"),
+	wire.Struct(new(SAMLACSHandler), "*"),
+	NewSAMLMetadataHandlerLogger,
+	wire.Struct(new(SAMLMetadataHandler), "*"),
 	wire.Struct(new(EnterLoginIDHandler), "*"),
 	wire.Struct(new(EnterPasswordHandler), "*"),
 	wire.Struct(new(CreatePasswordHandler), "*"),
 	wire.Struct(new(SetupTOTPHandler), "*"),
 	wire.Struct(new(EnterTOTPHandler), "*"),
+	wire.Struct(new(SetupPasskeyHandler), "*"),
+	wire.Struct(new(EnterPasskeyHandler), "*"),
 	wire.Struct(new(SetupOOBOTPHandler), "*"),
 	wire.Struct(new(EnterOOBOTPHandler), "*"),
 	wire.Struct(new(EnterRecoveryCodeHandler), "*"),
@@ -38,6 +43,10 @@ var DependencySet = wire.NewSet(
 	wire.Struct(new(VerifyIdentitySuccessHandler), "*"),
 	wire.Struct(new(ForgotPasswordHandler), "*"),
 	wire.Struct(new(ForgotPasswordSuccessHandler), "*"),
+	wire.Struct(new(DeviceHandler), "*"),
+	wire.Struct(new(DeviceApproveHandler), "*"),
+	wire.Struct(new(DeviceSuccessHandler), "*"),
+	wire.Struct(new(ConsentHandler), "*"),
 	wire.Struct(new(ResetPasswordHandler), "*"),
 	wire.Struct(new(ResetPasswordSuccessHandler), "*"),
 	wire.Struct(new(SettingsHandler), "*"),
@@ -47,9 +56,11 @@ var DependencySet = wire.NewSet(
 	wire.Struct(new(SettingsBiometricHandler), "*"),
 	wire.Struct(new(SettingsMFAHandler), "*"),
 	wire.Struct(new(SettingsTOTPHandler), "*"),
+	wire.Struct(new(SettingsPasskeyHandler), "*"),
 	wire.Struct(new(SettingsOOBOTPHandler), "*"),
 	wire.Struct(new(SettingsRecoveryCodeHandler), "*"),
 	wire.Struct(new(SettingsSessionsHandler), "*"),
+	wire.Struct(new(SettingsConnectedAppsHandler), "*"),
 	wire.Struct(new(ForceChangePasswordHandler), "*"),
 	wire.Struct(new(SettingsChangePasswordHandler), "*"),
 	wire.Struct(new(ForceChangeSecondaryPasswordHandler), "*"),
@@ -57,6 +68,7 @@ var DependencySet = wire.NewSet(
 	wire.Struct(new(UserDisabledHandler), "*"),
 	wire.Struct(new(LogoutHandler), "*"),
 	wire.Struct(new(ReturnHandler), "*"),
+	wire.Struct(new(MagicLinkHandler), "*"),
 	wire.Struct(new(ErrorHandler), "*"),
 	wire.Struct(new(WebsocketHandler), "*"),
 	wire.Struct(new(WechatAuthHandler), "*"),
@@ -0,0 +1,98 @@
+package webapp
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/authgear/authgear-server/pkg/auth/handler/webapp/viewmodels"
+	oauthhandler "github.com/authgear/authgear-server/pkg/lib/oauth/handler"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/template"
+	"github.com/authgear/authgear-server/pkg/util/validation"
+)
+
+var TemplateWebDeviceHTML = template.RegisterHTML(
+	"web/device.html",
+	components...,
+)
+
+var DeviceSchema = validation.NewSimpleSchema(`
+	{
+		"type": "object",
+		"properties": {
+			"user_code": { "type": "string", "minLength": 1 }
+		},
+		"required": ["user_code"]
+	}
+`)
+
+func ConfigureDeviceRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("OPTIONS", "POST", "GET").
+		WithPathPattern("/device")
+}
+
+type DeviceVerificationService interface {
+	StartVerification(r *http.Request, userCode string, uiLocales string) (httputil.Result, error)
+	GetVerification(userCode string) (*oauthhandler.DeviceVerification, error)
+	FinishVerification(r *http.Request, userCode string) (*url.URL, *http.Cookie, error)
+}
+
+type DeviceViewModel struct {
+	UserCode string
+}
+
+type DeviceHandler struct {
+	ControllerFactory ControllerFactory
+	BaseViewModel     *viewmodels.BaseViewModeler
+	Renderer          Renderer
+	Devices           DeviceVerificationService
+}
+
+func (h *DeviceHandler) GetData(r *http.Request, rw http.ResponseWriter) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	baseViewModel := h.BaseViewModel.ViewModel(r, rw)
+	viewModel := DeviceViewModel{
+		UserCode: r.Form.Get("user_code"),
+	}
+	viewmodels.Embed(data, baseViewModel)
+	viewmodels.Embed(data, viewModel)
+	return data, nil
+}
+
+func (h *DeviceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctrl, err := h.ControllerFactory.New(r, w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ctrl.Serve()
+
+	ctrl.Get(func() error {
+		data, err := h.GetData(r, w)
+		if err != nil {
+			return err
+		}
+
+		h.Renderer.RenderHTML(w, r, TemplateWebDeviceHTML, data)
+		return nil
+	})
+
+	ctrl.PostAction("", func() error {
+		err := DeviceSchema.Validator().ValidateValue(FormToJSON(r.Form))
+		if err != nil {
+			return err
+		}
+
+		userCode := r.Form.Get("user_code")
+		uiLocales := r.URL.Query().Get("ui_locales")
+		result, err := h.Devices.StartVerification(r, userCode, uiLocales)
+		if err != nil {
+			return err
+		}
+
+		result.WriteResponse(w, r)
+		return nil
+	})
+}
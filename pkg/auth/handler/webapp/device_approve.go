@@ -0,0 +1,116 @@
+package webapp
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/auth/handler/webapp/viewmodels"
+	"github.com/authgear/authgear-server/pkg/auth/webapp"
+	oauthhandler "github.com/authgear/authgear-server/pkg/lib/oauth/handler"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/template"
+	"github.com/authgear/authgear-server/pkg/util/urlutil"
+)
+
+var TemplateWebDeviceApproveHTML = template.RegisterHTML(
+	"web/device_approve.html",
+	components...,
+)
+
+func ConfigureDeviceApproveRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("OPTIONS", "POST", "GET").
+		WithPathPattern("/device/approve")
+}
+
+type DeviceApproveViewModel struct {
+	ClientName string
+	UserCode   string
+}
+
+type DeviceApproveHandler struct {
+	ControllerFactory ControllerFactory
+	BaseViewModel     *viewmodels.BaseViewModeler
+	Renderer          Renderer
+	Devices           DeviceVerificationService
+}
+
+func (h *DeviceApproveHandler) GetData(r *http.Request, rw http.ResponseWriter, verification *oauthhandler.DeviceVerification) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	baseViewModel := h.BaseViewModel.ViewModel(r, rw)
+	viewModel := DeviceApproveViewModel{
+		ClientName: verification.ClientName,
+		UserCode:   verification.UserCode,
+	}
+	viewmodels.Embed(data, baseViewModel)
+	viewmodels.Embed(data, viewModel)
+	return data, nil
+}
+
+func (h *DeviceApproveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctrl, err := h.ControllerFactory.New(r, w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ctrl.Serve()
+
+	userCode := r.Form.Get("user_code")
+
+	// Show the error in the device page instead,
+	// so that the end-user can enter the code again.
+	redirectToDevicePage := func(apiError *apierrors.APIError) error {
+		errorCookie, err := ctrl.ErrorCookie.SetError(r, apiError)
+		if err != nil {
+			return err
+		}
+		result := webapp.Result{
+			RedirectURI: urlutil.WithQueryParamsAdded(
+				&url.URL{Path: "/device"},
+				map[string]string{"user_code": userCode},
+			).String(),
+			NavigationAction: "replace",
+			Cookies:          []*http.Cookie{errorCookie},
+		}
+		result.WriteResponse(w, r)
+		return nil
+	}
+
+	ctrl.Get(func() error {
+		verification, err := h.Devices.GetVerification(userCode)
+		if apierrors.IsAPIError(err) {
+			return redirectToDevicePage(apierrors.AsAPIError(err))
+		} else if err != nil {
+			return err
+		}
+
+		data, err := h.GetData(r, w, verification)
+		if err != nil {
+			return err
+		}
+
+		h.Renderer.RenderHTML(w, r, TemplateWebDeviceApproveHTML, data)
+		return nil
+	})
+
+	// The grant is approved only when the end-user confirms,
+	// so that a link to this page cannot approve the grant on its own.
+	ctrl.PostAction("approve", func() error {
+		redirectURI, cookie, err := h.Devices.FinishVerification(r, userCode)
+		if apierrors.IsAPIError(err) {
+			return redirectToDevicePage(apierrors.AsAPIError(err))
+		} else if err != nil {
+			return err
+		}
+
+		// The end-user is redirected to the consent page if the client is not trusted yet.
+		result := webapp.Result{
+			RedirectURI:      redirectURI.String(),
+			NavigationAction: "replace",
+			Cookies:          []*http.Cookie{cookie},
+		}
+		result.WriteResponse(w, r)
+		return nil
+	})
+}
@@ -41,6 +41,7 @@ func ConfigureEnterOOBOTPRoute(route httproute.Route) httproute.Route {
 }
 
 type EnterOOBOTPViewModel struct {
+	OOBOTPCode             string
 	OOBOTPTarget           string
 	OOBOTPCodeSendCooldown int
 	OOBOTPCodeLength       int
@@ -67,6 +68,11 @@ func (h *EnterOOBOTPHandler) GetData(r *http.Request, rw http.ResponseWriter, se
 
 	baseViewModel := h.BaseViewModel.ViewModel(r, rw)
 	viewModel := EnterOOBOTPViewModel{}
+	// The code is filled by the magic link opened in another user agent.
+	if c, ok := session.CurrentStep().FormData["x_oob_otp_code"].(string); ok {
+		viewModel.OOBOTPCode = c
+	}
+
 	var n EnterOOBOTPNode
 	if graph.FindLastNode(&n) {
 		viewModel.OOBOTPCodeLength = n.GetOOBOTPCodeLength()
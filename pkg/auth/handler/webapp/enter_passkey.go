@@ -0,0 +1,132 @@
+package webapp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/authgear/authgear-server/pkg/auth/handler/webapp/viewmodels"
+	"github.com/authgear/authgear-server/pkg/auth/webapp"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/template"
+	"github.com/authgear/authgear-server/pkg/util/validation"
+	"github.com/authgear/authgear-server/pkg/util/webauthn"
+)
+
+var TemplateWebEnterPasskeyHTML = template.RegisterHTML(
+	"web/enter_passkey.html",
+	components...,
+)
+
+var EnterPasskeySchema = validation.NewSimpleSchema(`
+	{
+		"type": "object",
+		"properties": {
+			"x_assertion_response": { "type": "string", "minLength": 1 }
+		},
+		"required": ["x_assertion_response"]
+	}
+`)
+
+func ConfigureEnterPasskeyRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("OPTIONS", "POST", "GET").
+		WithPathPattern("/enter_passkey")
+}
+
+type EnterPasskeyViewModel struct {
+	RequestOptionsJSON string
+}
+
+type EnterPasskeyNode interface {
+	GetPasskeyRequestOptions() *webauthn.RequestOptions
+}
+
+type EnterPasskeyHandler struct {
+	ControllerFactory ControllerFactory
+	BaseViewModel     *viewmodels.BaseViewModeler
+	Renderer          Renderer
+}
+
+func (h *EnterPasskeyHandler) GetData(r *http.Request, rw http.ResponseWriter, session *webapp.Session, graph *interaction.Graph) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+
+	baseViewModel := h.BaseViewModel.ViewModel(r, rw)
+
+	var node EnterPasskeyNode
+	if !graph.FindLastNode(&node) {
+		panic("enter_passkey: expected graph has node implementing EnterPasskeyNode")
+	}
+
+	optionsJSON, err := json.Marshal(node.GetPasskeyRequestOptions())
+	if err != nil {
+		return nil, err
+	}
+	viewModel := EnterPasskeyViewModel{
+		RequestOptionsJSON: string(optionsJSON),
+	}
+
+	alternatives := viewmodels.AlternativeStepsViewModel{}
+	err = alternatives.AddAuthenticationAlternatives(graph, webapp.SessionStepEnterPasskey)
+	if err != nil {
+		return nil, err
+	}
+
+	viewmodels.Embed(data, baseViewModel)
+	viewmodels.Embed(data, viewModel)
+	viewmodels.Embed(data, alternatives)
+
+	return data, nil
+}
+
+func (h *EnterPasskeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctrl, err := h.ControllerFactory.New(r, w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ctrl.Serve()
+
+	ctrl.Get(func() error {
+		session, err := ctrl.InteractionSession()
+		if err != nil {
+			return err
+		}
+
+		graph, err := ctrl.InteractionGet()
+		if err != nil {
+			return err
+		}
+
+		data, err := h.GetData(r, w, session, graph)
+		if err != nil {
+			return err
+		}
+
+		h.Renderer.RenderHTML(w, r, TemplateWebEnterPasskeyHTML, data)
+		return nil
+	})
+
+	ctrl.PostAction("", func() error {
+		result, err := ctrl.InteractionPost(func() (input interface{}, err error) {
+			err = EnterPasskeySchema.Validator().ValidateValue(FormToJSON(r.Form))
+			if err != nil {
+				return
+			}
+
+			input = &InputAuthPasskey{
+				AssertionResponse: r.Form.Get("x_assertion_response"),
+				DeviceToken:       r.Form.Get("x_device_token") == "true",
+			}
+			return
+		})
+		if err != nil {
+			return err
+		}
+
+		result.WriteResponse(w, r)
+		return nil
+	})
+
+	handleAlternativeSteps(ctrl)
+}
@@ -92,6 +92,18 @@ var _ nodes.InputCreateAuthenticatorTOTPSetup = &InputSelectTOTP{}
 
 func (i *InputSelectTOTP) SetupTOTP() {}
 
+type InputTriggerPasskey struct{}
+
+var _ nodes.InputAuthenticationPasskeyTrigger = &InputTriggerPasskey{}
+
+func (i *InputTriggerPasskey) TriggerPasskey() {}
+
+type InputSelectPasskey struct{}
+
+var _ nodes.InputCreateAuthenticatorPasskeySetup = &InputSelectPasskey{}
+
+func (i *InputSelectPasskey) SetupPasskey() {}
+
 type InputSetupPassword struct {
 	Stage    string
 	Password string
@@ -131,6 +143,17 @@ var _ nodes.InputCreateDeviceToken = &InputAuthTOTP{}
 func (i *InputAuthTOTP) GetTOTP() string         { return i.Code }
 func (i *InputAuthTOTP) CreateDeviceToken() bool { return i.DeviceToken }
 
+type InputAuthPasskey struct {
+	AssertionResponse string
+	DeviceToken       bool
+}
+
+var _ nodes.InputAuthenticationPasskey = &InputAuthPasskey{}
+var _ nodes.InputCreateDeviceToken = &InputAuthPasskey{}
+
+func (i *InputAuthPasskey) GetPasskeyAssertionResponse() string { return i.AssertionResponse }
+func (i *InputAuthPasskey) CreateDeviceToken() bool             { return i.DeviceToken }
+
 type InputAuthPassword struct {
 	Stage       string
 	Password    string
@@ -190,6 +213,16 @@ var _ nodes.InputCreateAuthenticatorTOTP = &InputSetupTOTP{}
 func (i *InputSetupTOTP) GetTOTP() string            { return i.Code }
 func (i *InputSetupTOTP) GetTOTPDisplayName() string { return i.DisplayName }
 
+type InputSetupPasskey struct {
+	AttestationResponse string
+	DisplayName         string
+}
+
+var _ nodes.InputCreateAuthenticatorPasskey = &InputSetupPasskey{}
+
+func (i *InputSetupPasskey) GetPasskeyAttestationResponse() string { return i.AttestationResponse }
+func (i *InputSetupPasskey) GetPasskeyDisplayName() string         { return i.DisplayName }
+
 type InputOAuthCallback struct {
 	ProviderAlias string
 
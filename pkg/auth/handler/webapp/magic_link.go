@@ -0,0 +1,123 @@
+package webapp
+
+import (
+	"net/http"
+
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/auth/handler/webapp/viewmodels"
+	"github.com/authgear/authgear-server/pkg/auth/webapp"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
+	"github.com/authgear/authgear-server/pkg/util/geoip"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/template"
+)
+
+var TemplateWebMagicLinkHTML = template.RegisterHTML(
+	"web/magic_link.html",
+	components...,
+)
+
+func ConfigureMagicLinkRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("OPTIONS", "POST", "GET").
+		WithPathPattern("/magic_link")
+}
+
+type MagicLinkOOBCodeProvider interface {
+	GetMagicLink(authenticatorID string, token string) (*oob.Code, error)
+	VerifyMagicLink(authenticatorID string, token string) (*oob.Code, error)
+}
+
+type MagicLinkHandler struct {
+	ControllerFactory ControllerFactory
+	BaseViewModel     *viewmodels.BaseViewModeler
+	Renderer          Renderer
+	OOBCodes          MagicLinkOOBCodeProvider
+}
+
+func (h *MagicLinkHandler) GetData(r *http.Request, rw http.ResponseWriter) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	baseViewModel := h.BaseViewModel.ViewModel(r, rw)
+	viewmodels.Embed(data, baseViewModel)
+
+	code, err := h.OOBCodes.GetMagicLink(r.Form.Get("id"), r.Form.Get("token"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Show where the link was requested from,
+	// so that the user does not confirm a request made by someone else.
+	data["RequestedAt"] = code.CreatedAt
+	data["RequestIP"] = code.RequestIP
+	ua := model.ParseUserAgent(code.RequestUserAgent)
+	device := ua.Format()
+	if device == "" {
+		device = ua.Raw
+	}
+	data["RequestDevice"] = device
+	if ipInfo, ok := geoip.DefaultDatabase.IPString(code.RequestIP); ok {
+		data["RequestIPCountryCode"] = ipInfo.CountryCode
+		data["RequestIPEnglishCountryName"] = ipInfo.EnglishCountryName
+	}
+
+	return data, nil
+}
+
+func (h *MagicLinkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctrl, err := h.ControllerFactory.New(r, w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ctrl.Serve()
+
+	// The link is opened by a GET request, which may be issued by
+	// link scanners of mail clients.
+	// So the link is only consumed when the user confirms.
+	ctrl.Get(func() error {
+		data, err := h.GetData(r, w)
+		if err != nil {
+			return err
+		}
+
+		h.Renderer.RenderHTML(w, r, TemplateWebMagicLinkHTML, data)
+		return nil
+	})
+
+	ctrl.PostAction("confirm", func() error {
+		authenticatorID := r.Form.Get("id")
+		token := r.Form.Get("token")
+
+		code, err := h.OOBCodes.VerifyMagicLink(authenticatorID, token)
+		if err != nil {
+			return err
+		}
+
+		// The link may be opened in another user agent.
+		// Deliver the code to the web session of the original interaction,
+		// so that the original user agent can submit the code.
+		session, err := ctrl.GetSession(code.WebSessionID)
+		if err != nil {
+			return err
+		}
+
+		step := session.CurrentStep()
+		if step.Kind != webapp.SessionStepEnterOOBOTPAuthnEmail {
+			return webapp.ErrSessionStepMismatch
+		}
+		step.FormData["x_oob_otp_code"] = code.Code
+		session.Steps[len(session.Steps)-1] = step
+
+		// UpdateSession notifies the original user agent through websocket.
+		err = ctrl.UpdateSession(session)
+		if err != nil {
+			return err
+		}
+
+		result := &webapp.Result{
+			RedirectURI: "/return",
+		}
+		result.WriteResponse(w, r)
+		return nil
+	})
+}
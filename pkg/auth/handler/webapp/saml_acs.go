@@ -0,0 +1,47 @@
+package webapp
+
+import (
+	"net/http"
+
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+)
+
+func ConfigureSAMLACSRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("OPTIONS", "POST").
+		WithPathPattern("/sso/saml/acs/:alias")
+}
+
+// SAMLACSHandler is the assertion consumer service of SAML providers.
+// The IdP posts the SAMLResponse to this endpoint with the HTTP-POST binding.
+// The SAMLResponse is passed to the interaction as the authorization code.
+type SAMLACSHandler struct {
+	ControllerFactory ControllerFactory
+}
+
+func (h *SAMLACSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctrl, err := h.ControllerFactory.New(r, w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ctrl.Serve()
+
+	data := InputOAuthCallback{
+		ProviderAlias: httproute.GetParam(r, "alias"),
+
+		Code: r.Form.Get("SAMLResponse"),
+	}
+
+	ctrl.PostAction("", func() error {
+		result, err := ctrl.InteractionPost(func() (input interface{}, err error) {
+			input = &data
+			return
+		})
+		if err != nil {
+			return err
+		}
+		result.WriteResponse(w, r)
+		return nil
+	})
+}
@@ -0,0 +1,50 @@
+package webapp
+
+import (
+	"net/http"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/sso"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+func ConfigureSAMLMetadataRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("OPTIONS", "GET").
+		WithPathPattern("/sso/saml/metadata/:alias")
+}
+
+type SAMLMetadataProviderFactory interface {
+	NewOAuthProvider(alias string) sso.OAuthProvider
+}
+
+type SAMLMetadataHandlerLogger struct{ *log.Logger }
+
+func NewSAMLMetadataHandlerLogger(lf *log.Factory) SAMLMetadataHandlerLogger {
+	return SAMLMetadataHandlerLogger{lf.New("handler-saml-metadata")}
+}
+
+// SAMLMetadataHandler serves the SP metadata of a SAML provider,
+// which is to be registered in the IdP.
+type SAMLMetadataHandler struct {
+	Logger          SAMLMetadataHandlerLogger
+	ProviderFactory SAMLMetadataProviderFactory
+}
+
+func (h *SAMLMetadataHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	provider, ok := h.ProviderFactory.NewOAuthProvider(httproute.GetParam(r, "alias")).(sso.SAMLProvider)
+	if !ok {
+		http.NotFound(rw, r)
+		return
+	}
+
+	metadata, err := provider.SPMetadata()
+	if err != nil {
+		h.Logger.WithError(err).Error("failed to generate SAML SP metadata")
+		http.Error(rw, "internal server error", 500)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/samlmetadata+xml")
+	_, _ = rw.Write(metadata)
+}
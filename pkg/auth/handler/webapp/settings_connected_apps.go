@@ -0,0 +1,123 @@
+package webapp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/auth/handler/webapp/viewmodels"
+	"github.com/authgear/authgear-server/pkg/auth/webapp"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/template"
+)
+
+var TemplateWebSettingsConnectedAppsHTML = template.RegisterHTML(
+	"web/settings_connected_apps.html",
+	components...,
+)
+
+func ConfigureSettingsConnectedAppsRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("OPTIONS", "POST", "GET").
+		WithPathPattern("/settings/connected_apps")
+}
+
+type SettingsAuthorizationService interface {
+	List(userID string) ([]*model.Authorization, error)
+	Get(id string) (*model.Authorization, error)
+	Delete(id string, isAdminAPI bool) error
+}
+
+type ConnectedAppViewModel struct {
+	AuthorizationID string
+	ClientName      string
+	Scopes          []string
+	LastUsedAt      time.Time
+}
+
+type SettingsConnectedAppsViewModel struct {
+	ConnectedApps []ConnectedAppViewModel
+}
+
+type SettingsConnectedAppsHandler struct {
+	ControllerFactory ControllerFactory
+	BaseViewModel     *viewmodels.BaseViewModeler
+	Renderer          Renderer
+	OAuthConfig       *config.OAuthConfig
+	Authorizations    SettingsAuthorizationService
+}
+
+func (h *SettingsConnectedAppsHandler) GetData(r *http.Request, rw http.ResponseWriter, userID string) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	baseViewModel := h.BaseViewModel.ViewModel(r, rw)
+	viewmodels.Embed(data, baseViewModel)
+
+	authzs, err := h.Authorizations.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	viewModel := SettingsConnectedAppsViewModel{}
+	for _, authz := range authzs {
+		clientName := authz.ClientID
+		if client, ok := h.OAuthConfig.GetClient(authz.ClientID); ok && client.Name != "" {
+			clientName = client.Name
+		}
+		viewModel.ConnectedApps = append(viewModel.ConnectedApps, ConnectedAppViewModel{
+			AuthorizationID: authz.ID,
+			ClientName:      clientName,
+			Scopes:          authz.Scopes,
+			LastUsedAt:      authz.LastUsedAt,
+		})
+	}
+	viewmodels.Embed(data, viewModel)
+
+	return data, nil
+}
+
+func (h *SettingsConnectedAppsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctrl, err := h.ControllerFactory.New(r, w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ctrl.Serve()
+
+	userID := session.GetUserID(r.Context())
+	redirectURI := httputil.HostRelative(r.URL).String()
+
+	ctrl.Get(func() error {
+		data, err := h.GetData(r, w, *userID)
+		if err != nil {
+			return err
+		}
+
+		h.Renderer.RenderHTML(w, r, TemplateWebSettingsConnectedAppsHTML, data)
+		return nil
+	})
+
+	ctrl.PostAction("revoke", func() error {
+		authzID := r.Form.Get("x_authorization_id")
+
+		authz, err := h.Authorizations.Get(authzID)
+		if err != nil {
+			return err
+		}
+		if authz.UserID != *userID {
+			return apierrors.NewForbidden("cannot revoke authorization of other users")
+		}
+
+		err = h.Authorizations.Delete(authz.ID, false)
+		if err != nil {
+			return err
+		}
+
+		result := webapp.Result{RedirectURI: redirectURI}
+		result.WriteResponse(w, r)
+		return nil
+	})
+}
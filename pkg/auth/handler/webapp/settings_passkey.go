@@ -0,0 +1,136 @@
+package webapp
+
+import (
+	"net/http"
+
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/auth/handler/webapp/viewmodels"
+	"github.com/authgear/authgear-server/pkg/auth/webapp"
+	"github.com/authgear/authgear-server/pkg/lib/authn"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/interaction/intents"
+	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/template"
+)
+
+var TemplateWebSettingsPasskeyHTML = template.RegisterHTML(
+	"web/settings_passkey.html",
+	components...,
+)
+
+func ConfigureSettingsPasskeyRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("OPTIONS", "POST", "GET").
+		WithPathPattern("/settings/passkey")
+}
+
+type SettingsPasskeyViewModel struct {
+	Authenticators []*authenticator.Info
+}
+
+type SettingsPasskeyHandler struct {
+	ControllerFactory ControllerFactory
+	BaseViewModel     *viewmodels.BaseViewModeler
+	Renderer          Renderer
+	Authenticators    SettingsAuthenticatorService
+	Authentication    *config.AuthenticationConfig
+}
+
+func (h *SettingsPasskeyHandler) GetData(r *http.Request, rw http.ResponseWriter) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	baseViewModel := h.BaseViewModel.ViewModel(r, rw)
+	userID := session.GetUserID(r.Context())
+	viewModel := SettingsPasskeyViewModel{}
+	authenticators, err := h.Authenticators.List(*userID,
+		authenticator.KeepType(model.AuthenticatorTypePasskey),
+	)
+	if err != nil {
+		return nil, err
+	}
+	viewModel.Authenticators = authenticators
+
+	viewmodels.Embed(data, baseViewModel)
+	viewmodels.Embed(data, viewModel)
+
+	return data, nil
+}
+
+// addStage returns the stage of the passkey added by the user.
+// A passkey is preferably added as primary authenticator for passwordless login.
+func (h *SettingsPasskeyHandler) addStage() authn.AuthenticationStage {
+	for _, t := range *h.Authentication.PrimaryAuthenticators {
+		if t == model.AuthenticatorTypePasskey {
+			return authn.AuthenticationStagePrimary
+		}
+	}
+	return authn.AuthenticationStageSecondary
+}
+
+func (h *SettingsPasskeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctrl, err := h.ControllerFactory.New(r, w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ctrl.Serve()
+
+	redirectURI := httputil.HostRelative(r.URL).String()
+	authenticatorID := r.Form.Get("x_authenticator_id")
+	userID := ctrl.RequireUserID()
+
+	ctrl.Get(func() error {
+		data, err := h.GetData(r, w)
+		if err != nil {
+			return err
+		}
+
+		h.Renderer.RenderHTML(w, r, TemplateWebSettingsPasskeyHTML, data)
+		return nil
+	})
+
+	ctrl.PostAction("remove", func() error {
+		opts := webapp.SessionOptions{
+			RedirectURI: redirectURI,
+		}
+		intent := intents.NewIntentRemoveAuthenticator(userID)
+
+		result, err := ctrl.EntryPointPost(opts, intent, func() (input interface{}, err error) {
+			input = &InputRemoveAuthenticator{
+				Type: model.AuthenticatorTypePasskey,
+				ID:   authenticatorID,
+			}
+			return
+		})
+		if err != nil {
+			return err
+		}
+
+		result.WriteResponse(w, r)
+		return nil
+	})
+
+	ctrl.PostAction("add", func() error {
+		opts := webapp.SessionOptions{
+			RedirectURI: redirectURI,
+		}
+		intent := intents.NewIntentAddAuthenticator(
+			userID,
+			h.addStage(),
+			model.AuthenticatorTypePasskey,
+		)
+
+		result, err := ctrl.EntryPointPost(opts, intent, func() (input interface{}, err error) {
+			input = &InputCreateAuthenticator{}
+			return
+		})
+		if err != nil {
+			return err
+		}
+
+		result.WriteResponse(w, r)
+		return nil
+	})
+}
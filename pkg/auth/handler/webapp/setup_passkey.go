@@ -0,0 +1,145 @@
+package webapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/auth/handler/webapp/viewmodels"
+	"github.com/authgear/authgear-server/pkg/auth/webapp"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/httproute"
+	"github.com/authgear/authgear-server/pkg/util/template"
+	"github.com/authgear/authgear-server/pkg/util/validation"
+	"github.com/authgear/authgear-server/pkg/util/webauthn"
+)
+
+var TemplateWebSetupPasskeyHTML = template.RegisterHTML(
+	"web/setup_passkey.html",
+	components...,
+)
+
+var SetupPasskeySchema = validation.NewSimpleSchema(`
+	{
+		"type": "object",
+		"properties": {
+			"x_attestation_response": { "type": "string", "minLength": 1 }
+		},
+		"required": ["x_attestation_response"]
+	}
+`)
+
+func ConfigureSetupPasskeyRoute(route httproute.Route) httproute.Route {
+	return route.
+		WithMethods("OPTIONS", "POST", "GET").
+		WithPathPattern("/setup_passkey")
+}
+
+type SetupPasskeyNode interface {
+	GetPasskeyCreationOptions() *webauthn.CreationOptions
+}
+
+type SetupPasskeyViewModel struct {
+	CreationOptionsJSON string
+	AlternativeSteps    []viewmodels.AlternativeStep
+}
+
+type SetupPasskeyHandler struct {
+	ControllerFactory ControllerFactory
+	BaseViewModel     *viewmodels.BaseViewModeler
+	Renderer          Renderer
+	Clock             clock.Clock
+}
+
+func (h *SetupPasskeyHandler) MakeViewModel(graph *interaction.Graph) (*SetupPasskeyViewModel, error) {
+	var node SetupPasskeyNode
+	if !graph.FindLastNode(&node) {
+		panic(fmt.Errorf("setup_passkey: expected graph has node implementing SetupPasskeyNode"))
+	}
+
+	optionsJSON, err := json.Marshal(node.GetPasskeyCreationOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	alternatives := &viewmodels.AlternativeStepsViewModel{}
+	err = alternatives.AddCreateAuthenticatorAlternatives(graph, webapp.SessionStepSetupPasskey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetupPasskeyViewModel{
+		CreationOptionsJSON: string(optionsJSON),
+		AlternativeSteps:    alternatives.AlternativeSteps,
+	}, nil
+}
+
+func (h *SetupPasskeyHandler) GetData(r *http.Request, rw http.ResponseWriter, session *webapp.Session, graph *interaction.Graph) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	baseViewModel := h.BaseViewModel.ViewModel(r, rw)
+	viewModel, err := h.MakeViewModel(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	viewmodels.Embed(data, baseViewModel)
+	viewmodels.Embed(data, *viewModel)
+	return data, nil
+}
+
+func (h *SetupPasskeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctrl, err := h.ControllerFactory.New(r, w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ctrl.Serve()
+
+	ctrl.Get(func() error {
+		session, err := ctrl.InteractionSession()
+		if err != nil {
+			return err
+		}
+
+		graph, err := ctrl.InteractionGet()
+		if err != nil {
+			return err
+		}
+
+		data, err := h.GetData(r, w, session, graph)
+		if err != nil {
+			return err
+		}
+
+		h.Renderer.RenderHTML(w, r, TemplateWebSetupPasskeyHTML, data)
+		return nil
+	})
+
+	ctrl.PostAction("", func() error {
+		result, err := ctrl.InteractionPost(func() (input interface{}, err error) {
+			err = SetupPasskeySchema.Validator().ValidateValue(FormToJSON(r.Form))
+			if err != nil {
+				return
+			}
+
+			now := h.Clock.NowUTC()
+			displayName := fmt.Sprintf("Passkey @ %s", now.Format(time.RFC3339))
+
+			input = &InputSetupPasskey{
+				AttestationResponse: r.Form.Get("x_attestation_response"),
+				DisplayName:         displayName,
+			}
+			return
+		})
+		if err != nil {
+			return err
+		}
+
+		result.WriteResponse(w, r)
+		return nil
+	})
+
+	handleAlternativeSteps(ctrl)
+}
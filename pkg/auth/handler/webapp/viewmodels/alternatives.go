@@ -74,6 +74,12 @@ func (m *AlternativeStepsViewModel) AddAuthenticationAlternatives(graph *interac
 					Step: webapp.SessionStepEnterTOTP,
 				})
 			}
+		case *nodes.EdgeAuthenticationPasskeyTrigger:
+			if currentStepKind != webapp.SessionStepEnterPasskey {
+				m.AlternativeSteps = append(m.AlternativeSteps, AlternativeStep{
+					Step: webapp.SessionStepEnterPasskey,
+				})
+			}
 		case *nodes.EdgeAuthenticationOOBTrigger:
 			show := false
 			oobAuthenticatorType := edge.OOBAuthenticatorType
@@ -180,6 +186,12 @@ func (m *AlternativeStepsViewModel) AddCreateAuthenticatorAlternatives(graph *in
 					Step: webapp.SessionStepSetupTOTP,
 				})
 			}
+		case *nodes.EdgeCreateAuthenticatorPasskeySetup:
+			if currentStepKind != webapp.SessionStepSetupPasskey {
+				m.AlternativeSteps = append(m.AlternativeSteps, AlternativeStep{
+					Step: webapp.SessionStepSetupPasskey,
+				})
+			}
 		default:
 			panic(fmt.Errorf("create_authenticator_begin: unexpected edge: %T", edge))
 		}
@@ -17,6 +17,7 @@ type SettingsViewModel struct {
 	HasDeviceTokens                 bool
 	ListRecoveryCodesAllowed        bool
 	ShowBiometric                   bool
+	ShowPasskey                     bool
 }
 
 type SettingsIdentityService interface {
@@ -89,6 +90,25 @@ func (m *SettingsViewModeler) ViewModel(userID string) (*SettingsViewModel, erro
 		}
 	}
 
+	showPasskey := false
+	for _, a := range authenticators {
+		if a.Type == model.AuthenticatorTypePasskey {
+			showPasskey = true
+		}
+	}
+	for _, typ := range *m.Authentication.PrimaryAuthenticators {
+		if typ == model.AuthenticatorTypePasskey {
+			showPasskey = true
+		}
+	}
+	if someIdentityCanHaveMFA && !m.Authentication.SecondaryAuthenticationMode.IsDisabled() {
+		for _, typ := range *m.Authentication.SecondaryAuthenticators {
+			if typ == model.AuthenticatorTypePasskey {
+				showPasskey = true
+			}
+		}
+	}
+
 	viewModel := &SettingsViewModel{
 		Authenticators:                  authenticators,
 		SecondaryAuthenticationDisabled: m.Authentication.SecondaryAuthenticationMode.IsDisabled(),
@@ -99,6 +119,7 @@ func (m *SettingsViewModeler) ViewModel(userID string) (*SettingsViewModel, erro
 		HasDeviceTokens:                 hasDeviceTokens,
 		ListRecoveryCodesAllowed:        m.Authentication.RecoveryCode.ListEnabled,
 		ShowBiometric:                   showBiometric,
+		ShowPasskey:                     showPasskey,
 	}
 	return viewModel, nil
 }
@@ -132,6 +132,8 @@ func NewRouter(p *deps.RootProvider, configSource *configsource.ConfigSource) *h
 	router.Add(webapphandler.ConfigureEnterPasswordRoute(webappPageRoute), p.Handler(newWebAppEnterPasswordHandler))
 	router.Add(webapphandler.ConfigureSetupTOTPRoute(webappPageRoute), p.Handler(newWebAppSetupTOTPHandler))
 	router.Add(webapphandler.ConfigureEnterTOTPRoute(webappPageRoute), p.Handler(newWebAppEnterTOTPHandler))
+	router.Add(webapphandler.ConfigureSetupPasskeyRoute(webappPageRoute), p.Handler(newWebAppSetupPasskeyHandler))
+	router.Add(webapphandler.ConfigureEnterPasskeyRoute(webappPageRoute), p.Handler(newWebAppEnterPasskeyHandler))
 	router.Add(webapphandler.ConfigureSetupOOBOTPRoute(webappPageRoute), p.Handler(newWebAppSetupOOBOTPHandler))
 	router.Add(webapphandler.ConfigureEnterOOBOTPRoute(webappPageRoute), p.Handler(newWebAppEnterOOBOTPHandler))
 	router.Add(webapphandler.ConfigureEnterRecoveryCodeRoute(webappPageRoute), p.Handler(newWebAppEnterRecoveryCodeHandler))
@@ -145,7 +147,12 @@ func NewRouter(p *deps.RootProvider, configSource *configsource.ConfigSource) *h
 	router.Add(webapphandler.ConfigureResetPasswordSuccessRoute(webappPageRoute), p.Handler(newWebAppResetPasswordSuccessHandler))
 	router.Add(webapphandler.ConfigureUserDisabledRoute(webappPageRoute), p.Handler(newWebAppUserDisabledHandler))
 	router.Add(webapphandler.ConfigureReturnRoute(webappPageRoute), p.Handler(newWebAppReturnHandler))
+	router.Add(webapphandler.ConfigureMagicLinkRoute(webappPageRoute), p.Handler(newWebAppMagicLinkHandler))
 	router.Add(webapphandler.ConfigureErrorRoute(webappPageRoute), p.Handler(newWebAppErrorHandler))
+	router.Add(webapphandler.ConfigureDeviceRoute(webappPageRoute), p.Handler(newWebAppDeviceHandler))
+	router.Add(webapphandler.ConfigureDeviceApproveRoute(webappPageRoute), p.Handler(newWebAppDeviceApproveHandler))
+	router.Add(webapphandler.ConfigureDeviceSuccessRoute(webappPageRoute), p.Handler(newWebAppDeviceSuccessHandler))
+	router.Add(webapphandler.ConfigureConsentRoute(webappPageRoute), p.Handler(newWebAppConsentHandler))
 	router.Add(webapphandler.ConfigureForceChangePasswordRoute(webappPageRoute), p.Handler(newWebAppForceChangePasswordHandler))
 	router.Add(webapphandler.ConfigureForceChangeSecondaryPasswordRoute(webappPageRoute), p.Handler(newWebAppForceChangeSecondaryPasswordHandler))
 
@@ -159,13 +166,17 @@ func NewRouter(p *deps.RootProvider, configSource *configsource.ConfigSource) *h
 	router.Add(webapphandler.ConfigureSettingsBiometricRoute(webappSettingsSubRoutesRoute), p.Handler(newWebAppSettingsBiometricHandler))
 	router.Add(webapphandler.ConfigureSettingsMFARoute(webappSettingsSubRoutesRoute), p.Handler(newWebAppSettingsMFAHandler))
 	router.Add(webapphandler.ConfigureSettingsTOTPRoute(webappSettingsSubRoutesRoute), p.Handler(newWebAppSettingsTOTPHandler))
+	router.Add(webapphandler.ConfigureSettingsPasskeyRoute(webappSettingsSubRoutesRoute), p.Handler(newWebAppSettingsPasskeyHandler))
 	router.Add(webapphandler.ConfigureSettingsOOBOTPRoute(webappSettingsSubRoutesRoute), p.Handler(newWebAppSettingsOOBOTPHandler))
 	router.Add(webapphandler.ConfigureSettingsRecoveryCodeRoute(webappSettingsSubRoutesRoute), p.Handler(newWebAppSettingsRecoveryCodeHandler))
 	router.Add(webapphandler.ConfigureSettingsSessionsRoute(webappSettingsSubRoutesRoute), p.Handler(newWebAppSettingsSessionsHandler))
+	router.Add(webapphandler.ConfigureSettingsConnectedAppsRoute(webappSettingsSubRoutesRoute), p.Handler(newWebAppSettingsConnectedAppsHandler))
 	router.Add(webapphandler.ConfigureSettingsChangePasswordRoute(webappSettingsSubRoutesRoute), p.Handler(newWebAppSettingsChangePasswordHandler))
 	router.Add(webapphandler.ConfigureSettingsChangeSecondaryPasswordRoute(webappSettingsSubRoutesRoute), p.Handler(newWebAppSettingsChangeSecondaryPasswordHandler))
 
 	router.Add(webapphandler.ConfigureSSOCallbackRoute(webappSSOCallbackRoute), p.Handler(newWebAppSSOCallbackHandler))
+	router.Add(webapphandler.ConfigureSAMLACSRoute(webappSSOCallbackRoute), p.Handler(newWebAppSAMLACSHandler))
+	router.Add(webapphandler.ConfigureSAMLMetadataRoute(staticRoute), p.Handler(newWebAppSAMLMetadataHandler))
 
 	router.Add(webapphandler.ConfigureWechatAuthRoute(webappPageRoute), p.Handler(newWechatAuthHandler))
 	router.Add(webapphandler.ConfigureWechatCallbackRoute(webappSSOCallbackRoute), p.Handler(newWechatCallbackHandler))
@@ -178,6 +189,9 @@ func NewRouter(p *deps.RootProvider, configSource *configsource.ConfigSource) *h
 	router.Add(oauthhandler.ConfigureFromWebAppRoute(oauthAPIRoute), p.Handler(newOAuthFromWebAppHandler))
 	router.Add(oauthhandler.ConfigureTokenRoute(oauthAPIRoute), p.Handler(newOAuthTokenHandler))
 	router.Add(oauthhandler.ConfigureRevokeRoute(oauthAPIRoute), p.Handler(newOAuthRevokeHandler))
+	router.Add(oauthhandler.ConfigureIntrospectionRoute(oauthAPIRoute), p.Handler(newOAuthIntrospectionHandler))
+	router.Add(oauthhandler.ConfigureDeviceAuthorizationRoute(oauthAPIRoute), p.Handler(newOAuthDeviceAuthorizationHandler))
+	router.Add(oauthhandler.ConfigurePushedAuthorizationRequestRoute(oauthAPIRoute), p.Handler(newOAuthPushedAuthorizationRequestHandler))
 	router.Add(oauthhandler.ConfigureEndSessionRoute(oauthAPIRoute), p.Handler(newOAuthEndSessionHandler))
 
 	router.Add(oauthhandler.ConfigureChallengeRoute(apiRoute), p.Handler(newOAuthChallengeHandler))
@@ -18,6 +18,18 @@ var _ nodes.InputCreateAuthenticatorTOTPSetup = &inputSelectTOTP{}
 
 func (i *inputSelectTOTP) SetupTOTP() {}
 
+type inputTriggerPasskey struct{}
+
+var _ nodes.InputAuthenticationPasskeyTrigger = &inputTriggerPasskey{}
+
+func (i *inputTriggerPasskey) TriggerPasskey() {}
+
+type inputSelectPasskey struct{}
+
+var _ nodes.InputCreateAuthenticatorPasskeySetup = &inputSelectPasskey{}
+
+func (i *inputSelectPasskey) SetupPasskey() {}
+
 type inputAuthDeviceToken struct {
 	DeviceToken string
 }
@@ -199,6 +199,10 @@ func (s *Service2) doPost(
 					SessionStepEnterTOTP,
 					graph.InstanceID,
 				))
+			case *nodes.EdgeAuthenticationPasskeyTrigger:
+				inputFn = func() (interface{}, error) {
+					return &inputTriggerPasskey{}, nil
+				}
 			case *nodes.EdgeAuthenticationOOBTrigger:
 				inputFn = func() (input interface{}, err error) {
 					input = &inputTriggerOOB{
@@ -236,6 +240,10 @@ func (s *Service2) doPost(
 				inputFn = func() (interface{}, error) {
 					return &inputSelectTOTP{}, nil
 				}
+			case *nodes.EdgeCreateAuthenticatorPasskeySetup:
+				inputFn = func() (interface{}, error) {
+					return &inputSelectPasskey{}, nil
+				}
 			default:
 				panic(fmt.Errorf("webapp: unexpected edge: %T", defaultEdge))
 			}
@@ -454,6 +462,10 @@ func deriveSessionStepKind(graph *interaction.Graph) SessionStepKind {
 		}
 	case *nodes.NodeCreateAuthenticatorTOTPSetup:
 		return SessionStepSetupTOTP
+	case *nodes.NodeAuthenticationPasskeyTrigger:
+		return SessionStepEnterPasskey
+	case *nodes.NodeCreateAuthenticatorPasskeySetup:
+		return SessionStepSetupPasskey
 	case *nodes.NodeGenerateRecoveryCodeBegin:
 		return SessionStepSetupRecoveryCode
 	case *nodes.NodeVerifyIdentity:
@@ -24,6 +24,8 @@ const (
 	SessionStepSetupOOBOTPSMS          SessionStepKind = "setup-oob-otp-sms"
 	SessionStepEnterTOTP               SessionStepKind = "enter-totp"
 	SessionStepSetupTOTP               SessionStepKind = "setup-totp"
+	SessionStepEnterPasskey            SessionStepKind = "enter-passkey"
+	SessionStepSetupPasskey            SessionStepKind = "setup-passkey"
 	SessionStepEnterRecoveryCode       SessionStepKind = "enter-recovery-code"
 	SessionStepSetupRecoveryCode       SessionStepKind = "setup-recovery-code"
 	SessionStepVerifyIdentity          SessionStepKind = "verify-identity"
@@ -63,6 +65,10 @@ func (k SessionStepKind) Path() string {
 		return "/enter_totp"
 	case SessionStepSetupTOTP:
 		return "/setup_totp"
+	case SessionStepEnterPasskey:
+		return "/enter_passkey"
+	case SessionStepSetupPasskey:
+		return "/setup_passkey"
 	case SessionStepEnterRecoveryCode:
 		return "/enter_recovery_code"
 	case SessionStepSetupRecoveryCode:
@@ -88,17 +94,18 @@ func (k SessionStepKind) MatchPath(path string) bool {
 		// redirect user to page that display qr code
 		// https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/Wechat_webpage_authorization.html
 		return strings.HasPrefix(path, "/sso/wechat/auth/") ||
-			strings.HasPrefix(path, "/sso/oauth2/callback/")
+			strings.HasPrefix(path, "/sso/oauth2/callback/") ||
+			strings.HasPrefix(path, "/sso/saml/acs/")
 	case SessionStepAuthenticate:
 		switch path {
-		case "/enter_totp", "/enter_password", "/enter_oob_otp", "/enter_recovery_code":
+		case "/enter_totp", "/enter_password", "/enter_oob_otp", "/enter_recovery_code", "/enter_passkey":
 			return true
 		default:
 			return false
 		}
 	case SessionStepCreateAuthenticator:
 		switch path {
-		case "/setup_totp", "/setup_oob_otp", "/create_password":
+		case "/setup_totp", "/setup_oob_otp", "/create_password", "/setup_passkey":
 			return true
 		default:
 			return false
@@ -19,7 +19,10 @@ type EndpointsProvider interface {
 	SettingsEndpointURL() *url.URL
 	ResetPasswordEndpointURL() *url.URL
 	VerifyIdentityEndpointURL() *url.URL
+	MagicLinkEndpointURL() *url.URL
 	SSOCallbackEndpointURL() *url.URL
+	SAMLMetadataEndpointURL() *url.URL
+	SAMLACSEndpointURL() *url.URL
 	WeChatAuthorizeEndpointURL() *url.URL
 	WeChatCallbackEndpointURL() *url.URL
 }
@@ -53,12 +56,31 @@ func (p *URLProvider) VerifyIdentityURL(code string, id string) *url.URL {
 	)
 }
 
+func (p *URLProvider) MagicLinkURL(authenticatorID string, token string) *url.URL {
+	return urlutil.WithQueryParamsAdded(
+		p.Endpoints.MagicLinkEndpointURL(),
+		map[string]string{"id": authenticatorID, "token": token},
+	)
+}
+
 func (p *URLProvider) SSOCallbackURL(c config.OAuthSSOProviderConfig) *url.URL {
 	u := p.Endpoints.SSOCallbackEndpointURL()
 	u.Path = path.Join(u.Path, url.PathEscape(c.Alias))
 	return u
 }
 
+func (p *URLProvider) SAMLMetadataURL(alias string) *url.URL {
+	u := p.Endpoints.SAMLMetadataEndpointURL()
+	u.Path = path.Join(u.Path, url.PathEscape(alias))
+	return u
+}
+
+func (p *URLProvider) SAMLACSURL(alias string) *url.URL {
+	u := p.Endpoints.SAMLACSEndpointURL()
+	u.Path = path.Join(u.Path, url.PathEscape(alias))
+	return u
+}
+
 type AuthenticateURLPageService interface {
 	CreateSession(session *Session, redirectURI string) (*Result, error)
 }
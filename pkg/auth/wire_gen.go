@@ -17,6 +17,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/audit"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/passkey"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
 	service2 "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/service"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/totp"
@@ -26,6 +27,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/loginid"
 	oauth3 "github.com/authgear/authgear-server/pkg/lib/authn/identity/oauth"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/service"
+	"github.com/authgear/authgear-server/pkg/lib/authn/lockout"
 	"github.com/authgear/authgear-server/pkg/lib/authn/mfa"
 	"github.com/authgear/authgear-server/pkg/lib/authn/otp"
 	"github.com/authgear/authgear-server/pkg/lib/authn/sso"
@@ -35,6 +37,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/deps"
 	"github.com/authgear/authgear-server/pkg/lib/elasticsearch"
 	"github.com/authgear/authgear-server/pkg/lib/event"
+	"github.com/authgear/authgear-server/pkg/lib/eventstream"
 	"github.com/authgear/authgear-server/pkg/lib/facade"
 	"github.com/authgear/authgear-server/pkg/lib/feature/customattrs"
 	"github.com/authgear/authgear-server/pkg/lib/feature/forgotpassword"
@@ -259,7 +262,9 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -273,6 +278,7 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -303,22 +309,55 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       store2,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -358,6 +397,7 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -393,6 +433,7 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -427,15 +468,33 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -455,7 +514,19 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -524,16 +595,18 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	webappURLProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       webappURLProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	webappURLProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -544,6 +617,8 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  webappURLProvider,
 		Clock:                        clock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              webappURLProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -616,6 +691,7 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -624,6 +700,7 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -689,24 +766,28 @@ func newOAuthAuthorizeHandler(p *deps.RequestProvider) http.Handler {
 		Clock:   clock,
 	}
 	authorizationHandler := &handler.AuthorizationHandler{
-		Context:                   contextContext,
-		AppID:                     appID,
-		Config:                    oAuthConfig,
-		HTTPConfig:                httpConfig,
-		Logger:                    authorizationHandlerLogger,
-		Sessions:                  provider,
-		Authorizations:            authorizationStore,
-		OfflineGrants:             store,
-		CodeGrants:                store,
-		OAuthURLs:                 urlProvider,
-		WebAppURLs:                authenticateURLProvider,
-		ValidateScopes:            scopesValidator,
-		CodeGenerator:             tokenGenerator,
-		LoginHint:                 loginHintHandler,
-		IDTokens:                  idTokenIssuer,
-		AuthenticationInfoService: authenticationinfoStoreRedis,
-		Clock:                     clock,
-		Cookies:                   cookieManager,
+		Context:                     contextContext,
+		AppID:                       appID,
+		Config:                      oAuthConfig,
+		HTTPConfig:                  httpConfig,
+		Logger:                      authorizationHandlerLogger,
+		Sessions:                    provider,
+		Authorizations:              authorizationStore,
+		OfflineGrants:               store,
+		CodeGrants:                  store,
+		PushedAuthorizationRequests: store,
+		ConsentRequests:             store,
+		DeviceCodeGrants:            store,
+		OAuthURLs:                   urlProvider,
+		DeviceURLs:                  urlProvider,
+		WebAppURLs:                  authenticateURLProvider,
+		ValidateScopes:              scopesValidator,
+		CodeGenerator:               tokenGenerator,
+		LoginHint:                   loginHintHandler,
+		IDTokens:                    idTokenIssuer,
+		AuthenticationInfoService:   authenticationinfoStoreRedis,
+		Clock:                       clock,
+		Cookies:                     cookieManager,
 	}
 	authorizeHandler := &oauth.AuthorizeHandler{
 		Logger:       authorizeHandlerLogger,
@@ -894,7 +975,9 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -908,6 +991,7 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -938,22 +1022,55 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       store2,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -993,6 +1110,7 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -1028,6 +1146,7 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -1062,15 +1181,33 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -1090,7 +1227,19 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -1159,16 +1308,18 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	webappURLProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       webappURLProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	webappURLProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -1179,6 +1330,8 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  webappURLProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              webappURLProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -1251,6 +1404,7 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -1259,6 +1413,7 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -1324,24 +1479,28 @@ func newOAuthFromWebAppHandler(p *deps.RequestProvider) http.Handler {
 		Clock:   clockClock,
 	}
 	authorizationHandler := &handler.AuthorizationHandler{
-		Context:                   contextContext,
-		AppID:                     appID,
-		Config:                    oAuthConfig,
-		HTTPConfig:                httpConfig,
-		Logger:                    authorizationHandlerLogger,
-		Sessions:                  provider,
-		Authorizations:            authorizationStore,
-		OfflineGrants:             store,
-		CodeGrants:                store,
-		OAuthURLs:                 urlProvider,
-		WebAppURLs:                authenticateURLProvider,
-		ValidateScopes:            scopesValidator,
-		CodeGenerator:             tokenGenerator,
-		LoginHint:                 loginHintHandler,
-		IDTokens:                  idTokenIssuer,
-		AuthenticationInfoService: authenticationinfoStoreRedis,
-		Clock:                     clockClock,
-		Cookies:                   cookieManager,
+		Context:                     contextContext,
+		AppID:                       appID,
+		Config:                      oAuthConfig,
+		HTTPConfig:                  httpConfig,
+		Logger:                      authorizationHandlerLogger,
+		Sessions:                    provider,
+		Authorizations:              authorizationStore,
+		OfflineGrants:               store,
+		CodeGrants:                  store,
+		PushedAuthorizationRequests: store,
+		ConsentRequests:             store,
+		DeviceCodeGrants:            store,
+		OAuthURLs:                   urlProvider,
+		DeviceURLs:                  urlProvider,
+		WebAppURLs:                  authenticateURLProvider,
+		ValidateScopes:              scopesValidator,
+		CodeGenerator:               tokenGenerator,
+		LoginHint:                   loginHintHandler,
+		IDTokens:                    idTokenIssuer,
+		AuthenticationInfoService:   authenticationinfoStoreRedis,
+		Clock:                       clockClock,
+		Cookies:                     cookieManager,
 	}
 	fromWebAppHandler := &oauth.FromWebAppHandler{
 		Logger:   fromWebAppHandlerLogger,
@@ -1360,8 +1519,9 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 	appConfig := config.AppConfig
 	appID := appConfig.ID
 	oAuthConfig := appConfig.OAuth
-	handlerTokenHandlerLogger := handler.NewTokenHandlerLogger(factory)
 	secretConfig := config.SecretConfig
+	oAuthClientSecrets := deps.ProvideOAuthClientSecrets(secretConfig)
+	handlerTokenHandlerLogger := handler.NewTokenHandlerLogger(factory)
 	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
 	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
 	request := p.Request
@@ -1469,7 +1629,9 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -1483,6 +1645,7 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -1513,22 +1676,56 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	httpConfig := appConfig.HTTP
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       store2,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -1568,6 +1765,7 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -1584,7 +1782,6 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 	engine := &template.Engine{
 		Resolver: resolver,
 	}
-	httpConfig := appConfig.HTTP
 	localizationConfig := appConfig.Localization
 	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
 	staticAssetResolver := &web.StaticAssetResolver{
@@ -1604,6 +1801,7 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -1638,15 +1836,33 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -1666,7 +1882,19 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -1751,16 +1979,18 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -1771,6 +2001,8 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -1864,6 +2096,7 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -1872,6 +2105,7 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -1921,19 +2155,31 @@ func newOAuthTokenHandler(p *deps.RequestProvider) http.Handler {
 		GenerateToken:     tokenGenerator,
 		Clock:             clockClock,
 		Users:             queries,
+		Events:            eventService,
+	}
+	customClaimsLogger := handler.NewCustomClaimsLogger(factory)
+	customClaimsService := &handler.CustomClaimsService{
+		Logger: customClaimsLogger,
+		Events: eventService,
 	}
 	tokenHandler := &handler.TokenHandler{
 		AppID:            appID,
 		Config:           oAuthConfig,
+		ClientSecrets:    oAuthClientSecrets,
 		Logger:           handlerTokenHandlerLogger,
 		Authorizations:   authorizationStore,
 		CodeGrants:       store,
+		DeviceCodeGrants: store,
 		OfflineGrants:    store,
 		AppSessionTokens: store,
 		Graphs:           interactionService,
 		IDTokenIssuer:    idTokenIssuer,
 		Clock:            clockClock,
 		TokenService:     tokenService,
+		RateLimiter:      limiter,
+		Events:           eventService,
+		Hooks:            deliverer,
+		CustomClaims:     customClaimsService,
 	}
 	oauthTokenHandler := &oauth.TokenHandler{
 		Logger:       tokenHandlerLogger,
@@ -2000,6 +2246,7 @@ func newOAuthRevokeHandler(p *deps.RequestProvider) http.Handler {
 	localizationConfig := appConfig.Localization
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -2093,7 +2340,9 @@ func newOAuthRevokeHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, resourceManager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -2107,6 +2356,7 @@ func newOAuthRevokeHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -2137,22 +2387,55 @@ func newOAuthRevokeHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       store2,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -2205,15 +2488,34 @@ func newOAuthRevokeHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	queue := appProvider.TaskQueue
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          resourceManager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -2233,7 +2535,19 @@ func newOAuthRevokeHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	manager2 := &session.Manager{
 		IDPSessions:         manager,
 		AccessTokenSessions: sessionManager,
@@ -2252,43 +2566,71 @@ func newOAuthRevokeHandler(p *deps.RequestProvider) http.Handler {
 	return oauthRevokeHandler
 }
 
-func newOAuthMetadataHandler(p *deps.RequestProvider) http.Handler {
-	request := p.Request
+func newOAuthIntrospectionHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
-	rootProvider := appProvider.RootProvider
-	environmentConfig := rootProvider.EnvironmentConfig
-	trustProxy := environmentConfig.TrustProxy
-	mainOriginProvider := &MainOriginProvider{
-		Request:    request,
-		TrustProxy: trustProxy,
+	factory := appProvider.LoggerFactory
+	introspectionHandlerLogger := oauth.NewIntrospectionHandlerLogger(factory)
+	handle := appProvider.AppDatabase
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	oAuthConfig := appConfig.OAuth
+	secretConfig := config.SecretConfig
+	oAuthClientSecrets := deps.ProvideOAuthClientSecrets(secretConfig)
+	handlerIntrospectionHandlerLogger := handler.NewIntrospectionHandlerLogger(factory)
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	appID := appConfig.ID
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	request := p.Request
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
 	}
-	endpointsProvider := &EndpointsProvider{
-		OriginProvider: mainOriginProvider,
+	appredisHandle := appProvider.Redis
+	logger := redis.NewLogger(factory)
+	clockClock := _wireSystemClockValue
+	store := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      logger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
 	}
-	metadataProvider := &oauth2.MetadataProvider{
-		Endpoints: endpointsProvider,
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	storeRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
 	}
-	oidcMetadataProvider := &oidc.MetadataProvider{
-		Endpoints: endpointsProvider,
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
 	}
-	v := ProvideOAuthMetadataProviders(metadataProvider, oidcMetadataProvider)
-	metadataHandler := &oauth.MetadataHandler{
-		Providers: v,
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
 	}
-	return metadataHandler
-}
-
-func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
-	appProvider := p.AppProvider
-	factory := appProvider.LoggerFactory
-	jwksHandlerLogger := oauth.NewJWKSHandlerLogger(factory)
-	config := appProvider.Config
-	secretConfig := config.SecretConfig
-	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
-	request := p.Request
 	rootProvider := appProvider.RootProvider
 	environmentConfig := rootProvider.EnvironmentConfig
 	trustProxy := environmentConfig.TrustProxy
+	sessionConfig := appConfig.Session
+	idpsessionRand := _wireRandValue
+	provider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        storeRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
 	mainOriginProvider := &MainOriginProvider{
 		Request:    request,
 		TrustProxy: trustProxy,
@@ -2296,21 +2638,13 @@ func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
 	endpointsProvider := &EndpointsProvider{
 		OriginProvider: mainOriginProvider,
 	}
-	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
-	appConfig := config.AppConfig
-	appID := appConfig.ID
-	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
-	contextContext := deps.ProvideRequestContext(request)
-	handle := appProvider.AppDatabase
-	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
-	clockClock := _wireSystemClockValue
-	store := &user.Store{
+	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 		Clock:       clockClock,
 	}
 	rawQueries := &user.RawQueries{
-		Store: store,
+		Store: userStore,
 	}
 	authenticationConfig := appConfig.Authentication
 	identityConfig := appConfig.Identity
@@ -2337,7 +2671,7 @@ func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
 	normalizerFactory := &loginid.NormalizerFactory{
 		Config: loginIDConfig,
 	}
-	provider := &loginid.Provider{
+	loginidProvider := &loginid.Provider{
 		Store:             loginidStore,
 		Config:            loginIDConfig,
 		Checker:           checker,
@@ -2373,7 +2707,7 @@ func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
 		Identity:              identityConfig,
 		IdentityFeatureConfig: identityFeatureConfig,
 		Store:                 serviceStore,
-		LoginID:               provider,
+		LoginID:               loginidProvider,
 		OAuth:                 oauthProvider,
 		Anonymous:             anonymousProvider,
 		Biometric:             biometricProvider,
@@ -2388,13 +2722,15 @@ func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
 	}
 	authenticatorConfig := appConfig.Authenticator
 	authenticatorPasswordConfig := authenticatorConfig.Password
-	logger := password.NewLogger(factory)
+	passwordLogger := password.NewLogger(factory)
 	historyStore := &password.HistoryStore{
 		Clock:       clockClock,
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -2405,9 +2741,10 @@ func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
 		Store:           passwordStore,
 		Config:          authenticatorPasswordConfig,
 		Clock:           clockClock,
-		Logger:          logger,
+		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -2425,8 +2762,7 @@ func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	appredisHandle := appProvider.Redis
-	storeRedis := &oob.StoreRedis{
+	oobStoreRedis := &oob.StoreRedis{
 		Redis: appredisHandle,
 		AppID: appID,
 		Clock: clockClock,
@@ -2435,26 +2771,60 @@ func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
 	oobProvider := &oob.Provider{
 		Config:    authenticatorOOBConfig,
 		Store:     oobStore,
-		CodeStore: storeRedis,
+		CodeStore: oobStoreRedis,
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	httpConfig := appConfig.HTTP
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       store2,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -2483,17 +2853,17 @@ func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
 		UserProfileConfig: userProfileConfig,
 		Identities:        serviceService,
 		UserQueries:       rawQueries,
-		UserStore:         store,
+		UserStore:         userStore,
 		ClaimStore:        storePQ,
 	}
 	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
 		Config:      userProfileConfig,
 		UserQueries: rawQueries,
-		UserStore:   store,
+		UserStore:   userStore,
 	}
 	queries := &user.Queries{
 		RawQueries:         rawQueries,
-		Store:              store,
+		Store:              userStore,
 		Identities:         serviceService,
 		Authenticators:     service3,
 		Verification:       verificationService,
@@ -2506,50 +2876,189 @@ func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
 		Users:   queries,
 		Clock:   clockClock,
 	}
-	jwksHandler := &oauth.JWKSHandler{
-		Logger: jwksHandlerLogger,
-		JWKS:   idTokenIssuer,
+	accessTokenEncoding := &oauth2.AccessTokenEncoding{
+		Secrets:    oAuthKeyMaterials,
+		Clock:      clockClock,
+		UserClaims: idTokenIssuer,
+		BaseURL:    endpointsProvider,
 	}
-	return jwksHandler
+	tokenGenerator := _wireTokenGeneratorValue
+	eventLogger := event.NewLogger(factory)
+	localizationConfig := appConfig.Localization
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	queue := appProvider.TaskQueue
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	tokenService := handler.TokenService{
+		Request:           request,
+		AppID:             appID,
+		Config:            oAuthConfig,
+		TrustProxy:        trustProxy,
+		Authorizations:    authorizationStore,
+		OfflineGrants:     store,
+		AccessGrants:      store,
+		AccessEvents:      eventProvider,
+		AccessTokenIssuer: accessTokenEncoding,
+		GenerateToken:     tokenGenerator,
+		Clock:             clockClock,
+		Users:             queries,
+		Events:            eventService,
+	}
+	introspectionHandler := &handler.IntrospectionHandler{
+		Config:             oAuthConfig,
+		ClientSecrets:      oAuthClientSecrets,
+		Logger:             handlerIntrospectionHandlerLogger,
+		Authorizations:     authorizationStore,
+		AccessGrants:       store,
+		OfflineGrants:      store,
+		Sessions:           provider,
+		AccessTokenDecoder: accessTokenEncoding,
+		TokenService:       tokenService,
+		Users:              queries,
+		Clock:              clockClock,
+	}
+	oauthIntrospectionHandler := &oauth.IntrospectionHandler{
+		Logger:   introspectionHandlerLogger,
+		Database: handle,
+		Handler:  introspectionHandler,
+	}
+	return oauthIntrospectionHandler
 }
 
-func newOAuthUserInfoHandler(p *deps.RequestProvider) http.Handler {
+func newOAuthDeviceAuthorizationHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
-	userInfoHandlerLogger := oauth.NewUserInfoHandlerLogger(factory)
+	deviceAuthorizationHandlerLogger := oauth.NewDeviceAuthorizationHandlerLogger(factory)
 	handle := appProvider.AppDatabase
+	request := p.Request
+	contextContext := deps.ProvideRequestContext(request)
 	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	oAuthConfig := appConfig.OAuth
 	secretConfig := config.SecretConfig
-	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
-	request := p.Request
+	oAuthClientSecrets := deps.ProvideOAuthClientSecrets(secretConfig)
+	handlerDeviceAuthorizationHandlerLogger := handler.NewDeviceAuthorizationHandlerLogger(factory)
 	rootProvider := appProvider.RootProvider
 	environmentConfig := rootProvider.EnvironmentConfig
 	trustProxy := environmentConfig.TrustProxy
-	mainOriginProvider := &MainOriginProvider{
-		Request:    request,
-		TrustProxy: trustProxy,
-	}
-	endpointsProvider := &EndpointsProvider{
-		OriginProvider: mainOriginProvider,
-	}
 	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
-	appConfig := config.AppConfig
-	appID := appConfig.ID
 	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
-	contextContext := deps.ProvideRequestContext(request)
 	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	appredisHandle := appProvider.Redis
+	logger := redis.NewLogger(factory)
 	clockClock := _wireSystemClockValue
-	store := &user.Store{
+	store := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      logger,
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 		Clock:       clockClock,
 	}
-	rawQueries := &user.RawQueries{
-		Store: store,
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	urlProvider := &oauth2.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	serviceLogger := webapp.NewServiceLogger(factory)
+	sessionStoreRedis := &webapp.SessionStoreRedis{
+		AppID: appID,
+		Redis: appredisHandle,
 	}
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
 	authenticationConfig := appConfig.Authentication
-	identityConfig := appConfig.Identity
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
+	}
+	interactionLogger := interaction.NewLogger(factory)
 	featureConfig := config.FeatureConfig
+	identityConfig := appConfig.Identity
 	identityFeatureConfig := featureConfig.Identity
 	serviceStore := &service.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -2623,13 +3132,15 @@ func newOAuthUserInfoHandler(p *deps.RequestProvider) http.Handler {
 	}
 	authenticatorConfig := appConfig.Authenticator
 	authenticatorPasswordConfig := authenticatorConfig.Password
-	logger := password.NewLogger(factory)
+	passwordLogger := password.NewLogger(factory)
 	historyStore := &password.HistoryStore{
 		Clock:       clockClock,
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -2640,9 +3151,10 @@ func newOAuthUserInfoHandler(p *deps.RequestProvider) http.Handler {
 		Store:           passwordStore,
 		Config:          authenticatorPasswordConfig,
 		Clock:           clockClock,
-		Logger:          logger,
+		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -2660,7 +3172,6 @@ func newOAuthUserInfoHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	appredisHandle := appProvider.Redis
 	storeRedis := &oob.StoreRedis{
 		Redis: appredisHandle,
 		AppID: appID,
@@ -2674,22 +3185,55 @@ func newOAuthUserInfoHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       store2,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -2714,164 +3258,576 @@ func newOAuthUserInfoHandler(p *deps.RequestProvider) http.Handler {
 		ClaimStore:        storePQ,
 		RateLimiter:       limiter,
 	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
 	serviceNoEvent := &stdattrs.ServiceNoEvent{
 		UserProfileConfig: userProfileConfig,
 		Identities:        serviceService,
 		UserQueries:       rawQueries,
-		UserStore:         store,
+		UserStore:         userStore,
 		ClaimStore:        storePQ,
 	}
 	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
 		Config:      userProfileConfig,
 		UserQueries: rawQueries,
-		UserStore:   store,
+		UserStore:   userStore,
 	}
 	queries := &user.Queries{
 		RawQueries:         rawQueries,
-		Store:              store,
+		Store:              userStore,
 		Identities:         serviceService,
 		Authenticators:     service3,
 		Verification:       verificationService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
 	}
-	idTokenIssuer := &oidc.IDTokenIssuer{
-		Secrets: oAuthKeyMaterials,
-		BaseURL: endpointsProvider,
-		Users:   queries,
-		Clock:   clockClock,
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
 	}
-	userInfoHandler := &oauth.UserInfoHandler{
-		Logger:           userInfoHandlerLogger,
-		Database:         handle,
-		UserInfoProvider: idTokenIssuer,
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
 	}
-	return userInfoHandler
-}
-
-func newOAuthEndSessionHandler(p *deps.RequestProvider) http.Handler {
-	appProvider := p.AppProvider
-	factory := appProvider.LoggerFactory
-	endSessionHandlerLogger := oauth.NewEndSessionHandlerLogger(factory)
-	handle := appProvider.AppDatabase
-	config := appProvider.Config
-	appConfig := config.AppConfig
-	oAuthConfig := appConfig.OAuth
-	request := p.Request
-	rootProvider := appProvider.RootProvider
-	environmentConfig := rootProvider.EnvironmentConfig
-	trustProxy := environmentConfig.TrustProxy
-	mainOriginProvider := &MainOriginProvider{
-		Request:    request,
-		TrustProxy: trustProxy,
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
 	}
-	endpointsProvider := &EndpointsProvider{
-		OriginProvider: mainOriginProvider,
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
 	}
-	appredisHandle := appProvider.Redis
-	appID := appConfig.ID
-	clockClock := _wireSystemClockValue
 	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
-	storeRedis := &idpsession.StoreRedis{
+	idpsessionStoreRedis := &idpsession.StoreRedis{
 		Redis:  appredisHandle,
 		AppID:  appID,
 		Clock:  clockClock,
 		Logger: storeRedisLogger,
 	}
 	sessionConfig := appConfig.Session
-	httpConfig := appConfig.HTTP
-	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
-	cookieDef := session.NewSessionCookieDef(sessionConfig)
-	manager := &idpsession.Manager{
-		Store:     storeRedis,
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
 		Clock:     clockClock,
 		Config:    sessionConfig,
 		Cookies:   cookieManager,
-		CookieDef: cookieDef,
-	}
-	contextContext := deps.ProvideRequestContext(request)
-	logger := redis.NewLogger(factory)
-	secretConfig := config.SecretConfig
-	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
-	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
-	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
-	store := &redis.Store{
-		Context:     contextContext,
-		Redis:       appredisHandle,
-		AppID:       appID,
-		Logger:      logger,
-		SQLBuilder:  sqlBuilderApp,
-		SQLExecutor: sqlExecutor,
-		Clock:       clockClock,
+		CookieDef: cookieDef2,
 	}
 	sessionManager := &oauth2.SessionManager{
 		Store:  store,
 		Clock:  clockClock,
 		Config: oAuthConfig,
 	}
-	eventLogger := event.NewLogger(factory)
-	localizationConfig := appConfig.Localization
-	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
-	storeImpl := &event.StoreImpl{
-		SQLBuilder:  sqlBuilder,
-		SQLExecutor: sqlExecutor,
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
 	}
-	userStore := &user.Store{
-		SQLBuilder:  sqlBuilderApp,
-		SQLExecutor: sqlExecutor,
-		Clock:       clockClock,
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
 	}
-	rawQueries := &user.RawQueries{
-		Store: userStore,
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
 	}
-	authenticationConfig := appConfig.Authentication
-	identityConfig := appConfig.Identity
-	featureConfig := config.FeatureConfig
-	identityFeatureConfig := featureConfig.Identity
-	serviceStore := &service.Store{
-		SQLBuilder:  sqlBuilderApp,
-		SQLExecutor: sqlExecutor,
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
 	}
-	loginidStore := &loginid.Store{
-		SQLBuilder:  sqlBuilderApp,
-		SQLExecutor: sqlExecutor,
+	webappURLProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
 	}
-	loginIDConfig := identityConfig.LoginID
-	resourceManager := appProvider.Resources
-	typeCheckerFactory := &loginid.TypeCheckerFactory{
-		Config:    loginIDConfig,
-		Resources: resourceManager,
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       webappURLProvider,
 	}
-	checker := &loginid.Checker{
-		Config:             loginIDConfig,
-		TypeCheckerFactory: typeCheckerFactory,
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
 	}
-	normalizerFactory := &loginid.NormalizerFactory{
-		Config: loginIDConfig,
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
 	}
-	provider := &loginid.Provider{
-		Store:             loginidStore,
-		Config:            loginIDConfig,
-		Checker:           checker,
-		NormalizerFactory: normalizerFactory,
-		Clock:             clockClock,
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  webappURLProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              webappURLProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
 	}
-	oauthStore := &oauth3.Store{
-		SQLBuilder:  sqlBuilderApp,
-		SQLExecutor: sqlExecutor,
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
 	}
-	oauthProvider := &oauth3.Provider{
-		Store: oauthStore,
-		Clock: clockClock,
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           webappURLProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
 	}
-	anonymousStore := &anonymous.Store{
-		SQLBuilder:  sqlBuilderApp,
-		SQLExecutor: sqlExecutor,
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       webappURLProvider,
 	}
-	anonymousProvider := &anonymous.Provider{
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
+	}
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
+	}
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	interactionService := &interaction.Service{
+		Logger:  interactionLogger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
+	}
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
+	}
+	authenticateURLProvider := &webapp.AuthenticateURLProvider{
+		Endpoints: endpointsProvider,
+		Pages:     webappService2,
+		Clock:     clockClock,
+	}
+	scopesValidator := _wireScopesValidatorValue
+	tokenGenerator := _wireTokenGeneratorValue
+	deviceAuthorizationHandler := &handler.DeviceAuthorizationHandler{
+		Context:                   contextContext,
+		AppID:                     appID,
+		Config:                    oAuthConfig,
+		ClientSecrets:             oAuthClientSecrets,
+		Logger:                    handlerDeviceAuthorizationHandlerLogger,
+		TrustProxy:                trustProxy,
+		Authorizations:            authorizationStore,
+		DeviceCodeGrants:          store,
+		ConsentRequests:           store,
+		DeviceURLs:                urlProvider,
+		WebAppURLs:                authenticateURLProvider,
+		ValidateScopes:            scopesValidator,
+		CodeGenerator:             tokenGenerator,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Cookies:                   cookieManager,
+		RateLimiter:               limiter,
+		Clock:                     clockClock,
+	}
+	oauthDeviceAuthorizationHandler := &oauth.DeviceAuthorizationHandler{
+		Logger:   deviceAuthorizationHandlerLogger,
+		Database: handle,
+		Handler:  deviceAuthorizationHandler,
+	}
+	return oauthDeviceAuthorizationHandler
+}
+
+func newOAuthPushedAuthorizationRequestHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	pushedAuthorizationRequestHandlerLogger := oauth.NewPushedAuthorizationRequestHandlerLogger(factory)
+	handle := appProvider.AppDatabase
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	oAuthConfig := appConfig.OAuth
+	secretConfig := config.SecretConfig
+	oAuthClientSecrets := deps.ProvideOAuthClientSecrets(secretConfig)
+	httpConfig := appConfig.HTTP
+	pushedAuthorizationHandlerLogger := handler.NewPushedAuthorizationHandlerLogger(factory)
+	request := p.Request
+	contextContext := deps.ProvideRequestContext(request)
+	appredisHandle := appProvider.Redis
+	logger := redis.NewLogger(factory)
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	store := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      logger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	scopesValidator := _wireScopesValidatorValue
+	tokenGenerator := _wireTokenGeneratorValue
+	pushedAuthorizationHandler := &handler.PushedAuthorizationHandler{
+		AppID:                       appID,
+		Config:                      oAuthConfig,
+		ClientSecrets:               oAuthClientSecrets,
+		HTTPConfig:                  httpConfig,
+		Logger:                      pushedAuthorizationHandlerLogger,
+		PushedAuthorizationRequests: store,
+		ValidateScopes:              scopesValidator,
+		RequestURIGenerator:         tokenGenerator,
+		Clock:                       clockClock,
+	}
+	pushedAuthorizationRequestHandler := &oauth.PushedAuthorizationRequestHandler{
+		Logger:   pushedAuthorizationRequestHandlerLogger,
+		Database: handle,
+		Handler:  pushedAuthorizationHandler,
+	}
+	return pushedAuthorizationRequestHandler
+}
+
+func newOAuthMetadataHandler(p *deps.RequestProvider) http.Handler {
+	request := p.Request
+	appProvider := p.AppProvider
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	metadataProvider := &oauth2.MetadataProvider{
+		Endpoints: endpointsProvider,
+	}
+	oidcMetadataProvider := &oidc.MetadataProvider{
+		Endpoints: endpointsProvider,
+	}
+	v := ProvideOAuthMetadataProviders(metadataProvider, oidcMetadataProvider)
+	metadataHandler := &oauth.MetadataHandler{
+		Providers: v,
+	}
+	return metadataHandler
+}
+
+func newOAuthJWKSHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	jwksHandlerLogger := oauth.NewJWKSHandlerLogger(factory)
+	config := appProvider.Config
+	secretConfig := config.SecretConfig
+	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
+	request := p.Request
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	contextContext := deps.ProvideRequestContext(request)
+	handle := appProvider.AppDatabase
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	store := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	rawQueries := &user.RawQueries{
+		Store: store,
+	}
+	authenticationConfig := appConfig.Authentication
+	identityConfig := appConfig.Identity
+	featureConfig := config.FeatureConfig
+	identityFeatureConfig := featureConfig.Identity
+	serviceStore := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
 		Store: anonymousStore,
 		Clock: clockClock,
 	}
@@ -2903,13 +3859,15 @@ func newOAuthEndSessionHandler(p *deps.RequestProvider) http.Handler {
 	}
 	authenticatorConfig := appConfig.Authenticator
 	authenticatorPasswordConfig := authenticatorConfig.Password
-	passwordLogger := password.NewLogger(factory)
+	logger := password.NewLogger(factory)
 	historyStore := &password.HistoryStore{
 		Clock:       clockClock,
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, logger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -2920,9 +3878,10 @@ func newOAuthEndSessionHandler(p *deps.RequestProvider) http.Handler {
 		Store:           passwordStore,
 		Config:          authenticatorPasswordConfig,
 		Clock:           clockClock,
-		Logger:          passwordLogger,
+		Logger:          logger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -2940,7 +3899,8 @@ func newOAuthEndSessionHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	oobStoreRedis := &oob.StoreRedis{
+	appredisHandle := appProvider.Redis
+	storeRedis := &oob.StoreRedis{
 		Redis: appredisHandle,
 		AppID: appID,
 		Clock: clockClock,
@@ -2949,26 +3909,60 @@ func newOAuthEndSessionHandler(p *deps.RequestProvider) http.Handler {
 	oobProvider := &oob.Provider{
 		Config:    authenticatorOOBConfig,
 		Store:     oobStore,
-		CodeStore: oobStoreRedis,
+		CodeStore: storeRedis,
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	httpConfig := appConfig.HTTP
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       store2,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -2993,154 +3987,7761 @@ func newOAuthEndSessionHandler(p *deps.RequestProvider) http.Handler {
 		ClaimStore:        storePQ,
 		RateLimiter:       limiter,
 	}
-	serviceNoEvent := &stdattrs.ServiceNoEvent{
-		UserProfileConfig: userProfileConfig,
-		Identities:        serviceService,
-		UserQueries:       rawQueries,
-		UserStore:         userStore,
-		ClaimStore:        storePQ,
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         store,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   store,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              store,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	idTokenIssuer := &oidc.IDTokenIssuer{
+		Secrets: oAuthKeyMaterials,
+		BaseURL: endpointsProvider,
+		Users:   queries,
+		Clock:   clockClock,
+	}
+	jwksHandler := &oauth.JWKSHandler{
+		Logger: jwksHandlerLogger,
+		JWKS:   idTokenIssuer,
+	}
+	return jwksHandler
+}
+
+func newOAuthUserInfoHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	userInfoHandlerLogger := oauth.NewUserInfoHandlerLogger(factory)
+	handle := appProvider.AppDatabase
+	config := appProvider.Config
+	secretConfig := config.SecretConfig
+	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
+	request := p.Request
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	store := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	rawQueries := &user.RawQueries{
+		Store: store,
+	}
+	authenticationConfig := appConfig.Authentication
+	identityConfig := appConfig.Identity
+	featureConfig := config.FeatureConfig
+	identityFeatureConfig := featureConfig.Identity
+	serviceStore := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 serviceStore,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	store2 := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	logger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, logger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          logger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	appredisHandle := appProvider.Redis
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	httpConfig := appConfig.HTTP
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       store2,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         store,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   store,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              store,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	idTokenIssuer := &oidc.IDTokenIssuer{
+		Secrets: oAuthKeyMaterials,
+		BaseURL: endpointsProvider,
+		Users:   queries,
+		Clock:   clockClock,
+	}
+	userInfoHandler := &oauth.UserInfoHandler{
+		Logger:           userInfoHandlerLogger,
+		Database:         handle,
+		UserInfoProvider: idTokenIssuer,
+	}
+	return userInfoHandler
+}
+
+func newOAuthEndSessionHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	endSessionHandlerLogger := oauth.NewEndSessionHandlerLogger(factory)
+	handle := appProvider.AppDatabase
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	oAuthConfig := appConfig.OAuth
+	request := p.Request
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	appredisHandle := appProvider.Redis
+	appID := appConfig.ID
+	clockClock := _wireSystemClockValue
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	storeRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	cookieDef := session.NewSessionCookieDef(sessionConfig)
+	manager := &idpsession.Manager{
+		Store:     storeRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef,
+	}
+	contextContext := deps.ProvideRequestContext(request)
+	logger := redis.NewLogger(factory)
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	store := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      logger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	sessionManager := &oauth2.SessionManager{
+		Store:  store,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	eventLogger := event.NewLogger(factory)
+	localizationConfig := appConfig.Localization
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	authenticationConfig := appConfig.Authentication
+	identityConfig := appConfig.Identity
+	featureConfig := config.FeatureConfig
+	identityFeatureConfig := featureConfig.Identity
+	serviceStore := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	resourceManager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: resourceManager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 serviceStore,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	store2 := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, resourceManager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oobStoreRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: oobStoreRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       store2,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	queue := appProvider.TaskQueue
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          resourceManager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	manager2 := &session.Manager{
+		IDPSessions:         manager,
+		AccessTokenSessions: sessionManager,
+		Events:              eventService,
+	}
+	endSessionHandler := &handler2.EndSessionHandler{
+		Config:           oAuthConfig,
+		Endpoints:        endpointsProvider,
+		URLs:             urlProvider,
+		SessionManager:   manager2,
+		SessionCookieDef: cookieDef,
+		Cookies:          cookieManager,
+	}
+	oauthEndSessionHandler := &oauth.EndSessionHandler{
+		Logger:            endSessionHandlerLogger,
+		Database:          handle,
+		EndSessionHandler: endSessionHandler,
+	}
+	return oauthEndSessionHandler
+}
+
+func newOAuthChallengeHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	handle := appProvider.AppDatabase
+	appredisHandle := appProvider.Redis
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	clockClock := _wireSystemClockValue
+	provider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	factory := appProvider.LoggerFactory
+	jsonResponseWriterLogger := httputil.NewJSONResponseWriterLogger(factory)
+	jsonResponseWriter := &httputil.JSONResponseWriter{
+		Logger: jsonResponseWriterLogger,
+	}
+	challengeHandler := &oauth.ChallengeHandler{
+		Database:   handle,
+		Challenges: provider,
+		JSON:       jsonResponseWriter,
+	}
+	return challengeHandler
+}
+
+func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	handle := appProvider.AppDatabase
+	factory := appProvider.LoggerFactory
+	jsonResponseWriterLogger := httputil.NewJSONResponseWriterLogger(factory)
+	jsonResponseWriter := &httputil.JSONResponseWriter{
+		Logger: jsonResponseWriterLogger,
+	}
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	oAuthConfig := appConfig.OAuth
+	secretConfig := config.SecretConfig
+	oAuthClientSecrets := deps.ProvideOAuthClientSecrets(secretConfig)
+	tokenHandlerLogger := handler.NewTokenHandlerLogger(factory)
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	request := p.Request
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	appredisHandle := appProvider.Redis
+	logger := redis.NewLogger(factory)
+	clockClock := _wireSystemClockValue
+	store := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      logger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	interactionLogger := interaction.NewLogger(factory)
+	featureConfig := config.FeatureConfig
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	authenticationConfig := appConfig.Authentication
+	identityConfig := appConfig.Identity
+	identityFeatureConfig := featureConfig.Identity
+	serviceStore := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 serviceStore,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	store2 := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	httpConfig := appConfig.HTTP
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       store2,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
+	}
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	idpsessionStoreRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	cookieDef := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef,
+	}
+	sessionManager := &oauth2.SessionManager{
+		Store:  store,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
+	}
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
+	}
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
+	}
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           urlProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
+	}
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
+	}
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	mfaCookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef,
+		MFADeviceTokenCookie:      mfaCookieDef,
+	}
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	interactionService := &interaction.Service{
+		Logger:  interactionLogger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
+	}
+	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
+	idTokenIssuer := &oidc.IDTokenIssuer{
+		Secrets: oAuthKeyMaterials,
+		BaseURL: endpointsProvider,
+		Users:   queries,
+		Clock:   clockClock,
+	}
+	accessTokenEncoding := &oauth2.AccessTokenEncoding{
+		Secrets:    oAuthKeyMaterials,
+		Clock:      clockClock,
+		UserClaims: idTokenIssuer,
+		BaseURL:    endpointsProvider,
+	}
+	tokenGenerator := _wireTokenGeneratorValue
+	tokenService := handler.TokenService{
+		Request:           request,
+		AppID:             appID,
+		Config:            oAuthConfig,
+		TrustProxy:        trustProxy,
+		Authorizations:    authorizationStore,
+		OfflineGrants:     store,
+		AccessGrants:      store,
+		AccessEvents:      eventProvider,
+		AccessTokenIssuer: accessTokenEncoding,
+		GenerateToken:     tokenGenerator,
+		Clock:             clockClock,
+		Users:             queries,
+		Events:            eventService,
+	}
+	customClaimsLogger := handler.NewCustomClaimsLogger(factory)
+	customClaimsService := &handler.CustomClaimsService{
+		Logger: customClaimsLogger,
+		Events: eventService,
+	}
+	tokenHandler := &handler.TokenHandler{
+		AppID:            appID,
+		Config:           oAuthConfig,
+		ClientSecrets:    oAuthClientSecrets,
+		Logger:           tokenHandlerLogger,
+		Authorizations:   authorizationStore,
+		CodeGrants:       store,
+		DeviceCodeGrants: store,
+		OfflineGrants:    store,
+		AppSessionTokens: store,
+		Graphs:           interactionService,
+		IDTokenIssuer:    idTokenIssuer,
+		Clock:            clockClock,
+		TokenService:     tokenService,
+		RateLimiter:      limiter,
+		Events:           eventService,
+		Hooks:            deliverer,
+		CustomClaims:     customClaimsService,
+	}
+	appSessionTokenHandler := &oauth.AppSessionTokenHandler{
+		Database:         handle,
+		JSON:             jsonResponseWriter,
+		AppSessionTokens: tokenHandler,
+	}
+	return appSessionTokenHandler
+}
+
+func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	anonymousUserSignupAPIHandlerLogger := api.NewAnonymousUserSignupAPIHandlerLogger(factory)
+	handle := appProvider.AppDatabase
+	jsonResponseWriterLogger := httputil.NewJSONResponseWriterLogger(factory)
+	jsonResponseWriter := &httputil.JSONResponseWriter{
+		Logger: jsonResponseWriterLogger,
+	}
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	oAuthConfig := appConfig.OAuth
+	anonymousUserHandlerLogger := handler.NewAnonymousUserHandlerLogger(factory)
+	logger := interaction.NewLogger(factory)
+	request := p.Request
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	featureConfig := config.FeatureConfig
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	authenticationConfig := appConfig.Authentication
+	identityConfig := appConfig.Identity
+	identityFeatureConfig := featureConfig.Identity
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	serviceStore := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	appredisHandle := appProvider.Redis
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	httpConfig := appConfig.HTTP
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       serviceStore,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
+	}
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	idpsessionStoreRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	cookieDef := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef,
+	}
+	redisLogger := redis.NewLogger(factory)
+	redisStore := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      redisLogger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	sessionManager := &oauth2.SessionManager{
+		Store:  redisStore,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
+	}
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
+	}
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
+	}
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           urlProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
+	}
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
+	}
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	mfaCookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef,
+		MFADeviceTokenCookie:      mfaCookieDef,
+	}
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	interactionService := &interaction.Service{
+		Logger:  logger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
+	}
+	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
+	idTokenIssuer := &oidc.IDTokenIssuer{
+		Secrets: oAuthKeyMaterials,
+		BaseURL: endpointsProvider,
+		Users:   queries,
+		Clock:   clockClock,
+	}
+	accessTokenEncoding := &oauth2.AccessTokenEncoding{
+		Secrets:    oAuthKeyMaterials,
+		Clock:      clockClock,
+		UserClaims: idTokenIssuer,
+		BaseURL:    endpointsProvider,
+	}
+	tokenGenerator := _wireTokenGeneratorValue
+	tokenService := handler.TokenService{
+		Request:           request,
+		AppID:             appID,
+		Config:            oAuthConfig,
+		TrustProxy:        trustProxy,
+		Authorizations:    authorizationStore,
+		OfflineGrants:     redisStore,
+		AccessGrants:      redisStore,
+		AccessEvents:      eventProvider,
+		AccessTokenIssuer: accessTokenEncoding,
+		GenerateToken:     tokenGenerator,
+		Clock:             clockClock,
+		Users:             queries,
+		Events:            eventService,
+	}
+	anonymousStoreRedis := &anonymous.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+		Clock:   clockClock,
+	}
+	customClaimsLogger := handler.NewCustomClaimsLogger(factory)
+	customClaimsService := &handler.CustomClaimsService{
+		Logger: customClaimsLogger,
+		Events: eventService,
+	}
+	anonymousUserHandler := &handler.AnonymousUserHandler{
+		AppID:               appID,
+		OAuthConfig:         oAuthConfig,
+		Logger:              anonymousUserHandlerLogger,
+		Graphs:              interactionService,
+		Authorizations:      authorizationStore,
+		Clock:               clockClock,
+		TokenService:        tokenService,
+		UserProvider:        queries,
+		AnonymousIdentities: anonymousProvider,
+		PromotionCodes:      anonymousStoreRedis,
+		CustomClaims:        customClaimsService,
+	}
+	anonymousUserSignupAPIHandler := &api.AnonymousUserSignupAPIHandler{
+		Logger:               anonymousUserSignupAPIHandlerLogger,
+		Database:             handle,
+		JSON:                 jsonResponseWriter,
+		AnonymousUserHandler: anonymousUserHandler,
+	}
+	return anonymousUserSignupAPIHandler
+}
+
+func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	anonymousUserPromotionCodeAPIHandlerLogger := api.NewAnonymousUserPromotionCodeAPILogger(factory)
+	handle := appProvider.AppDatabase
+	jsonResponseWriterLogger := httputil.NewJSONResponseWriterLogger(factory)
+	jsonResponseWriter := &httputil.JSONResponseWriter{
+		Logger: jsonResponseWriterLogger,
+	}
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	oAuthConfig := appConfig.OAuth
+	anonymousUserHandlerLogger := handler.NewAnonymousUserHandlerLogger(factory)
+	logger := interaction.NewLogger(factory)
+	request := p.Request
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	featureConfig := config.FeatureConfig
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	authenticationConfig := appConfig.Authentication
+	identityConfig := appConfig.Identity
+	identityFeatureConfig := featureConfig.Identity
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	serviceStore := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	appredisHandle := appProvider.Redis
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	httpConfig := appConfig.HTTP
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       serviceStore,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
+	}
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	idpsessionStoreRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	cookieDef := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef,
+	}
+	redisLogger := redis.NewLogger(factory)
+	redisStore := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      redisLogger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	sessionManager := &oauth2.SessionManager{
+		Store:  redisStore,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
+	}
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
+	}
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
+	}
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           urlProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
+	}
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
+	}
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	mfaCookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef,
+		MFADeviceTokenCookie:      mfaCookieDef,
+	}
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	interactionService := &interaction.Service{
+		Logger:  logger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
+	}
+	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
+	idTokenIssuer := &oidc.IDTokenIssuer{
+		Secrets: oAuthKeyMaterials,
+		BaseURL: endpointsProvider,
+		Users:   queries,
+		Clock:   clockClock,
+	}
+	accessTokenEncoding := &oauth2.AccessTokenEncoding{
+		Secrets:    oAuthKeyMaterials,
+		Clock:      clockClock,
+		UserClaims: idTokenIssuer,
+		BaseURL:    endpointsProvider,
+	}
+	tokenGenerator := _wireTokenGeneratorValue
+	tokenService := handler.TokenService{
+		Request:           request,
+		AppID:             appID,
+		Config:            oAuthConfig,
+		TrustProxy:        trustProxy,
+		Authorizations:    authorizationStore,
+		OfflineGrants:     redisStore,
+		AccessGrants:      redisStore,
+		AccessEvents:      eventProvider,
+		AccessTokenIssuer: accessTokenEncoding,
+		GenerateToken:     tokenGenerator,
+		Clock:             clockClock,
+		Users:             queries,
+		Events:            eventService,
+	}
+	anonymousStoreRedis := &anonymous.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+		Clock:   clockClock,
+	}
+	customClaimsLogger := handler.NewCustomClaimsLogger(factory)
+	customClaimsService := &handler.CustomClaimsService{
+		Logger: customClaimsLogger,
+		Events: eventService,
+	}
+	anonymousUserHandler := &handler.AnonymousUserHandler{
+		AppID:               appID,
+		OAuthConfig:         oAuthConfig,
+		Logger:              anonymousUserHandlerLogger,
+		Graphs:              interactionService,
+		Authorizations:      authorizationStore,
+		Clock:               clockClock,
+		TokenService:        tokenService,
+		UserProvider:        queries,
+		AnonymousIdentities: anonymousProvider,
+		PromotionCodes:      anonymousStoreRedis,
+		CustomClaims:        customClaimsService,
+	}
+	anonymousUserPromotionCodeAPIHandler := &api.AnonymousUserPromotionCodeAPIHandler{
+		Logger:         anonymousUserPromotionCodeAPIHandlerLogger,
+		Database:       handle,
+		JSON:           jsonResponseWriter,
+		PromotionCodes: anonymousUserHandler,
+	}
+	return anonymousUserPromotionCodeAPIHandler
+}
+
+func newWebAppOAuthEntrypointHandler(p *deps.RequestProvider) http.Handler {
+	oAuthEntrypointHandler := &webapp2.OAuthEntrypointHandler{}
+	return oAuthEntrypointHandler
+}
+
+func newWebAppRootHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	authenticationConfig := appConfig.Authentication
+	request := p.Request
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	rootHandler := &webapp2.RootHandler{
+		AuthenticationConfig: authenticationConfig,
+		Cookies:              cookieManager,
+		SignedUpCookie:       signedUpCookieDef,
+	}
+	return rootHandler
+}
+
+func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	handle := appProvider.AppDatabase
+	appredisHandle := appProvider.Redis
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	serviceLogger := webapp.NewServiceLogger(factory)
+	request := p.Request
+	sessionStoreRedis := &webapp.SessionStoreRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	authenticationConfig := appConfig.Authentication
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
+	}
+	logger := interaction.NewLogger(factory)
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	featureConfig := config.FeatureConfig
+	identityConfig := appConfig.Identity
+	identityFeatureConfig := featureConfig.Identity
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	serviceStore := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       serviceStore,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
+	}
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	idpsessionStoreRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef2,
+	}
+	redisLogger := redis.NewLogger(factory)
+	redisStore := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      redisLogger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	oAuthConfig := appConfig.OAuth
+	sessionManager := &oauth2.SessionManager{
+		Store:  redisStore,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
+	}
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
+	}
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
+	}
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           urlProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
+	}
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
+	}
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
+	}
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	interactionService := &interaction.Service{
+		Logger:  logger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
+	}
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
+	}
+	uiConfig := appConfig.UI
+	uiFeatureConfig := featureConfig.UI
+	flashMessage := &httputil.FlashMessage{
+		Cookies: cookieManager,
+	}
+	baseViewModeler := &viewmodels.BaseViewModeler{
+		TrustProxy:            trustProxy,
+		OAuth:                 oAuthConfig,
+		AuthUI:                uiConfig,
+		AuthUIFeatureConfig:   uiFeatureConfig,
+		StaticAssets:          staticAssetResolver,
+		ForgotPassword:        forgotPasswordConfig,
+		Authentication:        authenticationConfig,
+		ErrorCookie:           errorCookie,
+		Translations:          translationService,
+		Clock:                 clockClock,
+		FlashMessage:          flashMessage,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	responseRendererLogger := webapp2.NewResponseRendererLogger(factory)
+	responseRenderer := &webapp2.ResponseRenderer{
+		TemplateEngine: engine,
+		Logger:         responseRendererLogger,
+	}
+	publisher := webapp2.NewPublisher(appID, appredisHandle)
+	controllerDeps := webapp2.ControllerDeps{
+		Database:      handle,
+		RedisHandle:   appredisHandle,
+		AppID:         appID,
+		Page:          webappService2,
+		BaseViewModel: baseViewModeler,
+		Renderer:      responseRenderer,
+		Publisher:     publisher,
+		Clock:         clockClock,
+		UIConfig:      uiConfig,
+		ErrorCookie:   errorCookie,
+		TrustProxy:    trustProxy,
+	}
+	controllerFactory := webapp2.ControllerFactory{
+		LoggerFactory:  factory,
+		ControllerDeps: controllerDeps,
+	}
+	formPrefiller := &webapp2.FormPrefiller{
+		LoginID: loginIDConfig,
+		UI:      uiConfig,
+	}
+	analyticredisHandle := appProvider.AnalyticRedis
+	analyticStoreRedisLogger := analytic.NewStoreRedisLogger(factory)
+	writeStoreRedis := &analytic.WriteStoreRedis{
+		Context: contextContext,
+		Redis:   analyticredisHandle,
+		AppID:   appID,
+		Clock:   clockClock,
+		Logger:  analyticStoreRedisLogger,
+	}
+	analyticService := &analytic.Service{
+		Counter: writeStoreRedis,
+	}
+	loginHandler := &webapp2.LoginHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		FormPrefiller:     formPrefiller,
+		Renderer:          responseRenderer,
+		AnalyticService:   analyticService,
+	}
+	return loginHandler
+}
+
+func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	handle := appProvider.AppDatabase
+	appredisHandle := appProvider.Redis
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	serviceLogger := webapp.NewServiceLogger(factory)
+	request := p.Request
+	sessionStoreRedis := &webapp.SessionStoreRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	authenticationConfig := appConfig.Authentication
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
+	}
+	logger := interaction.NewLogger(factory)
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	featureConfig := config.FeatureConfig
+	identityConfig := appConfig.Identity
+	identityFeatureConfig := featureConfig.Identity
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	serviceStore := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       serviceStore,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
+	}
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	idpsessionStoreRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef2,
+	}
+	redisLogger := redis.NewLogger(factory)
+	redisStore := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      redisLogger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	oAuthConfig := appConfig.OAuth
+	sessionManager := &oauth2.SessionManager{
+		Store:  redisStore,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
+	}
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
+	}
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
+	}
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           urlProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
+	}
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
+	}
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
+	}
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	interactionService := &interaction.Service{
+		Logger:  logger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
+	}
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
+	}
+	uiConfig := appConfig.UI
+	uiFeatureConfig := featureConfig.UI
+	flashMessage := &httputil.FlashMessage{
+		Cookies: cookieManager,
+	}
+	baseViewModeler := &viewmodels.BaseViewModeler{
+		TrustProxy:            trustProxy,
+		OAuth:                 oAuthConfig,
+		AuthUI:                uiConfig,
+		AuthUIFeatureConfig:   uiFeatureConfig,
+		StaticAssets:          staticAssetResolver,
+		ForgotPassword:        forgotPasswordConfig,
+		Authentication:        authenticationConfig,
+		ErrorCookie:           errorCookie,
+		Translations:          translationService,
+		Clock:                 clockClock,
+		FlashMessage:          flashMessage,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	responseRendererLogger := webapp2.NewResponseRendererLogger(factory)
+	responseRenderer := &webapp2.ResponseRenderer{
+		TemplateEngine: engine,
+		Logger:         responseRendererLogger,
+	}
+	publisher := webapp2.NewPublisher(appID, appredisHandle)
+	controllerDeps := webapp2.ControllerDeps{
+		Database:      handle,
+		RedisHandle:   appredisHandle,
+		AppID:         appID,
+		Page:          webappService2,
+		BaseViewModel: baseViewModeler,
+		Renderer:      responseRenderer,
+		Publisher:     publisher,
+		Clock:         clockClock,
+		UIConfig:      uiConfig,
+		ErrorCookie:   errorCookie,
+		TrustProxy:    trustProxy,
+	}
+	controllerFactory := webapp2.ControllerFactory{
+		LoggerFactory:  factory,
+		ControllerDeps: controllerDeps,
+	}
+	formPrefiller := &webapp2.FormPrefiller{
+		LoginID: loginIDConfig,
+		UI:      uiConfig,
+	}
+	analyticredisHandle := appProvider.AnalyticRedis
+	analyticStoreRedisLogger := analytic.NewStoreRedisLogger(factory)
+	writeStoreRedis := &analytic.WriteStoreRedis{
+		Context: contextContext,
+		Redis:   analyticredisHandle,
+		AppID:   appID,
+		Clock:   clockClock,
+		Logger:  analyticStoreRedisLogger,
+	}
+	analyticService := &analytic.Service{
+		Counter: writeStoreRedis,
+	}
+	signupHandler := &webapp2.SignupHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		FormPrefiller:     formPrefiller,
+		Renderer:          responseRenderer,
+		AnalyticService:   analyticService,
+	}
+	return signupHandler
+}
+
+func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	handle := appProvider.AppDatabase
+	appredisHandle := appProvider.Redis
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	serviceLogger := webapp.NewServiceLogger(factory)
+	request := p.Request
+	sessionStoreRedis := &webapp.SessionStoreRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	authenticationConfig := appConfig.Authentication
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
+	}
+	logger := interaction.NewLogger(factory)
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	featureConfig := config.FeatureConfig
+	identityConfig := appConfig.Identity
+	identityFeatureConfig := featureConfig.Identity
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	serviceStore := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       serviceStore,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
+	}
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	idpsessionStoreRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef2,
+	}
+	redisLogger := redis.NewLogger(factory)
+	redisStore := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      redisLogger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	oAuthConfig := appConfig.OAuth
+	sessionManager := &oauth2.SessionManager{
+		Store:  redisStore,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
+	}
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
+	}
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
+	}
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           urlProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
+	}
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
+	}
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
+	}
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	interactionService := &interaction.Service{
+		Logger:  logger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
+	}
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
+	}
+	uiConfig := appConfig.UI
+	uiFeatureConfig := featureConfig.UI
+	flashMessage := &httputil.FlashMessage{
+		Cookies: cookieManager,
+	}
+	baseViewModeler := &viewmodels.BaseViewModeler{
+		TrustProxy:            trustProxy,
+		OAuth:                 oAuthConfig,
+		AuthUI:                uiConfig,
+		AuthUIFeatureConfig:   uiFeatureConfig,
+		StaticAssets:          staticAssetResolver,
+		ForgotPassword:        forgotPasswordConfig,
+		Authentication:        authenticationConfig,
+		ErrorCookie:           errorCookie,
+		Translations:          translationService,
+		Clock:                 clockClock,
+		FlashMessage:          flashMessage,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	responseRendererLogger := webapp2.NewResponseRendererLogger(factory)
+	responseRenderer := &webapp2.ResponseRenderer{
+		TemplateEngine: engine,
+		Logger:         responseRendererLogger,
+	}
+	publisher := webapp2.NewPublisher(appID, appredisHandle)
+	controllerDeps := webapp2.ControllerDeps{
+		Database:      handle,
+		RedisHandle:   appredisHandle,
+		AppID:         appID,
+		Page:          webappService2,
+		BaseViewModel: baseViewModeler,
+		Renderer:      responseRenderer,
+		Publisher:     publisher,
+		Clock:         clockClock,
+		UIConfig:      uiConfig,
+		ErrorCookie:   errorCookie,
+		TrustProxy:    trustProxy,
+	}
+	controllerFactory := webapp2.ControllerFactory{
+		LoggerFactory:  factory,
+		ControllerDeps: controllerDeps,
+	}
+	formPrefiller := &webapp2.FormPrefiller{
+		LoginID: loginIDConfig,
+		UI:      uiConfig,
+	}
+	promoteHandler := &webapp2.PromoteHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		FormPrefiller:     formPrefiller,
+		Renderer:          responseRenderer,
+	}
+	return promoteHandler
+}
+
+func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	handle := appProvider.AppDatabase
+	appredisHandle := appProvider.Redis
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	serviceLogger := webapp.NewServiceLogger(factory)
+	request := p.Request
+	sessionStoreRedis := &webapp.SessionStoreRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	authenticationConfig := appConfig.Authentication
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
+	}
+	logger := interaction.NewLogger(factory)
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	featureConfig := config.FeatureConfig
+	identityConfig := appConfig.Identity
+	identityFeatureConfig := featureConfig.Identity
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	serviceStore := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       serviceStore,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
+	}
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	idpsessionStoreRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef2,
+	}
+	redisLogger := redis.NewLogger(factory)
+	redisStore := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      redisLogger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	oAuthConfig := appConfig.OAuth
+	sessionManager := &oauth2.SessionManager{
+		Store:  redisStore,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
+	}
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
+	}
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
+	}
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           urlProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
+	}
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
+	}
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
+	}
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	interactionService := &interaction.Service{
+		Logger:  logger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
+	}
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
+	}
+	uiConfig := appConfig.UI
+	uiFeatureConfig := featureConfig.UI
+	flashMessage := &httputil.FlashMessage{
+		Cookies: cookieManager,
+	}
+	baseViewModeler := &viewmodels.BaseViewModeler{
+		TrustProxy:            trustProxy,
+		OAuth:                 oAuthConfig,
+		AuthUI:                uiConfig,
+		AuthUIFeatureConfig:   uiFeatureConfig,
+		StaticAssets:          staticAssetResolver,
+		ForgotPassword:        forgotPasswordConfig,
+		Authentication:        authenticationConfig,
+		ErrorCookie:           errorCookie,
+		Translations:          translationService,
+		Clock:                 clockClock,
+		FlashMessage:          flashMessage,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	responseRendererLogger := webapp2.NewResponseRendererLogger(factory)
+	responseRenderer := &webapp2.ResponseRenderer{
+		TemplateEngine: engine,
+		Logger:         responseRendererLogger,
+	}
+	publisher := webapp2.NewPublisher(appID, appredisHandle)
+	controllerDeps := webapp2.ControllerDeps{
+		Database:      handle,
+		RedisHandle:   appredisHandle,
+		AppID:         appID,
+		Page:          webappService2,
+		BaseViewModel: baseViewModeler,
+		Renderer:      responseRenderer,
+		Publisher:     publisher,
+		Clock:         clockClock,
+		UIConfig:      uiConfig,
+		ErrorCookie:   errorCookie,
+		TrustProxy:    trustProxy,
+	}
+	controllerFactory := webapp2.ControllerFactory{
+		LoggerFactory:  factory,
+		ControllerDeps: controllerDeps,
+	}
+	selectAccountHandler := &webapp2.SelectAccountHandler{
+		ControllerFactory:         controllerFactory,
+		BaseViewModel:             baseViewModeler,
+		Renderer:                  responseRenderer,
+		AuthenticationConfig:      authenticationConfig,
+		SignedUpCookie:            signedUpCookieDef,
+		Users:                     queries,
+		Identities:                serviceService,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Cookies:                   cookieManager,
+	}
+	return selectAccountHandler
+}
+
+func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	handle := appProvider.AppDatabase
+	appredisHandle := appProvider.Redis
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	serviceLogger := webapp.NewServiceLogger(factory)
+	request := p.Request
+	sessionStoreRedis := &webapp.SessionStoreRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	authenticationConfig := appConfig.Authentication
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
+	}
+	logger := interaction.NewLogger(factory)
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	featureConfig := config.FeatureConfig
+	identityConfig := appConfig.Identity
+	identityFeatureConfig := featureConfig.Identity
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	serviceStore := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       serviceStore,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
+	}
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	idpsessionStoreRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef2,
+	}
+	redisLogger := redis.NewLogger(factory)
+	redisStore := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      redisLogger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	oAuthConfig := appConfig.OAuth
+	sessionManager := &oauth2.SessionManager{
+		Store:  redisStore,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
+	}
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
+	}
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
+	}
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           urlProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
+	}
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
+	}
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
+	}
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	interactionService := &interaction.Service{
+		Logger:  logger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
+	}
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
+	}
+	uiConfig := appConfig.UI
+	uiFeatureConfig := featureConfig.UI
+	flashMessage := &httputil.FlashMessage{
+		Cookies: cookieManager,
+	}
+	baseViewModeler := &viewmodels.BaseViewModeler{
+		TrustProxy:            trustProxy,
+		OAuth:                 oAuthConfig,
+		AuthUI:                uiConfig,
+		AuthUIFeatureConfig:   uiFeatureConfig,
+		StaticAssets:          staticAssetResolver,
+		ForgotPassword:        forgotPasswordConfig,
+		Authentication:        authenticationConfig,
+		ErrorCookie:           errorCookie,
+		Translations:          translationService,
+		Clock:                 clockClock,
+		FlashMessage:          flashMessage,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	responseRendererLogger := webapp2.NewResponseRendererLogger(factory)
+	responseRenderer := &webapp2.ResponseRenderer{
+		TemplateEngine: engine,
+		Logger:         responseRendererLogger,
+	}
+	publisher := webapp2.NewPublisher(appID, appredisHandle)
+	controllerDeps := webapp2.ControllerDeps{
+		Database:      handle,
+		RedisHandle:   appredisHandle,
+		AppID:         appID,
+		Page:          webappService2,
+		BaseViewModel: baseViewModeler,
+		Renderer:      responseRenderer,
+		Publisher:     publisher,
+		Clock:         clockClock,
+		UIConfig:      uiConfig,
+		ErrorCookie:   errorCookie,
+		TrustProxy:    trustProxy,
+	}
+	controllerFactory := webapp2.ControllerFactory{
+		LoggerFactory:  factory,
+		ControllerDeps: controllerDeps,
+	}
+	ssoCallbackHandler := &webapp2.SSOCallbackHandler{
+		ControllerFactory: controllerFactory,
+	}
+	return ssoCallbackHandler
+}
+
+func newWebAppSAMLACSHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	handle := appProvider.AppDatabase
+	appredisHandle := appProvider.Redis
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	serviceLogger := webapp.NewServiceLogger(factory)
+	request := p.Request
+	sessionStoreRedis := &webapp.SessionStoreRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	authenticationConfig := appConfig.Authentication
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
+	}
+	logger := interaction.NewLogger(factory)
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	featureConfig := config.FeatureConfig
+	identityConfig := appConfig.Identity
+	identityFeatureConfig := featureConfig.Identity
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	serviceStore := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       serviceStore,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
+	}
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	idpsessionStoreRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef2,
+	}
+	redisLogger := redis.NewLogger(factory)
+	redisStore := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      redisLogger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	oAuthConfig := appConfig.OAuth
+	sessionManager := &oauth2.SessionManager{
+		Store:  redisStore,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
+	}
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
+	}
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
+	}
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           urlProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
+	}
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
+	}
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
+	}
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
+	}
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+	}
+	interactionService := &interaction.Service{
+		Logger:  logger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
+	}
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
+	}
+	uiConfig := appConfig.UI
+	uiFeatureConfig := featureConfig.UI
+	flashMessage := &httputil.FlashMessage{
+		Cookies: cookieManager,
+	}
+	baseViewModeler := &viewmodels.BaseViewModeler{
+		TrustProxy:            trustProxy,
+		OAuth:                 oAuthConfig,
+		AuthUI:                uiConfig,
+		AuthUIFeatureConfig:   uiFeatureConfig,
+		StaticAssets:          staticAssetResolver,
+		ForgotPassword:        forgotPasswordConfig,
+		Authentication:        authenticationConfig,
+		ErrorCookie:           errorCookie,
+		Translations:          translationService,
+		Clock:                 clockClock,
+		FlashMessage:          flashMessage,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	responseRendererLogger := webapp2.NewResponseRendererLogger(factory)
+	responseRenderer := &webapp2.ResponseRenderer{
+		TemplateEngine: engine,
+		Logger:         responseRendererLogger,
+	}
+	publisher := webapp2.NewPublisher(appID, appredisHandle)
+	controllerDeps := webapp2.ControllerDeps{
+		Database:      handle,
+		RedisHandle:   appredisHandle,
+		AppID:         appID,
+		Page:          webappService2,
+		BaseViewModel: baseViewModeler,
+		Renderer:      responseRenderer,
+		Publisher:     publisher,
+		Clock:         clockClock,
+		UIConfig:      uiConfig,
+		ErrorCookie:   errorCookie,
+		TrustProxy:    trustProxy,
+	}
+	controllerFactory := webapp2.ControllerFactory{
+		LoggerFactory:  factory,
+		ControllerDeps: controllerDeps,
+	}
+	samlacsHandler := &webapp2.SAMLACSHandler{
+		ControllerFactory: controllerFactory,
+	}
+	return samlacsHandler
+}
+
+func newWebAppSAMLMetadataHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	samlMetadataHandlerLogger := webapp2.NewSAMLMetadataHandlerLogger(factory)
+	request := p.Request
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	identityConfig := appConfig.Identity
+	secretConfig := config.SecretConfig
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	clockClock := _wireSystemClockValue
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	loginIDConfig := identityConfig.LoginID
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	samlMetadataHandler := &webapp2.SAMLMetadataHandler{
+		Logger:          samlMetadataHandlerLogger,
+		ProviderFactory: oAuthProviderFactory,
+	}
+	return samlMetadataHandler
+}
+
+func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	handle := appProvider.AppDatabase
+	appredisHandle := appProvider.Redis
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	serviceLogger := webapp.NewServiceLogger(factory)
+	request := p.Request
+	sessionStoreRedis := &webapp.SessionStoreRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	authenticationConfig := appConfig.Authentication
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
+	}
+	logger := interaction.NewLogger(factory)
+	contextContext := deps.ProvideRequestContext(request)
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	clockClock := _wireSystemClockValue
+	featureConfig := config.FeatureConfig
+	identityConfig := appConfig.Identity
+	identityFeatureConfig := featureConfig.Identity
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth3.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth3.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	serviceStore := &service2.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	passwordLogger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          passwordLogger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	totpStore := &totp.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorTOTPConfig := authenticatorConfig.TOTP
+	totpProvider := &totp.Provider{
+		Store:  totpStore,
+		Config: authenticatorTOTPConfig,
+		Clock:  clockClock,
+	}
+	authenticatorOOBConfig := authenticatorConfig.OOB
+	oobStore := &oob.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedis := &oob.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	oobLogger := oob.NewLogger(factory)
+	oobProvider := &oob.Provider{
+		Config:    authenticatorOOBConfig,
+		Store:     oobStore,
+		CodeStore: storeRedis,
+		Clock:     clockClock,
+		Logger:    oobLogger,
+	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
+	ratelimitLogger := ratelimit.NewLogger(factory)
+	storageRedis := &ratelimit.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
+	limiter := &ratelimit.Limiter{
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
+		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
+	}
+	service3 := &service2.Service{
+		Store:       serviceStore,
+		Password:    passwordProvider,
+		TOTP:        totpProvider,
+		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
+		RateLimiter: limiter,
+		Lockout:     lockoutService,
+	}
+	verificationLogger := verification.NewLogger(factory)
+	verificationConfig := appConfig.Verification
+	userProfileConfig := appConfig.UserProfile
+	verificationStoreRedis := &verification.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	verificationService := &verification.Service{
+		Request:           request,
+		Logger:            verificationLogger,
+		Config:            verificationConfig,
+		UserProfileConfig: userProfileConfig,
+		TrustProxy:        trustProxy,
+		Clock:             clockClock,
+		CodeStore:         verificationStoreRedis,
+		ClaimStore:        storePQ,
+		RateLimiter:       limiter,
+	}
+	storeDeviceTokenRedis := &mfa.StoreDeviceTokenRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	storeRecoveryCodePQ := &mfa.StoreRecoveryCodePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	mfaService := &mfa.Service{
+		DeviceTokens:  storeDeviceTokenRedis,
+		RecoveryCodes: storeRecoveryCodePQ,
+		Clock:         clockClock,
+		Config:        authenticationConfig,
+		RateLimiter:   limiter,
+		Lockout:       lockoutService,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	defaultLanguageTag := deps.ProvideDefaultLanguageTag(config)
+	supportedLanguageTags := deps.ProvideSupportedLanguageTags(config)
+	resolver := &template.Resolver{
+		Resources:             manager,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
+	}
+	engine := &template.Engine{
+		Resolver: resolver,
+	}
+	localizationConfig := appConfig.Localization
+	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
+	staticAssetResolver := &web.StaticAssetResolver{
+		Context:            contextContext,
+		Config:             httpConfig,
+		Localization:       localizationConfig,
+		StaticAssetsPrefix: staticAssetURLPrefix,
+		Resources:          manager,
+	}
+	translationService := &translation.Service{
+		Context:        contextContext,
+		TemplateEngine: engine,
+		StaticAssets:   staticAssetResolver,
+	}
+	welcomeMessageConfig := appConfig.WelcomeMessage
+	queue := appProvider.TaskQueue
+	eventLogger := event.NewLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	queries := &user.Queries{
+		RawQueries:         rawQueries,
+		Store:              userStore,
+		Identities:         serviceService,
+		Authenticators:     service3,
+		Verification:       verificationService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	resolverImpl := &event.ResolverImpl{
+		Users: queries,
+	}
+	hookLogger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    hookLogger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
+	welcomemessageProvider := &welcomemessage.Provider{
+		Translation:          translationService,
+		RateLimiter:          limiter,
+		WelcomeMessageConfig: welcomeMessageConfig,
+		TaskQueue:            queue,
+		Events:               eventService,
+	}
+	rawCommands := &user.RawCommands{
+		Store:                  userStore,
+		Clock:                  clockClock,
+		WelcomeMessageProvider: welcomemessageProvider,
+	}
+	commands := &user.Commands{
+		RawCommands:        rawCommands,
+		RawQueries:         rawQueries,
+		Events:             eventService,
+		Verification:       verificationService,
+		UserProfileConfig:  userProfileConfig,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	stdattrsService := &stdattrs.Service{
+		UserProfileConfig: userProfileConfig,
+		ServiceNoEvent:    serviceNoEvent,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		Events:            eventService,
+	}
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
+	idpsessionStoreRedis := &idpsession.StoreRedis{
+		Redis:  appredisHandle,
+		AppID:  appID,
+		Clock:  clockClock,
+		Logger: storeRedisLogger,
+	}
+	sessionConfig := appConfig.Session
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
+	idpsessionManager := &idpsession.Manager{
+		Store:     idpsessionStoreRedis,
+		Clock:     clockClock,
+		Config:    sessionConfig,
+		Cookies:   cookieManager,
+		CookieDef: cookieDef2,
+	}
+	redisLogger := redis.NewLogger(factory)
+	redisStore := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      redisLogger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	oAuthConfig := appConfig.OAuth
+	sessionManager := &oauth2.SessionManager{
+		Store:  redisStore,
+		Clock:  clockClock,
+		Config: oAuthConfig,
+	}
+	coordinator := &facade.Coordinator{
+		Identities:      serviceService,
+		Authenticators:  service3,
+		Verification:    verificationService,
+		MFA:             mfaService,
+		UserCommands:    commands,
+		StdAttrsService: stdattrsService,
+		PasswordHistory: historyStore,
+		OAuth:           authorizationStore,
+		IDPSessions:     idpsessionManager,
+		OAuthSessions:   sessionManager,
+		IdentityConfig:  identityConfig,
+	}
+	identityFacade := facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	authenticatorFacade := facade.AuthenticatorFacade{
+		Coordinator: coordinator,
+	}
+	mainOriginProvider := &MainOriginProvider{
+		Request:    request,
+		TrustProxy: trustProxy,
+	}
+	endpointsProvider := &EndpointsProvider{
+		OriginProvider: mainOriginProvider,
+	}
+	messageSender := &otp.MessageSender{
+		Translation: translationService,
+		Endpoints:   endpointsProvider,
+		RateLimiter: limiter,
+		TaskQueue:   queue,
+		Events:      eventService,
+	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	codeSender := &oob.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
+	wechatURLProvider := &webapp.WechatURLProvider{
+		Endpoints: endpointsProvider,
+	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
+	normalizer := &stdattrs2.Normalizer{
+		LoginIDNormalizerFactory: normalizerFactory,
+	}
+	oAuthProviderFactory := &sso.OAuthProviderFactory{
+		Endpoints:                    endpointsProvider,
+		IdentityConfig:               identityConfig,
+		Credentials:                  oAuthClientCredentials,
+		RedirectURL:                  urlProvider,
+		Clock:                        clockClock,
+		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
+		StandardAttributesNormalizer: normalizer,
+	}
+	forgotPasswordConfig := appConfig.ForgotPassword
+	forgotpasswordStore := &forgotpassword.Store{
+		Context: contextContext,
+		AppID:   appID,
+		Redis:   appredisHandle,
+	}
+	providerLogger := forgotpassword.NewProviderLogger(factory)
+	forgotpasswordProvider := &forgotpassword.Provider{
+		Request:        request,
+		Translation:    translationService,
+		Config:         forgotPasswordConfig,
+		TrustProxy:     trustProxy,
+		Store:          forgotpasswordStore,
+		Clock:          clockClock,
+		URLs:           urlProvider,
+		TaskQueue:      queue,
+		Logger:         providerLogger,
+		Identities:     identityFacade,
+		Authenticators: authenticatorFacade,
+		RateLimiter:    limiter,
+		FeatureConfig:  featureConfig,
+		Events:         eventService,
+	}
+	verificationCodeSender := &verification.CodeSender{
+		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
+	}
+	responseWriter := p.ResponseWriter
+	nonceService := &nonce.Service{
+		Cookies:        cookieManager,
+		Request:        request,
+		ResponseWriter: responseWriter,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	challengeProvider := &challenge.Provider{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	userProvider := &user.Provider{
+		Commands: commands,
+		Queries:  queries,
+	}
+	authenticationinfoStoreRedis := &authenticationinfo.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+	}
+	eventStoreRedis := &access.EventStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
 	}
-	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
-		Config:      userProfileConfig,
-		UserQueries: rawQueries,
-		UserStore:   userStore,
+	eventProvider := &access.EventProvider{
+		Store: eventStoreRedis,
 	}
-	queries := &user.Queries{
-		RawQueries:         rawQueries,
-		Store:              userStore,
-		Identities:         serviceService,
-		Authenticators:     service3,
-		Verification:       verificationService,
-		StandardAttributes: serviceNoEvent,
-		CustomAttributes:   customattrsServiceNoEvent,
+	idpsessionRand := _wireRandValue
+	idpsessionProvider := &idpsession.Provider{
+		Context:      contextContext,
+		Request:      request,
+		AppID:        appID,
+		Redis:        appredisHandle,
+		Store:        idpsessionStoreRedis,
+		AccessEvents: eventProvider,
+		TrustProxy:   trustProxy,
+		Config:       sessionConfig,
+		Clock:        clockClock,
+		Random:       idpsessionRand,
 	}
-	resolverImpl := &event.ResolverImpl{
-		Users: queries,
+	interactionContext := &interaction.Context{
+		Request:                   request,
+		Database:                  sqlExecutor,
+		Clock:                     clockClock,
+		Config:                    appConfig,
+		FeatureConfig:             featureConfig,
+		TrustProxy:                trustProxy,
+		Identities:                identityFacade,
+		Authenticators:            authenticatorFacade,
+		AnonymousIdentities:       anonymousProvider,
+		BiometricIdentities:       biometricProvider,
+		OOBAuthenticators:         oobProvider,
+		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
+		OAuthProviderFactory:      oAuthProviderFactory,
+		MFA:                       mfaService,
+		ForgotPassword:            forgotpasswordProvider,
+		ResetPassword:             forgotpasswordProvider,
+		LoginIDNormalizerFactory:  normalizerFactory,
+		Verification:              verificationService,
+		VerificationCodeSender:    verificationCodeSender,
+		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
+		Nonces:                    nonceService,
+		Search:                    elasticsearchService,
+		Challenges:                challengeProvider,
+		Users:                     userProvider,
+		StdAttrsService:           stdattrsService,
+		Events:                    eventService,
+		CookieManager:             cookieManager,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Sessions:                  idpsessionProvider,
+		SessionManager:            idpsessionManager,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
 	}
-	hookLogger := hook.NewLogger(factory)
-	hookConfig := appConfig.Hook
-	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
-	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
-	asyncHTTPClient := hook.NewAsyncHTTPClient()
-	deliverer := &hook.Deliverer{
-		Config:             hookConfig,
-		Secret:             webhookKeyMaterials,
-		Clock:              clockClock,
-		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
-		StandardAttributes: serviceNoEvent,
-		CustomAttributes:   customattrsServiceNoEvent,
+	interactionStoreRedis := &interaction.StoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
 	}
-	sink := &hook.Sink{
-		Logger:    hookLogger,
-		Deliverer: deliverer,
+	interactionService := &interaction.Service{
+		Logger:  logger,
+		Context: interactionContext,
+		Store:   interactionStoreRedis,
 	}
-	auditLogger := audit.NewLogger(factory)
-	writeHandle := appProvider.AuditWriteDatabase
-	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
-	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
-	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
-	writeStore := &audit.WriteStore{
-		SQLBuilder:  auditdbSQLBuilderApp,
-		SQLExecutor: writeSQLExecutor,
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
 	}
-	auditSink := &audit.Sink{
-		Logger:   auditLogger,
-		Database: writeHandle,
-		Store:    writeStore,
+	uiConfig := appConfig.UI
+	uiFeatureConfig := featureConfig.UI
+	flashMessage := &httputil.FlashMessage{
+		Cookies: cookieManager,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
-	manager2 := &session.Manager{
-		IDPSessions:         manager,
-		AccessTokenSessions: sessionManager,
-		Events:              eventService,
+	baseViewModeler := &viewmodels.BaseViewModeler{
+		TrustProxy:            trustProxy,
+		OAuth:                 oAuthConfig,
+		AuthUI:                uiConfig,
+		AuthUIFeatureConfig:   uiFeatureConfig,
+		StaticAssets:          staticAssetResolver,
+		ForgotPassword:        forgotPasswordConfig,
+		Authentication:        authenticationConfig,
+		ErrorCookie:           errorCookie,
+		Translations:          translationService,
+		Clock:                 clockClock,
+		FlashMessage:          flashMessage,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
 	}
-	endSessionHandler := &handler2.EndSessionHandler{
-		Config:           oAuthConfig,
-		Endpoints:        endpointsProvider,
-		URLs:             urlProvider,
-		SessionManager:   manager2,
-		SessionCookieDef: cookieDef,
-		Cookies:          cookieManager,
+	responseRendererLogger := webapp2.NewResponseRendererLogger(factory)
+	responseRenderer := &webapp2.ResponseRenderer{
+		TemplateEngine: engine,
+		Logger:         responseRendererLogger,
 	}
-	oauthEndSessionHandler := &oauth.EndSessionHandler{
-		Logger:            endSessionHandlerLogger,
-		Database:          handle,
-		EndSessionHandler: endSessionHandler,
+	publisher := webapp2.NewPublisher(appID, appredisHandle)
+	controllerDeps := webapp2.ControllerDeps{
+		Database:      handle,
+		RedisHandle:   appredisHandle,
+		AppID:         appID,
+		Page:          webappService2,
+		BaseViewModel: baseViewModeler,
+		Renderer:      responseRenderer,
+		Publisher:     publisher,
+		Clock:         clockClock,
+		UIConfig:      uiConfig,
+		ErrorCookie:   errorCookie,
+		TrustProxy:    trustProxy,
 	}
-	return oauthEndSessionHandler
+	controllerFactory := webapp2.ControllerFactory{
+		LoggerFactory:  factory,
+		ControllerDeps: controllerDeps,
+	}
+	wechatAuthHandler := &webapp2.WechatAuthHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		Renderer:          responseRenderer,
+		IdentityConfig:    identityConfig,
+	}
+	return wechatAuthHandler
 }
 
-func newOAuthChallengeHandler(p *deps.RequestProvider) http.Handler {
+func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
 	appredisHandle := appProvider.Redis
 	config := appProvider.Config
 	appConfig := config.AppConfig
 	appID := appConfig.ID
-	clockClock := _wireSystemClockValue
-	provider := &challenge.Provider{
-		Redis: appredisHandle,
+	serviceLogger := webapp.NewServiceLogger(factory)
+	request := p.Request
+	sessionStoreRedis := &webapp.SessionStoreRedis{
 		AppID: appID,
-		Clock: clockClock,
-	}
-	factory := appProvider.LoggerFactory
-	jsonResponseWriterLogger := httputil.NewJSONResponseWriterLogger(factory)
-	jsonResponseWriter := &httputil.JSONResponseWriter{
-		Logger: jsonResponseWriterLogger,
-	}
-	challengeHandler := &oauth.ChallengeHandler{
-		Database:   handle,
-		Challenges: provider,
-		JSON:       jsonResponseWriter,
+		Redis: appredisHandle,
 	}
-	return challengeHandler
-}
-
-func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
-	appProvider := p.AppProvider
-	handle := appProvider.AppDatabase
-	factory := appProvider.LoggerFactory
-	jsonResponseWriterLogger := httputil.NewJSONResponseWriterLogger(factory)
-	jsonResponseWriter := &httputil.JSONResponseWriter{
-		Logger: jsonResponseWriterLogger,
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	authenticationConfig := appConfig.Authentication
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
 	}
-	config := appProvider.Config
-	appConfig := config.AppConfig
-	appID := appConfig.ID
-	oAuthConfig := appConfig.OAuth
-	tokenHandlerLogger := handler.NewTokenHandlerLogger(factory)
-	secretConfig := config.SecretConfig
-	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
-	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
-	request := p.Request
+	logger := interaction.NewLogger(factory)
 	contextContext := deps.ProvideRequestContext(request)
 	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
-	authorizationStore := &pq.AuthorizationStore{
-		SQLBuilder:  sqlBuilderApp,
-		SQLExecutor: sqlExecutor,
-	}
-	appredisHandle := appProvider.Redis
-	logger := redis.NewLogger(factory)
 	clockClock := _wireSystemClockValue
-	store := &redis.Store{
-		Context:     contextContext,
-		Redis:       appredisHandle,
-		AppID:       appID,
-		Logger:      logger,
-		SQLBuilder:  sqlBuilderApp,
-		SQLExecutor: sqlExecutor,
-		Clock:       clockClock,
-	}
-	interactionLogger := interaction.NewLogger(factory)
 	featureConfig := config.FeatureConfig
-	rootProvider := appProvider.RootProvider
-	environmentConfig := rootProvider.EnvironmentConfig
-	trustProxy := environmentConfig.TrustProxy
-	authenticationConfig := appConfig.Authentication
 	identityConfig := appConfig.Identity
 	identityFeatureConfig := featureConfig.Identity
-	serviceStore := &service.Store{
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	store := &service.Store{
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
@@ -3196,13 +11797,13 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		Authentication:        authenticationConfig,
 		Identity:              identityConfig,
 		IdentityFeatureConfig: identityFeatureConfig,
-		Store:                 serviceStore,
+		Store:                 store,
 		LoginID:               provider,
 		OAuth:                 oauthProvider,
 		Anonymous:             anonymousProvider,
 		Biometric:             biometricProvider,
 	}
-	store2 := &service2.Store{
+	serviceStore := &service2.Store{
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
@@ -3218,7 +11819,9 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -3232,6 +11835,7 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -3262,22 +11866,55 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
-		Store:       store2,
+		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -3317,6 +11954,7 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -3333,7 +11971,6 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 	engine := &template.Engine{
 		Resolver: resolver,
 	}
-	httpConfig := appConfig.HTTP
 	localizationConfig := appConfig.Localization
 	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
 	staticAssetResolver := &web.StaticAssetResolver{
@@ -3353,6 +11990,7 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -3387,15 +12025,33 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -3415,7 +12071,19 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -3445,6 +12113,10 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		UserStore:         userStore,
 		Events:            eventService,
 	}
+	authorizationStore := &pq.AuthorizationStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
 	storeRedisLogger := idpsession.NewStoreRedisLogger(factory)
 	idpsessionStoreRedis := &idpsession.StoreRedis{
 		Redis:  appredisHandle,
@@ -3453,17 +12125,27 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		Logger: storeRedisLogger,
 	}
 	sessionConfig := appConfig.Session
-	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
-	cookieDef := session.NewSessionCookieDef(sessionConfig)
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
 	idpsessionManager := &idpsession.Manager{
 		Store:     idpsessionStoreRedis,
 		Clock:     clockClock,
 		Config:    sessionConfig,
 		Cookies:   cookieManager,
-		CookieDef: cookieDef,
+		CookieDef: cookieDef2,
 	}
+	redisLogger := redis.NewLogger(factory)
+	redisStore := &redis.Store{
+		Context:     contextContext,
+		Redis:       appredisHandle,
+		AppID:       appID,
+		Logger:      redisLogger,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	oAuthConfig := appConfig.OAuth
 	sessionManager := &oauth2.SessionManager{
-		Store:  store,
+		Store:  redisStore,
 		Clock:  clockClock,
 		Config: oAuthConfig,
 	}
@@ -3500,16 +12182,18 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -3520,6 +12204,8 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -3599,7 +12285,6 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		Clock:        clockClock,
 		Random:       idpsessionRand,
 	}
-	mfaCookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
 	interactionContext := &interaction.Context{
 		Request:                   request,
 		Database:                  sqlExecutor,
@@ -3613,6 +12298,7 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -3621,6 +12307,7 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -3631,91 +12318,117 @@ func newOAuthAppSessionTokenHandler(p *deps.RequestProvider) http.Handler {
 		AuthenticationInfoService: authenticationinfoStoreRedis,
 		Sessions:                  idpsessionProvider,
 		SessionManager:            idpsessionManager,
-		SessionCookie:             cookieDef,
-		MFADeviceTokenCookie:      mfaCookieDef,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
 	}
 	interactionStoreRedis := &interaction.StoreRedis{
 		Redis: appredisHandle,
 		AppID: appID,
 	}
 	interactionService := &interaction.Service{
-		Logger:  interactionLogger,
+		Logger:  logger,
 		Context: interactionContext,
 		Store:   interactionStoreRedis,
 	}
-	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
-	idTokenIssuer := &oidc.IDTokenIssuer{
-		Secrets: oAuthKeyMaterials,
-		BaseURL: endpointsProvider,
-		Users:   queries,
-		Clock:   clockClock,
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
 	}
-	accessTokenEncoding := &oauth2.AccessTokenEncoding{
-		Secrets:    oAuthKeyMaterials,
-		Clock:      clockClock,
-		UserClaims: idTokenIssuer,
-		BaseURL:    endpointsProvider,
+	uiConfig := appConfig.UI
+	uiFeatureConfig := featureConfig.UI
+	flashMessage := &httputil.FlashMessage{
+		Cookies: cookieManager,
 	}
-	tokenGenerator := _wireTokenGeneratorValue
-	tokenService := handler.TokenService{
-		Request:           request,
-		AppID:             appID,
-		Config:            oAuthConfig,
-		TrustProxy:        trustProxy,
-		Authorizations:    authorizationStore,
-		OfflineGrants:     store,
-		AccessGrants:      store,
-		AccessEvents:      eventProvider,
-		AccessTokenIssuer: accessTokenEncoding,
-		GenerateToken:     tokenGenerator,
-		Clock:             clockClock,
-		Users:             queries,
+	baseViewModeler := &viewmodels.BaseViewModeler{
+		TrustProxy:            trustProxy,
+		OAuth:                 oAuthConfig,
+		AuthUI:                uiConfig,
+		AuthUIFeatureConfig:   uiFeatureConfig,
+		StaticAssets:          staticAssetResolver,
+		ForgotPassword:        forgotPasswordConfig,
+		Authentication:        authenticationConfig,
+		ErrorCookie:           errorCookie,
+		Translations:          translationService,
+		Clock:                 clockClock,
+		FlashMessage:          flashMessage,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
 	}
-	tokenHandler := &handler.TokenHandler{
-		AppID:            appID,
-		Config:           oAuthConfig,
-		Logger:           tokenHandlerLogger,
-		Authorizations:   authorizationStore,
-		CodeGrants:       store,
-		OfflineGrants:    store,
-		AppSessionTokens: store,
-		Graphs:           interactionService,
-		IDTokenIssuer:    idTokenIssuer,
-		Clock:            clockClock,
-		TokenService:     tokenService,
+	responseRendererLogger := webapp2.NewResponseRendererLogger(factory)
+	responseRenderer := &webapp2.ResponseRenderer{
+		TemplateEngine: engine,
+		Logger:         responseRendererLogger,
 	}
-	appSessionTokenHandler := &oauth.AppSessionTokenHandler{
-		Database:         handle,
-		JSON:             jsonResponseWriter,
-		AppSessionTokens: tokenHandler,
+	publisher := webapp2.NewPublisher(appID, appredisHandle)
+	controllerDeps := webapp2.ControllerDeps{
+		Database:      handle,
+		RedisHandle:   appredisHandle,
+		AppID:         appID,
+		Page:          webappService2,
+		BaseViewModel: baseViewModeler,
+		Renderer:      responseRenderer,
+		Publisher:     publisher,
+		Clock:         clockClock,
+		UIConfig:      uiConfig,
+		ErrorCookie:   errorCookie,
+		TrustProxy:    trustProxy,
 	}
-	return appSessionTokenHandler
+	controllerFactory := webapp2.ControllerFactory{
+		LoggerFactory:  factory,
+		ControllerDeps: controllerDeps,
+	}
+	jsonResponseWriterLogger := httputil.NewJSONResponseWriterLogger(factory)
+	jsonResponseWriter := &httputil.JSONResponseWriter{
+		Logger: jsonResponseWriterLogger,
+	}
+	wechatCallbackHandler := &webapp2.WechatCallbackHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		JSON:              jsonResponseWriter,
+	}
+	return wechatCallbackHandler
 }
 
-func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
-	anonymousUserSignupAPIHandlerLogger := api.NewAnonymousUserSignupAPIHandlerLogger(factory)
 	handle := appProvider.AppDatabase
-	jsonResponseWriterLogger := httputil.NewJSONResponseWriterLogger(factory)
-	jsonResponseWriter := &httputil.JSONResponseWriter{
-		Logger: jsonResponseWriterLogger,
-	}
+	appredisHandle := appProvider.Redis
 	config := appProvider.Config
 	appConfig := config.AppConfig
 	appID := appConfig.ID
-	oAuthConfig := appConfig.OAuth
-	anonymousUserHandlerLogger := handler.NewAnonymousUserHandlerLogger(factory)
-	logger := interaction.NewLogger(factory)
+	serviceLogger := webapp.NewServiceLogger(factory)
 	request := p.Request
+	sessionStoreRedis := &webapp.SessionStoreRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	authenticationConfig := appConfig.Authentication
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
+	}
+	logger := interaction.NewLogger(factory)
 	contextContext := deps.ProvideRequestContext(request)
 	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
 	clockClock := _wireSystemClockValue
 	featureConfig := config.FeatureConfig
-	rootProvider := appProvider.RootProvider
-	environmentConfig := rootProvider.EnvironmentConfig
-	trustProxy := environmentConfig.TrustProxy
-	authenticationConfig := appConfig.Authentication
 	identityConfig := appConfig.Identity
 	identityFeatureConfig := featureConfig.Identity
 	secretConfig := config.SecretConfig
@@ -3799,7 +12512,9 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -3813,6 +12528,7 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -3830,7 +12546,6 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	appredisHandle := appProvider.Redis
 	storeRedis := &oob.StoreRedis{
 		Redis: appredisHandle,
 		AppID: appID,
@@ -3844,22 +12559,55 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -3899,6 +12647,7 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -3915,7 +12664,6 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 	engine := &template.Engine{
 		Resolver: resolver,
 	}
-	httpConfig := appConfig.HTTP
 	localizationConfig := appConfig.Localization
 	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
 	staticAssetResolver := &web.StaticAssetResolver{
@@ -3935,6 +12683,7 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -3969,15 +12718,33 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -3997,7 +12764,19 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -4039,14 +12818,13 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		Logger: storeRedisLogger,
 	}
 	sessionConfig := appConfig.Session
-	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
-	cookieDef := session.NewSessionCookieDef(sessionConfig)
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
 	idpsessionManager := &idpsession.Manager{
 		Store:     idpsessionStoreRedis,
 		Clock:     clockClock,
 		Config:    sessionConfig,
 		Cookies:   cookieManager,
-		CookieDef: cookieDef,
+		CookieDef: cookieDef2,
 	}
 	redisLogger := redis.NewLogger(factory)
 	redisStore := &redis.Store{
@@ -4058,6 +12836,7 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		SQLExecutor: sqlExecutor,
 		Clock:       clockClock,
 	}
+	oAuthConfig := appConfig.OAuth
 	sessionManager := &oauth2.SessionManager{
 		Store:  redisStore,
 		Clock:  clockClock,
@@ -4096,16 +12875,18 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -4116,6 +12897,8 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -4195,7 +12978,6 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		Clock:        clockClock,
 		Random:       idpsessionRand,
 	}
-	mfaCookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
 	interactionContext := &interaction.Context{
 		Request:                   request,
 		Database:                  sqlExecutor,
@@ -4209,6 +12991,7 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -4217,6 +13000,7 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -4227,8 +13011,8 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		AuthenticationInfoService: authenticationinfoStoreRedis,
 		Sessions:                  idpsessionProvider,
 		SessionManager:            idpsessionManager,
-		SessionCookie:             cookieDef,
-		MFADeviceTokenCookie:      mfaCookieDef,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
 	}
 	interactionStoreRedis := &interaction.StoreRedis{
 		Redis: appredisHandle,
@@ -4239,85 +13023,102 @@ func newAPIAnonymousUserSignupHandler(p *deps.RequestProvider) http.Handler {
 		Context: interactionContext,
 		Store:   interactionStoreRedis,
 	}
-	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
-	idTokenIssuer := &oidc.IDTokenIssuer{
-		Secrets: oAuthKeyMaterials,
-		BaseURL: endpointsProvider,
-		Users:   queries,
-		Clock:   clockClock,
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
 	}
-	accessTokenEncoding := &oauth2.AccessTokenEncoding{
-		Secrets:    oAuthKeyMaterials,
-		Clock:      clockClock,
-		UserClaims: idTokenIssuer,
-		BaseURL:    endpointsProvider,
+	uiConfig := appConfig.UI
+	uiFeatureConfig := featureConfig.UI
+	flashMessage := &httputil.FlashMessage{
+		Cookies: cookieManager,
 	}
-	tokenGenerator := _wireTokenGeneratorValue
-	tokenService := handler.TokenService{
-		Request:           request,
-		AppID:             appID,
-		Config:            oAuthConfig,
-		TrustProxy:        trustProxy,
-		Authorizations:    authorizationStore,
-		OfflineGrants:     redisStore,
-		AccessGrants:      redisStore,
-		AccessEvents:      eventProvider,
-		AccessTokenIssuer: accessTokenEncoding,
-		GenerateToken:     tokenGenerator,
-		Clock:             clockClock,
-		Users:             queries,
+	baseViewModeler := &viewmodels.BaseViewModeler{
+		TrustProxy:            trustProxy,
+		OAuth:                 oAuthConfig,
+		AuthUI:                uiConfig,
+		AuthUIFeatureConfig:   uiFeatureConfig,
+		StaticAssets:          staticAssetResolver,
+		ForgotPassword:        forgotPasswordConfig,
+		Authentication:        authenticationConfig,
+		ErrorCookie:           errorCookie,
+		Translations:          translationService,
+		Clock:                 clockClock,
+		FlashMessage:          flashMessage,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
 	}
-	anonymousStoreRedis := &anonymous.StoreRedis{
-		Context: contextContext,
-		Redis:   appredisHandle,
-		AppID:   appID,
-		Clock:   clockClock,
+	responseRendererLogger := webapp2.NewResponseRendererLogger(factory)
+	responseRenderer := &webapp2.ResponseRenderer{
+		TemplateEngine: engine,
+		Logger:         responseRendererLogger,
 	}
-	anonymousUserHandler := &handler.AnonymousUserHandler{
-		AppID:               appID,
-		OAuthConfig:         oAuthConfig,
-		Logger:              anonymousUserHandlerLogger,
-		Graphs:              interactionService,
-		Authorizations:      authorizationStore,
-		Clock:               clockClock,
-		TokenService:        tokenService,
-		UserProvider:        queries,
-		AnonymousIdentities: anonymousProvider,
-		PromotionCodes:      anonymousStoreRedis,
+	publisher := webapp2.NewPublisher(appID, appredisHandle)
+	controllerDeps := webapp2.ControllerDeps{
+		Database:      handle,
+		RedisHandle:   appredisHandle,
+		AppID:         appID,
+		Page:          webappService2,
+		BaseViewModel: baseViewModeler,
+		Renderer:      responseRenderer,
+		Publisher:     publisher,
+		Clock:         clockClock,
+		UIConfig:      uiConfig,
+		ErrorCookie:   errorCookie,
+		TrustProxy:    trustProxy,
 	}
-	anonymousUserSignupAPIHandler := &api.AnonymousUserSignupAPIHandler{
-		Logger:               anonymousUserSignupAPIHandlerLogger,
-		Database:             handle,
-		JSON:                 jsonResponseWriter,
-		AnonymousUserHandler: anonymousUserHandler,
+	controllerFactory := webapp2.ControllerFactory{
+		LoggerFactory:  factory,
+		ControllerDeps: controllerDeps,
 	}
-	return anonymousUserSignupAPIHandler
+	enterLoginIDHandler := &webapp2.EnterLoginIDHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		Renderer:          responseRenderer,
+		Identities:        serviceService,
+	}
+	return enterLoginIDHandler
 }
 
-func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
-	anonymousUserPromotionCodeAPIHandlerLogger := api.NewAnonymousUserPromotionCodeAPILogger(factory)
 	handle := appProvider.AppDatabase
-	jsonResponseWriterLogger := httputil.NewJSONResponseWriterLogger(factory)
-	jsonResponseWriter := &httputil.JSONResponseWriter{
-		Logger: jsonResponseWriterLogger,
-	}
+	appredisHandle := appProvider.Redis
 	config := appProvider.Config
 	appConfig := config.AppConfig
 	appID := appConfig.ID
-	oAuthConfig := appConfig.OAuth
-	anonymousUserHandlerLogger := handler.NewAnonymousUserHandlerLogger(factory)
-	logger := interaction.NewLogger(factory)
+	serviceLogger := webapp.NewServiceLogger(factory)
 	request := p.Request
+	sessionStoreRedis := &webapp.SessionStoreRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	sessionCookieDef := webapp.NewSessionCookieDef()
+	signedUpCookieDef := webapp.NewSignedUpCookieDef()
+	authenticationConfig := appConfig.Authentication
+	cookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
+	errorCookieDef := webapp.NewErrorCookieDef()
+	rootProvider := appProvider.RootProvider
+	environmentConfig := rootProvider.EnvironmentConfig
+	trustProxy := environmentConfig.TrustProxy
+	httpConfig := appConfig.HTTP
+	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
+	errorCookie := &webapp.ErrorCookie{
+		Cookie:  errorCookieDef,
+		Cookies: cookieManager,
+	}
+	logger := interaction.NewLogger(factory)
 	contextContext := deps.ProvideRequestContext(request)
 	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
 	clockClock := _wireSystemClockValue
 	featureConfig := config.FeatureConfig
-	rootProvider := appProvider.RootProvider
-	environmentConfig := rootProvider.EnvironmentConfig
-	trustProxy := environmentConfig.TrustProxy
-	authenticationConfig := appConfig.Authentication
 	identityConfig := appConfig.Identity
 	identityFeatureConfig := featureConfig.Identity
 	secretConfig := config.SecretConfig
@@ -4401,7 +13202,9 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -4415,6 +13218,7 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -4432,7 +13236,6 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	appredisHandle := appProvider.Redis
 	storeRedis := &oob.StoreRedis{
 		Redis: appredisHandle,
 		AppID: appID,
@@ -4446,22 +13249,55 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -4501,6 +13337,7 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -4517,7 +13354,6 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 	engine := &template.Engine{
 		Resolver: resolver,
 	}
-	httpConfig := appConfig.HTTP
 	localizationConfig := appConfig.Localization
 	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
 	staticAssetResolver := &web.StaticAssetResolver{
@@ -4537,6 +13373,7 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -4571,15 +13408,33 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -4599,7 +13454,19 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -4641,14 +13508,13 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		Logger: storeRedisLogger,
 	}
 	sessionConfig := appConfig.Session
-	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
-	cookieDef := session.NewSessionCookieDef(sessionConfig)
+	cookieDef2 := session.NewSessionCookieDef(sessionConfig)
 	idpsessionManager := &idpsession.Manager{
 		Store:     idpsessionStoreRedis,
 		Clock:     clockClock,
 		Config:    sessionConfig,
 		Cookies:   cookieManager,
-		CookieDef: cookieDef,
+		CookieDef: cookieDef2,
 	}
 	redisLogger := redis.NewLogger(factory)
 	redisStore := &redis.Store{
@@ -4660,6 +13526,7 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		SQLExecutor: sqlExecutor,
 		Clock:       clockClock,
 	}
+	oAuthConfig := appConfig.OAuth
 	sessionManager := &oauth2.SessionManager{
 		Store:  redisStore,
 		Clock:  clockClock,
@@ -4698,16 +13565,18 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -4718,6 +13587,8 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -4797,7 +13668,6 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		Clock:        clockClock,
 		Random:       idpsessionRand,
 	}
-	mfaCookieDef := mfa.NewDeviceTokenCookieDef(authenticationConfig)
 	interactionContext := &interaction.Context{
 		Request:                   request,
 		Database:                  sqlExecutor,
@@ -4811,6 +13681,7 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -4819,6 +13690,7 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -4829,8 +13701,8 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		AuthenticationInfoService: authenticationinfoStoreRedis,
 		Sessions:                  idpsessionProvider,
 		SessionManager:            idpsessionManager,
-		SessionCookie:             cookieDef,
-		MFADeviceTokenCookie:      mfaCookieDef,
+		SessionCookie:             cookieDef2,
+		MFADeviceTokenCookie:      cookieDef,
 	}
 	interactionStoreRedis := &interaction.StoreRedis{
 		Redis: appredisHandle,
@@ -4841,87 +13713,69 @@ func newAPIAnonymousUserPromotionCodeHandler(p *deps.RequestProvider) http.Handl
 		Context: interactionContext,
 		Store:   interactionStoreRedis,
 	}
-	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
-	idTokenIssuer := &oidc.IDTokenIssuer{
-		Secrets: oAuthKeyMaterials,
-		BaseURL: endpointsProvider,
-		Users:   queries,
-		Clock:   clockClock,
+	webappService2 := &webapp.Service2{
+		Logger:               serviceLogger,
+		Request:              request,
+		Sessions:             sessionStoreRedis,
+		SessionCookie:        sessionCookieDef,
+		SignedUpCookie:       signedUpCookieDef,
+		MFADeviceTokenCookie: cookieDef,
+		ErrorCookie:          errorCookie,
+		Cookies:              cookieManager,
+		Graph:                interactionService,
 	}
-	accessTokenEncoding := &oauth2.AccessTokenEncoding{
-		Secrets:    oAuthKeyMaterials,
-		Clock:      clockClock,
-		UserClaims: idTokenIssuer,
-		BaseURL:    endpointsProvider,
+	uiConfig := appConfig.UI
+	uiFeatureConfig := featureConfig.UI
+	flashMessage := &httputil.FlashMessage{
+		Cookies: cookieManager,
 	}
-	tokenGenerator := _wireTokenGeneratorValue
-	tokenService := handler.TokenService{
-		Request:           request,
-		AppID:             appID,
-		Config:            oAuthConfig,
-		TrustProxy:        trustProxy,
-		Authorizations:    authorizationStore,
-		OfflineGrants:     redisStore,
-		AccessGrants:      redisStore,
-		AccessEvents:      eventProvider,
-		AccessTokenIssuer: accessTokenEncoding,
-		GenerateToken:     tokenGenerator,
-		Clock:             clockClock,
-		Users:             queries,
+	baseViewModeler := &viewmodels.BaseViewModeler{
+		TrustProxy:            trustProxy,
+		OAuth:                 oAuthConfig,
+		AuthUI:                uiConfig,
+		AuthUIFeatureConfig:   uiFeatureConfig,
+		StaticAssets:          staticAssetResolver,
+		ForgotPassword:        forgotPasswordConfig,
+		Authentication:        authenticationConfig,
+		ErrorCookie:           errorCookie,
+		Translations:          translationService,
+		Clock:                 clockClock,
+		FlashMessage:          flashMessage,
+		DefaultLanguageTag:    defaultLanguageTag,
+		SupportedLanguageTags: supportedLanguageTags,
 	}
-	anonymousStoreRedis := &anonymous.StoreRedis{
-		Context: contextContext,
-		Redis:   appredisHandle,
-		AppID:   appID,
-		Clock:   clockClock,
+	responseRendererLogger := webapp2.NewResponseRendererLogger(factory)
+	responseRenderer := &webapp2.ResponseRenderer{
+		TemplateEngine: engine,
+		Logger:         responseRendererLogger,
 	}
-	anonymousUserHandler := &handler.AnonymousUserHandler{
-		AppID:               appID,
-		OAuthConfig:         oAuthConfig,
-		Logger:              anonymousUserHandlerLogger,
-		Graphs:              interactionService,
-		Authorizations:      authorizationStore,
-		Clock:               clockClock,
-		TokenService:        tokenService,
-		UserProvider:        queries,
-		AnonymousIdentities: anonymousProvider,
-		PromotionCodes:      anonymousStoreRedis,
+	publisher := webapp2.NewPublisher(appID, appredisHandle)
+	controllerDeps := webapp2.ControllerDeps{
+		Database:      handle,
+		RedisHandle:   appredisHandle,
+		AppID:         appID,
+		Page:          webappService2,
+		BaseViewModel: baseViewModeler,
+		Renderer:      responseRenderer,
+		Publisher:     publisher,
+		Clock:         clockClock,
+		UIConfig:      uiConfig,
+		ErrorCookie:   errorCookie,
+		TrustProxy:    trustProxy,
 	}
-	anonymousUserPromotionCodeAPIHandler := &api.AnonymousUserPromotionCodeAPIHandler{
-		Logger:         anonymousUserPromotionCodeAPIHandlerLogger,
-		Database:       handle,
-		JSON:           jsonResponseWriter,
-		PromotionCodes: anonymousUserHandler,
+	controllerFactory := webapp2.ControllerFactory{
+		LoggerFactory:  factory,
+		ControllerDeps: controllerDeps,
 	}
-	return anonymousUserPromotionCodeAPIHandler
-}
-
-func newWebAppOAuthEntrypointHandler(p *deps.RequestProvider) http.Handler {
-	oAuthEntrypointHandler := &webapp2.OAuthEntrypointHandler{}
-	return oAuthEntrypointHandler
-}
-
-func newWebAppRootHandler(p *deps.RequestProvider) http.Handler {
-	appProvider := p.AppProvider
-	config := appProvider.Config
-	appConfig := config.AppConfig
-	authenticationConfig := appConfig.Authentication
-	request := p.Request
-	rootProvider := appProvider.RootProvider
-	environmentConfig := rootProvider.EnvironmentConfig
-	trustProxy := environmentConfig.TrustProxy
-	httpConfig := appConfig.HTTP
-	cookieManager := deps.NewCookieManager(request, trustProxy, httpConfig)
-	signedUpCookieDef := webapp.NewSignedUpCookieDef()
-	rootHandler := &webapp2.RootHandler{
-		AuthenticationConfig: authenticationConfig,
-		Cookies:              cookieManager,
-		SignedUpCookie:       signedUpCookieDef,
+	enterPasswordHandler := &webapp2.EnterPasswordHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		Renderer:          responseRenderer,
 	}
-	return rootHandler
+	return enterPasswordHandler
 }
 
-func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -5037,7 +13891,9 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -5051,6 +13907,7 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -5081,22 +13938,55 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -5136,6 +14026,7 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -5171,6 +14062,7 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -5205,15 +14097,33 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -5233,7 +14143,19 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -5332,16 +14254,18 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -5352,6 +14276,8 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -5444,6 +14370,7 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -5452,6 +14379,7 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -5528,33 +14456,16 @@ func newWebAppLoginHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	formPrefiller := &webapp2.FormPrefiller{
-		LoginID: loginIDConfig,
-		UI:      uiConfig,
-	}
-	analyticredisHandle := appProvider.AnalyticRedis
-	analyticStoreRedisLogger := analytic.NewStoreRedisLogger(factory)
-	writeStoreRedis := &analytic.WriteStoreRedis{
-		Context: contextContext,
-		Redis:   analyticredisHandle,
-		AppID:   appID,
-		Clock:   clockClock,
-		Logger:  analyticStoreRedisLogger,
-	}
-	analyticService := &analytic.Service{
-		Counter: writeStoreRedis,
-	}
-	loginHandler := &webapp2.LoginHandler{
+	createPasswordHandler := &webapp2.CreatePasswordHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
-		FormPrefiller:     formPrefiller,
 		Renderer:          responseRenderer,
-		AnalyticService:   analyticService,
+		PasswordPolicy:    passwordChecker,
 	}
-	return loginHandler
+	return createPasswordHandler
 }
 
-func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -5670,7 +14581,9 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -5684,6 +14597,7 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -5714,22 +14628,55 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -5769,6 +14716,7 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -5804,6 +14752,7 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -5838,15 +14787,33 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -5866,7 +14833,19 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -5965,16 +14944,18 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -5985,6 +14966,8 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -6077,6 +15060,7 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -6085,6 +15069,7 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -6161,33 +15146,17 @@ func newWebAppSignupHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	formPrefiller := &webapp2.FormPrefiller{
-		LoginID: loginIDConfig,
-		UI:      uiConfig,
-	}
-	analyticredisHandle := appProvider.AnalyticRedis
-	analyticStoreRedisLogger := analytic.NewStoreRedisLogger(factory)
-	writeStoreRedis := &analytic.WriteStoreRedis{
-		Context: contextContext,
-		Redis:   analyticredisHandle,
-		AppID:   appID,
-		Clock:   clockClock,
-		Logger:  analyticStoreRedisLogger,
-	}
-	analyticService := &analytic.Service{
-		Counter: writeStoreRedis,
-	}
-	signupHandler := &webapp2.SignupHandler{
+	setupTOTPHandler := &webapp2.SetupTOTPHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
-		FormPrefiller:     formPrefiller,
 		Renderer:          responseRenderer,
-		AnalyticService:   analyticService,
+		Clock:             clockClock,
+		Endpoints:         endpointsProvider,
 	}
-	return signupHandler
+	return setupTOTPHandler
 }
 
-func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSetupPasskeyHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -6303,7 +15272,9 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -6317,6 +15288,7 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -6347,22 +15319,55 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -6402,6 +15407,7 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -6437,6 +15443,7 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -6471,15 +15478,33 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -6499,7 +15524,19 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -6598,16 +15635,18 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -6618,6 +15657,8 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -6710,6 +15751,7 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -6718,6 +15760,7 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -6794,20 +15837,16 @@ func newWebAppPromoteHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	formPrefiller := &webapp2.FormPrefiller{
-		LoginID: loginIDConfig,
-		UI:      uiConfig,
-	}
-	promoteHandler := &webapp2.PromoteHandler{
+	setupPasskeyHandler := &webapp2.SetupPasskeyHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
-		FormPrefiller:     formPrefiller,
 		Renderer:          responseRenderer,
+		Clock:             clockClock,
 	}
-	return promoteHandler
+	return setupPasskeyHandler
 }
 
-func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppEnterPasskeyHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -6923,7 +15962,9 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -6937,6 +15978,7 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -6967,22 +16009,55 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -7022,6 +16097,7 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -7057,6 +16133,7 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -7091,15 +16168,33 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -7119,7 +16214,19 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -7218,16 +16325,18 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -7238,6 +16347,8 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -7330,6 +16441,7 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -7338,6 +16450,7 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -7414,21 +16527,15 @@ func newWebAppSelectAccountHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	selectAccountHandler := &webapp2.SelectAccountHandler{
-		ControllerFactory:         controllerFactory,
-		BaseViewModel:             baseViewModeler,
-		Renderer:                  responseRenderer,
-		AuthenticationConfig:      authenticationConfig,
-		SignedUpCookie:            signedUpCookieDef,
-		Users:                     queries,
-		Identities:                serviceService,
-		AuthenticationInfoService: authenticationinfoStoreRedis,
-		Cookies:                   cookieManager,
+	enterPasskeyHandler := &webapp2.EnterPasskeyHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		Renderer:          responseRenderer,
 	}
-	return selectAccountHandler
+	return enterPasskeyHandler
 }
 
-func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -7544,7 +16651,9 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -7558,6 +16667,7 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -7588,22 +16698,55 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -7643,6 +16786,7 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -7678,6 +16822,7 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -7712,15 +16857,33 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -7740,7 +16903,19 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -7839,16 +17014,18 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -7859,6 +17036,8 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -7951,6 +17130,7 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -7959,6 +17139,7 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -8035,13 +17216,15 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	ssoCallbackHandler := &webapp2.SSOCallbackHandler{
+	enterTOTPHandler := &webapp2.EnterTOTPHandler{
 		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		Renderer:          responseRenderer,
 	}
-	return ssoCallbackHandler
+	return enterTOTPHandler
 }
 
-func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -8157,7 +17340,9 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -8171,6 +17356,7 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -8201,22 +17387,55 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -8256,6 +17475,7 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -8291,6 +17511,7 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -8325,15 +17546,33 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -8353,7 +17592,19 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -8452,16 +17703,18 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -8472,6 +17725,8 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -8564,6 +17819,7 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -8572,6 +17828,7 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -8648,16 +17905,15 @@ func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	wechatAuthHandler := &webapp2.WechatAuthHandler{
+	setupOOBOTPHandler := &webapp2.SetupOOBOTPHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		IdentityConfig:    identityConfig,
 	}
-	return wechatAuthHandler
+	return setupOOBOTPHandler
 }
 
-func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -8773,7 +18029,9 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -8787,6 +18045,7 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -8817,22 +18076,55 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -8872,6 +18164,7 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -8907,6 +18200,7 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -8941,15 +18235,33 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -8969,7 +18281,19 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -9068,16 +18392,18 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -9088,6 +18414,8 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -9180,6 +18508,7 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -9188,6 +18517,7 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -9264,19 +18594,17 @@ func newWechatCallbackHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	jsonResponseWriterLogger := httputil.NewJSONResponseWriterLogger(factory)
-	jsonResponseWriter := &httputil.JSONResponseWriter{
-		Logger: jsonResponseWriterLogger,
-	}
-	wechatCallbackHandler := &webapp2.WechatCallbackHandler{
+	enterOOBOTPHandler := &webapp2.EnterOOBOTPHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
-		JSON:              jsonResponseWriter,
+		Renderer:          responseRenderer,
+		RateLimiter:       limiter,
+		FlashMessage:      flashMessage,
 	}
-	return wechatCallbackHandler
+	return enterOOBOTPHandler
 }
 
-func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -9392,7 +18720,9 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -9406,6 +18736,7 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -9436,22 +18767,55 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -9491,6 +18855,7 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -9526,6 +18891,7 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -9560,15 +18926,33 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -9588,7 +18972,19 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -9687,16 +19083,18 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -9707,6 +19105,8 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -9799,6 +19199,7 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -9807,6 +19208,7 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -9883,16 +19285,15 @@ func newWebAppEnterLoginIDHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	enterLoginIDHandler := &webapp2.EnterLoginIDHandler{
+	enterRecoveryCodeHandler := &webapp2.EnterRecoveryCodeHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		Identities:        serviceService,
 	}
-	return enterLoginIDHandler
+	return enterRecoveryCodeHandler
 }
 
-func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -10008,7 +19409,9 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -10022,6 +19425,7 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -10052,22 +19456,55 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -10107,6 +19544,7 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -10142,6 +19580,7 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -10176,15 +19615,33 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -10204,7 +19661,19 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -10303,16 +19772,18 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -10323,6 +19794,8 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -10415,6 +19888,7 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -10423,6 +19897,7 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -10499,15 +19974,15 @@ func newWebAppEnterPasswordHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	enterPasswordHandler := &webapp2.EnterPasswordHandler{
+	setupRecoveryCodeHandler := &webapp2.SetupRecoveryCodeHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
 	}
-	return enterPasswordHandler
+	return setupRecoveryCodeHandler
 }
 
-func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -10623,7 +20098,9 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -10637,6 +20114,7 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -10667,22 +20145,55 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -10722,6 +20233,7 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -10757,6 +20269,7 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -10791,15 +20304,33 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -10819,7 +20350,19 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -10918,16 +20461,18 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -10938,6 +20483,8 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -11030,6 +20577,7 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -11038,6 +20586,7 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -11114,16 +20663,18 @@ func newWebAppCreatePasswordHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	createPasswordHandler := &webapp2.CreatePasswordHandler{
+	verifyIdentityHandler := &webapp2.VerifyIdentityHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		PasswordPolicy:    passwordChecker,
+		Verifications:     verificationService,
+		RateLimiter:       limiter,
+		FlashMessage:      flashMessage,
 	}
-	return createPasswordHandler
+	return verifyIdentityHandler
 }
 
-func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -11239,7 +20790,9 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -11253,6 +20806,7 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -11283,22 +20837,55 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -11338,6 +20925,7 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -11373,6 +20961,7 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -11407,15 +20996,33 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -11435,7 +21042,19 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -11534,16 +21153,18 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -11554,6 +21175,8 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -11646,6 +21269,7 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -11654,6 +21278,7 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -11730,17 +21355,15 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	setupTOTPHandler := &webapp2.SetupTOTPHandler{
+	verifyIdentitySuccessHandler := &webapp2.VerifyIdentitySuccessHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		Clock:             clockClock,
-		Endpoints:         endpointsProvider,
 	}
-	return setupTOTPHandler
+	return verifyIdentitySuccessHandler
 }
 
-func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -11856,7 +21479,9 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -11870,6 +21495,7 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -11900,22 +21526,55 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -11955,6 +21614,7 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -11990,6 +21650,7 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -12024,15 +21685,33 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -12052,7 +21731,19 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -12151,16 +21842,18 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -12171,6 +21864,8 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -12263,6 +21958,7 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -12271,6 +21967,7 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -12347,15 +22044,20 @@ func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	enterTOTPHandler := &webapp2.EnterTOTPHandler{
+	formPrefiller := &webapp2.FormPrefiller{
+		LoginID: loginIDConfig,
+		UI:      uiConfig,
+	}
+	forgotPasswordHandler := &webapp2.ForgotPasswordHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
+		FormPrefiller:     formPrefiller,
 		Renderer:          responseRenderer,
 	}
-	return enterTOTPHandler
+	return forgotPasswordHandler
 }
 
-func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -12471,7 +22173,9 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -12485,6 +22189,7 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -12515,22 +22220,55 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -12570,6 +22308,7 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -12605,6 +22344,7 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -12639,15 +22379,33 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -12667,7 +22425,19 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -12766,16 +22536,18 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -12786,6 +22558,8 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -12878,6 +22652,7 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -12886,6 +22661,7 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -12962,15 +22738,15 @@ func newWebAppSetupOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	setupOOBOTPHandler := &webapp2.SetupOOBOTPHandler{
+	forgotPasswordSuccessHandler := &webapp2.ForgotPasswordSuccessHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
 	}
-	return setupOOBOTPHandler
+	return forgotPasswordSuccessHandler
 }
 
-func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppDeviceHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -13086,7 +22862,9 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -13100,6 +22878,7 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -13130,22 +22909,55 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -13185,6 +22997,7 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -13220,6 +23033,7 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -13254,15 +23068,33 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -13282,7 +23114,19 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -13381,16 +23225,18 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -13401,6 +23247,8 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -13493,6 +23341,7 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -13501,6 +23350,7 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -13577,17 +23427,47 @@ func newWebAppEnterOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	enterOOBOTPHandler := &webapp2.EnterOOBOTPHandler{
+	oAuthClientSecrets := deps.ProvideOAuthClientSecrets(secretConfig)
+	deviceAuthorizationHandlerLogger := handler.NewDeviceAuthorizationHandlerLogger(factory)
+	oauthURLProvider := &oauth2.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	authenticateURLProvider := &webapp.AuthenticateURLProvider{
+		Endpoints: endpointsProvider,
+		Pages:     webappService2,
+		Clock:     clockClock,
+	}
+	scopesValidator := _wireScopesValidatorValue
+	tokenGenerator := _wireTokenGeneratorValue
+	deviceAuthorizationHandler := &handler.DeviceAuthorizationHandler{
+		Context:                   contextContext,
+		AppID:                     appID,
+		Config:                    oAuthConfig,
+		ClientSecrets:             oAuthClientSecrets,
+		Logger:                    deviceAuthorizationHandlerLogger,
+		TrustProxy:                trustProxy,
+		Authorizations:            authorizationStore,
+		DeviceCodeGrants:          redisStore,
+		ConsentRequests:           redisStore,
+		DeviceURLs:                oauthURLProvider,
+		WebAppURLs:                authenticateURLProvider,
+		ValidateScopes:            scopesValidator,
+		CodeGenerator:             tokenGenerator,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Cookies:                   cookieManager,
+		RateLimiter:               limiter,
+		Clock:                     clockClock,
+	}
+	deviceHandler := &webapp2.DeviceHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		RateLimiter:       limiter,
-		FlashMessage:      flashMessage,
+		Devices:           deviceAuthorizationHandler,
 	}
-	return enterOOBOTPHandler
+	return deviceHandler
 }
 
-func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppDeviceApproveHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -13703,7 +23583,9 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -13717,6 +23599,7 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -13747,22 +23630,55 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -13802,6 +23718,7 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -13837,6 +23754,7 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -13871,15 +23789,33 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -13899,7 +23835,19 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -13998,16 +23946,18 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -14018,6 +23968,8 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -14110,6 +24062,7 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -14118,6 +24071,7 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -14194,15 +24148,47 @@ func newWebAppEnterRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	enterRecoveryCodeHandler := &webapp2.EnterRecoveryCodeHandler{
+	oAuthClientSecrets := deps.ProvideOAuthClientSecrets(secretConfig)
+	deviceAuthorizationHandlerLogger := handler.NewDeviceAuthorizationHandlerLogger(factory)
+	oauthURLProvider := &oauth2.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	authenticateURLProvider := &webapp.AuthenticateURLProvider{
+		Endpoints: endpointsProvider,
+		Pages:     webappService2,
+		Clock:     clockClock,
+	}
+	scopesValidator := _wireScopesValidatorValue
+	tokenGenerator := _wireTokenGeneratorValue
+	deviceAuthorizationHandler := &handler.DeviceAuthorizationHandler{
+		Context:                   contextContext,
+		AppID:                     appID,
+		Config:                    oAuthConfig,
+		ClientSecrets:             oAuthClientSecrets,
+		Logger:                    deviceAuthorizationHandlerLogger,
+		TrustProxy:                trustProxy,
+		Authorizations:            authorizationStore,
+		DeviceCodeGrants:          redisStore,
+		ConsentRequests:           redisStore,
+		DeviceURLs:                oauthURLProvider,
+		WebAppURLs:                authenticateURLProvider,
+		ValidateScopes:            scopesValidator,
+		CodeGenerator:             tokenGenerator,
+		AuthenticationInfoService: authenticationinfoStoreRedis,
+		Cookies:                   cookieManager,
+		RateLimiter:               limiter,
+		Clock:                     clockClock,
+	}
+	deviceApproveHandler := &webapp2.DeviceApproveHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
+		Devices:           deviceAuthorizationHandler,
 	}
-	return enterRecoveryCodeHandler
+	return deviceApproveHandler
 }
 
-func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppDeviceSuccessHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -14318,7 +24304,9 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -14332,6 +24320,7 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -14362,22 +24351,55 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -14417,6 +24439,7 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -14452,6 +24475,7 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -14486,15 +24510,33 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -14514,7 +24556,19 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -14613,16 +24667,18 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -14633,6 +24689,8 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -14725,6 +24783,7 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -14733,6 +24792,7 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -14809,15 +24869,15 @@ func newWebAppSetupRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	setupRecoveryCodeHandler := &webapp2.SetupRecoveryCodeHandler{
+	deviceSuccessHandler := &webapp2.DeviceSuccessHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
 	}
-	return setupRecoveryCodeHandler
+	return deviceSuccessHandler
 }
 
-func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppConsentHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -14933,7 +24993,9 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -14947,6 +25009,7 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -14977,22 +25040,55 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -15032,6 +25128,7 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -15067,6 +25164,7 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -15101,15 +25199,33 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -15129,7 +25245,19 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -15228,16 +25356,18 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -15248,6 +25378,8 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -15340,6 +25472,7 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -15348,6 +25481,7 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -15424,18 +25558,75 @@ func newWebAppVerifyIdentityHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	verifyIdentityHandler := &webapp2.VerifyIdentityHandler{
+	authorizationHandlerLogger := handler.NewAuthorizationHandlerLogger(factory)
+	oauthURLProvider := &oauth2.URLProvider{
+		Endpoints: endpointsProvider,
+	}
+	authenticateURLProvider := &webapp.AuthenticateURLProvider{
+		Endpoints: endpointsProvider,
+		Pages:     webappService2,
+		Clock:     clockClock,
+	}
+	scopesValidator := _wireScopesValidatorValue
+	tokenGenerator := _wireTokenGeneratorValue
+	anonymousStoreRedis := &anonymous.StoreRedis{
+		Context: contextContext,
+		Redis:   appredisHandle,
+		AppID:   appID,
+		Clock:   clockClock,
+	}
+	loginHintHandler := &webapp.LoginHintHandler{
+		Config:                  oAuthConfig,
+		Anonymous:               anonymousProvider,
+		AnonymousPromotionCodes: anonymousStoreRedis,
+		OfflineGrants:           redisStore,
+		AppSessionTokens:        redisStore,
+		AppSessions:             redisStore,
+		Clock:                   clockClock,
+		Cookies:                 cookieManager,
+		Pages:                   webappService2,
+	}
+	oAuthKeyMaterials := deps.ProvideOAuthKeyMaterials(secretConfig)
+	idTokenIssuer := &oidc.IDTokenIssuer{
+		Secrets: oAuthKeyMaterials,
+		BaseURL: endpointsProvider,
+		Users:   queries,
+		Clock:   clockClock,
+	}
+	authorizationHandler := &handler.AuthorizationHandler{
+		Context:                     contextContext,
+		AppID:                       appID,
+		Config:                      oAuthConfig,
+		HTTPConfig:                  httpConfig,
+		Logger:                      authorizationHandlerLogger,
+		Sessions:                    idpsessionProvider,
+		Authorizations:              authorizationStore,
+		OfflineGrants:               redisStore,
+		CodeGrants:                  redisStore,
+		PushedAuthorizationRequests: redisStore,
+		ConsentRequests:             redisStore,
+		DeviceCodeGrants:            redisStore,
+		OAuthURLs:                   oauthURLProvider,
+		DeviceURLs:                  oauthURLProvider,
+		WebAppURLs:                  authenticateURLProvider,
+		ValidateScopes:              scopesValidator,
+		CodeGenerator:               tokenGenerator,
+		LoginHint:                   loginHintHandler,
+		IDTokens:                    idTokenIssuer,
+		AuthenticationInfoService:   authenticationinfoStoreRedis,
+		Clock:                       clockClock,
+		Cookies:                     cookieManager,
+	}
+	consentHandler := &webapp2.ConsentHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		Verifications:     verificationService,
-		RateLimiter:       limiter,
-		FlashMessage:      flashMessage,
+		Consents:          authorizationHandler,
 	}
-	return verifyIdentityHandler
+	return consentHandler
 }
 
-func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -15551,7 +25742,9 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -15565,6 +25758,7 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -15595,22 +25789,55 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -15650,6 +25877,7 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -15685,6 +25913,7 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -15719,15 +25948,33 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -15747,7 +25994,19 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -15846,16 +26105,18 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -15866,6 +26127,8 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -15958,6 +26221,7 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -15966,6 +26230,7 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -16042,15 +26307,16 @@ func newWebAppVerifyIdentitySuccessHandler(p *deps.RequestProvider) http.Handler
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	verifyIdentitySuccessHandler := &webapp2.VerifyIdentitySuccessHandler{
+	resetPasswordHandler := &webapp2.ResetPasswordHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
+		PasswordPolicy:    passwordChecker,
 	}
-	return verifyIdentitySuccessHandler
+	return resetPasswordHandler
 }
 
-func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -16166,7 +26432,9 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -16180,6 +26448,7 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -16210,22 +26479,55 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -16265,6 +26567,7 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -16300,6 +26603,7 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -16334,15 +26638,33 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -16362,7 +26684,19 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -16461,16 +26795,18 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -16481,6 +26817,8 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -16573,6 +26911,7 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -16581,6 +26920,7 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -16657,20 +26997,15 @@ func newWebAppForgotPasswordHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	formPrefiller := &webapp2.FormPrefiller{
-		LoginID: loginIDConfig,
-		UI:      uiConfig,
-	}
-	forgotPasswordHandler := &webapp2.ForgotPasswordHandler{
+	resetPasswordSuccessHandler := &webapp2.ResetPasswordSuccessHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
-		FormPrefiller:     formPrefiller,
 		Renderer:          responseRenderer,
 	}
-	return forgotPasswordHandler
+	return resetPasswordSuccessHandler
 }
 
-func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -16786,7 +27121,9 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -16800,6 +27137,7 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -16830,22 +27168,55 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -16885,6 +27256,7 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -16920,6 +27292,7 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -16954,15 +27327,33 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -16982,7 +27373,19 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -17081,16 +27484,18 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -17101,6 +27506,8 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -17193,6 +27600,7 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -17201,6 +27609,7 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -17277,15 +27686,37 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	forgotPasswordSuccessHandler := &webapp2.ForgotPasswordSuccessHandler{
-		ControllerFactory: controllerFactory,
-		BaseViewModel:     baseViewModeler,
-		Renderer:          responseRenderer,
+	biometricConfig := identityConfig.Biometric
+	settingsViewModeler := &viewmodels.SettingsViewModeler{
+		Authenticators: service3,
+		Identities:     serviceService,
+		MFA:            mfaService,
+		Authentication: authenticationConfig,
+		Biometric:      biometricConfig,
 	}
-	return forgotPasswordSuccessHandler
+	facadeIdentityFacade := &facade.IdentityFacade{
+		Coordinator: coordinator,
+	}
+	settingsProfileViewModeler := &viewmodels.SettingsProfileViewModeler{
+		Localization:      localizationConfig,
+		UserProfileConfig: userProfileConfig,
+		Users:             queries,
+		Identities:        facadeIdentityFacade,
+		Clock:             clockClock,
+	}
+	settingsHandler := &webapp2.SettingsHandler{
+		ControllerFactory:        controllerFactory,
+		BaseViewModel:            baseViewModeler,
+		SettingsViewModel:        settingsViewModeler,
+		SettingsProfileViewModel: settingsProfileViewModeler,
+		Renderer:                 responseRenderer,
+		Identities:               serviceService,
+		Verification:             verificationService,
+	}
+	return settingsHandler
 }
 
-func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -17401,7 +27832,9 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -17415,6 +27848,7 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -17445,22 +27879,55 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -17500,6 +27967,7 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -17535,6 +28003,7 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -17569,15 +28038,33 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -17597,7 +28084,19 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -17696,16 +28195,18 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -17716,6 +28217,8 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -17808,6 +28311,7 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -17816,6 +28320,7 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -17892,16 +28397,26 @@ func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	resetPasswordHandler := &webapp2.ResetPasswordHandler{
-		ControllerFactory: controllerFactory,
-		BaseViewModel:     baseViewModeler,
-		Renderer:          responseRenderer,
-		PasswordPolicy:    passwordChecker,
+	facadeIdentityFacade := &facade.IdentityFacade{
+		Coordinator: coordinator,
 	}
-	return resetPasswordHandler
+	settingsProfileViewModeler := &viewmodels.SettingsProfileViewModeler{
+		Localization:      localizationConfig,
+		UserProfileConfig: userProfileConfig,
+		Users:             queries,
+		Identities:        facadeIdentityFacade,
+		Clock:             clockClock,
+	}
+	settingsProfileHandler := &webapp2.SettingsProfileHandler{
+		ControllerFactory:        controllerFactory,
+		BaseViewModel:            baseViewModeler,
+		SettingsProfileViewModel: settingsProfileViewModeler,
+		Renderer:                 responseRenderer,
+	}
+	return settingsProfileHandler
 }
 
-func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -18017,7 +28532,9 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -18031,6 +28548,7 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -18061,22 +28579,55 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -18116,6 +28667,7 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -18151,6 +28703,7 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -18185,15 +28738,33 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -18213,7 +28784,19 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -18312,16 +28895,18 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -18332,6 +28917,8 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -18424,6 +29011,7 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -18432,6 +29020,7 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -18508,15 +29097,39 @@ func newWebAppResetPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	resetPasswordSuccessHandler := &webapp2.ResetPasswordSuccessHandler{
-		ControllerFactory: controllerFactory,
-		BaseViewModel:     baseViewModeler,
-		Renderer:          responseRenderer,
+	facadeIdentityFacade := &facade.IdentityFacade{
+		Coordinator: coordinator,
 	}
-	return resetPasswordSuccessHandler
+	settingsProfileViewModeler := &viewmodels.SettingsProfileViewModeler{
+		Localization:      localizationConfig,
+		UserProfileConfig: userProfileConfig,
+		Users:             queries,
+		Identities:        facadeIdentityFacade,
+		Clock:             clockClock,
+	}
+	userFacade := &facade.UserFacade{
+		UserProvider: userProvider,
+		Coordinator:  coordinator,
+	}
+	customattrsService := &customattrs.Service{
+		Config:         userProfileConfig,
+		ServiceNoEvent: customattrsServiceNoEvent,
+		Events:         eventService,
+	}
+	settingsProfileEditHandler := &webapp2.SettingsProfileEditHandler{
+		ControllerFactory:        controllerFactory,
+		BaseViewModel:            baseViewModeler,
+		SettingsProfileViewModel: settingsProfileViewModeler,
+		Renderer:                 responseRenderer,
+		Users:                    userFacade,
+		StdAttrs:                 stdattrsService,
+		CustomAttrs:              customattrsService,
+		ErrorCookie:              errorCookie,
+	}
+	return settingsProfileEditHandler
 }
 
-func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -18632,7 +29245,9 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -18646,6 +29261,7 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -18676,22 +29292,55 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -18731,6 +29380,7 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -18766,6 +29416,7 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -18800,15 +29451,33 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -18828,7 +29497,19 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -18927,16 +29608,18 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -18947,6 +29630,8 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -19039,6 +29724,7 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -19047,6 +29733,7 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -19123,37 +29810,17 @@ func newWebAppSettingsHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	biometricConfig := identityConfig.Biometric
-	settingsViewModeler := &viewmodels.SettingsViewModeler{
-		Authenticators: service3,
-		Identities:     serviceService,
-		MFA:            mfaService,
-		Authentication: authenticationConfig,
-		Biometric:      biometricConfig,
-	}
-	facadeIdentityFacade := &facade.IdentityFacade{
-		Coordinator: coordinator,
-	}
-	settingsProfileViewModeler := &viewmodels.SettingsProfileViewModeler{
-		Localization:      localizationConfig,
-		UserProfileConfig: userProfileConfig,
-		Users:             queries,
-		Identities:        facadeIdentityFacade,
-		Clock:             clockClock,
-	}
-	settingsHandler := &webapp2.SettingsHandler{
-		ControllerFactory:        controllerFactory,
-		BaseViewModel:            baseViewModeler,
-		SettingsViewModel:        settingsViewModeler,
-		SettingsProfileViewModel: settingsProfileViewModeler,
-		Renderer:                 responseRenderer,
-		Identities:               serviceService,
-		Verification:             verificationService,
+	settingsIdentityHandler := &webapp2.SettingsIdentityHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		Renderer:          responseRenderer,
+		Identities:        serviceService,
+		Verification:      verificationService,
 	}
-	return settingsHandler
+	return settingsIdentityHandler
 }
 
-func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -19269,7 +29936,9 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -19283,6 +29952,7 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -19313,22 +29983,55 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -19368,6 +30071,7 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -19403,6 +30107,7 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -19437,15 +30142,33 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -19465,7 +30188,19 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -19564,16 +30299,18 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -19584,6 +30321,8 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -19676,6 +30415,7 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -19684,6 +30424,7 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -19760,26 +30501,16 @@ func newWebAppSettingsProfileHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	facadeIdentityFacade := &facade.IdentityFacade{
-		Coordinator: coordinator,
-	}
-	settingsProfileViewModeler := &viewmodels.SettingsProfileViewModeler{
-		Localization:      localizationConfig,
-		UserProfileConfig: userProfileConfig,
-		Users:             queries,
-		Identities:        facadeIdentityFacade,
-		Clock:             clockClock,
-	}
-	settingsProfileHandler := &webapp2.SettingsProfileHandler{
-		ControllerFactory:        controllerFactory,
-		BaseViewModel:            baseViewModeler,
-		SettingsProfileViewModel: settingsProfileViewModeler,
-		Renderer:                 responseRenderer,
+	settingsBiometricHandler := &webapp2.SettingsBiometricHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		Renderer:          responseRenderer,
+		Identities:        serviceService,
 	}
-	return settingsProfileHandler
+	return settingsBiometricHandler
 }
 
-func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -19895,7 +30626,9 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -19909,6 +30642,7 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -19939,22 +30673,55 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -19994,6 +30761,7 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -20029,6 +30797,7 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -20063,15 +30832,33 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -20091,7 +30878,19 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -20190,16 +30989,18 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -20210,6 +31011,8 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -20302,6 +31105,7 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -20310,6 +31114,7 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -20386,39 +31191,25 @@ func newWebAppSettingsProfileEditHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	facadeIdentityFacade := &facade.IdentityFacade{
-		Coordinator: coordinator,
-	}
-	settingsProfileViewModeler := &viewmodels.SettingsProfileViewModeler{
-		Localization:      localizationConfig,
-		UserProfileConfig: userProfileConfig,
-		Users:             queries,
-		Identities:        facadeIdentityFacade,
-		Clock:             clockClock,
-	}
-	userFacade := &facade.UserFacade{
-		UserProvider: userProvider,
-		Coordinator:  coordinator,
-	}
-	customattrsService := &customattrs.Service{
-		Config:         userProfileConfig,
-		ServiceNoEvent: customattrsServiceNoEvent,
-		Events:         eventService,
+	biometricConfig := identityConfig.Biometric
+	settingsViewModeler := &viewmodels.SettingsViewModeler{
+		Authenticators: service3,
+		Identities:     serviceService,
+		MFA:            mfaService,
+		Authentication: authenticationConfig,
+		Biometric:      biometricConfig,
 	}
-	settingsProfileEditHandler := &webapp2.SettingsProfileEditHandler{
-		ControllerFactory:        controllerFactory,
-		BaseViewModel:            baseViewModeler,
-		SettingsProfileViewModel: settingsProfileViewModeler,
-		Renderer:                 responseRenderer,
-		Users:                    userFacade,
-		StdAttrs:                 stdattrsService,
-		CustomAttrs:              customattrsService,
-		ErrorCookie:              errorCookie,
+	settingsMFAHandler := &webapp2.SettingsMFAHandler{
+		ControllerFactory: controllerFactory,
+		BaseViewModel:     baseViewModeler,
+		SettingsViewModel: settingsViewModeler,
+		Renderer:          responseRenderer,
+		MFA:               mfaService,
 	}
-	return settingsProfileEditHandler
+	return settingsMFAHandler
 }
 
-func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsPasskeyHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -20534,7 +31325,9 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -20548,6 +31341,7 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -20578,22 +31372,55 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -20633,6 +31460,7 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -20668,6 +31496,7 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -20702,15 +31531,33 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -20730,7 +31577,19 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -20829,16 +31688,18 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -20849,6 +31710,8 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -20941,6 +31804,7 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -20949,6 +31813,7 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -21025,17 +31890,17 @@ func newWebAppSettingsIdentityHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	settingsIdentityHandler := &webapp2.SettingsIdentityHandler{
+	settingsPasskeyHandler := &webapp2.SettingsPasskeyHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		Identities:        serviceService,
-		Verification:      verificationService,
+		Authenticators:    service3,
+		Authentication:    authenticationConfig,
 	}
-	return settingsIdentityHandler
+	return settingsPasskeyHandler
 }
 
-func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -21151,7 +32016,9 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -21165,6 +32032,7 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -21195,22 +32063,55 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -21250,6 +32151,7 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -21285,6 +32187,7 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -21319,15 +32222,33 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -21347,7 +32268,19 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -21446,16 +32379,18 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -21466,6 +32401,8 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -21558,6 +32495,7 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -21566,6 +32504,7 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -21642,16 +32581,16 @@ func newWebAppSettingsBiometricHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	settingsBiometricHandler := &webapp2.SettingsBiometricHandler{
+	settingsTOTPHandler := &webapp2.SettingsTOTPHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		Identities:        serviceService,
+		Authenticators:    service3,
 	}
-	return settingsBiometricHandler
+	return settingsTOTPHandler
 }
 
-func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -21767,7 +32706,9 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -21781,6 +32722,7 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -21811,22 +32753,55 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -21866,6 +32841,7 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -21901,6 +32877,7 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -21935,15 +32912,33 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -21963,7 +32958,19 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -22062,16 +33069,18 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -22082,6 +33091,8 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -22174,6 +33185,7 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -22182,6 +33194,7 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -22258,25 +33271,16 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	biometricConfig := identityConfig.Biometric
-	settingsViewModeler := &viewmodels.SettingsViewModeler{
-		Authenticators: service3,
-		Identities:     serviceService,
-		MFA:            mfaService,
-		Authentication: authenticationConfig,
-		Biometric:      biometricConfig,
-	}
-	settingsMFAHandler := &webapp2.SettingsMFAHandler{
+	settingsOOBOTPHandler := &webapp2.SettingsOOBOTPHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
-		SettingsViewModel: settingsViewModeler,
 		Renderer:          responseRenderer,
-		MFA:               mfaService,
+		Authenticators:    service3,
 	}
-	return settingsMFAHandler
+	return settingsOOBOTPHandler
 }
 
-func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -22392,7 +33396,9 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -22406,6 +33412,7 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -22436,22 +33443,55 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -22491,6 +33531,7 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -22526,6 +33567,7 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -22560,15 +33602,33 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -22588,7 +33648,19 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -22687,16 +33759,18 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -22707,6 +33781,8 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -22799,6 +33875,7 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -22807,6 +33884,7 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -22883,16 +33961,17 @@ func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	settingsTOTPHandler := &webapp2.SettingsTOTPHandler{
+	settingsRecoveryCodeHandler := &webapp2.SettingsRecoveryCodeHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		Authenticators:    service3,
+		Authentication:    authenticationConfig,
+		MFA:               mfaService,
 	}
-	return settingsTOTPHandler
+	return settingsRecoveryCodeHandler
 }
 
-func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -23008,7 +34087,9 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -23022,6 +34103,7 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -23052,22 +34134,55 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -23107,6 +34222,7 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -23142,6 +34258,7 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -23176,15 +34293,33 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -23204,7 +34339,19 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -23303,16 +34450,18 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -23323,6 +34472,8 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -23415,6 +34566,7 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -23423,6 +34575,7 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -23499,16 +34652,21 @@ func newWebAppSettingsOOBOTPHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	settingsOOBOTPHandler := &webapp2.SettingsOOBOTPHandler{
+	manager2 := &session.Manager{
+		IDPSessions:         idpsessionManager,
+		AccessTokenSessions: sessionManager,
+		Events:              eventService,
+	}
+	settingsSessionsHandler := &webapp2.SettingsSessionsHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		Authenticators:    service3,
+		Sessions:          manager2,
 	}
-	return settingsOOBOTPHandler
+	return settingsSessionsHandler
 }
 
-func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsConnectedAppsHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -23624,7 +34782,9 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -23638,6 +34798,7 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -23668,22 +34829,55 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -23723,6 +34917,7 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -23758,6 +34953,7 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -23792,15 +34988,33 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -23820,7 +35034,19 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -23919,16 +35145,18 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -23939,6 +35167,8 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -24031,6 +35261,7 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -24039,6 +35270,7 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -24115,17 +35347,27 @@ func newWebAppSettingsRecoveryCodeHandler(p *deps.RequestProvider) http.Handler
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	settingsRecoveryCodeHandler := &webapp2.SettingsRecoveryCodeHandler{
+	manager2 := &session.Manager{
+		IDPSessions:         idpsessionManager,
+		AccessTokenSessions: sessionManager,
+		Events:              eventService,
+	}
+	authorizationService := &oauth2.AuthorizationService{
+		Store:          authorizationStore,
+		OfflineGrants:  redisStore,
+		SessionManager: manager2,
+	}
+	settingsConnectedAppsHandler := &webapp2.SettingsConnectedAppsHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		Authentication:    authenticationConfig,
-		MFA:               mfaService,
+		OAuthConfig:       oAuthConfig,
+		Authorizations:    authorizationService,
 	}
-	return settingsRecoveryCodeHandler
+	return settingsConnectedAppsHandler
 }
 
-func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -24241,7 +35483,9 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -24255,6 +35499,7 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -24285,22 +35530,55 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -24340,6 +35618,7 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -24375,6 +35654,7 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -24409,15 +35689,33 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -24437,7 +35735,19 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -24536,16 +35846,18 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -24556,6 +35868,8 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -24648,6 +35962,7 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -24656,6 +35971,7 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -24732,21 +36048,16 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	manager2 := &session.Manager{
-		IDPSessions:         idpsessionManager,
-		AccessTokenSessions: sessionManager,
-		Events:              eventService,
-	}
-	settingsSessionsHandler := &webapp2.SettingsSessionsHandler{
+	forceChangePasswordHandler := &webapp2.ForceChangePasswordHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		Sessions:          manager2,
+		PasswordPolicy:    passwordChecker,
 	}
-	return settingsSessionsHandler
+	return forceChangePasswordHandler
 }
 
-func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -24862,7 +36173,9 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -24876,6 +36189,7 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -24906,22 +36220,55 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -24961,6 +36308,7 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -24996,6 +36344,7 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -25030,15 +36379,33 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -25058,7 +36425,19 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -25157,16 +36536,18 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -25177,6 +36558,8 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -25269,6 +36652,7 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -25277,6 +36661,7 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -25353,16 +36738,16 @@ func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	forceChangePasswordHandler := &webapp2.ForceChangePasswordHandler{
+	settingsChangePasswordHandler := &webapp2.SettingsChangePasswordHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
 		PasswordPolicy:    passwordChecker,
 	}
-	return forceChangePasswordHandler
+	return settingsChangePasswordHandler
 }
 
-func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -25478,7 +36863,9 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -25492,6 +36879,7 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -25522,22 +36910,55 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -25577,6 +36998,7 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -25612,6 +37034,7 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -25646,15 +37069,33 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -25674,7 +37115,19 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -25773,16 +37226,18 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -25793,6 +37248,8 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -25885,6 +37342,7 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -25893,6 +37351,7 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -25969,16 +37428,16 @@ func newWebAppSettingsChangePasswordHandler(p *deps.RequestProvider) http.Handle
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	settingsChangePasswordHandler := &webapp2.SettingsChangePasswordHandler{
+	forceChangeSecondaryPasswordHandler := &webapp2.ForceChangeSecondaryPasswordHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
 		PasswordPolicy:    passwordChecker,
 	}
-	return settingsChangePasswordHandler
+	return forceChangeSecondaryPasswordHandler
 }
 
-func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -26094,7 +37553,9 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -26108,6 +37569,7 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -26138,22 +37600,55 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -26193,6 +37688,7 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -26228,6 +37724,7 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -26262,15 +37759,33 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -26290,7 +37805,19 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -26389,16 +37916,18 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -26409,6 +37938,8 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -26501,6 +38032,7 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -26509,6 +38041,7 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -26585,16 +38118,16 @@ func newWebAppForceChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	forceChangeSecondaryPasswordHandler := &webapp2.ForceChangeSecondaryPasswordHandler{
+	settingsChangeSecondaryPasswordHandler := &webapp2.SettingsChangeSecondaryPasswordHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
 		PasswordPolicy:    passwordChecker,
 	}
-	return forceChangeSecondaryPasswordHandler
+	return settingsChangeSecondaryPasswordHandler
 }
 
-func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -26710,7 +38243,9 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -26724,6 +38259,7 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -26754,22 +38290,55 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -26809,6 +38378,7 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -26844,6 +38414,7 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -26878,15 +38449,33 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -26906,7 +38495,19 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -27005,16 +38606,18 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -27025,6 +38628,8 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -27117,6 +38722,7 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -27125,6 +38731,7 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -27201,16 +38808,15 @@ func newWebAppSettingsChangeSecondaryPasswordHandler(p *deps.RequestProvider) ht
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	settingsChangeSecondaryPasswordHandler := &webapp2.SettingsChangeSecondaryPasswordHandler{
+	userDisabledHandler := &webapp2.UserDisabledHandler{
 		ControllerFactory: controllerFactory,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
-		PasswordPolicy:    passwordChecker,
 	}
-	return settingsChangeSecondaryPasswordHandler
+	return userDisabledHandler
 }
 
-func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -27326,7 +38932,9 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -27340,6 +38948,7 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -27370,22 +38979,55 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -27425,6 +39067,7 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -27460,6 +39103,7 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -27494,15 +39138,33 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -27522,7 +39184,19 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -27621,16 +39295,18 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -27641,6 +39317,8 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -27733,6 +39411,7 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -27741,6 +39420,7 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -27817,15 +39497,34 @@ func newWebAppUserDisabledHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	userDisabledHandler := &webapp2.UserDisabledHandler{
+	manager2 := &session.Manager{
+		IDPSessions:         idpsessionManager,
+		AccessTokenSessions: sessionManager,
+		Events:              eventService,
+	}
+	logoutHandler := &webapp2.LogoutHandler{
 		ControllerFactory: controllerFactory,
+		Database:          handle,
+		TrustProxy:        trustProxy,
+		OAuth:             oAuthConfig,
+		UIConfig:          uiConfig,
+		SessionManager:    manager2,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
 	}
-	return userDisabledHandler
+	return logoutHandler
 }
 
-func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
+func newWebAppStaticAssetsHandler(p *deps.RequestProvider) http.Handler {
+	appProvider := p.AppProvider
+	manager := appProvider.Resources
+	staticAssetsHandler := &webapp2.StaticAssetsHandler{
+		Resources: manager,
+	}
+	return staticAssetsHandler
+}
+
+func newWebAppMagicLinkHandler(p *deps.RequestProvider) http.Handler {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
 	handle := appProvider.AppDatabase
@@ -27941,7 +39640,9 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -27955,6 +39656,7 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -27985,22 +39687,55 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -28040,6 +39775,7 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -28075,6 +39811,7 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -28109,15 +39846,33 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -28137,7 +39892,19 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -28236,16 +40003,18 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -28256,6 +40025,8 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -28348,6 +40119,7 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -28356,6 +40128,7 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -28432,31 +40205,13 @@ func newWebAppLogoutHandler(p *deps.RequestProvider) http.Handler {
 		LoggerFactory:  factory,
 		ControllerDeps: controllerDeps,
 	}
-	manager2 := &session.Manager{
-		IDPSessions:         idpsessionManager,
-		AccessTokenSessions: sessionManager,
-		Events:              eventService,
-	}
-	logoutHandler := &webapp2.LogoutHandler{
+	magicLinkHandler := &webapp2.MagicLinkHandler{
 		ControllerFactory: controllerFactory,
-		Database:          handle,
-		TrustProxy:        trustProxy,
-		OAuth:             oAuthConfig,
-		UIConfig:          uiConfig,
-		SessionManager:    manager2,
 		BaseViewModel:     baseViewModeler,
 		Renderer:          responseRenderer,
+		OOBCodes:          oobProvider,
 	}
-	return logoutHandler
-}
-
-func newWebAppStaticAssetsHandler(p *deps.RequestProvider) http.Handler {
-	appProvider := p.AppProvider
-	manager := appProvider.Resources
-	staticAssetsHandler := &webapp2.StaticAssetsHandler{
-		Resources: manager,
-	}
-	return staticAssetsHandler
+	return magicLinkHandler
 }
 
 func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
@@ -28575,7 +40330,9 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -28589,6 +40346,7 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -28619,22 +40377,55 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -28674,6 +40465,7 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -28709,6 +40501,7 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -28743,15 +40536,33 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -28771,7 +40582,19 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -28870,16 +40693,18 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -28890,6 +40715,8 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -28982,6 +40809,7 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -28990,6 +40818,7 @@ func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -29190,7 +41019,9 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -29204,6 +41035,7 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -29234,22 +41066,55 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -29289,6 +41154,7 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -29324,6 +41190,7 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -29358,15 +41225,33 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -29386,7 +41271,19 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
@@ -29485,16 +41382,18 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 		TaskQueue:   queue,
 		Events:      eventService,
 	}
+	urlProvider := &webapp.URLProvider{
+		Endpoints: endpointsProvider,
+	}
 	codeSender := &oob.CodeSender{
 		OTPMessageSender: messageSender,
+		WebAppURLs:       urlProvider,
 	}
 	oAuthClientCredentials := deps.ProvideOAuthClientCredentials(secretConfig)
-	urlProvider := &webapp.URLProvider{
-		Endpoints: endpointsProvider,
-	}
 	wechatURLProvider := &webapp.WechatURLProvider{
 		Endpoints: endpointsProvider,
 	}
+	samlspKeyMaterials := deps.ProvideSAMLSPKeyMaterials(secretConfig)
 	normalizer := &stdattrs2.Normalizer{
 		LoginIDNormalizerFactory: normalizerFactory,
 	}
@@ -29505,6 +41404,8 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 		RedirectURL:                  urlProvider,
 		Clock:                        clockClock,
 		WechatURLProvider:            wechatURLProvider,
+		SAMLURLProvider:              urlProvider,
+		SAMLSPKeyMaterials:           samlspKeyMaterials,
 		StandardAttributesNormalizer: normalizer,
 	}
 	forgotPasswordConfig := appConfig.ForgotPassword
@@ -29597,6 +41498,7 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 		BiometricIdentities:       biometricProvider,
 		OOBAuthenticators:         oobProvider,
 		OOBCodeSender:             codeSender,
+		PasskeyAuthenticators:     passkeyProvider,
 		OAuthProviderFactory:      oAuthProviderFactory,
 		MFA:                       mfaService,
 		ForgotPassword:            forgotpasswordProvider,
@@ -29605,6 +41507,7 @@ func newWebAppErrorHandler(p *deps.RequestProvider) http.Handler {
 		Verification:              verificationService,
 		VerificationCodeSender:    verificationCodeSender,
 		RateLimiter:               limiter,
+		AuthenticationLockout:     lockoutService,
 		Nonces:                    nonceService,
 		Search:                    elasticsearchService,
 		Challenges:                challengeProvider,
@@ -30058,7 +41961,9 @@ func newSessionMiddleware(p *deps.RequestProvider) httproute.Middleware {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -30072,6 +41977,7 @@ func newSessionMiddleware(p *deps.RequestProvider) httproute.Middleware {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -30102,22 +42008,55 @@ func newSessionMiddleware(p *deps.RequestProvider) httproute.Middleware {
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: handle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: handle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: handle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       store2,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -30410,7 +42349,9 @@ func newSettingsSubRoutesMiddleware(p *deps.RequestProvider) httproute.Middlewar
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, logger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -30424,6 +42365,7 @@ func newSettingsSubRoutesMiddleware(p *deps.RequestProvider) httproute.Middlewar
 		Logger:          logger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -30455,22 +42397,56 @@ func newSettingsSubRoutesMiddleware(p *deps.RequestProvider) httproute.Middlewar
 		Clock:     clockClock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: appredisHandle,
+		AppID: appID,
+		Clock: clockClock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	httpConfig := appConfig.HTTP
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clockClock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: appredisHandle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clockClock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       serviceStore,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -30513,6 +42489,7 @@ func newSettingsSubRoutesMiddleware(p *deps.RequestProvider) httproute.Middlewar
 		Clock:         clockClock,
 		Config:        authenticationConfig,
 		RateLimiter:   limiter,
+		Lockout:       lockoutService,
 	}
 	userStore := &user.Store{
 		SQLBuilder:  sqlBuilderApp,
@@ -30529,7 +42506,6 @@ func newSettingsSubRoutesMiddleware(p *deps.RequestProvider) httproute.Middlewar
 	engine := &template.Engine{
 		Resolver: resolver,
 	}
-	httpConfig := appConfig.HTTP
 	localizationConfig := appConfig.Localization
 	staticAssetURLPrefix := environmentConfig.StaticAssetURLPrefix
 	staticAssetResolver := &web.StaticAssetResolver{
@@ -30549,6 +42525,7 @@ func newSettingsSubRoutesMiddleware(p *deps.RequestProvider) httproute.Middlewar
 	eventLogger := event.NewLogger(factory)
 	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
 	storeImpl := &event.StoreImpl{
+		AppID:       appID,
 		SQLBuilder:  sqlBuilder,
 		SQLExecutor: sqlExecutor,
 	}
@@ -30583,15 +42560,33 @@ func newSettingsSubRoutesMiddleware(p *deps.RequestProvider) httproute.Middlewar
 	hookConfig := appConfig.Hook
 	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
 	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
 	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
 	deliverer := &hook.Deliverer{
 		Config:             hookConfig,
 		Secret:             webhookKeyMaterials,
 		Clock:              clockClock,
 		SyncHTTP:           syncHTTPClient,
-		AsyncHTTP:          asyncHTTPClient,
+		Deliveries:         deliveryService,
 		StandardAttributes: serviceNoEvent,
 		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
 	}
 	sink := &hook.Sink{
 		Logger:    hookLogger,
@@ -30611,7 +42606,19 @@ func newSettingsSubRoutesMiddleware(p *deps.RequestProvider) httproute.Middlewar
 		Database: writeHandle,
 		Store:    writeStore,
 	}
-	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink)
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	eventService := event.NewService(contextContext, request, trustProxy, eventLogger, handle, clockClock, localizationConfig, storeImpl, resolverImpl, sink, auditSink, eventstreamSink, queue)
 	welcomemessageProvider := &welcomemessage.Provider{
 		Translation:          translationService,
 		RateLimiter:          limiter,
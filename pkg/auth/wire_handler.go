@@ -55,6 +55,27 @@ func newOAuthRevokeHandler(p *deps.RequestProvider) http.Handler {
 	))
 }
 
+func newOAuthIntrospectionHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handleroauth.IntrospectionHandler)),
+	))
+}
+
+func newOAuthDeviceAuthorizationHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handleroauth.DeviceAuthorizationHandler)),
+	))
+}
+
+func newOAuthPushedAuthorizationRequestHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handleroauth.PushedAuthorizationRequestHandler)),
+	))
+}
+
 func newOAuthMetadataHandler(p *deps.RequestProvider) http.Handler {
 	panic(wire.Build(
 		DependencySet,
@@ -160,6 +181,20 @@ func newWebAppSSOCallbackHandler(p *deps.RequestProvider) http.Handler {
 	))
 }
 
+func newWebAppSAMLACSHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.SAMLACSHandler)),
+	))
+}
+
+func newWebAppSAMLMetadataHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.SAMLMetadataHandler)),
+	))
+}
+
 func newWechatAuthHandler(p *deps.RequestProvider) http.Handler {
 	panic(wire.Build(
 		DependencySet,
@@ -202,6 +237,20 @@ func newWebAppSetupTOTPHandler(p *deps.RequestProvider) http.Handler {
 	))
 }
 
+func newWebAppSetupPasskeyHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.SetupPasskeyHandler)),
+	))
+}
+
+func newWebAppEnterPasskeyHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.EnterPasskeyHandler)),
+	))
+}
+
 func newWebAppEnterTOTPHandler(p *deps.RequestProvider) http.Handler {
 	panic(wire.Build(
 		DependencySet,
@@ -265,6 +314,34 @@ func newWebAppForgotPasswordSuccessHandler(p *deps.RequestProvider) http.Handler
 	))
 }
 
+func newWebAppDeviceHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.DeviceHandler)),
+	))
+}
+
+func newWebAppDeviceApproveHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.DeviceApproveHandler)),
+	))
+}
+
+func newWebAppDeviceSuccessHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.DeviceSuccessHandler)),
+	))
+}
+
+func newWebAppConsentHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.ConsentHandler)),
+	))
+}
+
 func newWebAppResetPasswordHandler(p *deps.RequestProvider) http.Handler {
 	panic(wire.Build(
 		DependencySet,
@@ -321,6 +398,13 @@ func newWebAppSettingsMFAHandler(p *deps.RequestProvider) http.Handler {
 	))
 }
 
+func newWebAppSettingsPasskeyHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.SettingsPasskeyHandler)),
+	))
+}
+
 func newWebAppSettingsTOTPHandler(p *deps.RequestProvider) http.Handler {
 	panic(wire.Build(
 		DependencySet,
@@ -349,6 +433,13 @@ func newWebAppSettingsSessionsHandler(p *deps.RequestProvider) http.Handler {
 	))
 }
 
+func newWebAppSettingsConnectedAppsHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.SettingsConnectedAppsHandler)),
+	))
+}
+
 func newWebAppForceChangePasswordHandler(p *deps.RequestProvider) http.Handler {
 	panic(wire.Build(
 		DependencySet,
@@ -398,6 +489,13 @@ func newWebAppStaticAssetsHandler(p *deps.RequestProvider) http.Handler {
 	))
 }
 
+func newWebAppMagicLinkHandler(p *deps.RequestProvider) http.Handler {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(http.Handler), new(*handlerwebapp.MagicLinkHandler)),
+	))
+}
+
 func newWebAppReturnHandler(p *deps.RequestProvider) http.Handler {
 	panic(wire.Build(
 		DependencySet,
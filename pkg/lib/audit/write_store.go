@@ -38,7 +38,10 @@ func (s *WriteStore) PersistLog(logEntry *Log) (err error) {
 			logEntry.UserAgent,
 			logEntry.ClientID,
 			data,
-		)
+		).
+		// The same event may be persisted again when it is relayed again,
+		// so ignore the duplicate.
+		Suffix("ON CONFLICT DO NOTHING")
 
 	_, err = s.SQLExecutor.ExecWith(builder)
 	if err != nil {
@@ -8,6 +8,7 @@ const (
 	AuthenticationTypeTOTP         AuthenticationType = "totp"
 	AuthenticationTypeOOBOTPEmail  AuthenticationType = "oob_otp_email"
 	AuthenticationTypeOOBOTPSMS    AuthenticationType = "oob_otp_sms"
+	AuthenticationTypePasskey      AuthenticationType = "passkey"
 	AuthenticationTypeRecoveryCode AuthenticationType = "recovery_code"
 	AuthenticationTypeDeviceToken  AuthenticationType = "device_token"
 )
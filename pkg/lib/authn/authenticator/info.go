@@ -42,6 +42,18 @@ func (i *Info) ToRef() *Ref {
 	}
 }
 
+// ToModel returns the API model of the authenticator, which does not contain the secret.
+func (i *Info) ToModel() model.Authenticator {
+	return model.Authenticator{
+		Meta:      i.GetMeta(),
+		UserID:    i.UserID,
+		Type:      i.Type,
+		IsDefault: i.IsDefault,
+		Kind:      string(i.Kind),
+		Claims:    i.Claims,
+	}
+}
+
 func (i *Info) GetMeta() model.Meta {
 	return model.Meta{
 		ID:        i.ID,
@@ -60,6 +72,8 @@ func (i *Info) AMR() []string {
 		return []string{model.AMROTP}
 	case model.AuthenticatorTypeOOBSMS:
 		return []string{model.AMROTP, model.AMRSMS}
+	case model.AuthenticatorTypePasskey:
+		return []string{model.AMRXPasskey}
 	default:
 		panic("authenticator: unknown authenticator type: " + i.Type)
 	}
@@ -103,6 +117,12 @@ func (i *Info) Equal(that *Info) bool {
 		iPhone := i.Claims[AuthenticatorClaimOOBOTPPhone].(string)
 		thatPhone := that.Claims[AuthenticatorClaimOOBOTPPhone].(string)
 		return iPhone == thatPhone
+	case model.AuthenticatorTypePasskey:
+		// If they are passkey, they have the same credential ID.
+		// A credential cannot be registered as both primary and secondary.
+		iCredentialID := i.Claims[AuthenticatorClaimPasskeyCredentialID].(string)
+		thatCredentialID := that.Claims[AuthenticatorClaimPasskeyCredentialID].(string)
+		return iCredentialID == thatCredentialID
 	default:
 		panic("authenticator: unknown authenticator type: " + i.Type)
 	}
@@ -115,6 +135,8 @@ func (i *Info) StandardClaims() map[model.ClaimName]string {
 		break
 	case model.AuthenticatorTypeTOTP:
 		break
+	case model.AuthenticatorTypePasskey:
+		break
 	case model.AuthenticatorTypeOOBEmail:
 		claims[model.ClaimEmail] = i.Claims[AuthenticatorClaimOOBOTPEmail].(string)
 	case model.AuthenticatorTypeOOBSMS:
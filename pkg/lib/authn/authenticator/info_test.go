@@ -138,6 +138,24 @@ func TestAuthenticatorEqualTrue(t *testing.T) {
 					},
 				},
 			},
+
+			// Passkey with the same credential ID.
+			{
+				&Info{
+					Type: model.AuthenticatorTypePasskey,
+					Kind: KindPrimary,
+					Claims: map[string]interface{}{
+						AuthenticatorClaimPasskeyCredentialID: "credential",
+					},
+				},
+				&Info{
+					Type: model.AuthenticatorTypePasskey,
+					Kind: KindSecondary,
+					Claims: map[string]interface{}{
+						AuthenticatorClaimPasskeyCredentialID: "credential",
+					},
+				},
+			},
 		}
 
 		for _, c := range cases {
@@ -207,6 +225,24 @@ func TestAuthenticatorEqualFalse(t *testing.T) {
 					},
 				},
 			},
+
+			// Passkey with different credential ID.
+			{
+				&Info{
+					Type: model.AuthenticatorTypePasskey,
+					Kind: KindPrimary,
+					Claims: map[string]interface{}{
+						AuthenticatorClaimPasskeyCredentialID: "credential1",
+					},
+				},
+				&Info{
+					Type: model.AuthenticatorTypePasskey,
+					Kind: KindPrimary,
+					Claims: map[string]interface{}{
+						AuthenticatorClaimPasskeyCredentialID: "credential2",
+					},
+				},
+			},
 		}
 
 		for _, c := range cases {
@@ -11,3 +11,10 @@ const (
 	// AuthenticatorClaimOOBOTPPhone is a claim with string value for OOB OTP phone channel.
 	AuthenticatorClaimOOBOTPPhone string = "https://authgear.com/claims/oob_otp/phone"
 )
+
+const (
+	// AuthenticatorClaimPasskeyCredentialID is a claim with string value for the base64url-encoded passkey credential ID.
+	AuthenticatorClaimPasskeyCredentialID string = "https://authgear.com/claims/passkey/credential_id"
+	// AuthenticatorClaimPasskeyDisplayName is a claim with string value for passkey display name.
+	AuthenticatorClaimPasskeyDisplayName string = "https://authgear.com/claims/passkey/display_name"
+)
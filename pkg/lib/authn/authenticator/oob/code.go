@@ -2,10 +2,50 @@ package oob
 
 import (
 	"time"
+
+	"github.com/authgear/authgear-server/pkg/util/base32"
+	"github.com/authgear/authgear-server/pkg/util/rand"
 )
 
 type Code struct {
 	AuthenticatorID string    `json:"authenticator_id"`
 	Code            string    `json:"code"`
 	ExpireAt        time.Time `json:"expire_at"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	// WebSessionID is the web session of the interaction requesting the code.
+	WebSessionID string `json:"web_session_id,omitempty"`
+	// MagicLinkToken is the secret embedded in the magic link.
+	// It is cleared once the magic link is used.
+	MagicLinkToken string `json:"magic_link_token,omitempty"`
+
+	// RequestIP and RequestUserAgent identify the user agent requesting the code.
+	// They are shown when the magic link is opened,
+	// so that the user can tell whether the request is made by themselves.
+	RequestIP        string `json:"request_ip,omitempty"`
+	RequestUserAgent string `json:"request_user_agent,omitempty"`
+}
+
+type CodeOptions struct {
+	WebSessionID     string
+	WithMagicLink    bool
+	RequestIP        string
+	RequestUserAgent string
+}
+
+// IsReusableBy reports whether the code can be sent again for the request.
+// A magic link delivers the code to the web session of the code,
+// so a code with a magic link is not shared with other requests.
+func (c *Code) IsReusableBy(now time.Time, opts CodeOptions) bool {
+	if now.After(c.ExpireAt) {
+		return false
+	}
+	if opts.WithMagicLink {
+		return c.WebSessionID == opts.WebSessionID && c.MagicLinkToken != ""
+	}
+	return c.MagicLinkToken == ""
+}
+
+func NewMagicLinkToken() string {
+	return rand.StringWithAlphabet(32, base32.Alphabet, rand.SecureRand)
 }
@@ -8,3 +8,4 @@ var InvalidOOBCode = apierrors.Forbidden.WithReason("InvalidOOBCode")
 
 var ErrCodeNotFound = InvalidOOBCode.NewWithCause("oob code is expired or invalid", apierrors.StringCause("CodeNotFound"))
 var ErrInvalidCode = InvalidOOBCode.NewWithCause("invalid oob code", apierrors.StringCause("InvalidOOBCode"))
+var ErrInvalidMagicLink = InvalidOOBCode.NewWithCause("invalid magic link", apierrors.StringCause("InvalidMagicLink"))
@@ -1,6 +1,7 @@
 package oob
 
 import (
+	"crypto/subtle"
 	"errors"
 	"sort"
 	"time"
@@ -15,6 +16,7 @@ import (
 
 type CodeStore interface {
 	Create(code *Code) error
+	Update(code *Code) error
 	Get(authenticatorID string) (*Code, error)
 	Delete(authenticatorID string) error
 }
@@ -84,13 +86,24 @@ func (p *Provider) GetCode(authenticatorID string) (*Code, error) {
 	return p.CodeStore.Get(authenticatorID)
 }
 
-func (p *Provider) CreateCode(authenticatorID string) (*Code, error) {
+// CreateCode creates a new code for the authenticator, replacing the existing one.
+// If opts.WithMagicLink is true, a magic link token is also generated so that
+// the code can be delivered to the web session by opening a link.
+func (p *Provider) CreateCode(authenticatorID string, opts CodeOptions) (*Code, error) {
 	code := secretcode.OOBOTPSecretCode.Generate()
+	now := p.Clock.NowUTC()
 	codeModel := &Code{
 		AuthenticatorID: authenticatorID,
 		Code:            code,
 		// TODO(oob): Expiry should be configurable
-		ExpireAt: p.Clock.NowUTC().Add(time.Duration(3600) * time.Second),
+		ExpireAt:         now.Add(time.Duration(3600) * time.Second),
+		CreatedAt:        now,
+		WebSessionID:     opts.WebSessionID,
+		RequestIP:        opts.RequestIP,
+		RequestUserAgent: opts.RequestUserAgent,
+	}
+	if opts.WithMagicLink && opts.WebSessionID != "" {
+		codeModel.MagicLinkToken = NewMagicLinkToken()
 	}
 
 	err := p.CodeStore.Create(codeModel)
@@ -120,6 +133,42 @@ func (p *Provider) VerifyCode(authenticatorID string, code string) (*Code, error
 	return codeModel, nil
 }
 
+// GetMagicLink returns the code of a magic link without using the link.
+func (p *Provider) GetMagicLink(authenticatorID string, token string) (*Code, error) {
+	codeModel, err := p.CodeStore.Get(authenticatorID)
+	if errors.Is(err, ErrCodeNotFound) {
+		return nil, ErrInvalidMagicLink
+	} else if err != nil {
+		return nil, err
+	}
+
+	if codeModel.MagicLinkToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(codeModel.MagicLinkToken)) != 1 {
+		return nil, ErrInvalidMagicLink
+	}
+
+	return codeModel, nil
+}
+
+// VerifyMagicLink verifies the token of a magic link and returns the code.
+// The magic link is invalidated afterwards, while the code remains valid
+// until it is used to complete the authentication.
+func (p *Provider) VerifyMagicLink(authenticatorID string, token string) (*Code, error) {
+	codeModel, err := p.GetMagicLink(authenticatorID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	codeModel.MagicLinkToken = ""
+	err = p.CodeStore.Update(codeModel)
+	if errors.Is(err, ErrCodeNotFound) {
+		return nil, ErrInvalidMagicLink
+	} else if err != nil {
+		return nil, err
+	}
+
+	return codeModel, nil
+}
+
 func sortAuthenticators(as []*Authenticator) {
 	sort.Slice(as, func(i, j int) bool {
 		return as[i].CreatedAt.Before(as[j].CreatedAt)
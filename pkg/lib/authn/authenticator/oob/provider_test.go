@@ -0,0 +1,141 @@
+package oob
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+type fakeCodeStore struct {
+	Codes map[string]*Code
+}
+
+func (s *fakeCodeStore) Create(code *Code) error {
+	c := *code
+	s.Codes[code.AuthenticatorID] = &c
+	return nil
+}
+
+func (s *fakeCodeStore) Update(code *Code) error {
+	if _, ok := s.Codes[code.AuthenticatorID]; !ok {
+		return ErrCodeNotFound
+	}
+	c := *code
+	s.Codes[code.AuthenticatorID] = &c
+	return nil
+}
+
+func (s *fakeCodeStore) Get(authenticatorID string) (*Code, error) {
+	code, ok := s.Codes[authenticatorID]
+	if !ok {
+		return nil, ErrCodeNotFound
+	}
+	c := *code
+	return &c, nil
+}
+
+func (s *fakeCodeStore) Delete(authenticatorID string) error {
+	delete(s.Codes, authenticatorID)
+	return nil
+}
+
+func TestProviderCode(t *testing.T) {
+	Convey("Provider code", t, func() {
+		store := &fakeCodeStore{Codes: map[string]*Code{}}
+		clk := clock.NewMockClockAt("2020-01-01T00:00:00Z")
+		p := &Provider{
+			CodeStore: store,
+			Clock:     clk,
+		}
+
+		Convey("should replace the existing code", func() {
+			code1, err := p.CreateCode("authenticator-id", CodeOptions{WebSessionID: "session-1", WithMagicLink: true})
+			So(err, ShouldBeNil)
+			code2, err := p.CreateCode("authenticator-id", CodeOptions{WebSessionID: "session-2", WithMagicLink: true})
+			So(err, ShouldBeNil)
+
+			_, err = p.GetMagicLink("authenticator-id", code1.MagicLinkToken)
+			So(err, ShouldBeError, ErrInvalidMagicLink)
+
+			code, err := p.GetMagicLink("authenticator-id", code2.MagicLinkToken)
+			So(err, ShouldBeNil)
+			So(code.WebSessionID, ShouldEqual, "session-2")
+		})
+
+		Convey("should record the requesting user agent", func() {
+			_, err := p.CreateCode("authenticator-id", CodeOptions{
+				WebSessionID:     "session-1",
+				WithMagicLink:    true,
+				RequestIP:        "127.0.0.1",
+				RequestUserAgent: "Mozilla/5.0",
+			})
+			So(err, ShouldBeNil)
+
+			code := store.Codes["authenticator-id"]
+			So(code.RequestIP, ShouldEqual, "127.0.0.1")
+			So(code.RequestUserAgent, ShouldEqual, "Mozilla/5.0")
+			So(code.CreatedAt, ShouldResemble, clk.NowUTC())
+		})
+
+		Convey("should not generate magic link without web session", func() {
+			code, err := p.CreateCode("authenticator-id", CodeOptions{WithMagicLink: true})
+			So(err, ShouldBeNil)
+			So(code.MagicLinkToken, ShouldEqual, "")
+		})
+
+		Convey("should use magic link once", func() {
+			code, err := p.CreateCode("authenticator-id", CodeOptions{WebSessionID: "session-1", WithMagicLink: true})
+			So(err, ShouldBeNil)
+
+			_, err = p.GetMagicLink("authenticator-id", code.MagicLinkToken)
+			So(err, ShouldBeNil)
+
+			verified, err := p.VerifyMagicLink("authenticator-id", code.MagicLinkToken)
+			So(err, ShouldBeNil)
+			So(verified.Code, ShouldEqual, code.Code)
+
+			_, err = p.VerifyMagicLink("authenticator-id", code.MagicLinkToken)
+			So(err, ShouldBeError, ErrInvalidMagicLink)
+		})
+	})
+}
+
+func TestCodeIsReusableBy(t *testing.T) {
+	Convey("Code.IsReusableBy", t, func() {
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		code := &Code{
+			ExpireAt:       now.Add(time.Hour),
+			WebSessionID:   "session-1",
+			MagicLinkToken: "token",
+		}
+
+		Convey("should not reuse expired code", func() {
+			So(code.IsReusableBy(now.Add(2*time.Hour), CodeOptions{WebSessionID: "session-1", WithMagicLink: true}), ShouldBeFalse)
+		})
+
+		Convey("should reuse magic link in the same web session", func() {
+			So(code.IsReusableBy(now, CodeOptions{WebSessionID: "session-1", WithMagicLink: true}), ShouldBeTrue)
+		})
+
+		Convey("should not reuse magic link in another web session", func() {
+			So(code.IsReusableBy(now, CodeOptions{WebSessionID: "session-2", WithMagicLink: true}), ShouldBeFalse)
+		})
+
+		Convey("should not reuse used magic link", func() {
+			code.MagicLinkToken = ""
+			So(code.IsReusableBy(now, CodeOptions{WebSessionID: "session-1", WithMagicLink: true}), ShouldBeFalse)
+		})
+
+		Convey("should not send magic link without magic link", func() {
+			So(code.IsReusableBy(now, CodeOptions{WebSessionID: "session-1"}), ShouldBeFalse)
+		})
+
+		Convey("should reuse code without magic link", func() {
+			code.MagicLinkToken = ""
+			So(code.IsReusableBy(now, CodeOptions{WebSessionID: "session-2"}), ShouldBeTrue)
+		})
+	})
+}
@@ -1,6 +1,8 @@
 package oob
 
 import (
+	"net/url"
+
 	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn/otp"
 )
@@ -10,21 +12,29 @@ type OTPMessageSender interface {
 	SendSMS(phone string, opts otp.SendOptions) error
 }
 
+type WebAppURLProvider interface {
+	MagicLinkURL(authenticatorID string, token string) *url.URL
+}
+
 type CodeSender struct {
 	OTPMessageSender OTPMessageSender
+	WebAppURLs       WebAppURLProvider
 }
 
 func (s *CodeSender) SendCode(
 	channel model.AuthenticatorOOBChannel,
 	target string,
-	code string,
+	code *Code,
 	messageType otp.MessageType,
 ) (err error) {
 	opts := otp.SendOptions{
-		OTP:         code,
-		URL:         "", // TODO(interaction): Include login link in email.
+		OTP:         code.Code,
 		MessageType: messageType,
 	}
+	if code.MagicLinkToken != "" {
+		opts.URL = s.WebAppURLs.MagicLinkURL(code.AuthenticatorID, code.MagicLinkToken).String()
+	}
+
 	switch channel {
 	case model.AuthenticatorOOBChannelEmail:
 		err = s.OTPMessageSender.SendEmail(target, opts)
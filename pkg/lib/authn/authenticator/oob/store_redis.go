@@ -30,10 +30,9 @@ func (s *StoreRedis) Create(code *Code) error {
 		codeKey := redisCodeKey(s.AppID, code.AuthenticatorID)
 		ttl := code.ExpireAt.Sub(s.Clock.NowUTC())
 
-		_, err := conn.SetNX(ctx, codeKey, data, ttl).Result()
-		if errors.Is(err, goredis.Nil) {
-			return errors.New("duplicated code")
-		} else if err != nil {
+		// The existing code of the authenticator is replaced.
+		_, err := conn.Set(ctx, codeKey, data, ttl).Result()
+		if err != nil {
 			return err
 		}
 
@@ -41,6 +40,29 @@ func (s *StoreRedis) Create(code *Code) error {
 	})
 }
 
+func (s *StoreRedis) Update(code *Code) error {
+	ctx := context.Background()
+	data, err := json.Marshal(code)
+	if err != nil {
+		return err
+	}
+
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		codeKey := redisCodeKey(s.AppID, code.AuthenticatorID)
+		ttl := code.ExpireAt.Sub(s.Clock.NowUTC())
+
+		updated, err := conn.SetXX(ctx, codeKey, data, ttl).Result()
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return ErrCodeNotFound
+		}
+
+		return nil
+	})
+}
+
 func (s *StoreRedis) Get(authenticatorID string) (*Code, error) {
 	ctx := context.Background()
 	key := redisCodeKey(s.AppID, authenticatorID)
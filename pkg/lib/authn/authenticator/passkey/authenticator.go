@@ -0,0 +1,18 @@
+package passkey
+
+import (
+	"time"
+)
+
+type Authenticator struct {
+	ID           string
+	IsDefault    bool
+	Kind         string
+	UserID       string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	CredentialID string
+	PublicKey    []byte
+	SignCount    int64
+	DisplayName  string
+}
@@ -0,0 +1,20 @@
+package passkey
+
+import (
+	"time"
+)
+
+type ChallengePurpose string
+
+const (
+	ChallengePurposeRegistration   ChallengePurpose = "registration"
+	ChallengePurposeAuthentication ChallengePurpose = "authentication"
+)
+
+const ChallengeLifetime = 5 * time.Minute
+
+type Challenge struct {
+	Challenge string           `json:"challenge"`
+	Purpose   ChallengePurpose `json:"purpose"`
+	ExpireAt  time.Time        `json:"expire_at"`
+}
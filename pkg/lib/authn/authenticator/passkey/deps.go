@@ -0,0 +1,12 @@
+package passkey
+
+import (
+	"github.com/google/wire"
+)
+
+var DependencySet = wire.NewSet(
+	wire.Struct(new(Store), "*"),
+	wire.Struct(new(Provider), "*"),
+	wire.Struct(new(ChallengeStoreRedis), "*"),
+	wire.Bind(new(ChallengeStore), new(*ChallengeStoreRedis)),
+)
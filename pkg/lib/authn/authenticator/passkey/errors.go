@@ -0,0 +1,8 @@
+package passkey
+
+import (
+	"errors"
+)
+
+var ErrInvalidCredential = errors.New("invalid passkey credential")
+var ErrChallengeNotFound = errors.New("passkey challenge not found")
@@ -0,0 +1,208 @@
+package passkey
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/uuid"
+	"github.com/authgear/authgear-server/pkg/util/webauthn"
+)
+
+type Provider struct {
+	Store          *Store
+	ChallengeStore ChallengeStore
+	Config         *config.AuthenticatorPasskeyConfig
+	HTTPConfig     *config.HTTPConfig
+	Clock          clock.Clock
+}
+
+func (p *Provider) Get(userID string, id string) (*Authenticator, error) {
+	return p.Store.Get(userID, id)
+}
+
+func (p *Provider) GetMany(ids []string) ([]*Authenticator, error) {
+	return p.Store.GetMany(ids)
+}
+
+func (p *Provider) Delete(a *Authenticator) error {
+	return p.Store.Delete(a.ID)
+}
+
+func (p *Provider) List(userID string) ([]*Authenticator, error) {
+	authenticators, err := p.Store.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sortAuthenticators(authenticators)
+	return authenticators, nil
+}
+
+// CreationOptions starts a registration ceremony.
+// Existing credentials are excluded so that the same device is not registered twice.
+func (p *Provider) CreationOptions(userID string, userName string, excludeCredentialIDs []string) (*webauthn.CreationOptions, error) {
+	challenge, err := p.newChallenge(ChallengePurposeRegistration)
+	if err != nil {
+		return nil, err
+	}
+
+	user := webauthn.UserEntity{
+		ID:          webauthn.EncodeBase64URL([]byte(userID)),
+		Name:        userName,
+		DisplayName: userName,
+	}
+	options := p.relyingParty().CreationOptions(challenge, user, decodeCredentialIDs(excludeCredentialIDs), string(p.Config.UserVerification))
+	options.Timeout = int(ChallengeLifetime.Milliseconds())
+	return options, nil
+}
+
+// RequestOptions starts an authentication ceremony with the allowed credentials.
+func (p *Provider) RequestOptions(allowCredentialIDs []string) (*webauthn.RequestOptions, error) {
+	challenge, err := p.newChallenge(ChallengePurposeAuthentication)
+	if err != nil {
+		return nil, err
+	}
+
+	options := p.relyingParty().RequestOptions(challenge, decodeCredentialIDs(allowCredentialIDs), string(p.Config.UserVerification))
+	options.Timeout = int(ChallengeLifetime.Milliseconds())
+	return options, nil
+}
+
+// New verifies the attestation response of a registration ceremony.
+func (p *Provider) New(userID string, attestationResponse string, displayName string, isDefault bool, kind string) (*Authenticator, error) {
+	resp, err := webauthn.ParseAttestationResponse([]byte(attestationResponse))
+	if err != nil {
+		return nil, ErrInvalidCredential
+	}
+
+	credential, err := p.relyingParty().VerifyRegistration(resp, resp.ClientData.Challenge, p.requireUserVerification())
+	if errors.Is(err, webauthn.ErrInvalidResponse) {
+		return nil, ErrInvalidCredential
+	} else if err != nil {
+		return nil, err
+	}
+
+	// The challenge is consumed only after a successful verification,
+	// so that the ceremony can be retried with the same challenge.
+	err = p.consumeChallenge(resp.ClientData.Challenge, ChallengePurposeRegistration)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Authenticator{
+		ID:           uuid.New(),
+		UserID:       userID,
+		CredentialID: webauthn.EncodeBase64URL(credential.ID),
+		PublicKey:    credential.PublicKey,
+		SignCount:    int64(credential.SignCount),
+		DisplayName:  displayName,
+		IsDefault:    isDefault,
+		Kind:         kind,
+	}
+	return a, nil
+}
+
+func (p *Provider) Create(a *Authenticator) error {
+	now := p.Clock.NowUTC()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	return p.Store.Create(a)
+}
+
+// Authenticate verifies the assertion response of an authentication ceremony,
+// and records the new sign count of the passkey.
+func (p *Provider) Authenticate(a *Authenticator, assertionResponse string) error {
+	resp, err := webauthn.ParseAssertionResponse([]byte(assertionResponse))
+	if err != nil {
+		return ErrInvalidCredential
+	}
+
+	credential := &webauthn.Credential{
+		PublicKey: a.PublicKey,
+		SignCount: uint32(a.SignCount),
+	}
+	credential.ID, err = webauthn.DecodeBase64URL(a.CredentialID)
+	if err != nil {
+		return fmt.Errorf("passkey: malformed credential ID: %w", err)
+	}
+
+	signCount, err := p.relyingParty().VerifyAssertion(resp, resp.ClientData.Challenge, credential, p.requireUserVerification())
+	if errors.Is(err, webauthn.ErrInvalidResponse) || errors.Is(err, webauthn.ErrSignCountNotIncreased) {
+		return ErrInvalidCredential
+	} else if err != nil {
+		return err
+	}
+
+	err = p.consumeChallenge(resp.ClientData.Challenge, ChallengePurposeAuthentication)
+	if err != nil {
+		return err
+	}
+
+	a.SignCount = int64(signCount)
+	a.UpdatedAt = p.Clock.NowUTC()
+	return p.Store.UpdateSignCount(a)
+}
+
+func (p *Provider) relyingParty() *webauthn.RelyingParty {
+	u, err := url.Parse(p.HTTPConfig.PublicOrigin)
+	if err != nil {
+		panic(fmt.Errorf("passkey: invalid public origin: %w", err))
+	}
+	return &webauthn.RelyingParty{
+		ID:      u.Hostname(),
+		Name:    u.Hostname(),
+		Origins: []string{u.Scheme + "://" + u.Host},
+	}
+}
+
+func (p *Provider) requireUserVerification() bool {
+	return p.Config.UserVerification == config.PasskeyUserVerificationRequired
+}
+
+func (p *Provider) newChallenge(purpose ChallengePurpose) (string, error) {
+	c := &Challenge{
+		Challenge: webauthn.NewChallenge(),
+		Purpose:   purpose,
+		ExpireAt:  p.Clock.NowUTC().Add(ChallengeLifetime),
+	}
+	err := p.ChallengeStore.Create(c)
+	if err != nil {
+		return "", err
+	}
+	return c.Challenge, nil
+}
+
+func (p *Provider) consumeChallenge(challenge string, purpose ChallengePurpose) error {
+	c, err := p.ChallengeStore.Consume(challenge)
+	if errors.Is(err, ErrChallengeNotFound) {
+		return ErrInvalidCredential
+	} else if err != nil {
+		return err
+	}
+	if c.Purpose != purpose {
+		return ErrInvalidCredential
+	}
+	return nil
+}
+
+func decodeCredentialIDs(credentialIDs []string) [][]byte {
+	var ids [][]byte
+	for _, credentialID := range credentialIDs {
+		id, err := webauthn.DecodeBase64URL(credentialID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func sortAuthenticators(as []*Authenticator) {
+	sort.Slice(as, func(i, j int) bool {
+		return as[i].CreatedAt.Before(as[j].CreatedAt)
+	})
+}
@@ -0,0 +1,79 @@
+package passkey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/redis/appredis"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+type ChallengeStore interface {
+	Create(challenge *Challenge) error
+	Consume(challenge string) (*Challenge, error)
+}
+
+type ChallengeStoreRedis struct {
+	Redis *appredis.Handle
+	AppID config.AppID
+	Clock clock.Clock
+}
+
+func (s *ChallengeStoreRedis) Create(challenge *Challenge) error {
+	ctx := context.Background()
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		key := redisChallengeKey(s.AppID, challenge.Challenge)
+		ttl := challenge.ExpireAt.Sub(s.Clock.NowUTC())
+
+		ok, err := conn.SetNX(ctx, key, data, ttl).Result()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("duplicated challenge")
+		}
+
+		return nil
+	})
+}
+
+// Consume gets and deletes the challenge, so that each challenge can be used once only.
+func (s *ChallengeStoreRedis) Consume(challenge string) (*Challenge, error) {
+	ctx := context.Background()
+	key := redisChallengeKey(s.AppID, challenge)
+	var c *Challenge
+	err := s.Redis.WithConn(func(conn *goredis.Conn) error {
+		data, err := conn.Get(ctx, key).Bytes()
+		if errors.Is(err, goredis.Nil) {
+			return ErrChallengeNotFound
+		} else if err != nil {
+			return err
+		}
+
+		n, err := conn.Del(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		// Another request consumed the challenge concurrently.
+		if n == 0 {
+			return ErrChallengeNotFound
+		}
+
+		return json.Unmarshal(data, &c)
+	})
+	return c, err
+}
+
+func redisChallengeKey(appID config.AppID, challenge string) string {
+	return fmt.Sprintf("app:%s:passkey-challenge:%s", appID, challenge)
+}
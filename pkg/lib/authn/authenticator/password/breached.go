@@ -0,0 +1,205 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/resource"
+)
+
+type ResourceManager interface {
+	Read(desc resource.Descriptor, view resource.View) (interface{}, error)
+	ReadEffectiveResourceCached(desc resource.Descriptor) (interface{}, error)
+}
+
+// BreachedPasswordsTXT is a list of SHA-1 hashes of breached passwords, one hash per line.
+// A hash may be followed by a colon and the number of occurrences,
+// which is the format of the password list of Have I Been Pwned.
+// It is parsed once per version of the app resources.
+var BreachedPasswordsTXT = resource.RegisterResource(&resource.NewlineJoinedDescriptor{
+	Path: "breached_passwords.txt",
+	Parse: func(data []byte) (interface{}, error) {
+		return ParseBreachedPasswordList(data)
+	},
+})
+
+// breachedPasswordIndexSize is the number of buckets of the prefix index,
+// which is indexed by the first 2 bytes of the hash.
+const breachedPasswordIndexSize = 1 << 16
+
+type BreachedPasswordList struct {
+	// hashes is sorted so that it can be binary searched.
+	hashes [][sha1.Size]byte
+	// index[p] is the position in hashes of the first hash with prefix p or greater.
+	// The hashes with prefix p are hashes[index[p]:index[p+1]].
+	index []int
+}
+
+func breachedPasswordPrefix(hash [sha1.Size]byte) int {
+	return int(hash[0])<<8 | int(hash[1])
+}
+
+func ParseBreachedPasswordList(data []byte) (*BreachedPasswordList, error) {
+	list := &BreachedPasswordList{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if i := strings.IndexByte(text, ':'); i >= 0 {
+			text = text[:i]
+		}
+
+		var hash [sha1.Size]byte
+		b, err := hex.DecodeString(text)
+		if err != nil || len(b) != sha1.Size {
+			return nil, fmt.Errorf("invalid SHA-1 hash at line %d", line)
+		}
+		copy(hash[:], b)
+		list.hashes = append(list.hashes, hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list.hashes, func(i, j int) bool {
+		return bytes.Compare(list.hashes[i][:], list.hashes[j][:]) < 0
+	})
+
+	list.index = make([]int, breachedPasswordIndexSize+1)
+	i := 0
+	for p := 0; p < breachedPasswordIndexSize; p++ {
+		list.index[p] = i
+		for i < len(list.hashes) && breachedPasswordPrefix(list.hashes[i]) == p {
+			i++
+		}
+	}
+	list.index[breachedPasswordIndexSize] = len(list.hashes)
+
+	return list, nil
+}
+
+func (l *BreachedPasswordList) Contains(hash [sha1.Size]byte) bool {
+	p := breachedPasswordPrefix(hash)
+	bucket := l.hashes[l.index[p]:l.index[p+1]]
+	i := sort.Search(len(bucket), func(i int) bool {
+		return bytes.Compare(bucket[i][:], hash[:]) >= 0
+	})
+	return i < len(bucket) && bucket[i] == hash
+}
+
+// BreachedPasswordSource tells whether a password is breached by its SHA-1 hash.
+type BreachedPasswordSource interface {
+	IsBreached(hash [sha1.Size]byte) (bool, error)
+}
+
+// BreachedPasswordFileSource checks against the app resource BreachedPasswordsTXT.
+// It never reports a breach if the resource does not exist.
+// The parsed list is cached in the resource manager of the app.
+type BreachedPasswordFileSource struct {
+	Resources ResourceManager
+}
+
+func (s *BreachedPasswordFileSource) IsBreached(hash [sha1.Size]byte) (bool, error) {
+	result, err := s.Resources.ReadEffectiveResourceCached(BreachedPasswordsTXT)
+	if errors.Is(err, resource.ErrResourceNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return result.(*BreachedPasswordList).Contains(hash), nil
+}
+
+type BreachedPasswordHTTPClient struct {
+	*http.Client
+}
+
+func NewBreachedPasswordHTTPClient() BreachedPasswordHTTPClient {
+	return BreachedPasswordHTTPClient{
+		httputil.NewExternalClient(5 * time.Second),
+	}
+}
+
+// BreachedPasswordAPISource checks against an API compatible with
+// https://haveibeenpwned.com/API/v3#SearchingPwnedPasswordsByRange
+// Only the first 5 characters of the hash are sent, so the password is not disclosed.
+type BreachedPasswordAPISource struct {
+	Endpoint   string
+	HTTPClient BreachedPasswordHTTPClient
+}
+
+func (s *BreachedPasswordAPISource) IsBreached(hash [sha1.Size]byte) (bool, error) {
+	hexHash := strings.ToUpper(hex.EncodeToString(hash[:]))
+	prefix, suffix := hexHash[:5], hexHash[5:]
+
+	req, err := http.NewRequest("GET", strings.TrimSuffix(s.Endpoint, "/")+"/"+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// Padding prevents the response size from revealing the prefix.
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], suffix) {
+			continue
+		}
+		// Padding entries have a count of 0.
+		count, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return false, err
+		}
+		return count > 0, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+func newBreachedPasswordSources(
+	cfg *config.BreachedPasswordCheckConfig,
+	resources ResourceManager,
+	httpClient BreachedPasswordHTTPClient,
+) []BreachedPasswordSource {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	sources := []BreachedPasswordSource{
+		&BreachedPasswordFileSource{Resources: resources},
+	}
+	if cfg.APIEnabled {
+		sources = append(sources, &BreachedPasswordAPISource{
+			Endpoint:   cfg.APIEndpoint,
+			HTTPClient: httpClient,
+		})
+	}
+	return sources
+}
@@ -0,0 +1,201 @@
+package password
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/afero"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/util/log"
+	"github.com/authgear/authgear-server/pkg/util/resource"
+)
+
+type mockBreachedPasswordSource struct {
+	breached map[[sha1.Size]byte]bool
+	err      error
+}
+
+func (s *mockBreachedPasswordSource) IsBreached(hash [sha1.Size]byte) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	return s.breached[hash], nil
+}
+
+func TestBreachedPasswordList(t *testing.T) {
+	Convey("BreachedPasswordList", t, func() {
+		// SHA-1 of "password" and "123456"
+		list, err := ParseBreachedPasswordList([]byte(`# comment
+7C4A8D09CA3762AF61E59520943DC26494F8941B:24230577
+
+5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8
+`))
+		So(err, ShouldBeNil)
+		So(list.Contains(sha1.Sum([]byte("password"))), ShouldBeTrue)
+		So(list.Contains(sha1.Sum([]byte("123456"))), ShouldBeTrue)
+		So(list.Contains(sha1.Sum([]byte("correct horse battery staple"))), ShouldBeFalse)
+
+		_, err = ParseBreachedPasswordList([]byte("7C4A8D09CA3762AF\n"))
+		So(err, ShouldBeError, "invalid SHA-1 hash at line 1")
+
+		// Hashes sharing the prefix index bucket of "password".
+		list, err = ParseBreachedPasswordList([]byte(`5BAA0000000000000000000000000000000000FF
+5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+5BAAFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF
+FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF
+0000000000000000000000000000000000000000
+`))
+		So(err, ShouldBeNil)
+		So(list.Contains(sha1.Sum([]byte("password"))), ShouldBeTrue)
+		So(list.Contains([sha1.Size]byte{}), ShouldBeTrue)
+		var max [sha1.Size]byte
+		for i := range max {
+			max[i] = 0xFF
+		}
+		So(list.Contains(max), ShouldBeTrue)
+		So(list.Contains(sha1.Sum([]byte("123456"))), ShouldBeFalse)
+	})
+}
+
+func TestBreachedPasswordFileSource(t *testing.T) {
+	Convey("BreachedPasswordFileSource", t, func() {
+		fs := afero.NewMemMapFs()
+		registry := &resource.Registry{}
+		registry.Register(BreachedPasswordsTXT)
+		resources := resource.NewManager(registry, []resource.Fs{
+			resource.LeveledAferoFs{Fs: fs, FsLevel: resource.FsLevelApp},
+		})
+		source := &BreachedPasswordFileSource{Resources: resources}
+
+		Convey("should not report breach if the resource does not exist", func() {
+			breached, err := source.IsBreached(sha1.Sum([]byte("password")))
+			So(err, ShouldBeNil)
+			So(breached, ShouldBeFalse)
+		})
+
+		Convey("should check against the list parsed once per resource version", func() {
+			// SHA-1 of "password"
+			_ = afero.WriteFile(fs, "breached_passwords.txt", []byte("5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8\n"), 0666)
+
+			breached, err := source.IsBreached(sha1.Sum([]byte("password")))
+			So(err, ShouldBeNil)
+			So(breached, ShouldBeTrue)
+
+			// SHA-1 of "123456"
+			_ = afero.WriteFile(fs, "breached_passwords.txt", []byte("7C4A8D09CA3762AF61E59520943DC26494F8941B\n"), 0666)
+
+			breached, err = source.IsBreached(sha1.Sum([]byte("password")))
+			So(err, ShouldBeNil)
+			So(breached, ShouldBeTrue)
+
+			source.Resources = resource.NewManager(registry, resources.Fs)
+			breached, err = source.IsBreached(sha1.Sum([]byte("password")))
+			So(err, ShouldBeNil)
+			So(breached, ShouldBeFalse)
+			breached, err = source.IsBreached(sha1.Sum([]byte("123456")))
+			So(err, ShouldBeNil)
+			So(breached, ShouldBeTrue)
+		})
+	})
+}
+
+func TestBreachedPasswordAPISource(t *testing.T) {
+	Convey("BreachedPasswordAPISource", t, func() {
+		var requestedPath string
+		var padding string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestedPath = r.URL.Path
+			padding = r.Header.Get("Add-Padding")
+			if r.URL.Path == "/range/5BAA6" {
+				// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+				fmt.Fprint(w, "003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:9659365\r\n")
+				return
+			}
+			if r.URL.Path == "/range/7C4A8" {
+				// Padding entry of SHA-1 of "123456"
+				fmt.Fprint(w, "D09CA3762AF61E59520943DC26494F8941B:0\r\n")
+				return
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		source := &BreachedPasswordAPISource{
+			Endpoint:   server.URL + "/range/",
+			HTTPClient: BreachedPasswordHTTPClient{server.Client()},
+		}
+
+		breached, err := source.IsBreached(sha1.Sum([]byte("password")))
+		So(err, ShouldBeNil)
+		So(breached, ShouldBeTrue)
+		So(requestedPath, ShouldEqual, "/range/5BAA6")
+		So(padding, ShouldEqual, "true")
+
+		breached, err = source.IsBreached(sha1.Sum([]byte("123456")))
+		So(err, ShouldBeNil)
+		So(breached, ShouldBeFalse)
+
+		_, err = source.IsBreached(sha1.Sum([]byte("correct horse battery staple")))
+		So(err, ShouldBeError, "unexpected status code: 429")
+	})
+}
+
+func TestCheckerBreachedPassword(t *testing.T) {
+	Convey("Checker breached password", t, func() {
+		source := &mockBreachedPasswordSource{
+			breached: map[[sha1.Size]byte]bool{
+				sha1.Sum([]byte("password")): true,
+			},
+		}
+		pc := &Checker{
+			BreachedPasswordCheckEnabled: true,
+			BreachedPasswordFailureMode:  config.BreachedPasswordCheckFailureModeOpen,
+			BreachedPasswordSources:      []BreachedPasswordSource{source},
+			Logger:                       Logger{log.Null},
+		}
+
+		Convey("should reject breached password", func() {
+			err := pc.ValidateNewPassword("user-id", "password")
+			So(err, ShouldBeError, "password policy violated")
+			So(apierrors.AsAPIError(err).Info["causes"], ShouldResemble, []apierrors.Cause{
+				Policy{Name: PasswordBreached},
+			})
+
+			So(pc.ValidateNewPassword("user-id", "correct horse battery staple"), ShouldBeNil)
+			So(pc.PasswordPolicy(), ShouldResemble, []Policy{{Name: PasswordBreached}})
+		})
+
+		Convey("should accept password if check fails open", func() {
+			source.err = fmt.Errorf("unavailable")
+			So(pc.ValidateNewPassword("user-id", "password"), ShouldBeNil)
+		})
+
+		Convey("should reject password if check fails closed", func() {
+			source.err = fmt.Errorf("unavailable")
+			pc.BreachedPasswordFailureMode = config.BreachedPasswordCheckFailureModeClosed
+			err := pc.ValidateNewPassword("user-id", "password")
+			So(apierrors.IsKind(err, BreachedPasswordCheckUnavailable), ShouldBeTrue)
+		})
+
+		Convey("should check current password only if forced to change", func() {
+			breached, err := pc.IsCurrentPasswordBreached("password")
+			So(err, ShouldBeNil)
+			So(breached, ShouldBeFalse)
+
+			pc.BreachedPasswordForceChangeOnLogin = true
+			breached, err = pc.IsCurrentPasswordBreached("password")
+			So(err, ShouldBeNil)
+			So(breached, ShouldBeTrue)
+
+			breached, err = pc.IsCurrentPasswordBreached("correct horse battery staple")
+			So(err, ShouldBeNil)
+			So(breached, ShouldBeFalse)
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package password
 
 import (
+	"crypto/sha1"
 	"regexp"
 	"strings"
 
@@ -121,6 +122,12 @@ type Checker struct {
 	PwHistoryDays          config.DurationDays
 	PasswordHistoryEnabled bool
 	PasswordHistoryStore   CheckerHistoryStore
+
+	BreachedPasswordCheckEnabled       bool
+	BreachedPasswordFailureMode        config.BreachedPasswordCheckFailureMode
+	BreachedPasswordForceChangeOnLogin bool
+	BreachedPasswordSources            []BreachedPasswordSource
+	Logger                             Logger
 }
 
 func (pc *Checker) policyPasswordLength() Policy {
@@ -230,6 +237,40 @@ func (pc *Checker) checkPasswordHistory(password, authID string) (*Policy, error
 	return nil, nil
 }
 
+func (pc *Checker) isPasswordBreached(password string) (bool, error) {
+	hash := sha1.Sum([]byte(password))
+	for _, source := range pc.BreachedPasswordSources {
+		breached, err := source.IsBreached(hash)
+		if err != nil {
+			return false, err
+		}
+		if breached {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (pc *Checker) checkPasswordBreached(password string) (*Policy, error) {
+	if !pc.BreachedPasswordCheckEnabled {
+		return nil, nil
+	}
+
+	breached, err := pc.isPasswordBreached(password)
+	if err != nil {
+		if pc.BreachedPasswordFailureMode == config.BreachedPasswordCheckFailureModeClosed {
+			return nil, BreachedPasswordCheckUnavailable.Wrap(err, "failed to check breached password")
+		}
+		pc.Logger.WithError(err).Warn("failed to check breached password, accepting the password")
+		return nil, nil
+	}
+
+	if breached {
+		return &Policy{Name: PasswordBreached}, nil
+	}
+	return nil, nil
+}
+
 // ValidateNewPassword should be used when the user changes their password.
 func (pc *Checker) ValidateNewPassword(userID string, plainPassword string) error {
 	var violations []apierrors.Cause
@@ -253,6 +294,12 @@ func (pc *Checker) ValidateNewPassword(userID string, plainPassword string) erro
 	}
 	check(p)
 
+	p, err = pc.checkPasswordBreached(plainPassword)
+	if err != nil {
+		return err
+	}
+	check(p)
+
 	if len(violations) == 0 {
 		return nil
 	}
@@ -284,6 +331,15 @@ func (pc *Checker) ValidateCurrentPassword(plainPassword string) error {
 	return PasswordPolicyViolated.NewWithCauses("password policy violated", violations)
 }
 
+// IsCurrentPasswordBreached should be used when the user authenticates.
+// It reports whether the user should be forced to change the breached password.
+func (pc *Checker) IsCurrentPasswordBreached(plainPassword string) (bool, error) {
+	if !pc.BreachedPasswordCheckEnabled || !pc.BreachedPasswordForceChangeOnLogin {
+		return false, nil
+	}
+	return pc.isPasswordBreached(plainPassword)
+}
+
 // PasswordPolicy outputs a list of PasswordPolicy to reflect the password policy.
 func (pc *Checker) PasswordPolicy() (out []Policy) {
 	if pc.PwMinLength > 0 {
@@ -310,6 +366,9 @@ func (pc *Checker) PasswordPolicy() (out []Policy) {
 	if pc.shouldCheckPasswordHistory() {
 		out = append(out, pc.policyPasswordHistory())
 	}
+	if pc.BreachedPasswordCheckEnabled {
+		out = append(out, Policy{Name: PasswordBreached})
+	}
 	if out == nil {
 		out = []Policy{}
 	}
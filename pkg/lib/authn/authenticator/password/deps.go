@@ -4,9 +4,17 @@ import (
 	"github.com/google/wire"
 
 	"github.com/authgear/authgear-server/pkg/lib/config"
+	pwd "github.com/authgear/authgear-server/pkg/util/password"
 )
 
-func ProvideChecker(cfg *config.AuthenticatorPasswordConfig, s CheckerHistoryStore) *Checker {
+func ProvideChecker(
+	cfg *config.AuthenticatorPasswordConfig,
+	s CheckerHistoryStore,
+	resources ResourceManager,
+	httpClient BreachedPasswordHTTPClient,
+	logger Logger,
+) *Checker {
+	breachedCfg := cfg.Policy.BreachedPasswordCheck
 	return &Checker{
 		PwMinLength:            *cfg.Policy.MinLength,
 		PwUppercaseRequired:    cfg.Policy.UppercaseRequired,
@@ -19,6 +27,32 @@ func ProvideChecker(cfg *config.AuthenticatorPasswordConfig, s CheckerHistorySto
 		PwHistoryDays:          cfg.Policy.HistoryDays,
 		PasswordHistoryEnabled: cfg.Policy.IsEnabled(),
 		PasswordHistoryStore:   s,
+
+		BreachedPasswordCheckEnabled:       breachedCfg.Enabled,
+		BreachedPasswordFailureMode:        breachedCfg.FailureMode,
+		BreachedPasswordForceChangeOnLogin: breachedCfg.ForceChangeOnLogin,
+		BreachedPasswordSources:            newBreachedPasswordSources(breachedCfg, resources, httpClient),
+		Logger:                             logger,
+	}
+}
+
+func ProvideHasher(cfg *config.AuthenticatorPasswordConfig) *pwd.Hasher {
+	c := cfg.Hashing
+	switch c.Algorithm {
+	case config.PasswordHashingAlgorithmArgon2id:
+		return pwd.NewArgon2idHasher(
+			uint32(c.Argon2id.MemoryKiB),
+			uint32(c.Argon2id.Iterations),
+			uint8(c.Argon2id.Parallelism),
+		)
+	case config.PasswordHashingAlgorithmScrypt:
+		return pwd.NewScryptHasher(
+			c.Scrypt.LogN,
+			c.Scrypt.BlockSize,
+			c.Scrypt.Parallelism,
+		)
+	default:
+		return pwd.NewBcryptSHA512Hasher(c.BcryptSHA512.Cost)
 	}
 }
 
@@ -29,6 +63,8 @@ var DependencySet = wire.NewSet(
 	NewHousekeeperLogger,
 	wire.Struct(new(Housekeeper), "*"),
 	ProvideChecker,
+	ProvideHasher,
+	NewBreachedPasswordHTTPClient,
 	wire.Struct(new(HistoryStore), "*"),
 	wire.Bind(new(CheckerHistoryStore), new(*HistoryStore)),
 )
@@ -5,6 +5,8 @@ import (
 )
 
 var PasswordPolicyViolated apierrors.Kind = apierrors.Invalid.WithReason("PasswordPolicyViolated")
+var BreachedPasswordCheckUnavailable = apierrors.ServiceUnavailable.WithReason("BreachedPasswordCheckUnavailable")
+var InvalidPasswordHash = apierrors.Invalid.WithReason("InvalidPasswordHash")
 
 type PolicyName string
 
@@ -26,6 +28,8 @@ const (
 	PasswordBelowGuessableLevel PolicyName = "PasswordBelowGuessableLevel"
 	// PasswordReused is self-explanatory
 	PasswordReused PolicyName = "PasswordReused"
+	// PasswordBreached means the password is found in known data breaches
+	PasswordBreached PolicyName = "PasswordBreached"
 	// PasswordExpired is self-explanatory
 	PasswordExpired PolicyName = "PasswordExpired"
 )
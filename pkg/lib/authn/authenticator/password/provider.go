@@ -22,6 +22,7 @@ type Provider struct {
 	Logger          Logger
 	PasswordHistory *HistoryStore
 	PasswordChecker *Checker
+	PasswordHasher  *pwd.Hasher
 	Housekeeper     *Housekeeper
 }
 
@@ -62,6 +63,24 @@ func (p *Provider) New(userID string, password string, isDefault bool, kind stri
 	return authen, nil
 }
 
+// NewWithHash returns an authenticator of a password hash imported from other systems.
+// The hash is verified when the user authenticates,
+// and is then migrated to the configured format.
+func (p *Provider) NewWithHash(userID string, passwordHash []byte, isDefault bool, kind string) (*Authenticator, error) {
+	err := pwd.ValidateHash(passwordHash)
+	if err != nil {
+		return nil, InvalidPasswordHash.Errorf("invalid password hash: %w", err)
+	}
+
+	return &Authenticator{
+		ID:           uuid.New(),
+		UserID:       userID,
+		IsDefault:    isDefault,
+		Kind:         kind,
+		PasswordHash: passwordHash,
+	}, nil
+}
+
 // WithPassword return new authenticator pointer if password is changed
 // Otherwise original authenticator will be returned
 func (p *Provider) WithPassword(a *Authenticator, password string) (*Authenticator, error) {
@@ -104,7 +123,7 @@ func (p *Provider) Authenticate(a *Authenticator, password string) (requireUpdat
 		return
 	}
 
-	migrated, err := pwd.TryMigrate([]byte(password), &a.PasswordHash)
+	migrated, err := p.PasswordHasher.TryMigrate([]byte(password), &a.PasswordHash)
 	if err != nil {
 		p.Logger.WithError(err).WithField("authenticator_id", a.ID).
 			Warn("Failed to migrate password")
@@ -126,6 +145,17 @@ func (p *Provider) Authenticate(a *Authenticator, password string) (requireUpdat
 		}
 	}
 
+	if !requireUpdate {
+		breached, checkErr := p.PasswordChecker.IsCurrentPasswordBreached(password)
+		if checkErr != nil {
+			// Do not block authentication if the check cannot be performed.
+			p.Logger.WithError(checkErr).WithField("authenticator_id", a.ID).
+				Warn("Failed to check breached password")
+		} else if breached {
+			requireUpdate = true
+		}
+	}
+
 	return
 }
 
@@ -158,7 +188,7 @@ func sortAuthenticators(as []*Authenticator) {
 }
 
 func (p *Provider) populatePasswordHash(a *Authenticator, password string) *Authenticator {
-	hash, err := pwd.Hash([]byte(password))
+	hash, err := p.PasswordHasher.Hash([]byte(password))
 	if err != nil {
 		panic(fmt.Errorf("password: failed to hash password: %w", err))
 	}
@@ -0,0 +1,34 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProviderNewWithHash(t *testing.T) {
+	Convey("Provider.NewWithHash", t, func() {
+		p := &Provider{}
+
+		Convey("should accept well-formed hash", func() {
+			h := "$pbkdf2-sha256$i=1000$c2FsdHNhbHRzYWx0c2FsdA$8nX7hwFEzIB8aPajJTYK8weHQc5Ngz0pFVAKvSu4jQA"
+			a, err := p.NewWithHash("user-id", []byte(h), false, "primary")
+			So(err, ShouldBeNil)
+			So(a.UserID, ShouldEqual, "user-id")
+			So(string(a.PasswordHash), ShouldEqual, h)
+		})
+
+		Convey("should reject hash which would match any password", func() {
+			for _, h := range []string{
+				"$scrypt$ln=15,r=8,p=1$c2FsdA$",
+				"$argon2i$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$",
+				"$pbkdf2-sha256$i=1000$c2FsdA$",
+			} {
+				_, err := p.NewWithHash("user-id", []byte(h), false, "primary")
+				So(apierrors.AsAPIError(err).Reason, ShouldEqual, "InvalidPasswordHash")
+			}
+		})
+	})
+}
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"encoding/base64"
+
 	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/passkey"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/totp"
 )
@@ -101,3 +104,36 @@ func oobotpFromAuthenticatorInfo(a *authenticator.Info) *oob.Authenticator {
 		Kind:                 string(a.Kind),
 	}
 }
+
+func passkeyToAuthenticatorInfo(p *passkey.Authenticator) *authenticator.Info {
+	return &authenticator.Info{
+		Type:      model.AuthenticatorTypePasskey,
+		ID:        p.ID,
+		UserID:    p.UserID,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+		Secret:    base64.RawURLEncoding.EncodeToString(p.PublicKey),
+		Claims: map[string]interface{}{
+			authenticator.AuthenticatorClaimPasskeyCredentialID: p.CredentialID,
+			authenticator.AuthenticatorClaimPasskeyDisplayName:  p.DisplayName,
+		},
+		IsDefault: p.IsDefault,
+		Kind:      authenticator.Kind(p.Kind),
+	}
+}
+
+func passkeyFromAuthenticatorInfo(a *authenticator.Info) *passkey.Authenticator {
+	// The sign count is not part of the info, it is always read from the store on authentication.
+	publicKey, _ := base64.RawURLEncoding.DecodeString(a.Secret)
+	return &passkey.Authenticator{
+		ID:           a.ID,
+		UserID:       a.UserID,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+		CredentialID: a.Claims[authenticator.AuthenticatorClaimPasskeyCredentialID].(string),
+		PublicKey:    publicKey,
+		DisplayName:  a.Claims[authenticator.AuthenticatorClaimPasskeyDisplayName].(string),
+		IsDefault:    a.IsDefault,
+		Kind:         string(a.Kind),
+	}
+}
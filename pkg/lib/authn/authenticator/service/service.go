@@ -6,6 +6,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/passkey"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/totp"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity"
@@ -46,16 +47,32 @@ type OOBOTPAuthenticatorProvider interface {
 	VerifyCode(authenticatorID string, code string) (*oob.Code, error)
 }
 
+type PasskeyAuthenticatorProvider interface {
+	Get(userID, id string) (*passkey.Authenticator, error)
+	GetMany(ids []string) ([]*passkey.Authenticator, error)
+	List(userID string) ([]*passkey.Authenticator, error)
+	New(userID string, attestationResponse string, displayName string, isDefault bool, kind string) (*passkey.Authenticator, error)
+	Create(*passkey.Authenticator) error
+	Delete(*passkey.Authenticator) error
+	Authenticate(a *passkey.Authenticator, assertionResponse string) error
+}
+
 type RateLimiter interface {
 	TakeToken(bucket ratelimit.Bucket) error
 }
 
+type Lockout interface {
+	Check(userID string) error
+}
+
 type Service struct {
 	Store       *Store
 	Password    PasswordAuthenticatorProvider
 	TOTP        TOTPAuthenticatorProvider
 	OOBOTP      OOBOTPAuthenticatorProvider
+	Passkey     PasskeyAuthenticatorProvider
 	RateLimiter RateLimiter
+	Lockout     Lockout
 }
 
 func (s *Service) Get(userID string, typ model.AuthenticatorType, id string) (*authenticator.Info, error) {
@@ -81,13 +98,20 @@ func (s *Service) Get(userID string, typ model.AuthenticatorType, id string) (*a
 			return nil, err
 		}
 		return oobotpToAuthenticatorInfo(o), nil
+
+	case model.AuthenticatorTypePasskey:
+		p, err := s.Passkey.Get(userID, id)
+		if err != nil {
+			return nil, err
+		}
+		return passkeyToAuthenticatorInfo(p), nil
 	}
 
 	panic("authenticator: unknown authenticator type " + typ)
 }
 
 func (s *Service) GetMany(refs []*authenticator.Ref) ([]*authenticator.Info, error) {
-	var passwordIDs, totpIDs, oobIDs []string
+	var passwordIDs, totpIDs, oobIDs, passkeyIDs []string
 	for _, ref := range refs {
 		switch ref.Type {
 		case model.AuthenticatorTypePassword:
@@ -96,6 +120,8 @@ func (s *Service) GetMany(refs []*authenticator.Ref) ([]*authenticator.Info, err
 			totpIDs = append(totpIDs, ref.ID)
 		case model.AuthenticatorTypeOOBEmail, model.AuthenticatorTypeOOBSMS:
 			oobIDs = append(oobIDs, ref.ID)
+		case model.AuthenticatorTypePasskey:
+			passkeyIDs = append(passkeyIDs, ref.ID)
 		default:
 			panic("authenticator: unknown authenticator type " + ref.Type)
 		}
@@ -127,6 +153,14 @@ func (s *Service) GetMany(refs []*authenticator.Ref) ([]*authenticator.Info, err
 		infos = append(infos, oobotpToAuthenticatorInfo(a))
 	}
 
+	pk, err := s.Passkey.GetMany(passkeyIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range pk {
+		infos = append(infos, passkeyToAuthenticatorInfo(a))
+	}
+
 	return infos, nil
 }
 
@@ -159,6 +193,15 @@ func (s *Service) List(userID string, filters ...authenticator.Filter) ([]*authe
 			ais = append(ais, oobotpToAuthenticatorInfo(a))
 		}
 	}
+	{
+		as, err := s.Passkey.List(userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range as {
+			ais = append(ais, passkeyToAuthenticatorInfo(a))
+		}
+	}
 
 	var filtered []*authenticator.Info
 	for _, a := range ais {
@@ -208,6 +251,16 @@ func (s *Service) New(spec *authenticator.Spec, secret string) (*authenticator.I
 		phone := spec.Claims[authenticator.AuthenticatorClaimOOBOTPPhone].(string)
 		o := s.OOBOTP.New(spec.UserID, model.AuthenticatorTypeOOBSMS, phone, spec.IsDefault, string(spec.Kind))
 		return oobotpToAuthenticatorInfo(o), nil
+
+	case model.AuthenticatorTypePasskey:
+		displayName, _ := spec.Claims[authenticator.AuthenticatorClaimPasskeyDisplayName].(string)
+		p, err := s.Passkey.New(spec.UserID, secret, displayName, spec.IsDefault, string(spec.Kind))
+		if errors.Is(err, passkey.ErrInvalidCredential) {
+			return nil, authenticator.ErrInvalidCredentials
+		} else if err != nil {
+			return nil, err
+		}
+		return passkeyToAuthenticatorInfo(p), nil
 	}
 
 	panic("authenticator: unknown authenticator type " + spec.Type)
@@ -261,6 +314,12 @@ func (s *Service) Create(info *authenticator.Info) error {
 			return err
 		}
 
+	case model.AuthenticatorTypePasskey:
+		a := passkeyFromAuthenticatorInfo(info)
+		if err := s.Passkey.Create(a); err != nil {
+			return err
+		}
+
 	default:
 		panic("authenticator: unknown authenticator type " + info.Type)
 	}
@@ -301,6 +360,12 @@ func (s *Service) Delete(info *authenticator.Info) error {
 		if err := s.OOBOTP.Delete(a); err != nil {
 			return err
 		}
+
+	case model.AuthenticatorTypePasskey:
+		a := passkeyFromAuthenticatorInfo(info)
+		if err := s.Passkey.Delete(a); err != nil {
+			return err
+		}
 	default:
 		panic("authenticator: delete authenticator is not supported yet for type " + info.Type)
 	}
@@ -309,6 +374,14 @@ func (s *Service) Delete(info *authenticator.Info) error {
 }
 
 func (s *Service) VerifySecret(info *authenticator.Info, secret string) (requireUpdate bool, err error) {
+	// Passkey is not subject to lockout since its assertion cannot be guessed.
+	if info.Type != model.AuthenticatorTypePasskey {
+		err = s.Lockout.Check(info.UserID)
+		if err != nil {
+			return
+		}
+	}
+
 	err = s.RateLimiter.TakeToken(AuthenticateSecretRateLimitBucket(info.UserID, info.Type))
 	if err != nil {
 		return
@@ -340,6 +413,22 @@ func (s *Service) VerifySecret(info *authenticator.Info, secret string) (require
 			return
 		}
 		return
+	case model.AuthenticatorTypePasskey:
+		// The sign count and public key are not part of the info;
+		// read the latest from the store.
+		var a *passkey.Authenticator
+		a, err = s.Passkey.Get(info.UserID, info.ID)
+		if err != nil {
+			return
+		}
+		err = s.Passkey.Authenticate(a, secret)
+		if errors.Is(err, passkey.ErrInvalidCredential) {
+			err = authenticator.ErrInvalidCredentials
+			return
+		} else if err != nil {
+			return
+		}
+		return
 	}
 
 	panic("authenticator: unhandled authenticator type " + info.Type)
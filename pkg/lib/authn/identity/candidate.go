@@ -68,6 +68,10 @@ func IsOAuthSSOProviderTypeDisabled(typ config.OAuthSSOProviderType, featureConf
 		return featureConfig.Apple.Disabled
 	case config.OAuthSSOProviderTypeWechat:
 		return featureConfig.Wechat.Disabled
+	case config.OAuthSSOProviderTypeOIDC:
+		return featureConfig.OIDC.Disabled
+	case config.OAuthSSOProviderTypeSAML:
+		return featureConfig.SAML.Disabled
 	default:
 		panic(fmt.Sprintf("node: unknown oauth sso type: %T", typ))
 	}
@@ -0,0 +1,5 @@
+package lockout
+
+import "github.com/authgear/authgear-server/pkg/api/apierrors"
+
+var ErrLocked = apierrors.TooManyRequest.WithReason("AccountLockout")
@@ -0,0 +1,130 @@
+package lockout
+
+import (
+	"math"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+// State is the lockout state of a user.
+type State struct {
+	Attempts    int
+	LockedUntil *time.Time
+}
+
+func (s *State) IsLocked() bool {
+	return s.LockedUntil != nil
+}
+
+func (s *State) ToModel() *model.AuthenticationLockout {
+	return &model.AuthenticationLockout{
+		FailedAttempts: s.Attempts,
+		IsLocked:       s.IsLocked(),
+		LockedUntil:    s.LockedUntil,
+	}
+}
+
+// Service locks a user temporarily after too many failed attempts
+// to authenticate.
+//
+// The failed attempts are counted within the history duration since the last attempt.
+// When the attempts reach the maximum, the user is locked for the minimum duration;
+// the duration is multiplied by the backoff factor for every further failed attempt,
+// up to the maximum duration.
+type Service struct {
+	Config  *config.AuthenticationLockoutConfig
+	Clock   clock.Clock
+	Storage Storage
+}
+
+func (s *Service) Get(userID string) (*State, error) {
+	if !s.Config.IsEnabled() {
+		return &State{}, nil
+	}
+
+	record, err := s.Storage.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.state(record), nil
+}
+
+// Check returns ErrLocked if the user is locked.
+func (s *Service) Check(userID string) error {
+	state, err := s.Get(userID)
+	if err != nil {
+		return err
+	}
+
+	if state.IsLocked() {
+		return ErrLocked.NewWithInfo("user is locked due to too many failed attempts", apierrors.Details{
+			"until": state.LockedUntil,
+		})
+	}
+
+	return nil
+}
+
+// MakeAttempt records a failed attempt of the user.
+func (s *Service) MakeAttempt(userID string) (*State, error) {
+	if !s.Config.IsEnabled() {
+		return &State{}, nil
+	}
+
+	// The attempts are reset when the record expires after the history duration.
+	historyDuration := s.Config.HistoryDuration.Duration()
+	record, err := s.Storage.MakeAttempt(userID, s.Clock.NowUTC(), historyDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the record until the lock expires if the lock is longer.
+	if duration := s.lockDuration(record.Attempts); duration > historyDuration {
+		err = s.Storage.ExpireAt(userID, record.LastAttemptAt.Add(duration))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.state(record), nil
+}
+
+// ClearAttempts unlocks the user and resets the failed attempts.
+func (s *Service) ClearAttempts(userID string) error {
+	return s.Storage.Clear(userID)
+}
+
+func (s *Service) state(record *Record) *State {
+	state := &State{Attempts: record.Attempts}
+
+	duration := s.lockDuration(record.Attempts)
+	if duration > 0 {
+		lockedUntil := record.LastAttemptAt.Add(duration)
+		if s.Clock.NowUTC().Before(lockedUntil) {
+			state.LockedUntil = &lockedUntil
+		}
+	}
+
+	return state
+}
+
+func (s *Service) lockDuration(attempts int) time.Duration {
+	if attempts < s.Config.MaxAttempts {
+		return 0
+	}
+
+	minDuration := s.Config.MinimumDuration.Duration()
+	maxDuration := s.Config.MaximumDuration.Duration()
+
+	factor := math.Pow(s.Config.BackoffFactor, float64(attempts-s.Config.MaxAttempts))
+	duration := float64(minDuration) * factor
+	if duration >= float64(maxDuration) {
+		return maxDuration
+	}
+	return time.Duration(duration)
+}
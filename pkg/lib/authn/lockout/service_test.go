@@ -0,0 +1,182 @@
+package lockout_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/lib/authn/lockout"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+type storageMemoryItem struct {
+	record   lockout.Record
+	expireAt time.Time
+}
+
+type storageMemory struct {
+	clock *clock.MockClock
+	items map[string]*storageMemoryItem
+}
+
+func (s *storageMemory) Get(userID string) (*lockout.Record, error) {
+	item, ok := s.items[userID]
+	if !ok || !s.clock.NowUTC().Before(item.expireAt) {
+		return &lockout.Record{}, nil
+	}
+	record := item.record
+	return &record, nil
+}
+
+func (s *storageMemory) MakeAttempt(userID string, now time.Time, ttl time.Duration) (*lockout.Record, error) {
+	record, _ := s.Get(userID)
+	record.Attempts++
+	record.LastAttemptAt = now
+	s.items[userID] = &storageMemoryItem{
+		record:   *record,
+		expireAt: now.Add(ttl),
+	}
+	return record, nil
+}
+
+func (s *storageMemory) ExpireAt(userID string, expireAt time.Time) error {
+	if item, ok := s.items[userID]; ok {
+		item.expireAt = expireAt
+	}
+	return nil
+}
+
+func (s *storageMemory) Clear(userID string) error {
+	delete(s.items, userID)
+	return nil
+}
+
+func TestService(t *testing.T) {
+	Convey("Service", t, func() {
+		clock := clock.NewMockClockAt("2006-01-02T15:04:05Z")
+		storage := &storageMemory{clock: clock, items: map[string]*storageMemoryItem{}}
+		cfg := &config.AuthenticationLockoutConfig{
+			MaxAttempts: 3,
+		}
+		cfg.SetDefaults()
+
+		s := &lockout.Service{
+			Config:  cfg,
+			Clock:   clock,
+			Storage: storage,
+		}
+
+		makeAttempts := func(n int) *lockout.State {
+			var state *lockout.State
+			var err error
+			for i := 0; i < n; i++ {
+				state, err = s.MakeAttempt("user-id")
+				So(err, ShouldBeNil)
+			}
+			return state
+		}
+
+		Convey("should lock user after max attempts", func() {
+			state := makeAttempts(2)
+			So(state.Attempts, ShouldEqual, 2)
+			So(state.IsLocked(), ShouldBeFalse)
+			So(s.Check("user-id"), ShouldBeNil)
+
+			state = makeAttempts(1)
+			So(state.Attempts, ShouldEqual, 3)
+			So(state.IsLocked(), ShouldBeTrue)
+			So(*state.LockedUntil, ShouldEqual, clock.NowUTC().Add(1*time.Minute))
+
+			err := s.Check("user-id")
+			So(apierrors.AsAPIError(err).Reason, ShouldEqual, "AccountLockout")
+
+			So(s.Check("other-user-id"), ShouldBeNil)
+		})
+
+		Convey("should unlock user automatically", func() {
+			makeAttempts(3)
+
+			clock.AdvanceSeconds(59)
+			So(s.Check("user-id"), ShouldNotBeNil)
+
+			clock.AdvanceSeconds(1)
+			So(s.Check("user-id"), ShouldBeNil)
+
+			state, err := s.Get("user-id")
+			So(err, ShouldBeNil)
+			So(state.Attempts, ShouldEqual, 3)
+			So(state.IsLocked(), ShouldBeFalse)
+		})
+
+		Convey("should lock user progressively", func() {
+			state := makeAttempts(4)
+			So(*state.LockedUntil, ShouldEqual, clock.NowUTC().Add(2*time.Minute))
+
+			state = makeAttempts(1)
+			So(*state.LockedUntil, ShouldEqual, clock.NowUTC().Add(4*time.Minute))
+
+			state = makeAttempts(10)
+			So(*state.LockedUntil, ShouldEqual, clock.NowUTC().Add(1*time.Hour))
+		})
+
+		Convey("should forget attempts after history duration", func() {
+			makeAttempts(2)
+
+			clock.AdvanceSeconds(86400)
+			state := makeAttempts(1)
+			So(state.Attempts, ShouldEqual, 1)
+			So(state.IsLocked(), ShouldBeFalse)
+		})
+
+		Convey("should keep attempts only within history duration", func() {
+			makeAttempts(2)
+			So(storage.items["user-id"].expireAt, ShouldEqual, clock.NowUTC().Add(24*time.Hour))
+
+			clock.AdvanceSeconds(86399)
+			state := makeAttempts(1)
+			So(state.Attempts, ShouldEqual, 3)
+			So(state.IsLocked(), ShouldBeTrue)
+		})
+
+		Convey("should keep attempts until the lock expires if it is longer than history duration", func() {
+			cfg.HistoryDuration = config.DurationSeconds(60)
+
+			state := makeAttempts(5)
+			So(*state.LockedUntil, ShouldEqual, clock.NowUTC().Add(4*time.Minute))
+			So(storage.items["user-id"].expireAt, ShouldEqual, clock.NowUTC().Add(4*time.Minute))
+
+			clock.AdvanceSeconds(239)
+			So(s.Check("user-id"), ShouldNotBeNil)
+
+			clock.AdvanceSeconds(1)
+			So(s.Check("user-id"), ShouldBeNil)
+			state = makeAttempts(1)
+			So(state.Attempts, ShouldEqual, 1)
+		})
+
+		Convey("should unlock user when attempts are cleared", func() {
+			makeAttempts(3)
+			So(s.Check("user-id"), ShouldNotBeNil)
+
+			err := s.ClearAttempts("user-id")
+			So(err, ShouldBeNil)
+			So(s.Check("user-id"), ShouldBeNil)
+
+			state, err := s.Get("user-id")
+			So(err, ShouldBeNil)
+			So(state.Attempts, ShouldEqual, 0)
+		})
+
+		Convey("should do nothing if lockout is disabled", func() {
+			cfg.MaxAttempts = 0
+
+			state := makeAttempts(10)
+			So(state.IsLocked(), ShouldBeFalse)
+			So(storage.items, ShouldBeEmpty)
+			So(s.Check("user-id"), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,21 @@
+package lockout
+
+import (
+	"time"
+)
+
+// Record is the failed attempts of a user within the history duration.
+type Record struct {
+	Attempts      int
+	LastAttemptAt time.Time
+}
+
+type Storage interface {
+	Get(userID string) (*Record, error)
+	// MakeAttempt records a failed attempt made at now.
+	// The record expires after ttl since the last attempt.
+	MakeAttempt(userID string, now time.Time, ttl time.Duration) (*Record, error)
+	// ExpireAt keeps the record until expireAt.
+	ExpireAt(userID string, expireAt time.Time) error
+	Clear(userID string) error
+}
@@ -0,0 +1,91 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/redis/appredis"
+)
+
+type StorageRedis struct {
+	AppID config.AppID
+	Redis *appredis.Handle
+}
+
+func (s *StorageRedis) Get(userID string) (record *Record, err error) {
+	err = s.Redis.WithConn(func(conn *goredis.Conn) error {
+		ctx := context.Background()
+		values, err := conn.HGetAll(ctx, redisLockoutKey(s.AppID, userID)).Result()
+		if err != nil {
+			return err
+		}
+		record = parseRecord(values)
+		return nil
+	})
+	return
+}
+
+func (s *StorageRedis) MakeAttempt(userID string, now time.Time, ttl time.Duration) (record *Record, err error) {
+	err = s.Redis.WithConn(func(conn *goredis.Conn) error {
+		ctx := context.Background()
+		key := redisLockoutKey(s.AppID, userID)
+
+		var attempts *goredis.IntCmd
+		_, err := conn.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			attempts = pipe.HIncrBy(ctx, key, "attempts", 1)
+			pipe.HSet(ctx, key, "last_attempt_at", now.UnixNano())
+			pipe.PExpire(ctx, key, ttl)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		record = &Record{
+			Attempts:      int(attempts.Val()),
+			LastAttemptAt: now,
+		}
+		return nil
+	})
+	return
+}
+
+func (s *StorageRedis) ExpireAt(userID string, expireAt time.Time) error {
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		ctx := context.Background()
+		_, err := conn.PExpireAt(ctx, redisLockoutKey(s.AppID, userID), expireAt).Result()
+		return err
+	})
+}
+
+func (s *StorageRedis) Clear(userID string) error {
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		ctx := context.Background()
+		_, err := conn.Del(ctx, redisLockoutKey(s.AppID, userID)).Result()
+		if err != nil && !errors.Is(err, goredis.Nil) {
+			return err
+		}
+		return nil
+	})
+}
+
+func parseRecord(values map[string]string) *Record {
+	record := &Record{}
+	if attempts, err := strconv.Atoi(values["attempts"]); err == nil {
+		record.Attempts = attempts
+	}
+	if nano, err := strconv.ParseInt(values["last_attempt_at"], 10, 64); err == nil {
+		record.LastAttemptAt = time.Unix(0, nano).UTC()
+	}
+	return record
+}
+
+func redisLockoutKey(appID config.AppID, userID string) string {
+	return fmt.Sprintf("app:%s:lockout:%s", appID, userID)
+}
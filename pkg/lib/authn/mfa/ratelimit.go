@@ -3,14 +3,14 @@ package mfa
 import (
 	"fmt"
 
+	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
 	"github.com/authgear/authgear-server/pkg/util/duration"
 )
 
-// TODO(rate-limit): allow configuration of bucket size & reset period
-
 func RecoveryCodeAuthRateLimitBucket(userID string) ratelimit.Bucket {
 	return ratelimit.Bucket{
+		Name:        config.RateLimitMFARecoveryCode,
 		Key:         fmt.Sprintf("auth-recovery-code:%s", userID),
 		Size:        10,
 		ResetPeriod: duration.PerMinute,
@@ -19,6 +19,7 @@ func RecoveryCodeAuthRateLimitBucket(userID string) ratelimit.Bucket {
 
 func DeviceTokenAuthRateLimitBucket(userID string) ratelimit.Bucket {
 	return ratelimit.Bucket{
+		Name:        config.RateLimitMFADeviceToken,
 		Key:         fmt.Sprintf("auth-device-token:%s", userID),
 		Size:        10,
 		ResetPeriod: duration.PerMinute,
@@ -27,12 +27,17 @@ type RateLimiter interface {
 	TakeToken(bucket ratelimit.Bucket) error
 }
 
+type Lockout interface {
+	Check(userID string) error
+}
+
 type Service struct {
 	DeviceTokens  StoreDeviceToken
 	RecoveryCodes StoreRecoveryCode
 	Clock         clock.Clock
 	Config        *config.AuthenticationConfig
 	RateLimiter   RateLimiter
+	Lockout       Lockout
 }
 
 func (s *Service) GenerateDeviceToken() string {
@@ -109,7 +114,12 @@ func (s *Service) ReplaceRecoveryCodes(userID string, codes []string) ([]*Recove
 }
 
 func (s *Service) VerifyRecoveryCode(userID string, code string) (*RecoveryCode, error) {
-	err := s.RateLimiter.TakeToken(RecoveryCodeAuthRateLimitBucket(userID))
+	err := s.Lockout.Check(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.RateLimiter.TakeToken(RecoveryCodeAuthRateLimitBucket(userID))
 	if err != nil {
 		return nil, err
 	}
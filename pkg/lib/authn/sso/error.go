@@ -6,6 +6,7 @@ import (
 
 var InvalidConfiguration = apierrors.InternalError.WithReason("InvalidConfiguration")
 var OAuthProtocolError = apierrors.BadRequest.WithReason("OAuthProtocolError")
+var SAMLProtocolError = apierrors.BadRequest.WithReason("SAMLProtocolError")
 
 var OAuthError = apierrors.BadRequest.WithReason("OAuthError")
 
@@ -0,0 +1,157 @@
+package sso
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/stdattrs"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+// OIDCImpl is a generic OpenID Connect provider.
+// The endpoints are discovered from the issuer with OpenID Connect Discovery.
+type OIDCImpl struct {
+	Clock                        clock.Clock
+	RedirectURL                  RedirectURLProvider
+	ProviderConfig               config.OAuthSSOProviderConfig
+	Credentials                  config.OAuthClientCredentialsItem
+	StandardAttributesNormalizer StandardAttributesNormalizer
+	DiscoveryDocumentCache       *OIDCDiscoveryDocumentCache
+}
+
+func (*OIDCImpl) Type() config.OAuthSSOProviderType {
+	return config.OAuthSSOProviderTypeOIDC
+}
+
+func (f *OIDCImpl) Config() config.OAuthSSOProviderConfig {
+	return f.ProviderConfig
+}
+
+func (f *OIDCImpl) getOpenIDConfiguration() (*OIDCDiscoveryDocument, error) {
+	// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfig
+	endpoint := strings.TrimSuffix(f.ProviderConfig.Issuer, "/") + "/.well-known/openid-configuration"
+	d, err := f.DiscoveryDocumentCache.Fetch(f.Clock, http.DefaultClient, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// The issuer in the discovery document MUST be identical to the configured issuer.
+	// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfigurationValidation
+	if d.Issuer != f.ProviderConfig.Issuer {
+		return nil, InvalidConfiguration.New(fmt.Sprintf("issuer mismatch in OIDC discovery document: %s", d.Issuer))
+	}
+
+	return d, nil
+}
+
+func (f *OIDCImpl) GetAuthURL(param GetAuthURLParam) (string, error) {
+	c, err := f.getOpenIDConfiguration()
+	if err != nil {
+		return "", err
+	}
+	return c.MakeOAuthURL(OIDCAuthParams{
+		ProviderConfig: f.ProviderConfig,
+		RedirectURI:    f.RedirectURL.SSOCallbackURL(f.ProviderConfig).String(),
+		Nonce:          param.Nonce,
+		State:          param.State,
+		Prompt:         f.GetPrompt(param.Prompt),
+	}), nil
+}
+
+func (f *OIDCImpl) GetAuthInfo(r OAuthAuthorizationResponse, param GetAuthInfoParam) (authInfo AuthInfo, err error) {
+	return f.OpenIDConnectGetAuthInfo(r, param)
+}
+
+func (f *OIDCImpl) OpenIDConnectGetAuthInfo(r OAuthAuthorizationResponse, param GetAuthInfoParam) (authInfo AuthInfo, err error) {
+	c, err := f.getOpenIDConfiguration()
+	if err != nil {
+		return
+	}
+
+	// OPTIMIZE(sso): Cache JWKs
+	keySet, err := c.FetchJWKs(http.DefaultClient)
+	if err != nil {
+		return
+	}
+
+	var tokenResp AccessTokenResp
+	jwtToken, err := c.ExchangeCode(
+		http.DefaultClient,
+		f.Clock,
+		r.Code,
+		keySet,
+		f.ProviderConfig.ClientID,
+		f.Credentials.ClientSecret,
+		f.RedirectURL.SSOCallbackURL(f.ProviderConfig).String(),
+		param.Nonce,
+		&tokenResp,
+	)
+	if err != nil {
+		return
+	}
+
+	if jwtToken.Issuer() != c.Issuer {
+		err = OAuthProtocolError.New(fmt.Sprintf("unexpected issuer in ID token: %s", jwtToken.Issuer()))
+		return
+	}
+
+	claims, err := jwtToken.AsMap(context.TODO())
+	if err != nil {
+		return
+	}
+
+	sub, ok := claims["sub"].(string)
+	if !ok || sub == "" {
+		err = OAuthProtocolError.New("sub not found in ID token")
+		return
+	}
+
+	authInfo.ProviderRawProfile = claims
+	authInfo.ProviderUserID = sub
+	authInfo.StandardAttributes = stdattrs.Extract(MapClaims(claims, f.ProviderConfig.ClaimMapping))
+
+	err = f.StandardAttributesNormalizer.Normalize(authInfo.StandardAttributes)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (f *OIDCImpl) GetPrompt(prompt []string) []string {
+	// Standard values of prompt are supported.
+	// https://openid.net/specs/openid-connect-core-1_0.html#AuthRequest
+	return prompt
+}
+
+// MapClaims returns a copy of claims, with the standard attributes
+// taken from the claims specified in mapping.
+func MapClaims(claims map[string]interface{}, mapping *config.OAuthSSOClaimMappingConfig) map[string]interface{} {
+	out := make(map[string]interface{}, len(claims))
+	for k, v := range claims {
+		out[k] = v
+	}
+
+	if mapping == nil {
+		return out
+	}
+
+	for attr, claim := range *mapping {
+		if v, ok := claims[claim]; ok {
+			out[attr] = v
+		} else {
+			// Do not fall back to the claim with the same name.
+			delete(out, attr)
+		}
+	}
+
+	return out
+}
+
+var (
+	_ OAuthProvider         = &OIDCImpl{}
+	_ OpenIDConnectProvider = &OIDCImpl{}
+)
@@ -0,0 +1,247 @@
+package sso
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/lestrrat-go/jwx/jwt"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/lib/authn/stdattrs"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/jwtutil"
+)
+
+type mockRedirectURLProvider struct{}
+
+func (mockRedirectURLProvider) SSOCallbackURL(providerConfig config.OAuthSSOProviderConfig) *url.URL {
+	u, _ := url.Parse("https://auth/sso/oauth2/callback/" + providerConfig.Alias)
+	return u
+}
+
+// newMockOIDCProvider starts an OpenID provider which issues an ID token
+// with the given claims for any authorization code.
+func newMockOIDCProvider(clk clock.Clock, claims map[string]interface{}) (server *httptest.Server, discoveryRequests *int) {
+	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	So(err, ShouldBeNil)
+	key, err := jwk.New(privKey)
+	So(err, ShouldBeNil)
+	_ = key.Set(jwk.KeyIDKey, "mykey")
+	_ = key.Set(jwk.AlgorithmKey, "RS256")
+	keySet := jwk.NewSet()
+	keySet.Add(key)
+	publicKeySet, err := jwk.PublicSetOf(keySet)
+	So(err, ShouldBeNil)
+
+	discoveryRequests = new(int)
+	mux := http.NewServeMux()
+	server = httptest.NewServer(mux)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		*discoveryRequests++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                 server.URL,
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"jwks_uri":               server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(publicKeySet)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		token := jwt.New()
+		_ = token.Set(jwt.IssuerKey, server.URL)
+		_ = token.Set(jwt.AudienceKey, "client-id")
+		_ = token.Set(jwt.IssuedAtKey, clk.NowUTC().Unix())
+		_ = token.Set(jwt.ExpirationKey, clk.NowUTC().Add(time.Hour).Unix())
+		_ = token.Set("nonce", "nonce")
+		for k, v := range claims {
+			_ = token.Set(k, v)
+		}
+
+		hdr := jws.NewHeaders()
+		_ = hdr.Set(jws.KeyIDKey, "mykey")
+		idToken, err := jwtutil.SignWithHeader(token, hdr, jwa.RS256, key)
+		if err != nil {
+			panic(err)
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"token_type":   "Bearer",
+			"access_token": "access-token",
+			"id_token":     string(idToken),
+		})
+	})
+
+	return
+}
+
+func TestOIDCImpl(t *testing.T) {
+	Convey("OIDCImpl", t, func() {
+		clk := clock.NewMockClockAt("2020-02-01T00:00:00Z")
+		server, discoveryRequests := newMockOIDCProvider(clk, map[string]interface{}{
+			"sub":  "user-id",
+			"mail": "user@example.com",
+			"name": "User",
+		})
+		defer server.Close()
+
+		newProvider := func(providerConfig config.OAuthSSOProviderConfig) *OIDCImpl {
+			return &OIDCImpl{
+				Clock:                        clk,
+				RedirectURL:                  mockRedirectURLProvider{},
+				ProviderConfig:               providerConfig,
+				Credentials:                  config.OAuthClientCredentialsItem{ClientSecret: "client-secret"},
+				StandardAttributesNormalizer: mockStandardAttributesNormalizer{},
+				DiscoveryDocumentCache:       &OIDCDiscoveryDocumentCache{},
+			}
+		}
+
+		Convey("should make auth URL with discovered endpoint", func() {
+			p := newProvider(config.OAuthSSOProviderConfig{
+				Alias:    "keycloak",
+				Type:     config.OAuthSSOProviderTypeOIDC,
+				ClientID: "client-id",
+				Issuer:   server.URL,
+				Scope:    "openid email",
+			})
+			u, err := p.GetAuthURL(GetAuthURLParam{
+				Nonce: "nonce",
+				State: "state",
+			})
+			So(err, ShouldBeNil)
+
+			parsed, err := url.Parse(u)
+			So(err, ShouldBeNil)
+			So(parsed.Scheme+"://"+parsed.Host+parsed.Path, ShouldEqual, server.URL+"/authorize")
+			So(parsed.Query().Get("client_id"), ShouldEqual, "client-id")
+			So(parsed.Query().Get("scope"), ShouldEqual, "openid email")
+			So(parsed.Query().Get("nonce"), ShouldEqual, "nonce")
+			So(parsed.Query().Get("state"), ShouldEqual, "state")
+		})
+
+		Convey("should cache discovery document", func() {
+			p := newProvider(config.OAuthSSOProviderConfig{
+				Alias:    "keycloak",
+				Type:     config.OAuthSSOProviderTypeOIDC,
+				ClientID: "client-id",
+				Issuer:   server.URL,
+			})
+
+			_, err := p.GetAuthURL(GetAuthURLParam{})
+			So(err, ShouldBeNil)
+			_, err = p.GetAuthURL(GetAuthURLParam{})
+			So(err, ShouldBeNil)
+			So(*discoveryRequests, ShouldEqual, 1)
+
+			clk.AdvanceSeconds(int(OIDCDiscoveryDocumentCacheTTL / time.Second))
+			_, err = p.GetAuthURL(GetAuthURLParam{})
+			So(err, ShouldBeNil)
+			So(*discoveryRequests, ShouldEqual, 2)
+		})
+
+		Convey("should reject mismatched issuer", func() {
+			p := newProvider(config.OAuthSSOProviderConfig{
+				Alias:    "keycloak",
+				Type:     config.OAuthSSOProviderTypeOIDC,
+				ClientID: "client-id",
+				Issuer:   server.URL + "/",
+			})
+			_, err := p.GetAuthURL(GetAuthURLParam{})
+			So(apierrors.IsKind(err, InvalidConfiguration), ShouldBeTrue)
+		})
+
+		Convey("should get auth info with claim mapping", func() {
+			p := newProvider(config.OAuthSSOProviderConfig{
+				Alias:    "keycloak",
+				Type:     config.OAuthSSOProviderTypeOIDC,
+				ClientID: "client-id",
+				Issuer:   server.URL,
+				ClaimMapping: &config.OAuthSSOClaimMappingConfig{
+					"email": "mail",
+				},
+			})
+			authInfo, err := p.GetAuthInfo(
+				OAuthAuthorizationResponse{Code: "code"},
+				GetAuthInfoParam{Nonce: "nonce"},
+			)
+			So(err, ShouldBeNil)
+			So(authInfo.ProviderUserID, ShouldEqual, "user-id")
+			So(authInfo.StandardAttributes, ShouldResemble, stdattrs.T{
+				"email": "user@example.com",
+				"name":  "User",
+			})
+		})
+
+		Convey("should reject ID token with mismatched nonce", func() {
+			p := newProvider(config.OAuthSSOProviderConfig{
+				Alias:    "keycloak",
+				Type:     config.OAuthSSOProviderTypeOIDC,
+				ClientID: "client-id",
+				Issuer:   server.URL,
+			})
+			_, err := p.GetAuthInfo(
+				OAuthAuthorizationResponse{Code: "code"},
+				GetAuthInfoParam{Nonce: "other-nonce"},
+			)
+			So(err, ShouldBeError, "invalid nonce")
+		})
+	})
+}
+
+func TestMapClaims(t *testing.T) {
+	Convey("MapClaims", t, func() {
+		claims := map[string]interface{}{
+			"sub":   "user-id",
+			"email": "user@example.com",
+			"mail":  "mail@example.com",
+			"name":  "User",
+		}
+
+		Convey("should copy claims without mapping", func() {
+			So(MapClaims(claims, nil), ShouldResemble, claims)
+		})
+
+		Convey("should take mapped claims", func() {
+			So(MapClaims(claims, &config.OAuthSSOClaimMappingConfig{
+				"email":    "mail",
+				"nickname": "name",
+			}), ShouldResemble, map[string]interface{}{
+				"sub":      "user-id",
+				"email":    "mail@example.com",
+				"mail":     "mail@example.com",
+				"name":     "User",
+				"nickname": "User",
+			})
+		})
+
+		Convey("should not fall back to claim with same name", func() {
+			So(MapClaims(claims, &config.OAuthSSOClaimMappingConfig{
+				"email": "upn",
+			}), ShouldResemble, map[string]interface{}{
+				"sub":  "user-id",
+				"mail": "mail@example.com",
+				"name": "User",
+			})
+		})
+
+		Convey("should not modify claims", func() {
+			_ = MapClaims(claims, &config.OAuthSSOClaimMappingConfig{
+				"email": "upn",
+			})
+			So(claims["email"], ShouldEqual, "user@example.com")
+		})
+	})
+}
@@ -49,6 +49,7 @@ type NonOpenIDConnectProvider interface {
 // "apple"
 // "azureadv2"
 // "adfs"
+// "oidc"
 type OpenIDConnectProvider interface {
 	OpenIDConnectGetAuthInfo(r OAuthAuthorizationResponse, param GetAuthInfoParam) (authInfo AuthInfo, err error)
 }
@@ -72,6 +73,8 @@ type OAuthProviderFactory struct {
 	RedirectURL                  RedirectURLProvider
 	Clock                        clock.Clock
 	WechatURLProvider            WechatURLProvider
+	SAMLURLProvider              SAMLURLProvider
+	SAMLSPKeyMaterials           *config.SAMLSPKeyMaterials
 	StandardAttributesNormalizer StandardAttributesNormalizer
 }
 
@@ -80,6 +83,21 @@ func (p *OAuthProviderFactory) NewOAuthProvider(alias string) OAuthProvider {
 	if !ok {
 		return nil
 	}
+
+	// SAML IdPs do not have client credentials.
+	if providerConfig.Type == config.OAuthSSOProviderTypeSAML {
+		return &SAMLImpl{
+			Clock:                        p.Clock,
+			ProviderConfig:               *providerConfig,
+			URLProvider:                  p.SAMLURLProvider,
+			KeyMaterials:                 p.SAMLSPKeyMaterials,
+			StandardAttributesNormalizer: p.StandardAttributesNormalizer,
+		}
+	}
+
+	if p.Credentials == nil {
+		return nil
+	}
 	credentials, ok := p.Credentials.Lookup(alias)
 	if !ok {
 		return nil
@@ -139,6 +157,15 @@ func (p *OAuthProviderFactory) NewOAuthProvider(alias string) OAuthProvider {
 			URLProvider:                  p.WechatURLProvider,
 			StandardAttributesNormalizer: p.StandardAttributesNormalizer,
 		}
+	case config.OAuthSSOProviderTypeOIDC:
+		return &OIDCImpl{
+			Clock:                        p.Clock,
+			RedirectURL:                  p.RedirectURL,
+			ProviderConfig:               *providerConfig,
+			Credentials:                  *credentials,
+			StandardAttributesNormalizer: p.StandardAttributesNormalizer,
+			DiscoveryDocumentCache:       oidcDiscoveryDocumentCache,
+		}
 	}
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/lestrrat-go/jwx/jwk"
@@ -36,6 +37,7 @@ type OIDCAuthParams struct {
 }
 
 type OIDCDiscoveryDocument struct {
+	Issuer                string `json:"issuer"`
 	AuthorizationEndpoint string `json:"authorization_endpoint"`
 	TokenEndpoint         string `json:"token_endpoint"`
 	JWKSUri               string `json:"jwks_uri"`
@@ -66,12 +68,58 @@ func FetchOIDCDiscoveryDocument(client *http.Client, endpoint string) (*OIDCDisc
 	return &document, nil
 }
 
+// OIDCDiscoveryDocumentCacheTTL is how long a discovery document is cached.
+const OIDCDiscoveryDocumentCacheTTL = 1 * time.Hour
+
+type oidcDiscoveryDocumentCacheEntry struct {
+	Document *OIDCDiscoveryDocument
+	ExpireAt time.Time
+}
+
+// OIDCDiscoveryDocumentCache caches discovery documents by endpoint,
+// so that the provider is not contacted for discovery on every authentication.
+type OIDCDiscoveryDocumentCache struct {
+	mutex   sync.Mutex
+	entries map[string]oidcDiscoveryDocumentCacheEntry
+}
+
+var oidcDiscoveryDocumentCache = &OIDCDiscoveryDocumentCache{}
+
+func (c *OIDCDiscoveryDocumentCache) Fetch(clk clock.Clock, client *http.Client, endpoint string) (*OIDCDiscoveryDocument, error) {
+	now := clk.NowUTC()
+
+	c.mutex.Lock()
+	entry, ok := c.entries[endpoint]
+	c.mutex.Unlock()
+	if ok && now.Before(entry.ExpireAt) {
+		return entry.Document, nil
+	}
+
+	// The lock is not held when fetching, so concurrent misses may fetch more than once.
+	document, err := FetchOIDCDiscoveryDocument(client, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]oidcDiscoveryDocumentCacheEntry)
+	}
+	c.entries[endpoint] = oidcDiscoveryDocumentCacheEntry{
+		Document: document,
+		ExpireAt: now.Add(OIDCDiscoveryDocumentCacheTTL),
+	}
+
+	return document, nil
+}
+
 func (d *OIDCDiscoveryDocument) MakeOAuthURL(params OIDCAuthParams) string {
 	v := url.Values{}
 	v.Add("response_type", "code")
 	v.Add("client_id", params.ProviderConfig.ClientID)
 	v.Add("redirect_uri", params.RedirectURI)
-	v.Add("scope", params.ProviderConfig.Type.Scope())
+	v.Add("scope", params.ProviderConfig.EffectiveScope())
 	v.Add("nonce", params.Nonce)
 	v.Add("response_mode", "form_post")
 	for key, value := range params.ExtraParams {
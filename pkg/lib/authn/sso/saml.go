@@ -0,0 +1,639 @@
+package sso
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/stdattrs"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/duration"
+)
+
+const (
+	SAMLNamespaceProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	SAMLNamespaceAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	SAMLNamespaceMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	SAMLBindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	SAMLBindingHTTPPOST     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+
+	SAMLStatusSuccess             = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	SAMLNameIDFormatEmailAddress  = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	SAMLSubjectConfirmationBearer = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+)
+
+type SAMLURLProvider interface {
+	SAMLMetadataURL(alias string) *url.URL
+	SAMLACSURL(alias string) *url.URL
+}
+
+// SAMLProvider is SAML 2.0 IdP.
+// The SAMLResponse received at the assertion consumer service is passed as the code.
+type SAMLProvider interface {
+	SPMetadata() ([]byte, error)
+}
+
+type SAMLImpl struct {
+	Clock                        clock.Clock
+	ProviderConfig               config.OAuthSSOProviderConfig
+	URLProvider                  SAMLURLProvider
+	KeyMaterials                 *config.SAMLSPKeyMaterials
+	StandardAttributesNormalizer StandardAttributesNormalizer
+}
+
+type samlIdPMetadata struct {
+	XMLName          xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID         string   `xml:"entityID,attr"`
+	IDPSSODescriptor *struct {
+		KeyDescriptors []struct {
+			Use     string `xml:"use,attr"`
+			KeyInfo struct {
+				X509Data struct {
+					X509Certificates []string `xml:"http://www.w3.org/2000/09/xmldsig# X509Certificate"`
+				} `xml:"http://www.w3.org/2000/09/xmldsig# X509Data"`
+			} `xml:"http://www.w3.org/2000/09/xmldsig# KeyInfo"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:metadata KeyDescriptor"`
+		SingleSignOnServices []struct {
+			Binding  string `xml:"Binding,attr"`
+			Location string `xml:"Location,attr"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:metadata SingleSignOnService"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:metadata IDPSSODescriptor"`
+}
+
+type samlIdP struct {
+	EntityID     string
+	SSOURL       string
+	Certificates []*x509.Certificate
+}
+
+func (*SAMLImpl) Type() config.OAuthSSOProviderType {
+	return config.OAuthSSOProviderTypeSAML
+}
+
+func (f *SAMLImpl) Config() config.OAuthSSOProviderConfig {
+	return f.ProviderConfig
+}
+
+// samlIdPs caches the parsed IdP metadata by the metadata XML,
+// so that the metadata of a provider is parsed once.
+var samlIdPs sync.Map
+
+func (f *SAMLImpl) idp() (*samlIdP, error) {
+	if idp, ok := samlIdPs.Load(f.ProviderConfig.IdPMetadata); ok {
+		return idp.(*samlIdP), nil
+	}
+
+	idp, err := parseSAMLIdPMetadata(f.ProviderConfig.IdPMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	samlIdPs.Store(f.ProviderConfig.IdPMetadata, idp)
+	return idp, nil
+}
+
+func parseSAMLIdPMetadata(rawMetadata string) (*samlIdP, error) {
+	var metadata samlIdPMetadata
+	err := xml.Unmarshal([]byte(rawMetadata), &metadata)
+	if err != nil {
+		return nil, InvalidConfiguration.New(fmt.Sprintf("invalid SAML IdP metadata: %v", err))
+	}
+	if metadata.IDPSSODescriptor == nil {
+		return nil, InvalidConfiguration.New("IDPSSODescriptor not found in SAML IdP metadata")
+	}
+
+	idp := &samlIdP{EntityID: metadata.EntityID}
+	for _, s := range metadata.IDPSSODescriptor.SingleSignOnServices {
+		if s.Binding == SAMLBindingHTTPRedirect {
+			idp.SSOURL = s.Location
+			break
+		}
+	}
+	if idp.SSOURL == "" {
+		return nil, InvalidConfiguration.New("SingleSignOnService with HTTP-Redirect binding not found in SAML IdP metadata")
+	}
+
+	for _, k := range metadata.IDPSSODescriptor.KeyDescriptors {
+		if k.Use != "" && k.Use != "signing" {
+			continue
+		}
+		for _, c := range k.KeyInfo.X509Data.X509Certificates {
+			der, err := decodeSAMLBase64(c)
+			if err != nil {
+				return nil, InvalidConfiguration.New(fmt.Sprintf("invalid certificate in SAML IdP metadata: %v", err))
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, InvalidConfiguration.New(fmt.Sprintf("invalid certificate in SAML IdP metadata: %v", err))
+			}
+			idp.Certificates = append(idp.Certificates, cert)
+		}
+	}
+	if len(idp.Certificates) == 0 {
+		return nil, InvalidConfiguration.New("signing certificate not found in SAML IdP metadata")
+	}
+
+	return idp, nil
+}
+
+func (f *SAMLImpl) spKey() (*rsa.PrivateKey, *x509.Certificate, error) {
+	if f.KeyMaterials == nil || f.KeyMaterials.PrivateKey() == nil {
+		return nil, nil, InvalidConfiguration.New("SAML SP key materials are not configured")
+	}
+	return f.KeyMaterials.PrivateKey(), f.KeyMaterials.Certificate(), nil
+}
+
+func (f *SAMLImpl) entityID() string {
+	return f.URLProvider.SAMLMetadataURL(f.ProviderConfig.Alias).String()
+}
+
+func (f *SAMLImpl) acsURL() string {
+	return f.URLProvider.SAMLACSURL(f.ProviderConfig.Alias).String()
+}
+
+// samlRequestID derives the ID of AuthnRequest from the hashed nonce,
+// so that the response can be bound to the interaction with InResponseTo.
+func samlRequestID(hashedNonce string) string {
+	// ID must be a NCName, which cannot start with a digit.
+	return "_" + hashedNonce
+}
+
+func (f *SAMLImpl) GetAuthURL(param GetAuthURLParam) (string, error) {
+	idp, err := f.idp()
+	if err != nil {
+		return "", err
+	}
+	key, _, err := f.spKey()
+	if err != nil {
+		return "", err
+	}
+
+	type nameIDPolicy struct {
+		Format      string `xml:"Format,attr,omitempty"`
+		AllowCreate bool   `xml:"AllowCreate,attr"`
+	}
+	request := struct {
+		XMLName                     xml.Name      `xml:"samlp:AuthnRequest"`
+		SAMLP                       string        `xml:"xmlns:samlp,attr"`
+		SAML                        string        `xml:"xmlns:saml,attr"`
+		ID                          string        `xml:"ID,attr"`
+		Version                     string        `xml:"Version,attr"`
+		IssueInstant                string        `xml:"IssueInstant,attr"`
+		Destination                 string        `xml:"Destination,attr"`
+		AssertionConsumerServiceURL string        `xml:"AssertionConsumerServiceURL,attr"`
+		ProtocolBinding             string        `xml:"ProtocolBinding,attr"`
+		ForceAuthn                  bool          `xml:"ForceAuthn,attr,omitempty"`
+		Issuer                      string        `xml:"saml:Issuer"`
+		NameIDPolicy                *nameIDPolicy `xml:"samlp:NameIDPolicy"`
+	}{
+		SAMLP:                       SAMLNamespaceProtocol,
+		SAML:                        SAMLNamespaceAssertion,
+		ID:                          samlRequestID(param.Nonce),
+		Version:                     "2.0",
+		IssueInstant:                f.Clock.NowUTC().Format(time.RFC3339),
+		Destination:                 idp.SSOURL,
+		AssertionConsumerServiceURL: f.acsURL(),
+		ProtocolBinding:             SAMLBindingHTTPPOST,
+		ForceAuthn:                  len(f.GetPrompt(param.Prompt)) > 0,
+		Issuer:                      f.entityID(),
+		NameIDPolicy: &nameIDPolicy{
+			Format:      f.ProviderConfig.NameIDFormat,
+			AllowCreate: true,
+		},
+	}
+	requestXML, err := xml.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	// HTTP-Redirect binding.
+	// See https://docs.oasis-open.org/security/saml/v2.0/saml-bindings-2.0-os.pdf section 3.4
+	var deflated bytes.Buffer
+	w, err := flate.NewWriter(&deflated, flate.DefaultCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(requestXML); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	query := "SAMLRequest=" + url.QueryEscape(base64.StdEncoding.EncodeToString(deflated.Bytes()))
+	if param.State != "" {
+		query += "&RelayState=" + url.QueryEscape(param.State)
+	}
+	query += "&SigAlg=" + url.QueryEscape(dsig.RSASHA256SignatureMethod)
+
+	h := crypto.SHA256.New()
+	h.Write([]byte(query))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, h.Sum(nil))
+	if err != nil {
+		return "", err
+	}
+	query += "&Signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(signature))
+
+	sep := "?"
+	if strings.Contains(idp.SSOURL, "?") {
+		sep = "&"
+	}
+	return idp.SSOURL + sep + query, nil
+}
+
+func (f *SAMLImpl) GetAuthInfo(r OAuthAuthorizationResponse, param GetAuthInfoParam) (authInfo AuthInfo, err error) {
+	idp, err := f.idp()
+	if err != nil {
+		return
+	}
+
+	rawResponse, err := decodeSAMLBase64(r.Code)
+	if err != nil {
+		err = SAMLProtocolError.New("malformed SAML response")
+		return
+	}
+
+	doc := etree.NewDocument()
+	if err = doc.ReadFromBytes(rawResponse); err != nil || doc.Root() == nil {
+		err = SAMLProtocolError.New("malformed SAML response")
+		return
+	}
+	response := doc.Root()
+	if !samlIs(response, SAMLNamespaceProtocol, "Response") {
+		err = SAMLProtocolError.New("unexpected SAML message")
+		return
+	}
+
+	requestID := samlRequestID(param.Nonce)
+	acsURL := f.acsURL()
+	now := f.Clock.NowUTC()
+
+	// Either the response or the assertion must be signed by the IdP.
+	// Only the elements returned by signature validation are read,
+	// so that unsigned content cannot be wrapped into the response.
+	responseSigned := false
+	if _, ok := samlChild(response, dsig.Namespace, "Signature"); ok {
+		response, err = f.verifySignature(response, idp.Certificates)
+		if err != nil {
+			err = SAMLProtocolError.New(fmt.Sprintf("invalid signature in SAML response: %v", err))
+			return
+		}
+		responseSigned = true
+	}
+
+	if destination := response.SelectAttr("Destination"); destination != nil && destination.Value != acsURL {
+		err = SAMLProtocolError.New("unexpected destination in SAML response")
+		return
+	}
+	if response.SelectAttrValue("InResponseTo", "") != requestID {
+		err = SAMLProtocolError.New("unexpected InResponseTo in SAML response")
+		return
+	}
+
+	statusCode, _ := samlChild(response, SAMLNamespaceProtocol, "Status", "StatusCode")
+	if statusCode == nil {
+		err = SAMLProtocolError.New("status not found in SAML response")
+		return
+	}
+	if status := statusCode.SelectAttrValue("Value", ""); status != SAMLStatusSuccess {
+		err = SAMLProtocolError.New(fmt.Sprintf("SAML authentication failed: %s", status))
+		return
+	}
+
+	if len(samlChildren(response, SAMLNamespaceAssertion, "EncryptedAssertion")) > 0 {
+		err = SAMLProtocolError.New("encrypted assertion is not supported")
+		return
+	}
+	assertion, ok := samlChild(response, SAMLNamespaceAssertion, "Assertion")
+	if !ok {
+		err = SAMLProtocolError.New("expected exactly one assertion in SAML response")
+		return
+	}
+	if _, ok := samlChild(assertion, dsig.Namespace, "Signature"); ok {
+		assertion, err = f.verifySignature(assertion, idp.Certificates)
+		if err != nil {
+			err = SAMLProtocolError.New(fmt.Sprintf("invalid signature in SAML assertion: %v", err))
+			return
+		}
+	} else if !responseSigned {
+		err = SAMLProtocolError.New("SAML assertion is not signed")
+		return
+	}
+
+	if issuer, ok := samlChild(assertion, SAMLNamespaceAssertion, "Issuer"); !ok || strings.TrimSpace(samlText(issuer)) != idp.EntityID {
+		err = SAMLProtocolError.New("unexpected issuer in SAML assertion")
+		return
+	}
+
+	subject, ok := samlChild(assertion, SAMLNamespaceAssertion, "Subject")
+	if !ok {
+		err = SAMLProtocolError.New("subject not found in SAML assertion")
+		return
+	}
+	nameIDEl, ok := samlChild(subject, SAMLNamespaceAssertion, "NameID")
+	if !ok || strings.TrimSpace(samlText(nameIDEl)) == "" {
+		err = SAMLProtocolError.New("NameID not found in SAML assertion")
+		return
+	}
+	nameID := strings.TrimSpace(samlText(nameIDEl))
+	nameIDFormat := nameIDEl.SelectAttrValue("Format", "")
+
+	// The checks of the response above cover unsigned data if only the assertion is signed.
+	// In that case, the signed assertion itself must be bound to the request,
+	// otherwise a signed assertion of another flow could be replayed.
+	if err = f.checkSubjectConfirmation(subject, requestID, !responseSigned, acsURL, now); err != nil {
+		return
+	}
+	if err = f.checkConditions(assertion, now); err != nil {
+		return
+	}
+
+	claims := map[string]interface{}{
+		"name_id": nameID,
+	}
+	if nameIDFormat != "" {
+		claims["name_id_format"] = nameIDFormat
+	}
+	for _, statement := range samlChildren(assertion, SAMLNamespaceAssertion, "AttributeStatement") {
+		for _, attribute := range samlChildren(statement, SAMLNamespaceAssertion, "Attribute") {
+			name := attribute.SelectAttrValue("Name", "")
+			if name == "" {
+				continue
+			}
+			var values []interface{}
+			for _, v := range samlChildren(attribute, SAMLNamespaceAssertion, "AttributeValue") {
+				values = append(values, samlText(v))
+			}
+			if len(values) == 1 {
+				claims[name] = values[0]
+			} else if len(values) > 1 {
+				claims[name] = values
+			}
+		}
+	}
+
+	extracted := stdattrs.Extract(MapClaims(claims, f.ProviderConfig.ClaimMapping))
+	if _, ok := extracted[stdattrs.Email]; !ok && nameIDFormat == SAMLNameIDFormatEmailAddress {
+		extracted[stdattrs.Email] = nameID
+	}
+
+	authInfo.ProviderRawProfile = claims
+	authInfo.ProviderUserID = nameID
+	authInfo.StandardAttributes = extracted
+
+	err = f.StandardAttributesNormalizer.Normalize(authInfo.StandardAttributes)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// samlSignatureMethods and samlDigestMethods are the allowed algorithms of signatures.
+// SHA-1 is not allowed.
+var samlSignatureMethods = map[string]struct{}{
+	dsig.RSASHA256SignatureMethod:   {},
+	dsig.RSASHA384SignatureMethod:   {},
+	dsig.RSASHA512SignatureMethod:   {},
+	dsig.ECDSASHA256SignatureMethod: {},
+	dsig.ECDSASHA384SignatureMethod: {},
+	dsig.ECDSASHA512SignatureMethod: {},
+}
+
+var samlDigestMethods = map[string]struct{}{
+	"http://www.w3.org/2001/04/xmlenc#sha256":       {},
+	"http://www.w3.org/2001/04/xmldsig-more#sha384": {},
+	"http://www.w3.org/2001/04/xmlenc#sha512":       {},
+}
+
+// verifySignature verifies the enveloped signature of el, and returns the signed element.
+func (f *SAMLImpl) verifySignature(el *etree.Element, certs []*x509.Certificate) (*etree.Element, error) {
+	for _, method := range el.FindElements(".//SignatureMethod") {
+		if _, ok := samlSignatureMethods[method.SelectAttrValue("Algorithm", "")]; !ok {
+			return nil, fmt.Errorf("unsupported signature method: %s", method.SelectAttrValue("Algorithm", ""))
+		}
+	}
+	for _, method := range el.FindElements(".//DigestMethod") {
+		if _, ok := samlDigestMethods[method.SelectAttrValue("Algorithm", "")]; !ok {
+			return nil, fmt.Errorf("unsupported digest method: %s", method.SelectAttrValue("Algorithm", ""))
+		}
+	}
+
+	// The namespaces declared by the ancestors are needed to canonicalize el alone.
+	nsCtx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		return nil, err
+	}
+	detached, err := etreeutils.NSDetatch(nsCtx, el)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: certs})
+	// The validity of the certificate is checked at the time of the clock.
+	ctx.Clock = dsig.NewFakeClockAt(f.Clock.NowUTC())
+	return ctx.Validate(detached)
+}
+
+func samlIs(el *etree.Element, namespace string, local string) bool {
+	return el.NamespaceURI() == namespace && el.Tag == local
+}
+
+func samlChildren(el *etree.Element, namespace string, local string) []*etree.Element {
+	var out []*etree.Element
+	for _, child := range el.ChildElements() {
+		if samlIs(child, namespace, local) {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
+// samlChild returns the only child element along the path.
+func samlChild(el *etree.Element, namespace string, path ...string) (*etree.Element, bool) {
+	for _, local := range path {
+		children := samlChildren(el, namespace, local)
+		if len(children) != 1 {
+			return nil, false
+		}
+		el = children[0]
+	}
+	return el, true
+}
+
+// samlText returns the concatenated character data of el.
+// Comments are skipped instead of ending the text, so that a comment cannot truncate a signed value.
+func samlText(el *etree.Element) string {
+	var buf strings.Builder
+	for _, child := range el.Child {
+		if cd, ok := child.(*etree.CharData); ok {
+			buf.WriteString(cd.Data)
+		}
+	}
+	return buf.String()
+}
+
+func (f *SAMLImpl) checkSubjectConfirmation(subject *etree.Element, requestID string, requireInResponseTo bool, acsURL string, now time.Time) error {
+	for _, confirmation := range samlChildren(subject, SAMLNamespaceAssertion, "SubjectConfirmation") {
+		if confirmation.SelectAttrValue("Method", "") != SAMLSubjectConfirmationBearer {
+			continue
+		}
+		data, ok := samlChild(confirmation, SAMLNamespaceAssertion, "SubjectConfirmationData")
+		if !ok {
+			continue
+		}
+		if data.SelectAttrValue("Recipient", "") != acsURL {
+			continue
+		}
+		if inResponseTo := data.SelectAttr("InResponseTo"); inResponseTo != nil {
+			if inResponseTo.Value != requestID {
+				continue
+			}
+		} else if requireInResponseTo {
+			continue
+		}
+		notOnOrAfter := data.SelectAttr("NotOnOrAfter")
+		if notOnOrAfter == nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, notOnOrAfter.Value)
+		if err != nil || !now.Before(t.Add(duration.ClockSkew)) {
+			continue
+		}
+		return nil
+	}
+	return SAMLProtocolError.New("no valid bearer subject confirmation in SAML assertion")
+}
+
+func (f *SAMLImpl) checkConditions(assertion *etree.Element, now time.Time) error {
+	conditions, ok := samlChild(assertion, SAMLNamespaceAssertion, "Conditions")
+	if !ok {
+		return SAMLProtocolError.New("conditions not found in SAML assertion")
+	}
+
+	if notBefore := conditions.SelectAttr("NotBefore"); notBefore != nil {
+		t, err := time.Parse(time.RFC3339, notBefore.Value)
+		if err != nil || now.Add(duration.ClockSkew).Before(t) {
+			return SAMLProtocolError.New("SAML assertion is not yet valid")
+		}
+	}
+	if notOnOrAfter := conditions.SelectAttr("NotOnOrAfter"); notOnOrAfter != nil {
+		t, err := time.Parse(time.RFC3339, notOnOrAfter.Value)
+		if err != nil || !now.Before(t.Add(duration.ClockSkew)) {
+			return SAMLProtocolError.New("SAML assertion has expired")
+		}
+	}
+
+	// The SP must be one of the audiences in every audience restriction.
+	entityID := f.entityID()
+	restrictions := samlChildren(conditions, SAMLNamespaceAssertion, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return SAMLProtocolError.New("audience restriction not found in SAML assertion")
+	}
+	for _, restriction := range restrictions {
+		found := false
+		for _, audience := range samlChildren(restriction, SAMLNamespaceAssertion, "Audience") {
+			if strings.TrimSpace(samlText(audience)) == entityID {
+				found = true
+			}
+		}
+		if !found {
+			return SAMLProtocolError.New("unexpected audience in SAML assertion")
+		}
+	}
+
+	return nil
+}
+
+func (f *SAMLImpl) GetPrompt(prompt []string) []string {
+	// prompt=login is mapped to ForceAuthn.
+	for _, p := range prompt {
+		if p == "login" {
+			return []string{"login"}
+		}
+	}
+	return []string{}
+}
+
+// SPMetadata returns the metadata of Authgear as a SAML service provider.
+func (f *SAMLImpl) SPMetadata() ([]byte, error) {
+	_, cert, err := f.spKey()
+	if err != nil {
+		return nil, err
+	}
+
+	type keyDescriptor struct {
+		Use             string `xml:"use,attr"`
+		X509Certificate string `xml:"ds:KeyInfo>ds:X509Data>ds:X509Certificate"`
+	}
+	type endpoint struct {
+		Binding   string `xml:"Binding,attr"`
+		Location  string `xml:"Location,attr"`
+		Index     int    `xml:"index,attr"`
+		IsDefault bool   `xml:"isDefault,attr"`
+	}
+	metadata := struct {
+		XMLName         xml.Name `xml:"md:EntityDescriptor"`
+		MD              string   `xml:"xmlns:md,attr"`
+		DS              string   `xml:"xmlns:ds,attr"`
+		EntityID        string   `xml:"entityID,attr"`
+		SPSSODescriptor struct {
+			AuthnRequestsSigned        bool          `xml:"AuthnRequestsSigned,attr"`
+			WantAssertionsSigned       bool          `xml:"WantAssertionsSigned,attr"`
+			ProtocolSupportEnumeration string        `xml:"protocolSupportEnumeration,attr"`
+			KeyDescriptor              keyDescriptor `xml:"md:KeyDescriptor"`
+			NameIDFormat               string        `xml:"md:NameIDFormat,omitempty"`
+			AssertionConsumerService   endpoint      `xml:"md:AssertionConsumerService"`
+		} `xml:"md:SPSSODescriptor"`
+	}{
+		MD:       SAMLNamespaceMetadata,
+		DS:       dsig.Namespace,
+		EntityID: f.entityID(),
+	}
+	metadata.SPSSODescriptor.AuthnRequestsSigned = true
+	metadata.SPSSODescriptor.WantAssertionsSigned = true
+	metadata.SPSSODescriptor.ProtocolSupportEnumeration = SAMLNamespaceProtocol
+	metadata.SPSSODescriptor.KeyDescriptor = keyDescriptor{
+		Use:             "signing",
+		X509Certificate: base64.StdEncoding.EncodeToString(cert.Raw),
+	}
+	metadata.SPSSODescriptor.NameIDFormat = f.ProviderConfig.NameIDFormat
+	metadata.SPSSODescriptor.AssertionConsumerService = endpoint{
+		Binding:   SAMLBindingHTTPPOST,
+		Location:  f.acsURL(),
+		Index:     0,
+		IsDefault: true,
+	}
+
+	out, err := xml.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func decodeSAMLBase64(s string) ([]byte, error) {
+	// Base64 content may be wrapped with whitespaces.
+	s = strings.Join(strings.Fields(s), "")
+	return base64.StdEncoding.DecodeString(s)
+}
+
+var (
+	_ OAuthProvider = &SAMLImpl{}
+	_ SAMLProvider  = &SAMLImpl{}
+)
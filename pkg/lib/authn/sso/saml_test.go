@@ -0,0 +1,257 @@
+package sso
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/stdattrs"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+type mockSAMLURLProvider struct{}
+
+func (mockSAMLURLProvider) SAMLMetadataURL(alias string) *url.URL {
+	u, _ := url.Parse("https://dev.sudo.wtf:8443/v1/teams/asa")
+	return u
+}
+
+func (mockSAMLURLProvider) SAMLACSURL(alias string) *url.URL {
+	u, _ := url.Parse("https://dev.sudo.wtf:8443/v1/_saml_callback")
+	return u
+}
+
+type mockStandardAttributesNormalizer struct{}
+
+func (mockStandardAttributesNormalizer) Normalize(stdattrs.T) error {
+	return nil
+}
+
+func readSAMLTestData(name string) string {
+	b, err := os.ReadFile(filepath.Join("testdata", "saml", name))
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+// samlTestKeyStore returns a key store with a self-signed certificate valid at the test clock.
+func samlTestKeyStore() dsig.X509KeyStore {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	So(err, ShouldBeNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "okta"},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	So(err, ShouldBeNil)
+	return dsig.TLSCertKeyStore(tls.Certificate{
+		Certificate: [][]byte{certDER},
+		PrivateKey:  key,
+	})
+}
+
+func samlTestIdPMetadata(certDER []byte) string {
+	return `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="http://www.okta.com/exkrfkzzb7NyB3UeP0h7">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+        <ds:X509Data>
+          <ds:X509Certificate>` + base64.StdEncoding.EncodeToString(certDER) + `</ds:X509Certificate>
+        </ds:X509Data>
+      </ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://dev.okta.com/sso/saml"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`
+}
+
+// samlTestAssertionOnlySignedResponse returns an unsigned response to requestID,
+// containing an assertion signed by ks.
+// If subjectInResponseTo is empty, InResponseTo is absent in SubjectConfirmationData.
+func samlTestAssertionOnlySignedResponse(ks dsig.X509KeyStore, requestID string, subjectInResponseTo string) string {
+	assertion := etree.NewElement("saml:Assertion")
+	assertion.CreateAttr("xmlns:saml", SAMLNamespaceAssertion)
+	assertion.CreateAttr("ID", "_assertion")
+	assertion.CreateAttr("Version", "2.0")
+	assertion.CreateAttr("IssueInstant", "2020-09-01T17:51:12Z")
+	assertion.CreateElement("saml:Issuer").SetText("http://www.okta.com/exkrfkzzb7NyB3UeP0h7")
+	subject := assertion.CreateElement("saml:Subject")
+	nameID := subject.CreateElement("saml:NameID")
+	nameID.CreateAttr("Format", SAMLNameIDFormatEmailAddress)
+	nameID.SetText("phoebe.yu@okta.com")
+	confirmation := subject.CreateElement("saml:SubjectConfirmation")
+	confirmation.CreateAttr("Method", SAMLSubjectConfirmationBearer)
+	data := confirmation.CreateElement("saml:SubjectConfirmationData")
+	if subjectInResponseTo != "" {
+		data.CreateAttr("InResponseTo", subjectInResponseTo)
+	}
+	data.CreateAttr("NotOnOrAfter", "2020-09-01T17:56:12Z")
+	data.CreateAttr("Recipient", "https://dev.sudo.wtf:8443/v1/_saml_callback")
+	conditions := assertion.CreateElement("saml:Conditions")
+	conditions.CreateAttr("NotBefore", "2020-09-01T17:46:12Z")
+	conditions.CreateAttr("NotOnOrAfter", "2020-09-01T17:56:12Z")
+	conditions.CreateElement("saml:AudienceRestriction").CreateElement("saml:Audience").SetText("https://dev.sudo.wtf:8443/v1/teams/asa")
+
+	// Like real IdPs, sign with exclusive canonicalization so that the signature
+	// does not cover the namespaces of the response.
+	ctx := dsig.NewDefaultSigningContext(ks)
+	ctx.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	signed, err := ctx.SignEnveloped(assertion)
+	So(err, ShouldBeNil)
+
+	response := etree.NewElement("samlp:Response")
+	response.CreateAttr("xmlns:samlp", SAMLNamespaceProtocol)
+	response.CreateAttr("ID", "_response")
+	response.CreateAttr("InResponseTo", requestID)
+	response.CreateAttr("Destination", "https://dev.sudo.wtf:8443/v1/_saml_callback")
+	response.CreateElement("samlp:Status").CreateElement("samlp:StatusCode").CreateAttr("Value", SAMLStatusSuccess)
+	response.AddChild(signed)
+
+	doc := etree.NewDocument()
+	doc.SetRoot(response)
+	xml, err := doc.WriteToString()
+	So(err, ShouldBeNil)
+	return xml
+}
+
+func TestSAMLImplGetAuthInfo(t *testing.T) {
+	// The fixtures are derived from a response of Okta,
+	// taken from the tests of github.com/russellhaering/goxmldsig.
+	// Both the response and the assertion are signed.
+	Convey("SAMLImpl.GetAuthInfo", t, func() {
+		impl := &SAMLImpl{
+			Clock: clock.NewMockClockAt("2020-09-01T17:51:12Z"),
+			ProviderConfig: config.OAuthSSOProviderConfig{
+				Alias:       "okta",
+				Type:        config.OAuthSSOProviderTypeSAML,
+				IdPMetadata: readSAMLTestData("okta_metadata.xml"),
+			},
+			URLProvider:                  mockSAMLURLProvider{},
+			StandardAttributesNormalizer: mockStandardAttributesNormalizer{},
+		}
+		param := GetAuthInfoParam{
+			Nonce: "ffea96b1-44a2-4a86-9683-45807984ab5b",
+		}
+		getAuthInfo := func(response string) (AuthInfo, error) {
+			return impl.GetAuthInfo(OAuthAuthorizationResponse{
+				Code: base64.StdEncoding.EncodeToString([]byte(response)),
+			}, param)
+		}
+
+		Convey("should accept valid response", func() {
+			authInfo, err := getAuthInfo(readSAMLTestData("okta_response.xml"))
+			So(err, ShouldBeNil)
+			So(authInfo.ProviderUserID, ShouldEqual, "phoebe.yu@okta.com")
+			So(authInfo.ProviderRawProfile, ShouldResemble, map[string]interface{}{
+				"name_id":        "phoebe.yu@okta.com",
+				"name_id_format": SAMLNameIDFormatEmailAddress,
+				"FirstName":      "Phoebe",
+				"LastName":       "Yu",
+				"Email":          "phoebe.yu@okta.com",
+				"Login":          "phoebe.yu@okta.com",
+				"SSHUserName":    "",
+			})
+			So(authInfo.StandardAttributes[stdattrs.Email], ShouldEqual, "phoebe.yu@okta.com")
+		})
+
+		Convey("should read the whole text around comments", func() {
+			authInfo, err := getAuthInfo(readSAMLTestData("okta_response_comment_injection.xml"))
+			So(err, ShouldBeNil)
+			So(authInfo.ProviderUserID, ShouldEqual, "phoebe.yu@okta.com")
+		})
+
+		Convey("should reject unexpected InResponseTo", func() {
+			param.Nonce = "another-nonce"
+			_, err := getAuthInfo(readSAMLTestData("okta_response.xml"))
+			So(err, ShouldBeError, "unexpected InResponseTo in SAML response")
+		})
+
+		Convey("should reject expired assertion", func() {
+			impl.Clock = clock.NewMockClockAt("2020-09-01T18:30:00Z")
+			_, err := getAuthInfo(readSAMLTestData("okta_response.xml"))
+			So(err, ShouldBeError, "no valid bearer subject confirmation in SAML assertion")
+		})
+
+		Convey("should reject modified assertion", func() {
+			_, err := getAuthInfo(readSAMLTestData("okta_response_modified_assertion.xml"))
+			So(err, ShouldBeError, "invalid signature in SAML assertion: Signature could not be verified")
+		})
+
+		Convey("should reject injected assertion", func() {
+			_, err := getAuthInfo(readSAMLTestData("okta_response_injected_assertion.xml"))
+			So(err, ShouldBeError, "expected exactly one assertion in SAML response")
+		})
+
+		Convey("should reject injected assertion in signed response", func() {
+			_, err := getAuthInfo(readSAMLTestData("okta_response_signed_injected_assertion.xml"))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "invalid signature in SAML response")
+		})
+
+		Convey("should reject assertion wrapping the signed assertion", func() {
+			_, err := getAuthInfo(readSAMLTestData("okta_response_wrapped_assertion.xml"))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "invalid signature in SAML assertion")
+		})
+
+		Convey("should bind assertion to the request if only the assertion is signed", func() {
+			ks := samlTestKeyStore()
+			_, certDER, err := ks.GetKeyPair()
+			So(err, ShouldBeNil)
+			impl.ProviderConfig.IdPMetadata = samlTestIdPMetadata(certDER)
+			requestID := "_ffea96b1-44a2-4a86-9683-45807984ab5b"
+
+			authInfo, err := getAuthInfo(samlTestAssertionOnlySignedResponse(ks, requestID, requestID))
+			So(err, ShouldBeNil)
+			So(authInfo.ProviderUserID, ShouldEqual, "phoebe.yu@okta.com")
+
+			// A signed assertion of another flow is replayed in an unsigned response to this request.
+			_, err = getAuthInfo(samlTestAssertionOnlySignedResponse(ks, requestID, "_another-request"))
+			So(err, ShouldBeError, "no valid bearer subject confirmation in SAML assertion")
+
+			_, err = getAuthInfo(samlTestAssertionOnlySignedResponse(ks, requestID, ""))
+			So(err, ShouldBeError, "no valid bearer subject confirmation in SAML assertion")
+		})
+
+		Convey("should reject SHA-1 signature", func() {
+			ks := dsig.RandomKeyStoreForTest()
+			_, certDER, err := ks.GetKeyPair()
+			So(err, ShouldBeNil)
+			impl.ProviderConfig.IdPMetadata = samlTestIdPMetadata(certDER)
+
+			response := etree.NewElement("samlp:Response")
+			response.CreateAttr("xmlns:samlp", SAMLNamespaceProtocol)
+			response.CreateAttr("ID", "_response")
+			response.CreateAttr("InResponseTo", "_ffea96b1-44a2-4a86-9683-45807984ab5b")
+			ctx := dsig.NewDefaultSigningContext(ks)
+			err = ctx.SetSignatureMethod(dsig.RSASHA1SignatureMethod)
+			So(err, ShouldBeNil)
+			signed, err := ctx.SignEnveloped(response)
+			So(err, ShouldBeNil)
+			doc := etree.NewDocument()
+			doc.SetRoot(signed)
+			xml, err := doc.WriteToString()
+			So(err, ShouldBeNil)
+
+			_, err = getAuthInfo(xml)
+			So(err, ShouldBeError, "invalid signature in SAML response: unsupported signature method: "+dsig.RSASHA1SignatureMethod)
+		})
+	})
+}
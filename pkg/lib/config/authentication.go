@@ -29,7 +29,8 @@ var _ = Schema.Add("AuthenticationConfig", `
 		},
 		"secondary_authentication_mode": { "$ref": "#/$defs/SecondaryAuthenticationMode" },
 		"device_token": { "$ref": "#/$defs/DeviceTokenConfig" },
-		"recovery_code": { "$ref": "#/$defs/RecoveryCodeConfig" }
+		"recovery_code": { "$ref": "#/$defs/RecoveryCodeConfig" },
+		"lockout": { "$ref": "#/$defs/AuthenticationLockoutConfig" }
 	}
 }
 `)
@@ -44,25 +45,26 @@ var _ = Schema.Add("IdentityType", `
 var _ = Schema.Add("PrimaryAuthenticatorType", `
 {
 	"type": "string",
-	"enum": ["password", "oob_otp_email", "oob_otp_sms"]
+	"enum": ["password", "oob_otp_email", "oob_otp_sms", "passkey"]
 }
 `)
 
 var _ = Schema.Add("SecondaryAuthenticatorType", `
 {
 	"type": "string",
-	"enum": ["password", "oob_otp_email", "oob_otp_sms", "totp"]
+	"enum": ["password", "oob_otp_email", "oob_otp_sms", "totp", "passkey"]
 }
 `)
 
 type AuthenticationConfig struct {
-	Identities                  []model.IdentityType        `json:"identities,omitempty"`
-	PrimaryAuthenticators       *[]model.AuthenticatorType  `json:"primary_authenticators,omitempty"`
-	SecondaryAuthenticators     *[]model.AuthenticatorType  `json:"secondary_authenticators,omitempty"`
-	SecondaryAuthenticationMode SecondaryAuthenticationMode `json:"secondary_authentication_mode,omitempty"`
-	DeviceToken                 *DeviceTokenConfig          `json:"device_token,omitempty"`
-	RecoveryCode                *RecoveryCodeConfig         `json:"recovery_code,omitempty"`
-	PublicSignupDisabled        bool                        `json:"public_signup_disabled,omitempty"`
+	Identities                  []model.IdentityType         `json:"identities,omitempty"`
+	PrimaryAuthenticators       *[]model.AuthenticatorType   `json:"primary_authenticators,omitempty"`
+	SecondaryAuthenticators     *[]model.AuthenticatorType   `json:"secondary_authenticators,omitempty"`
+	SecondaryAuthenticationMode SecondaryAuthenticationMode  `json:"secondary_authentication_mode,omitempty"`
+	DeviceToken                 *DeviceTokenConfig           `json:"device_token,omitempty"`
+	RecoveryCode                *RecoveryCodeConfig          `json:"recovery_code,omitempty"`
+	Lockout                     *AuthenticationLockoutConfig `json:"lockout,omitempty"`
+	PublicSignupDisabled        bool                         `json:"public_signup_disabled,omitempty"`
 }
 
 func (c *AuthenticationConfig) SetDefaults() {
@@ -151,3 +153,48 @@ func (c *RecoveryCodeConfig) SetDefaults() {
 		c.Count = 16
 	}
 }
+
+var _ = Schema.Add("AuthenticationLockoutConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"max_attempts": { "type": "integer", "minimum": 0 },
+		"history_duration_seconds": { "$ref": "#/$defs/DurationSeconds", "minimum": 1 },
+		"minimum_duration_seconds": { "$ref": "#/$defs/DurationSeconds", "minimum": 1 },
+		"maximum_duration_seconds": { "$ref": "#/$defs/DurationSeconds", "minimum": 1 },
+		"backoff_factor": { "type": "number", "minimum": 1 }
+	}
+}
+`)
+
+// AuthenticationLockoutConfig configures the lockout of a user
+// after too many failed attempts to authenticate with password, TOTP,
+// OOB OTP or recovery code.
+// The lockout is disabled if MaxAttempts is 0.
+type AuthenticationLockoutConfig struct {
+	MaxAttempts     int             `json:"max_attempts,omitempty"`
+	HistoryDuration DurationSeconds `json:"history_duration_seconds,omitempty"`
+	MinimumDuration DurationSeconds `json:"minimum_duration_seconds,omitempty"`
+	MaximumDuration DurationSeconds `json:"maximum_duration_seconds,omitempty"`
+	BackoffFactor   float64         `json:"backoff_factor,omitempty"`
+}
+
+func (c *AuthenticationLockoutConfig) SetDefaults() {
+	if c.HistoryDuration == 0 {
+		c.HistoryDuration = DurationSeconds(86400)
+	}
+	if c.MinimumDuration == 0 {
+		c.MinimumDuration = DurationSeconds(60)
+	}
+	if c.MaximumDuration == 0 {
+		c.MaximumDuration = DurationSeconds(3600)
+	}
+	if c.BackoffFactor == 0 {
+		c.BackoffFactor = 2
+	}
+}
+
+func (c *AuthenticationLockoutConfig) IsEnabled() bool {
+	return c.MaxAttempts > 0
+}
@@ -7,7 +7,8 @@ var _ = Schema.Add("AuthenticatorConfig", `
 	"properties": {
 		"password": { "$ref": "#/$defs/AuthenticatorPasswordConfig" },
 		"totp": { "$ref": "#/$defs/AuthenticatorTOTPConfig" },
-		"oob_otp": { "$ref": "#/$defs/AuthenticatorOOBConfig" }
+		"oob_otp": { "$ref": "#/$defs/AuthenticatorOOBConfig" },
+		"passkey": { "$ref": "#/$defs/AuthenticatorPasskeyConfig" }
 	}
 }
 `)
@@ -16,6 +17,7 @@ type AuthenticatorConfig struct {
 	Password *AuthenticatorPasswordConfig `json:"password,omitempty"`
 	TOTP     *AuthenticatorTOTPConfig     `json:"totp,omitempty"`
 	OOB      *AuthenticatorOOBConfig      `json:"oob_otp,omitempty"`
+	Passkey  *AuthenticatorPasskeyConfig  `json:"passkey,omitempty"`
 }
 
 var _ = Schema.Add("AuthenticatorPasswordConfig", `
@@ -24,14 +26,16 @@ var _ = Schema.Add("AuthenticatorPasswordConfig", `
 	"additionalProperties": false,
 	"properties": {
 		"policy": { "$ref": "#/$defs/PasswordPolicyConfig" },
-		"force_change": { "type": "boolean" }
+		"force_change": { "type": "boolean" },
+		"hashing": { "$ref": "#/$defs/PasswordHashingConfig" }
 	}
 }
 `)
 
 type AuthenticatorPasswordConfig struct {
-	Policy      *PasswordPolicyConfig `json:"policy,omitempty"`
-	ForceChange *bool                 `json:"force_change,omitempty"`
+	Policy      *PasswordPolicyConfig  `json:"policy,omitempty"`
+	ForceChange *bool                  `json:"force_change,omitempty"`
+	Hashing     *PasswordHashingConfig `json:"hashing,omitempty"`
 }
 
 func (c *AuthenticatorPasswordConfig) SetDefaults() {
@@ -40,6 +44,130 @@ func (c *AuthenticatorPasswordConfig) SetDefaults() {
 	}
 }
 
+var _ = Schema.Add("PasswordHashingConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"algorithm": { "$ref": "#/$defs/PasswordHashingAlgorithm" },
+		"bcrypt_sha512": { "$ref": "#/$defs/PasswordHashingBcryptSHA512Config" },
+		"argon2id": { "$ref": "#/$defs/PasswordHashingArgon2idConfig" },
+		"scrypt": { "$ref": "#/$defs/PasswordHashingScryptConfig" }
+	}
+}
+`)
+
+// PasswordHashingConfig configures how new passwords are hashed.
+// Existing hashes of other algorithms or lower cost are rehashed when the user logs in.
+type PasswordHashingConfig struct {
+	Algorithm    PasswordHashingAlgorithm           `json:"algorithm,omitempty"`
+	BcryptSHA512 *PasswordHashingBcryptSHA512Config `json:"bcrypt_sha512,omitempty"`
+	Argon2id     *PasswordHashingArgon2idConfig     `json:"argon2id,omitempty"`
+	Scrypt       *PasswordHashingScryptConfig       `json:"scrypt,omitempty"`
+}
+
+func (c *PasswordHashingConfig) SetDefaults() {
+	if c.Algorithm == "" {
+		c.Algorithm = PasswordHashingAlgorithmBcryptSHA512
+	}
+}
+
+var _ = Schema.Add("PasswordHashingAlgorithm", `
+{
+	"type": "string",
+	"enum": ["bcrypt_sha512", "argon2id", "scrypt"]
+}
+`)
+
+type PasswordHashingAlgorithm string
+
+const (
+	PasswordHashingAlgorithmBcryptSHA512 PasswordHashingAlgorithm = "bcrypt_sha512"
+	PasswordHashingAlgorithmArgon2id     PasswordHashingAlgorithm = "argon2id"
+	PasswordHashingAlgorithmScrypt       PasswordHashingAlgorithm = "scrypt"
+)
+
+var _ = Schema.Add("PasswordHashingBcryptSHA512Config", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"cost": { "type": "integer", "minimum": 10, "maximum": 31 }
+	}
+}
+`)
+
+type PasswordHashingBcryptSHA512Config struct {
+	Cost int `json:"cost,omitempty"`
+}
+
+func (c *PasswordHashingBcryptSHA512Config) SetDefaults() {
+	if c.Cost == 0 {
+		c.Cost = 10
+	}
+}
+
+var _ = Schema.Add("PasswordHashingArgon2idConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"memory_kib": { "type": "integer", "minimum": 8192, "maximum": 1048576 },
+		"iterations": { "type": "integer", "minimum": 1, "maximum": 16 },
+		"parallelism": { "type": "integer", "minimum": 1, "maximum": 16 }
+	}
+}
+`)
+
+type PasswordHashingArgon2idConfig struct {
+	MemoryKiB   int `json:"memory_kib,omitempty"`
+	Iterations  int `json:"iterations,omitempty"`
+	Parallelism int `json:"parallelism,omitempty"`
+}
+
+func (c *PasswordHashingArgon2idConfig) SetDefaults() {
+	if c.MemoryKiB == 0 {
+		c.MemoryKiB = 19456
+	}
+	if c.Iterations == 0 {
+		c.Iterations = 2
+	}
+	if c.Parallelism == 0 {
+		c.Parallelism = 1
+	}
+}
+
+var _ = Schema.Add("PasswordHashingScryptConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"log_n": { "type": "integer", "minimum": 14, "maximum": 20 },
+		"block_size": { "type": "integer", "minimum": 8, "maximum": 32 },
+		"parallelism": { "type": "integer", "minimum": 1, "maximum": 16 }
+	}
+}
+`)
+
+type PasswordHashingScryptConfig struct {
+	// LogN is the base 2 logarithm of the CPU/memory cost parameter N.
+	LogN        int `json:"log_n,omitempty"`
+	BlockSize   int `json:"block_size,omitempty"`
+	Parallelism int `json:"parallelism,omitempty"`
+}
+
+func (c *PasswordHashingScryptConfig) SetDefaults() {
+	if c.LogN == 0 {
+		c.LogN = 15
+	}
+	if c.BlockSize == 0 {
+		c.BlockSize = 8
+	}
+	if c.Parallelism == 0 {
+		c.Parallelism = 1
+	}
+}
+
 var _ = Schema.Add("PasswordPolicyConfig", `
 {
 	"type": "object",
@@ -53,7 +181,8 @@ var _ = Schema.Add("PasswordPolicyConfig", `
 		"minimum_guessable_level": { "type": "integer" },
 		"excluded_keywords": { "type": "array", "items": { "type": "string" } },
 		"history_size": { "type": "integer" },
-		"history_days": { "$ref": "#/$defs/DurationDays" }
+		"history_days": { "$ref": "#/$defs/DurationDays" },
+		"breached_password_check": { "$ref": "#/$defs/BreachedPasswordCheckConfig" }
 	}
 }
 `)
@@ -68,6 +197,8 @@ type PasswordPolicyConfig struct {
 	ExcludedKeywords      []string     `json:"excluded_keywords,omitempty"`
 	HistorySize           int          `json:"history_size,omitempty"`
 	HistoryDays           DurationDays `json:"history_days,omitempty"`
+
+	BreachedPasswordCheck *BreachedPasswordCheckConfig `json:"breached_password_check,omitempty"`
 }
 
 func (c *PasswordPolicyConfig) IsEnabled() bool {
@@ -80,6 +211,58 @@ func (c *PasswordPolicyConfig) SetDefaults() {
 	}
 }
 
+var _ = Schema.Add("BreachedPasswordCheckConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"enabled": { "type": "boolean" },
+		"api_enabled": { "type": "boolean" },
+		"api_endpoint": { "type": "string", "format": "uri" },
+		"failure_mode": { "$ref": "#/$defs/BreachedPasswordCheckFailureMode" },
+		"force_change_on_login": { "type": "boolean" }
+	}
+}
+`)
+
+// BreachedPasswordCheckConfig configures checking passwords against known breached passwords.
+// The breached password list is loaded from the app resource breached_passwords.txt,
+// and optionally from an API compatible with the range API of Have I Been Pwned.
+type BreachedPasswordCheckConfig struct {
+	Enabled            bool                             `json:"enabled,omitempty"`
+	APIEnabled         bool                             `json:"api_enabled,omitempty"`
+	APIEndpoint        string                           `json:"api_endpoint,omitempty"`
+	FailureMode        BreachedPasswordCheckFailureMode `json:"failure_mode,omitempty"`
+	ForceChangeOnLogin bool                             `json:"force_change_on_login,omitempty"`
+}
+
+const DefaultBreachedPasswordCheckAPIEndpoint = "https://api.pwnedpasswords.com/range/"
+
+func (c *BreachedPasswordCheckConfig) SetDefaults() {
+	if c.APIEndpoint == "" {
+		c.APIEndpoint = DefaultBreachedPasswordCheckAPIEndpoint
+	}
+	if c.FailureMode == "" {
+		c.FailureMode = BreachedPasswordCheckFailureModeOpen
+	}
+}
+
+var _ = Schema.Add("BreachedPasswordCheckFailureMode", `
+{
+	"type": "string",
+	"enum": ["open", "closed"]
+}
+`)
+
+// BreachedPasswordCheckFailureMode decides whether a new password is accepted
+// when the breached password check cannot be performed.
+type BreachedPasswordCheckFailureMode string
+
+const (
+	BreachedPasswordCheckFailureModeOpen   BreachedPasswordCheckFailureMode = "open"
+	BreachedPasswordCheckFailureModeClosed BreachedPasswordCheckFailureMode = "closed"
+)
+
 var _ = Schema.Add("AuthenticatorTOTPConfig", `
 {
 	"type": "object",
@@ -141,17 +324,75 @@ var _ = Schema.Add("AuthenticatorOOBEmailConfig", `
 	"type": "object",
 	"additionalProperties": false,
 	"properties": {
-		"maximum": { "type": "integer" }
+		"maximum": { "type": "integer" },
+		"email_otp_mode": { "$ref": "#/$defs/AuthenticatorEmailOTPMode" }
 	}
 }
 `)
 
 type AuthenticatorOOBEmailConfig struct {
-	Maximum *int `json:"maximum,omitempty"`
+	Maximum      *int                      `json:"maximum,omitempty"`
+	EmailOTPMode AuthenticatorEmailOTPMode `json:"email_otp_mode,omitempty"`
 }
 
 func (c *AuthenticatorOOBEmailConfig) SetDefaults() {
 	if c.Maximum == nil {
 		c.Maximum = newInt(99)
 	}
+	if c.EmailOTPMode == "" {
+		c.EmailOTPMode = AuthenticatorEmailOTPModeCode
+	}
 }
+
+var _ = Schema.Add("AuthenticatorEmailOTPMode", `
+{
+	"type": "string",
+	"enum": ["code", "magic_link"]
+}
+`)
+
+type AuthenticatorEmailOTPMode string
+
+const (
+	// AuthenticatorEmailOTPModeCode sends a numeric code only.
+	AuthenticatorEmailOTPModeCode AuthenticatorEmailOTPMode = "code"
+	// AuthenticatorEmailOTPModeMagicLink sends a magic link in addition to the code.
+	// Opening the link on any device completes the authentication in the original interaction.
+	AuthenticatorEmailOTPModeMagicLink AuthenticatorEmailOTPMode = "magic_link"
+)
+
+var _ = Schema.Add("AuthenticatorPasskeyConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"maximum": { "type": "integer" },
+		"user_verification": {
+			"type": "string",
+			"enum": ["required", "preferred", "discouraged"]
+		}
+	}
+}
+`)
+
+type AuthenticatorPasskeyConfig struct {
+	Maximum          *int                    `json:"maximum,omitempty"`
+	UserVerification PasskeyUserVerification `json:"user_verification,omitempty"`
+}
+
+func (c *AuthenticatorPasskeyConfig) SetDefaults() {
+	if c.Maximum == nil {
+		c.Maximum = newInt(99)
+	}
+	if c.UserVerification == "" {
+		c.UserVerification = PasskeyUserVerificationPreferred
+	}
+}
+
+type PasskeyUserVerification string
+
+const (
+	PasskeyUserVerificationRequired    PasskeyUserVerification = "required"
+	PasskeyUserVerificationPreferred   PasskeyUserVerification = "preferred"
+	PasskeyUserVerificationDiscouraged PasskeyUserVerification = "discouraged"
+)
@@ -40,7 +40,8 @@ var _ = Schema.Add("AppConfig", `
 		"user_profile": { "$ref": "#/$defs/UserProfileConfig" },
 		"forgot_password": { "$ref": "#/$defs/ForgotPasswordConfig" },
 		"welcome_message": { "$ref": "#/$defs/WelcomeMessageConfig" },
-		"verification": { "$ref": "#/$defs/VerificationConfig" }
+		"verification": { "$ref": "#/$defs/VerificationConfig" },
+		"rate_limits": { "$ref": "#/$defs/RateLimitsConfig" }
 	},
 	"required": ["id", "http"]
 }
@@ -68,6 +69,8 @@ type AppConfig struct {
 	ForgotPassword *ForgotPasswordConfig `json:"forgot_password,omitempty"`
 	WelcomeMessage *WelcomeMessageConfig `json:"welcome_message,omitempty"`
 	Verification   *VerificationConfig   `json:"verification,omitempty"`
+
+	RateLimits *RateLimitsConfig `json:"rate_limits,omitempty"`
 }
 
 // nolint: gocyclo
@@ -78,11 +81,24 @@ func (c *AppConfig) Validate(ctx *validation.Context) {
 				"refresh token lifetime must be greater than or equal to access token lifetime",
 			)
 		}
+		for _, grantType := range client.GrantTypes {
+			if grantType == "client_credentials" && !client.IsConfidential() {
+				ctx.Child("oauth", "clients", strconv.Itoa(i), "grant_types").EmitErrorMessage(
+					"client_credentials grant requires a confidential client",
+				)
+			}
+		}
 	}
 
 	oAuthProviderIDs := map[string]struct{}{}
 	oauthProviderAliases := map[string]struct{}{}
 	for i, provider := range c.Identity.OAuth.Providers {
+		if provider.Type == OAuthSSOProviderTypeSAML && provider.SAMLIdPEntityID() == "" {
+			ctx.Child("identity", "oauth", "providers", strconv.Itoa(i), "idp_metadata").
+				EmitErrorMessage("invalid SAML IdP metadata")
+			continue
+		}
+
 		// Ensure provider ID is not duplicated
 		// Except WeChat provider with different app type
 		providerID := map[string]interface{}{}
@@ -158,6 +174,12 @@ func (c *AppConfig) Validate(ctx *validation.Context) {
 		}
 	}
 
+	lockout := c.Authentication.Lockout
+	if lockout.MaximumDuration < lockout.MinimumDuration {
+		ctx.Child("authentication", "lockout", "maximum_duration_seconds").
+			EmitErrorMessage("maximum duration must be greater than or equal to minimum duration")
+	}
+
 	phoneInputPinnedOK := true
 	phoneInputAllowListMap := make(map[string]bool)
 	for _, alpha2 := range c.UI.PhoneInput.AllowList {
@@ -206,6 +228,19 @@ func (c *AppConfig) Validate(ctx *validation.Context) {
 		customAttributeIDs[customAttributeConfig.ID] = struct{}{}
 		customAttributePointers[customAttributeConfig.Pointer] = struct{}{}
 	}
+
+	for i, h := range c.Hook.BlockingHandlers {
+		if _, ok := HookScriptPath(h.URL); IsHookScriptURL(h.URL) && !ok {
+			ctx.Child("hook", "blocking_handlers", strconv.Itoa(i), "url").
+				EmitErrorMessage("invalid hook script URL")
+		}
+	}
+	for i, h := range c.Hook.NonBlockingHandlers {
+		if IsHookScriptURL(h.URL) {
+			ctx.Child("hook", "non_blocking_handlers", strconv.Itoa(i), "url").
+				EmitErrorMessage("hook script is only supported for blocking events")
+		}
+	}
 }
 
 func Parse(inputYAML []byte) (*AppConfig, error) {
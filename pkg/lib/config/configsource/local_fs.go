@@ -137,8 +137,9 @@ func (s *LocalFS) reload() error {
 	}
 
 	appCtx = &config.AppContext{
-		AppFs:     appCtx.AppFs,
-		Resources: appCtx.Resources,
+		AppFs: appCtx.AppFs,
+		// Use a new manager to discard resources cached in the old one.
+		Resources: resource.NewManager(appCtx.Resources.Registry, appCtx.Resources.Fs),
 		Config:    newConfig,
 		PlanName:  LocalFSPlanName,
 	}
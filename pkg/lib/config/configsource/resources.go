@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"strconv"
 
 	"sigs.k8s.io/yaml"
@@ -183,6 +184,43 @@ func (d AuthgearYAMLDescriptor) validate(original *config.AppConfig, incoming *c
 		}
 	}
 
+	for _, name := range config.RateLimitNames {
+		ceiling := fc.RateLimits.Get(name)
+		if ceiling == nil || !ceiling.IsEnabled() {
+			continue
+		}
+		incomingPolicy := incoming.RateLimits.Get(name)
+		if incomingPolicy == nil || reflect.DeepEqual(incomingPolicy, original.RateLimits.Get(name)) {
+			continue
+		}
+		if !incomingPolicy.IsEnabled() {
+			validationCtx.Child(
+				"rate_limits",
+				string(name),
+				"enabled",
+			).EmitErrorMessage("rate limit cannot be disabled")
+			continue
+		}
+		if incomingPolicy.Size == 0 && incomingPolicy.Period == 0 {
+			continue
+		}
+		// Compare with the ceiling if only one of size and period is overridden.
+		size, period := incomingPolicy.Rate(ceiling.Size, ceiling.Period.Duration())
+		if ceiling.Exceeds(size, period) {
+			validationCtx.Child(
+				"rate_limits",
+				string(name),
+			).EmitErrorMessage(
+				fmt.Sprintf("exceed the maximum rate limit, actual: %d per %v, expected: %d per %v",
+					size,
+					period,
+					ceiling.Size,
+					ceiling.Period.Duration(),
+				),
+			)
+		}
+	}
+
 	// Check custom attributes not removed nor edited.
 	for _, originalCustomAttr := range original.UserProfile.CustomAttributes.Attributes {
 		found := false
@@ -195,4 +195,80 @@ user_profile:
 			So(err, ShouldBeNil)
 		})
 	})
+
+	Convey("AuthgearYAML rate limits", t, func() {
+		path := "authgear.yaml"
+		featureConfig := config.NewEffectiveDefaultFeatureConfig()
+		featureConfig.RateLimits.SMSMessage = &config.RateLimitFeatureConfig{
+			Size:   10,
+			Period: 60,
+		}
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, ContextKeyFeatureConfig, featureConfig)
+		app := resource.LeveledAferoFs{FsLevel: resource.FsLevelApp}
+		descriptor := &AuthgearYAMLDescriptor{}
+
+		update := func(original string, incoming string) error {
+			_, err := descriptor.UpdateResource(
+				ctx,
+				nil,
+				&resource.ResourceFile{
+					Location: resource.Location{
+						Fs:   app,
+						Path: path,
+					},
+					Data: []byte(original),
+				},
+				[]byte(incoming),
+			)
+			return err
+		}
+
+		original := `id: test
+http:
+  public_origin: http://test
+`
+
+		Convey("Rate limit within the ceiling can be configured", func() {
+			err := update(original, `id: test
+http:
+  public_origin: http://test
+rate_limits:
+  sms_message:
+    size: 20
+    period_seconds: 120
+    sliding_window: true
+  signup:
+    size: 1000
+`)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Rate limit cannot exceed the ceiling", func() {
+			err := update(original, `id: test
+http:
+  public_origin: http://test
+rate_limits:
+  sms_message:
+    size: 20
+    period_seconds: 60
+`)
+			So(err, ShouldBeError, `invalid authgear.yaml:
+/rate_limits/sms_message: exceed the maximum rate limit, actual: 20 per 1m0s, expected: 10 per 1m0s`)
+		})
+
+		Convey("Rate limit cannot be disabled if there is a ceiling", func() {
+			err := update(original, `id: test
+http:
+  public_origin: http://test
+rate_limits:
+  sms_message:
+    enabled: false
+  signup:
+    enabled: false
+`)
+			So(err, ShouldBeError, `invalid authgear.yaml:
+/rate_limits/sms_message/enabled: rate limit cannot be disabled`)
+		})
+	})
 }
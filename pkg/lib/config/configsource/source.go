@@ -74,6 +74,10 @@ func (c *Controller) ReloadApp(appID string) {
 	c.Handle.ReloadApp(appID)
 }
 
+func (c *Controller) ResolveContext(appID string) (*config.AppContext, error) {
+	return c.ContextResolver.ResolveContext(appID)
+}
+
 func (c *Controller) GetConfigSource() *ConfigSource {
 	return &ConfigSource{
 		AppIDResolver:   c.AppIDResolver,
@@ -19,8 +19,12 @@ func setFieldDefaults(value interface{}) {
 		case reflect.Struct:
 			numField := t.NumField()
 			for j := 0; j < numField; j++ {
-				field := v.Field(j)
 				ft := t.Field(j)
+				// Unexported fields are not part of the config.
+				if ft.PkgPath != "" {
+					continue
+				}
+				field := v.Field(j)
 				set(ft.Type, field)
 			}
 		case reflect.Ptr:
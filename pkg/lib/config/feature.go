@@ -18,7 +18,8 @@ var _ = FeatureConfigSchema.Add("FeatureConfig", `
 		"ui": { "$ref": "#/$defs/UIFeatureConfig" },
 		"oauth": { "$ref": "#/$defs/OAuthFeatureConfig" },
 		"hook": { "$ref": "#/$defs/HookFeatureConfig" },
-		"audit_log": { "$ref": "#/$defs/AuditLogFeatureConfig" }
+		"audit_log": { "$ref": "#/$defs/AuditLogFeatureConfig" },
+		"rate_limits": { "$ref": "#/$defs/RateLimitsFeatureConfig" }
 	}
 }
 `)
@@ -31,6 +32,7 @@ type FeatureConfig struct {
 	OAuth          *OAuthFeatureConfig          `json:"oauth,omitempty"`
 	Hook           *HookFeatureConfig           `json:"hook,omitempty"`
 	AuditLog       *AuditLogFeatureConfig       `json:"audit_log,omitempty"`
+	RateLimits     *RateLimitsFeatureConfig     `json:"rate_limits,omitempty"`
 }
 
 func ParseFeatureConfig(inputYAML []byte) (*FeatureConfig, error) {
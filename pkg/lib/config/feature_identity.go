@@ -91,7 +91,9 @@ var _ = FeatureConfigSchema.Add("OAuthSSOProvidersFeatureConfig", `
 		"azureadv2": { "$ref": "#/$defs/OAuthSSOProviderFeatureConfig" },
 		"adfs": { "$ref": "#/$defs/OAuthSSOProviderFeatureConfig" },
 		"apple": { "$ref": "#/$defs/OAuthSSOProviderFeatureConfig" },
-		"wechat": { "$ref": "#/$defs/OAuthSSOProviderFeatureConfig" }
+		"wechat": { "$ref": "#/$defs/OAuthSSOProviderFeatureConfig" },
+		"oidc": { "$ref": "#/$defs/OAuthSSOProviderFeatureConfig" },
+		"saml": { "$ref": "#/$defs/OAuthSSOProviderFeatureConfig" }
 	}
 }
 `)
@@ -104,6 +106,8 @@ type OAuthSSOProvidersFeatureConfig struct {
 	ADFS      *OAuthSSOProviderFeatureConfig `json:"adfs,omitempty"`
 	Apple     *OAuthSSOProviderFeatureConfig `json:"apple,omitempty"`
 	Wechat    *OAuthSSOProviderFeatureConfig `json:"wechat,omitempty"`
+	OIDC      *OAuthSSOProviderFeatureConfig `json:"oidc,omitempty"`
+	SAML      *OAuthSSOProviderFeatureConfig `json:"saml,omitempty"`
 }
 
 var _ = FeatureConfigSchema.Add("OAuthSSOProviderFeatureConfig", `
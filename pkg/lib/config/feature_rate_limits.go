@@ -0,0 +1,121 @@
+package config
+
+import (
+	"time"
+)
+
+var _ = FeatureConfigSchema.Add("RateLimitsFeatureConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"request": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"signup": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"signup_anonymous": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"account_enumeration": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"verification_send_code": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"verification_verify_code": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"oob_send_code": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"sms_message": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"email_message": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"forgot_password_send_code": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"forgot_password_verify_code": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"authenticate_secret": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"mfa_recovery_code": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"mfa_device_token": { "$ref": "#/$defs/RateLimitFeatureConfig" },
+		"device_verify_user_code": { "$ref": "#/$defs/RateLimitFeatureConfig" }
+	}
+}
+`)
+
+// RateLimitsFeatureConfig limits the rate limit policies an app can configure.
+type RateLimitsFeatureConfig struct {
+	Request                  *RateLimitFeatureConfig `json:"request,omitempty"`
+	Signup                   *RateLimitFeatureConfig `json:"signup,omitempty"`
+	SignupAnonymous          *RateLimitFeatureConfig `json:"signup_anonymous,omitempty"`
+	AccountEnumeration       *RateLimitFeatureConfig `json:"account_enumeration,omitempty"`
+	VerificationSendCode     *RateLimitFeatureConfig `json:"verification_send_code,omitempty"`
+	VerificationVerifyCode   *RateLimitFeatureConfig `json:"verification_verify_code,omitempty"`
+	OOBSendCode              *RateLimitFeatureConfig `json:"oob_send_code,omitempty"`
+	SMSMessage               *RateLimitFeatureConfig `json:"sms_message,omitempty"`
+	EmailMessage             *RateLimitFeatureConfig `json:"email_message,omitempty"`
+	ForgotPasswordSendCode   *RateLimitFeatureConfig `json:"forgot_password_send_code,omitempty"`
+	ForgotPasswordVerifyCode *RateLimitFeatureConfig `json:"forgot_password_verify_code,omitempty"`
+	AuthenticateSecret       *RateLimitFeatureConfig `json:"authenticate_secret,omitempty"`
+	MFARecoveryCode          *RateLimitFeatureConfig `json:"mfa_recovery_code,omitempty"`
+	MFADeviceToken           *RateLimitFeatureConfig `json:"mfa_device_token,omitempty"`
+	DeviceVerifyUserCode     *RateLimitFeatureConfig `json:"device_verify_user_code,omitempty"`
+}
+
+// Get returns the ceiling of the policy, or nil if it is absent.
+func (c *RateLimitsFeatureConfig) Get(name RateLimitName) *RateLimitFeatureConfig {
+	if c == nil {
+		return nil
+	}
+	switch name {
+	case RateLimitRequest:
+		return c.Request
+	case RateLimitSignup:
+		return c.Signup
+	case RateLimitSignupAnonymous:
+		return c.SignupAnonymous
+	case RateLimitAccountEnumeration:
+		return c.AccountEnumeration
+	case RateLimitVerificationSendCode:
+		return c.VerificationSendCode
+	case RateLimitVerificationVerifyCode:
+		return c.VerificationVerifyCode
+	case RateLimitOOBSendCode:
+		return c.OOBSendCode
+	case RateLimitSMSMessage:
+		return c.SMSMessage
+	case RateLimitEmailMessage:
+		return c.EmailMessage
+	case RateLimitForgotPasswordSendCode:
+		return c.ForgotPasswordSendCode
+	case RateLimitForgotPasswordVerifyCode:
+		return c.ForgotPasswordVerifyCode
+	case RateLimitAuthenticateSecret:
+		return c.AuthenticateSecret
+	case RateLimitMFARecoveryCode:
+		return c.MFARecoveryCode
+	case RateLimitMFADeviceToken:
+		return c.MFADeviceToken
+	case RateLimitDeviceVerifyUserCode:
+		return c.DeviceVerifyUserCode
+	default:
+		return nil
+	}
+}
+
+var _ = FeatureConfigSchema.Add("RateLimitFeatureConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"size": { "type": "integer", "minimum": 1 },
+		"period_seconds": { "type": "integer", "minimum": 1 }
+	},
+	"required": ["size", "period_seconds"]
+}
+`)
+
+// RateLimitFeatureConfig is the ceiling of a rate limit policy.
+// An app cannot allow more than Size requests per Period, nor disable the policy.
+type RateLimitFeatureConfig struct {
+	Size   int             `json:"size,omitempty"`
+	Period DurationSeconds `json:"period_seconds,omitempty"`
+}
+
+func (c *RateLimitFeatureConfig) IsEnabled() bool {
+	return c.Size > 0 && c.Period > 0
+}
+
+// Exceeds reports whether the rate of size per period exceeds the ceiling.
+func (c *RateLimitFeatureConfig) Exceeds(size int, period time.Duration) bool {
+	if !c.IsEnabled() {
+		return false
+	}
+	// size / period > c.Size / c.Period
+	return float64(size)*c.Period.Duration().Seconds() > float64(c.Size)*period.Seconds()
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/rsa"
+	"crypto/x509"
 	"encoding/json"
 	mathrand "math/rand"
 	"time"
@@ -78,6 +80,11 @@ func GenerateSecretConfigFromOptions(opts *GenerateSecretConfigOptions, createdA
 		Data: &WebhookKeyMaterials{Set: wrapInSet(secrets.GenerateOctetKey(createdAt, rng))},
 	})
 
+	items = append(items, SecretItem{
+		Key:  SAMLSPKeyMaterialsKey,
+		Data: &SAMLSPKeyMaterials{Set: wrapInSet(generateSAMLSPKey(createdAt, rng))},
+	})
+
 	items = append(items, SecretItem{
 		Key:  AdminAPIAuthKeyKey,
 		Data: &AdminAPIAuthKey{Set: wrapInSet(secrets.GenerateRSAKey(createdAt, rng))},
@@ -99,6 +106,22 @@ func marshalSecretData(items []SecretItem) {
 	}
 }
 
+func generateSAMLSPKey(createdAt time.Time, rng *mathrand.Rand) jwk.Key {
+	jwkKey := secrets.GenerateRSAKey(createdAt, rng)
+
+	var key rsa.PrivateKey
+	if err := jwkKey.Raw(&key); err != nil {
+		panic(err)
+	}
+	cert, err := secrets.SelfSignCertificate(&key, jwkKey.KeyID())
+	if err != nil {
+		panic(err)
+	}
+	_ = jwkKey.Set(jwk.X509CertChainKey, []*x509.Certificate{cert})
+
+	return jwkKey
+}
+
 func wrapInSet(jwkKey jwk.Key) jwk.Set {
 	keySet := jwk.NewSet()
 	_ = keySet.Add(jwkKey)
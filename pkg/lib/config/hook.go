@@ -1,5 +1,10 @@
 package config
 
+import (
+	"net/url"
+	"regexp"
+)
+
 var _ = Schema.Add("HookConfig", `
 {
 	"type": "object",
@@ -34,16 +39,18 @@ var _ = Schema.Add("BlockingHookHandlersConfig", `
 	"type": "object",
 	"additionalProperties": false,
 	"properties": {
-		"event": { "type": "string", "enum" : ["user.pre_create", "user.profile.pre_update"] },
-		"url": { "type": "string", "format": "uri" }
+		"event": { "type": "string", "enum" : ["user.pre_create", "user.profile.pre_update", "user.pre_authenticate", "identity.pre_create", "identity.pre_delete", "oauth.pre_token_issue", "oidc.jwt.pre_create"] },
+		"url": { "type": "string", "format": "uri" },
+		"filters": { "type": "array", "items": { "$ref": "#/$defs/HookHandlerFilterConfig" } }
 	},
 	"required": ["event", "url"]
 }
 `)
 
 type BlockingHandlersConfig struct {
-	Event string `json:"event"`
-	URL   string `json:"url"`
+	Event   string                    `json:"event"`
+	URL     string                    `json:"url"`
+	Filters []HookHandlerFilterConfig `json:"filters,omitempty"`
 }
 
 var _ = Schema.Add("NonBlockingHookHandlersConfig", `
@@ -71,17 +78,85 @@ var _ = Schema.Add("NonBlockingHookHandlersConfig", `
 					"identity.username.removed",
 					"identity.username.updated",
 					"identity.oauth.connected",
-					"identity.oauth.disconnected"
+					"identity.oauth.disconnected",
+					"user.password.changed",
+					"user.password.reset",
+					"user.recovery_codes.regenerated",
+					"user.disabled",
+					"user.reenabled",
+					"user.deleted",
+					"authenticator.created",
+					"authenticator.deleted",
+					"identity.verified",
+					"session.created",
+					"session.revoked"
 				]
 			}
 		},
-		"url": { "type": "string", "format": "uri" }
+		"url": { "type": "string", "format": "uri" },
+		"filters": { "type": "array", "items": { "$ref": "#/$defs/HookHandlerFilterConfig" } }
 	},
 	"required": ["events", "url"]
 }
 `)
 
 type NonBlockingHandlersConfig struct {
-	Events []string `json:"events"`
-	URL    string   `json:"url"`
+	Events  []string                  `json:"events"`
+	URL     string                    `json:"url"`
+	Filters []HookHandlerFilterConfig `json:"filters,omitempty"`
+}
+
+var _ = Schema.Add("HookHandlerFilterConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"pointer": { "type": "string", "format": "json-pointer" },
+		"values": {
+			"type": "array",
+			"minItems": 1,
+			"items": { "type": ["string", "number", "boolean", "null"] }
+		}
+	},
+	"required": ["pointer", "values"]
+}
+`)
+
+// HookHandlerFilterConfig matches the event if the value at Pointer of the event
+// equals to any of Values. An event is delivered to a handler only if it matches
+// all filters of the handler.
+type HookHandlerFilterConfig struct {
+	Pointer string        `json:"pointer"`
+	Values  []interface{} `json:"values"`
+}
+
+// HookScriptURLScheme is the URL scheme of hook handlers referring to
+// scripts stored as app resources, e.g. authgearjs://hooks/check_email.js.
+const HookScriptURLScheme = "authgearjs"
+
+var hookScriptPathRegexp = regexp.MustCompile(`^hooks/[a-zA-Z0-9_-]+\.js$`)
+
+func IsHookScriptURL(urlStr string) bool {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == HookScriptURLScheme
+}
+
+// HookScriptPath returns the resource path of the script referred by the hook handler URL.
+func HookScriptPath(urlStr string) (string, bool) {
+	u, err := url.Parse(urlStr)
+	if err != nil || u.Scheme != HookScriptURLScheme {
+		return "", false
+	}
+	p := u.Host + u.Path
+	if !IsHookScriptPath(p) {
+		return "", false
+	}
+	return p, true
+}
+
+func IsHookScriptPath(p string) bool {
+	return hookScriptPathRegexp.MatchString(p)
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/xml"
 	"fmt"
 	"strconv"
 )
@@ -291,7 +292,9 @@ var _ = Schema.Add("OAuthSSOProviderType", `
 		"azureadv2",
 		"adfs",
 		"apple",
-		"wechat"
+		"wechat",
+		"oidc",
+		"saml"
 	]
 }
 `)
@@ -322,6 +325,12 @@ func (t OAuthSSOProviderType) Scope() string {
 	case OAuthSSOProviderTypeWechat:
 		// https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/Wechat_webpage_authorization.html
 		return "snsapi_userinfo"
+	case OAuthSSOProviderTypeOIDC:
+		// https://openid.net/specs/openid-connect-core-1_0.html#ScopeClaims
+		return "openid profile email"
+	case OAuthSSOProviderTypeSAML:
+		// SAML does not have scope.
+		return ""
 	}
 
 	panic(fmt.Sprintf("oauth: unknown provider type %s", string(t)))
@@ -335,6 +344,8 @@ const (
 	OAuthSSOProviderTypeADFS      OAuthSSOProviderType = "adfs"
 	OAuthSSOProviderTypeApple     OAuthSSOProviderType = "apple"
 	OAuthSSOProviderTypeWechat    OAuthSSOProviderType = "wechat"
+	OAuthSSOProviderTypeOIDC      OAuthSSOProviderType = "oidc"
+	OAuthSSOProviderTypeSAML      OAuthSSOProviderType = "saml"
 )
 
 var OAuthSSOProviderTypes = []OAuthSSOProviderType{
@@ -345,6 +356,8 @@ var OAuthSSOProviderTypes = []OAuthSSOProviderType{
 	OAuthSSOProviderTypeADFS,
 	OAuthSSOProviderTypeApple,
 	OAuthSSOProviderTypeWechat,
+	OAuthSSOProviderTypeOIDC,
+	OAuthSSOProviderTypeSAML,
 }
 
 var _ = Schema.Add("OAuthSSOWeChatAppType", `
@@ -381,10 +394,24 @@ var _ = Schema.Add("OAuthSSOProviderConfig", `
 		"account_id": { "type": "string", "format": "wechat_account_id"},
 		"is_sandbox_account": { "type": "boolean" },
 		"wechat_redirect_uris": { "type": "array", "items": { "type": "string", "format": "uri" } },
-		"discovery_document_endpoint": { "type": "string", "format": "uri" }
+		"discovery_document_endpoint": { "type": "string", "format": "uri" },
+		"issuer": { "type": "string", "format": "uri" },
+		"scope": { "type": "string", "minLength": 1 },
+		"claim_mapping": { "$ref": "#/$defs/OAuthSSOClaimMappingConfig" },
+		"idp_metadata": { "type": "string", "minLength": 1 },
+		"name_id_format": { "type": "string", "format": "uri" }
 	},
-	"required": ["alias", "type", "client_id"],
+	"required": ["alias", "type"],
 	"allOf": [
+		{
+			"if": { "properties": { "type": { "const": "saml" } } },
+			"then": {
+				"required": ["idp_metadata"]
+			},
+			"else": {
+				"required": ["client_id"]
+			}
+		},
 		{
 			"if": { "properties": { "type": { "const": "apple" } } },
 			"then": {
@@ -408,6 +435,18 @@ var _ = Schema.Add("OAuthSSOProviderConfig", `
 			"then": {
 				"required": ["discovery_document_endpoint"]
 			}
+		},
+		{
+			"if": { "properties": { "type": { "const": "oidc" } } },
+			"then": {
+				"required": ["issuer"]
+			}
+		},
+		{
+			"if": { "required": ["scope"] },
+			"then": {
+				"properties": { "type": { "const": "oidc" } }
+			}
 		}
 	]
 }
@@ -435,6 +474,17 @@ type OAuthSSOProviderConfig struct {
 
 	// DiscoveryDocumentEndpoint is specific to `adfs`.
 	DiscoveryDocumentEndpoint string `json:"discovery_document_endpoint,omitempty"`
+
+	// Issuer and Scope are specific to `oidc`.
+	Issuer string `json:"issuer,omitempty"`
+	Scope  string `json:"scope,omitempty"`
+
+	// ClaimMapping is specific to `oidc` and `saml`.
+	ClaimMapping *OAuthSSOClaimMappingConfig `json:"claim_mapping,omitempty"`
+
+	// IdPMetadata and NameIDFormat are specific to `saml`.
+	IdPMetadata  string `json:"idp_metadata,omitempty"`
+	NameIDFormat string `json:"name_id_format,omitempty"`
 }
 
 func (c *OAuthSSOProviderConfig) SetDefaults() {
@@ -443,6 +493,15 @@ func (c *OAuthSSOProviderConfig) SetDefaults() {
 	}
 }
 
+// EffectiveScope returns the scope to be requested from the provider.
+// Only `oidc` allows overriding the scope.
+func (c *OAuthSSOProviderConfig) EffectiveScope() string {
+	if c.Type == OAuthSSOProviderTypeOIDC && c.Scope != "" {
+		return c.Scope
+	}
+	return c.Type.Scope()
+}
+
 func (c *OAuthSSOProviderConfig) ProviderID() ProviderID {
 	keys := map[string]interface{}{}
 	switch c.Type {
@@ -492,6 +551,21 @@ func (c *OAuthSSOProviderConfig) ProviderID() ProviderID {
 		// https://developers.weixin.qq.com/miniprogram/en/dev/framework/open-ability/union-id.html
 		keys["account_id"] = c.AccountID
 		keys["is_sandbox_account"] = strconv.FormatBool(c.IsSandboxAccount)
+	case OAuthSSOProviderTypeOIDC:
+		// Generic OIDC provider.
+		// sub is locally unique within the issuer.
+		// Therefore, ProviderID is Type + issuer.
+		//
+		// Rotating the OAuth application is OK.
+		// But changing the issuer is problematic.
+		keys["issuer"] = c.Issuer
+	case OAuthSSOProviderTypeSAML:
+		// SAML IdP.
+		// NameID is scoped to the entity ID of the IdP.
+		// Therefore, ProviderID is Type + entity ID.
+		//
+		// Changing the IdP is problematic.
+		keys["entity_id"] = c.SAMLIdPEntityID()
 	}
 
 	return ProviderID{
@@ -500,6 +574,51 @@ func (c *OAuthSSOProviderConfig) ProviderID() ProviderID {
 	}
 }
 
+var _ = Schema.Add("OAuthSSOClaimMappingConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"email": { "type": "string", "minLength": 1 },
+		"email_verified": { "type": "string", "minLength": 1 },
+		"phone_number": { "type": "string", "minLength": 1 },
+		"phone_number_verified": { "type": "string", "minLength": 1 },
+		"preferred_username": { "type": "string", "minLength": 1 },
+		"family_name": { "type": "string", "minLength": 1 },
+		"given_name": { "type": "string", "minLength": 1 },
+		"middle_name": { "type": "string", "minLength": 1 },
+		"name": { "type": "string", "minLength": 1 },
+		"nickname": { "type": "string", "minLength": 1 },
+		"picture": { "type": "string", "minLength": 1 },
+		"profile": { "type": "string", "minLength": 1 },
+		"website": { "type": "string", "minLength": 1 },
+		"gender": { "type": "string", "minLength": 1 },
+		"birthdate": { "type": "string", "minLength": 1 },
+		"zoneinfo": { "type": "string", "minLength": 1 },
+		"locale": { "type": "string", "minLength": 1 },
+		"address": { "type": "string", "minLength": 1 }
+	}
+}
+`)
+
+// OAuthSSOClaimMappingConfig maps a standard attribute to
+// the name of the claim in the ID token of the provider.
+// For `saml`, the claim is the name of the attribute in the assertion.
+type OAuthSSOClaimMappingConfig map[string]string
+
+// SAMLIdPEntityID returns the entity ID in IdPMetadata.
+// It returns an empty string if IdPMetadata is invalid.
+func (c *OAuthSSOProviderConfig) SAMLIdPEntityID() string {
+	var metadata struct {
+		XMLName  xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+		EntityID string   `xml:"entityID,attr"`
+	}
+	if err := xml.Unmarshal([]byte(c.IdPMetadata), &metadata); err != nil {
+		return ""
+	}
+	return metadata.EntityID
+}
+
 // ProviderID combining with a subject ID identifies an user from an external system.
 type ProviderID struct {
 	Type string
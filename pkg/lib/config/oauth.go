@@ -33,10 +33,10 @@ var _ = Schema.Add("OAuthClientConfig", `
 		"name": { "type": "string" },
 		"redirect_uris": {
 			"type": "array",
-			"items": { "type": "string", "format": "uri" },
-			"minItems": 1
+			"items": { "type": "string", "format": "uri" }
 		},
 		"grant_types": { "type": "array", "items": { "type": "string" } },
+		"client_credentials_scopes": { "type": "array", "items": { "type": "string", "minLength": 1 } },
 		"response_types": { "type": "array", "items": { "type": "string" } },
 		"post_logout_redirect_uris": { "type": "array", "items": { "type": "string", "format": "uri" } },
 		"access_token_lifetime_seconds": { "$ref": "#/$defs/DurationSeconds", "minimum": 300 },
@@ -44,26 +44,45 @@ var _ = Schema.Add("OAuthClientConfig", `
 		"refresh_token_idle_timeout_enabled": { "type": "boolean" },
 		"refresh_token_idle_timeout_seconds": { "$ref": "#/$defs/DurationSeconds" },
 		"issue_jwt_access_token": { "type": "boolean" },
-		"is_first_party": { "type": "boolean" }
+		"is_first_party": { "type": "boolean" },
+		"token_endpoint_auth_method": { "$ref": "#/$defs/OAuthClientAuthMethod" },
+		"require_pushed_authorization_requests": { "type": "boolean" },
+		"custom_claims_failure_policy": { "$ref": "#/$defs/OAuthCustomClaimsFailurePolicy" }
 	},
-	"required": ["name", "client_id", "redirect_uris"]
+	"required": ["name", "client_id"],
+	"if": {
+		"properties": { "grant_types": { "const": ["client_credentials"] } },
+		"required": ["grant_types"]
+	},
+	"else": {
+		"properties": { "redirect_uris": { "minItems": 1 } },
+		"required": ["redirect_uris"]
+	}
 }
 `)
 
 type OAuthClientConfig struct {
-	ClientID                       string          `json:"client_id,omitempty"`
-	ClientURI                      string          `json:"client_uri,omitempty"`
-	Name                           string          `json:"name,omitempty"`
-	RedirectURIs                   []string        `json:"redirect_uris,omitempty"`
-	GrantTypes                     []string        `json:"grant_types,omitempty"`
-	ResponseTypes                  []string        `json:"response_types,omitempty"`
-	PostLogoutRedirectURIs         []string        `json:"post_logout_redirect_uris,omitempty"`
-	AccessTokenLifetime            DurationSeconds `json:"access_token_lifetime_seconds,omitempty"`
-	RefreshTokenLifetime           DurationSeconds `json:"refresh_token_lifetime_seconds,omitempty"`
-	RefreshTokenIdleTimeoutEnabled *bool           `json:"refresh_token_idle_timeout_enabled,omitempty"`
-	RefreshTokenIdleTimeout        DurationSeconds `json:"refresh_token_idle_timeout_seconds,omitempty"`
-	IssueJWTAccessToken            bool            `json:"issue_jwt_access_token,omitempty"`
-	IsFirstParty                   *bool           `json:"is_first_party,omitempty"`
+	ClientID                       string                `json:"client_id,omitempty"`
+	ClientURI                      string                `json:"client_uri,omitempty"`
+	Name                           string                `json:"name,omitempty"`
+	RedirectURIs                   []string              `json:"redirect_uris,omitempty"`
+	GrantTypes                     []string              `json:"grant_types,omitempty"`
+	ClientCredentialsScopes        []string              `json:"client_credentials_scopes,omitempty"`
+	ResponseTypes                  []string              `json:"response_types,omitempty"`
+	PostLogoutRedirectURIs         []string              `json:"post_logout_redirect_uris,omitempty"`
+	AccessTokenLifetime            DurationSeconds       `json:"access_token_lifetime_seconds,omitempty"`
+	RefreshTokenLifetime           DurationSeconds       `json:"refresh_token_lifetime_seconds,omitempty"`
+	RefreshTokenIdleTimeoutEnabled *bool                 `json:"refresh_token_idle_timeout_enabled,omitempty"`
+	RefreshTokenIdleTimeout        DurationSeconds       `json:"refresh_token_idle_timeout_seconds,omitempty"`
+	IssueJWTAccessToken            bool                  `json:"issue_jwt_access_token,omitempty"`
+	IsFirstParty                   *bool                 `json:"is_first_party,omitempty"`
+	TokenEndpointAuthMethod        OAuthClientAuthMethod `json:"token_endpoint_auth_method,omitempty"`
+	// RequirePushedAuthorizationRequests requires the client to push
+	// its authorization requests to the pushed authorization request endpoint.
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests,omitempty"`
+	// CustomClaimsFailurePolicy decides whether tokens are issued without custom claims
+	// if the custom claims cannot be resolved.
+	CustomClaimsFailurePolicy OAuthCustomClaimsFailurePolicy `json:"custom_claims_failure_policy,omitempty"`
 }
 
 func (c *OAuthClientConfig) SetDefaults() {
@@ -91,3 +110,45 @@ func (c *OAuthClientConfig) SetDefaults() {
 		c.IsFirstParty = &b
 	}
 }
+
+// IsConfidential reports whether the client must authenticate itself
+// at the token endpoint with a client secret.
+func (c *OAuthClientConfig) IsConfidential() bool {
+	switch c.TokenEndpointAuthMethod {
+	case OAuthClientAuthMethodClientSecretBasic, OAuthClientAuthMethodClientSecretPost:
+		return true
+	default:
+		return false
+	}
+}
+
+var _ = Schema.Add("OAuthClientAuthMethod", `
+{
+	"type": "string",
+	"enum": ["none", "client_secret_basic", "client_secret_post"]
+}
+`)
+
+type OAuthClientAuthMethod string
+
+const (
+	OAuthClientAuthMethodNone              OAuthClientAuthMethod = "none"
+	OAuthClientAuthMethodClientSecretBasic OAuthClientAuthMethod = "client_secret_basic"
+	OAuthClientAuthMethodClientSecretPost  OAuthClientAuthMethod = "client_secret_post"
+)
+
+var _ = Schema.Add("OAuthCustomClaimsFailurePolicy", `
+{
+	"type": "string",
+	"enum": ["fail_closed", "fallback"]
+}
+`)
+
+type OAuthCustomClaimsFailurePolicy string
+
+const (
+	// OAuthCustomClaimsFailurePolicyFailClosed fails the token issuance.
+	OAuthCustomClaimsFailurePolicyFailClosed OAuthCustomClaimsFailurePolicy = "fail_closed"
+	// OAuthCustomClaimsFailurePolicyFallback issues tokens without custom claims.
+	OAuthCustomClaimsFailurePolicyFallback OAuthCustomClaimsFailurePolicy = "fallback"
+)
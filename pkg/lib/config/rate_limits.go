@@ -0,0 +1,168 @@
+package config
+
+import (
+	"time"
+)
+
+var _ = Schema.Add("RateLimitsConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"request": { "$ref": "#/$defs/RateLimitConfig" },
+		"signup": { "$ref": "#/$defs/RateLimitConfig" },
+		"signup_anonymous": { "$ref": "#/$defs/RateLimitConfig" },
+		"account_enumeration": { "$ref": "#/$defs/RateLimitConfig" },
+		"verification_send_code": { "$ref": "#/$defs/RateLimitConfig" },
+		"verification_verify_code": { "$ref": "#/$defs/RateLimitConfig" },
+		"oob_send_code": { "$ref": "#/$defs/RateLimitConfig" },
+		"sms_message": { "$ref": "#/$defs/RateLimitConfig" },
+		"email_message": { "$ref": "#/$defs/RateLimitConfig" },
+		"forgot_password_send_code": { "$ref": "#/$defs/RateLimitConfig" },
+		"forgot_password_verify_code": { "$ref": "#/$defs/RateLimitConfig" },
+		"authenticate_secret": { "$ref": "#/$defs/RateLimitConfig" },
+		"mfa_recovery_code": { "$ref": "#/$defs/RateLimitConfig" },
+		"mfa_device_token": { "$ref": "#/$defs/RateLimitConfig" },
+		"device_verify_user_code": { "$ref": "#/$defs/RateLimitConfig" }
+	}
+}
+`)
+
+// RateLimitName is the name of a rate limit policy.
+type RateLimitName string
+
+const (
+	RateLimitRequest                  RateLimitName = "request"
+	RateLimitSignup                   RateLimitName = "signup"
+	RateLimitSignupAnonymous          RateLimitName = "signup_anonymous"
+	RateLimitAccountEnumeration       RateLimitName = "account_enumeration"
+	RateLimitVerificationSendCode     RateLimitName = "verification_send_code"
+	RateLimitVerificationVerifyCode   RateLimitName = "verification_verify_code"
+	RateLimitOOBSendCode              RateLimitName = "oob_send_code"
+	RateLimitSMSMessage               RateLimitName = "sms_message"
+	RateLimitEmailMessage             RateLimitName = "email_message"
+	RateLimitForgotPasswordSendCode   RateLimitName = "forgot_password_send_code"
+	RateLimitForgotPasswordVerifyCode RateLimitName = "forgot_password_verify_code"
+	RateLimitAuthenticateSecret       RateLimitName = "authenticate_secret"
+	RateLimitMFARecoveryCode          RateLimitName = "mfa_recovery_code"
+	RateLimitMFADeviceToken           RateLimitName = "mfa_device_token"
+	RateLimitDeviceVerifyUserCode     RateLimitName = "device_verify_user_code"
+)
+
+var RateLimitNames = []RateLimitName{
+	RateLimitRequest,
+	RateLimitSignup,
+	RateLimitSignupAnonymous,
+	RateLimitAccountEnumeration,
+	RateLimitVerificationSendCode,
+	RateLimitVerificationVerifyCode,
+	RateLimitOOBSendCode,
+	RateLimitSMSMessage,
+	RateLimitEmailMessage,
+	RateLimitForgotPasswordSendCode,
+	RateLimitForgotPasswordVerifyCode,
+	RateLimitAuthenticateSecret,
+	RateLimitMFARecoveryCode,
+	RateLimitMFADeviceToken,
+	RateLimitDeviceVerifyUserCode,
+}
+
+// RateLimitsConfig overrides the rate limit policies.
+// The built-in policy is used if it is not overridden.
+type RateLimitsConfig struct {
+	Request                  *RateLimitConfig `json:"request,omitempty"`
+	Signup                   *RateLimitConfig `json:"signup,omitempty"`
+	SignupAnonymous          *RateLimitConfig `json:"signup_anonymous,omitempty"`
+	AccountEnumeration       *RateLimitConfig `json:"account_enumeration,omitempty"`
+	VerificationSendCode     *RateLimitConfig `json:"verification_send_code,omitempty"`
+	VerificationVerifyCode   *RateLimitConfig `json:"verification_verify_code,omitempty"`
+	OOBSendCode              *RateLimitConfig `json:"oob_send_code,omitempty"`
+	SMSMessage               *RateLimitConfig `json:"sms_message,omitempty"`
+	EmailMessage             *RateLimitConfig `json:"email_message,omitempty"`
+	ForgotPasswordSendCode   *RateLimitConfig `json:"forgot_password_send_code,omitempty"`
+	ForgotPasswordVerifyCode *RateLimitConfig `json:"forgot_password_verify_code,omitempty"`
+	AuthenticateSecret       *RateLimitConfig `json:"authenticate_secret,omitempty"`
+	MFARecoveryCode          *RateLimitConfig `json:"mfa_recovery_code,omitempty"`
+	MFADeviceToken           *RateLimitConfig `json:"mfa_device_token,omitempty"`
+	DeviceVerifyUserCode     *RateLimitConfig `json:"device_verify_user_code,omitempty"`
+}
+
+// Get returns the override of the policy, or nil if it is absent.
+func (c *RateLimitsConfig) Get(name RateLimitName) *RateLimitConfig {
+	if c == nil {
+		return nil
+	}
+	switch name {
+	case RateLimitRequest:
+		return c.Request
+	case RateLimitSignup:
+		return c.Signup
+	case RateLimitSignupAnonymous:
+		return c.SignupAnonymous
+	case RateLimitAccountEnumeration:
+		return c.AccountEnumeration
+	case RateLimitVerificationSendCode:
+		return c.VerificationSendCode
+	case RateLimitVerificationVerifyCode:
+		return c.VerificationVerifyCode
+	case RateLimitOOBSendCode:
+		return c.OOBSendCode
+	case RateLimitSMSMessage:
+		return c.SMSMessage
+	case RateLimitEmailMessage:
+		return c.EmailMessage
+	case RateLimitForgotPasswordSendCode:
+		return c.ForgotPasswordSendCode
+	case RateLimitForgotPasswordVerifyCode:
+		return c.ForgotPasswordVerifyCode
+	case RateLimitAuthenticateSecret:
+		return c.AuthenticateSecret
+	case RateLimitMFARecoveryCode:
+		return c.MFARecoveryCode
+	case RateLimitMFADeviceToken:
+		return c.MFADeviceToken
+	case RateLimitDeviceVerifyUserCode:
+		return c.DeviceVerifyUserCode
+	default:
+		return nil
+	}
+}
+
+var _ = Schema.Add("RateLimitConfig", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"enabled": { "type": "boolean" },
+		"size": { "type": "integer", "minimum": 1 },
+		"period_seconds": { "$ref": "#/$defs/DurationSeconds", "minimum": 1 },
+		"sliding_window": { "type": "boolean" }
+	}
+}
+`)
+
+type RateLimitConfig struct {
+	Enabled *bool           `json:"enabled,omitempty"`
+	Size    int             `json:"size,omitempty"`
+	Period  DurationSeconds `json:"period_seconds,omitempty"`
+	// SlidingWindow makes a token available again once the period has elapsed
+	// since the token was taken, instead of refilling all tokens at the end of the period.
+	SlidingWindow bool `json:"sliding_window,omitempty"`
+}
+
+func (c *RateLimitConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// Rate returns the size and the period in effect,
+// using the given defaults if they are not overridden.
+func (c *RateLimitConfig) Rate(defaultSize int, defaultPeriod time.Duration) (size int, period time.Duration) {
+	size, period = defaultSize, defaultPeriod
+	if c.Size != 0 {
+		size = c.Size
+	}
+	if c.Period != 0 {
+		period = c.Period.Duration()
+	}
+	return
+}
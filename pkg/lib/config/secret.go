@@ -2,6 +2,7 @@ package config
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -186,12 +187,22 @@ func (c *SecretConfig) Validate(appConfig *AppConfig) error {
 	require(RedisCredentialsKey, "redis credentials")
 	require(AdminAPIAuthKeyKey, "admin API auth key materials")
 
-	if len(appConfig.Identity.OAuth.Providers) > 0 {
+	var oauthProviders []OAuthSSOProviderConfig
+	var samlProviders []OAuthSSOProviderConfig
+	for _, p := range appConfig.Identity.OAuth.Providers {
+		if p.Type == OAuthSSOProviderTypeSAML {
+			samlProviders = append(samlProviders, p)
+		} else {
+			oauthProviders = append(oauthProviders, p)
+		}
+	}
+
+	if len(oauthProviders) > 0 {
 		require(OAuthClientCredentialsKey, "OAuth client credentials")
 		secretIndex, data, _ := c.LookupDataWithIndex(OAuthClientCredentialsKey)
 		oauth, ok := data.(*OAuthClientCredentials)
 		if ok {
-			for _, p := range appConfig.Identity.OAuth.Providers {
+			for _, p := range oauthProviders {
 				var matchedItem *OAuthClientCredentialsItem = nil
 				var matchedItemIndex int = -1
 				for index := range oauth.Items {
@@ -220,31 +231,70 @@ func (c *SecretConfig) Validate(appConfig *AppConfig) error {
 		}
 	}
 
+	if len(samlProviders) > 0 {
+		require(SAMLSPKeyMaterialsKey, "SAML SP key materials")
+	}
+
+	var confidentialClients []OAuthClientConfig
+	for _, c := range appConfig.OAuth.Clients {
+		if c.IsConfidential() {
+			confidentialClients = append(confidentialClients, c)
+		}
+	}
+	if len(confidentialClients) > 0 {
+		require(OAuthClientSecretsKey, "OAuth client secrets")
+		if secrets, ok := c.LookupData(OAuthClientSecretsKey).(*OAuthClientSecrets); ok {
+			for _, client := range confidentialClients {
+				if _, ok := secrets.Lookup(client.ClientID); !ok {
+					ctx.EmitErrorMessage(fmt.Sprintf("OAuth client secret for '%s' is required", client.ClientID))
+				}
+			}
+		}
+	}
+
 	require(OAuthKeyMaterialsKey, "OAuth key materials")
 	require(CSRFKeyMaterialsKey, "CSRF key materials")
 	if len(appConfig.Hook.BlockingHandlers) > 0 || len(appConfig.Hook.NonBlockingHandlers) > 0 {
 		require(WebhookKeyMaterialsKey, "web-hook signing key materials")
 	}
 
+	if secretIndex, data, ok := c.LookupDataWithIndex(WebhookHandlerCredentialsKey); ok {
+		if creds, ok := data.(*WebhookHandlerCredentials); ok {
+			for index, item := range creds.Items {
+				if item.TLSClientCertificate == "" {
+					continue
+				}
+				if _, err := tls.X509KeyPair([]byte(item.TLSClientCertificate), []byte(item.TLSClientKey)); err != nil {
+					ctx.Child("secrets", fmt.Sprintf("%d", secretIndex), "data", "items", fmt.Sprintf("%d", index)).
+						EmitErrorMessage(fmt.Sprintf("invalid TLS client certificate: %s", err))
+				}
+			}
+		}
+	}
+
 	return ctx.Error("invalid secrets")
 }
 
 type SecretKey string
 
 const (
-	DatabaseCredentialsKey      SecretKey = "db"
-	AuditDatabaseCredentialsKey SecretKey = "audit.db"
-	ElasticsearchCredentialsKey SecretKey = "elasticsearch"
-	RedisCredentialsKey         SecretKey = "redis"
-	AnalyticRedisCredentialsKey SecretKey = "analytic.redis"
-	AdminAPIAuthKeyKey          SecretKey = "admin-api.auth"
-	OAuthClientCredentialsKey   SecretKey = "sso.oauth.client"
-	SMTPServerCredentialsKey    SecretKey = "mail.smtp"
-	TwilioCredentialsKey        SecretKey = "sms.twilio"
-	NexmoCredentialsKey         SecretKey = "sms.nexmo"
-	OAuthKeyMaterialsKey        SecretKey = "oauth"
-	CSRFKeyMaterialsKey         SecretKey = "csrf"
-	WebhookKeyMaterialsKey      SecretKey = "webhook"
+	DatabaseCredentialsKey       SecretKey = "db"
+	AuditDatabaseCredentialsKey  SecretKey = "audit.db"
+	ElasticsearchCredentialsKey  SecretKey = "elasticsearch"
+	RedisCredentialsKey          SecretKey = "redis"
+	AnalyticRedisCredentialsKey  SecretKey = "analytic.redis"
+	EventStreamCredentialsKey    SecretKey = "event-stream"
+	AdminAPIAuthKeyKey           SecretKey = "admin-api.auth"
+	OAuthClientCredentialsKey    SecretKey = "sso.oauth.client"
+	OAuthClientSecretsKey        SecretKey = "oauth.client-secrets"
+	SMTPServerCredentialsKey     SecretKey = "mail.smtp"
+	TwilioCredentialsKey         SecretKey = "sms.twilio"
+	NexmoCredentialsKey          SecretKey = "sms.nexmo"
+	OAuthKeyMaterialsKey         SecretKey = "oauth"
+	CSRFKeyMaterialsKey          SecretKey = "csrf"
+	WebhookKeyMaterialsKey       SecretKey = "webhook"
+	WebhookHandlerCredentialsKey SecretKey = "webhook.handlers"
+	SAMLSPKeyMaterialsKey        SecretKey = "sso.saml.sp"
 )
 
 func (key SecretKey) IsUpdatable() bool {
@@ -267,19 +317,23 @@ type secretKeyDef struct {
 }
 
 var secretItemKeys = map[SecretKey]secretKeyDef{
-	DatabaseCredentialsKey:      {"DatabaseCredentials", func() SecretItemData { return &DatabaseCredentials{} }},
-	AuditDatabaseCredentialsKey: {"AuditDatabaseCredentials", func() SecretItemData { return &AuditDatabaseCredentials{} }},
-	ElasticsearchCredentialsKey: {"ElasticsearchCredentials", func() SecretItemData { return &ElasticsearchCredentials{} }},
-	RedisCredentialsKey:         {"RedisCredentials", func() SecretItemData { return &RedisCredentials{} }},
-	AnalyticRedisCredentialsKey: {"AnalyticRedisCredentials", func() SecretItemData { return &AnalyticRedisCredentials{} }},
-	AdminAPIAuthKeyKey:          {"AdminAPIAuthKey", func() SecretItemData { return &AdminAPIAuthKey{} }},
-	OAuthClientCredentialsKey:   {"OAuthClientCredentials", func() SecretItemData { return &OAuthClientCredentials{} }},
-	SMTPServerCredentialsKey:    {"SMTPServerCredentials", func() SecretItemData { return &SMTPServerCredentials{} }},
-	TwilioCredentialsKey:        {"TwilioCredentials", func() SecretItemData { return &TwilioCredentials{} }},
-	NexmoCredentialsKey:         {"NexmoCredentials", func() SecretItemData { return &NexmoCredentials{} }},
-	OAuthKeyMaterialsKey:        {"OAuthKeyMaterials", func() SecretItemData { return &OAuthKeyMaterials{} }},
-	CSRFKeyMaterialsKey:         {"CSRFKeyMaterials", func() SecretItemData { return &CSRFKeyMaterials{} }},
-	WebhookKeyMaterialsKey:      {"WebhookKeyMaterials", func() SecretItemData { return &WebhookKeyMaterials{} }},
+	DatabaseCredentialsKey:       {"DatabaseCredentials", func() SecretItemData { return &DatabaseCredentials{} }},
+	AuditDatabaseCredentialsKey:  {"AuditDatabaseCredentials", func() SecretItemData { return &AuditDatabaseCredentials{} }},
+	ElasticsearchCredentialsKey:  {"ElasticsearchCredentials", func() SecretItemData { return &ElasticsearchCredentials{} }},
+	RedisCredentialsKey:          {"RedisCredentials", func() SecretItemData { return &RedisCredentials{} }},
+	AnalyticRedisCredentialsKey:  {"AnalyticRedisCredentials", func() SecretItemData { return &AnalyticRedisCredentials{} }},
+	EventStreamCredentialsKey:    {"EventStreamCredentials", func() SecretItemData { return &EventStreamCredentials{} }},
+	AdminAPIAuthKeyKey:           {"AdminAPIAuthKey", func() SecretItemData { return &AdminAPIAuthKey{} }},
+	OAuthClientCredentialsKey:    {"OAuthClientCredentials", func() SecretItemData { return &OAuthClientCredentials{} }},
+	OAuthClientSecretsKey:        {"OAuthClientSecrets", func() SecretItemData { return &OAuthClientSecrets{} }},
+	SMTPServerCredentialsKey:     {"SMTPServerCredentials", func() SecretItemData { return &SMTPServerCredentials{} }},
+	TwilioCredentialsKey:         {"TwilioCredentials", func() SecretItemData { return &TwilioCredentials{} }},
+	NexmoCredentialsKey:          {"NexmoCredentials", func() SecretItemData { return &NexmoCredentials{} }},
+	OAuthKeyMaterialsKey:         {"OAuthKeyMaterials", func() SecretItemData { return &OAuthKeyMaterials{} }},
+	CSRFKeyMaterialsKey:          {"CSRFKeyMaterials", func() SecretItemData { return &CSRFKeyMaterials{} }},
+	WebhookKeyMaterialsKey:       {"WebhookKeyMaterials", func() SecretItemData { return &WebhookKeyMaterials{} }},
+	WebhookHandlerCredentialsKey: {"WebhookHandlerCredentials", func() SecretItemData { return &WebhookHandlerCredentials{} }},
+	SAMLSPKeyMaterialsKey:        {"SAMLSPKeyMaterials", func() SecretItemData { return &SAMLSPKeyMaterials{} }},
 }
 
 var _ = SecretConfigSchema.AddJSON("SecretKey", map[string]interface{}{
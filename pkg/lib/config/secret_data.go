@@ -1,9 +1,14 @@
 package config
 
 import (
+	"crypto/rsa"
+	"crypto/x509"
 	"encoding/json"
+	"fmt"
 
 	"github.com/lestrrat-go/jwx/jwk"
+
+	"github.com/authgear/authgear-server/pkg/util/secrets"
 )
 
 var _ = SecretConfigSchema.Add("DatabaseCredentials", `
@@ -121,6 +126,35 @@ func (c *AnalyticRedisCredentials) SensitiveStrings() []string {
 	return []string{c.RedisURL}
 }
 
+var _ = SecretConfigSchema.Add("EventStreamCredentials", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"redis_url": { "type": "string" },
+		"kafka_rest_proxy_url": { "type": "string", "format": "uri" },
+		"stream": { "type": "string", "minLength": 1 }
+	},
+	"oneOf": [
+		{ "required": ["redis_url"] },
+		{ "required": ["kafka_rest_proxy_url"] }
+	],
+	"required": ["stream"]
+}
+`)
+
+// EventStreamCredentials configures the event bus receiving the non-blocking events.
+// Stream is the key of Redis stream, or the Kafka topic.
+type EventStreamCredentials struct {
+	RedisURL          string `json:"redis_url,omitempty"`
+	KafkaRESTProxyURL string `json:"kafka_rest_proxy_url,omitempty"`
+	Stream            string `json:"stream,omitempty"`
+}
+
+func (c *EventStreamCredentials) SensitiveStrings() []string {
+	return []string{c.RedisURL, c.KafkaRESTProxyURL}
+}
+
 var _ = SecretConfigSchema.Add("OAuthClientCredentials", `
 {
 	"type": "object",
@@ -178,6 +212,68 @@ func (c *OAuthClientCredentialsItem) SensitiveStrings() []string {
 	return []string{c.ClientSecret}
 }
 
+var _ = SecretConfigSchema.Add("OAuthClientSecrets", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"items": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"client_id": {
+						"type": "string"
+					},
+					"client_secret_hashes": {
+						"type": "array",
+						"items": { "type": "string" },
+						"minItems": 1
+					}
+				},
+				"required": ["client_id", "client_secret_hashes"]
+			}
+		}
+	},
+	"required": ["items"]
+}
+`)
+
+// OAuthClientSecrets stores the hashed secrets of confidential OAuth clients.
+// More than one hash can be configured for a client to rotate its secret
+// without downtime.
+type OAuthClientSecrets struct {
+	Items []OAuthClientSecretsItem `json:"items,omitempty"`
+}
+
+func (c *OAuthClientSecrets) Lookup(clientID string) (*OAuthClientSecretsItem, bool) {
+	for _, item := range c.Items {
+		if item.ClientID == clientID {
+			ii := item
+			return &ii, true
+		}
+	}
+	return nil, false
+}
+
+func (c *OAuthClientSecrets) SensitiveStrings() []string {
+	var out []string
+	for _, item := range c.Items {
+		out = append(out, item.SensitiveStrings()...)
+	}
+	return out
+}
+
+type OAuthClientSecretsItem struct {
+	ClientID           string   `json:"client_id,omitempty"`
+	ClientSecretHashes []string `json:"client_secret_hashes,omitempty"`
+}
+
+func (c *OAuthClientSecretsItem) SensitiveStrings() []string {
+	return c.ClientSecretHashes
+}
+
 var _ = SecretConfigSchema.Add("SMTPMode", `
 {
 	"type": "string",
@@ -349,6 +445,80 @@ func (c *CSRFKeyMaterials) SensitiveStrings() []string {
 	return nil
 }
 
+var _ = SecretConfigSchema.Add("SAMLSPKeyMaterials", `{ "$ref": "#/$defs/JWS" }`)
+
+// SAMLSPKeyMaterials are the RSA keys to sign SAML authentication requests.
+// The first key is used. Its certificate is the first certificate of x5c, or
+// a self-signed certificate of the key if x5c is absent.
+type SAMLSPKeyMaterials struct {
+	jwk.Set
+
+	// privateKey and certificate are loaded from Set when the secret is loaded.
+	privateKey  *rsa.PrivateKey
+	certificate *x509.Certificate
+}
+
+var _ json.Marshaler = &SAMLSPKeyMaterials{}
+var _ json.Unmarshaler = &SAMLSPKeyMaterials{}
+
+func (c *SAMLSPKeyMaterials) MarshalJSON() ([]byte, error) {
+	return c.Set.(interface{}).(json.Marshaler).MarshalJSON()
+}
+func (c *SAMLSPKeyMaterials) UnmarshalJSON(b []byte) error {
+	if c.Set == nil {
+		c.Set = jwk.NewSet()
+	}
+	err := c.Set.(interface{}).(json.Unmarshaler).UnmarshalJSON(b)
+	if err != nil {
+		return err
+	}
+	return c.load()
+}
+
+func (c *SAMLSPKeyMaterials) load() error {
+	jwkKey, ok := c.Set.Get(0)
+	if !ok {
+		return fmt.Errorf("SAML SP key materials are empty")
+	}
+
+	var key rsa.PrivateKey
+	if err := jwkKey.Raw(&key); err != nil {
+		return fmt.Errorf("invalid SAML SP key: %w", err)
+	}
+
+	var cert *x509.Certificate
+	if chain := jwkKey.X509CertChain(); len(chain) > 0 {
+		cert = chain[0]
+		if !key.PublicKey.Equal(cert.PublicKey) {
+			return fmt.Errorf("SAML SP certificate does not match the key")
+		}
+	} else {
+		var err error
+		cert, err = secrets.SelfSignCertificate(&key, jwkKey.KeyID())
+		if err != nil {
+			return err
+		}
+	}
+
+	c.privateKey = &key
+	c.certificate = cert
+	return nil
+}
+
+// PrivateKey returns the loaded private key, or nil if the secret is not loaded.
+func (c *SAMLSPKeyMaterials) PrivateKey() *rsa.PrivateKey {
+	return c.privateKey
+}
+
+// Certificate returns the certificate of PrivateKey.
+func (c *SAMLSPKeyMaterials) Certificate() *x509.Certificate {
+	return c.certificate
+}
+
+func (c *SAMLSPKeyMaterials) SensitiveStrings() []string {
+	return nil
+}
+
 var _ = SecretConfigSchema.Add("WebhookKeyMaterials", `{ "$ref": "#/$defs/JWS" }`)
 
 type WebhookKeyMaterials struct {
@@ -372,6 +542,77 @@ func (c *WebhookKeyMaterials) SensitiveStrings() []string {
 	return nil
 }
 
+var _ = SecretConfigSchema.Add("WebhookHandlerCredentials", `
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"items": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"url": { "type": "string", "format": "uri" },
+					"headers": {
+						"type": "object",
+						"additionalProperties": { "type": "string" }
+					},
+					"tls_client_certificate": { "type": "string", "minLength": 1 },
+					"tls_client_key": { "type": "string", "minLength": 1 }
+				},
+				"required": ["url"],
+				"dependentRequired": {
+					"tls_client_certificate": ["tls_client_key"],
+					"tls_client_key": ["tls_client_certificate"]
+				}
+			}
+		}
+	},
+	"required": ["items"]
+}
+`)
+
+// WebhookHandlerCredentials configures the credentials of webhook handlers, looked up by handler URL.
+type WebhookHandlerCredentials struct {
+	Items []WebhookHandlerCredentialsItem `json:"items,omitempty"`
+}
+
+func (c *WebhookHandlerCredentials) Lookup(url string) (*WebhookHandlerCredentialsItem, bool) {
+	for _, item := range c.Items {
+		if item.URL == url {
+			ii := item
+			return &ii, true
+		}
+	}
+	return nil, false
+}
+
+func (c *WebhookHandlerCredentials) SensitiveStrings() []string {
+	var out []string
+	for _, item := range c.Items {
+		out = append(out, item.SensitiveStrings()...)
+	}
+	return out
+}
+
+// WebhookHandlerCredentialsItem contains the headers added to the requests to the handler,
+// and optionally the PEM-encoded client certificate and private key for mutual TLS.
+type WebhookHandlerCredentialsItem struct {
+	URL                  string            `json:"url,omitempty"`
+	Headers              map[string]string `json:"headers,omitempty"`
+	TLSClientCertificate string            `json:"tls_client_certificate,omitempty"`
+	TLSClientKey         string            `json:"tls_client_key,omitempty"`
+}
+
+func (c *WebhookHandlerCredentialsItem) SensitiveStrings() []string {
+	out := []string{c.TLSClientKey}
+	for _, value := range c.Headers {
+		out = append(out, value)
+	}
+	return out
+}
+
 var _ = SecretConfigSchema.Add("AdminAPIAuthKey", `{ "$ref": "#/$defs/JWS" }`)
 
 type AdminAPIAuthKey struct {
@@ -7,6 +7,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/audit"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
 	authenticatoroob "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
+	authenticatorpasskey "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/passkey"
 	authenticatorpassword "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
 	authenticatorservice "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/service"
 	authenticatortotp "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/totp"
@@ -16,6 +17,7 @@ import (
 	identityloginid "github.com/authgear/authgear-server/pkg/lib/authn/identity/loginid"
 	identityoauth "github.com/authgear/authgear-server/pkg/lib/authn/identity/oauth"
 	identityservice "github.com/authgear/authgear-server/pkg/lib/authn/identity/service"
+	"github.com/authgear/authgear-server/pkg/lib/authn/lockout"
 	"github.com/authgear/authgear-server/pkg/lib/authn/mfa"
 	"github.com/authgear/authgear-server/pkg/lib/authn/otp"
 	"github.com/authgear/authgear-server/pkg/lib/authn/sso"
@@ -23,6 +25,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/authn/user"
 	libes "github.com/authgear/authgear-server/pkg/lib/elasticsearch"
 	"github.com/authgear/authgear-server/pkg/lib/event"
+	"github.com/authgear/authgear-server/pkg/lib/eventstream"
 	"github.com/authgear/authgear-server/pkg/lib/facade"
 	featurecustomattrs "github.com/authgear/authgear-server/pkg/lib/feature/customattrs"
 	"github.com/authgear/authgear-server/pkg/lib/feature/forgotpassword"
@@ -45,6 +48,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/session/access"
 	"github.com/authgear/authgear-server/pkg/lib/session/idpsession"
 	"github.com/authgear/authgear-server/pkg/lib/translation"
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
 	"github.com/authgear/authgear-server/pkg/lib/web"
 	"github.com/authgear/authgear-server/pkg/util/template"
 )
@@ -85,16 +89,22 @@ var CommonDependencySet = wire.NewSet(
 		wire.Bind(new(welcomemessage.EventService), new(*event.Service)),
 		wire.Bind(new(featurestdattrs.EventService), new(*event.Service)),
 		wire.Bind(new(featurecustomattrs.EventService), new(*event.Service)),
+		wire.Bind(new(oauthhandler.EventService), new(*event.Service)),
 	),
 
 	wire.NewSet(
 		hook.DependencySet,
+		wire.Bind(new(oauthhandler.TokenHandlerHooks), new(*hook.Deliverer)),
 	),
 
 	wire.NewSet(
 		audit.DependencySet,
 	),
 
+	wire.NewSet(
+		eventstream.DependencySet,
+	),
+
 	wire.NewSet(
 		idpsession.DependencySet,
 
@@ -113,20 +123,25 @@ var CommonDependencySet = wire.NewSet(
 		wire.Bind(new(idpsession.AccessEventProvider), new(*access.EventProvider)),
 		wire.Bind(new(oidchandler.LogoutSessionManager), new(*session.Manager)),
 		wire.Bind(new(oauthhandler.SessionManager), new(*session.Manager)),
+		wire.Bind(new(oauth.AuthorizationServiceSessionManager), new(*session.Manager)),
 	),
 
 	wire.NewSet(
 		authenticatorpassword.DependencySet,
 		wire.Bind(new(facade.PasswordHistoryStore), new(*authenticatorpassword.HistoryStore)),
+		wire.Bind(new(userimport.PasswordAuthenticatorProvider), new(*authenticatorpassword.Provider)),
 		authenticatoroob.DependencySet,
 		wire.Bind(new(interaction.OOBAuthenticatorProvider), new(*authenticatoroob.Provider)),
 		wire.Bind(new(interaction.OOBCodeSender), new(*authenticatoroob.CodeSender)),
 		authenticatortotp.DependencySet,
+		authenticatorpasskey.DependencySet,
+		wire.Bind(new(interaction.PasskeyAuthenticatorProvider), new(*authenticatorpasskey.Provider)),
 
 		authenticatorservice.DependencySet,
 		wire.Bind(new(authenticatorservice.PasswordAuthenticatorProvider), new(*authenticatorpassword.Provider)),
 		wire.Bind(new(authenticatorservice.OOBOTPAuthenticatorProvider), new(*authenticatoroob.Provider)),
 		wire.Bind(new(authenticatorservice.TOTPAuthenticatorProvider), new(*authenticatortotp.Provider)),
+		wire.Bind(new(authenticatorservice.PasskeyAuthenticatorProvider), new(*authenticatorpasskey.Provider)),
 
 		wire.Bind(new(facade.AuthenticatorService), new(*authenticatorservice.Service)),
 		wire.Bind(new(user.AuthenticatorService), new(*authenticatorservice.Service)),
@@ -139,6 +154,14 @@ var CommonDependencySet = wire.NewSet(
 		wire.Bind(new(facade.MFAService), new(*mfa.Service)),
 	),
 
+	wire.NewSet(
+		lockout.DependencySet,
+
+		wire.Bind(new(interaction.AuthenticationLockoutService), new(*lockout.Service)),
+		wire.Bind(new(authenticatorservice.Lockout), new(*lockout.Service)),
+		wire.Bind(new(mfa.Lockout), new(*lockout.Service)),
+	),
+
 	wire.NewSet(
 		stdattrs.DependencySet,
 		wire.Bind(new(sso.StandardAttributesNormalizer), new(*stdattrs.Normalizer)),
@@ -148,6 +171,7 @@ var CommonDependencySet = wire.NewSet(
 		featurecustomattrs.DependencySet,
 		wire.Bind(new(user.CustomAttributesService), new(*featurecustomattrs.ServiceNoEvent)),
 		wire.Bind(new(hook.CustomAttributesServiceNoEvent), new(*featurecustomattrs.ServiceNoEvent)),
+		wire.Bind(new(userimport.CustomAttributesService), new(*featurecustomattrs.ServiceNoEvent)),
 	),
 
 	wire.NewSet(
@@ -170,6 +194,7 @@ var CommonDependencySet = wire.NewSet(
 		wire.Bind(new(facade.IdentityService), new(*identityservice.Service)),
 		wire.Bind(new(user.IdentityService), new(*identityservice.Service)),
 		wire.Bind(new(featurestdattrs.IdentityService), new(*identityservice.Service)),
+		wire.Bind(new(userimport.IdentityService), new(*identityservice.Service)),
 
 		wire.Bind(new(oauthhandler.PromotionCodeStore), new(*identityanonymous.StoreRedis)),
 		wire.Bind(new(oauthhandler.AnonymousIdentityProvider), new(*identityanonymous.Provider)),
@@ -198,6 +223,7 @@ var CommonDependencySet = wire.NewSet(
 		wire.Bind(new(oauthhandler.TokenHandlerUserFacade), new(*user.Queries)),
 		wire.Bind(new(oauthhandler.UserProvider), new(*user.Queries)),
 		wire.Bind(new(event.ResolverUserQueries), new(*user.Queries)),
+		wire.Bind(new(userimport.UserStore), new(*user.Store)),
 	),
 
 	wire.NewSet(
@@ -224,6 +250,9 @@ var CommonDependencySet = wire.NewSet(
 		oauthredis.DependencySet,
 		wire.Bind(new(oauth.AccessGrantStore), new(*oauthredis.Store)),
 		wire.Bind(new(oauth.CodeGrantStore), new(*oauthredis.Store)),
+		wire.Bind(new(oauth.DeviceCodeGrantStore), new(*oauthredis.Store)),
+		wire.Bind(new(oauth.PushedAuthorizationRequestStore), new(*oauthredis.Store)),
+		wire.Bind(new(oauth.ConsentRequestStore), new(*oauthredis.Store)),
 		wire.Bind(new(oauth.OfflineGrantStore), new(*oauthredis.Store)),
 		wire.Bind(new(oauth.AppSessionTokenStore), new(*oauthredis.Store)),
 		wire.Bind(new(oauth.AppSessionStore), new(*oauthredis.Store)),
@@ -233,6 +262,7 @@ var CommonDependencySet = wire.NewSet(
 		wire.Bind(new(session.AccessTokenSessionManager), new(*oauth.SessionManager)),
 		wire.Bind(new(facade.OAuthSessionManager), new(*oauth.SessionManager)),
 		wire.Bind(new(oauthhandler.OAuthURLProvider), new(*oauth.URLProvider)),
+		wire.Bind(new(oauthhandler.DeviceURLProvider), new(*oauth.URLProvider)),
 		wire.Value(oauthhandler.TokenGenerator(oauth.GenerateToken)),
 
 		oauthhandler.DependencySet,
@@ -242,6 +272,7 @@ var CommonDependencySet = wire.NewSet(
 		wire.Bind(new(oauthhandler.IDTokenVerifier), new(*oidc.IDTokenIssuer)),
 		wire.Bind(new(oauthhandler.IDTokenIssuer), new(*oidc.IDTokenIssuer)),
 		wire.Bind(new(oauthhandler.AccessTokenIssuer), new(*oauth.AccessTokenEncoding)),
+		wire.Bind(new(oauthhandler.IntrospectionAccessTokenDecoder), new(*oauth.AccessTokenEncoding)),
 		wire.Bind(new(oauth.UserClaimsProvider), new(*oidc.IDTokenIssuer)),
 
 		oidchandler.DependencySet,
@@ -258,6 +289,7 @@ var CommonDependencySet = wire.NewSet(
 		wire.Bind(new(user.VerificationService), new(*verification.Service)),
 		wire.Bind(new(facade.VerificationService), new(*verification.Service)),
 		wire.Bind(new(interaction.VerificationService), new(*verification.Service)),
+		wire.Bind(new(userimport.VerifiedClaimStore), new(*verification.StorePQ)),
 		wire.Bind(new(interaction.VerificationCodeSender), new(*verification.CodeSender)),
 	),
 
@@ -288,6 +320,8 @@ var CommonDependencySet = wire.NewSet(
 		wire.Bind(new(welcomemessage.RateLimiter), new(*ratelimit.Limiter)),
 		wire.Bind(new(mfa.RateLimiter), new(*ratelimit.Limiter)),
 		wire.Bind(new(verification.RateLimiter), new(*ratelimit.Limiter)),
+		wire.Bind(new(oauthhandler.TokenHandlerRateLimiter), new(*ratelimit.Limiter)),
+		wire.Bind(new(oauthhandler.DeviceAuthorizationRateLimiter), new(*ratelimit.Limiter)),
 	),
 
 	wire.NewSet(
@@ -301,5 +335,10 @@ var CommonDependencySet = wire.NewSet(
 		wire.Bind(new(facade.StdAttrsService), new(*featurestdattrs.Service)),
 		wire.Bind(new(interaction.StdAttrsService), new(*featurestdattrs.Service)),
 		wire.Bind(new(hook.StandardAttributesServiceNoEvent), new(*featurestdattrs.ServiceNoEvent)),
+		wire.Bind(new(userimport.StandardAttributesService), new(*featurestdattrs.ServiceNoEvent)),
+	),
+
+	wire.NewSet(
+		userimport.DependencySet,
 	),
 )
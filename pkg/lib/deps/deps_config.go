@@ -25,6 +25,10 @@ var configDeps = wire.NewSet(
 		"ForgotPassword",
 		"WelcomeMessage",
 		"Verification",
+		"RateLimits",
+	),
+	wire.FieldsOf(new(*config.AuthenticationConfig),
+		"Lockout",
 	),
 	wire.FieldsOf(new(*config.IdentityConfig),
 		"LoginID",
@@ -36,11 +40,13 @@ var configDeps = wire.NewSet(
 		"Password",
 		"TOTP",
 		"OOB",
+		"Passkey",
 	),
 	wire.FieldsOf(new(*config.FeatureConfig),
 		"Identity",
 		"UI",
 		"AuditLog",
+		"RateLimits",
 	),
 	ProvideDefaultLanguageTag,
 	ProvideSupportedLanguageTags,
@@ -61,14 +67,18 @@ var secretDeps = wire.NewSet(
 	ProvideElasticsearchCredentials,
 	ProvideRedisCredentials,
 	ProvideAnalyticRedisCredentials,
+	ProvideEventStreamCredentials,
 	ProvideAdminAPIAuthKeyMaterials,
 	ProvideOAuthClientCredentials,
+	ProvideOAuthClientSecrets,
 	ProvideSMTPServerCredentials,
 	ProvideTwilioCredentials,
 	ProvideNexmoCredentials,
 	ProvideOAuthKeyMaterials,
 	ProvideCSRFKeyMaterials,
 	ProvideWebhookKeyMaterials,
+	ProvideWebhookHandlerCredentials,
+	ProvideSAMLSPKeyMaterials,
 )
 
 func ProvideDatabaseCredentials(c *config.SecretConfig) *config.DatabaseCredentials {
@@ -96,6 +106,11 @@ func ProvideAnalyticRedisCredentials(c *config.SecretConfig) *config.AnalyticRed
 	return s
 }
 
+func ProvideEventStreamCredentials(c *config.SecretConfig) *config.EventStreamCredentials {
+	s, _ := c.LookupData(config.EventStreamCredentialsKey).(*config.EventStreamCredentials)
+	return s
+}
+
 func ProvideAdminAPIAuthKeyMaterials(c *config.SecretConfig) *config.AdminAPIAuthKey {
 	s, _ := c.LookupData(config.AdminAPIAuthKeyKey).(*config.AdminAPIAuthKey)
 	return s
@@ -106,6 +121,11 @@ func ProvideOAuthClientCredentials(c *config.SecretConfig) *config.OAuthClientCr
 	return s
 }
 
+func ProvideOAuthClientSecrets(c *config.SecretConfig) *config.OAuthClientSecrets {
+	s, _ := c.LookupData(config.OAuthClientSecretsKey).(*config.OAuthClientSecrets)
+	return s
+}
+
 func ProvideSMTPServerCredentials(c *config.SecretConfig) *config.SMTPServerCredentials {
 	s, _ := c.LookupData(config.SMTPServerCredentialsKey).(*config.SMTPServerCredentials)
 	return s
@@ -131,7 +151,17 @@ func ProvideCSRFKeyMaterials(c *config.SecretConfig) *config.CSRFKeyMaterials {
 	return s
 }
 
+func ProvideSAMLSPKeyMaterials(c *config.SecretConfig) *config.SAMLSPKeyMaterials {
+	s, _ := c.LookupData(config.SAMLSPKeyMaterialsKey).(*config.SAMLSPKeyMaterials)
+	return s
+}
+
 func ProvideWebhookKeyMaterials(c *config.SecretConfig) *config.WebhookKeyMaterials {
 	s, _ := c.LookupData(config.WebhookKeyMaterialsKey).(*config.WebhookKeyMaterials)
 	return s
 }
+
+func ProvideWebhookHandlerCredentials(c *config.SecretConfig) *config.WebhookHandlerCredentials {
+	s, _ := c.LookupData(config.WebhookHandlerCredentialsKey).(*config.WebhookHandlerCredentials)
+	return s
+}
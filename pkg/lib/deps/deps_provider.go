@@ -6,12 +6,16 @@ import (
 
 	"github.com/google/wire"
 
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/loginid"
 	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/config/configsource"
 	"github.com/authgear/authgear-server/pkg/lib/event"
+	"github.com/authgear/authgear-server/pkg/lib/eventstream"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
 	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
 	"github.com/authgear/authgear-server/pkg/lib/infra/db/globaldb"
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
 	"github.com/authgear/authgear-server/pkg/lib/web"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/resource"
@@ -50,14 +54,20 @@ var appRootDeps = wire.NewSet(
 		"AuditWriteDatabase",
 		"Redis",
 		"AnalyticRedis",
+		"EventStreamRedis",
 		"TaskQueue",
 		"Resources",
 	),
 
 	wire.Bind(new(event.Database), new(*appdb.Handle)),
+	wire.Bind(new(userimport.RunnerDatabase), new(*appdb.Handle)),
+	wire.Bind(new(hook.DeliveryServiceDatabase), new(*appdb.Handle)),
+	wire.Bind(new(eventstream.PublisherDatabase), new(*appdb.Handle)),
 	wire.Bind(new(template.ResourceManager), new(*resource.Manager)),
 	wire.Bind(new(loginid.ResourceManager), new(*resource.Manager)),
+	wire.Bind(new(password.ResourceManager), new(*resource.Manager)),
 	wire.Bind(new(web.ResourceManager), new(*resource.Manager)),
+	wire.Bind(new(hook.ResourceManager), new(*resource.Manager)),
 )
 
 var RootDependencySet = wire.NewSet(
@@ -14,6 +14,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/infra/redis"
 	"github.com/authgear/authgear-server/pkg/lib/infra/redis/analyticredis"
 	"github.com/authgear/authgear-server/pkg/lib/infra/redis/appredis"
+	"github.com/authgear/authgear-server/pkg/lib/infra/redis/eventstreamredis"
 	"github.com/authgear/authgear-server/pkg/lib/infra/task"
 	"github.com/authgear/authgear-server/pkg/util/httproute"
 	"github.com/authgear/authgear-server/pkg/util/log"
@@ -132,6 +133,17 @@ func (p *RootProvider) NewAppProvider(ctx context.Context, appCtx *config.AppCon
 		loggerFactory,
 	)
 
+	var eventStreamCredentials *config.EventStreamCredentials
+	if c := cfg.SecretConfig.LookupData(config.EventStreamCredentialsKey); c != nil {
+		eventStreamCredentials = c.(*config.EventStreamCredentials)
+	}
+	eventStreamRedis := eventstreamredis.NewHandle(
+		p.RedisPool,
+		cfg.AppConfig.Redis,
+		eventStreamCredentials,
+		loggerFactory,
+	)
+
 	provider := &AppProvider{
 		RootProvider:       p,
 		Context:            ctx,
@@ -142,6 +154,7 @@ func (p *RootProvider) NewAppProvider(ctx context.Context, appCtx *config.AppCon
 		AuditWriteDatabase: auditWriteDatabase,
 		Redis:              redis,
 		AnalyticRedis:      analyticRedis,
+		EventStreamRedis:   eventStreamRedis,
 		Resources:          appCtx.Resources,
 	}
 	provider.TaskQueue = p.TaskQueueFactory(provider)
@@ -197,6 +210,7 @@ type AppProvider struct {
 	AuditWriteDatabase *auditdb.WriteHandle
 	Redis              *appredis.Handle
 	AnalyticRedis      *analyticredis.Handle
+	EventStreamRedis   *eventstreamredis.Handle
 	TaskQueue          task.Queue
 	Resources          *resource.Manager
 }
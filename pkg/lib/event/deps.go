@@ -8,16 +8,22 @@ import (
 
 	"github.com/authgear/authgear-server/pkg/lib/audit"
 	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/eventstream"
 	"github.com/authgear/authgear-server/pkg/lib/hook"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 )
 
 var DependencySet = wire.NewSet(
 	NewLogger,
 	NewService,
+	NewRelayLogger,
+	NewRelay,
 	wire.Struct(new(StoreImpl), "*"),
 	wire.Struct(new(ResolverImpl), "*"),
 	wire.Bind(new(Store), new(*StoreImpl)),
+	wire.Bind(new(RelayStore), new(*StoreImpl)),
 	wire.Bind(new(Resolver), new(*ResolverImpl)),
 )
 
@@ -33,6 +39,8 @@ func NewService(
 	resolver Resolver,
 	hookSink *hook.Sink,
 	auditSink *audit.Sink,
+	eventStreamSink *eventstream.Sink,
+	taskQueue task.Queue,
 ) *Service {
 	return &Service{
 		Context:      ctx,
@@ -44,6 +52,33 @@ func NewService(
 		Localization: localization,
 		Store:        store,
 		Resolver:     resolver,
-		Sinks:        []Sink{hookSink, auditSink},
+		Sinks:        []Sink{hookSink, auditSink, eventStreamSink},
+		TaskQueue:    taskQueue,
+	}
+}
+
+func NewRelay(
+	logger RelayLogger,
+	clock clock.Clock,
+	database *appdb.Handle,
+	store RelayStore,
+	hookSink *hook.Sink,
+	auditSink *audit.Sink,
+	eventStreamSink *eventstream.Sink,
+	taskQueue task.Queue,
+) *Relay {
+	return &Relay{
+		Logger:   logger,
+		Clock:    clock,
+		Database: database,
+		Store:    store,
+		// The names identify the sinks in the delivery state of outbox records,
+		// so they must not be changed.
+		Sinks: []RelaySink{
+			{Name: "webhook", Sink: hookSink},
+			{Name: "audit", Sink: auditSink},
+			{Name: "eventstream", Sink: eventStreamSink},
+		},
+		TaskQueue: taskQueue,
 	}
 }
@@ -0,0 +1,141 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/util/backoff"
+)
+
+type OutboxDeliveryStatus string
+
+const (
+	// OutboxDeliveryStatusPending means the delivery failed and is to be retried.
+	OutboxDeliveryStatusPending OutboxDeliveryStatus = "pending"
+	// OutboxDeliveryStatusDelivered means the sink has received the event.
+	OutboxDeliveryStatusDelivered OutboxDeliveryStatus = "delivered"
+	// OutboxDeliveryStatusDead means the delivery failed too many times and is given up.
+	OutboxDeliveryStatusDead OutboxDeliveryStatus = "dead"
+)
+
+// OutboxDelivery is the delivery state of an outbox record to a sink.
+// A sink without delivery state has not been attempted yet.
+type OutboxDelivery struct {
+	Status OutboxDeliveryStatus `json:"status"`
+	backoff.State
+	LastError string `json:"last_error,omitempty"`
+}
+
+// IsFinished tells whether the sink is done with the record.
+func (d OutboxDelivery) IsFinished() bool {
+	return d.Status == OutboxDeliveryStatusDelivered || d.Status == OutboxDeliveryStatusDead
+}
+
+// OutboxRecord is a non-blocking event pending to be relayed to the sinks.
+// It is written in the transaction producing the event,
+// so that the event is not lost if the process crashes after commit.
+type OutboxRecord struct {
+	ID        string
+	CreatedAt time.Time
+	// Seq is assigned when the record is relayed for the first time.
+	// It is 0 before that.
+	Seq        int64
+	EventType  string
+	ForWebHook bool
+	ForAudit   bool
+	Payload    []byte
+	// Deliveries is the delivery state of the record, keyed by sink name.
+	Deliveries map[string]OutboxDelivery
+	// DeadLetteredAt is set when every sink is done with the record,
+	// and some of them have given up. The record is kept for inspection.
+	DeadLetteredAt *time.Time
+}
+
+func newOutboxRecord(e *event.Event, now time.Time) (*OutboxRecord, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	nonBlockingPayload := e.Payload.(event.NonBlockingPayload)
+	return &OutboxRecord{
+		ID:         e.ID,
+		CreatedAt:  now,
+		EventType:  string(e.Type),
+		ForWebHook: nonBlockingPayload.ForWebHook(),
+		ForAudit:   nonBlockingPayload.ForAudit(),
+		Payload:    payload,
+		Deliveries: map[string]OutboxDelivery{},
+	}, nil
+}
+
+// Event restores the event of the record with the seq of the record.
+// The payload of the restored event is kept in its JSON form.
+func (r *OutboxRecord) Event() (*event.Event, error) {
+	var e struct {
+		ID      string          `json:"id"`
+		Type    event.Type      `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+		Context event.Context   `json:"context"`
+	}
+	if err := json.Unmarshal(r.Payload, &e); err != nil {
+		return nil, err
+	}
+
+	return &event.Event{
+		ID:   e.ID,
+		Seq:  r.Seq,
+		Type: e.Type,
+		Payload: &outboxPayload{
+			eventType:  e.Type,
+			context:    e.Context,
+			forWebHook: r.ForWebHook,
+			forAudit:   r.ForAudit,
+			raw:        e.Payload,
+		},
+		Context:       e.Context,
+		IsNonBlocking: true,
+	}, nil
+}
+
+// outboxPayload is a non-blocking payload restored from an outbox record.
+type outboxPayload struct {
+	eventType  event.Type
+	context    event.Context
+	forWebHook bool
+	forAudit   bool
+	raw        json.RawMessage
+}
+
+func (p *outboxPayload) NonBlockingEventType() event.Type {
+	return p.eventType
+}
+
+func (p *outboxPayload) UserID() string {
+	if p.context.UserID == nil {
+		return ""
+	}
+	return *p.context.UserID
+}
+
+func (p *outboxPayload) IsAdminAPI() bool {
+	return p.context.TriggeredBy == event.TriggeredByTypeAdminAPI
+}
+
+func (p *outboxPayload) FillContext(ctx *event.Context) {}
+
+func (p *outboxPayload) ForWebHook() bool {
+	return p.forWebHook
+}
+
+func (p *outboxPayload) ForAudit() bool {
+	return p.forAudit
+}
+
+func (p *outboxPayload) MarshalJSON() ([]byte, error) {
+	return p.raw, nil
+}
+
+var _ event.NonBlockingPayload = &outboxPayload{}
+var _ json.Marshaler = &outboxPayload{}
@@ -0,0 +1,226 @@
+package event
+
+import (
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/backoff"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+//go:generate mockgen -source=relay.go -destination=relay_mock_test.go -package event
+
+const (
+	// RelayBatchSize is the maximum number of outbox records relayed in a transaction.
+	RelayBatchSize = 100
+	// RelayMaxBatches is the maximum number of batches relayed in a task.
+	RelayMaxBatches = 10
+	// RelayMaxAttempts is the number of failed attempts after which
+	// the delivery of a record to a sink is dead-lettered.
+	RelayMaxAttempts = 10
+)
+
+// RelayRetryBackoff is the back-off between the attempts to deliver a record to a sink.
+var RelayRetryBackoff = backoff.Exponential{Base: 10 * time.Second, Max: 10 * time.Minute}
+
+type RelayDatabase interface {
+	WithTx(do func() error) error
+}
+
+type RelayStore interface {
+	NextSequenceNumber() (int64, error)
+	LockOutbox() error
+	ListOutboxRecords(limit uint64) ([]*OutboxRecord, error)
+	UpdateOutboxRecord(r *OutboxRecord) error
+	DeleteOutboxRecords(ids []string) error
+	WithSavepoint(do func() error) error
+}
+
+type RelayLogger struct{ *log.Logger }
+
+func NewRelayLogger(lf *log.Factory) RelayLogger { return RelayLogger{lf.New("event-relay")} }
+
+// RelaySink is a sink identified by name in the delivery state of outbox records.
+type RelaySink struct {
+	Name string
+	Sink Sink
+}
+
+// Relay sends the non-blocking events in the outbox of the app to the sinks,
+// in the order of seq.
+//
+// The delivery to each sink is tracked separately in the outbox record,
+// and is recorded in the same transaction as the sink receives the event,
+// so the sinks writing to the app database receive every event exactly once.
+// A failing sink is retried with back-off without blocking the other sinks,
+// and the delivery is dead-lettered after RelayMaxAttempts failed attempts.
+type Relay struct {
+	Logger    RelayLogger
+	Clock     clock.Clock
+	Database  RelayDatabase
+	Store     RelayStore
+	Sinks     []RelaySink
+	TaskQueue task.Queue
+}
+
+// Relay relays the records in the outbox to the sinks.
+// The deliveries in back-off are retried when the relay is run after
+// their next attempt is due, which the worker does periodically.
+func (r *Relay) Relay() error {
+	for i := 0; i < RelayMaxBatches; i++ {
+		attempted := 0
+		err := r.Database.WithTx(func() error {
+			// Only one relay of the app can run at the same time,
+			// otherwise the events may be relayed out of order, or more than once.
+			err := r.Store.LockOutbox()
+			if err != nil {
+				return err
+			}
+
+			records, err := r.Store.ListOutboxRecords(RelayBatchSize)
+			if err != nil {
+				return err
+			}
+
+			attempted, err = r.relayBatch(records)
+			return err
+		})
+		if err != nil {
+			// The records are kept in the outbox,
+			// and relayed again in the next periodic run.
+			return err
+		}
+		// Nothing can be relayed now.
+		if attempted == 0 {
+			return nil
+		}
+	}
+
+	// There may be more pending records, continue in another task
+	// to avoid occupying the outbox of the app for too long.
+	// Tasks are enqueued when the transaction is committed.
+	return r.Database.WithTx(func() error {
+		r.TaskQueue.Enqueue(&tasks.RelayEventOutboxParam{})
+		return nil
+	})
+}
+
+// relayBatch relays the records and returns the number of deliveries attempted.
+func (r *Relay) relayBatch(records []*OutboxRecord) (attempted int, err error) {
+	now := r.Clock.NowUTC()
+
+	// A sink is blocked by its earliest undelivered record,
+	// so that it receives the events in the order of seq.
+	blocked := make(map[string]bool)
+
+	var finishedIDs []string
+	for _, record := range records {
+		changed := false
+		if record.Seq == 0 {
+			// The relays of the app are serialized,
+			// so the seq is assigned in the order the events are relayed.
+			record.Seq, err = r.Store.NextSequenceNumber()
+			if err != nil {
+				return attempted, err
+			}
+			changed = true
+		}
+
+		e, err := record.Event()
+		if err != nil {
+			return attempted, err
+		}
+
+		for _, sink := range r.Sinks {
+			delivery := record.Deliveries[sink.Name]
+			if delivery.IsFinished() || blocked[sink.Name] {
+				continue
+			}
+			if !delivery.IsDue(now) {
+				blocked[sink.Name] = true
+				continue
+			}
+
+			attempted++
+			changed = true
+			err := r.Store.WithSavepoint(func() error {
+				return sink.Sink.ReceiveNonBlockingEvent(e)
+			})
+			if err == nil {
+				delivery.Succeed()
+				delivery.Status = OutboxDeliveryStatusDelivered
+				delivery.LastError = ""
+				record.Deliveries[sink.Name] = delivery
+				continue
+			}
+
+			nextAttemptAt := delivery.Fail(now, RelayRetryBackoff)
+			delivery.LastError = err.Error()
+			logger := r.Logger.WithError(err).WithFields(map[string]interface{}{
+				"seq":      record.Seq,
+				"sink":     sink.Name,
+				"attempts": delivery.Attempts,
+			})
+			if delivery.Attempts >= RelayMaxAttempts {
+				delivery.Status = OutboxDeliveryStatusDead
+				delivery.NextAttemptAt = nil
+				logger.Error("failed to relay event, dead-lettered")
+			} else {
+				delivery.Status = OutboxDeliveryStatusPending
+				blocked[sink.Name] = true
+				logger.WithField("next_attempt_at", nextAttemptAt).Warn("failed to relay event, retrying")
+			}
+			record.Deliveries[sink.Name] = delivery
+		}
+
+		if !r.isFinished(record) {
+			if changed {
+				err = r.Store.UpdateOutboxRecord(record)
+				if err != nil {
+					return attempted, err
+				}
+			}
+			continue
+		}
+
+		if r.isDeadLettered(record) {
+			record.DeadLetteredAt = &now
+			err = r.Store.UpdateOutboxRecord(record)
+			if err != nil {
+				return attempted, err
+			}
+			continue
+		}
+
+		finishedIDs = append(finishedIDs, record.ID)
+	}
+
+	if len(finishedIDs) > 0 {
+		err = r.Store.DeleteOutboxRecords(finishedIDs)
+		if err != nil {
+			return attempted, err
+		}
+	}
+
+	return attempted, nil
+}
+
+func (r *Relay) isFinished(record *OutboxRecord) bool {
+	for _, sink := range r.Sinks {
+		if !record.Deliveries[sink.Name].IsFinished() {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Relay) isDeadLettered(record *OutboxRecord) bool {
+	for _, sink := range r.Sinks {
+		if record.Deliveries[sink.Name].Status == OutboxDeliveryStatusDead {
+			return true
+		}
+	}
+	return false
+}
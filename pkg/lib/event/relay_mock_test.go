@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: relay.go
+
+// Package event is a generated GoMock package.
+package event
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRelayDatabase is a mock of RelayDatabase interface.
+type MockRelayDatabase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelayDatabaseMockRecorder
+}
+
+// MockRelayDatabaseMockRecorder is the mock recorder for MockRelayDatabase.
+type MockRelayDatabaseMockRecorder struct {
+	mock *MockRelayDatabase
+}
+
+// NewMockRelayDatabase creates a new mock instance.
+func NewMockRelayDatabase(ctrl *gomock.Controller) *MockRelayDatabase {
+	mock := &MockRelayDatabase{ctrl: ctrl}
+	mock.recorder = &MockRelayDatabaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelayDatabase) EXPECT() *MockRelayDatabaseMockRecorder {
+	return m.recorder
+}
+
+// WithTx mocks base method.
+func (m *MockRelayDatabase) WithTx(do func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", do)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRelayDatabaseMockRecorder) WithTx(do interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRelayDatabase)(nil).WithTx), do)
+}
+
+// MockRelayStore is a mock of RelayStore interface.
+type MockRelayStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelayStoreMockRecorder
+}
+
+// MockRelayStoreMockRecorder is the mock recorder for MockRelayStore.
+type MockRelayStoreMockRecorder struct {
+	mock *MockRelayStore
+}
+
+// NewMockRelayStore creates a new mock instance.
+func NewMockRelayStore(ctrl *gomock.Controller) *MockRelayStore {
+	mock := &MockRelayStore{ctrl: ctrl}
+	mock.recorder = &MockRelayStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelayStore) EXPECT() *MockRelayStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteOutboxRecords mocks base method.
+func (m *MockRelayStore) DeleteOutboxRecords(ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOutboxRecords", ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOutboxRecords indicates an expected call of DeleteOutboxRecords.
+func (mr *MockRelayStoreMockRecorder) DeleteOutboxRecords(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOutboxRecords", reflect.TypeOf((*MockRelayStore)(nil).DeleteOutboxRecords), ids)
+}
+
+// ListOutboxRecords mocks base method.
+func (m *MockRelayStore) ListOutboxRecords(limit uint64) ([]*OutboxRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutboxRecords", limit)
+	ret0, _ := ret[0].([]*OutboxRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutboxRecords indicates an expected call of ListOutboxRecords.
+func (mr *MockRelayStoreMockRecorder) ListOutboxRecords(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboxRecords", reflect.TypeOf((*MockRelayStore)(nil).ListOutboxRecords), limit)
+}
+
+// LockOutbox mocks base method.
+func (m *MockRelayStore) LockOutbox() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockOutbox")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockOutbox indicates an expected call of LockOutbox.
+func (mr *MockRelayStoreMockRecorder) LockOutbox() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockOutbox", reflect.TypeOf((*MockRelayStore)(nil).LockOutbox))
+}
+
+// NextSequenceNumber mocks base method.
+func (m *MockRelayStore) NextSequenceNumber() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextSequenceNumber")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextSequenceNumber indicates an expected call of NextSequenceNumber.
+func (mr *MockRelayStoreMockRecorder) NextSequenceNumber() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextSequenceNumber", reflect.TypeOf((*MockRelayStore)(nil).NextSequenceNumber))
+}
+
+// UpdateOutboxRecord mocks base method.
+func (m *MockRelayStore) UpdateOutboxRecord(r *OutboxRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOutboxRecord", r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOutboxRecord indicates an expected call of UpdateOutboxRecord.
+func (mr *MockRelayStoreMockRecorder) UpdateOutboxRecord(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOutboxRecord", reflect.TypeOf((*MockRelayStore)(nil).UpdateOutboxRecord), r)
+}
+
+// WithSavepoint mocks base method.
+func (m *MockRelayStore) WithSavepoint(do func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithSavepoint", do)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithSavepoint indicates an expected call of WithSavepoint.
+func (mr *MockRelayStoreMockRecorder) WithSavepoint(do interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithSavepoint", reflect.TypeOf((*MockRelayStore)(nil).WithSavepoint), do)
+}
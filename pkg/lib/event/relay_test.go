@@ -0,0 +1,339 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/backoff"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/log"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRelay(t *testing.T) {
+	Convey("Relay", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		clk := clock.NewMockClockAt("2006-01-02T15:04:05Z")
+		now := clk.NowUTC()
+		database := NewMockRelayDatabase(ctrl)
+		store := NewMockRelayStore(ctrl)
+		sink1 := NewMockSink(ctrl)
+		sink2 := NewMockSink(ctrl)
+		queue := &mockTaskQueue{}
+
+		relay := &Relay{
+			Logger:   RelayLogger{log.Null},
+			Clock:    clk,
+			Database: database,
+			Store:    store,
+			Sinks: []RelaySink{
+				{Name: "sink1", Sink: sink1},
+				{Name: "sink2", Sink: sink2},
+			},
+			TaskQueue: queue,
+		}
+
+		database.EXPECT().WithTx(gomock.Any()).AnyTimes().DoAndReturn(func(do func() error) error {
+			return do()
+		})
+		store.EXPECT().WithSavepoint(gomock.Any()).AnyTimes().DoAndReturn(func(do func() error) error {
+			return do()
+		})
+
+		userID := "user-id"
+		makeRecord := func(id string, seq int64) *OutboxRecord {
+			r, err := newOutboxRecord(&event.Event{
+				ID:   id,
+				Type: MockNonBlockingEventType1,
+				Payload: &MockNonBlockingEvent1{
+					MockUserEventBase: MockUserEventBase{model.User{
+						Meta: model.Meta{ID: userID},
+					}},
+				},
+				Context: event.Context{
+					Timestamp:   1136214245,
+					UserID:      &userID,
+					TriggeredBy: event.TriggeredByTypeUser,
+				},
+				IsNonBlocking: true,
+			}, now)
+			So(err, ShouldBeNil)
+			r.Seq = seq
+			return r
+		}
+
+		var received []string
+		receive := func(name string) func(e *event.Event) error {
+			return func(e *event.Event) error {
+				received = append(received, name+":"+e.ID)
+				return nil
+			}
+		}
+
+		Convey("should relay events to sinks in order and delete them", func() {
+			records := []*OutboxRecord{makeRecord("event-1", 1), makeRecord("event-2", 2)}
+
+			gomock.InOrder(
+				store.EXPECT().LockOutbox().Return(nil),
+				store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(records, nil),
+				store.EXPECT().DeleteOutboxRecords([]string{"event-1", "event-2"}).Return(nil),
+				store.EXPECT().LockOutbox().Return(nil),
+				store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(nil, nil),
+			)
+			sink1.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Times(2).DoAndReturn(receive("sink1"))
+			sink2.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Times(2).DoAndReturn(receive("sink2"))
+
+			err := relay.Relay()
+			So(err, ShouldBeNil)
+			So(received, ShouldResemble, []string{"sink1:event-1", "sink2:event-1", "sink1:event-2", "sink2:event-2"})
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should assign seq to records in the order they are relayed", func() {
+			records := []*OutboxRecord{makeRecord("event-1", 0), makeRecord("event-2", 0)}
+
+			var seqs []int64
+			receiveSeq := func(e *event.Event) error {
+				seqs = append(seqs, e.Seq)
+				return nil
+			}
+
+			gomock.InOrder(
+				store.EXPECT().LockOutbox().Return(nil),
+				store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(records, nil),
+				store.EXPECT().NextSequenceNumber().Return(int64(11), nil),
+				store.EXPECT().NextSequenceNumber().Return(int64(12), nil),
+				store.EXPECT().DeleteOutboxRecords([]string{"event-1", "event-2"}).Return(nil),
+				store.EXPECT().LockOutbox().Return(nil),
+				store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(nil, nil),
+			)
+			sink1.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Times(2).DoAndReturn(receiveSeq)
+			sink2.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Times(2).DoAndReturn(receiveSeq)
+
+			err := relay.Relay()
+			So(err, ShouldBeNil)
+			So(seqs, ShouldResemble, []int64{11, 11, 12, 12})
+		})
+
+		Convey("should keep the seq of a record across retries", func() {
+			records := []*OutboxRecord{makeRecord("event-1", 0)}
+
+			var updated []OutboxRecord
+			gomock.InOrder(
+				store.EXPECT().LockOutbox().Return(nil),
+				store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(records, nil),
+				store.EXPECT().NextSequenceNumber().Return(int64(11), nil),
+				store.EXPECT().UpdateOutboxRecord(gomock.Any()).DoAndReturn(func(r *OutboxRecord) error {
+					updated = append(updated, *r)
+					return nil
+				}),
+				store.EXPECT().LockOutbox().Return(nil),
+				store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(records, nil),
+			)
+			sink1.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Return(fmt.Errorf("e"))
+			sink2.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Return(nil)
+
+			err := relay.Relay()
+			So(err, ShouldBeNil)
+			So(updated, ShouldHaveLength, 1)
+			So(updated[0].Seq, ShouldEqual, 11)
+		})
+
+		Convey("should retry a failing sink with back-off without blocking other sinks", func() {
+			records := []*OutboxRecord{makeRecord("event-1", 1), makeRecord("event-2", 2)}
+
+			store.EXPECT().LockOutbox().Times(2).Return(nil)
+			store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Times(2).Return(records, nil)
+			store.EXPECT().UpdateOutboxRecord(gomock.Any()).Times(2).Return(nil)
+			store.EXPECT().DeleteOutboxRecords(gomock.Any()).Times(0)
+			sink1.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Return(fmt.Errorf("e"))
+			sink2.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Times(2).DoAndReturn(receive("sink2"))
+
+			err := relay.Relay()
+			So(err, ShouldBeNil)
+			So(received, ShouldResemble, []string{"sink2:event-1", "sink2:event-2"})
+
+			nextAttemptAt := now.Add(10 * time.Second)
+			So(records[0].Deliveries, ShouldResemble, map[string]OutboxDelivery{
+				"sink1": {
+					Status:    OutboxDeliveryStatusPending,
+					State:     backoff.State{Attempts: 1, NextAttemptAt: &nextAttemptAt},
+					LastError: "e",
+				},
+				"sink2": {Status: OutboxDeliveryStatusDelivered, State: backoff.State{Attempts: 1}},
+			})
+			// event-2 is not sent to sink1 before event-1.
+			So(records[1].Deliveries, ShouldResemble, map[string]OutboxDelivery{
+				"sink2": {Status: OutboxDeliveryStatusDelivered, State: backoff.State{Attempts: 1}},
+			})
+			// The retry is made by the periodic relay after it is due.
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should not retry a sink before the retry is due", func() {
+			records := []*OutboxRecord{makeRecord("event-1", 1)}
+			nextAttemptAt := now.Add(5 * time.Second)
+			records[0].Deliveries["sink1"] = OutboxDelivery{
+				Status: OutboxDeliveryStatusPending,
+				State:  backoff.State{Attempts: 1, NextAttemptAt: &nextAttemptAt},
+			}
+			records[0].Deliveries["sink2"] = OutboxDelivery{Status: OutboxDeliveryStatusDelivered}
+
+			store.EXPECT().LockOutbox().Return(nil)
+			store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(records, nil)
+
+			err := relay.Relay()
+			So(err, ShouldBeNil)
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should delete the record when the retry succeeds", func() {
+			records := []*OutboxRecord{makeRecord("event-1", 1)}
+			nextAttemptAt := now
+			records[0].Deliveries["sink1"] = OutboxDelivery{
+				Status: OutboxDeliveryStatusPending,
+				State:  backoff.State{Attempts: 3, NextAttemptAt: &nextAttemptAt},
+			}
+			records[0].Deliveries["sink2"] = OutboxDelivery{Status: OutboxDeliveryStatusDelivered}
+
+			gomock.InOrder(
+				store.EXPECT().LockOutbox().Return(nil),
+				store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(records, nil),
+				store.EXPECT().DeleteOutboxRecords([]string{"event-1"}).Return(nil),
+				store.EXPECT().LockOutbox().Return(nil),
+				store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(nil, nil),
+			)
+			sink1.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).DoAndReturn(receive("sink1"))
+
+			err := relay.Relay()
+			So(err, ShouldBeNil)
+			So(received, ShouldResemble, []string{"sink1:event-1"})
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should dead-letter the delivery after max attempts", func() {
+			records := []*OutboxRecord{makeRecord("event-1", 1), makeRecord("event-2", 2)}
+			nextAttemptAt := now
+			records[0].Deliveries["sink1"] = OutboxDelivery{
+				Status: OutboxDeliveryStatusPending,
+				State:  backoff.State{Attempts: RelayMaxAttempts - 1, NextAttemptAt: &nextAttemptAt},
+			}
+			records[0].Deliveries["sink2"] = OutboxDelivery{Status: OutboxDeliveryStatusDelivered}
+
+			var updated []OutboxRecord
+			gomock.InOrder(
+				store.EXPECT().LockOutbox().Return(nil),
+				store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(records, nil),
+				store.EXPECT().UpdateOutboxRecord(gomock.Any()).DoAndReturn(func(r *OutboxRecord) error {
+					updated = append(updated, *r)
+					return nil
+				}),
+				store.EXPECT().DeleteOutboxRecords([]string{"event-2"}).Return(nil),
+				store.EXPECT().LockOutbox().Return(nil),
+				store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(nil, nil),
+			)
+			sink1.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Return(fmt.Errorf("e"))
+			sink1.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).DoAndReturn(receive("sink1"))
+			sink2.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).DoAndReturn(receive("sink2"))
+
+			err := relay.Relay()
+			So(err, ShouldBeNil)
+			// The next event is sent to the sink after dead-lettering.
+			So(received, ShouldResemble, []string{"sink1:event-2", "sink2:event-2"})
+			So(updated, ShouldHaveLength, 1)
+			So(updated[0].ID, ShouldEqual, "event-1")
+			So(updated[0].DeadLetteredAt, ShouldResemble, &now)
+			So(updated[0].Deliveries["sink1"], ShouldResemble, OutboxDelivery{
+				Status:    OutboxDeliveryStatusDead,
+				State:     backoff.State{Attempts: RelayMaxAttempts},
+				LastError: "e",
+			})
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should keep the records if the outbox cannot be read", func() {
+			store.EXPECT().LockOutbox().Return(nil)
+			store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Return(nil, fmt.Errorf("e"))
+
+			err := relay.Relay()
+			So(err, ShouldBeError, "e")
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should continue in another task after max batches", func() {
+			store.EXPECT().LockOutbox().Times(RelayMaxBatches).Return(nil)
+			store.EXPECT().ListOutboxRecords(uint64(RelayBatchSize)).Times(RelayMaxBatches).DoAndReturn(func(limit uint64) ([]*OutboxRecord, error) {
+				return []*OutboxRecord{makeRecord("event-1", 1)}, nil
+			})
+			store.EXPECT().DeleteOutboxRecords([]string{"event-1"}).Times(RelayMaxBatches).Return(nil)
+			sink1.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Times(RelayMaxBatches).Return(nil)
+			sink2.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Times(RelayMaxBatches).Return(nil)
+
+			err := relay.Relay()
+			So(err, ShouldBeNil)
+			So(queue.params, ShouldResemble, []task.Param{
+				&tasks.RelayEventOutboxParam{},
+			})
+		})
+	})
+}
+
+func TestOutboxRecord(t *testing.T) {
+	Convey("OutboxRecord", t, func() {
+		userID := "user-id"
+		e := &event.Event{
+			ID:   "event-id",
+			Type: MockNonBlockingEventType1,
+			Payload: &MockNonBlockingEvent1{
+				MockUserEventBase: MockUserEventBase{model.User{
+					Meta: model.Meta{ID: userID},
+				}},
+			},
+			Context: event.Context{
+				Timestamp:   1136214245,
+				UserID:      &userID,
+				TriggeredBy: event.TriggeredByTypeAdminAPI,
+			},
+			IsNonBlocking: true,
+		}
+
+		r, err := newOutboxRecord(e, time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC))
+		So(err, ShouldBeNil)
+		So(r.ID, ShouldEqual, "event-id")
+		So(r.Seq, ShouldEqual, 0)
+
+		r.Seq = 42
+		restored, err := r.Event()
+		So(err, ShouldBeNil)
+		So(restored.ID, ShouldEqual, e.ID)
+		So(restored.Seq, ShouldEqual, 42)
+		So(restored.Type, ShouldEqual, e.Type)
+		So(restored.Context, ShouldResemble, e.Context)
+		So(restored.IsNonBlocking, ShouldBeTrue)
+
+		payload := restored.Payload.(event.NonBlockingPayload)
+		So(payload.NonBlockingEventType(), ShouldEqual, MockNonBlockingEventType1)
+		So(payload.UserID(), ShouldEqual, userID)
+		So(payload.IsAdminAPI(), ShouldBeTrue)
+		So(payload.ForWebHook(), ShouldBeTrue)
+		So(payload.ForAudit(), ShouldBeTrue)
+
+		e.Seq = 42
+		original, err := json.Marshal(e)
+		So(err, ShouldBeNil)
+		relayed, err := json.Marshal(restored)
+		So(err, ShouldBeNil)
+		So(string(relayed), ShouldEqual, string(original))
+	})
+}
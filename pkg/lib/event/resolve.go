@@ -3,6 +3,7 @@ package event
 import (
 	"errors"
 	"reflect"
+	"strings"
 
 	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn/user"
@@ -25,9 +26,13 @@ func (r *ResolverImpl) Resolve(anything interface{}) (err error) {
 	for i, refField := range fields {
 		if jsonName, ok := refField.Tag.Lookup("resolve"); ok {
 			for j, targetField := range fields {
-				if name, ok := targetField.Tag.Lookup("json"); ok {
-					if jsonName == name {
+				if tag, ok := targetField.Tag.Lookup("json"); ok {
+					if name := strings.Split(tag, ",")[0]; jsonName == name {
 						userRef := struc.Field(i).Interface().(model.UserRef)
+						// The payload is not about any user.
+						if userRef.ID == "" {
+							continue
+						}
 
 						var u *model.User
 						u, err = r.Users.Get(userRef.ID, accesscontrol.RoleGreatest)
@@ -39,7 +44,11 @@ func (r *ResolverImpl) Resolve(anything interface{}) (err error) {
 							return
 						}
 
-						struc.Field(j).Set(reflect.ValueOf(*u))
+						if targetField.Type.Kind() == reflect.Ptr {
+							struc.Field(j).Set(reflect.ValueOf(u))
+						} else {
+							struc.Field(j).Set(reflect.ValueOf(*u))
+						}
 					}
 				}
 			}
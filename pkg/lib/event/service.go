@@ -9,7 +9,9 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/clientid"
 	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/infra/db"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
 	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/httputil"
 	"github.com/authgear/authgear-server/pkg/util/intl"
@@ -29,6 +31,7 @@ type Sink interface {
 
 type Store interface {
 	NextSequenceNumber() (int64, error)
+	CreateOutboxRecord(r *OutboxRecord) error
 }
 
 type Resolver interface {
@@ -50,9 +53,9 @@ type Service struct {
 	Store        Store
 	Resolver     Resolver
 	Sinks        []Sink
+	TaskQueue    task.Queue
 
 	NonBlockingPayloads []event.NonBlockingPayload `wire:"-"`
-	NonBlockingEvents   []*event.Event             `wire:"-"`
 	DatabaseHooked      bool                       `wire:"-"`
 	IsDispatchEventErr  bool                       `wire:"-"`
 }
@@ -111,40 +114,43 @@ func (s *Service) WillCommitTx() (err error) {
 		return
 	}
 
+	if len(s.NonBlockingPayloads) == 0 {
+		return
+	}
+
 	// We have to prepare the event here because we need an ongoing transaction
-	// to get the seq number, as well as resolving refs.
+	// to resolve refs.
 
 	for _, payload := range s.NonBlockingPayloads {
 		eventContext := s.makeContext(payload)
-		seq, err := s.nextSeq()
+		err = s.Resolver.Resolve(payload)
 		if err != nil {
 			return err
 		}
-		err = s.Resolver.Resolve(payload)
+		// The seq of non-blocking events is assigned by the relay,
+		// so that the events are relayed in the order of seq
+		// without serializing the transactions producing them.
+		e := newNonBlockingEvent(0, payload, eventContext)
+		record, err := newOutboxRecord(e, s.Clock.NowUTC())
+		if err != nil {
+			return err
+		}
+		// The event is written to the outbox in this transaction,
+		// and relayed to the sinks after commit.
+		err = s.Store.CreateOutboxRecord(record)
 		if err != nil {
 			return err
 		}
-		e := newNonBlockingEvent(seq, payload, eventContext)
-		s.NonBlockingEvents = append(s.NonBlockingEvents, e)
 	}
 
+	// The relay skips the sinks of which the retry is not due yet,
+	// so relaying the new events does not retry a failing sink early.
+	s.TaskQueue.Enqueue(&tasks.RelayEventOutboxParam{})
+
 	return
 }
 
-func (s *Service) DidCommitTx() {
-	defer func() {
-		s.NonBlockingEvents = nil
-	}()
-
-	for _, e := range s.NonBlockingEvents {
-		for _, sink := range s.Sinks {
-			err := sink.ReceiveNonBlockingEvent(e)
-			if err != nil {
-				s.Logger.WithError(err).Error("failed to dispatch nonblocking event")
-			}
-		}
-	}
-}
+func (s *Service) DidCommitTx() {}
 
 func (s *Service) nextSeq() (seq int64, err error) {
 	seq, err = s.Store.NextSequenceNumber()
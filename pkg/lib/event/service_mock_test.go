@@ -121,6 +121,20 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// CreateOutboxRecord mocks base method.
+func (m *MockStore) CreateOutboxRecord(r *OutboxRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxRecord", r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOutboxRecord indicates an expected call of CreateOutboxRecord.
+func (mr *MockStoreMockRecorder) CreateOutboxRecord(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxRecord", reflect.TypeOf((*MockStore)(nil).CreateOutboxRecord), r)
+}
+
 // NextSequenceNumber mocks base method.
 func (m *MockStore) NextSequenceNumber() (int64, error) {
 	m.ctrl.T.Helper()
@@ -11,14 +11,24 @@ import (
 	"github.com/authgear/authgear-server/pkg/api/event"
 	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
 	"github.com/authgear/authgear-server/pkg/lib/session"
 	"github.com/authgear/authgear-server/pkg/lib/session/idpsession"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/log"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
 
+type mockTaskQueue struct {
+	params []task.Param
+}
+
+func (q *mockTaskQueue) Enqueue(param task.Param) {
+	q.params = append(q.params, param)
+}
+
 func TestServiceDispatchEvent(t *testing.T) {
 	Convey("Service", t, func() {
 		ctrl := gomock.NewController(t)
@@ -30,6 +40,7 @@ func TestServiceDispatchEvent(t *testing.T) {
 		sink := NewMockSink(ctrl)
 		store := NewMockStore(ctrl)
 		resolver := NewMockResolver(ctrl)
+		queue := &mockTaskQueue{}
 		fallbackLanguage := "en"
 		supportedLanguages := []string{"en"}
 		logger := Logger{log.Null}
@@ -49,6 +60,7 @@ func TestServiceDispatchEvent(t *testing.T) {
 			Store:        store,
 			Resolver:     resolver,
 			Sinks:        []Sink{sink},
+			TaskQueue:    queue,
 		}
 
 		var seq0 int64
@@ -174,7 +186,7 @@ func TestServiceDispatchEvent(t *testing.T) {
 			})
 		})
 
-		Convey("send events to sink when transaction was committed", func() {
+		Convey("write events to outbox before transaction is committed", func() {
 			userID := "user-id"
 			payload := &MockNonBlockingEvent1{
 				MockUserEventBase: MockUserEventBase{model.User{
@@ -185,27 +197,50 @@ func TestServiceDispatchEvent(t *testing.T) {
 				payload,
 			}
 
-			sink.EXPECT().ReceiveNonBlockingEvent(&event.Event{
-				ID:      "0000000000000000",
-				Type:    payload.NonBlockingEventType(),
-				Seq:     0,
-				Payload: payload,
-				Context: event.Context{
-					Timestamp:          1136214245,
-					UserID:             &userID,
-					Language:           fallbackLanguage,
-					PreferredLanguages: []string{},
-					TriggeredBy:        event.TriggeredByTypeUser,
-				},
-				IsNonBlocking: true,
+			sink.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Times(0)
+			store.EXPECT().CreateOutboxRecord(gomock.Any()).DoAndReturn(func(r *OutboxRecord) error {
+				So(r.ID, ShouldEqual, "0000000000000000")
+				So(r.EventType, ShouldEqual, string(MockNonBlockingEventType1))
+				So(r.CreatedAt, ShouldEqual, clock.NowUTC())
+				So(r.ForWebHook, ShouldBeTrue)
+				So(r.ForAudit, ShouldBeTrue)
+				// The seq is assigned by the relay.
+				So(r.Seq, ShouldEqual, 0)
+
+				e, err := r.Event()
+				So(err, ShouldBeNil)
+				So(e.Type, ShouldEqual, MockNonBlockingEventType1)
+				So(e.Context.UserID, ShouldResemble, &userID)
+				So(e.IsNonBlocking, ShouldBeTrue)
+				return nil
 			})
 
 			err := service.WillCommitTx()
 			So(err, ShouldBeNil)
+			So(queue.params, ShouldResemble, []task.Param{
+				&tasks.RelayEventOutboxParam{},
+			})
 
 			service.DidCommitTx()
 		})
 
+		Convey("fail to commit if outbox has error", func() {
+			payload := &MockNonBlockingEvent1{
+				MockUserEventBase: MockUserEventBase{model.User{
+					Meta: model.Meta{ID: "user-id"},
+				}},
+			}
+			service.NonBlockingPayloads = []event.NonBlockingPayload{
+				payload,
+			}
+
+			store.EXPECT().CreateOutboxRecord(gomock.Any()).Return(fmt.Errorf("e"))
+
+			err := service.WillCommitTx()
+			So(err, ShouldBeError, "e")
+			So(queue.params, ShouldBeEmpty)
+		})
+
 		Convey("skip non-blocking events if blocking event has error", func() {
 			userID := "user-id"
 			user := model.User{
@@ -232,7 +267,7 @@ func TestServiceDispatchEvent(t *testing.T) {
 					TriggeredBy:        event.TriggeredByTypeUser,
 				},
 			}).Return(fmt.Errorf("e"))
-			sink.EXPECT().ReceiveNonBlockingEvent(gomock.Any()).Times(0)
+			store.EXPECT().CreateOutboxRecord(gomock.Any()).Times(0)
 
 			err := service.DispatchEvent(nonBlocking)
 			So(err, ShouldBeNil)
@@ -242,6 +277,7 @@ func TestServiceDispatchEvent(t *testing.T) {
 
 			err = service.WillCommitTx()
 			So(err, ShouldBeNil)
+			So(queue.params, ShouldBeEmpty)
 
 			service.DidCommitTx()
 		})
@@ -1,12 +1,26 @@
 package event
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
 
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
 	appdb "github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
 )
 
+// outboxLockClass is the class ID of the advisory lock serializing
+// the relays of an app.
+const outboxLockClass = 3
+
+// relaySavepoint isolates the delivery to a sink from the other sinks.
+const relaySavepoint = "event_relay_sink"
+
 type StoreImpl struct {
+	AppID       config.AppID
 	SQLBuilder  *appdb.SQLBuilder
 	SQLExecutor *appdb.SQLExecutor
 }
@@ -21,3 +35,144 @@ func (s *StoreImpl) NextSequenceNumber() (seq int64, err error) {
 	err = row.Scan(&seq)
 	return
 }
+
+func (s *StoreImpl) CreateOutboxRecord(r *OutboxRecord) error {
+	deliveries, err := json.Marshal(r.Deliveries)
+	if err != nil {
+		return err
+	}
+
+	q := s.SQLBuilder.WithAppID(string(s.AppID)).
+		Insert(s.SQLBuilder.TableName("_auth_event_outbox")).
+		Columns(
+			"id",
+			"created_at",
+			"event_type",
+			"for_webhook",
+			"for_audit",
+			"payload",
+			"deliveries",
+		).
+		Values(
+			r.ID,
+			r.CreatedAt,
+			r.EventType,
+			r.ForWebHook,
+			r.ForAudit,
+			r.Payload,
+			deliveries,
+		)
+
+	_, err = s.SQLExecutor.ExecWith(q)
+	return err
+}
+
+// LockOutbox blocks other relays of the app until the end of the transaction.
+func (s *StoreImpl) LockOutbox() error {
+	q := s.SQLBuilder.WithoutAppID().
+		Select().
+		Column(sq.Expr("pg_advisory_xact_lock(?, hashtext(?))", outboxLockClass, string(s.AppID)))
+	_, err := s.SQLExecutor.ExecWith(q)
+	return err
+}
+
+// ListOutboxRecords lists the earliest outbox records in the order of seq.
+// The records without seq are listed after them, in the order they are written.
+// Dead-lettered records are excluded.
+func (s *StoreImpl) ListOutboxRecords(limit uint64) ([]*OutboxRecord, error) {
+	q := s.SQLBuilder.WithAppID(string(s.AppID)).
+		Select(
+			"id",
+			"created_at",
+			"seq",
+			"event_type",
+			"for_webhook",
+			"for_audit",
+			"payload",
+			"deliveries",
+		).
+		From(s.SQLBuilder.TableName("_auth_event_outbox")).
+		Where("dead_lettered_at IS NULL").
+		OrderBy("seq ASC NULLS LAST", "position ASC").
+		Limit(limit)
+
+	rows, err := s.SQLExecutor.QueryWith(q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*OutboxRecord
+	for rows.Next() {
+		r := &OutboxRecord{}
+		var seq sql.NullInt64
+		var deliveries []byte
+		err := rows.Scan(
+			&r.ID,
+			&r.CreatedAt,
+			&seq,
+			&r.EventType,
+			&r.ForWebHook,
+			&r.ForAudit,
+			&r.Payload,
+			&deliveries,
+		)
+		if err != nil {
+			return nil, err
+		}
+		r.Seq = seq.Int64
+		if err := json.Unmarshal(deliveries, &r.Deliveries); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+// UpdateOutboxRecord updates the seq and the delivery state of the record.
+func (s *StoreImpl) UpdateOutboxRecord(r *OutboxRecord) error {
+	deliveries, err := json.Marshal(r.Deliveries)
+	if err != nil {
+		return err
+	}
+
+	q := s.SQLBuilder.WithAppID(string(s.AppID)).
+		Update(s.SQLBuilder.TableName("_auth_event_outbox")).
+		Set("seq", r.Seq).
+		Set("deliveries", deliveries).
+		Set("dead_lettered_at", r.DeadLetteredAt).
+		Where("id = ?", r.ID)
+
+	_, err = s.SQLExecutor.ExecWith(q)
+	return err
+}
+
+// WithSavepoint runs do in a savepoint of the current transaction.
+// If do fails, the changes made by do are rolled back,
+// and the transaction can be continued.
+func (s *StoreImpl) WithSavepoint(do func() error) error {
+	_, err := s.SQLExecutor.ExecWith(sq.Expr("SAVEPOINT " + relaySavepoint))
+	if err != nil {
+		return err
+	}
+
+	if err := do(); err != nil {
+		if _, rbErr := s.SQLExecutor.ExecWith(sq.Expr("ROLLBACK TO SAVEPOINT " + relaySavepoint)); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+
+	_, err = s.SQLExecutor.ExecWith(sq.Expr("RELEASE SAVEPOINT " + relaySavepoint))
+	return err
+}
+
+func (s *StoreImpl) DeleteOutboxRecords(ids []string) error {
+	q := s.SQLBuilder.WithAppID(string(s.AppID)).
+		Delete(s.SQLBuilder.TableName("_auth_event_outbox")).
+		Where("id = ANY (?)", pq.Array(ids))
+
+	_, err := s.SQLExecutor.ExecWith(q)
+	return err
+}
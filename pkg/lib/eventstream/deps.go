@@ -0,0 +1,16 @@
+package eventstream
+
+import (
+	"github.com/google/wire"
+)
+
+var DependencySet = wire.NewSet(
+	NewHTTPClient,
+	NewProducer,
+	NewPublisherLogger,
+	wire.Struct(new(Store), "*"),
+	wire.Struct(new(Sink), "*"),
+	wire.Struct(new(Publisher), "*"),
+	wire.Bind(new(SinkStore), new(*Store)),
+	wire.Bind(new(PublisherStore), new(*Store)),
+)
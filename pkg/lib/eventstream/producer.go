@@ -0,0 +1,54 @@
+package eventstream
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/redis/eventstreamredis"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+)
+
+//go:generate mockgen -source=producer.go -destination=producer_mock_test.go -package eventstream
+
+// Producer publishes records to the event stream in the given order.
+// If an error is returned, some of the records may have been published.
+type Producer interface {
+	Produce(records []*Record) error
+}
+
+type HTTPClient struct {
+	*http.Client
+}
+
+func NewHTTPClient() HTTPClient {
+	return HTTPClient{
+		httputil.NewExternalClient(30 * time.Second),
+	}
+}
+
+// NewProducer returns the producer of the configured event stream,
+// or nil if event stream is not configured.
+func NewProducer(
+	appID config.AppID,
+	credentials *config.EventStreamCredentials,
+	redis *eventstreamredis.Handle,
+	httpClient HTTPClient,
+) Producer {
+	switch {
+	case credentials == nil:
+		return nil
+	case credentials.RedisURL != "":
+		return &RedisStreamProducer{
+			AppID:       appID,
+			Credentials: credentials,
+			Redis:       redis,
+		}
+	default:
+		return &KafkaRESTProxyProducer{
+			AppID:       appID,
+			Credentials: credentials,
+			HTTPClient:  httpClient,
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package eventstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+)
+
+// KafkaRESTProxyProducer produces records to a Kafka topic via
+// a Kafka REST Proxy (v2 API), e.g. Confluent REST Proxy or Redpanda.
+// The app ID is used as the record key, so the records of an app
+// go to the same partition and are kept in order.
+type KafkaRESTProxyProducer struct {
+	AppID       config.AppID
+	Credentials *config.EventStreamCredentials
+	HTTPClient  HTTPClient
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+}
+
+func (p *KafkaRESTProxyProducer) Produce(records []*Record) error {
+	body := kafkaProduceRequest{}
+	for _, r := range records {
+		body.Records = append(body.Records, kafkaRecord{
+			Key:   string(p.AppID),
+			Value: json.RawMessage(r.Payload),
+		})
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("eventstream: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(p.Credentials.KafkaRESTProxyURL, "/") + "/topics/" + url.PathEscape(p.Credentials.Stream)
+	request, err := http.NewRequest("POST", endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("eventstream: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	request.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := p.HTTPClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("eventstream: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("eventstream: unexpected status code: %d", resp.StatusCode)
+	}
+
+	var produceResp kafkaProduceResponse
+	err = json.NewDecoder(resp.Body).Decode(&produceResp)
+	if err != nil {
+		return fmt.Errorf("eventstream: invalid response: %w", err)
+	}
+
+	for _, offset := range produceResp.Offsets {
+		if offset.ErrorCode != nil || offset.Error != nil {
+			var message string
+			if offset.Error != nil {
+				message = *offset.Error
+			}
+			return fmt.Errorf("eventstream: failed to produce record: %s", message)
+		}
+	}
+
+	return nil
+}
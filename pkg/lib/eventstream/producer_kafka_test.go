@@ -0,0 +1,84 @@
+package eventstream
+
+import (
+	"net/http"
+	"testing"
+
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestKafkaRESTProxyProducer(t *testing.T) {
+	Convey("KafkaRESTProxyProducer", t, func() {
+		httpClient := &http.Client{}
+		gock.InterceptClient(httpClient)
+		defer gock.Off()
+
+		p := &KafkaRESTProxyProducer{
+			AppID: "app-id",
+			Credentials: &config.EventStreamCredentials{
+				KafkaRESTProxyURL: "http://kafka.example.com/",
+				Stream:            "authgear-events",
+			},
+			HTTPClient: HTTPClient{httpClient},
+		}
+
+		records := []*Record{
+			{ID: "a", Seq: 1, Payload: []byte(`{"seq":1}`)},
+			{ID: "b", Seq: 2, Payload: []byte(`{"seq":2}`)},
+		}
+
+		Convey("should produce records keyed by app ID", func() {
+			gock.New("http://kafka.example.com").
+				Post("/topics/authgear-events").
+				MatchType("application/vnd.kafka.json.v2+json").
+				JSON(map[string]interface{}{
+					"records": []interface{}{
+						map[string]interface{}{"key": "app-id", "value": map[string]interface{}{"seq": 1}},
+						map[string]interface{}{"key": "app-id", "value": map[string]interface{}{"seq": 2}},
+					},
+				}).
+				Reply(200).
+				JSON(map[string]interface{}{
+					"offsets": []interface{}{
+						map[string]interface{}{"partition": 0, "offset": 10, "error_code": nil, "error": nil},
+						map[string]interface{}{"partition": 0, "offset": 11, "error_code": nil, "error": nil},
+					},
+				})
+			defer func() { gock.Flush() }()
+
+			err := p.Produce(records)
+			So(err, ShouldBeNil)
+			So(gock.IsDone(), ShouldBeTrue)
+		})
+
+		Convey("should return error if any record failed", func() {
+			gock.New("http://kafka.example.com").
+				Post("/topics/authgear-events").
+				Reply(200).
+				JSON(map[string]interface{}{
+					"offsets": []interface{}{
+						map[string]interface{}{"partition": 0, "offset": 10, "error_code": nil, "error": nil},
+						map[string]interface{}{"partition": nil, "offset": nil, "error_code": 50003, "error": "timeout"},
+					},
+				})
+			defer func() { gock.Flush() }()
+
+			err := p.Produce(records)
+			So(err, ShouldBeError, "eventstream: failed to produce record: timeout")
+		})
+
+		Convey("should return error on unexpected status code", func() {
+			gock.New("http://kafka.example.com").
+				Post("/topics/authgear-events").
+				Reply(500)
+			defer func() { gock.Flush() }()
+
+			err := p.Produce(records)
+			So(err, ShouldBeError, "eventstream: unexpected status code: 500")
+		})
+	})
+}
@@ -0,0 +1,39 @@
+package eventstream
+
+import (
+	"context"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/redis/eventstreamredis"
+)
+
+// RedisStreamProducer appends records to a Redis stream with XADD.
+type RedisStreamProducer struct {
+	AppID       config.AppID
+	Credentials *config.EventStreamCredentials
+	Redis       *eventstreamredis.Handle
+}
+
+func (p *RedisStreamProducer) Produce(records []*Record) error {
+	ctx := context.Background()
+	return p.Redis.WithConn(func(conn *goredis.Conn) error {
+		// Commands in a pipeline are executed in order.
+		_, err := conn.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+			for _, r := range records {
+				pipe.XAdd(ctx, &goredis.XAddArgs{
+					Stream: p.Credentials.Stream,
+					Values: []interface{}{
+						"app_id", string(p.AppID),
+						"seq", r.Seq,
+						"type", r.EventType,
+						"payload", string(r.Payload),
+					},
+				})
+			}
+			return nil
+		})
+		return err
+	})
+}
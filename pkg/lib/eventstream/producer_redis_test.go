@@ -0,0 +1,65 @@
+package eventstream
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/redis"
+	"github.com/authgear/authgear-server/pkg/lib/infra/redis/eventstreamredis"
+	"github.com/authgear/authgear-server/pkg/util/log"
+	"github.com/authgear/authgear-server/pkg/util/uuid"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestRedisStreamProducer requires a local Redis, e.g.
+// EVENT_STREAM_TEST_REDIS_URL=redis://localhost:6379 go test ./pkg/lib/eventstream/...
+func TestRedisStreamProducer(t *testing.T) {
+	redisURL := os.Getenv("EVENT_STREAM_TEST_REDIS_URL")
+	if redisURL == "" {
+		t.Skip("EVENT_STREAM_TEST_REDIS_URL is not set")
+	}
+
+	Convey("RedisStreamProducer", t, func() {
+		pool := redis.NewPool()
+		defer pool.Close()
+
+		redisConfig := &config.RedisConfig{}
+		redisConfig.SetDefaults()
+		credentials := &config.EventStreamCredentials{
+			RedisURL: redisURL,
+			Stream:   "authgear-events-" + uuid.New(),
+		}
+		handle := eventstreamredis.NewHandle(pool, redisConfig, credentials, log.NewFactory(log.LevelWarn))
+
+		ctx := context.Background()
+		defer handle.Client().Del(ctx, credentials.Stream)
+
+		p := &RedisStreamProducer{
+			AppID:       "app-id",
+			Credentials: credentials,
+			Redis:       handle,
+		}
+
+		Convey("should append records to the stream in order", func() {
+			err := p.Produce([]*Record{
+				{ID: "a", Seq: 1, EventType: "user.created", Payload: []byte(`{"seq":1}`)},
+				{ID: "b", Seq: 2, EventType: "user.authenticated", Payload: []byte(`{"seq":2}`)},
+			})
+			So(err, ShouldBeNil)
+
+			messages, err := handle.Client().XRange(ctx, credentials.Stream, "-", "+").Result()
+			So(err, ShouldBeNil)
+			So(messages, ShouldHaveLength, 2)
+			So(messages[0].Values, ShouldResemble, map[string]interface{}{
+				"app_id":  "app-id",
+				"seq":     "1",
+				"type":    "user.created",
+				"payload": `{"seq":1}`,
+			})
+			So(messages[1].Values["seq"], ShouldEqual, "2")
+		})
+	})
+}
@@ -0,0 +1,161 @@
+package eventstream
+
+import (
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/backoff"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+//go:generate mockgen -source=publisher.go -destination=publisher_mock_test.go -package eventstream
+
+const (
+	// PublishBatchSize is the maximum number of records produced at once.
+	PublishBatchSize = 100
+	// PublishMaxBatches is the maximum number of batches published in a task.
+	PublishMaxBatches = 10
+	// PublishLease is the period for which the app is claimed by a publisher.
+	// It is longer than the timeout of the producer.
+	PublishLease = 2 * time.Minute
+)
+
+// PublishRetryBackoff is the back-off between the attempts to publish.
+var PublishRetryBackoff = backoff.Exponential{Base: 10 * time.Second, Max: 10 * time.Minute}
+
+type PublisherDatabase interface {
+	WithTx(do func() error) error
+}
+
+type PublisherStore interface {
+	GetPublisherStateForUpdate() (*PublisherState, error)
+	UpdatePublisherState(state *PublisherState) error
+	ListRecords(limit uint64) ([]*Record, error)
+	DeleteRecords(ids []string) error
+}
+
+type PublisherLogger struct{ *log.Logger }
+
+func NewPublisherLogger(lf *log.Factory) PublisherLogger {
+	return PublisherLogger{lf.New("event-stream-publisher")}
+}
+
+// Publisher publishes the persisted events of the app in the order of seq.
+// A record is deleted only after it is produced successfully,
+// so every event is published at least once.
+//
+// The publisher claims the app for PublishLease in a transaction,
+// and produces the records outside of any transaction,
+// so only one publisher of the app produces the records at the same time.
+// The retries are persisted in the publisher state of the app,
+// and are resumed by the periodic tasks of the worker.
+type Publisher struct {
+	Logger    PublisherLogger
+	Clock     clock.Clock
+	Database  PublisherDatabase
+	Store     PublisherStore
+	Producer  Producer
+	TaskQueue task.Queue
+}
+
+// Publish publishes the pending records if the app is not in back-off.
+func (p *Publisher) Publish() error {
+	if p.Producer == nil {
+		// Event stream is not configured.
+		return nil
+	}
+
+	for i := 0; i < PublishMaxBatches; i++ {
+		records, err := p.claim()
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		produceErr := p.Producer.Produce(records)
+
+		err = p.Database.WithTx(func() error {
+			return p.record(records, produceErr)
+		})
+		if err != nil {
+			return err
+		}
+		if produceErr != nil {
+			return nil
+		}
+	}
+
+	// There may be more pending records, continue in another task
+	// to avoid occupying the app for too long.
+	// Tasks are enqueued when the transaction is committed.
+	return p.Database.WithTx(func() error {
+		p.TaskQueue.Enqueue(&tasks.PublishEventStreamParam{})
+		return nil
+	})
+}
+
+// claim claims the app and returns the records to produce.
+// No records are returned if the app is claimed by others, or is in back-off.
+func (p *Publisher) claim() (records []*Record, err error) {
+	err = p.Database.WithTx(func() error {
+		state, err := p.Store.GetPublisherStateForUpdate()
+		if err != nil {
+			return err
+		}
+
+		now := p.Clock.NowUTC()
+		if !state.IsDue(now) {
+			return nil
+		}
+
+		records, err = p.Store.ListRecords(PublishBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		state.Claim(now, PublishLease)
+		return p.Store.UpdatePublisherState(state)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// record deletes the produced records, or schedules a retry if failed to produce.
+func (p *Publisher) record(records []*Record, produceErr error) error {
+	state, err := p.Store.GetPublisherStateForUpdate()
+	if err != nil {
+		return err
+	}
+
+	if produceErr != nil {
+		nextAttemptAt := state.Fail(p.Clock.NowUTC(), PublishRetryBackoff)
+		p.Logger.WithError(produceErr).WithFields(map[string]interface{}{
+			"seq":             records[0].Seq,
+			"attempts":        state.Attempts,
+			"next_attempt_at": nextAttemptAt,
+		}).Error("failed to publish events, retrying")
+		return p.Store.UpdatePublisherState(state)
+	}
+
+	// The back-off is reset.
+	state.State = backoff.State{}
+	err = p.Store.UpdatePublisherState(state)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r.ID
+	}
+	return p.Store.DeleteRecords(ids)
+}
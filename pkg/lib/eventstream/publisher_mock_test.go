@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: publisher.go
+
+// Package eventstream is a generated GoMock package.
+package eventstream
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPublisherDatabase is a mock of PublisherDatabase interface.
+type MockPublisherDatabase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherDatabaseMockRecorder
+}
+
+// MockPublisherDatabaseMockRecorder is the mock recorder for MockPublisherDatabase.
+type MockPublisherDatabaseMockRecorder struct {
+	mock *MockPublisherDatabase
+}
+
+// NewMockPublisherDatabase creates a new mock instance.
+func NewMockPublisherDatabase(ctrl *gomock.Controller) *MockPublisherDatabase {
+	mock := &MockPublisherDatabase{ctrl: ctrl}
+	mock.recorder = &MockPublisherDatabaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisherDatabase) EXPECT() *MockPublisherDatabaseMockRecorder {
+	return m.recorder
+}
+
+// WithTx mocks base method.
+func (m *MockPublisherDatabase) WithTx(do func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", do)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockPublisherDatabaseMockRecorder) WithTx(do interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockPublisherDatabase)(nil).WithTx), do)
+}
+
+// MockPublisherStore is a mock of PublisherStore interface.
+type MockPublisherStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherStoreMockRecorder
+}
+
+// MockPublisherStoreMockRecorder is the mock recorder for MockPublisherStore.
+type MockPublisherStoreMockRecorder struct {
+	mock *MockPublisherStore
+}
+
+// NewMockPublisherStore creates a new mock instance.
+func NewMockPublisherStore(ctrl *gomock.Controller) *MockPublisherStore {
+	mock := &MockPublisherStore{ctrl: ctrl}
+	mock.recorder = &MockPublisherStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisherStore) EXPECT() *MockPublisherStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteRecords mocks base method.
+func (m *MockPublisherStore) DeleteRecords(ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecords", ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecords indicates an expected call of DeleteRecords.
+func (mr *MockPublisherStoreMockRecorder) DeleteRecords(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecords", reflect.TypeOf((*MockPublisherStore)(nil).DeleteRecords), ids)
+}
+
+// GetPublisherStateForUpdate mocks base method.
+func (m *MockPublisherStore) GetPublisherStateForUpdate() (*PublisherState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublisherStateForUpdate")
+	ret0, _ := ret[0].(*PublisherState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublisherStateForUpdate indicates an expected call of GetPublisherStateForUpdate.
+func (mr *MockPublisherStoreMockRecorder) GetPublisherStateForUpdate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublisherStateForUpdate", reflect.TypeOf((*MockPublisherStore)(nil).GetPublisherStateForUpdate))
+}
+
+// ListRecords mocks base method.
+func (m *MockPublisherStore) ListRecords(limit uint64) ([]*Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecords", limit)
+	ret0, _ := ret[0].([]*Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecords indicates an expected call of ListRecords.
+func (mr *MockPublisherStoreMockRecorder) ListRecords(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecords", reflect.TypeOf((*MockPublisherStore)(nil).ListRecords), limit)
+}
+
+// UpdatePublisherState mocks base method.
+func (m *MockPublisherStore) UpdatePublisherState(state *PublisherState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePublisherState", state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePublisherState indicates an expected call of UpdatePublisherState.
+func (mr *MockPublisherStoreMockRecorder) UpdatePublisherState(state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePublisherState", reflect.TypeOf((*MockPublisherStore)(nil).UpdatePublisherState), state)
+}
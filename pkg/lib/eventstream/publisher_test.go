@@ -0,0 +1,156 @@
+package eventstream
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/backoff"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/log"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type mockTaskQueue struct {
+	params []task.Param
+}
+
+func (q *mockTaskQueue) Enqueue(param task.Param) {
+	q.params = append(q.params, param)
+}
+
+type mockPublisherDatabase struct {
+	txs int
+}
+
+func (d *mockPublisherDatabase) WithTx(do func() error) error {
+	d.txs++
+	return do()
+}
+
+func TestPublisher(t *testing.T) {
+	Convey("Publisher", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		clock := clock.NewMockClockAt("2006-01-02T15:04:05Z")
+		now := clock.NowUTC()
+		database := &mockPublisherDatabase{}
+		store := NewMockPublisherStore(ctrl)
+		producer := NewMockProducer(ctrl)
+		queue := &mockTaskQueue{}
+
+		p := &Publisher{
+			Logger:    PublisherLogger{log.Null},
+			Clock:     clock,
+			Database:  database,
+			Store:     store,
+			Producer:  producer,
+			TaskQueue: queue,
+		}
+
+		records := []*Record{
+			{ID: "a", Seq: 1},
+			{ID: "b", Seq: 2},
+		}
+
+		Convey("should publish and delete records in order", func() {
+			state := &PublisherState{}
+			claimedUntil := now.Add(PublishLease)
+			gomock.InOrder(
+				store.EXPECT().GetPublisherStateForUpdate().Return(state, nil),
+				store.EXPECT().ListRecords(uint64(PublishBatchSize)).Return(records, nil),
+				store.EXPECT().UpdatePublisherState(state).DoAndReturn(func(s *PublisherState) error {
+					// The app is claimed before producing the records.
+					So(s.ClaimedUntil, ShouldResemble, &claimedUntil)
+					return nil
+				}),
+				producer.EXPECT().Produce(records).DoAndReturn(func(records []*Record) error {
+					// The records are produced outside of a transaction.
+					So(database.txs, ShouldEqual, 1)
+					return nil
+				}),
+				store.EXPECT().GetPublisherStateForUpdate().Return(state, nil),
+				store.EXPECT().UpdatePublisherState(state).Return(nil),
+				store.EXPECT().DeleteRecords([]string{"a", "b"}).Return(nil),
+				store.EXPECT().GetPublisherStateForUpdate().Return(state, nil),
+				store.EXPECT().ListRecords(uint64(PublishBatchSize)).Return(nil, nil),
+			)
+
+			err := p.Publish()
+			So(err, ShouldBeNil)
+			So(state.ClaimedUntil, ShouldBeNil)
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should keep records and retry with back-off if failed to produce", func() {
+			state := &PublisherState{State: backoff.State{Attempts: 2}}
+			gomock.InOrder(
+				store.EXPECT().GetPublisherStateForUpdate().Return(state, nil),
+				store.EXPECT().ListRecords(uint64(PublishBatchSize)).Return(records, nil),
+				store.EXPECT().UpdatePublisherState(state).Return(nil),
+				producer.EXPECT().Produce(records).Return(errors.New("unavailable")),
+				store.EXPECT().GetPublisherStateForUpdate().Return(state, nil),
+				store.EXPECT().UpdatePublisherState(state).Return(nil),
+			)
+			store.EXPECT().DeleteRecords(gomock.Any()).Times(0)
+
+			err := p.Publish()
+			So(err, ShouldBeNil)
+			nextAttemptAt := now.Add(40 * time.Second)
+			So(state, ShouldResemble, &PublisherState{State: backoff.State{
+				Attempts:      3,
+				NextAttemptAt: &nextAttemptAt,
+			}})
+			// The retry is resumed by the periodic tasks after it is due.
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should not publish before the retry is due", func() {
+			nextAttemptAt := now.Add(time.Second)
+			state := &PublisherState{State: backoff.State{Attempts: 1, NextAttemptAt: &nextAttemptAt}}
+			store.EXPECT().GetPublisherStateForUpdate().Return(state, nil)
+			producer.EXPECT().Produce(gomock.Any()).Times(0)
+
+			err := p.Publish()
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should not publish if the app is claimed by another publisher", func() {
+			claimedUntil := now.Add(time.Minute)
+			state := &PublisherState{State: backoff.State{ClaimedUntil: &claimedUntil}}
+			store.EXPECT().GetPublisherStateForUpdate().Return(state, nil)
+			producer.EXPECT().Produce(gomock.Any()).Times(0)
+
+			err := p.Publish()
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should continue in another task if there are too many records", func() {
+			state := &PublisherState{}
+			store.EXPECT().GetPublisherStateForUpdate().Times(2*PublishMaxBatches).Return(state, nil)
+			store.EXPECT().UpdatePublisherState(state).Times(2 * PublishMaxBatches).Return(nil)
+			store.EXPECT().ListRecords(uint64(PublishBatchSize)).Times(PublishMaxBatches).Return(records, nil)
+			producer.EXPECT().Produce(records).Times(PublishMaxBatches).Return(nil)
+			store.EXPECT().DeleteRecords([]string{"a", "b"}).Times(PublishMaxBatches).Return(nil)
+
+			err := p.Publish()
+			So(err, ShouldBeNil)
+			So(queue.params, ShouldResemble, []task.Param{
+				&tasks.PublishEventStreamParam{},
+			})
+		})
+
+		Convey("should do nothing if event stream is not configured", func() {
+			p.Producer = nil
+
+			err := p.Publish()
+			So(err, ShouldBeNil)
+		})
+	})
+}
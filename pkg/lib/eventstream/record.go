@@ -0,0 +1,23 @@
+package eventstream
+
+import (
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/util/backoff"
+)
+
+// Record is a non-blocking event pending to be published to the event stream.
+type Record struct {
+	ID        string
+	CreatedAt time.Time
+	Seq       int64
+	EventType string
+	Payload   []byte
+}
+
+// PublisherState is the state of the publisher of an app.
+// Attempts is the number of consecutive failed attempts,
+// and an attempt claims the app to produce the records.
+type PublisherState struct {
+	backoff.State
+}
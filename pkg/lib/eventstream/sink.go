@@ -0,0 +1,57 @@
+package eventstream
+
+import (
+	"encoding/json"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/uuid"
+)
+
+//go:generate mockgen -source=sink.go -destination=sink_mock_test.go -package eventstream
+
+type SinkStore interface {
+	CreateRecord(r *Record) error
+}
+
+// Sink persists every non-blocking event in the transaction relaying it,
+// and publishes the events to the event stream after commit.
+type Sink struct {
+	Credentials *config.EventStreamCredentials
+	Clock       clock.Clock
+	Store       SinkStore
+	TaskQueue   task.Queue
+}
+
+func (s *Sink) ReceiveBlockingEvent(e *event.Event) error {
+	// Blocking events are not published.
+	return nil
+}
+
+func (s *Sink) ReceiveNonBlockingEvent(e *event.Event) error {
+	if s.Credentials == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	err = s.Store.CreateRecord(&Record{
+		ID:        uuid.New(),
+		CreatedAt: s.Clock.NowUTC(),
+		Seq:       e.Seq,
+		EventType: string(e.Type),
+		Payload:   payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.TaskQueue.Enqueue(&tasks.PublishEventStreamParam{})
+	return nil
+}
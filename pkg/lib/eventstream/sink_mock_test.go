@@ -0,0 +1,48 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: sink.go
+
+// Package eventstream is a generated GoMock package.
+package eventstream
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSinkStore is a mock of SinkStore interface.
+type MockSinkStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSinkStoreMockRecorder
+}
+
+// MockSinkStoreMockRecorder is the mock recorder for MockSinkStore.
+type MockSinkStoreMockRecorder struct {
+	mock *MockSinkStore
+}
+
+// NewMockSinkStore creates a new mock instance.
+func NewMockSinkStore(ctrl *gomock.Controller) *MockSinkStore {
+	mock := &MockSinkStore{ctrl: ctrl}
+	mock.recorder = &MockSinkStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSinkStore) EXPECT() *MockSinkStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateRecord mocks base method.
+func (m *MockSinkStore) CreateRecord(r *Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecord", r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecord indicates an expected call of CreateRecord.
+func (mr *MockSinkStoreMockRecorder) CreateRecord(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecord", reflect.TypeOf((*MockSinkStore)(nil).CreateRecord), r)
+}
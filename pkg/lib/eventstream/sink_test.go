@@ -0,0 +1,67 @@
+package eventstream
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSink(t *testing.T) {
+	Convey("Sink", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		clock := clock.NewMockClockAt("2006-01-02T15:04:05Z")
+		store := NewMockSinkStore(ctrl)
+		queue := &mockTaskQueue{}
+
+		s := &Sink{
+			Credentials: &config.EventStreamCredentials{
+				RedisURL: "redis://localhost",
+				Stream:   "authgear-events",
+			},
+			Clock:     clock,
+			Store:     store,
+			TaskQueue: queue,
+		}
+
+		e := &event.Event{
+			ID:            "event-id",
+			Seq:           42,
+			Type:          "user.created",
+			IsNonBlocking: true,
+		}
+
+		Convey("should persist event and publish after relay", func() {
+			store.EXPECT().CreateRecord(gomock.Any()).DoAndReturn(func(r *Record) error {
+				So(r.Seq, ShouldEqual, 42)
+				So(r.EventType, ShouldEqual, "user.created")
+				So(r.CreatedAt, ShouldEqual, clock.NowUTC())
+				So(string(r.Payload), ShouldContainSubstring, `"seq":42`)
+				return nil
+			})
+
+			err := s.ReceiveNonBlockingEvent(e)
+			So(err, ShouldBeNil)
+			So(queue.params, ShouldResemble, []task.Param{
+				&tasks.PublishEventStreamParam{},
+			})
+		})
+
+		Convey("should do nothing if event stream is not configured", func() {
+			s.Credentials = nil
+
+			err := s.ReceiveNonBlockingEvent(e)
+			So(err, ShouldBeNil)
+			So(queue.params, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,134 @@
+package eventstream
+
+import (
+	"github.com/lib/pq"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
+)
+
+type Store struct {
+	AppID       config.AppID
+	SQLBuilder  *appdb.SQLBuilder
+	SQLExecutor *appdb.SQLExecutor
+}
+
+func (s *Store) CreateRecord(r *Record) error {
+	q := s.SQLBuilder.WithAppID(string(s.AppID)).
+		Insert(s.SQLBuilder.TableName("_auth_event_stream")).
+		Columns(
+			"id",
+			"created_at",
+			"seq",
+			"event_type",
+			"payload",
+		).
+		Values(
+			r.ID,
+			r.CreatedAt,
+			r.Seq,
+			r.EventType,
+			r.Payload,
+		)
+
+	_, err := s.SQLExecutor.ExecWith(q)
+	return err
+}
+
+// GetPublisherStateForUpdate locks the publisher state of the app
+// until the end of the transaction. The state is created if it does not exist.
+func (s *Store) GetPublisherStateForUpdate() (*PublisherState, error) {
+	insert := s.SQLBuilder.WithAppID(string(s.AppID)).
+		Insert(s.SQLBuilder.TableName("_auth_event_stream_publisher")).
+		Columns("attempts").
+		Values(0).
+		Suffix("ON CONFLICT DO NOTHING")
+	_, err := s.SQLExecutor.ExecWith(insert)
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.SQLBuilder.WithAppID(string(s.AppID)).
+		Select(
+			"attempts",
+			"next_attempt_at",
+			"claimed_until",
+		).
+		From(s.SQLBuilder.TableName("_auth_event_stream_publisher")).
+		Suffix("FOR UPDATE")
+	scanner, err := s.SQLExecutor.QueryRowWith(q)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &PublisherState{}
+	err = scanner.Scan(
+		&state.Attempts,
+		&state.NextAttemptAt,
+		&state.ClaimedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (s *Store) UpdatePublisherState(state *PublisherState) error {
+	q := s.SQLBuilder.WithAppID(string(s.AppID)).
+		Update(s.SQLBuilder.TableName("_auth_event_stream_publisher")).
+		Set("attempts", state.Attempts).
+		Set("next_attempt_at", state.NextAttemptAt).
+		Set("claimed_until", state.ClaimedUntil)
+
+	_, err := s.SQLExecutor.ExecWith(q)
+	return err
+}
+
+// ListRecords lists the earliest records in the order of seq.
+func (s *Store) ListRecords(limit uint64) ([]*Record, error) {
+	q := s.SQLBuilder.WithAppID(string(s.AppID)).
+		Select(
+			"id",
+			"created_at",
+			"seq",
+			"event_type",
+			"payload",
+		).
+		From(s.SQLBuilder.TableName("_auth_event_stream")).
+		OrderBy("seq ASC").
+		Limit(limit)
+
+	rows, err := s.SQLExecutor.QueryWith(q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*Record
+	for rows.Next() {
+		r := &Record{}
+		err := rows.Scan(
+			&r.ID,
+			&r.CreatedAt,
+			&r.Seq,
+			&r.EventType,
+			&r.Payload,
+		)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+func (s *Store) DeleteRecords(ids []string) error {
+	q := s.SQLBuilder.WithAppID(string(s.AppID)).
+		Delete(s.SQLBuilder.TableName("_auth_event_stream")).
+		Where("id = ANY (?)", pq.Array(ids))
+
+	_, err := s.SQLExecutor.ExecWith(q)
+	return err
+}
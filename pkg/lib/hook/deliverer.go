@@ -2,19 +2,28 @@ package hook
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/url"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
 
 	"github.com/authgear/authgear-server/pkg/api/apierrors"
 	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/webhook"
 	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/util/accesscontrol"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/crypto"
+	"github.com/authgear/authgear-server/pkg/util/jsruntime"
 	"github.com/authgear/authgear-server/pkg/util/jwkutil"
+	"github.com/authgear/authgear-server/pkg/util/resource"
 )
 
 //go:generate mockgen -source=deliverer.go -destination=deliverer_mock_test.go -package hook
@@ -27,14 +36,26 @@ type CustomAttributesServiceNoEvent interface {
 	UpdateAllCustomAttributes(role accesscontrol.Role, userID string, reprForm map[string]interface{}) error
 }
 
+type DeliveryScheduler interface {
+	Schedule(e *event.Event, url string) error
+}
+
+// HookScriptMemoryLimit is the maximum memory a hook script can allocate.
+const HookScriptMemoryLimit = 32 * 1024 * 1024
+
+// HookScriptFunction is the name of the global function a hook script must define.
+const HookScriptFunction = "handler"
+
 type Deliverer struct {
 	Config             *config.HookConfig
 	Secret             *config.WebhookKeyMaterials
 	Clock              clock.Clock
 	SyncHTTP           SyncHTTPClient
-	AsyncHTTP          AsyncHTTPClient
+	Deliveries         DeliveryScheduler
 	StandardAttributes StandardAttributesServiceNoEvent
 	CustomAttributes   CustomAttributesServiceNoEvent
+	Resources          ResourceManager
+	Credentials        *config.WebhookHandlerCredentials
 }
 
 func (deliverer *Deliverer) DeliverBlockingEvent(e *event.Event) error {
@@ -47,6 +68,14 @@ func (deliverer *Deliverer) DeliverBlockingEvent(e *event.Event) error {
 			continue
 		}
 
+		matched, err := matchFilters(hook.Filters, e)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+
 		elapsed := deliverer.Clock.NowMonotonic().Sub(startTime)
 		if elapsed > totalTimeout {
 			return WebHookDeliveryTimeout.NewWithInfo("webhook delivery timeout", apierrors.Details{
@@ -55,14 +84,29 @@ func (deliverer *Deliverer) DeliverBlockingEvent(e *event.Event) error {
 			})
 		}
 
-		request, err := deliverer.prepareRequest(hook.URL, e)
-		if err != nil {
-			return err
-		}
+		var resp *event.HookResponse
+		if scriptPath, ok := config.HookScriptPath(hook.URL); ok {
+			var err error
+			resp, err = deliverer.runScript(scriptPath, e)
+			if err != nil {
+				return err
+			}
+		} else {
+			creds := lookupCredentials(deliverer.Credentials, hook.URL)
+			request, err := deliverer.prepareRequest(hook.URL, creds, e)
+			if err != nil {
+				return err
+			}
 
-		resp, err := performRequest(deliverer.SyncHTTP.Client, request, true)
-		if err != nil {
-			return err
+			client, err := clientWithCredentials(deliverer.SyncHTTP.Client, creds)
+			if err != nil {
+				return err
+			}
+
+			_, resp, err = performRequest(client, request, true)
+			if err != nil {
+				return err
+			}
 		}
 
 		if !resp.IsAllowed {
@@ -134,12 +178,15 @@ func (deliverer *Deliverer) DeliverNonBlockingEvent(e *event.Event) error {
 			continue
 		}
 
-		request, err := deliverer.prepareRequest(hook.URL, e)
+		matched, err := matchFilters(hook.Filters, e)
 		if err != nil {
 			return err
 		}
+		if !matched {
+			continue
+		}
 
-		_, err = performRequest(deliverer.AsyncHTTP.Client, request, false)
+		err = deliverer.Deliveries.Schedule(e, hook.URL)
 		if err != nil {
 			return err
 		}
@@ -171,35 +218,152 @@ func (deliverer *Deliverer) WillDeliverNonBlockingEvent(eventType event.Type) bo
 	return false
 }
 
-func (deliverer *Deliverer) prepareRequest(urlStr string, event *event.Event) (*http.Request, error) {
+// TestDeliveryResult is the result of delivering a sample event with TestDeliver.
+type TestDeliveryResult struct {
+	// StatusCode is the HTTP status code of the response.
+	// It is zero if no response is received, or the handler is a hook script.
+	StatusCode int
+	// Duration is the time taken to deliver the event.
+	Duration time.Duration
+	// Response is the parsed hook response. It is nil for non-blocking events.
+	Response *event.HookResponse
+	// Error is the reason of the failed delivery, e.g. the validation error of the hook response.
+	Error error
+}
+
+// TestDeliver delivers e to the handler at urlStr synchronously, regardless of
+// the configured handlers and filters, and reports the outcome.
+// Mutations in the hook response are not applied.
+func (deliverer *Deliverer) TestDeliver(urlStr string, e *event.Event) (*TestDeliveryResult, error) {
+	result := &TestDeliveryResult{}
+	startTime := deliverer.Clock.NowMonotonic()
+
+	if config.IsHookScriptURL(urlStr) {
+		scriptPath, ok := config.HookScriptPath(urlStr)
+		if !ok || e.IsNonBlocking {
+			return nil, apierrors.NewInvalid("hook script is only supported for blocking events")
+		}
+		result.Response, result.Error = deliverer.runScript(scriptPath, e)
+	} else {
+		creds := lookupCredentials(deliverer.Credentials, urlStr)
+		request, err := deliverer.prepareRequest(urlStr, creds, e)
+		if err != nil {
+			return nil, err
+		}
+
+		client, err := clientWithCredentials(deliverer.SyncHTTP.Client, creds)
+		if err != nil {
+			return nil, err
+		}
+
+		result.StatusCode, result.Response, result.Error = performRequest(client, request, !e.IsNonBlocking)
+	}
+
+	result.Duration = deliverer.Clock.NowMonotonic().Sub(startTime)
+	return result, nil
+}
+
+func (deliverer *Deliverer) prepareRequest(urlStr string, creds *config.WebhookHandlerCredentialsItem, event *event.Event) (*http.Request, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+
+	return newRequest(deliverer.Secret, creds, deliverer.Clock.NowUTC(), urlStr, body)
+}
+
+func (deliverer *Deliverer) runScript(scriptPath string, e *event.Event) (*event.HookResponse, error) {
+	script, err := deliverer.Resources.Read(HookScript, resource.EffectiveFile{Path: scriptPath})
+	if err != nil {
+		return nil, fmt.Errorf("webhook: failed to read %s: %w", scriptPath, err)
+	}
+
+	input, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+
+	output, err := jsruntime.Run(jsruntime.RunOptions{
+		Filename:    scriptPath,
+		Script:      string(script.([]byte)),
+		Function:    HookScriptFunction,
+		Input:       input,
+		Timeout:     deliverer.Config.SyncTimeout.Duration(),
+		MemoryLimit: HookScriptMemoryLimit,
+	})
+	if errors.Is(err, jsruntime.ErrTimeout) {
+		return nil, WebHookDeliveryTimeout.New("webhook delivery timeout")
+	} else if err != nil {
+		return nil, WebHookInvalidResponse.NewWithInfo("hook script error", apierrors.Details{
+			"error": err.Error(),
+		})
+	}
+
+	hookResp, err := event.ParseHookResponse(bytes.NewReader(output))
+	if err != nil {
+		apiError := apierrors.AsAPIError(err)
+		return nil, WebHookInvalidResponse.NewWithInfo("invalid response body", apiError.Info)
+	}
+
+	return hookResp, nil
+}
+
+func newRequest(secret *config.WebhookKeyMaterials, creds *config.WebhookHandlerCredentialsItem, now time.Time, urlStr string, body []byte) (*http.Request, error) {
 	hookURL, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, fmt.Errorf("webhook: %w", err)
 	}
 
-	body, err := json.Marshal(event)
+	// The legacy body signature is signed with the first key only.
+	key, err := jwkutil.ExtractOctetKey(secret.Set, "")
 	if err != nil {
 		return nil, fmt.Errorf("webhook: %w", err)
 	}
+	bodySignature := crypto.HMACSHA256String(key, body)
 
-	key, err := jwkutil.ExtractOctetKey(deliverer.Secret.Set, "")
+	keys, err := signingKeys(secret)
 	if err != nil {
 		return nil, fmt.Errorf("webhook: %w", err)
 	}
-	signature := crypto.HMACSHA256String(key, body)
+	signature := webhook.Sign(keys, now, body)
 
 	request, err := http.NewRequest("POST", hookURL.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("webhook: %w", err)
 	}
 
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add(HeaderRequestBodySignature, signature)
+	// Custom headers cannot override the headers set by Authgear.
+	if creds != nil {
+		for name, value := range creds.Headers {
+			request.Header.Set(name, value)
+		}
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderRequestBodySignature, bodySignature)
+	request.Header.Set(webhook.HeaderSignature, signature)
 
 	return request, nil
 }
 
-func performRequest(client *http.Client, request *http.Request, withResponse bool) (hookResp *event.HookResponse, err error) {
+// signingKeys returns all keys in secret, so that the receiver can verify
+// the request with either the old or the new key during key rotation.
+func signingKeys(secret *config.WebhookKeyMaterials) ([]webhook.Key, error) {
+	var keys []webhook.Key
+	for it := secret.Set.Iterate(context.Background()); it.Next(context.Background()); {
+		key := it.Pair().Value.(jwk.Key)
+		if key.KeyType() != jwa.OctetSeq {
+			return nil, errors.New("unexpected key type (key type should be octet)")
+		}
+		var octetKey []byte
+		if err := key.Raw(&octetKey); err != nil {
+			return nil, err
+		}
+		keys = append(keys, webhook.Key{ID: key.KeyID(), Secret: octetKey})
+	}
+	return keys, nil
+}
+
+func performRequest(client *http.Client, request *http.Request, withResponse bool) (statusCode int, hookResp *event.HookResponse, err error) {
 	var resp *http.Response
 	resp, err = client.Do(request)
 	if reqError, ok := err.(net.Error); ok && reqError.Timeout() {
@@ -212,6 +376,7 @@ func performRequest(client *http.Client, request *http.Request, withResponse boo
 
 	defer resp.Body.Close()
 
+	statusCode = resp.StatusCode
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		err = WebHookInvalidResponse.NewWithInfo("invalid status code", apierrors.Details{
 			"status_code": resp.StatusCode,
@@ -7,6 +7,7 @@ package hook
 import (
 	reflect "reflect"
 
+	event "github.com/authgear/authgear-server/pkg/api/event"
 	accesscontrol "github.com/authgear/authgear-server/pkg/util/accesscontrol"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -84,3 +85,40 @@ func (mr *MockCustomAttributesServiceNoEventMockRecorder) UpdateAllCustomAttribu
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAllCustomAttributes", reflect.TypeOf((*MockCustomAttributesServiceNoEvent)(nil).UpdateAllCustomAttributes), role, userID, reprForm)
 }
+
+// MockDeliveryScheduler is a mock of DeliveryScheduler interface.
+type MockDeliveryScheduler struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliverySchedulerMockRecorder
+}
+
+// MockDeliverySchedulerMockRecorder is the mock recorder for MockDeliveryScheduler.
+type MockDeliverySchedulerMockRecorder struct {
+	mock *MockDeliveryScheduler
+}
+
+// NewMockDeliveryScheduler creates a new mock instance.
+func NewMockDeliveryScheduler(ctrl *gomock.Controller) *MockDeliveryScheduler {
+	mock := &MockDeliveryScheduler{ctrl: ctrl}
+	mock.recorder = &MockDeliverySchedulerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryScheduler) EXPECT() *MockDeliverySchedulerMockRecorder {
+	return m.recorder
+}
+
+// Schedule mocks base method.
+func (m *MockDeliveryScheduler) Schedule(e *event.Event, url string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", e, url)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockDeliverySchedulerMockRecorder) Schedule(e, url interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockDeliveryScheduler)(nil).Schedule), e, url)
+}
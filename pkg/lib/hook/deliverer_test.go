@@ -3,16 +3,21 @@ package hook
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/spf13/afero"
 	"gopkg.in/h2non/gock.v1"
 
 	"github.com/authgear/authgear-server/pkg/api/event"
 	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/api/webhook"
 	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/util/accesscontrol"
 	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/crypto"
+	"github.com/authgear/authgear-server/pkg/util/resource"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -40,15 +45,21 @@ func TestDeliverer(t *testing.T) {
 		gock.InterceptClient(httpClient)
 		stdAttrsService := NewMockStandardAttributesServiceNoEvent(ctrl)
 		customAttrsService := NewMockCustomAttributesServiceNoEvent(ctrl)
+		deliveries := NewMockDeliveryScheduler(ctrl)
+		appFs := afero.NewMemMapFs()
+		resources := resource.NewManager(resource.DefaultRegistry, []resource.Fs{
+			resource.LeveledAferoFs{Fs: appFs, FsLevel: resource.FsLevelApp},
+		})
 
 		deliverer := Deliverer{
 			Config:             cfg,
 			Secret:             secret,
 			Clock:              clock,
 			SyncHTTP:           SyncHTTPClient{httpClient},
-			AsyncHTTP:          AsyncHTTPClient{httpClient},
+			Deliveries:         deliveries,
 			StandardAttributes: stdAttrsService,
 			CustomAttributes:   customAttrsService,
+			Resources:          resources,
 		}
 
 		defer gock.Off()
@@ -267,6 +278,45 @@ func TestDeliverer(t *testing.T) {
 				So(gock.IsDone(), ShouldBeTrue)
 			})
 
+			Convey("should apply filters and custom headers", func() {
+				e.Context = event.Context{ClientID: "client-a"}
+				cfg.BlockingHandlers = []config.BlockingHandlersConfig{
+					{
+						Event: string(MockBlockingEventType1),
+						URL:   "https://example.com/a",
+						Filters: []config.HookHandlerFilterConfig{
+							{Pointer: "/context/client_id", Values: []interface{}{"client-a"}},
+						},
+					},
+					{
+						Event: string(MockBlockingEventType1),
+						URL:   "https://example.com/b",
+						Filters: []config.HookHandlerFilterConfig{
+							{Pointer: "/context/client_id", Values: []interface{}{"client-b"}},
+						},
+					},
+				}
+				deliverer.Credentials = &config.WebhookHandlerCredentials{
+					Items: []config.WebhookHandlerCredentialsItem{
+						{URL: "https://example.com/a", Headers: map[string]string{"Authorization": "Bearer token"}},
+					},
+				}
+
+				gock.New("https://example.com").
+					Post("/a").
+					MatchHeader("Authorization", "^Bearer token$").
+					Reply(200).
+					JSON(map[string]interface{}{
+						"is_allowed": true,
+					})
+				defer func() { gock.Flush() }()
+
+				err := deliverer.DeliverBlockingEvent(&e)
+
+				So(err, ShouldBeNil)
+				So(gock.IsDone(), ShouldBeTrue)
+			})
+
 			Convey("should reject invalid status code", func() {
 				cfg.BlockingHandlers = []config.BlockingHandlersConfig{
 					{
@@ -326,6 +376,73 @@ func TestDeliverer(t *testing.T) {
 				So(err, ShouldBeError, "webhook delivery timeout")
 				So(gock.IsDone(), ShouldBeTrue)
 			})
+
+			Convey("should run hook scripts", func() {
+				cfg.BlockingHandlers = []config.BlockingHandlersConfig{
+					{
+						Event: string(MockBlockingEventType1),
+						URL:   "authgearjs://hooks/allow.js",
+					},
+					{
+						Event: string(MockBlockingEventType1),
+						URL:   "authgearjs://hooks/disallow.js",
+					},
+				}
+
+				_ = afero.WriteFile(appFs, "hooks/allow.js", []byte(`
+					function handler(e) {
+						return { is_allowed: e.id === "event-id" };
+					}
+				`), 0666)
+				_ = afero.WriteFile(appFs, "hooks/disallow.js", []byte(`
+					async function handler(e) {
+						return { is_allowed: false, reason: "nope" };
+					}
+				`), 0666)
+
+				err := deliverer.DeliverBlockingEvent(&e)
+
+				So(err, ShouldBeError, "disallowed by web-hook event handler")
+			})
+
+			Convey("should reject invalid hook script response", func() {
+				cfg.BlockingHandlers = []config.BlockingHandlersConfig{
+					{
+						Event: string(MockBlockingEventType1),
+						URL:   "authgearjs://hooks/a.js",
+					},
+				}
+
+				_ = afero.WriteFile(appFs, "hooks/a.js", []byte(`
+					function handler(e) {
+						return { allowed: true };
+					}
+				`), 0666)
+
+				err := deliverer.DeliverBlockingEvent(&e)
+
+				So(err, ShouldBeError, "invalid response body")
+			})
+
+			Convey("should time out long running hook scripts", func() {
+				cfg.SyncTimeout = 1
+				cfg.BlockingHandlers = []config.BlockingHandlersConfig{
+					{
+						Event: string(MockBlockingEventType1),
+						URL:   "authgearjs://hooks/a.js",
+					},
+				}
+
+				_ = afero.WriteFile(appFs, "hooks/a.js", []byte(`
+					function handler(e) {
+						for (;;) {}
+					}
+				`), 0666)
+
+				err := deliverer.DeliverBlockingEvent(&e)
+
+				So(err, ShouldBeError, "webhook delivery timeout")
+			})
 		})
 
 		Convey("delivering non-blocking events", func() {
@@ -347,18 +464,200 @@ func TestDeliverer(t *testing.T) {
 					},
 				}
 
+				deliveries.EXPECT().Schedule(&e, "https://example.com/a").Return(nil)
+
+				err := deliverer.DeliverNonBlockingEvent(&e)
+
+				So(err, ShouldBeNil)
+			})
+
+			Convey("should apply filters", func() {
+				e.Context = event.Context{ClientID: "client-a"}
+				cfg.NonBlockingHandlers = []config.NonBlockingHandlersConfig{
+					{
+						Events: []string{"*"},
+						URL:    "https://example.com/a",
+						Filters: []config.HookHandlerFilterConfig{
+							{Pointer: "/context/client_id", Values: []interface{}{"client-a", "client-b"}},
+						},
+					},
+					{
+						Events: []string{"*"},
+						URL:    "https://example.com/b",
+						Filters: []config.HookHandlerFilterConfig{
+							{Pointer: "/context/client_id", Values: []interface{}{"client-b"}},
+						},
+					},
+					{
+						Events: []string{"*"},
+						URL:    "https://example.com/c",
+						Filters: []config.HookHandlerFilterConfig{
+							{Pointer: "/payload/identity/type", Values: []interface{}{"login_id"}},
+						},
+					},
+				}
+
+				deliveries.EXPECT().Schedule(&e, "https://example.com/a").Return(nil)
+
+				err := deliverer.DeliverNonBlockingEvent(&e)
+
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("test delivering events", func() {
+			e := event.Event{
+				ID:   "event-id",
+				Type: MockBlockingEventType1,
+			}
+
+			Convey("should return parsed hook response and timing", func() {
 				gock.New("https://example.com").
 					Post("/a").
 					JSON(e).
+					HeaderPresent(webhook.HeaderSignature).
 					Reply(200).
-					BodyString("test")
+					Map(func(resp *http.Response) *http.Response {
+						clock.AdvanceSeconds(1)
+						return resp
+					}).
+					JSON(map[string]interface{}{
+						"is_allowed": false,
+						"title":      "Title",
+						"reason":     "Reason",
+					})
 				defer func() { gock.Flush() }()
 
-				err := deliverer.DeliverNonBlockingEvent(&e)
+				result, err := deliverer.TestDeliver("https://example.com/a", &e)
 
 				So(err, ShouldBeNil)
+				So(result.Error, ShouldBeNil)
+				So(result.StatusCode, ShouldEqual, 200)
+				So(result.Duration, ShouldEqual, time.Second)
+				So(result.Response, ShouldResemble, &event.HookResponse{
+					IsAllowed: false,
+					Title:     "Title",
+					Reason:    "Reason",
+				})
 				So(gock.IsDone(), ShouldBeTrue)
 			})
+
+			Convey("should report invalid hook response", func() {
+				gock.New("https://example.com").
+					Post("/a").
+					Reply(200).
+					JSON(map[string]interface{}{
+						"allowed": true,
+					})
+				defer func() { gock.Flush() }()
+
+				result, err := deliverer.TestDeliver("https://example.com/a", &e)
+
+				So(err, ShouldBeNil)
+				So(result.StatusCode, ShouldEqual, 200)
+				So(result.Response, ShouldBeNil)
+				So(result.Error, ShouldBeError, "invalid response body")
+			})
+
+			Convey("should report invalid status code", func() {
+				gock.New("https://example.com").
+					Post("/a").
+					Reply(500)
+				defer func() { gock.Flush() }()
+
+				result, err := deliverer.TestDeliver("https://example.com/a", &e)
+
+				So(err, ShouldBeNil)
+				So(result.StatusCode, ShouldEqual, 500)
+				So(result.Error, ShouldBeError, "invalid status code")
+			})
+
+			Convey("should not parse response of non-blocking events", func() {
+				e.IsNonBlocking = true
+				gock.New("https://example.com").
+					Post("/a").
+					Reply(200)
+				defer func() { gock.Flush() }()
+
+				result, err := deliverer.TestDeliver("https://example.com/a", &e)
+
+				So(err, ShouldBeNil)
+				So(result.StatusCode, ShouldEqual, 200)
+				So(result.Response, ShouldBeNil)
+				So(result.Error, ShouldBeNil)
+			})
+
+			Convey("should run hook scripts", func() {
+				_ = afero.WriteFile(appFs, "hooks/a.js", []byte(`
+					function handler(e) {
+						return { is_allowed: true };
+					}
+				`), 0666)
+
+				result, err := deliverer.TestDeliver("authgearjs://hooks/a.js", &e)
+
+				So(err, ShouldBeNil)
+				So(result.StatusCode, ShouldEqual, 0)
+				So(result.Error, ShouldBeNil)
+				So(result.Response.IsAllowed, ShouldBeTrue)
+			})
+
+			Convey("should reject hook scripts for non-blocking events", func() {
+				e.IsNonBlocking = true
+
+				_, err := deliverer.TestDeliver("authgearjs://hooks/a.js", &e)
+
+				So(err, ShouldBeError, "hook script is only supported for blocking events")
+			})
+		})
+	})
+}
+
+func TestNewRequest(t *testing.T) {
+	Convey("newRequest", t, func() {
+		now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+		body := []byte(`{"type":"user.created"}`)
+
+		key1, err := jwk.New([]byte("secret1"))
+		So(err, ShouldBeNil)
+		_ = key1.Set(jwk.KeyIDKey, "key-1")
+		key2, err := jwk.New([]byte("secret2"))
+		So(err, ShouldBeNil)
+		_ = key2.Set(jwk.KeyIDKey, "key-2")
+		set := jwk.NewSet()
+		_ = set.Add(key1)
+		_ = set.Add(key2)
+
+		request, err := newRequest(&config.WebhookKeyMaterials{Set: set}, nil, now, "https://example.com/a", body)
+		So(err, ShouldBeNil)
+
+		Convey("should sign with all keys", func() {
+			header := request.Header.Get(webhook.HeaderSignature)
+			for _, key := range []webhook.Key{
+				{ID: "key-1", Secret: []byte("secret1")},
+				{ID: "key-2", Secret: []byte("secret2")},
+			} {
+				err := webhook.Verify(header, body, []webhook.Key{key}, now, webhook.DefaultTolerance)
+				So(err, ShouldBeNil)
+			}
+		})
+
+		Convey("should not let custom headers override signatures", func() {
+			creds := &config.WebhookHandlerCredentialsItem{
+				URL: "https://example.com/a",
+				Headers: map[string]string{
+					"Authorization":         "Bearer token",
+					webhook.HeaderSignature: "forged",
+				},
+			}
+			request, err := newRequest(&config.WebhookKeyMaterials{Set: set}, creds, now, "https://example.com/a", body)
+			So(err, ShouldBeNil)
+			So(request.Header.Get("Authorization"), ShouldEqual, "Bearer token")
+			So(request.Header.Get(webhook.HeaderSignature), ShouldStartWith, "t=")
+		})
+
+		Convey("should keep legacy body signature", func() {
+			So(request.Header.Get(HeaderRequestBodySignature), ShouldEqual, crypto.HMACSHA256String([]byte("secret1"), body))
 		})
 	})
 }
@@ -0,0 +1,64 @@
+package hook
+
+import (
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/util/backoff"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// DeliveryRetryBackoff is the back-off between the attempts of a delivery.
+var DeliveryRetryBackoff = backoff.Exponential{Base: 1 * time.Minute, Max: 6 * time.Hour}
+
+const (
+	// DeliveryRetryPeriod is the period after the creation of a delivery
+	// in which the delivery is retried automatically.
+	DeliveryRetryPeriod = 72 * time.Hour
+	// DeliveryAttemptLease is the period for which a delivery is claimed by an attempt.
+	// It is longer than the timeout of the request, so that the delivery is not
+	// attempted concurrently, and is attempted again if the server stopped during the attempt.
+	DeliveryAttemptLease = 2 * time.Minute
+	// DeliveryResponseExcerptMaxLength is the maximum length of the response body recorded.
+	DeliveryResponseExcerptMaxLength = 1024
+)
+
+// Delivery is a non-blocking event delivery to a webhook handler.
+type Delivery struct {
+	ID              string         `json:"id"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+	EventID         string         `json:"eventID"`
+	EventType       string         `json:"eventType"`
+	URL             string         `json:"url"`
+	Payload         []byte         `json:"-"`
+	Status          DeliveryStatus `json:"status"`
+	LastAttemptedAt *time.Time     `json:"lastAttemptedAt,omitempty"`
+	backoff.State
+}
+
+// IsDue reports whether the next attempt of the delivery is due.
+func (d *Delivery) IsDue(now time.Time) bool {
+	return d.Status == DeliveryStatusPending && d.State.IsDue(now)
+}
+
+// DeliveryAttempt is the record of a single HTTP request of a delivery.
+type DeliveryAttempt struct {
+	ID              string        `json:"id"`
+	DeliveryID      string        `json:"deliveryID"`
+	CreatedAt       time.Time     `json:"createdAt"`
+	StatusCode      *int          `json:"statusCode,omitempty"`
+	Latency         time.Duration `json:"-"`
+	ResponseExcerpt string        `json:"responseExcerpt"`
+	Error           string        `json:"error"`
+}
+
+func (a *DeliveryAttempt) IsSuccessful() bool {
+	return a.StatusCode != nil && *a.StatusCode >= 200 && *a.StatusCode < 300
+}
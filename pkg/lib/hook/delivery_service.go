@@ -0,0 +1,334 @@
+package hook
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/backoff"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
+	"github.com/authgear/authgear-server/pkg/util/log"
+	"github.com/authgear/authgear-server/pkg/util/uuid"
+)
+
+//go:generate mockgen -source=delivery_service.go -destination=delivery_service_mock_test.go -package hook
+
+// deliveryResumeLimit is the maximum number of due deliveries resumed in a run.
+const deliveryResumeLimit = 100
+
+type DeliveryServiceDatabase interface {
+	WithTx(do func() error) error
+}
+
+type DeliveryServiceStore interface {
+	GetDelivery(id string) (*Delivery, error)
+	GetDeliveryForUpdate(id string) (*Delivery, error)
+	GetDeliveriesByIDs(ids []string) ([]*Delivery, error)
+	ListDueDeliveries(now time.Time, limit uint64) ([]*Delivery, error)
+	CountDeliveries(opts DeliveryQueryPageOptions) (uint64, error)
+	QueryDeliveryPage(opts DeliveryQueryPageOptions, pageArgs graphqlutil.PageArgs) ([]*Delivery, uint64, error)
+	CreateDelivery(d *Delivery) error
+	UpdateDelivery(d *Delivery) error
+	CreateAttempt(a *DeliveryAttempt) error
+	ListAttempts(deliveryID string) ([]*DeliveryAttempt, error)
+}
+
+type DeliveryServiceLogger struct{ *log.Logger }
+
+func NewDeliveryServiceLogger(lf *log.Factory) DeliveryServiceLogger {
+	return DeliveryServiceLogger{lf.New("webhook-delivery")}
+}
+
+// DeliveryService persists non-blocking event deliveries and attempts them,
+// retrying failed deliveries with exponential back-off.
+//
+// The time of the next attempt is persisted with the delivery,
+// and the due deliveries are resumed by ResumeDue periodically,
+// so the retries survive restarts of the server.
+type DeliveryService struct {
+	Logger      DeliveryServiceLogger
+	Clock       clock.Clock
+	Secret      *config.WebhookKeyMaterials
+	AsyncHTTP   AsyncHTTPClient
+	Credentials *config.WebhookHandlerCredentials
+	Database    DeliveryServiceDatabase
+	Store       DeliveryServiceStore
+	TaskQueue   task.Queue
+}
+
+// Schedule persists a delivery of the event to the handler URL,
+// and enqueues its first attempt.
+func (s *DeliveryService) Schedule(e *event.Event, url string) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	now := s.Clock.NowUTC()
+	d := &Delivery{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		EventID:   e.ID,
+		EventType: string(e.Type),
+		URL:       url,
+		Payload:   payload,
+		Status:    DeliveryStatusPending,
+		State:     backoff.State{NextAttemptAt: &now},
+	}
+	err = s.Store.CreateDelivery(d)
+	if err != nil {
+		return err
+	}
+
+	s.TaskQueue.Enqueue(&tasks.DeliverWebhookParam{DeliveryID: d.ID})
+	return nil
+}
+
+// Attempt performs the delivery if it is due, and schedules a retry if the attempt failed.
+//
+// The delivery is claimed for DeliveryAttemptLease in a transaction,
+// so that the request is made without holding the lock of the delivery,
+// and the result is recorded in another transaction.
+func (s *DeliveryService) Attempt(id string) error {
+	var d *Delivery
+	err := s.Database.WithTx(func() (err error) {
+		d, err = s.claim(id)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		// The delivery is not due, or is being attempted concurrently.
+		return nil
+	}
+
+	attempt, retryAfter := s.perform(d)
+
+	return s.Database.WithTx(func() error {
+		return s.record(id, attempt, retryAfter)
+	})
+}
+
+// ResumeDue enqueues the attempts of the pending deliveries whose next attempt is due.
+func (s *DeliveryService) ResumeDue() error {
+	return s.Database.WithTx(func() error {
+		due, err := s.Store.ListDueDeliveries(s.Clock.NowUTC(), deliveryResumeLimit)
+		if err != nil {
+			return err
+		}
+
+		// Tasks are enqueued when the transaction is committed.
+		for _, d := range due {
+			s.TaskQueue.Enqueue(&tasks.DeliverWebhookParam{DeliveryID: d.ID})
+		}
+
+		return nil
+	})
+}
+
+// Redeliver schedules an immediate attempt of the delivery regardless of its status.
+func (s *DeliveryService) Redeliver(id string) (*Delivery, error) {
+	d, err := s.Store.GetDeliveryForUpdate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.Clock.NowUTC()
+	if d.IsClaimed(now) {
+		return nil, ErrDeliveryInProgress
+	}
+
+	d.Status = DeliveryStatusPending
+	d.UpdatedAt = now
+	d.NextAttemptAt = &now
+	err = s.Store.UpdateDelivery(d)
+	if err != nil {
+		return nil, err
+	}
+
+	s.TaskQueue.Enqueue(&tasks.DeliverWebhookParam{DeliveryID: d.ID})
+	return d, nil
+}
+
+func (s *DeliveryService) Get(id string) (*Delivery, error) {
+	return s.Store.GetDelivery(id)
+}
+
+func (s *DeliveryService) GetByIDs(ids []string) ([]*Delivery, error) {
+	return s.Store.GetDeliveriesByIDs(ids)
+}
+
+func (s *DeliveryService) ListAttempts(id string) ([]*DeliveryAttempt, error) {
+	return s.Store.ListAttempts(id)
+}
+
+func (s *DeliveryService) Count(opts DeliveryQueryPageOptions) (uint64, error) {
+	return s.Store.CountDeliveries(opts)
+}
+
+func (s *DeliveryService) QueryPage(opts DeliveryQueryPageOptions, pageArgs graphqlutil.PageArgs) ([]model.PageItemRef, error) {
+	deliveries, offset, err := s.Store.QueryDeliveryPage(opts, pageArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]model.PageItemRef, len(deliveries))
+	for i, d := range deliveries {
+		pageKey := db.PageKey{Offset: offset + uint64(i)}
+		cursor, err := pageKey.ToPageCursor()
+		if err != nil {
+			return nil, err
+		}
+
+		models[i] = model.PageItemRef{ID: d.ID, Cursor: cursor}
+	}
+
+	return models, nil
+}
+
+// claim returns the delivery claimed for an attempt,
+// or nil if the delivery is not due.
+func (s *DeliveryService) claim(id string) (*Delivery, error) {
+	d, err := s.Store.GetDeliveryForUpdate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.Clock.NowUTC()
+	if !d.IsDue(now) {
+		return nil, nil
+	}
+
+	d.UpdatedAt = now
+	d.Claim(now, DeliveryAttemptLease)
+	err = s.Store.UpdateDelivery(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// record records the attempt, and schedules a retry if the attempt failed.
+func (s *DeliveryService) record(id string, attempt *DeliveryAttempt, retryAfter *time.Time) error {
+	d, err := s.Store.GetDeliveryForUpdate(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.Store.CreateAttempt(attempt)
+	if err != nil {
+		return err
+	}
+
+	now := s.Clock.NowUTC()
+	d.UpdatedAt = now
+	d.LastAttemptedAt = &attempt.CreatedAt
+	if attempt.IsSuccessful() {
+		d.Status = DeliveryStatusSucceeded
+		d.Succeed()
+	} else {
+		next := d.Fail(now, DeliveryRetryBackoff)
+		if retryAfter != nil && retryAfter.After(next) {
+			next = *retryAfter
+		}
+
+		if next.After(d.CreatedAt.Add(DeliveryRetryPeriod)) {
+			d.Status = DeliveryStatusFailed
+			d.NextAttemptAt = nil
+			s.Logger.WithFields(map[string]interface{}{
+				"delivery_id":   d.ID,
+				"event_id":      d.EventID,
+				"event_type":    d.EventType,
+				"attempt_count": d.Attempts,
+				"error":         attempt.Error,
+			}).Error("webhook delivery failed permanently")
+		} else {
+			d.NextAttemptAt = &next
+		}
+	}
+
+	return s.Store.UpdateDelivery(d)
+}
+
+func (s *DeliveryService) perform(d *Delivery) (attempt *DeliveryAttempt, retryAfter *time.Time) {
+	now := s.Clock.NowUTC()
+	attempt = &DeliveryAttempt{
+		ID:         uuid.New(),
+		DeliveryID: d.ID,
+		CreatedAt:  now,
+	}
+
+	creds := lookupCredentials(s.Credentials, d.URL)
+	request, err := newRequest(s.Secret, creds, now, d.URL, d.Payload)
+	if err != nil {
+		attempt.Error = err.Error()
+		return
+	}
+
+	client, err := clientWithCredentials(s.AsyncHTTP.Client, creds)
+	if err != nil {
+		attempt.Error = err.Error()
+		return
+	}
+
+	start := s.Clock.NowMonotonic()
+	resp, err := client.Do(request)
+	attempt.Latency = s.Clock.NowMonotonic().Sub(start)
+	if reqError, ok := err.(net.Error); ok && reqError.Timeout() {
+		attempt.Error = "webhook delivery timeout"
+		return
+	} else if err != nil {
+		attempt.Error = err.Error()
+		return
+	}
+	defer resp.Body.Close()
+
+	statusCode := resp.StatusCode
+	attempt.StatusCode = &statusCode
+
+	excerpt, err := io.ReadAll(io.LimitReader(resp.Body, DeliveryResponseExcerptMaxLength))
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	// PostgreSQL text cannot contain NUL.
+	attempt.ResponseExcerpt = strings.ReplaceAll(strings.ToValidUTF8(string(excerpt), ""), "\x00", "")
+
+	if !attempt.IsSuccessful() {
+		attempt.Error = "invalid status code"
+		retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	}
+
+	return
+}
+
+func parseRetryAfter(value string, now time.Time) *time.Time {
+	if value == "" {
+		return nil
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		t := now.Add(time.Duration(seconds) * time.Second)
+		return &t
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		t = t.UTC()
+		return &t
+	}
+
+	return nil
+}
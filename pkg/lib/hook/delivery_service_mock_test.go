@@ -0,0 +1,221 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: delivery_service.go
+
+// Package hook is a generated GoMock package.
+package hook
+
+import (
+	reflect "reflect"
+	time "time"
+
+	graphqlutil "github.com/authgear/authgear-server/pkg/util/graphqlutil"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeliveryServiceDatabase is a mock of DeliveryServiceDatabase interface.
+type MockDeliveryServiceDatabase struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryServiceDatabaseMockRecorder
+}
+
+// MockDeliveryServiceDatabaseMockRecorder is the mock recorder for MockDeliveryServiceDatabase.
+type MockDeliveryServiceDatabaseMockRecorder struct {
+	mock *MockDeliveryServiceDatabase
+}
+
+// NewMockDeliveryServiceDatabase creates a new mock instance.
+func NewMockDeliveryServiceDatabase(ctrl *gomock.Controller) *MockDeliveryServiceDatabase {
+	mock := &MockDeliveryServiceDatabase{ctrl: ctrl}
+	mock.recorder = &MockDeliveryServiceDatabaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryServiceDatabase) EXPECT() *MockDeliveryServiceDatabaseMockRecorder {
+	return m.recorder
+}
+
+// WithTx mocks base method.
+func (m *MockDeliveryServiceDatabase) WithTx(do func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", do)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockDeliveryServiceDatabaseMockRecorder) WithTx(do interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockDeliveryServiceDatabase)(nil).WithTx), do)
+}
+
+// MockDeliveryServiceStore is a mock of DeliveryServiceStore interface.
+type MockDeliveryServiceStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryServiceStoreMockRecorder
+}
+
+// MockDeliveryServiceStoreMockRecorder is the mock recorder for MockDeliveryServiceStore.
+type MockDeliveryServiceStoreMockRecorder struct {
+	mock *MockDeliveryServiceStore
+}
+
+// NewMockDeliveryServiceStore creates a new mock instance.
+func NewMockDeliveryServiceStore(ctrl *gomock.Controller) *MockDeliveryServiceStore {
+	mock := &MockDeliveryServiceStore{ctrl: ctrl}
+	mock.recorder = &MockDeliveryServiceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryServiceStore) EXPECT() *MockDeliveryServiceStoreMockRecorder {
+	return m.recorder
+}
+
+// CountDeliveries mocks base method.
+func (m *MockDeliveryServiceStore) CountDeliveries(opts DeliveryQueryPageOptions) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDeliveries", opts)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDeliveries indicates an expected call of CountDeliveries.
+func (mr *MockDeliveryServiceStoreMockRecorder) CountDeliveries(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDeliveries", reflect.TypeOf((*MockDeliveryServiceStore)(nil).CountDeliveries), opts)
+}
+
+// CreateAttempt mocks base method.
+func (m *MockDeliveryServiceStore) CreateAttempt(a *DeliveryAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttempt", a)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAttempt indicates an expected call of CreateAttempt.
+func (mr *MockDeliveryServiceStoreMockRecorder) CreateAttempt(a interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttempt", reflect.TypeOf((*MockDeliveryServiceStore)(nil).CreateAttempt), a)
+}
+
+// CreateDelivery mocks base method.
+func (m *MockDeliveryServiceStore) CreateDelivery(d *Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelivery", d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDelivery indicates an expected call of CreateDelivery.
+func (mr *MockDeliveryServiceStoreMockRecorder) CreateDelivery(d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelivery", reflect.TypeOf((*MockDeliveryServiceStore)(nil).CreateDelivery), d)
+}
+
+// GetDeliveriesByIDs mocks base method.
+func (m *MockDeliveryServiceStore) GetDeliveriesByIDs(ids []string) ([]*Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveriesByIDs", ids)
+	ret0, _ := ret[0].([]*Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveriesByIDs indicates an expected call of GetDeliveriesByIDs.
+func (mr *MockDeliveryServiceStoreMockRecorder) GetDeliveriesByIDs(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveriesByIDs", reflect.TypeOf((*MockDeliveryServiceStore)(nil).GetDeliveriesByIDs), ids)
+}
+
+// GetDelivery mocks base method.
+func (m *MockDeliveryServiceStore) GetDelivery(id string) (*Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", id)
+	ret0, _ := ret[0].(*Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockDeliveryServiceStoreMockRecorder) GetDelivery(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockDeliveryServiceStore)(nil).GetDelivery), id)
+}
+
+// GetDeliveryForUpdate mocks base method.
+func (m *MockDeliveryServiceStore) GetDeliveryForUpdate(id string) (*Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryForUpdate", id)
+	ret0, _ := ret[0].(*Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryForUpdate indicates an expected call of GetDeliveryForUpdate.
+func (mr *MockDeliveryServiceStoreMockRecorder) GetDeliveryForUpdate(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryForUpdate", reflect.TypeOf((*MockDeliveryServiceStore)(nil).GetDeliveryForUpdate), id)
+}
+
+// ListAttempts mocks base method.
+func (m *MockDeliveryServiceStore) ListAttempts(deliveryID string) ([]*DeliveryAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttempts", deliveryID)
+	ret0, _ := ret[0].([]*DeliveryAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttempts indicates an expected call of ListAttempts.
+func (mr *MockDeliveryServiceStoreMockRecorder) ListAttempts(deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttempts", reflect.TypeOf((*MockDeliveryServiceStore)(nil).ListAttempts), deliveryID)
+}
+
+// ListDueDeliveries mocks base method.
+func (m *MockDeliveryServiceStore) ListDueDeliveries(now time.Time, limit uint64) ([]*Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueDeliveries", now, limit)
+	ret0, _ := ret[0].([]*Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueDeliveries indicates an expected call of ListDueDeliveries.
+func (mr *MockDeliveryServiceStoreMockRecorder) ListDueDeliveries(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueDeliveries", reflect.TypeOf((*MockDeliveryServiceStore)(nil).ListDueDeliveries), now, limit)
+}
+
+// QueryDeliveryPage mocks base method.
+func (m *MockDeliveryServiceStore) QueryDeliveryPage(opts DeliveryQueryPageOptions, pageArgs graphqlutil.PageArgs) ([]*Delivery, uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryDeliveryPage", opts, pageArgs)
+	ret0, _ := ret[0].([]*Delivery)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// QueryDeliveryPage indicates an expected call of QueryDeliveryPage.
+func (mr *MockDeliveryServiceStoreMockRecorder) QueryDeliveryPage(opts, pageArgs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryDeliveryPage", reflect.TypeOf((*MockDeliveryServiceStore)(nil).QueryDeliveryPage), opts, pageArgs)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockDeliveryServiceStore) UpdateDelivery(d *Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockDeliveryServiceStoreMockRecorder) UpdateDelivery(d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockDeliveryServiceStore)(nil).UpdateDelivery), d)
+}
@@ -0,0 +1,298 @@
+package hook
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lestrrat-go/jwx/jwk"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/webhook"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/backoff"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/log"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type mockTaskQueue struct {
+	params []task.Param
+}
+
+func (q *mockTaskQueue) Enqueue(param task.Param) {
+	q.params = append(q.params, param)
+}
+
+type mockDeliveryDatabase struct {
+	txs int
+}
+
+func (d *mockDeliveryDatabase) WithTx(do func() error) error {
+	d.txs++
+	return do()
+}
+
+func TestDeliveryService(t *testing.T) {
+	Convey("DeliveryService", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		key, err := jwk.New([]byte("aG9vay1zZWNyZXQ"))
+		So(err, ShouldBeNil)
+		set := jwk.NewSet()
+		_ = set.Add(key)
+		secret := &config.WebhookKeyMaterials{
+			Set: set,
+		}
+
+		clock := clock.NewMockClockAt("2006-01-02T15:04:05Z")
+		now := clock.NowUTC()
+
+		httpClient := &http.Client{}
+		gock.InterceptClient(httpClient)
+		defer gock.Off()
+
+		store := NewMockDeliveryServiceStore(ctrl)
+		queue := &mockTaskQueue{}
+		database := &mockDeliveryDatabase{}
+
+		s := &DeliveryService{
+			Logger:    DeliveryServiceLogger{log.Null},
+			Clock:     clock,
+			Secret:    secret,
+			AsyncHTTP: AsyncHTTPClient{httpClient},
+			Database:  database,
+			Store:     store,
+			TaskQueue: queue,
+		}
+
+		newDelivery := func() *Delivery {
+			return &Delivery{
+				ID:        "delivery-id",
+				CreatedAt: now,
+				UpdatedAt: now,
+				EventID:   "event-id",
+				EventType: "user.created",
+				URL:       "https://example.com/a",
+				Payload:   []byte(`{"id":"event-id"}`),
+				Status:    DeliveryStatusPending,
+				State:     backoff.State{NextAttemptAt: &now},
+			}
+		}
+
+		Convey("should persist delivery and enqueue the first attempt", func() {
+			e := &event.Event{
+				ID:            "event-id",
+				Type:          "user.created",
+				IsNonBlocking: true,
+			}
+
+			var created *Delivery
+			store.EXPECT().CreateDelivery(gomock.Any()).DoAndReturn(func(d *Delivery) error {
+				created = d
+				return nil
+			})
+
+			err := s.Schedule(e, "https://example.com/a")
+			So(err, ShouldBeNil)
+			So(created.EventID, ShouldEqual, "event-id")
+			So(created.URL, ShouldEqual, "https://example.com/a")
+			So(created.Status, ShouldEqual, DeliveryStatusPending)
+			So(queue.params, ShouldResemble, []task.Param{
+				&tasks.DeliverWebhookParam{DeliveryID: created.ID},
+			})
+		})
+
+		Convey("should mark successful delivery as succeeded", func() {
+			d := newDelivery()
+			claimedUntil := now.Add(DeliveryAttemptLease)
+			gomock.InOrder(
+				store.EXPECT().GetDeliveryForUpdate("delivery-id").Return(d, nil),
+				store.EXPECT().UpdateDelivery(d).DoAndReturn(func(d *Delivery) error {
+					// The delivery is claimed before the request.
+					So(d.ClaimedUntil, ShouldResemble, &claimedUntil)
+					So(d.Attempts, ShouldEqual, 0)
+					return nil
+				}),
+				store.EXPECT().GetDeliveryForUpdate("delivery-id").Return(d, nil),
+				store.EXPECT().CreateAttempt(gomock.Any()).DoAndReturn(func(a *DeliveryAttempt) error {
+					So(*a.StatusCode, ShouldEqual, 200)
+					So(a.ResponseExcerpt, ShouldEqual, "ok")
+					So(a.Error, ShouldEqual, "")
+					return nil
+				}),
+				store.EXPECT().UpdateDelivery(d).Return(nil),
+			)
+
+			gock.New("https://example.com").
+				Post("/a").
+				MatchHeader(HeaderRequestBodySignature, ".+").
+				MatchHeader(webhook.HeaderSignature, "^t=[0-9]+,v1=.+").
+				Reply(200).
+				BodyString("ok")
+			defer func() { gock.Flush() }()
+
+			err := s.Attempt("delivery-id")
+			So(err, ShouldBeNil)
+			So(gock.IsDone(), ShouldBeTrue)
+			So(d.Status, ShouldEqual, DeliveryStatusSucceeded)
+			So(d.Attempts, ShouldEqual, 1)
+			So(d.NextAttemptAt, ShouldBeNil)
+			So(d.ClaimedUntil, ShouldBeNil)
+			// The request is made between the claim and the record transactions.
+			So(database.txs, ShouldEqual, 2)
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should retry failed delivery with exponential back-off", func() {
+			d := newDelivery()
+			d.Attempts = 2
+			store.EXPECT().GetDeliveryForUpdate("delivery-id").Times(2).Return(d, nil)
+			store.EXPECT().CreateAttempt(gomock.Any()).DoAndReturn(func(a *DeliveryAttempt) error {
+				So(*a.StatusCode, ShouldEqual, 500)
+				So(a.Error, ShouldEqual, "invalid status code")
+				return nil
+			})
+			store.EXPECT().UpdateDelivery(d).Times(2).Return(nil)
+
+			gock.New("https://example.com").
+				Post("/a").
+				Reply(500)
+			defer func() { gock.Flush() }()
+
+			err := s.Attempt("delivery-id")
+			So(err, ShouldBeNil)
+			So(d.Status, ShouldEqual, DeliveryStatusPending)
+			So(d.Attempts, ShouldEqual, 3)
+			So(*d.NextAttemptAt, ShouldEqual, now.Add(4*time.Minute))
+			So(d.ClaimedUntil, ShouldBeNil)
+			// The retry is resumed by ResumeDue after it is due.
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should respect Retry-After", func() {
+			d := newDelivery()
+			store.EXPECT().GetDeliveryForUpdate("delivery-id").Times(2).Return(d, nil)
+			store.EXPECT().CreateAttempt(gomock.Any()).Return(nil)
+			store.EXPECT().UpdateDelivery(d).Times(2).Return(nil)
+
+			gock.New("https://example.com").
+				Post("/a").
+				Reply(503).
+				SetHeader("Retry-After", "3600")
+			defer func() { gock.Flush() }()
+
+			err := s.Attempt("delivery-id")
+			So(err, ShouldBeNil)
+			So(*d.NextAttemptAt, ShouldEqual, now.Add(1*time.Hour))
+		})
+
+		Convey("should fail permanently after retry period", func() {
+			d := newDelivery()
+			createdAt := now.Add(-DeliveryRetryPeriod)
+			d.CreatedAt = createdAt
+			d.Attempts = 20
+			store.EXPECT().GetDeliveryForUpdate("delivery-id").Times(2).Return(d, nil)
+			store.EXPECT().CreateAttempt(gomock.Any()).Return(nil)
+			store.EXPECT().UpdateDelivery(d).Times(2).Return(nil)
+
+			gock.New("https://example.com").
+				Post("/a").
+				Reply(500)
+			defer func() { gock.Flush() }()
+
+			err := s.Attempt("delivery-id")
+			So(err, ShouldBeNil)
+			So(d.Status, ShouldEqual, DeliveryStatusFailed)
+			So(d.NextAttemptAt, ShouldBeNil)
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should skip delivery that is not due", func() {
+			d := newDelivery()
+			next := now.Add(time.Minute)
+			d.NextAttemptAt = &next
+			store.EXPECT().GetDeliveryForUpdate("delivery-id").Return(d, nil)
+
+			err := s.Attempt("delivery-id")
+			So(err, ShouldBeNil)
+			So(d.Attempts, ShouldEqual, 0)
+		})
+
+		Convey("should skip delivery that is being attempted", func() {
+			d := newDelivery()
+			claimedUntil := now.Add(time.Minute)
+			d.ClaimedUntil = &claimedUntil
+			store.EXPECT().GetDeliveryForUpdate("delivery-id").Return(d, nil)
+
+			err := s.Attempt("delivery-id")
+			So(err, ShouldBeNil)
+			So(d.Attempts, ShouldEqual, 0)
+		})
+
+		Convey("should attempt delivery whose claim has expired", func() {
+			d := newDelivery()
+			claimedUntil := now.Add(-time.Second)
+			d.ClaimedUntil = &claimedUntil
+			store.EXPECT().GetDeliveryForUpdate("delivery-id").Times(2).Return(d, nil)
+			store.EXPECT().CreateAttempt(gomock.Any()).Return(nil)
+			store.EXPECT().UpdateDelivery(d).Times(2).Return(nil)
+
+			gock.New("https://example.com").
+				Post("/a").
+				Reply(200)
+			defer func() { gock.Flush() }()
+
+			err := s.Attempt("delivery-id")
+			So(err, ShouldBeNil)
+			So(d.Status, ShouldEqual, DeliveryStatusSucceeded)
+		})
+
+		Convey("should resume due deliveries", func() {
+			store.EXPECT().ListDueDeliveries(now, gomock.Any()).Return([]*Delivery{
+				{ID: "delivery-1"},
+				{ID: "delivery-2"},
+			}, nil)
+
+			err := s.ResumeDue()
+			So(err, ShouldBeNil)
+			So(queue.params, ShouldResemble, []task.Param{
+				&tasks.DeliverWebhookParam{DeliveryID: "delivery-1"},
+				&tasks.DeliverWebhookParam{DeliveryID: "delivery-2"},
+			})
+		})
+
+		Convey("should not redeliver delivery that is being attempted", func() {
+			d := newDelivery()
+			claimedUntil := now.Add(time.Minute)
+			d.ClaimedUntil = &claimedUntil
+			store.EXPECT().GetDeliveryForUpdate("delivery-id").Return(d, nil)
+
+			_, err := s.Redeliver("delivery-id")
+			So(err, ShouldBeError, ErrDeliveryInProgress)
+			So(queue.params, ShouldBeEmpty)
+		})
+
+		Convey("should redeliver failed delivery", func() {
+			d := newDelivery()
+			d.Status = DeliveryStatusFailed
+			d.NextAttemptAt = nil
+			store.EXPECT().GetDeliveryForUpdate("delivery-id").Return(d, nil)
+			store.EXPECT().UpdateDelivery(d).Return(nil)
+
+			d, err := s.Redeliver("delivery-id")
+			So(err, ShouldBeNil)
+			So(d.Status, ShouldEqual, DeliveryStatusPending)
+			So(*d.NextAttemptAt, ShouldEqual, now)
+			So(queue.params, ShouldResemble, []task.Param{
+				&tasks.DeliverWebhookParam{DeliveryID: "delivery-id"},
+			})
+		})
+	})
+}
@@ -0,0 +1,315 @@
+package hook
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/db"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
+	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
+)
+
+type DeliveryQueryPageOptions struct {
+	Statuses []DeliveryStatus
+}
+
+func (o DeliveryQueryPageOptions) Apply(q db.SelectBuilder) db.SelectBuilder {
+	if len(o.Statuses) > 0 {
+		statuses := make([]string, len(o.Statuses))
+		for i, s := range o.Statuses {
+			statuses[i] = string(s)
+		}
+		q = q.Where("status = ANY (?)", pq.Array(statuses))
+	}
+
+	return q
+}
+
+type DeliveryStore struct {
+	SQLBuilder  *appdb.SQLBuilderApp
+	SQLExecutor *appdb.SQLExecutor
+}
+
+func (s *DeliveryStore) selectDeliveryQuery() db.SelectBuilder {
+	return s.SQLBuilder.
+		Select(
+			"id",
+			"created_at",
+			"updated_at",
+			"event_id",
+			"event_type",
+			"url",
+			"payload",
+			"status",
+			"attempt_count",
+			"next_attempt_at",
+			"last_attempted_at",
+			"claimed_until",
+		).
+		From(s.SQLBuilder.TableName("_auth_webhook_delivery"))
+}
+
+func (s *DeliveryStore) scanDelivery(scn db.Scanner) (*Delivery, error) {
+	d := &Delivery{}
+
+	var status string
+	err := scn.Scan(
+		&d.ID,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+		&d.EventID,
+		&d.EventType,
+		&d.URL,
+		&d.Payload,
+		&status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.LastAttemptedAt,
+		&d.ClaimedUntil,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDeliveryNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	d.Status = DeliveryStatus(status)
+
+	return d, nil
+}
+
+func (s *DeliveryStore) queryDeliveries(q db.SelectBuilder) ([]*Delivery, error) {
+	rows, err := s.SQLExecutor.QueryWith(q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*Delivery
+	for rows.Next() {
+		d, err := s.scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, nil
+}
+
+func (s *DeliveryStore) GetDelivery(id string) (*Delivery, error) {
+	q := s.selectDeliveryQuery().Where("id = ?", id)
+
+	scanner, err := s.SQLExecutor.QueryRowWith(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scanDelivery(scanner)
+}
+
+// GetDeliveryForUpdate locks the delivery until the end of the transaction.
+func (s *DeliveryStore) GetDeliveryForUpdate(id string) (*Delivery, error) {
+	q := s.selectDeliveryQuery().
+		Where("id = ?", id).
+		Suffix("FOR UPDATE")
+
+	scanner, err := s.SQLExecutor.QueryRowWith(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scanDelivery(scanner)
+}
+
+func (s *DeliveryStore) GetDeliveriesByIDs(ids []string) ([]*Delivery, error) {
+	q := s.selectDeliveryQuery().Where("id = ANY (?)", pq.Array(ids))
+	return s.queryDeliveries(q)
+}
+
+// ListDueDeliveries lists the pending deliveries whose next attempt is due at now.
+func (s *DeliveryStore) ListDueDeliveries(now time.Time, limit uint64) ([]*Delivery, error) {
+	q := s.selectDeliveryQuery().
+		Where("status = ? AND next_attempt_at <= ?", string(DeliveryStatusPending), now).
+		Where("(claimed_until IS NULL OR claimed_until <= ?)", now).
+		OrderBy("next_attempt_at ASC").
+		Limit(limit)
+	return s.queryDeliveries(q)
+}
+
+func (s *DeliveryStore) CountDeliveries(opts DeliveryQueryPageOptions) (uint64, error) {
+	q := s.SQLBuilder.
+		Select("count(*)").
+		From(s.SQLBuilder.TableName("_auth_webhook_delivery"))
+
+	q = opts.Apply(q)
+
+	scanner, err := s.SQLExecutor.QueryRowWith(q)
+	if err != nil {
+		return 0, err
+	}
+
+	var count uint64
+	err = scanner.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (s *DeliveryStore) QueryDeliveryPage(opts DeliveryQueryPageOptions, pageArgs graphqlutil.PageArgs) ([]*Delivery, uint64, error) {
+	q := s.selectDeliveryQuery()
+
+	q = opts.Apply(q)
+
+	q = q.OrderBy("created_at DESC")
+
+	q, offset, err := db.ApplyPageArgs(q, pageArgs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, err := s.queryDeliveries(q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, offset, nil
+}
+
+func (s *DeliveryStore) CreateDelivery(d *Delivery) error {
+	q := s.SQLBuilder.
+		Insert(s.SQLBuilder.TableName("_auth_webhook_delivery")).
+		Columns(
+			"id",
+			"created_at",
+			"updated_at",
+			"event_id",
+			"event_type",
+			"url",
+			"payload",
+			"status",
+			"attempt_count",
+			"next_attempt_at",
+			"last_attempted_at",
+			"claimed_until",
+		).
+		Values(
+			d.ID,
+			d.CreatedAt,
+			d.UpdatedAt,
+			d.EventID,
+			d.EventType,
+			d.URL,
+			d.Payload,
+			string(d.Status),
+			d.Attempts,
+			d.NextAttemptAt,
+			d.LastAttemptedAt,
+			d.ClaimedUntil,
+		)
+
+	_, err := s.SQLExecutor.ExecWith(q)
+	return err
+}
+
+func (s *DeliveryStore) UpdateDelivery(d *Delivery) error {
+	q := s.SQLBuilder.
+		Update(s.SQLBuilder.TableName("_auth_webhook_delivery")).
+		Set("updated_at", d.UpdatedAt).
+		Set("status", string(d.Status)).
+		Set("attempt_count", d.Attempts).
+		Set("next_attempt_at", d.NextAttemptAt).
+		Set("last_attempted_at", d.LastAttemptedAt).
+		Set("claimed_until", d.ClaimedUntil).
+		Where("id = ?", d.ID)
+
+	result, err := s.SQLExecutor.ExecWith(q)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+func (s *DeliveryStore) CreateAttempt(a *DeliveryAttempt) error {
+	q := s.SQLBuilder.
+		Insert(s.SQLBuilder.TableName("_auth_webhook_delivery_attempt")).
+		Columns(
+			"id",
+			"delivery_id",
+			"created_at",
+			"status_code",
+			"latency_ms",
+			"response_excerpt",
+			"error",
+		).
+		Values(
+			a.ID,
+			a.DeliveryID,
+			a.CreatedAt,
+			a.StatusCode,
+			a.Latency.Milliseconds(),
+			a.ResponseExcerpt,
+			a.Error,
+		)
+
+	_, err := s.SQLExecutor.ExecWith(q)
+	return err
+}
+
+func (s *DeliveryStore) ListAttempts(deliveryID string) ([]*DeliveryAttempt, error) {
+	q := s.SQLBuilder.
+		Select(
+			"id",
+			"delivery_id",
+			"created_at",
+			"status_code",
+			"latency_ms",
+			"response_excerpt",
+			"error",
+		).
+		From(s.SQLBuilder.TableName("_auth_webhook_delivery_attempt")).
+		Where("delivery_id = ?", deliveryID).
+		OrderBy("created_at ASC")
+
+	rows, err := s.SQLExecutor.QueryWith(q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []*DeliveryAttempt
+	for rows.Next() {
+		a := &DeliveryAttempt{}
+		var latencyMS int64
+		err := rows.Scan(
+			&a.ID,
+			&a.DeliveryID,
+			&a.CreatedAt,
+			&a.StatusCode,
+			&latencyMS,
+			&a.ResponseExcerpt,
+			&a.Error,
+		)
+		if err != nil {
+			return nil, err
+		}
+		a.Latency = time.Duration(latencyMS) * time.Millisecond
+		attempts = append(attempts, a)
+	}
+
+	return attempts, nil
+}
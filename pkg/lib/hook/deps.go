@@ -8,6 +8,11 @@ var DependencySet = wire.NewSet(
 	NewSyncHTTPClient,
 	NewAsyncHTTPClient,
 	NewLogger,
+	NewDeliveryServiceLogger,
+	wire.Struct(new(DeliveryStore), "*"),
+	wire.Struct(new(DeliveryService), "*"),
+	wire.Bind(new(DeliveryServiceStore), new(*DeliveryStore)),
+	wire.Bind(new(DeliveryScheduler), new(*DeliveryService)),
 	wire.Struct(new(Deliverer), "*"),
 	wire.Bind(new(deliverer), new(*Deliverer)),
 	wire.Struct(new(Sink), "*"),
@@ -9,6 +9,9 @@ var WebHookDisallowed = apierrors.Forbidden.WithReason("WebHookDisallowed")
 var WebHookDeliveryTimeout = apierrors.InternalError.WithReason("WebHookDeliveryTimeout")
 var WebHookInvalidResponse = apierrors.InternalError.WithReason("WebHookInvalidResponse")
 
+var ErrDeliveryNotFound = apierrors.NotFound.WithReason("WebHookDeliveryNotFound").New("webhook delivery not found")
+var ErrDeliveryInProgress = apierrors.Invalid.WithReason("WebHookDeliveryInProgress").New("webhook delivery is in progress")
+
 type OperationDisallowedItem struct {
 	Title  string `json:"title"`
 	Reason string `json:"reason"`
@@ -0,0 +1,95 @@
+package hook
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/iawaknahc/jsonschema/pkg/jsonpointer"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+)
+
+// matchFilters reports whether e matches all filters.
+// A filter is not matched if its pointer does not refer to any value of e.
+func matchFilters(filters []config.HookHandlerFilterConfig, e *event.Event) (bool, error) {
+	if len(filters) == 0 {
+		return true, nil
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return false, fmt.Errorf("webhook: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return false, fmt.Errorf("webhook: %w", err)
+	}
+
+	for _, filter := range filters {
+		ptr, err := jsonpointer.Parse(filter.Pointer)
+		if err != nil {
+			return false, fmt.Errorf("webhook: %w", err)
+		}
+
+		value, err := ptr.Traverse(doc)
+		if err != nil {
+			return false, nil
+		}
+
+		matched := false
+		for _, v := range filter.Values {
+			if reflect.DeepEqual(value, v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func lookupCredentials(creds *config.WebhookHandlerCredentials, url string) *config.WebhookHandlerCredentialsItem {
+	if creds == nil {
+		return nil
+	}
+	item, ok := creds.Lookup(url)
+	if !ok {
+		return nil
+	}
+	return item
+}
+
+// clientWithCredentials returns a client presenting the TLS client certificate of creds.
+// client is returned as is if no TLS client certificate is configured.
+func clientWithCredentials(client *http.Client, creds *config.WebhookHandlerCredentialsItem) (*http.Client, error) {
+	if creds == nil || creds.TLSClientCertificate == "" {
+		return client, nil
+	}
+
+	cert, err := tls.X509KeyPair([]byte(creds.TLSClientCertificate), []byte(creds.TLSClientKey))
+	if err != nil {
+		return nil, fmt.Errorf("webhook: invalid TLS client certificate: %w", err)
+	}
+
+	var transport *http.Transport
+	if t, ok := client.Transport.(*http.Transport); ok {
+		transport = t.Clone()
+	} else {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+
+	c := *client
+	c.Transport = transport
+	return &c, nil
+}
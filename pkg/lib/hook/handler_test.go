@@ -0,0 +1,134 @@
+package hook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMatchFilters(t *testing.T) {
+	Convey("matchFilters", t, func() {
+		e := &event.Event{
+			Type:    MockNonBlockingEventType1,
+			Payload: &MockNonBlockingEvent1{},
+			Context: event.Context{ClientID: "client-a"},
+		}
+
+		test := func(filters []config.HookHandlerFilterConfig, expected bool) {
+			matched, err := matchFilters(filters, e)
+			So(err, ShouldBeNil)
+			So(matched, ShouldEqual, expected)
+		}
+
+		test(nil, true)
+		test([]config.HookHandlerFilterConfig{
+			{Pointer: "/context/client_id", Values: []interface{}{"client-a"}},
+		}, true)
+		test([]config.HookHandlerFilterConfig{
+			{Pointer: "/context/client_id", Values: []interface{}{"client-b", "client-a"}},
+		}, true)
+		test([]config.HookHandlerFilterConfig{
+			{Pointer: "/context/client_id", Values: []interface{}{"client-b"}},
+		}, false)
+		test([]config.HookHandlerFilterConfig{
+			{Pointer: "/context/client_id", Values: []interface{}{"client-a"}},
+			{Pointer: "/type", Values: []interface{}{string(MockNonBlockingEventType2)}},
+		}, false)
+		test([]config.HookHandlerFilterConfig{
+			{Pointer: "/context/timestamp", Values: []interface{}{float64(0)}},
+		}, true)
+		test([]config.HookHandlerFilterConfig{
+			{Pointer: "/context/user_id", Values: []interface{}{nil}},
+		}, true)
+		test([]config.HookHandlerFilterConfig{
+			{Pointer: "/payload/identity/type", Values: []interface{}{"login_id"}},
+		}, false)
+	})
+}
+
+func TestClientWithCredentials(t *testing.T) {
+	Convey("clientWithCredentials", t, func() {
+		certPEM, keyPEM := newTestCertificate()
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(200)
+		}))
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+		server.StartTLS()
+		defer server.Close()
+
+		client := server.Client()
+
+		Convey("should return the client as is without certificate", func() {
+			c, err := clientWithCredentials(client, &config.WebhookHandlerCredentialsItem{})
+			So(err, ShouldBeNil)
+			So(c, ShouldEqual, client)
+
+			_, err = c.Get(server.URL)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should present client certificate", func() {
+			c, err := clientWithCredentials(client, &config.WebhookHandlerCredentialsItem{
+				TLSClientCertificate: certPEM,
+				TLSClientKey:         keyPEM,
+			})
+			So(err, ShouldBeNil)
+
+			resp, err := c.Get(server.URL)
+			So(err, ShouldBeNil)
+			defer resp.Body.Close()
+			So(resp.StatusCode, ShouldEqual, 200)
+		})
+
+		Convey("should reject invalid certificate", func() {
+			_, err := clientWithCredentials(client, &config.WebhookHandlerCredentialsItem{
+				TLSClientCertificate: certPEM,
+				TLSClientKey:         "invalid",
+			})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func newTestCertificate() (certPEM string, keyPEM string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "authgear"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return
+}
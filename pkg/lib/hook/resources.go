@@ -0,0 +1,103 @@
+package hook
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/util/resource"
+)
+
+type ResourceManager interface {
+	Read(desc resource.Descriptor, view resource.View) (interface{}, error)
+}
+
+const hookScriptDir = "hooks"
+
+// HookScriptDescriptor describes the hook scripts at hooks/<name>.js.
+// Only EffectiveFileView, AppFileView and ValidateResourceView are supported.
+type HookScriptDescriptor struct{}
+
+var _ resource.Descriptor = HookScriptDescriptor{}
+
+func (d HookScriptDescriptor) MatchResource(p string) (*resource.Match, bool) {
+	if config.IsHookScriptPath(p) {
+		return &resource.Match{}, true
+	}
+	return nil, false
+}
+
+func (d HookScriptDescriptor) FindResources(fs resource.Fs) ([]resource.Location, error) {
+	dir, err := fs.Open(hookScriptDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(0)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []resource.Location
+	for _, name := range names {
+		p := path.Join(hookScriptDir, name)
+		if !config.IsHookScriptPath(p) {
+			continue
+		}
+		locations = append(locations, resource.Location{
+			Fs:   fs,
+			Path: p,
+		})
+	}
+
+	return locations, nil
+}
+
+func (d HookScriptDescriptor) ViewResources(resources []resource.ResourceFile, rawView resource.View) (interface{}, error) {
+	switch view := rawView.(type) {
+	case resource.AppFileView:
+		var appResources []resource.ResourceFile
+		for _, resrc := range resources {
+			if resrc.Location.Fs.GetFsLevel() == resource.FsLevelApp {
+				appResources = append(appResources, resrc)
+			}
+		}
+		return d.viewByPath(appResources, view.AppFilePath())
+	case resource.EffectiveFileView:
+		return d.viewByPath(resources, view.EffectiveFilePath())
+	case resource.ValidateResourceView:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported view: %T", rawView)
+	}
+}
+
+func (d HookScriptDescriptor) viewByPath(resources []resource.ResourceFile, p string) (interface{}, error) {
+	var found bool
+	var bytes []byte
+	for _, resrc := range resources {
+		if resrc.Location.Path == p {
+			found = true
+			bytes = resrc.Data
+		}
+	}
+
+	if !found {
+		return nil, resource.ErrResourceNotFound
+	}
+	return bytes, nil
+}
+
+func (d HookScriptDescriptor) UpdateResource(_ context.Context, _ []resource.ResourceFile, resrc *resource.ResourceFile, data []byte) (*resource.ResourceFile, error) {
+	return &resource.ResourceFile{
+		Location: resrc.Location,
+		Data:     data,
+	}, nil
+}
+
+var HookScript = resource.RegisterResource(HookScriptDescriptor{})
@@ -0,0 +1,328 @@
+package hook
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/blocking"
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+)
+
+var ErrUnknownEventType = apierrors.Invalid.WithReason("WebHookUnknownEventType")
+
+const (
+	sampleEventID         = "0000000000000000"
+	sampleUserID          = "00000000-0000-0000-0000-000000000001"
+	sampleIdentityID      = "00000000-0000-0000-0000-000000000002"
+	sampleAuthenticatorID = "00000000-0000-0000-0000-000000000003"
+	sampleSessionID       = "00000000-0000-0000-0000-000000000004"
+	sampleClientID        = "sample-client"
+	sampleEmail           = "user@example.com"
+)
+
+type samplePayloadFactory func(now time.Time) event.Payload
+
+var sampleBlockingPayloads = map[event.Type]samplePayloadFactory{
+	blocking.UserPreCreate: func(now time.Time) event.Payload {
+		return &blocking.UserPreCreateBlockingEventPayload{
+			UserModel:  sampleUser(now),
+			Identities: []model.Identity{sampleIdentity(now)},
+		}
+	},
+	blocking.UserProfilePreUpdate: func(now time.Time) event.Payload {
+		return &blocking.UserProfilePreUpdateBlockingEventPayload{
+			UserModel: sampleUser(now),
+		}
+	},
+	blocking.UserPreAuthenticate: func(now time.Time) event.Payload {
+		return &blocking.UserPreAuthenticateBlockingEventPayload{
+			UserModel: sampleUser(now),
+			AMR:       []string{"pwd"},
+		}
+	},
+	blocking.IdentityPreCreate: func(now time.Time) event.Payload {
+		return &blocking.IdentityPreCreateBlockingEventPayload{
+			UserModel: sampleUser(now),
+			Identity:  sampleIdentity(now),
+		}
+	},
+	blocking.IdentityPreDelete: func(now time.Time) event.Payload {
+		return &blocking.IdentityPreDeleteBlockingEventPayload{
+			UserModel: sampleUser(now),
+			Identity:  sampleIdentity(now),
+		}
+	},
+	blocking.OAuthPreTokenIssue: func(now time.Time) event.Payload {
+		user := sampleUser(now)
+		return &blocking.OAuthPreTokenIssueBlockingEventPayload{
+			UserModel: &user,
+			ClientID:  sampleClientID,
+			GrantType: "authorization_code",
+			Scopes:    []string{"openid", "offline_access"},
+		}
+	},
+	blocking.OIDCJWTPreCreate: func(now time.Time) event.Payload {
+		return &blocking.OIDCJWTPreCreateBlockingEventPayload{
+			UserModel: sampleUser(now),
+			ClientID:  sampleClientID,
+			Scopes:    []string{"openid", "offline_access"},
+			JWT: blocking.OIDCJWT{
+				Payload: map[string]interface{}{
+					"sub": sampleUserID,
+				},
+			},
+		}
+	},
+}
+
+var sampleNonBlockingPayloads = map[event.Type]samplePayloadFactory{
+	nonblocking.UserCreated: func(now time.Time) event.Payload {
+		return &nonblocking.UserCreatedEventPayload{
+			UserModel:  sampleUser(now),
+			Identities: []model.Identity{sampleIdentity(now)},
+		}
+	},
+	nonblocking.UserAuthenticated: func(now time.Time) event.Payload {
+		return &nonblocking.UserAuthenticatedEventPayload{
+			UserModel: sampleUser(now),
+			Session:   sampleSession(now),
+		}
+	},
+	nonblocking.UserProfileUpdated: func(now time.Time) event.Payload {
+		return &nonblocking.UserProfileUpdatedEventPayload{
+			UserModel: sampleUser(now),
+		}
+	},
+	nonblocking.UserAnonymousPromoted: func(now time.Time) event.Payload {
+		anonymousUser := sampleUser(now)
+		anonymousUser.IsAnonymous = true
+		anonymousUser.StandardAttributes = nil
+		return &nonblocking.UserAnonymousPromotedEventPayload{
+			AnonymousUserModel: anonymousUser,
+			UserModel:          sampleUser(now),
+			Identities:         []model.Identity{sampleIdentity(now)},
+		}
+	},
+	nonblocking.IdentityOAuthConnected: func(now time.Time) event.Payload {
+		return &nonblocking.IdentityOAuthConnectedEventPayload{
+			UserModel: sampleUser(now),
+			Identity:  sampleOAuthIdentity(now),
+		}
+	},
+	nonblocking.IdentityOAuthDisconnected: func(now time.Time) event.Payload {
+		return &nonblocking.IdentityOAuthDisconnectedEventPayload{
+			UserModel: sampleUser(now),
+			Identity:  sampleOAuthIdentity(now),
+		}
+	},
+	nonblocking.UserPasswordChanged: func(now time.Time) event.Payload {
+		return &nonblocking.UserPasswordChangedEventPayload{
+			UserModel: sampleUser(now),
+		}
+	},
+	nonblocking.UserPasswordReset: func(now time.Time) event.Payload {
+		return &nonblocking.UserPasswordResetEventPayload{
+			UserModel: sampleUser(now),
+		}
+	},
+	nonblocking.UserRecoveryCodesRegenerated: func(now time.Time) event.Payload {
+		return &nonblocking.UserRecoveryCodesRegeneratedEventPayload{
+			UserModel: sampleUser(now),
+		}
+	},
+	nonblocking.UserDisabled: func(now time.Time) event.Payload {
+		user := sampleUser(now)
+		user.IsDisabled = true
+		return &nonblocking.UserDisabledEventPayload{
+			UserModel: user,
+		}
+	},
+	nonblocking.UserReenabled: func(now time.Time) event.Payload {
+		return &nonblocking.UserReenabledEventPayload{
+			UserModel: sampleUser(now),
+		}
+	},
+	nonblocking.UserDeleted: func(now time.Time) event.Payload {
+		return &nonblocking.UserDeletedEventPayload{
+			UserModel: sampleUser(now),
+		}
+	},
+	nonblocking.AuthenticatorCreated: func(now time.Time) event.Payload {
+		return &nonblocking.AuthenticatorCreatedEventPayload{
+			UserModel:     sampleUser(now),
+			Authenticator: sampleAuthenticator(now),
+		}
+	},
+	nonblocking.AuthenticatorDeleted: func(now time.Time) event.Payload {
+		return &nonblocking.AuthenticatorDeletedEventPayload{
+			UserModel:     sampleUser(now),
+			Authenticator: sampleAuthenticator(now),
+		}
+	},
+	nonblocking.IdentityVerified: func(now time.Time) event.Payload {
+		return &nonblocking.IdentityVerifiedEventPayload{
+			UserModel: sampleUser(now),
+			Identity:  sampleIdentity(now),
+		}
+	},
+	nonblocking.SessionCreated: func(now time.Time) event.Payload {
+		return &nonblocking.SessionCreatedEventPayload{
+			UserModel: sampleUser(now),
+			Session:   sampleSession(now),
+		}
+	},
+	nonblocking.SessionRevoked: func(now time.Time) event.Payload {
+		return &nonblocking.SessionRevokedEventPayload{
+			UserModel: sampleUser(now),
+			Session:   sampleSession(now),
+		}
+	},
+}
+
+func init() {
+	loginIDTypes := []config.LoginIDKeyType{
+		config.LoginIDKeyTypeEmail,
+		config.LoginIDKeyTypePhone,
+		config.LoginIDKeyTypeUsername,
+	}
+	for _, t := range loginIDTypes {
+		loginIDType := string(t)
+		sampleNonBlockingPayloads[event.Type(fmt.Sprintf(nonblocking.IdentityLoginIDAddedFormat, loginIDType))] = func(now time.Time) event.Payload {
+			return &nonblocking.IdentityLoginIDAddedEventPayload{
+				UserModel:   sampleUser(now),
+				Identity:    sampleLoginIDIdentity(now, loginIDType),
+				LoginIDType: loginIDType,
+			}
+		}
+		sampleNonBlockingPayloads[event.Type(fmt.Sprintf(nonblocking.IdentityLoginIDRemovedFormat, loginIDType))] = func(now time.Time) event.Payload {
+			return &nonblocking.IdentityLoginIDRemovedEventPayload{
+				UserModel:   sampleUser(now),
+				Identity:    sampleLoginIDIdentity(now, loginIDType),
+				LoginIDType: loginIDType,
+			}
+		}
+		sampleNonBlockingPayloads[event.Type(fmt.Sprintf(nonblocking.IdentityLoginIDUpdatedFormat, loginIDType))] = func(now time.Time) event.Payload {
+			return &nonblocking.IdentityLoginIDUpdatedEventPayload{
+				UserModel:   sampleUser(now),
+				NewIdentity: sampleLoginIDIdentity(now, loginIDType),
+				OldIdentity: sampleLoginIDIdentity(now, loginIDType),
+				LoginIDType: loginIDType,
+			}
+		}
+	}
+}
+
+// NewSampleEvent builds an event of eventType with sample data,
+// so that hook handlers can be tested without triggering the actual event.
+func NewSampleEvent(eventType event.Type, now time.Time) (*event.Event, error) {
+	var payload event.Payload
+	isNonBlocking := false
+	if f, ok := sampleBlockingPayloads[eventType]; ok {
+		payload = f(now)
+	} else if f, ok := sampleNonBlockingPayloads[eventType]; ok {
+		payload = f(now)
+		isNonBlocking = true
+	} else {
+		return nil, ErrUnknownEventType.NewWithInfo("unknown event type", apierrors.Details{
+			"event_type": eventType,
+		})
+	}
+
+	userID := sampleUserID
+	e := &event.Event{
+		ID:      sampleEventID,
+		Type:    eventType,
+		Payload: payload,
+		Context: event.Context{
+			Timestamp:          now.Unix(),
+			UserID:             &userID,
+			TriggeredBy:        event.TriggeredByTypeUser,
+			PreferredLanguages: []string{},
+			Language:           "en",
+		},
+		IsNonBlocking: isNonBlocking,
+	}
+	return e, nil
+}
+
+func sampleMeta(id string, now time.Time) model.Meta {
+	return model.Meta{
+		ID:        id,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func sampleUser(now time.Time) model.User {
+	return model.User{
+		Meta:       sampleMeta(sampleUserID, now),
+		IsVerified: true,
+		StandardAttributes: map[string]interface{}{
+			"email":          sampleEmail,
+			"email_verified": true,
+		},
+	}
+}
+
+func sampleIdentity(now time.Time) model.Identity {
+	return sampleLoginIDIdentity(now, string(config.LoginIDKeyTypeEmail))
+}
+
+func sampleLoginIDIdentity(now time.Time, loginIDType string) model.Identity {
+	var value string
+	switch config.LoginIDKeyType(loginIDType) {
+	case config.LoginIDKeyTypePhone:
+		value = "+85298765432"
+	case config.LoginIDKeyTypeUsername:
+		value = "user"
+	default:
+		value = sampleEmail
+	}
+	return model.Identity{
+		Meta: sampleMeta(sampleIdentityID, now),
+		Type: string(model.IdentityTypeLoginID),
+		Claims: map[string]interface{}{
+			identity.IdentityClaimLoginIDKey:           loginIDType,
+			identity.IdentityClaimLoginIDType:          loginIDType,
+			identity.IdentityClaimLoginIDOriginalValue: value,
+			identity.IdentityClaimLoginIDValue:         value,
+		},
+	}
+}
+
+func sampleOAuthIdentity(now time.Time) model.Identity {
+	return model.Identity{
+		Meta: sampleMeta(sampleIdentityID, now),
+		Type: string(model.IdentityTypeOAuth),
+		Claims: map[string]interface{}{
+			identity.IdentityClaimOAuthProviderType: "google",
+			identity.IdentityClaimOAuthSubjectID:    "sample-subject",
+			"email":                                 sampleEmail,
+		},
+	}
+}
+
+func sampleAuthenticator(now time.Time) model.Authenticator {
+	return model.Authenticator{
+		Meta:      sampleMeta(sampleAuthenticatorID, now),
+		UserID:    sampleUserID,
+		Type:      model.AuthenticatorTypePassword,
+		IsDefault: false,
+		Kind:      "primary",
+		Claims:    map[string]interface{}{},
+	}
+}
+
+func sampleSession(now time.Time) model.Session {
+	return model.Session{
+		Meta:           sampleMeta(sampleSessionID, now),
+		Type:           model.SessionTypeIDP,
+		AMR:            []string{"pwd"},
+		LastAccessedAt: now,
+	}
+}
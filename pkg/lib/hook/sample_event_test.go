@@ -0,0 +1,63 @@
+package hook
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewSampleEvent(t *testing.T) {
+	Convey("NewSampleEvent", t, func() {
+		now := time.Date(2022, 1, 24, 0, 0, 0, 0, time.UTC)
+
+		Convey("should build events of all types supported by hook handlers", func() {
+			type handlersSchema struct {
+				Properties map[string]struct {
+					Enum  []string `json:"enum"`
+					Items struct {
+						Enum []string `json:"enum"`
+					} `json:"items"`
+				} `json:"properties"`
+			}
+			var schema struct {
+				Defs map[string]handlersSchema `json:"$defs"`
+			}
+			schemaString, err := config.Schema.DumpSchemaString(false)
+			So(err, ShouldBeNil)
+			So(json.Unmarshal([]byte(schemaString), &schema), ShouldBeNil)
+			blockingHandlers := schema.Defs["BlockingHookHandlersConfig"]
+			nonBlockingHandlers := schema.Defs["NonBlockingHookHandlersConfig"]
+			So(blockingHandlers.Properties["event"].Enum, ShouldNotBeEmpty)
+			So(nonBlockingHandlers.Properties["events"].Items.Enum, ShouldNotBeEmpty)
+
+			for _, t := range blockingHandlers.Properties["event"].Enum {
+				e, err := NewSampleEvent(event.Type(t), now)
+				So(err, ShouldBeNil)
+				So(e.Type, ShouldEqual, event.Type(t))
+				So(e.IsNonBlocking, ShouldBeFalse)
+				So(e.Payload.(event.BlockingPayload).BlockingEventType(), ShouldEqual, event.Type(t))
+			}
+
+			for _, t := range nonBlockingHandlers.Properties["events"].Items.Enum {
+				if t == "*" {
+					continue
+				}
+				e, err := NewSampleEvent(event.Type(t), now)
+				So(err, ShouldBeNil)
+				So(e.Type, ShouldEqual, event.Type(t))
+				So(e.IsNonBlocking, ShouldBeTrue)
+				So(e.Payload.(event.NonBlockingPayload).NonBlockingEventType(), ShouldEqual, event.Type(t))
+			}
+		})
+
+		Convey("should reject unknown event type", func() {
+			_, err := NewSampleEvent("unknown", now)
+			So(err, ShouldBeError, "unknown event type")
+		})
+	})
+}
@@ -39,6 +39,9 @@ func (s *Sink) ReceiveBlockingEvent(e *event.Event) (err error) {
 }
 
 func (s *Sink) ReceiveNonBlockingEvent(e *event.Event) (err error) {
+	// Non-blocking events are persisted as deliveries here,
+	// and delivered asynchronously afterwards.
+
 	// Skip events that are not for webhook.
 	payload := e.Payload.(event.NonBlockingPayload)
 	if !payload.ForWebHook() {
@@ -46,8 +49,10 @@ func (s *Sink) ReceiveNonBlockingEvent(e *event.Event) (err error) {
 	}
 
 	if s.Deliverer.WillDeliverNonBlockingEvent(e.Type) {
-		if err := s.Deliverer.DeliverNonBlockingEvent(e); err != nil {
-			s.Logger.WithError(err).Error("failed to dispatch non blocking event")
+		err = s.Deliverer.DeliverNonBlockingEvent(e)
+		if err != nil {
+			err = fmt.Errorf("failed to persist non blocking event: %w", err)
+			return
 		}
 	}
 
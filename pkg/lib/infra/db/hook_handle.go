@@ -69,7 +69,7 @@ func (h *HookHandle) WithTx(do func() error) (err error) {
 		} else if err != nil {
 			_ = rollbackTx(tx)
 		} else {
-			err = commitTx(tx, h.hooks)
+			err = commitTx(tx, h)
 		}
 	}()
 
@@ -104,7 +104,7 @@ func (h *HookHandle) ReadOnly(do func() error) (err error) {
 		} else if err != nil {
 			_ = rollbackTx(tx)
 		} else {
-			err = commitTx(tx, h.hooks)
+			err = commitTx(tx, h)
 		}
 	}()
 
@@ -128,9 +128,11 @@ func (h *HookHandle) beginTx() (*sqlx.Tx, error) {
 	return tx, nil
 }
 
-func commitTx(tx *sqlx.Tx, hooks []TransactionHook) error {
-	for _, hook := range hooks {
-		err := hook.WillCommitTx()
+func commitTx(tx *sqlx.Tx, h *HookHandle) error {
+	// Hooks may register other hooks while committing,
+	// so we have to iterate with index instead of range.
+	for i := 0; i < len(h.hooks); i++ {
+		err := h.hooks[i].WillCommitTx()
 		if err != nil {
 			if rbErr := tx.Rollback(); rbErr != nil {
 				err = errorutil.WithSecondaryError(err, rbErr)
@@ -144,8 +146,8 @@ func commitTx(tx *sqlx.Tx, hooks []TransactionHook) error {
 		return fmt.Errorf("hook-handle: failed to commit transaction: %w", err)
 	}
 
-	for _, hook := range hooks {
-		hook.DidCommitTx()
+	for i := 0; i < len(h.hooks); i++ {
+		h.hooks[i].DidCommitTx()
 	}
 
 	return nil
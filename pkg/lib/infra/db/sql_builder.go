@@ -147,3 +147,8 @@ func (b SelectBuilder) Limit(limit uint64) SelectBuilder {
 	b.builder = b.builder.Limit(limit)
 	return b
 }
+
+func (b SelectBuilder) Suffix(sql string, args ...interface{}) SelectBuilder {
+	b.builder = b.builder.Suffix(sql, args...)
+	return b
+}
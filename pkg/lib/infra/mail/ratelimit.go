@@ -3,6 +3,7 @@ package mail
 import (
 	"fmt"
 
+	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
 	"github.com/authgear/authgear-server/pkg/util/duration"
 )
@@ -11,10 +12,9 @@ type RateLimiter interface {
 	TakeToken(bucket ratelimit.Bucket) error
 }
 
-// TODO(rate-limit): allow configuration of bucket size & reset period
-
 func RateLimitBucket(email string) ratelimit.Bucket {
 	return ratelimit.Bucket{
+		Name:        config.RateLimitEmailMessage,
 		Key:         fmt.Sprintf("email-message:%s", email),
 		Size:        10,
 		ResetPeriod: duration.PerMinute,
@@ -0,0 +1,31 @@
+package eventstreamredis
+
+import (
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/redis"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+type Handle struct {
+	*redis.Handle
+}
+
+func NewHandle(pool *redis.Pool, cfg *config.RedisConfig, credentials *config.EventStreamCredentials, lf *log.Factory) *Handle {
+	if credentials == nil || credentials.RedisURL == "" {
+		return nil
+	}
+
+	return &Handle{
+		Handle: redis.NewHandle(
+			pool,
+			redis.ConnectionOptions{
+				RedisURL:              credentials.RedisURL,
+				MaxOpenConnection:     cfg.MaxOpenConnection,
+				MaxIdleConnection:     cfg.MaxIdleConnection,
+				IdleConnectionTimeout: cfg.IdleConnectionTimeout,
+				MaxConnectionLifetime: cfg.MaxConnectionLifetime,
+			},
+			lf.New("eventstreamredis-handle"),
+		),
+	}
+}
@@ -3,6 +3,7 @@ package sms
 import (
 	"fmt"
 
+	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
 	"github.com/authgear/authgear-server/pkg/util/duration"
 )
@@ -11,10 +12,9 @@ type RateLimiter interface {
 	TakeToken(bucket ratelimit.Bucket) error
 }
 
-// TODO(rate-limit): allow configuration of bucket size & reset period
-
 func RateLimitBucket(phone string) ratelimit.Bucket {
 	return ratelimit.Bucket{
+		Name:        config.RateLimitSMSMessage,
 		Key:         fmt.Sprintf("sms-message:%s", phone),
 		Size:        10,
 		ResetPeriod: duration.PerMinute,
@@ -14,6 +14,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/anonymous"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/biometric"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/loginid"
+	"github.com/authgear/authgear-server/pkg/lib/authn/lockout"
 	"github.com/authgear/authgear-server/pkg/lib/authn/mfa"
 	"github.com/authgear/authgear-server/pkg/lib/authn/otp"
 	"github.com/authgear/authgear-server/pkg/lib/authn/sso"
@@ -27,6 +28,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/util/accesscontrol"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/webauthn"
 )
 
 type IdentityService interface {
@@ -54,14 +56,19 @@ type AuthenticatorService interface {
 
 type OOBAuthenticatorProvider interface {
 	GetCode(authenticatorID string) (*oob.Code, error)
-	CreateCode(authenticatorID string) (*oob.Code, error)
+	CreateCode(authenticatorID string, opts oob.CodeOptions) (*oob.Code, error)
+}
+
+type PasskeyAuthenticatorProvider interface {
+	CreationOptions(userID string, userName string, excludeCredentialIDs []string) (*webauthn.CreationOptions, error)
+	RequestOptions(allowCredentialIDs []string) (*webauthn.RequestOptions, error)
 }
 
 type OOBCodeSender interface {
 	SendCode(
 		channel model.AuthenticatorOOBChannel,
 		target string,
-		code string,
+		code *oob.Code,
 		messageType otp.MessageType,
 	) error
 }
@@ -170,6 +177,11 @@ type RateLimiter interface {
 	CheckToken(bucket ratelimit.Bucket) (pass bool, resetDuration time.Duration, err error)
 }
 
+type AuthenticationLockoutService interface {
+	MakeAttempt(userID string) (*lockout.State, error)
+	ClearAttempts(userID string) error
+}
+
 type NonceService interface {
 	GenerateAndSet() string
 	GetAndClear() string
@@ -200,6 +212,7 @@ type Context struct {
 	BiometricIdentities      BiometricIdentityProvider
 	OOBAuthenticators        OOBAuthenticatorProvider
 	OOBCodeSender            OOBCodeSender
+	PasskeyAuthenticators    PasskeyAuthenticatorProvider
 	OAuthProviderFactory     OAuthProviderFactory
 	MFA                      MFAService
 	ForgotPassword           ForgotPasswordService
@@ -208,6 +221,7 @@ type Context struct {
 	Verification             VerificationService
 	VerificationCodeSender   VerificationCodeSender
 	RateLimiter              RateLimiter
+	AuthenticationLockout    AuthenticationLockoutService
 
 	Nonces NonceService
 
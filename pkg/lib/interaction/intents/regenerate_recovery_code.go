@@ -37,6 +37,7 @@ func (i *IntentRegenerateRecoveryCode) DeriveEdgesForNode(graph *interaction.Gra
 		return []interaction.Edge{
 			&nodes.EdgeDoGenerateRecoveryCode{
 				RecoveryCodes: node.RecoveryCodes,
+				IsRegenerate:  true,
 			},
 		}, nil
 
@@ -157,6 +157,19 @@ func (n *NodeAuthenticationBegin) GetAuthenticationEdges() ([]interaction.Edge,
 		},
 	)
 
+	passkeys := authenticator.ApplyFilters(
+		availableAuthenticators,
+		authenticator.KeepType(model.AuthenticatorTypePasskey),
+	)
+	interaction.SortAuthenticators(
+		nil,
+		passkeys,
+		func(i int) interaction.SortableAuthenticator {
+			a := interaction.SortableAuthenticatorInfo(*passkeys[i])
+			return &a
+		},
+	)
+
 	if len(passwords) > 0 {
 		edges = append(edges, &EdgeAuthenticationPassword{
 			Stage:          n.Stage,
@@ -164,6 +177,13 @@ func (n *NodeAuthenticationBegin) GetAuthenticationEdges() ([]interaction.Edge,
 		})
 	}
 
+	if len(passkeys) > 0 {
+		edges = append(edges, &EdgeAuthenticationPasskeyTrigger{
+			Stage:          n.Stage,
+			Authenticators: passkeys,
+		})
+	}
+
 	if len(totps) > 0 {
 		edges = append(edges, &EdgeAuthenticationTOTP{
 			Stage:          n.Stage,
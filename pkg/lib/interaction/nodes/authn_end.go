@@ -32,6 +32,16 @@ func (e *EdgeAuthenticationEnd) Instantiate(ctx *interaction.Context, graph *int
 
 	if err := node.IsFailure(); err != nil {
 		userID := graph.MustGetUserID()
+
+		var lockoutState *model.AuthenticationLockout
+		if node.IsLockable() {
+			state, err := ctx.AuthenticationLockout.MakeAttempt(userID)
+			if err != nil {
+				return nil, err
+			}
+			lockoutState = state.ToModel()
+		}
+
 		err = ctx.Events.DispatchEvent(&nonblocking.AuthenticationFailedEventPayload{
 			UserRef: model.UserRef{
 				Meta: model.Meta{
@@ -40,10 +50,22 @@ func (e *EdgeAuthenticationEnd) Instantiate(ctx *interaction.Context, graph *int
 			},
 			AuthenticationStage: string(e.Stage),
 			AuthenticationType:  string(e.AuthenticationType),
+			Lockout:             lockoutState,
 		})
 		if err != nil {
 			return nil, err
 		}
+	} else {
+		clear, err := node.clearsAttempts(ctx, graph)
+		if err != nil {
+			return nil, err
+		}
+		if clear {
+			err = ctx.AuthenticationLockout.ClearAttempts(graph.MustGetUserID())
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return node, nil
@@ -79,6 +101,46 @@ func (n *NodeAuthenticationEnd) FillDetails(err error) error {
 	})
 }
 
+// IsLockable reports whether a failed attempt is counted towards
+// the lockout of the user.
+func (n *NodeAuthenticationEnd) IsLockable() bool {
+	switch n.AuthenticationType {
+	case authn.AuthenticationTypePassword,
+		authn.AuthenticationTypeTOTP,
+		authn.AuthenticationTypeOOBOTPEmail,
+		authn.AuthenticationTypeOOBOTPSMS,
+		authn.AuthenticationTypeRecoveryCode:
+		return true
+	default:
+		return false
+	}
+}
+
+// clearsAttempts reports whether the successful authentication resets
+// the failed attempts of the user.
+//
+// The failed attempts are not reset by the primary authentication
+// if the user has secondary authenticators, so that the secondary
+// authenticators cannot be guessed by someone knowing the password.
+// They are reset after the secondary authentication instead.
+func (n *NodeAuthenticationEnd) clearsAttempts(ctx *interaction.Context, graph *interaction.Graph) (bool, error) {
+	switch n.Stage {
+	case authn.AuthenticationStagePrimary:
+		ais, err := ctx.Authenticators.List(
+			graph.MustGetUserID(),
+			authenticator.KeepKind(authenticator.KindSecondary),
+		)
+		if err != nil {
+			return false, err
+		}
+		return len(ais) == 0, nil
+	case authn.AuthenticationStageSecondary:
+		return true, nil
+	default:
+		panic("interaction: unknown authentication stage: " + n.Stage)
+	}
+}
+
 func (n *NodeAuthenticationEnd) IsFailure() (err error) {
 	switch n.AuthenticationType {
 	case authn.AuthenticationTypeNone:
@@ -86,7 +148,8 @@ func (n *NodeAuthenticationEnd) IsFailure() (err error) {
 	case authn.AuthenticationTypePassword,
 		authn.AuthenticationTypeTOTP,
 		authn.AuthenticationTypeOOBOTPEmail,
-		authn.AuthenticationTypeOOBOTPSMS:
+		authn.AuthenticationTypeOOBOTPSMS,
+		authn.AuthenticationTypePasskey:
 		if n.VerifiedAuthenticator == nil {
 			err = n.FillDetails(interaction.ErrInvalidCredentials)
 			return
@@ -0,0 +1,151 @@
+package nodes
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/authn/lockout"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+type fakeLockoutStorage struct {
+	records map[string]*lockout.Record
+}
+
+func (s *fakeLockoutStorage) Get(userID string) (*lockout.Record, error) {
+	record, ok := s.records[userID]
+	if !ok {
+		return &lockout.Record{}, nil
+	}
+	r := *record
+	return &r, nil
+}
+
+func (s *fakeLockoutStorage) MakeAttempt(userID string, now time.Time, ttl time.Duration) (*lockout.Record, error) {
+	record, _ := s.Get(userID)
+	record.Attempts++
+	record.LastAttemptAt = now
+	s.records[userID] = record
+	return record, nil
+}
+
+func (s *fakeLockoutStorage) ExpireAt(userID string, expireAt time.Time) error {
+	return nil
+}
+
+func (s *fakeLockoutStorage) Clear(userID string) error {
+	delete(s.records, userID)
+	return nil
+}
+
+type fakeEventService struct {
+	Payloads []event.Payload
+}
+
+func (s *fakeEventService) DispatchEvent(payload event.Payload) error {
+	s.Payloads = append(s.Payloads, payload)
+	return nil
+}
+
+type fakeAuthenticatorList struct {
+	interaction.AuthenticatorService
+	Authenticators []*authenticator.Info
+}
+
+func (s *fakeAuthenticatorList) List(userID string, filters ...authenticator.Filter) ([]*authenticator.Info, error) {
+	return authenticator.ApplyFilters(s.Authenticators, filters...), nil
+}
+
+func TestAuthenticationEndLockout(t *testing.T) {
+	Convey("EdgeAuthenticationEnd", t, func() {
+		cfg := &config.AuthenticationLockoutConfig{MaxAttempts: 3}
+		cfg.SetDefaults()
+		lockoutService := &lockout.Service{
+			Config:  cfg,
+			Clock:   clock.NewMockClockAt("2006-01-02T15:04:05Z"),
+			Storage: &fakeLockoutStorage{records: map[string]*lockout.Record{}},
+		}
+		authenticators := &fakeAuthenticatorList{}
+		events := &fakeEventService{}
+
+		ctx := &interaction.Context{
+			Authenticators:        authenticators,
+			AuthenticationLockout: lockoutService,
+			Events:                events,
+		}
+		graph := &interaction.Graph{
+			Nodes: []interaction.Node{&NodeDoUseUser{UseUserID: "user-id"}},
+		}
+
+		password := &authenticator.Info{
+			UserID: "user-id",
+			Type:   model.AuthenticatorTypePassword,
+			Kind:   authenticator.KindPrimary,
+		}
+		totp := &authenticator.Info{
+			UserID: "user-id",
+			Type:   model.AuthenticatorTypeTOTP,
+			Kind:   authenticator.KindSecondary,
+		}
+
+		authenticate := func(stage authn.AuthenticationStage, typ authn.AuthenticationType, verified *authenticator.Info) {
+			edge := &EdgeAuthenticationEnd{
+				Stage:                 stage,
+				AuthenticationType:    typ,
+				VerifiedAuthenticator: verified,
+			}
+			_, err := edge.Instantiate(ctx, graph, nil)
+			So(err, ShouldBeNil)
+		}
+
+		attempts := func() int {
+			state, err := lockoutService.Get("user-id")
+			So(err, ShouldBeNil)
+			return state.Attempts
+		}
+
+		Convey("should reset failed attempts after successful primary authentication", func() {
+			authenticators.Authenticators = []*authenticator.Info{password}
+
+			authenticate(authn.AuthenticationStagePrimary, authn.AuthenticationTypePassword, nil)
+			authenticate(authn.AuthenticationStagePrimary, authn.AuthenticationTypePassword, nil)
+			So(attempts(), ShouldEqual, 2)
+			So(events.Payloads, ShouldHaveLength, 2)
+
+			authenticate(authn.AuthenticationStagePrimary, authn.AuthenticationTypePassword, password)
+			So(attempts(), ShouldEqual, 0)
+		})
+
+		Convey("should reset failed attempts after successful secondary authentication", func() {
+			authenticators.Authenticators = []*authenticator.Info{password, totp}
+
+			authenticate(authn.AuthenticationStagePrimary, authn.AuthenticationTypePassword, nil)
+			authenticate(authn.AuthenticationStagePrimary, authn.AuthenticationTypePassword, password)
+			authenticate(authn.AuthenticationStageSecondary, authn.AuthenticationTypeTOTP, nil)
+			// The failed attempts are kept until the secondary authentication succeeds.
+			So(attempts(), ShouldEqual, 2)
+
+			authenticate(authn.AuthenticationStageSecondary, authn.AuthenticationTypeTOTP, totp)
+			So(attempts(), ShouldEqual, 0)
+		})
+
+		Convey("should reset failed attempts if secondary authentication is skipped", func() {
+			authenticators.Authenticators = []*authenticator.Info{password, totp}
+
+			authenticate(authn.AuthenticationStagePrimary, authn.AuthenticationTypePassword, nil)
+			authenticate(authn.AuthenticationStagePrimary, authn.AuthenticationTypePassword, password)
+			So(attempts(), ShouldEqual, 1)
+
+			authenticate(authn.AuthenticationStageSecondary, authn.AuthenticationTypeDeviceToken, nil)
+			So(attempts(), ShouldEqual, 0)
+		})
+	})
+}
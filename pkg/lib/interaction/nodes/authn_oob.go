@@ -3,9 +3,11 @@ package nodes
 import (
 	"fmt"
 
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
 	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/authn/lockout"
 	"github.com/authgear/authgear-server/pkg/lib/interaction"
 )
 
@@ -31,7 +33,9 @@ func (e *EdgeAuthenticationOOB) Instantiate(ctx *interaction.Context, graph *int
 
 	info := e.Authenticator
 	_, err := ctx.Authenticators.VerifySecret(info, input.GetOOBOTP())
-	if err != nil {
+	if apierrors.IsKind(err, lockout.ErrLocked) {
+		return nil, err
+	} else if err != nil {
 		info = nil
 	}
 
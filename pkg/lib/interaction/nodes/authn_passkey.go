@@ -0,0 +1,94 @@
+package nodes
+
+import (
+	"errors"
+
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/util/webauthn"
+)
+
+func init() {
+	interaction.RegisterNode(&NodeAuthenticationPasskey{})
+}
+
+type InputAuthenticationPasskey interface {
+	GetPasskeyAssertionResponse() string
+}
+
+type EdgeAuthenticationPasskey struct {
+	Stage          authn.AuthenticationStage
+	Authenticators []*authenticator.Info
+	// Challenge is the challenge issued in NodeAuthenticationPasskeyTrigger.
+	Challenge string
+}
+
+func (e *EdgeAuthenticationPasskey) AuthenticatorType() model.AuthenticatorType {
+	return model.AuthenticatorTypePasskey
+}
+
+func (e *EdgeAuthenticationPasskey) IsDefaultAuthenticator() bool {
+	filtered := authenticator.ApplyFilters(e.Authenticators, authenticator.KeepDefault)
+	return len(filtered) > 0
+}
+
+func (e *EdgeAuthenticationPasskey) Instantiate(ctx *interaction.Context, graph *interaction.Graph, rawInput interface{}) (interaction.Node, error) {
+	var input InputAuthenticationPasskey
+	if !interaction.Input(rawInput, &input) {
+		return nil, interaction.ErrIncompatibleInput
+	}
+
+	assertionResponse := input.GetPasskeyAssertionResponse()
+
+	// The assertion response tells which passkey was used,
+	// so only that passkey is verified.
+	var info *authenticator.Info
+	// The assertion must answer the challenge issued in this interaction.
+	resp, err := webauthn.ParseAssertionResponse([]byte(assertionResponse))
+	if err == nil && resp.ClientData.Challenge == e.Challenge {
+		credentialID := webauthn.EncodeBase64URL(resp.CredentialID)
+		for _, a := range e.Authenticators {
+			if a.Claims[authenticator.AuthenticatorClaimPasskeyCredentialID] != credentialID {
+				continue
+			}
+
+			_, err := ctx.Authenticators.VerifySecret(a, assertionResponse)
+			if errors.Is(err, authenticator.ErrInvalidCredentials) {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+
+			aa := a
+			info = aa
+			break
+		}
+	}
+
+	return &NodeAuthenticationPasskey{Stage: e.Stage, Authenticator: info}, nil
+}
+
+type NodeAuthenticationPasskey struct {
+	Stage         authn.AuthenticationStage `json:"stage"`
+	Authenticator *authenticator.Info       `json:"authenticator"`
+}
+
+func (n *NodeAuthenticationPasskey) Prepare(ctx *interaction.Context, graph *interaction.Graph) error {
+	return nil
+}
+
+func (n *NodeAuthenticationPasskey) GetEffects() ([]interaction.Effect, error) {
+	return nil, nil
+}
+
+func (n *NodeAuthenticationPasskey) DeriveEdges(graph *interaction.Graph) ([]interaction.Edge, error) {
+	return []interaction.Edge{
+		&EdgeAuthenticationEnd{
+			Stage:                 n.Stage,
+			AuthenticationType:    authn.AuthenticationTypePasskey,
+			VerifiedAuthenticator: n.Authenticator,
+		},
+	}, nil
+}
@@ -0,0 +1,83 @@
+package nodes
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/util/webauthn"
+)
+
+func init() {
+	interaction.RegisterNode(&NodeAuthenticationPasskeyTrigger{})
+}
+
+type InputAuthenticationPasskeyTrigger interface {
+	TriggerPasskey()
+}
+
+type EdgeAuthenticationPasskeyTrigger struct {
+	Stage          authn.AuthenticationStage
+	Authenticators []*authenticator.Info
+}
+
+func (e *EdgeAuthenticationPasskeyTrigger) AuthenticatorType() model.AuthenticatorType {
+	return model.AuthenticatorTypePasskey
+}
+
+func (e *EdgeAuthenticationPasskeyTrigger) IsDefaultAuthenticator() bool {
+	filtered := authenticator.ApplyFilters(e.Authenticators, authenticator.KeepDefault)
+	return len(filtered) > 0
+}
+
+func (e *EdgeAuthenticationPasskeyTrigger) Instantiate(ctx *interaction.Context, graph *interaction.Graph, rawInput interface{}) (interaction.Node, error) {
+	var input InputAuthenticationPasskeyTrigger
+	if !interaction.Input(rawInput, &input) {
+		return nil, interaction.ErrIncompatibleInput
+	}
+
+	var allowCredentialIDs []string
+	for _, a := range e.Authenticators {
+		allowCredentialIDs = append(allowCredentialIDs, a.Claims[authenticator.AuthenticatorClaimPasskeyCredentialID].(string))
+	}
+
+	options, err := ctx.PasskeyAuthenticators.RequestOptions(allowCredentialIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NodeAuthenticationPasskeyTrigger{
+		Stage:          e.Stage,
+		Authenticators: e.Authenticators,
+		RequestOptions: options,
+	}, nil
+}
+
+type NodeAuthenticationPasskeyTrigger struct {
+	Stage          authn.AuthenticationStage `json:"stage"`
+	Authenticators []*authenticator.Info     `json:"authenticators"`
+	RequestOptions *webauthn.RequestOptions  `json:"request_options"`
+}
+
+func (n *NodeAuthenticationPasskeyTrigger) Prepare(ctx *interaction.Context, graph *interaction.Graph) error {
+	return nil
+}
+
+func (n *NodeAuthenticationPasskeyTrigger) GetEffects() ([]interaction.Effect, error) {
+	return nil, nil
+}
+
+func (n *NodeAuthenticationPasskeyTrigger) DeriveEdges(graph *interaction.Graph) ([]interaction.Edge, error) {
+	return []interaction.Edge{
+		&EdgeAuthenticationPasskey{
+			Stage:          n.Stage,
+			Authenticators: n.Authenticators,
+			Challenge:      n.RequestOptions.Challenge,
+		},
+	}, nil
+}
+
+// GetPasskeyRequestOptions implements EnterPasskeyNode.
+func (n *NodeAuthenticationPasskeyTrigger) GetPasskeyRequestOptions() *webauthn.RequestOptions {
+	return n.RequestOptions
+}
@@ -0,0 +1,123 @@
+package nodes
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/blocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/lib/session/idpsession"
+)
+
+var errDisallowed = errors.New("disallowed")
+
+// disallowingEventService records the dispatched events,
+// and disallows all blocking events like a web-hook handler would.
+type disallowingEventService struct {
+	Payloads []event.Payload
+}
+
+func (s *disallowingEventService) DispatchEvent(payload event.Payload) error {
+	s.Payloads = append(s.Payloads, payload)
+	if _, ok := payload.(event.BlockingPayload); ok {
+		return errDisallowed
+	}
+	return nil
+}
+
+func TestBlockingEvents(t *testing.T) {
+	Convey("Blocking events", t, func() {
+		events := &disallowingEventService{}
+		ctx := &interaction.Context{
+			Events:       events,
+			IsCommitting: true,
+		}
+		userRef := model.UserRef{
+			Meta: model.Meta{
+				ID: "user-id",
+			},
+		}
+		loginID := &identity.Info{
+			ID:     "identity-id",
+			UserID: "user-id",
+			Type:   model.IdentityTypeLoginID,
+			Claims: map[string]interface{}{
+				identity.IdentityClaimLoginIDType:  "email",
+				identity.IdentityClaimLoginIDValue: "user@example.com",
+			},
+		}
+
+		apply := func(node interaction.Node) error {
+			graph := &interaction.Graph{
+				Nodes: []interaction.Node{
+					&NodeDoUseUser{UseUserID: "user-id"},
+					node,
+				},
+			}
+			return graph.Apply(ctx)
+		}
+
+		Convey("should dispatch identity.pre_create before adding identity", func() {
+			err := apply(&NodeDoCreateIdentity{
+				Identity:   loginID,
+				IsAddition: true,
+				IsAdminAPI: true,
+			})
+			So(err, ShouldEqual, errDisallowed)
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&blocking.IdentityPreCreateBlockingEventPayload{
+					UserRef:  userRef,
+					Identity: loginID.ToModel(),
+					AdminAPI: true,
+				},
+			})
+		})
+
+		Convey("should dispatch identity.pre_delete before removing identity", func() {
+			err := apply(&NodeDoRemoveIdentity{
+				Identity: loginID,
+			})
+			So(err, ShouldEqual, errDisallowed)
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&blocking.IdentityPreDeleteBlockingEventPayload{
+					UserRef:  userRef,
+					Identity: loginID.ToModel(),
+				},
+			})
+		})
+
+		Convey("should dispatch user.pre_authenticate before creating session", func() {
+			err := apply(&NodeDoEnsureSession{
+				CreateReason:    session.CreateReasonLogin,
+				SessionToCreate: &idpsession.IDPSession{ID: "session-id"},
+			})
+			So(err, ShouldEqual, errDisallowed)
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&blocking.UserPreAuthenticateBlockingEventPayload{
+					UserRef: userRef,
+					AMR:     []string{},
+				},
+			})
+		})
+
+		Convey("should dispatch user.pre_authenticate before reauthenticating session", func() {
+			err := apply(&NodeDoEnsureSession{
+				CreateReason:    session.CreateReasonReauthenticate,
+				UpdateSessionID: "session-id",
+			})
+			So(err, ShouldEqual, errDisallowed)
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&blocking.UserPreAuthenticateBlockingEventPayload{
+					UserRef: userRef,
+					AMR:     []string{},
+				},
+			})
+		})
+	})
+}
@@ -65,7 +65,12 @@ func (n *NodeCreateAuthenticatorBegin) Prepare(ctx *interaction.Context, graph *
 	}
 
 	if n.Stage == authn.AuthenticationStagePrimary {
-		n.Identity = graph.MustGetUserLastIdentity()
+		if n.isAddingPrimaryPasskey() {
+			// The user may add a passkey in settings, where the graph has no identity.
+			n.Identity, _ = graph.GetUserLastIdentity()
+		} else {
+			n.Identity = graph.MustGetUserLastIdentity()
+		}
 	}
 	n.AuthenticationConfig = ctx.Config.Authentication
 	n.AuthenticatorConfig = ctx.Config.Authenticator
@@ -134,9 +139,22 @@ func (n *NodeCreateAuthenticatorBegin) deriveEdges() ([]interaction.Edge, error)
 	return edges, nil
 }
 
+func (n *NodeCreateAuthenticatorBegin) isAddingPrimaryPasskey() bool {
+	return n.Stage == authn.AuthenticationStagePrimary &&
+		n.RequestedByUser &&
+		n.AuthenticatorType != nil &&
+		*n.AuthenticatorType == model.AuthenticatorTypePasskey
+}
+
 func (n *NodeCreateAuthenticatorBegin) derivePrimary() ([]interaction.Edge, error) {
 	// Determine whether we need to create primary authenticator.
 
+	// 0. Passkeys are not bound to any identity.
+	// The user can add them on request even if the identity has a primary authenticator.
+	if n.isAddingPrimaryPasskey() {
+		return n.derivePrimaryPasskey(), nil
+	}
+
 	// 1. Check whether the identity actually requires primary authenticator.
 	// If it does not, then no primary authenticator is needed.
 	identityRequiresPrimaryAuthentication := len(n.Identity.PrimaryAuthenticatorTypes()) > 0
@@ -180,6 +198,12 @@ func (n *NodeCreateAuthenticatorBegin) derivePrimary() ([]interaction.Edge, erro
 				IsDefault: isDefault,
 			})
 
+		case model.AuthenticatorTypePasskey:
+			edges = append(edges, &EdgeCreateAuthenticatorPasskeySetup{
+				Stage:     n.Stage,
+				IsDefault: isDefault,
+			})
+
 		case model.AuthenticatorTypeOOBSMS:
 			loginIDType := n.Identity.Claims[identity.IdentityClaimLoginIDType].(string)
 			loginID := n.Identity.Claims[identity.IdentityClaimLoginIDValue].(string)
@@ -225,6 +249,35 @@ func (n *NodeCreateAuthenticatorBegin) derivePrimary() ([]interaction.Edge, erro
 	return edges[:1], nil
 }
 
+func (n *NodeCreateAuthenticatorBegin) derivePrimaryPasskey() []interaction.Edge {
+	allowed := false
+	for _, t := range *n.AuthenticationConfig.PrimaryAuthenticators {
+		if t == model.AuthenticatorTypePasskey {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil
+	}
+
+	passkeys := authenticator.ApplyFilters(
+		n.Authenticators,
+		authenticator.KeepKind(authenticator.KindPrimary),
+		authenticator.KeepType(model.AuthenticatorTypePasskey),
+	)
+	if len(passkeys) >= *n.AuthenticatorConfig.Passkey.Maximum {
+		return nil
+	}
+
+	isDefault := len(authenticator.ApplyFilters(n.Authenticators, authenticator.KeepKind(authenticator.KindPrimary))) == 0
+	return []interaction.Edge{
+		&EdgeCreateAuthenticatorPasskeySetup{
+			Stage:     n.Stage,
+			IsDefault: isDefault,
+		},
+	}
+}
+
 // nolint: gocyclo
 func (n *NodeCreateAuthenticatorBegin) deriveSecondary() (edges []interaction.Edge) {
 	// Determine whether we need to create secondary authenticator.
@@ -289,6 +342,7 @@ func (n *NodeCreateAuthenticatorBegin) deriveSecondary() (edges []interaction.Ed
 	totpCount := 0
 	oobSMSCount := 0
 	oobEmailCount := 0
+	passkeyCount := 0
 	for _, a := range ais {
 		switch a.Type {
 		case model.AuthenticatorTypePassword:
@@ -299,6 +353,8 @@ func (n *NodeCreateAuthenticatorBegin) deriveSecondary() (edges []interaction.Ed
 			oobEmailCount++
 		case model.AuthenticatorTypeOOBSMS:
 			oobSMSCount++
+		case model.AuthenticatorTypePasskey:
+			passkeyCount++
 		default:
 			panic("interaction: unknown authenticator type: " + a.Type)
 		}
@@ -321,6 +377,14 @@ func (n *NodeCreateAuthenticatorBegin) deriveSecondary() (edges []interaction.Ed
 					IsDefault: isDefault,
 				})
 			}
+		case model.AuthenticatorTypePasskey:
+			// Condition B and C.
+			if passkeyCount < *n.AuthenticatorConfig.Passkey.Maximum {
+				edges = append(edges, &EdgeCreateAuthenticatorPasskeySetup{
+					Stage:     n.Stage,
+					IsDefault: isDefault,
+				})
+			}
 		case model.AuthenticatorTypeOOBEmail:
 			// Condition B and C.
 			if oobEmailCount < *n.AuthenticatorConfig.OOB.Email.Maximum {
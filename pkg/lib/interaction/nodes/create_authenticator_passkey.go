@@ -0,0 +1,91 @@
+package nodes
+
+import (
+	"errors"
+
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/util/webauthn"
+)
+
+func init() {
+	interaction.RegisterNode(&NodeCreateAuthenticatorPasskey{})
+}
+
+type InputCreateAuthenticatorPasskey interface {
+	GetPasskeyAttestationResponse() string
+	GetPasskeyDisplayName() string
+}
+
+type EdgeCreateAuthenticatorPasskey struct {
+	Stage     authn.AuthenticationStage
+	IsDefault bool
+	// Challenge is the challenge issued in NodeCreateAuthenticatorPasskeySetup.
+	Challenge string
+}
+
+func (e *EdgeCreateAuthenticatorPasskey) AuthenticatorType() model.AuthenticatorType {
+	return model.AuthenticatorTypePasskey
+}
+
+func (e *EdgeCreateAuthenticatorPasskey) IsDefaultAuthenticator() bool {
+	return false
+}
+
+func (e *EdgeCreateAuthenticatorPasskey) Instantiate(ctx *interaction.Context, graph *interaction.Graph, rawInput interface{}) (interaction.Node, error) {
+	var input InputCreateAuthenticatorPasskey
+	if !interaction.Input(rawInput, &input) {
+		return nil, interaction.ErrIncompatibleInput
+	}
+
+	// The attestation must answer the challenge issued in this interaction.
+	attestationResponse := input.GetPasskeyAttestationResponse()
+	resp, err := webauthn.ParseAttestationResponse([]byte(attestationResponse))
+	if err != nil || resp.ClientData.Challenge != e.Challenge {
+		return nil, interaction.ErrInvalidCredentials
+	}
+
+	userID := graph.MustGetUserID()
+	spec := &authenticator.Spec{
+		UserID:    userID,
+		IsDefault: e.IsDefault,
+		Kind:      stageToAuthenticatorKind(e.Stage),
+		Type:      model.AuthenticatorTypePasskey,
+		Claims: map[string]interface{}{
+			authenticator.AuthenticatorClaimPasskeyDisplayName: input.GetPasskeyDisplayName(),
+		},
+	}
+
+	info, err := ctx.Authenticators.New(spec, attestationResponse)
+	if errors.Is(err, authenticator.ErrInvalidCredentials) {
+		return nil, interaction.ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &NodeCreateAuthenticatorPasskey{Stage: e.Stage, Authenticator: info}, nil
+}
+
+type NodeCreateAuthenticatorPasskey struct {
+	Stage         authn.AuthenticationStage `json:"stage"`
+	Authenticator *authenticator.Info       `json:"authenticator"`
+}
+
+func (n *NodeCreateAuthenticatorPasskey) Prepare(ctx *interaction.Context, graph *interaction.Graph) error {
+	return nil
+}
+
+func (n *NodeCreateAuthenticatorPasskey) GetEffects() ([]interaction.Effect, error) {
+	return nil, nil
+}
+
+func (n *NodeCreateAuthenticatorPasskey) DeriveEdges(graph *interaction.Graph) ([]interaction.Edge, error) {
+	return []interaction.Edge{
+		&EdgeCreateAuthenticatorEnd{
+			Stage:          n.Stage,
+			Authenticators: []*authenticator.Info{n.Authenticator},
+		},
+	}, nil
+}
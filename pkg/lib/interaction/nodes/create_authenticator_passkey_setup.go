@@ -0,0 +1,94 @@
+package nodes
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/util/webauthn"
+)
+
+func init() {
+	interaction.RegisterNode(&NodeCreateAuthenticatorPasskeySetup{})
+}
+
+type InputCreateAuthenticatorPasskeySetup interface {
+	SetupPasskey()
+}
+
+type EdgeCreateAuthenticatorPasskeySetup struct {
+	Stage     authn.AuthenticationStage
+	IsDefault bool
+}
+
+func (e *EdgeCreateAuthenticatorPasskeySetup) AuthenticatorType() model.AuthenticatorType {
+	return model.AuthenticatorTypePasskey
+}
+
+func (e *EdgeCreateAuthenticatorPasskeySetup) IsDefaultAuthenticator() bool {
+	return false
+}
+
+func (e *EdgeCreateAuthenticatorPasskeySetup) Instantiate(ctx *interaction.Context, graph *interaction.Graph, rawInput interface{}) (interaction.Node, error) {
+	var input InputCreateAuthenticatorPasskeySetup
+	if !interaction.Input(rawInput, &input) {
+		return nil, interaction.ErrIncompatibleInput
+	}
+
+	userID := graph.MustGetUserID()
+
+	// In settings, the interaction may not have identity.
+	userName := userID
+	if ii, ok := graph.GetUserLastIdentity(); ok {
+		userName = ii.DisplayID()
+	}
+
+	existing, err := ctx.Authenticators.List(userID, authenticator.KeepType(model.AuthenticatorTypePasskey))
+	if err != nil {
+		return nil, err
+	}
+	var excludeCredentialIDs []string
+	for _, a := range existing {
+		excludeCredentialIDs = append(excludeCredentialIDs, a.Claims[authenticator.AuthenticatorClaimPasskeyCredentialID].(string))
+	}
+
+	options, err := ctx.PasskeyAuthenticators.CreationOptions(userID, userName, excludeCredentialIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NodeCreateAuthenticatorPasskeySetup{
+		Stage:           e.Stage,
+		IsDefault:       e.IsDefault,
+		CreationOptions: options,
+	}, nil
+}
+
+type NodeCreateAuthenticatorPasskeySetup struct {
+	Stage           authn.AuthenticationStage `json:"stage"`
+	IsDefault       bool                      `json:"is_default"`
+	CreationOptions *webauthn.CreationOptions `json:"creation_options"`
+}
+
+func (n *NodeCreateAuthenticatorPasskeySetup) Prepare(ctx *interaction.Context, graph *interaction.Graph) error {
+	return nil
+}
+
+func (n *NodeCreateAuthenticatorPasskeySetup) GetEffects() ([]interaction.Effect, error) {
+	return nil, nil
+}
+
+func (n *NodeCreateAuthenticatorPasskeySetup) DeriveEdges(graph *interaction.Graph) ([]interaction.Edge, error) {
+	return []interaction.Edge{
+		&EdgeCreateAuthenticatorPasskey{
+			Stage:     n.Stage,
+			IsDefault: n.IsDefault,
+			Challenge: n.CreationOptions.Challenge,
+		},
+	}, nil
+}
+
+// GetPasskeyCreationOptions implements SetupPasskeyNode.
+func (n *NodeCreateAuthenticatorPasskeySetup) GetPasskeyCreationOptions() *webauthn.CreationOptions {
+	return n.CreationOptions
+}
@@ -1,6 +1,8 @@
 package nodes
 
 import (
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
 	"github.com/authgear/authgear-server/pkg/lib/interaction"
@@ -19,12 +21,14 @@ func (e *EdgeDoCreateAuthenticator) Instantiate(ctx *interaction.Context, graph
 	return &NodeDoCreateAuthenticator{
 		Stage:          e.Stage,
 		Authenticators: e.Authenticators,
+		IsAdminAPI:     interaction.IsAdminAPI(rawInput),
 	}, nil
 }
 
 type NodeDoCreateAuthenticator struct {
 	Stage          authn.AuthenticationStage `json:"stage"`
 	Authenticators []*authenticator.Info     `json:"authenticators"`
+	IsAdminAPI     bool                      `json:"is_admin_api"`
 }
 
 func (n *NodeDoCreateAuthenticator) Prepare(ctx *interaction.Context, graph *interaction.Graph) error {
@@ -41,6 +45,24 @@ func (n *NodeDoCreateAuthenticator) GetEffects() ([]interaction.Effect, error) {
 				}
 			}
 
+			return nil
+		}),
+		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
+			for _, a := range n.Authenticators {
+				err := ctx.Events.DispatchEvent(&nonblocking.AuthenticatorCreatedEventPayload{
+					UserRef: model.UserRef{
+						Meta: model.Meta{
+							ID: a.UserID,
+						},
+					},
+					Authenticator: a.ToModel(),
+					AdminAPI:      n.IsAdminAPI,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
 			return nil
 		}),
 	}, nil
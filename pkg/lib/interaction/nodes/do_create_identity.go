@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/blocking"
 	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
 	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity"
@@ -79,6 +80,27 @@ func (n *NodeDoCreateIdentity) GetEffects() ([]interaction.Effect, error) {
 
 			return nil
 		}),
+		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
+			// Identities of new user are included in user.pre_create.
+			if _, creating := graph.GetNewUserID(); creating {
+				return nil
+			}
+
+			err := ctx.Events.DispatchEvent(&blocking.IdentityPreCreateBlockingEventPayload{
+				UserRef: model.UserRef{
+					Meta: model.Meta{
+						ID: n.Identity.UserID,
+					},
+				},
+				Identity: n.Identity.ToModel(),
+				AdminAPI: n.IsAdminAPI,
+			})
+			if err != nil {
+				return err
+			}
+
+			return nil
+		}),
 		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
 			if _, creating := graph.GetNewUserID(); creating {
 				return nil
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/authgear/authgear-server/pkg/api/event/blocking"
 	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
 	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
@@ -121,6 +122,29 @@ func (n *NodeDoEnsureSession) Prepare(ctx *interaction.Context, graph *interacti
 
 func (n *NodeDoEnsureSession) GetEffects() ([]interaction.Effect, error) {
 	return []interaction.Effect{
+		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
+			if n.CreateReason != session.CreateReasonLogin && n.CreateReason != session.CreateReasonReauthenticate {
+				return nil
+			}
+			if n.SessionToCreate == nil && n.UpdateSessionID == "" {
+				return nil
+			}
+
+			err := ctx.Events.DispatchEvent(&blocking.UserPreAuthenticateBlockingEventPayload{
+				UserRef: model.UserRef{
+					Meta: model.Meta{
+						ID: graph.MustGetUserID(),
+					},
+				},
+				AMR:      graph.GetAMR(),
+				AdminAPI: n.IsAdminAPI,
+			})
+			if err != nil {
+				return err
+			}
+
+			return nil
+		}),
 		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
 			return ctx.AuthenticationInfoService.Save(n.AuthenticationInfoEntry)
 		}),
@@ -200,6 +224,15 @@ func (n *NodeDoEnsureSession) GetEffects() ([]interaction.Effect, error) {
 					return err
 				}
 
+				err = ctx.Events.DispatchEvent(&nonblocking.SessionCreatedEventPayload{
+					UserRef:  userRef,
+					Session:  *n.SessionToCreate.ToAPIModel(),
+					AdminAPI: n.IsAdminAPI,
+				})
+				if err != nil {
+					return err
+				}
+
 				// Clean up unreachable IdP Session.
 				s := session.GetSession(ctx.Request.Context())
 				if s != nil && s.SessionType() == session.TypeIdentityProvider {
@@ -1,6 +1,8 @@
 package nodes
 
 import (
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/interaction"
 )
 
@@ -10,16 +12,19 @@ func init() {
 
 type EdgeDoGenerateRecoveryCode struct {
 	RecoveryCodes []string
+	IsRegenerate  bool
 }
 
 func (e *EdgeDoGenerateRecoveryCode) Instantiate(ctx *interaction.Context, graph *interaction.Graph, rawInput interface{}) (interaction.Node, error) {
 	return &NodeDoGenerateRecoveryCode{
 		RecoveryCodes: e.RecoveryCodes,
+		IsRegenerate:  e.IsRegenerate,
 	}, nil
 }
 
 type NodeDoGenerateRecoveryCode struct {
 	RecoveryCodes []string `json:"recovery_nodes"`
+	IsRegenerate  bool     `json:"is_regenerate"`
 }
 
 func (n *NodeDoGenerateRecoveryCode) Prepare(ctx *interaction.Context, graph *interaction.Graph) error {
@@ -36,6 +41,19 @@ func (n *NodeDoGenerateRecoveryCode) GetEffects() ([]interaction.Effect, error)
 
 			return nil
 		}),
+		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
+			if !n.IsRegenerate || len(n.RecoveryCodes) == 0 {
+				return nil
+			}
+
+			return ctx.Events.DispatchEvent(&nonblocking.UserRecoveryCodesRegeneratedEventPayload{
+				UserRef: model.UserRef{
+					Meta: model.Meta{
+						ID: graph.MustGetUserID(),
+					},
+				},
+			})
+		}),
 	}, nil
 }
 
@@ -1,6 +1,8 @@
 package nodes
 
 import (
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
 	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/interaction"
@@ -19,12 +21,14 @@ func (e *EdgeDoRemoveAuthenticator) Instantiate(ctx *interaction.Context, graph
 	return &NodeDoRemoveAuthenticator{
 		Authenticator:        e.Authenticator,
 		BypassMFARequirement: e.BypassMFARequirement,
+		IsAdminAPI:           interaction.IsAdminAPI(rawInput),
 	}, nil
 }
 
 type NodeDoRemoveAuthenticator struct {
 	Authenticator        *authenticator.Info `json:"authenticator"`
 	BypassMFARequirement bool                `json:"bypass_mfa_requirement"`
+	IsAdminAPI           bool                `json:"is_admin_api"`
 }
 
 func (n *NodeDoRemoveAuthenticator) Prepare(ctx *interaction.Context, graph *interaction.Graph) error {
@@ -82,6 +86,22 @@ func (n *NodeDoRemoveAuthenticator) GetEffects() ([]interaction.Effect, error) {
 				return err
 			}
 
+			return nil
+		}),
+		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
+			err := ctx.Events.DispatchEvent(&nonblocking.AuthenticatorDeletedEventPayload{
+				UserRef: model.UserRef{
+					Meta: model.Meta{
+						ID: n.Authenticator.UserID,
+					},
+				},
+				Authenticator: n.Authenticator.ToModel(),
+				AdminAPI:      n.IsAdminAPI,
+			})
+			if err != nil {
+				return err
+			}
+
 			return nil
 		}),
 	}, nil
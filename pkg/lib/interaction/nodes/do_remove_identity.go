@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/blocking"
 	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
 	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity"
@@ -66,6 +67,22 @@ func (n *NodeDoRemoveIdentity) GetEffects() ([]interaction.Effect, error) {
 
 			return nil
 		}),
+		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
+			err := ctx.Events.DispatchEvent(&blocking.IdentityPreDeleteBlockingEventPayload{
+				UserRef: model.UserRef{
+					Meta: model.Meta{
+						ID: n.Identity.UserID,
+					},
+				},
+				Identity: n.Identity.ToModel(),
+				AdminAPI: n.IsAdminAPI,
+			})
+			if err != nil {
+				return err
+			}
+
+			return nil
+		}),
 		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
 			userRef := model.UserRef{
 				Meta: model.Meta{
@@ -1,6 +1,9 @@
 package nodes
 
 import (
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
 	"github.com/authgear/authgear-server/pkg/lib/interaction"
@@ -14,6 +17,7 @@ type EdgeDoUpdateAuthenticator struct {
 	Stage                     authn.AuthenticationStage
 	AuthenticatorBeforeUpdate *authenticator.Info
 	AuthenticatorAfterUpdate  *authenticator.Info
+	IsPasswordReset           bool
 }
 
 func (e *EdgeDoUpdateAuthenticator) Instantiate(ctx *interaction.Context, graph *interaction.Graph, rawInput interface{}) (interaction.Node, error) {
@@ -21,6 +25,8 @@ func (e *EdgeDoUpdateAuthenticator) Instantiate(ctx *interaction.Context, graph
 		Stage:                     e.Stage,
 		AuthenticatorBeforeUpdate: e.AuthenticatorBeforeUpdate,
 		AuthenticatorAfterUpdate:  e.AuthenticatorAfterUpdate,
+		IsPasswordReset:           e.IsPasswordReset,
+		IsAdminAPI:                interaction.IsAdminAPI(rawInput),
 	}, nil
 }
 
@@ -28,6 +34,8 @@ type NodeDoUpdateAuthenticator struct {
 	Stage                     authn.AuthenticationStage `json:"stage"`
 	AuthenticatorBeforeUpdate *authenticator.Info       `json:"authenticator_before_update"`
 	AuthenticatorAfterUpdate  *authenticator.Info       `json:"authenticator_after_update"`
+	IsPasswordReset           bool                      `json:"is_password_reset"`
+	IsAdminAPI                bool                      `json:"is_admin_api"`
 }
 
 func (n *NodeDoUpdateAuthenticator) Prepare(ctx *interaction.Context, graph *interaction.Graph) error {
@@ -39,6 +47,32 @@ func (n *NodeDoUpdateAuthenticator) GetEffects() ([]interaction.Effect, error) {
 		interaction.EffectRun(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
 			return ctx.Authenticators.Update(n.AuthenticatorAfterUpdate)
 		}),
+		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
+			if n.AuthenticatorAfterUpdate.Type != model.AuthenticatorTypePassword {
+				return nil
+			}
+
+			userRef := model.UserRef{
+				Meta: model.Meta{
+					ID: n.AuthenticatorAfterUpdate.UserID,
+				},
+			}
+
+			var e event.Payload
+			if n.IsPasswordReset {
+				e = &nonblocking.UserPasswordResetEventPayload{
+					UserRef:  userRef,
+					AdminAPI: n.IsAdminAPI,
+				}
+			} else {
+				e = &nonblocking.UserPasswordChangedEventPayload{
+					UserRef:  userRef,
+					AdminAPI: n.IsAdminAPI,
+				}
+			}
+
+			return ctx.Events.DispatchEvent(e)
+		}),
 	}, nil
 }
 
@@ -1,6 +1,8 @@
 package nodes
 
 import (
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity"
 	"github.com/authgear/authgear-server/pkg/lib/feature/verification"
 	"github.com/authgear/authgear-server/pkg/lib/interaction"
@@ -42,6 +44,20 @@ func (n *NodeDoVerifyIdentity) GetEffects() ([]interaction.Effect, error) {
 
 			return nil
 		}),
+		interaction.EffectOnCommit(func(ctx *interaction.Context, graph *interaction.Graph, nodeIndex int) error {
+			if n.NewVerifiedClaim == nil {
+				return nil
+			}
+
+			return ctx.Events.DispatchEvent(&nonblocking.IdentityVerifiedEventPayload{
+				UserRef: model.UserRef{
+					Meta: model.Meta{
+						ID: n.Identity.UserID,
+					},
+				},
+				Identity: n.Identity.ToModel(),
+			})
+		}),
 	}, nil
 }
 
@@ -0,0 +1,241 @@
+package nodes
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/blocking"
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity"
+	"github.com/authgear/authgear-server/pkg/lib/feature/verification"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/lib/session/idpsession"
+)
+
+type fakeUserService struct {
+	interaction.UserService
+}
+
+func (s *fakeUserService) UpdateLoginTime(userID string, lastLoginAt time.Time) error {
+	return nil
+}
+
+type fakeSessionProvider struct {
+	interaction.SessionProvider
+	Created []*idpsession.IDPSession
+}
+
+func (s *fakeSessionProvider) Create(session *idpsession.IDPSession) error {
+	s.Created = append(s.Created, session)
+	return nil
+}
+
+type fakeAuthenticationInfoService struct{}
+
+func (s *fakeAuthenticationInfoService) Save(entry *authenticationinfo.Entry) error {
+	return nil
+}
+
+type fakeSearchService struct{}
+
+func (s *fakeSearchService) ReindexUser(userID string, isDelete bool) error {
+	return nil
+}
+
+func TestNonBlockingEvents(t *testing.T) {
+	Convey("Non-blocking events", t, func() {
+		events := &fakeEventService{}
+		sessions := &fakeSessionProvider{}
+		ctx := &interaction.Context{
+			IsCommitting:              true,
+			Request:                   httptest.NewRequest("POST", "/", nil),
+			Events:                    events,
+			Users:                     &fakeUserService{},
+			Sessions:                  sessions,
+			AuthenticationInfoService: &fakeAuthenticationInfoService{},
+			Search:                    &fakeSearchService{},
+		}
+		userRef := model.UserRef{
+			Meta: model.Meta{
+				ID: "user-id",
+			},
+		}
+		password := &authenticator.Info{
+			ID:     "password-id",
+			UserID: "user-id",
+			Type:   model.AuthenticatorTypePassword,
+			Kind:   authenticator.KindPrimary,
+		}
+		totp := &authenticator.Info{
+			ID:     "totp-id",
+			UserID: "user-id",
+			Type:   model.AuthenticatorTypeTOTP,
+			Kind:   authenticator.KindSecondary,
+		}
+		email := &identity.Info{
+			ID:     "identity-id",
+			UserID: "user-id",
+			Type:   model.IdentityTypeLoginID,
+			Claims: map[string]interface{}{
+				identity.StandardClaimEmail: "user@example.com",
+			},
+		}
+
+		apply := func(node interaction.Node) {
+			graph := &interaction.Graph{
+				Nodes: []interaction.Node{
+					&NodeDoUseUser{UseUserID: "user-id"},
+					node,
+				},
+			}
+			err := graph.Apply(ctx)
+			So(err, ShouldBeNil)
+		}
+
+		Convey("should dispatch authenticator.created for each created authenticator", func() {
+			apply(&NodeDoCreateAuthenticator{
+				Stage:          authn.AuthenticationStageSecondary,
+				Authenticators: []*authenticator.Info{password, totp},
+				IsAdminAPI:     true,
+			})
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&nonblocking.AuthenticatorCreatedEventPayload{
+					UserRef:       userRef,
+					Authenticator: password.ToModel(),
+					AdminAPI:      true,
+				},
+				&nonblocking.AuthenticatorCreatedEventPayload{
+					UserRef:       userRef,
+					Authenticator: totp.ToModel(),
+					AdminAPI:      true,
+				},
+			})
+		})
+
+		Convey("should dispatch authenticator.deleted", func() {
+			apply(&NodeDoRemoveAuthenticator{
+				Authenticator: totp,
+			})
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&nonblocking.AuthenticatorDeletedEventPayload{
+					UserRef:       userRef,
+					Authenticator: totp.ToModel(),
+				},
+			})
+		})
+
+		Convey("should dispatch user.password.changed", func() {
+			apply(&NodeDoUpdateAuthenticator{
+				Stage:                     authn.AuthenticationStagePrimary,
+				AuthenticatorBeforeUpdate: password,
+				AuthenticatorAfterUpdate:  password,
+			})
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&nonblocking.UserPasswordChangedEventPayload{
+					UserRef: userRef,
+				},
+			})
+		})
+
+		Convey("should dispatch user.password.reset", func() {
+			apply(&NodeDoUpdateAuthenticator{
+				Stage:                     authn.AuthenticationStagePrimary,
+				AuthenticatorBeforeUpdate: password,
+				AuthenticatorAfterUpdate:  password,
+				IsPasswordReset:           true,
+				IsAdminAPI:                true,
+			})
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&nonblocking.UserPasswordResetEventPayload{
+					UserRef:  userRef,
+					AdminAPI: true,
+				},
+			})
+		})
+
+		Convey("should not dispatch password events for other authenticators", func() {
+			apply(&NodeDoUpdateAuthenticator{
+				Stage:                     authn.AuthenticationStageSecondary,
+				AuthenticatorBeforeUpdate: totp,
+				AuthenticatorAfterUpdate:  totp,
+			})
+			So(events.Payloads, ShouldBeEmpty)
+		})
+
+		Convey("should dispatch identity.verified", func() {
+			apply(&NodeDoVerifyIdentity{
+				Identity:         email,
+				NewVerifiedClaim: nil,
+			})
+			So(events.Payloads, ShouldBeEmpty)
+
+			apply(&NodeDoVerifyIdentity{
+				Identity: email,
+				NewVerifiedClaim: &verification.Claim{
+					UserID: "user-id",
+					Name:   identity.StandardClaimEmail,
+					Value:  "user@example.com",
+				},
+			})
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&nonblocking.IdentityVerifiedEventPayload{
+					UserRef:  userRef,
+					Identity: email.ToModel(),
+				},
+			})
+		})
+
+		Convey("should dispatch session.created", func() {
+			s := &idpsession.IDPSession{
+				ID:        "session-id",
+				CreatedAt: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+				Attrs:     *session.NewAttrs("user-id"),
+			}
+			apply(&NodeDoEnsureSession{
+				CreateReason:    session.CreateReasonLogin,
+				SessionToCreate: s,
+			})
+			So(sessions.Created, ShouldResemble, []*idpsession.IDPSession{s})
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&blocking.UserPreAuthenticateBlockingEventPayload{
+					UserRef: userRef,
+					AMR:     []string{},
+				},
+				&nonblocking.UserAuthenticatedEventPayload{
+					UserRef: userRef,
+					Session: *s.ToAPIModel(),
+				},
+				&nonblocking.SessionCreatedEventPayload{
+					UserRef: userRef,
+					Session: *s.ToAPIModel(),
+				},
+			})
+		})
+
+		Convey("should dispatch session.created on signup", func() {
+			s := &idpsession.IDPSession{
+				ID:    "session-id",
+				Attrs: *session.NewAttrs("user-id"),
+			}
+			apply(&NodeDoEnsureSession{
+				CreateReason:    session.CreateReasonSignup,
+				SessionToCreate: s,
+			})
+			So(events.Payloads, ShouldResemble, []event.Payload{
+				&nonblocking.SessionCreatedEventPayload{
+					UserRef: userRef,
+					Session: *s.ToAPIModel(),
+				},
+			})
+		})
+	})
+}
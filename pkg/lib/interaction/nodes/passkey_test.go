@@ -0,0 +1,240 @@
+package nodes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/util/webauthn"
+)
+
+type fakePasskeyAuthenticatorProvider struct {
+	Challenge     string
+	UserID        string
+	CredentialIDs []string
+}
+
+func (p *fakePasskeyAuthenticatorProvider) CreationOptions(userID string, userName string, excludeCredentialIDs []string) (*webauthn.CreationOptions, error) {
+	p.UserID = userID
+	p.CredentialIDs = excludeCredentialIDs
+	return &webauthn.CreationOptions{Challenge: p.Challenge}, nil
+}
+
+func (p *fakePasskeyAuthenticatorProvider) RequestOptions(allowCredentialIDs []string) (*webauthn.RequestOptions, error) {
+	p.CredentialIDs = allowCredentialIDs
+	return &webauthn.RequestOptions{Challenge: p.Challenge}, nil
+}
+
+type fakePasskeyAuthenticatorService struct {
+	interaction.AuthenticatorService
+	Existing []*authenticator.Info
+	Created  []string
+	Verified []string
+}
+
+func (s *fakePasskeyAuthenticatorService) List(userID string, filters ...authenticator.Filter) ([]*authenticator.Info, error) {
+	return authenticator.ApplyFilters(s.Existing, filters...), nil
+}
+
+func (s *fakePasskeyAuthenticatorService) New(spec *authenticator.Spec, secret string) (*authenticator.Info, error) {
+	s.Created = append(s.Created, secret)
+	return &authenticator.Info{UserID: spec.UserID, Type: spec.Type}, nil
+}
+
+func (s *fakePasskeyAuthenticatorService) VerifySecret(info *authenticator.Info, secret string) (bool, error) {
+	s.Verified = append(s.Verified, secret)
+	return false, nil
+}
+
+type inputPasskey struct {
+	AssertionResponse   string
+	AttestationResponse string
+}
+
+func (i *inputPasskey) TriggerPasskey()                       {}
+func (i *inputPasskey) SetupPasskey()                         {}
+func (i *inputPasskey) GetPasskeyAssertionResponse() string   { return i.AssertionResponse }
+func (i *inputPasskey) GetPasskeyAttestationResponse() string { return i.AttestationResponse }
+func (i *inputPasskey) GetPasskeyDisplayName() string         { return "Passkey" }
+
+func passkeyClientDataJSON(typ string, challenge string) string {
+	b, err := json.Marshal(webauthn.ClientData{
+		Type:      typ,
+		Challenge: challenge,
+		Origin:    "https://example.com",
+	})
+	if err != nil {
+		panic(err)
+	}
+	return webauthn.EncodeBase64URL(b)
+}
+
+// passkeyAuthData is authenticator data without attested credential data.
+func passkeyAuthData() []byte {
+	return append(make([]byte, 32), 0x01, 0, 0, 0, 1)
+}
+
+func passkeyAssertionResponse(credentialID string, challenge string) string {
+	b, err := json.Marshal(map[string]interface{}{
+		"id":    credentialID,
+		"rawId": credentialID,
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    passkeyClientDataJSON(webauthn.ClientDataTypeGet, challenge),
+			"authenticatorData": webauthn.EncodeBase64URL(passkeyAuthData()),
+			"signature":         webauthn.EncodeBase64URL([]byte("signature")),
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func passkeyAttestationResponse(credentialID string, challenge string) string {
+	rawID, err := webauthn.DecodeBase64URL(credentialID)
+	if err != nil {
+		panic(err)
+	}
+	coseKey, err := webauthncbor.Marshal(map[int64]interface{}{
+		1:  int64(2),
+		3:  webauthn.AlgorithmES256,
+		-1: int64(1),
+		-2: make([]byte, 32),
+		-3: make([]byte, 32),
+	})
+	if err != nil {
+		panic(err)
+	}
+	// Set the attested credential data flag, and append the attested credential data.
+	authData := passkeyAuthData()
+	authData[32] |= 0x40
+	authData = append(authData, make([]byte, 16)...)
+	authData = append(authData, byte(len(rawID)>>8), byte(len(rawID)))
+	authData = append(authData, rawID...)
+	authData = append(authData, coseKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	if err != nil {
+		panic(err)
+	}
+	b, err := json.Marshal(map[string]interface{}{
+		"id":    credentialID,
+		"rawId": credentialID,
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    passkeyClientDataJSON(webauthn.ClientDataTypeCreate, challenge),
+			"attestationObject": webauthn.EncodeBase64URL(attestationObject),
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func TestPasskeyChallenge(t *testing.T) {
+	credentialID := webauthn.EncodeBase64URL([]byte("credential"))
+	passkey := &authenticator.Info{
+		ID:     "passkey",
+		UserID: "user",
+		Type:   model.AuthenticatorTypePasskey,
+		Claims: map[string]interface{}{
+			authenticator.AuthenticatorClaimPasskeyCredentialID: credentialID,
+		},
+	}
+
+	Convey("Passkey authentication", t, func() {
+		provider := &fakePasskeyAuthenticatorProvider{Challenge: "issued"}
+		authenticators := &fakePasskeyAuthenticatorService{}
+		ctx := &interaction.Context{
+			Authenticators:        authenticators,
+			PasskeyAuthenticators: provider,
+		}
+		graph := &interaction.Graph{}
+
+		trigger := &EdgeAuthenticationPasskeyTrigger{
+			Stage:          authn.AuthenticationStagePrimary,
+			Authenticators: []*authenticator.Info{passkey},
+		}
+		node, err := trigger.Instantiate(ctx, graph, &inputPasskey{})
+		So(err, ShouldBeNil)
+		So(provider.CredentialIDs, ShouldResemble, []string{credentialID})
+		So(node.(*NodeAuthenticationPasskeyTrigger).GetPasskeyRequestOptions().Challenge, ShouldEqual, "issued")
+
+		edges, err := node.DeriveEdges(graph)
+		So(err, ShouldBeNil)
+		edge := edges[0].(*EdgeAuthenticationPasskey)
+		So(edge.Challenge, ShouldEqual, "issued")
+
+		Convey("should verify assertion of the issued challenge", func() {
+			input := &inputPasskey{AssertionResponse: passkeyAssertionResponse(credentialID, "issued")}
+			node, err := edge.Instantiate(ctx, graph, input)
+			So(err, ShouldBeNil)
+			So(node.(*NodeAuthenticationPasskey).Authenticator, ShouldEqual, passkey)
+			So(authenticators.Verified, ShouldResemble, []string{input.AssertionResponse})
+		})
+
+		Convey("should reject assertion of another challenge", func() {
+			input := &inputPasskey{AssertionResponse: passkeyAssertionResponse(credentialID, "other")}
+			node, err := edge.Instantiate(ctx, graph, input)
+			So(err, ShouldBeNil)
+			So(node.(*NodeAuthenticationPasskey).Authenticator, ShouldBeNil)
+			So(authenticators.Verified, ShouldBeEmpty)
+		})
+	})
+
+	Convey("Passkey registration", t, func() {
+		provider := &fakePasskeyAuthenticatorProvider{Challenge: "issued"}
+		authenticators := &fakePasskeyAuthenticatorService{
+			Existing: []*authenticator.Info{passkey},
+		}
+		ctx := &interaction.Context{
+			Authenticators:        authenticators,
+			PasskeyAuthenticators: provider,
+		}
+		graph := &interaction.Graph{
+			Nodes: []interaction.Node{&NodeDoUseUser{UseUserID: "user"}},
+		}
+
+		setup := &EdgeCreateAuthenticatorPasskeySetup{
+			Stage:     authn.AuthenticationStagePrimary,
+			IsDefault: true,
+		}
+		node, err := setup.Instantiate(ctx, graph, &inputPasskey{})
+		So(err, ShouldBeNil)
+		So(provider.UserID, ShouldEqual, "user")
+		So(provider.CredentialIDs, ShouldResemble, []string{credentialID})
+		So(node.(*NodeCreateAuthenticatorPasskeySetup).GetPasskeyCreationOptions().Challenge, ShouldEqual, "issued")
+
+		edges, err := node.DeriveEdges(graph)
+		So(err, ShouldBeNil)
+		edge := edges[0].(*EdgeCreateAuthenticatorPasskey)
+		So(edge.Challenge, ShouldEqual, "issued")
+		So(edge.IsDefault, ShouldBeTrue)
+
+		Convey("should create passkey with attestation of the issued challenge", func() {
+			input := &inputPasskey{AttestationResponse: passkeyAttestationResponse(credentialID, "issued")}
+			_, err := edge.Instantiate(ctx, graph, input)
+			So(err, ShouldBeNil)
+			So(authenticators.Created, ShouldResemble, []string{input.AttestationResponse})
+		})
+
+		Convey("should reject attestation of another challenge", func() {
+			input := &inputPasskey{AttestationResponse: passkeyAttestationResponse(credentialID, "other")}
+			_, err := edge.Instantiate(ctx, graph, input)
+			So(err, ShouldBeError, interaction.ErrInvalidCredentials)
+			So(authenticators.Created, ShouldBeEmpty)
+		})
+	})
+}
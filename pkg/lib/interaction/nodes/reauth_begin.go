@@ -45,6 +45,11 @@ func (e *EdgeReauthenticationBegin) getAuthenticators(ctx *interaction.Context,
 			authenticator.KeepKind(authenticator.KindPrimary),
 			authenticator.KeepType(model.AuthenticatorTypePassword),
 		},
+		// Primary passkey
+		{
+			authenticator.KeepKind(authenticator.KindPrimary),
+			authenticator.KeepType(model.AuthenticatorTypePasskey),
+		},
 		// Primary OOB email
 		{
 			authenticator.KeepKind(authenticator.KindPrimary),
@@ -63,6 +68,11 @@ func (e *EdgeReauthenticationBegin) getAuthenticators(ctx *interaction.Context,
 			authenticator.KeepKind(authenticator.KindSecondary),
 			authenticator.KeepType(model.AuthenticatorTypeTOTP),
 		},
+		// Secondary passkey
+		{
+			authenticator.KeepKind(authenticator.KindSecondary),
+			authenticator.KeepType(model.AuthenticatorTypePasskey),
+		},
 		// Secondary password
 		{
 			authenticator.KeepKind(authenticator.KindSecondary),
@@ -152,6 +162,7 @@ func (n *NodeReauthenticationBegin) GetAuthenticationStage() authn.Authenticatio
 // GetAuthenticationEdges implements AuthenticationBeginNode.
 func (n *NodeReauthenticationBegin) GetAuthenticationEdges() ([]interaction.Edge, error) {
 	var edges []interaction.Edge
+	var passkeys []*authenticator.Info
 
 	for _, a := range n.Authenticators {
 		switch a.Type {
@@ -177,8 +188,18 @@ func (n *NodeReauthenticationBegin) GetAuthenticationEdges() ([]interaction.Edge
 				Authenticators:       []*authenticator.Info{a},
 				OOBAuthenticatorType: model.AuthenticatorTypeOOBSMS,
 			})
+		case model.AuthenticatorTypePasskey:
+			// All passkeys are offered in a single ceremony.
+			passkeys = append(passkeys, a)
 		}
 	}
 
+	if len(passkeys) > 0 {
+		edges = append(edges, &EdgeAuthenticationPasskeyTrigger{
+			Stage:          n.Stage,
+			Authenticators: passkeys,
+		})
+	}
+
 	return edges, nil
 }
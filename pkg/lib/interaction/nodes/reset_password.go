@@ -100,6 +100,7 @@ func (n *NodeResetPasswordEnd) DeriveEdges(graph *interaction.Graph) ([]interact
 				Stage:                     authn.AuthenticationStagePrimary,
 				AuthenticatorBeforeUpdate: n.OldAuthenticator,
 				AuthenticatorAfterUpdate:  n.NewAuthenticator,
+				IsPasswordReset:           true,
 			},
 		}, nil
 	}
@@ -8,9 +8,11 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
 	"github.com/authgear/authgear-server/pkg/lib/authn/otp"
+	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/feature"
 	"github.com/authgear/authgear-server/pkg/lib/interaction"
 	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
 )
 
 func cloneAuthenticator(info *authenticator.Info) *authenticator.Info {
@@ -89,8 +91,22 @@ func (p *SendOOBCode) Do() (*otp.CodeSendResult, error) {
 		return nil, err
 	}
 
-	if code == nil || p.Context.Clock.NowUTC().After(code.ExpireAt) {
-		code, err = p.Context.OOBAuthenticators.CreateCode(p.AuthenticatorInfo.ID)
+	// Magic link is only offered for authenticating with email,
+	// and it requires a web session to deliver the code to.
+	opts := oob.CodeOptions{
+		WebSessionID: p.Context.WebSessionID,
+		WithMagicLink: p.IsAuthenticating &&
+			p.AuthenticatorInfo.Type == model.AuthenticatorTypeOOBEmail &&
+			p.Context.Config.Authenticator.OOB.Email.EmailOTPMode == config.AuthenticatorEmailOTPModeMagicLink &&
+			p.Context.WebSessionID != "",
+	}
+	if p.Context.Request != nil {
+		opts.RequestIP = httputil.GetIP(p.Context.Request, bool(p.Context.TrustProxy))
+		opts.RequestUserAgent = p.Context.Request.UserAgent()
+	}
+
+	if code == nil || !code.IsReusableBy(p.Context.Clock.NowUTC(), opts) {
+		code, err = p.Context.OOBAuthenticators.CreateCode(p.AuthenticatorInfo.ID, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -110,7 +126,7 @@ func (p *SendOOBCode) Do() (*otp.CodeSendResult, error) {
 		return nil, err
 	}
 
-	err = p.Context.OOBCodeSender.SendCode(channel, target, code.Code, messageType)
+	err = p.Context.OOBCodeSender.SendCode(channel, target, code, messageType)
 	if err != nil {
 		return nil, err
 	}
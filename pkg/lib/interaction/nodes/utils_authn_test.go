@@ -0,0 +1,167 @@
+package nodes
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
+	"github.com/authgear/authgear-server/pkg/lib/authn/otp"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/interaction"
+	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+type fakeOOBAuthenticatorProvider struct {
+	Clock   clock.Clock
+	Codes   map[string]*oob.Code
+	Created int
+}
+
+func (p *fakeOOBAuthenticatorProvider) GetCode(authenticatorID string) (*oob.Code, error) {
+	code, ok := p.Codes[authenticatorID]
+	if !ok {
+		return nil, oob.ErrCodeNotFound
+	}
+	return code, nil
+}
+
+func (p *fakeOOBAuthenticatorProvider) CreateCode(authenticatorID string, opts oob.CodeOptions) (*oob.Code, error) {
+	p.Created++
+	code := &oob.Code{
+		AuthenticatorID:  authenticatorID,
+		Code:             "00000" + string(rune('0'+p.Created)),
+		ExpireAt:         p.Clock.NowUTC().Add(time.Hour),
+		CreatedAt:        p.Clock.NowUTC(),
+		WebSessionID:     opts.WebSessionID,
+		RequestIP:        opts.RequestIP,
+		RequestUserAgent: opts.RequestUserAgent,
+	}
+	if opts.WithMagicLink {
+		code.MagicLinkToken = "token-" + code.Code
+	}
+	p.Codes[authenticatorID] = code
+	return code, nil
+}
+
+type fakeOOBCodeSender struct {
+	Sent []*oob.Code
+}
+
+func (s *fakeOOBCodeSender) SendCode(channel model.AuthenticatorOOBChannel, target string, code *oob.Code, messageType otp.MessageType) error {
+	c := *code
+	s.Sent = append(s.Sent, &c)
+	return nil
+}
+
+type fakeRateLimiter struct{}
+
+func (fakeRateLimiter) TakeToken(bucket ratelimit.Bucket) error {
+	return nil
+}
+
+func (fakeRateLimiter) CheckToken(bucket ratelimit.Bucket) (bool, time.Duration, error) {
+	return true, 0, nil
+}
+
+func TestSendOOBCode(t *testing.T) {
+	Convey("SendOOBCode", t, func() {
+		clk := clock.NewMockClockAt("2020-01-01T00:00:00Z")
+		codes := &fakeOOBAuthenticatorProvider{Clock: clk, Codes: map[string]*oob.Code{}}
+		sender := &fakeOOBCodeSender{}
+		cfg := &config.AppConfig{
+			Authenticator: &config.AuthenticatorConfig{
+				OOB: &config.AuthenticatorOOBConfig{
+					Email: &config.AuthenticatorOOBEmailConfig{
+						EmailOTPMode: config.AuthenticatorEmailOTPModeMagicLink,
+					},
+				},
+			},
+		}
+		info := &authenticator.Info{
+			ID:   "authenticator-id",
+			Type: model.AuthenticatorTypeOOBEmail,
+			Claims: map[string]interface{}{
+				authenticator.AuthenticatorClaimOOBOTPEmail: "user@example.com",
+			},
+		}
+		send := func(webSessionID string, userAgent string) *oob.Code {
+			r, _ := http.NewRequest("POST", "/", nil)
+			r.RemoteAddr = "127.0.0.1:12345"
+			r.Header.Set("User-Agent", userAgent)
+			ctx := &interaction.Context{
+				WebSessionID:      webSessionID,
+				Request:           r,
+				Clock:             clk,
+				Config:            cfg,
+				OOBAuthenticators: codes,
+				OOBCodeSender:     sender,
+				RateLimiter:       fakeRateLimiter{},
+			}
+			_, err := (&SendOOBCode{
+				Context:           ctx,
+				Stage:             authn.AuthenticationStagePrimary,
+				IsAuthenticating:  true,
+				AuthenticatorInfo: info,
+			}).Do()
+			So(err, ShouldBeNil)
+			return sender.Sent[len(sender.Sent)-1]
+		}
+
+		Convey("should send magic link bound to the requesting web session", func() {
+			code := send("session-1", "Mozilla/5.0")
+			So(code.WebSessionID, ShouldEqual, "session-1")
+			So(code.MagicLinkToken, ShouldNotBeEmpty)
+			So(code.RequestIP, ShouldEqual, "127.0.0.1")
+			So(code.RequestUserAgent, ShouldEqual, "Mozilla/5.0")
+		})
+
+		Convey("should resend the code to the same web session", func() {
+			code1 := send("session-1", "Mozilla/5.0")
+			code2 := send("session-1", "Mozilla/5.0")
+			So(code2, ShouldResemble, code1)
+			So(codes.Created, ShouldEqual, 1)
+		})
+
+		Convey("should create a fresh code for another web session", func() {
+			code1 := send("session-1", "Mozilla/5.0")
+			code2 := send("session-2", "curl/7.64.1")
+			So(code2.Code, ShouldNotEqual, code1.Code)
+			So(code2.WebSessionID, ShouldEqual, "session-2")
+			So(code2.MagicLinkToken, ShouldNotEqual, code1.MagicLinkToken)
+			So(code2.RequestUserAgent, ShouldEqual, "curl/7.64.1")
+		})
+
+		Convey("should create a fresh code after the magic link is used", func() {
+			code1 := send("session-1", "Mozilla/5.0")
+			codes.Codes["authenticator-id"].MagicLinkToken = ""
+			code2 := send("session-1", "Mozilla/5.0")
+			So(code2.Code, ShouldNotEqual, code1.Code)
+			So(code2.MagicLinkToken, ShouldNotBeEmpty)
+		})
+
+		Convey("should create a fresh code with magic link for code created in code mode", func() {
+			cfg.Authenticator.OOB.Email.EmailOTPMode = config.AuthenticatorEmailOTPModeCode
+			code1 := send("session-1", "Mozilla/5.0")
+			So(code1.MagicLinkToken, ShouldBeEmpty)
+
+			cfg.Authenticator.OOB.Email.EmailOTPMode = config.AuthenticatorEmailOTPModeMagicLink
+			code2 := send("session-1", "Mozilla/5.0")
+			So(code2.MagicLinkToken, ShouldNotBeEmpty)
+		})
+
+		Convey("should share the code without magic link", func() {
+			cfg.Authenticator.OOB.Email.EmailOTPMode = config.AuthenticatorEmailOTPModeCode
+			code1 := send("session-1", "Mozilla/5.0")
+			code2 := send("session-2", "Mozilla/5.0")
+			So(code2.Code, ShouldEqual, code1.Code)
+			So(codes.Created, ShouldEqual, 1)
+		})
+	})
+}
@@ -3,6 +3,7 @@ package interaction
 import (
 	"fmt"
 
+	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
 	"github.com/authgear/authgear-server/pkg/util/duration"
 )
@@ -16,10 +17,9 @@ const (
 	OOBTypeAuthenticateSecondary OOBType = "authenticate-secondary-oob"
 )
 
-// TODO(rate-limit): allow configuration of bucket size & reset period
-
 func RequestRateLimitBucket(ip string) ratelimit.Bucket {
 	return ratelimit.Bucket{
+		Name:        config.RateLimitRequest,
 		Key:         fmt.Sprintf("request:%s", ip),
 		Size:        200,
 		ResetPeriod: duration.PerMinute,
@@ -28,6 +28,7 @@ func RequestRateLimitBucket(ip string) ratelimit.Bucket {
 
 func SignupRateLimitBucket(ip string) ratelimit.Bucket {
 	return ratelimit.Bucket{
+		Name:        config.RateLimitSignup,
 		Key:         fmt.Sprintf("signup:%s", ip),
 		Size:        10,
 		ResetPeriod: duration.PerMinute,
@@ -36,6 +37,7 @@ func SignupRateLimitBucket(ip string) ratelimit.Bucket {
 
 func SignupAnonymousUserRateLimitBucket(ip string) ratelimit.Bucket {
 	return ratelimit.Bucket{
+		Name:        config.RateLimitSignupAnonymous,
 		Key:         fmt.Sprintf("signup-anonymous-user:%s", ip),
 		Size:        60,
 		ResetPeriod: duration.PerHour,
@@ -44,6 +46,7 @@ func SignupAnonymousUserRateLimitBucket(ip string) ratelimit.Bucket {
 
 func AccountEnumerationRateLimitBucket(ip string) ratelimit.Bucket {
 	return ratelimit.Bucket{
+		Name:        config.RateLimitAccountEnumeration,
 		Key:         fmt.Sprintf("account-enumeration:%s", ip),
 		Size:        10,
 		ResetPeriod: duration.PerMinute,
@@ -52,6 +55,7 @@ func AccountEnumerationRateLimitBucket(ip string) ratelimit.Bucket {
 
 func SendVerificationCodeRateLimitBucket(target string) ratelimit.Bucket {
 	return ratelimit.Bucket{
+		Name:        config.RateLimitVerificationSendCode,
 		Key:         fmt.Sprintf("verification-send-code:%s", target),
 		Size:        1,
 		ResetPeriod: duration.PerMinute,
@@ -60,6 +64,7 @@ func SendVerificationCodeRateLimitBucket(target string) ratelimit.Bucket {
 
 func SendOOBCodeRateLimitBucket(oobType OOBType, target string) ratelimit.Bucket {
 	return ratelimit.Bucket{
+		Name:        config.RateLimitOOBSendCode,
 		Key:         fmt.Sprintf("oob-send-code:%s:%s", oobType, target),
 		Size:        1,
 		ResetPeriod: duration.PerMinute,
@@ -0,0 +1,110 @@
+package oauth
+
+import (
+	"errors"
+
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/session"
+)
+
+type AuthorizationServiceSessionManager interface {
+	Revoke(session session.Session, isAdminAPI bool) error
+}
+
+type AuthorizationService struct {
+	Store          AuthorizationStore
+	OfflineGrants  OfflineGrantStore
+	SessionManager AuthorizationServiceSessionManager
+}
+
+func (s *AuthorizationService) Get(id string) (*model.Authorization, error) {
+	authz, err := s.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := s.OfflineGrants.ListOfflineGrants(authz.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAuthorizationAPIModel(authz, grants), nil
+}
+
+func (s *AuthorizationService) List(userID string) ([]*model.Authorization, error) {
+	authzs, err := s.Store.ListByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := s.OfflineGrants.ListOfflineGrants(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]*model.Authorization, len(authzs))
+	for i, authz := range authzs {
+		models[i] = toAuthorizationAPIModel(authz, grants)
+	}
+	return models, nil
+}
+
+// Delete deletes the authorization, and revokes the offline grants issued
+// with it, so that the client can no longer access on behalf of the user.
+func (s *AuthorizationService) Delete(id string, isAdminAPI bool) error {
+	authz, err := s.getByID(id)
+	if err != nil {
+		return err
+	}
+
+	grants, err := s.OfflineGrants.ListOfflineGrants(authz.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, grant := range grants {
+		if grant.AuthorizationID != authz.ID {
+			continue
+		}
+		err = s.SessionManager.Revoke(grant, isAdminAPI)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.Store.Delete(authz)
+}
+
+func (s *AuthorizationService) getByID(id string) (*Authorization, error) {
+	authz, err := s.Store.GetByID(id)
+	if errors.Is(err, ErrAuthorizationNotFound) {
+		return nil, AuthorizationNotFound.New("authorization not found")
+	} else if err != nil {
+		return nil, err
+	}
+	return authz, nil
+}
+
+func toAuthorizationAPIModel(authz *Authorization, grants []*OfflineGrant) *model.Authorization {
+	lastUsedAt := authz.UpdatedAt
+	for _, grant := range grants {
+		if grant.AuthorizationID != authz.ID {
+			continue
+		}
+		if t := grant.AccessInfo.LastAccess.Timestamp; t.After(lastUsedAt) {
+			lastUsedAt = t
+		}
+	}
+
+	return &model.Authorization{
+		Meta: model.Meta{
+			ID:        authz.ID,
+			CreatedAt: authz.CreatedAt,
+			UpdatedAt: authz.UpdatedAt,
+		},
+		UserID:     authz.UserID,
+		ClientID:   authz.ClientID,
+		Scopes:     authz.Scopes,
+		LastUsedAt: lastUsedAt,
+	}
+}
@@ -0,0 +1,123 @@
+package oauth_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/lib/session/access"
+)
+
+type fakeAuthorizationStore struct {
+	oauth.AuthorizationStore
+	authzs  []*oauth.Authorization
+	deleted []string
+}
+
+func (s *fakeAuthorizationStore) GetByID(id string) (*oauth.Authorization, error) {
+	for _, a := range s.authzs {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return nil, oauth.ErrAuthorizationNotFound
+}
+
+func (s *fakeAuthorizationStore) ListByUserID(userID string) ([]*oauth.Authorization, error) {
+	return s.authzs, nil
+}
+
+func (s *fakeAuthorizationStore) Delete(authz *oauth.Authorization) error {
+	s.deleted = append(s.deleted, authz.ID)
+	return nil
+}
+
+type fakeOfflineGrantStore struct {
+	oauth.OfflineGrantStore
+	grants []*oauth.OfflineGrant
+}
+
+func (s *fakeOfflineGrantStore) ListOfflineGrants(userID string) ([]*oauth.OfflineGrant, error) {
+	return s.grants, nil
+}
+
+type fakeSessionManager struct {
+	revoked []string
+}
+
+func (m *fakeSessionManager) Revoke(s session.Session, isAdminAPI bool) error {
+	m.revoked = append(m.revoked, s.SessionID())
+	return nil
+}
+
+func TestAuthorizationService(t *testing.T) {
+	Convey("AuthorizationService", t, func() {
+		t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		t1 := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+		t2 := time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)
+
+		authzStore := &fakeAuthorizationStore{
+			authzs: []*oauth.Authorization{
+				{ID: "authz-a", UserID: "user-id", ClientID: "client-a", CreatedAt: t0, UpdatedAt: t0, Scopes: []string{"openid"}},
+				{ID: "authz-b", UserID: "user-id", ClientID: "client-b", CreatedAt: t0, UpdatedAt: t1, Scopes: []string{"openid", "offline_access"}},
+			},
+		}
+		grantStore := &fakeOfflineGrantStore{
+			grants: []*oauth.OfflineGrant{
+				{ID: "grant-1", AuthorizationID: "authz-a", AccessInfo: access.Info{LastAccess: access.Event{Timestamp: t1}}},
+				{ID: "grant-2", AuthorizationID: "authz-a", AccessInfo: access.Info{LastAccess: access.Event{Timestamp: t2}}},
+				{ID: "grant-3", AuthorizationID: "authz-c", AccessInfo: access.Info{LastAccess: access.Event{Timestamp: t2}}},
+			},
+		}
+		sessionManager := &fakeSessionManager{}
+
+		s := &oauth.AuthorizationService{
+			Store:          authzStore,
+			OfflineGrants:  grantStore,
+			SessionManager: sessionManager,
+		}
+
+		Convey("should list authorizations with last used time", func() {
+			authzs, err := s.List("user-id")
+			So(err, ShouldBeNil)
+			So(authzs, ShouldResemble, []*model.Authorization{
+				{
+					Meta:       model.Meta{ID: "authz-a", CreatedAt: t0, UpdatedAt: t0},
+					UserID:     "user-id",
+					ClientID:   "client-a",
+					Scopes:     []string{"openid"},
+					LastUsedAt: t2,
+				},
+				{
+					Meta:       model.Meta{ID: "authz-b", CreatedAt: t0, UpdatedAt: t1},
+					UserID:     "user-id",
+					ClientID:   "client-b",
+					Scopes:     []string{"openid", "offline_access"},
+					LastUsedAt: t1,
+				},
+			})
+		})
+
+		Convey("should revoke offline grants when deleting authorization", func() {
+			err := s.Delete("authz-a", false)
+			So(err, ShouldBeNil)
+			So(sessionManager.revoked, ShouldResemble, []string{"grant-1", "grant-2"})
+			So(authzStore.deleted, ShouldResemble, []string{"authz-a"})
+		})
+
+		Convey("should return not found error for unknown authorization", func() {
+			_, err := s.Get("authz-unknown")
+			So(apierrors.IsKind(err, oauth.AuthorizationNotFound), ShouldBeTrue)
+
+			err = s.Delete("authz-unknown", false)
+			So(apierrors.IsKind(err, oauth.AuthorizationNotFound), ShouldBeTrue)
+			So(sessionManager.revoked, ShouldBeEmpty)
+			So(authzStore.deleted, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package oauth
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// reservedClaims are the claims set by Authgear that cannot be set as custom claims.
+var reservedClaims = map[string]struct{}{
+	// RFC7519
+	"iss": {},
+	"sub": {},
+	"aud": {},
+	"exp": {},
+	"nbf": {},
+	"iat": {},
+	"jti": {},
+	// OIDC Core
+	"auth_time": {},
+	"nonce":     {},
+	"acr":       {},
+	"amr":       {},
+	"azp":       {},
+	"at_hash":   {},
+	"c_hash":    {},
+	"sid":       {},
+	// RFC8693
+	"client_id": {},
+	"scope":     {},
+}
+
+// reservedClaimPrefixes are the namespaces of claims set by Authgear.
+var reservedClaimPrefixes = []string{
+	"https://authgear.com/",
+}
+
+// ValidateCustomClaims validates custom claims are namespaced,
+// i.e. the claim name is a URI like "https://example.com/tenant_id",
+// and do not conflict with the claims set by Authgear.
+func ValidateCustomClaims(claims map[string]interface{}) error {
+	for name := range claims {
+		if _, ok := reservedClaims[name]; ok {
+			return fmt.Errorf("custom claim is reserved: %v", name)
+		}
+
+		for _, prefix := range reservedClaimPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				return fmt.Errorf("custom claim is reserved: %v", name)
+			}
+		}
+
+		u, err := url.Parse(name)
+		if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+			return fmt.Errorf("custom claim is not namespaced: %v", name)
+		}
+	}
+
+	return nil
+}
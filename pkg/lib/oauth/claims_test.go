@@ -0,0 +1,30 @@
+package oauth
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateCustomClaims(t *testing.T) {
+	Convey("ValidateCustomClaims", t, func() {
+		So(ValidateCustomClaims(nil), ShouldBeNil)
+		So(ValidateCustomClaims(map[string]interface{}{
+			"https://example.com/tenant_id": "tenant",
+			"urn:example:roles":             []string{"admin"},
+		}), ShouldBeNil)
+
+		So(ValidateCustomClaims(map[string]interface{}{
+			"sub": "user",
+		}), ShouldBeError, "custom claim is reserved: sub")
+		So(ValidateCustomClaims(map[string]interface{}{
+			"https://authgear.com/claims/user/is_verified": true,
+		}), ShouldBeError, "custom claim is reserved: https://authgear.com/claims/user/is_verified")
+		So(ValidateCustomClaims(map[string]interface{}{
+			"tenant_id": "tenant",
+		}), ShouldBeError, "custom claim is not namespaced: tenant_id")
+		So(ValidateCustomClaims(map[string]interface{}{
+			"/tenant_id": "tenant",
+		}), ShouldBeError, "custom claim is not namespaced: /tenant_id")
+	})
+}
@@ -0,0 +1,28 @@
+package oauth
+
+import (
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
+)
+
+// ConsentRequest is a pending authorization request of a third-party client,
+// waiting for the end-user to grant the requested scopes.
+type ConsentRequest struct {
+	AppID    string `json:"app_id"`
+	ClientID string `json:"client_id"`
+
+	CreatedAt time.Time `json:"created_at"`
+	ExpireAt  time.Time `json:"expire_at"`
+	CodeHash  string    `json:"code_hash"`
+
+	Parameters         map[string]string    `json:"parameters"`
+	RedirectURI        string               `json:"redirect_uri"`
+	IDPSessionID       string               `json:"session_id,omitempty"`
+	AuthenticationInfo authenticationinfo.T `json:"authentication_info"`
+	IDTokenHintSID     string               `json:"id_token_hint_sid,omitempty"`
+
+	// DeviceUserCode is the user code of the device code grant to be approved.
+	// It is empty for authorization requests.
+	DeviceUserCode string `json:"device_user_code,omitempty"`
+}
@@ -8,6 +8,7 @@ var DependencySet = wire.NewSet(
 	wire.Struct(new(MetadataProvider), "*"),
 	wire.Struct(new(Resolver), "*"),
 	wire.Struct(new(SessionManager), "*"),
+	wire.Struct(new(AuthorizationService), "*"),
 	wire.Struct(new(URLProvider), "*"),
 
 	wire.Struct(new(AccessTokenEncoding), "*"),
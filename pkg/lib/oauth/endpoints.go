@@ -7,4 +7,11 @@ type EndpointsProvider interface {
 	FromWebAppEndpointURL() *url.URL
 	TokenEndpointURL() *url.URL
 	RevokeEndpointURL() *url.URL
+	IntrospectionEndpointURL() *url.URL
+	PushedAuthorizationRequestEndpointURL() *url.URL
+	DeviceAuthorizationEndpointURL() *url.URL
+	DeviceVerificationEndpointURL() *url.URL
+	DeviceApprovalEndpointURL() *url.URL
+	DeviceSuccessEndpointURL() *url.URL
+	ConsentEndpointURL() *url.URL
 }
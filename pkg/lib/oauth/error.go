@@ -1,6 +1,21 @@
 package oauth
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+)
 
 var ErrAuthorizationNotFound = errors.New("oauth authorization not found")
 var ErrGrantNotFound = errors.New("oauth grant not found")
+var ErrUserCodeAlreadyExists = errors.New("user code already exists")
+
+var AuthorizationNotFound = apierrors.NotFound.WithReason("AuthorizationNotFound")
+
+var InvalidUserCode = apierrors.BadRequest.WithReason("InvalidUserCode")
+
+var ErrInvalidUserCode = InvalidUserCode.New("invalid user code")
+
+var InvalidConsentRequest = apierrors.BadRequest.WithReason("InvalidConsentRequest")
+
+var ErrInvalidConsentRequest = InvalidConsentRequest.New("invalid consent request")
@@ -0,0 +1,66 @@
+package oauth
+
+import (
+	"strings"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
+	"github.com/authgear/authgear-server/pkg/util/rand"
+)
+
+// nolint: gosec
+const DeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+const (
+	// userCodeAlphabet excludes vowels and similar looking characters,
+	// as recommended in RFC8628 section 6.1.
+	userCodeAlphabet string = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   int    = 8
+)
+
+type DeviceCodeGrantStatus string
+
+const (
+	DeviceCodeGrantStatusPending  DeviceCodeGrantStatus = "pending"
+	DeviceCodeGrantStatusApproved DeviceCodeGrantStatus = "approved"
+	DeviceCodeGrantStatusDenied   DeviceCodeGrantStatus = "denied"
+)
+
+type DeviceCodeGrant struct {
+	AppID    string   `json:"app_id"`
+	ClientID string   `json:"client_id"`
+	Scopes   []string `json:"scopes"`
+
+	CreatedAt      time.Time `json:"created_at"`
+	ExpireAt       time.Time `json:"expire_at"`
+	DeviceCodeHash string    `json:"device_code_hash"`
+	UserCode       string    `json:"user_code"`
+
+	Status             DeviceCodeGrantStatus `json:"status"`
+	AuthorizationID    string                `json:"authz_id,omitempty"`
+	IDPSessionID       string                `json:"session_id,omitempty"`
+	SID                string                `json:"sid,omitempty"`
+	AuthenticationInfo authenticationinfo.T  `json:"authentication_info"`
+}
+
+// GenerateUserCode generates a user code to be entered by the end-user.
+func GenerateUserCode() string {
+	return rand.StringWithAlphabet(userCodeLength, userCodeAlphabet, rand.SecureRand)
+}
+
+// NormalizeUserCode normalizes the user code entered by the end-user.
+// Dashes and spaces are ignored, and the comparison is case-insensitive.
+func NormalizeUserCode(userCode string) string {
+	userCode = strings.ToUpper(userCode)
+	userCode = strings.ReplaceAll(userCode, "-", "")
+	userCode = strings.ReplaceAll(userCode, " ", "")
+	return userCode
+}
+
+// FormatUserCode formats the user code for display, e.g. BCDF-GHJK.
+func FormatUserCode(userCode string) string {
+	if len(userCode) != userCodeLength {
+		return userCode
+	}
+	return userCode[:userCodeLength/2] + "-" + userCode[userCodeLength/2:]
+}
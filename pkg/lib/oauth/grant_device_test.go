@@ -0,0 +1,27 @@
+package oauth
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUserCode(t *testing.T) {
+	Convey("GenerateUserCode", t, func() {
+		userCode := GenerateUserCode()
+		So(userCode, ShouldHaveLength, 8)
+		So(NormalizeUserCode(userCode), ShouldEqual, userCode)
+	})
+
+	Convey("NormalizeUserCode", t, func() {
+		So(NormalizeUserCode("BCDF-GHJK"), ShouldEqual, "BCDFGHJK")
+		So(NormalizeUserCode("bcdf ghjk"), ShouldEqual, "BCDFGHJK")
+		So(NormalizeUserCode(" bcdf-GHJK "), ShouldEqual, "BCDFGHJK")
+	})
+
+	Convey("FormatUserCode", t, func() {
+		So(FormatUserCode("BCDFGHJK"), ShouldEqual, "BCDF-GHJK")
+		So(FormatUserCode("BCDF"), ShouldEqual, "BCDF")
+		So(NormalizeUserCode(FormatUserCode("BCDFGHJK")), ShouldEqual, "BCDFGHJK")
+	})
+}
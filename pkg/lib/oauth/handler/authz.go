@@ -24,8 +24,9 @@ func checkAuthorization(
 		return nil, err
 	}
 
-	// Authorization of requested scopes not granted, requesting consent.
-	// TODO(oauth): request consent, for now just always implicitly grant scopes.
+	// Authorization of requested scopes not granted.
+	// First-party clients are granted implicitly;
+	// third-party clients must have obtained the consent of the end-user.
 	if authz == nil {
 		authz = &oauth.Authorization{
 			ID:        uuid.New(),
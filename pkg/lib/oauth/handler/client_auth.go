@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/util/password"
+)
+
+var errInvalidClientCredentials = protocol.NewErrorStatusCode("invalid_client", "invalid client credentials", http.StatusUnauthorized)
+
+type clientCredentials struct {
+	Method       config.OAuthClientAuthMethod
+	ClientSecret string
+}
+
+// parseClientCredentials extracts the client credentials presented in the request.
+// If HTTP Basic authentication is used, the client ID in r is populated from
+// the Authorization header.
+func parseClientCredentials(req *http.Request, r protocol.TokenRequest) (*clientCredentials, error) {
+	username, pass, ok := req.BasicAuth()
+	if ok {
+		// The client ID and secret are form-urlencoded before being used
+		// as the username and password. See RFC6749 section 2.3.1.
+		clientID, err := url.QueryUnescape(username)
+		if err != nil {
+			return nil, protocol.NewError("invalid_request", "invalid client ID")
+		}
+		clientSecret, err := url.QueryUnescape(pass)
+		if err != nil {
+			return nil, protocol.NewError("invalid_request", "invalid client secret")
+		}
+
+		if r.ClientSecret() != "" {
+			return nil, protocol.NewError("invalid_request", "multiple client authentication methods are used")
+		}
+		if r.ClientID() != "" && r.ClientID() != clientID {
+			return nil, protocol.NewError("invalid_request", "client ID mismatch")
+		}
+		r["client_id"] = clientID
+
+		return &clientCredentials{
+			Method:       config.OAuthClientAuthMethodClientSecretBasic,
+			ClientSecret: clientSecret,
+		}, nil
+	}
+
+	if r.ClientSecret() != "" {
+		return &clientCredentials{
+			Method:       config.OAuthClientAuthMethodClientSecretPost,
+			ClientSecret: r.ClientSecret(),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// authenticateClient verifies the presented credentials against the client config.
+// Public clients are not authenticated, while confidential clients
+// must authenticate with the configured method.
+func authenticateClient(
+	secrets *config.OAuthClientSecrets,
+	client *config.OAuthClientConfig,
+	cred *clientCredentials,
+) error {
+	if !client.IsConfidential() {
+		return nil
+	}
+
+	if cred == nil || cred.Method != client.TokenEndpointAuthMethod {
+		return errInvalidClientCredentials
+	}
+
+	if secrets == nil {
+		return errInvalidClientCredentials
+	}
+	item, ok := secrets.Lookup(client.ClientID)
+	if !ok {
+		return errInvalidClientCredentials
+	}
+
+	for _, hash := range item.ClientSecretHashes {
+		if password.Compare([]byte(cred.ClientSecret), []byte(hash)) == nil {
+			return nil
+		}
+	}
+
+	return errInvalidClientCredentials
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/util/password"
+)
+
+func TestClientAuthentication(t *testing.T) {
+	Convey("parseClientCredentials", t, func() {
+		newRequest := func(form url.Values) *http.Request {
+			req, _ := http.NewRequest("POST", "/oauth2/token", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return req
+		}
+
+		Convey("should parse client_secret_basic", func() {
+			req := newRequest(url.Values{})
+			req.SetBasicAuth("client%3Aid", "secret%2F1")
+			r := protocol.TokenRequest{}
+
+			cred, err := parseClientCredentials(req, r)
+			So(err, ShouldBeNil)
+			So(cred, ShouldResemble, &clientCredentials{
+				Method:       config.OAuthClientAuthMethodClientSecretBasic,
+				ClientSecret: "secret/1",
+			})
+			So(r.ClientID(), ShouldEqual, "client:id")
+		})
+
+		Convey("should parse client_secret_post", func() {
+			req := newRequest(url.Values{})
+			r := protocol.TokenRequest{
+				"client_id":     "client-id",
+				"client_secret": "secret",
+			}
+
+			cred, err := parseClientCredentials(req, r)
+			So(err, ShouldBeNil)
+			So(cred, ShouldResemble, &clientCredentials{
+				Method:       config.OAuthClientAuthMethodClientSecretPost,
+				ClientSecret: "secret",
+			})
+		})
+
+		Convey("should return nil if no credentials are presented", func() {
+			req := newRequest(url.Values{})
+			r := protocol.TokenRequest{"client_id": "client-id"}
+
+			cred, err := parseClientCredentials(req, r)
+			So(err, ShouldBeNil)
+			So(cred, ShouldBeNil)
+		})
+
+		Convey("should reject multiple methods", func() {
+			req := newRequest(url.Values{})
+			req.SetBasicAuth("client-id", "secret")
+			r := protocol.TokenRequest{"client_secret": "secret"}
+
+			_, err := parseClientCredentials(req, r)
+			So(err, ShouldBeError, "multiple client authentication methods are used")
+		})
+
+		Convey("should reject mismatched client ID", func() {
+			req := newRequest(url.Values{})
+			req.SetBasicAuth("client-id", "secret")
+			r := protocol.TokenRequest{"client_id": "another-client-id"}
+
+			_, err := parseClientCredentials(req, r)
+			So(err, ShouldBeError, "client ID mismatch")
+		})
+	})
+
+	Convey("authenticateClient", t, func() {
+		hash, err := password.Hash([]byte("secret"))
+		So(err, ShouldBeNil)
+
+		secrets := &config.OAuthClientSecrets{
+			Items: []config.OAuthClientSecretsItem{
+				{ClientID: "confidential", ClientSecretHashes: []string{"invalid", string(hash)}},
+			},
+		}
+		publicClient := &config.OAuthClientConfig{ClientID: "public"}
+		confidentialClient := &config.OAuthClientConfig{
+			ClientID:                "confidential",
+			TokenEndpointAuthMethod: config.OAuthClientAuthMethodClientSecretBasic,
+		}
+
+		Convey("should not authenticate public clients", func() {
+			So(authenticateClient(secrets, publicClient, nil), ShouldBeNil)
+		})
+
+		Convey("should authenticate confidential clients", func() {
+			So(authenticateClient(secrets, confidentialClient, &clientCredentials{
+				Method:       config.OAuthClientAuthMethodClientSecretBasic,
+				ClientSecret: "secret",
+			}), ShouldBeNil)
+
+			So(authenticateClient(secrets, confidentialClient, nil), ShouldBeError, "invalid client credentials")
+			So(authenticateClient(secrets, confidentialClient, &clientCredentials{
+				Method:       config.OAuthClientAuthMethodClientSecretBasic,
+				ClientSecret: "wrong",
+			}), ShouldBeError, "invalid client credentials")
+			So(authenticateClient(secrets, confidentialClient, &clientCredentials{
+				Method:       config.OAuthClientAuthMethodClientSecretPost,
+				ClientSecret: "secret",
+			}), ShouldBeError, "invalid client credentials")
+			So(authenticateClient(nil, confidentialClient, &clientCredentials{
+				Method:       config.OAuthClientAuthMethodClientSecretBasic,
+				ClientSecret: "secret",
+			}), ShouldBeError, "invalid client credentials")
+		})
+	})
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/event/blocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+type CustomClaimsLogger struct{ *log.Logger }
+
+func NewCustomClaimsLogger(lf *log.Factory) CustomClaimsLogger {
+	return CustomClaimsLogger{lf.New("oauth-custom-claims")}
+}
+
+// CustomClaimsService resolves the custom claims of JWTs issued to the user
+// with the oidc.jwt.pre_create blocking event.
+type CustomClaimsService struct {
+	Logger CustomClaimsLogger
+	Events EventService
+}
+
+func (s *CustomClaimsService) ResolveCustomClaims(
+	client *config.OAuthClientConfig,
+	userID string,
+	scopes []string,
+) (map[string]interface{}, error) {
+	payload := &blocking.OIDCJWTPreCreateBlockingEventPayload{
+		UserRef: model.UserRef{
+			Meta: model.Meta{
+				ID: userID,
+			},
+		},
+		ClientID: client.ClientID,
+		Scopes:   scopes,
+		JWT: blocking.OIDCJWT{
+			Payload: map[string]interface{}{},
+		},
+	}
+
+	err := s.Events.DispatchEvent(payload)
+	if apierrors.IsKind(err, hook.WebHookDisallowed) {
+		// Disallowed by the handler explicitly, regardless of the failure policy.
+		return nil, err
+	}
+	if err == nil {
+		err = oauth.ValidateCustomClaims(payload.JWT.Payload)
+	}
+	if err != nil {
+		if client.CustomClaimsFailurePolicy == config.OAuthCustomClaimsFailurePolicyFallback {
+			s.Logger.WithError(err).WithField("client_id", client.ClientID).
+				Warn("failed to resolve custom claims, issuing tokens without custom claims")
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return payload.JWT.Payload, nil
+}
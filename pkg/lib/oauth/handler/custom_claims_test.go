@@ -0,0 +1,102 @@
+package handler_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/blocking"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/handler"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+func TestCustomClaimsService(t *testing.T) {
+	Convey("CustomClaimsService", t, func() {
+		events := &mockEventService{}
+		s := &handler.CustomClaimsService{
+			Logger: handler.CustomClaimsLogger{log.Null},
+			Events: events,
+		}
+		client := &config.OAuthClientConfig{
+			ClientID: "client-id",
+		}
+
+		Convey("should resolve custom claims from mutations", func() {
+			events.mutations = &event.Mutations{
+				JWT: event.JWTMutations{
+					Payload: map[string]interface{}{
+						"https://example.com/tenant_id": "tenant",
+					},
+				},
+			}
+
+			claims, err := s.ResolveCustomClaims(client, "user-id", []string{"openid"})
+			So(err, ShouldBeNil)
+			So(claims, ShouldResemble, map[string]interface{}{
+				"https://example.com/tenant_id": "tenant",
+			})
+
+			payload := events.payloads[0].(*blocking.OIDCJWTPreCreateBlockingEventPayload)
+			So(payload.UserID(), ShouldEqual, "user-id")
+			So(payload.ClientID, ShouldEqual, "client-id")
+			So(payload.Scopes, ShouldResemble, []string{"openid"})
+		})
+
+		Convey("should resolve empty custom claims without handlers", func() {
+			claims, err := s.ResolveCustomClaims(client, "user-id", nil)
+			So(err, ShouldBeNil)
+			So(claims, ShouldBeEmpty)
+		})
+
+		Convey("should fail closed by default", func() {
+			events.mutations = &event.Mutations{
+				JWT: event.JWTMutations{
+					Payload: map[string]interface{}{
+						"sub": "another-user-id",
+					},
+				},
+			}
+			_, err := s.ResolveCustomClaims(client, "user-id", nil)
+			So(err, ShouldBeError, "custom claim is reserved: sub")
+
+			events.mutations = nil
+			events.err = errors.New("webhook delivery timeout")
+			_, err = s.ResolveCustomClaims(client, "user-id", nil)
+			So(err, ShouldBeError, "webhook delivery timeout")
+		})
+
+		Convey("should fall back to no custom claims", func() {
+			client.CustomClaimsFailurePolicy = config.OAuthCustomClaimsFailurePolicyFallback
+
+			events.mutations = &event.Mutations{
+				JWT: event.JWTMutations{
+					Payload: map[string]interface{}{
+						"tenant_id": "tenant",
+					},
+				},
+			}
+			claims, err := s.ResolveCustomClaims(client, "user-id", nil)
+			So(err, ShouldBeNil)
+			So(claims, ShouldBeNil)
+
+			events.mutations = nil
+			events.err = errors.New("webhook delivery timeout")
+			claims, err = s.ResolveCustomClaims(client, "user-id", nil)
+			So(err, ShouldBeNil)
+			So(claims, ShouldBeNil)
+		})
+
+		Convey("should not fall back if disallowed", func() {
+			client.CustomClaimsFailurePolicy = config.OAuthCustomClaimsFailurePolicyFallback
+			events.err = hook.WebHookDisallowed.New("disallowed")
+
+			_, err := s.ResolveCustomClaims(client, "user-id", nil)
+			So(apierrors.IsKind(err, hook.WebHookDisallowed), ShouldBeTrue)
+		})
+	})
+}
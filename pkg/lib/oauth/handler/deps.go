@@ -10,7 +10,16 @@ var DependencySet = wire.NewSet(
 	NewTokenHandlerLogger,
 	wire.Struct(new(TokenHandler), "*"),
 	wire.Struct(new(RevokeHandler), "*"),
+	NewIntrospectionHandlerLogger,
+	wire.Struct(new(IntrospectionHandler), "*"),
+	NewDeviceAuthorizationHandlerLogger,
+	wire.Struct(new(DeviceAuthorizationHandler), "*"),
+	NewPushedAuthorizationHandlerLogger,
+	wire.Struct(new(PushedAuthorizationHandler), "*"),
 	NewAnonymousUserHandlerLogger,
 	wire.Struct(new(AnonymousUserHandler), "*"),
 	wire.Struct(new(TokenService), "*"),
+	NewCustomClaimsLogger,
+	wire.Struct(new(CustomClaimsService), "*"),
+	wire.Bind(new(CustomClaimsResolver), new(*CustomClaimsService)),
 )
@@ -78,6 +78,7 @@ type AnonymousUserHandler struct {
 	UserProvider        UserProvider
 	AnonymousIdentities AnonymousIdentityProvider
 	PromotionCodes      PromotionCodeStore
+	CustomClaims        CustomClaimsResolver
 }
 
 // SignupAnonymousUser return token response or api errors
@@ -164,9 +165,14 @@ func (h *AnonymousUserHandler) signupAnonymousUserWithRefreshTokenSessionType(
 			return nil, ErrLoggedInAsNormalUser
 		}
 
+		customClaims, err := h.resolveCustomClaims(client, authz.UserID, scopes)
+		if err != nil {
+			return nil, err
+		}
+
 		resp := protocol.TokenResponse{}
 		err = h.TokenService.IssueAccessGrant(client, scopes, authz.ID, authz.UserID,
-			grant.ID, oauth.GrantSessionKindOffline, resp)
+			grant.ID, oauth.GrantSessionKindOffline, customClaims, resp)
 		if err != nil {
 			return nil, err
 		}
@@ -198,6 +204,11 @@ func (h *AnonymousUserHandler) signupAnonymousUserWithRefreshTokenSessionType(
 		return nil, err
 	}
 
+	customClaims, err := h.resolveCustomClaims(client, authz.UserID, scopes)
+	if err != nil {
+		return nil, err
+	}
+
 	resp := protocol.TokenResponse{}
 	opts := IssueOfflineGrantOptions{
 		Scopes:             scopes,
@@ -211,7 +222,7 @@ func (h *AnonymousUserHandler) signupAnonymousUserWithRefreshTokenSessionType(
 	}
 
 	err = h.TokenService.IssueAccessGrant(client, scopes, authz.ID, authz.UserID,
-		offlineGrant.ID, oauth.GrantSessionKindOffline, resp)
+		offlineGrant.ID, oauth.GrantSessionKindOffline, customClaims, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -221,6 +232,19 @@ func (h *AnonymousUserHandler) signupAnonymousUserWithRefreshTokenSessionType(
 	}, nil
 }
 
+// resolveCustomClaims resolves the custom claims if JWT access token is going to be issued.
+func (h *AnonymousUserHandler) resolveCustomClaims(
+	client *config.OAuthClientConfig,
+	userID string,
+	scopes []string,
+) (map[string]interface{}, error) {
+	if !client.IssueJWTAccessToken {
+		return nil, nil
+	}
+
+	return h.CustomClaims.ResolveCustomClaims(client, userID, scopes)
+}
+
 func (h *AnonymousUserHandler) runSignupAnonymousUserGraph(
 	suppressIDPSessionCookie bool,
 ) (*interaction.Graph, error) {
@@ -37,6 +37,7 @@ type IDTokenVerifier interface {
 
 type OAuthURLProvider interface {
 	FromWebAppURL(r protocol.AuthorizationRequest) *url.URL
+	ConsentURL(code string) *url.URL
 }
 
 type WebAppAuthenticateURLProvider interface {
@@ -73,19 +74,23 @@ type AuthorizationHandler struct {
 	HTTPConfig *config.HTTPConfig
 	Logger     AuthorizationHandlerLogger
 
-	Sessions                  SessionProvider
-	Authorizations            oauth.AuthorizationStore
-	OfflineGrants             oauth.OfflineGrantStore
-	CodeGrants                oauth.CodeGrantStore
-	OAuthURLs                 OAuthURLProvider
-	WebAppURLs                WebAppAuthenticateURLProvider
-	ValidateScopes            ScopesValidator
-	CodeGenerator             TokenGenerator
-	LoginHint                 LoginHintHandler
-	IDTokens                  IDTokenVerifier
-	AuthenticationInfoService AuthenticationInfoService
-	Clock                     clock.Clock
-	Cookies                   CookieManager
+	Sessions                    SessionProvider
+	Authorizations              oauth.AuthorizationStore
+	OfflineGrants               oauth.OfflineGrantStore
+	CodeGrants                  oauth.CodeGrantStore
+	PushedAuthorizationRequests oauth.PushedAuthorizationRequestStore
+	ConsentRequests             oauth.ConsentRequestStore
+	DeviceCodeGrants            oauth.DeviceCodeGrantStore
+	OAuthURLs                   OAuthURLProvider
+	DeviceURLs                  DeviceURLProvider
+	WebAppURLs                  WebAppAuthenticateURLProvider
+	ValidateScopes              ScopesValidator
+	CodeGenerator               TokenGenerator
+	LoginHint                   LoginHintHandler
+	IDTokens                    IDTokenVerifier
+	AuthenticationInfoService   AuthenticationInfoService
+	Clock                       clock.Clock
+	Cookies                     CookieManager
 }
 
 func (h *AuthorizationHandler) Handle(r protocol.AuthorizationRequest) httputil.Result {
@@ -96,6 +101,12 @@ func (h *AuthorizationHandler) Handle(r protocol.AuthorizationRequest) httputil.
 			Response:     protocol.NewErrorResponse("unauthorized_client", "invalid client ID"),
 		}
 	}
+
+	r, err := h.resolvePushedAuthorizationRequest(client, r, false)
+	if err != nil {
+		return h.requestResultError(err)
+	}
+
 	redirectURI, errResp := parseRedirectURI(client, h.HTTPConfig, r)
 	if errResp != nil {
 		return authorizationResultError{
@@ -106,23 +117,7 @@ func (h *AuthorizationHandler) Handle(r protocol.AuthorizationRequest) httputil.
 
 	result, err := h.doHandle(redirectURI, client, r)
 	if err != nil {
-		var oauthError *protocol.OAuthProtocolError
-		resultErr := authorizationResultError{
-			RedirectURI:  redirectURI,
-			ResponseMode: r.ResponseMode(),
-		}
-		if errors.As(err, &oauthError) {
-			resultErr.Response = oauthError.Response
-		} else {
-			h.Logger.WithError(err).Error("authz handler failed")
-			resultErr.Response = protocol.NewErrorResponse("server_error", "internal server error")
-			resultErr.InternalError = true
-		}
-		state := r.State()
-		if state != "" {
-			resultErr.Response.State(r.State())
-		}
-		result = resultErr
+		result = h.redirectResultError(redirectURI, r, err)
 	}
 
 	return result
@@ -136,6 +131,12 @@ func (h *AuthorizationHandler) HandleFromWebApp(r protocol.AuthorizationRequest,
 			Response:     protocol.NewErrorResponse("unauthorized_client", "invalid client ID"),
 		}
 	}
+
+	r, err := h.resolvePushedAuthorizationRequest(client, r, true)
+	if err != nil {
+		return h.requestResultError(err)
+	}
+
 	redirectURI, errResp := parseRedirectURI(client, h.HTTPConfig, r)
 	if errResp != nil {
 		return authorizationResultError{
@@ -146,26 +147,114 @@ func (h *AuthorizationHandler) HandleFromWebApp(r protocol.AuthorizationRequest,
 
 	result, err := h.doHandleFromWebApp(redirectURI, client, r, req)
 	if err != nil {
-		var oauthError *protocol.OAuthProtocolError
-		resultErr := authorizationResultError{
-			RedirectURI:  redirectURI,
-			ResponseMode: r.ResponseMode(),
+		result = h.redirectResultError(redirectURI, r, err)
+	}
+
+	return result
+}
+
+// redirectResultError returns the error result to be redirected to the client.
+func (h *AuthorizationHandler) redirectResultError(
+	redirectURI *url.URL,
+	r protocol.AuthorizationRequest,
+	err error,
+) httputil.Result {
+	var oauthError *protocol.OAuthProtocolError
+	resultErr := authorizationResultError{
+		RedirectURI:  redirectURI,
+		ResponseMode: r.ResponseMode(),
+	}
+	if errors.As(err, &oauthError) {
+		resultErr.Response = oauthError.Response
+	} else {
+		h.Logger.WithError(err).Error("authz handler failed")
+		resultErr.Response = protocol.NewErrorResponse("server_error", "internal server error")
+		resultErr.InternalError = true
+	}
+	state := r.State()
+	if state != "" {
+		resultErr.Response.State(r.State())
+	}
+	return resultErr
+}
+
+// requestResultError returns the error result when the request cannot be resolved.
+// The error cannot be redirected because the redirect URI is unknown.
+func (h *AuthorizationHandler) requestResultError(err error) httputil.Result {
+	var oauthError *protocol.OAuthProtocolError
+	if errors.As(err, &oauthError) {
+		return authorizationResultError{
+			Response: oauthError.Response,
 		}
-		if errors.As(err, &oauthError) {
-			resultErr.Response = oauthError.Response
-		} else {
-			h.Logger.WithError(err).Error("authz handler failed")
-			resultErr.Response = protocol.NewErrorResponse("server_error", "internal server error")
-			resultErr.InternalError = true
+	}
+
+	h.Logger.WithError(err).Error("authz handler failed")
+	return authorizationResultError{
+		Response:      protocol.NewErrorResponse("server_error", "internal server error"),
+		InternalError: true,
+	}
+}
+
+// resolvePushedAuthorizationRequest resolves the authorization request
+// referenced by request_uri. Parameters other than client_id and request_uri
+// in the request are ignored. See RFC9126 section 4.
+//
+// The pushed request is marked as used at the authorization endpoint,
+// and is deleted when the request is resolved again after the end-user
+// finishes the interaction in the web app.
+func (h *AuthorizationHandler) resolvePushedAuthorizationRequest(
+	client *config.OAuthClientConfig,
+	r protocol.AuthorizationRequest,
+	fromWebApp bool,
+) (protocol.AuthorizationRequest, error) {
+	requestURI := r.RequestURI()
+	if requestURI == "" {
+		if client.RequirePushedAuthorizationRequests {
+			return nil, protocol.NewError("invalid_request", "pushed authorization request is required")
+		}
+		return r, nil
+	}
+
+	errInvalidRequestURI := protocol.NewError("invalid_request_uri", "invalid request_uri")
+
+	par, err := h.PushedAuthorizationRequests.GetPushedAuthorizationRequest(oauth.HashToken(requestURI))
+	if errors.Is(err, oauth.ErrGrantNotFound) {
+		return nil, errInvalidRequestURI
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := h.Clock.NowUTC()
+	if par.ClientID != client.ClientID || now.After(par.ExpireAt) {
+		return nil, errInvalidRequestURI
+	}
+
+	if fromWebApp {
+		if !par.Used {
+			return nil, errInvalidRequestURI
 		}
-		state := r.State()
-		if state != "" {
-			resultErr.Response.State(r.State())
+		err = h.PushedAuthorizationRequests.DeletePushedAuthorizationRequest(par)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if par.Used {
+			return nil, errInvalidRequestURI
+		}
+		par.Used = true
+		par.ExpireAt = now.Add(pushedAuthorizationRequestUsedValidDuration)
+		err = h.PushedAuthorizationRequests.UpdatePushedAuthorizationRequest(par)
+		if err != nil {
+			return nil, err
 		}
-		result = resultErr
 	}
 
-	return result
+	resolved := protocol.AuthorizationRequest{}
+	for k, v := range par.Parameters {
+		resolved[k] = v
+	}
+	resolved["request_uri"] = requestURI
+	return resolved, nil
 }
 
 func (h *AuthorizationHandler) doHandle(
@@ -173,7 +262,7 @@ func (h *AuthorizationHandler) doHandle(
 	client *config.OAuthClientConfig,
 	r protocol.AuthorizationRequest,
 ) (httputil.Result, error) {
-	if err := h.validateRequest(client, r); err != nil {
+	if err := validateAuthorizationRequest(client, r); err != nil {
 		return nil, err
 	}
 
@@ -268,10 +357,33 @@ func (h *AuthorizationHandler) doHandle(
 
 	authenticationInfo := idpSession.GetAuthenticationInfo()
 
-	return h.finish(redirectURI, r, idpSession.SessionID(), authenticationInfo, idTokenHintSID)
+	return h.finish(redirectURI, client, r, idpSession.SessionID(), authenticationInfo, idTokenHintSID)
 }
 
 func (h *AuthorizationHandler) finish(
+	redirectURI *url.URL,
+	client *config.OAuthClientConfig,
+	r protocol.AuthorizationRequest,
+	idpSessionID string,
+	authenticationInfo authenticationinfo.T,
+	idTokenHintSID string,
+) (httputil.Result, error) {
+	consentRequired, err := isConsentRequired(h.Authorizations, client, authenticationInfo.UserID, r.Scope(), r.Prompt())
+	if err != nil {
+		return nil, err
+	}
+
+	if consentRequired {
+		if slice.ContainsString(r.Prompt(), "none") {
+			return nil, protocol.NewError("consent_required", "consent required")
+		}
+		return h.requestConsent(redirectURI, client, r, idpSessionID, authenticationInfo, idTokenHintSID)
+	}
+
+	return h.issue(redirectURI, r, idpSessionID, authenticationInfo, idTokenHintSID)
+}
+
+func (h *AuthorizationHandler) issue(
 	redirectURI *url.URL,
 	r protocol.AuthorizationRequest,
 	idpSessionID string,
@@ -324,7 +436,7 @@ func (h *AuthorizationHandler) doHandleFromWebApp(
 	r protocol.AuthorizationRequest,
 	req *http.Request,
 ) (httputil.Result, error) {
-	if err := h.validateRequest(client, r); err != nil {
+	if err := validateAuthorizationRequest(client, r); err != nil {
 		return nil, err
 	}
 
@@ -359,10 +471,10 @@ func (h *AuthorizationHandler) doHandleFromWebApp(
 	}
 
 	authenticationInfo := entry.T
-	return h.finish(redirectURI, r, idpSessionID, authenticationInfo, idTokenHintSID)
+	return h.finish(redirectURI, client, r, idpSessionID, authenticationInfo, idTokenHintSID)
 }
 
-func (h *AuthorizationHandler) validateRequest(
+func validateAuthorizationRequest(
 	client *config.OAuthClientConfig,
 	r protocol.AuthorizationRequest,
 ) error {
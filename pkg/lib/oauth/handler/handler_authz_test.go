@@ -51,6 +51,8 @@ func TestAuthorizationHandler(t *testing.T) {
 		clock := clock.NewMockClockAt("2020-02-01T00:00:00Z")
 		authzStore := &mockAuthzStore{}
 		codeGrantStore := &mockCodeGrantStore{}
+		parStore := &mockPushedAuthorizationRequestStore{}
+		consentRequestStore := &mockConsentRequestStore{}
 		authenticationInfoService := &mockAuthenticationInfoService{}
 		cookieManager := &mockCookieManager{}
 
@@ -62,17 +64,22 @@ func TestAuthorizationHandler(t *testing.T) {
 				PublicOrigin: "http://accounts.example.com",
 			},
 
-			Authorizations:            authzStore,
-			CodeGrants:                codeGrantStore,
-			OAuthURLs:                 mockURLsProvider{},
-			WebAppURLs:                mockURLsProvider{},
-			ValidateScopes:            func(*config.OAuthClientConfig, []string) error { return nil },
-			CodeGenerator:             func() string { return "authz-code" },
-			Clock:                     clock,
-			AuthenticationInfoService: authenticationInfoService,
-			Cookies:                   cookieManager,
+			Authorizations:              authzStore,
+			CodeGrants:                  codeGrantStore,
+			PushedAuthorizationRequests: parStore,
+			ConsentRequests:             consentRequestStore,
+			OAuthURLs:                   mockURLsProvider{},
+			WebAppURLs:                  mockURLsProvider{},
+			ValidateScopes:              func(*config.OAuthClientConfig, []string) error { return nil },
+			CodeGenerator:               func() string { return "authz-code" },
+			Clock:                       clock,
+			AuthenticationInfoService:   authenticationInfoService,
+			Cookies:                     cookieManager,
 		}
 		handle := func(r protocol.AuthorizationRequest) *httptest.ResponseRecorder {
+			for i := range h.Config.Clients {
+				h.Config.Clients[i].SetDefaults()
+			}
 			result := h.Handle(r)
 			req, _ := http.NewRequest("GET", "/authorize", nil)
 			resp := httptest.NewRecorder()
@@ -313,6 +320,113 @@ func TestAuthorizationHandler(t *testing.T) {
 				})
 			})
 		})
+		Convey("pushed authorization request", func() {
+			h.Config.Clients = []config.OAuthClientConfig{{
+				ClientID:     "client-id",
+				RedirectURIs: []string{"https://example.com/"},
+			}}
+			requestURI := oauth.RequestURIPrefix + "request-uri"
+			parStore.requests = []oauth.PushedAuthorizationRequest{{
+				AppID:          "app-id",
+				ClientID:       "client-id",
+				CreatedAt:      time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+				ExpireAt:       time.Date(2020, 2, 1, 0, 5, 0, 0, time.UTC),
+				RequestURIHash: oauth.HashToken(requestURI),
+				Parameters: map[string]string{
+					"client_id":             "client-id",
+					"response_type":         "code",
+					"scope":                 "openid",
+					"code_challenge_method": "S256",
+					"code_challenge":        "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+				},
+			}}
+
+			Convey("require pushed authorization request", func() {
+				h.Config.Clients[0].RequirePushedAuthorizationRequests = true
+				resp := handle(protocol.AuthorizationRequest{
+					"client_id":             "client-id",
+					"response_type":         "code",
+					"scope":                 "openid",
+					"code_challenge_method": "S256",
+					"code_challenge":        "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+				})
+				So(resp.Result().StatusCode, ShouldEqual, 400)
+				So(resp.Body.String(), ShouldEqual,
+					"Invalid OAuth authorization request:\n"+
+						"error: invalid_request\n"+
+						"error_description: pushed authorization request is required\n")
+			})
+			Convey("unknown request_uri", func() {
+				resp := handle(protocol.AuthorizationRequest{
+					"client_id":   "client-id",
+					"request_uri": oauth.RequestURIPrefix + "unknown",
+				})
+				So(resp.Result().StatusCode, ShouldEqual, 400)
+				So(resp.Body.String(), ShouldEqual,
+					"Invalid OAuth authorization request:\n"+
+						"error: invalid_request_uri\n"+
+						"error_description: invalid request_uri\n")
+			})
+			Convey("request_uri of another client", func() {
+				h.Config.Clients = append(h.Config.Clients, config.OAuthClientConfig{
+					ClientID:     "another-client-id",
+					RedirectURIs: []string{"https://example.com/"},
+				})
+				resp := handle(protocol.AuthorizationRequest{
+					"client_id":   "another-client-id",
+					"request_uri": requestURI,
+				})
+				So(resp.Result().StatusCode, ShouldEqual, 400)
+				So(resp.Body.String(), ShouldEqual,
+					"Invalid OAuth authorization request:\n"+
+						"error: invalid_request_uri\n"+
+						"error_description: invalid request_uri\n")
+			})
+			Convey("expired request_uri", func() {
+				clock.AdvanceSeconds(301)
+				resp := handle(protocol.AuthorizationRequest{
+					"client_id":   "client-id",
+					"request_uri": requestURI,
+				})
+				So(resp.Result().StatusCode, ShouldEqual, 400)
+				So(resp.Body.String(), ShouldEqual,
+					"Invalid OAuth authorization request:\n"+
+						"error: invalid_request_uri\n"+
+						"error_description: invalid request_uri\n")
+			})
+			Convey("resolve request_uri", func() {
+				h.Config.Clients[0].RequirePushedAuthorizationRequests = true
+				var scopes []string
+				h.ValidateScopes = func(client *config.OAuthClientConfig, s []string) error {
+					scopes = s
+					return nil
+				}
+				resp := handle(protocol.AuthorizationRequest{
+					"client_id":   "client-id",
+					"request_uri": requestURI,
+					"scope":       "ignored",
+				})
+				So(resp.Result().StatusCode, ShouldEqual, 302)
+				So(redirection(resp), ShouldEqual, "https://auth/authenticate")
+				So(scopes, ShouldResemble, []string{"openid"})
+
+				So(parStore.requests, ShouldHaveLength, 1)
+				So(parStore.requests[0].Used, ShouldBeTrue)
+				So(parStore.requests[0].ExpireAt, ShouldEqual, time.Date(2020, 2, 1, 0, 20, 0, 0, time.UTC))
+
+				Convey("request_uri cannot be reused", func() {
+					resp := handle(protocol.AuthorizationRequest{
+						"client_id":   "client-id",
+						"request_uri": requestURI,
+					})
+					So(resp.Result().StatusCode, ShouldEqual, 400)
+					So(resp.Body.String(), ShouldEqual,
+						"Invalid OAuth authorization request:\n"+
+							"error: invalid_request_uri\n"+
+							"error_description: invalid request_uri\n")
+				})
+			})
+		})
 		Convey("none response type", func() {
 			h.Config.Clients = []config.OAuthClientConfig{{
 				ClientID:      "client-id",
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/util/duration"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/slice"
+)
+
+const ConsentRequestValidDuration = duration.UserInteraction
+
+// Consent is the consent request shown to the end-user.
+type Consent struct {
+	ClientName string
+	ClientURI  string
+	// NewScopes are the requested scopes that are not granted yet.
+	NewScopes []string
+	// GrantedScopes are the requested scopes that are granted already.
+	GrantedScopes []string
+}
+
+type ConsentURLProvider interface {
+	ConsentURL(code string) *url.URL
+}
+
+// isConsentRequired reports whether the end-user must grant the requested scopes.
+// First-party clients are always trusted.
+func isConsentRequired(
+	authorizations oauth.AuthorizationStore,
+	client *config.OAuthClientConfig,
+	userID string,
+	scopes []string,
+	prompt []string,
+) (bool, error) {
+	if *client.IsFirstParty {
+		return false, nil
+	}
+
+	if slice.ContainsString(prompt, "consent") {
+		return true, nil
+	}
+
+	authz, err := authorizations.Get(userID, client.ClientID)
+	if errors.Is(err, oauth.ErrAuthorizationNotFound) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return !authz.IsAuthorized(scopes), nil
+}
+
+// requestConsent stores the consent request, and returns the URL of the consent page.
+func requestConsent(
+	consentRequests oauth.ConsentRequestStore,
+	urls ConsentURLProvider,
+	codeGenerator TokenGenerator,
+	now time.Time,
+	consentRequest *oauth.ConsentRequest,
+) (*url.URL, error) {
+	code := codeGenerator()
+
+	consentRequest.CreatedAt = now
+	consentRequest.ExpireAt = now.Add(ConsentRequestValidDuration)
+	consentRequest.CodeHash = oauth.HashToken(code)
+
+	err := consentRequests.CreateConsentRequest(consentRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return urls.ConsentURL(code), nil
+}
+
+func (h *AuthorizationHandler) requestConsent(
+	redirectURI *url.URL,
+	client *config.OAuthClientConfig,
+	r protocol.AuthorizationRequest,
+	idpSessionID string,
+	authenticationInfo authenticationinfo.T,
+	idTokenHintSID string,
+) (httputil.Result, error) {
+	parameters := make(map[string]string)
+	for k, v := range r {
+		parameters[k] = v
+	}
+
+	consentURL, err := requestConsent(h.ConsentRequests, h.OAuthURLs, h.CodeGenerator, h.Clock.NowUTC(), &oauth.ConsentRequest{
+		AppID:              string(h.AppID),
+		ClientID:           client.ClientID,
+		Parameters:         parameters,
+		RedirectURI:        redirectURI.String(),
+		IDPSessionID:       idpSessionID,
+		AuthenticationInfo: authenticationInfo,
+		IDTokenHintSID:     idTokenHintSID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &httputil.ResultRedirect{
+		URL: consentURL.String(),
+	}, nil
+}
+
+// GetConsent returns the consent request to be shown to the end-user.
+func (h *AuthorizationHandler) GetConsent(code string) (*Consent, error) {
+	consentRequest, client, err := h.getConsentRequest(code)
+	if err != nil {
+		return nil, err
+	}
+
+	r := protocol.AuthorizationRequest(consentRequest.Parameters)
+
+	var grantedScopes []string
+	authz, err := h.Authorizations.Get(consentRequest.AuthenticationInfo.UserID, client.ClientID)
+	if err == nil {
+		grantedScopes = authz.Scopes
+	} else if !errors.Is(err, oauth.ErrAuthorizationNotFound) {
+		return nil, err
+	}
+
+	consent := &Consent{
+		ClientName: client.Name,
+		ClientURI:  client.ClientURI,
+	}
+	if consent.ClientName == "" {
+		consent.ClientName = client.ClientID
+	}
+	for _, s := range r.Scope() {
+		if slice.ContainsString(grantedScopes, s) {
+			consent.GrantedScopes = append(consent.GrantedScopes, s)
+		} else {
+			consent.NewScopes = append(consent.NewScopes, s)
+		}
+	}
+
+	return consent, nil
+}
+
+// HandleConsent finishes the authorization request with the decision of the end-user.
+// The requested scopes are granted only if the end-user consents.
+func (h *AuthorizationHandler) HandleConsent(code string, granted bool) (httputil.Result, error) {
+	consentRequest, _, err := h.getConsentRequest(code)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.ConsentRequests.DeleteConsentRequest(consentRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if consentRequest.DeviceUserCode != "" {
+		return h.handleDeviceConsent(consentRequest, granted)
+	}
+
+	r := protocol.AuthorizationRequest(consentRequest.Parameters)
+	redirectURI, err := url.Parse(consentRequest.RedirectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if !granted {
+		return h.redirectResultError(
+			redirectURI,
+			r,
+			protocol.NewError("access_denied", "the end-user denied the consent"),
+		), nil
+	}
+
+	result, err := h.issue(
+		redirectURI,
+		r,
+		consentRequest.IDPSessionID,
+		consentRequest.AuthenticationInfo,
+		consentRequest.IDTokenHintSID,
+	)
+	if err != nil {
+		result = h.redirectResultError(redirectURI, r, err)
+	}
+
+	return result, nil
+}
+
+// handleDeviceConsent approves or denies the device code grant with the decision of the end-user.
+func (h *AuthorizationHandler) handleDeviceConsent(consentRequest *oauth.ConsentRequest, granted bool) (httputil.Result, error) {
+	grant, err := getPendingDeviceCodeGrant(h.DeviceCodeGrants, h.Clock.NowUTC(), consentRequest.DeviceUserCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !granted {
+		grant.Status = oauth.DeviceCodeGrantStatusDenied
+		err = h.DeviceCodeGrants.UpdateDeviceCodeGrant(grant)
+		if err != nil {
+			return nil, err
+		}
+		// The device is notified with access_denied when it polls the token endpoint.
+		return &httputil.ResultRedirect{
+			URL: h.DeviceURLs.DeviceVerificationURL().String(),
+		}, nil
+	}
+
+	err = approveDeviceCodeGrant(
+		h.Authorizations,
+		h.DeviceCodeGrants,
+		h.Clock.NowUTC(),
+		h.AppID,
+		grant,
+		consentRequest.IDPSessionID,
+		consentRequest.IDTokenHintSID,
+		consentRequest.AuthenticationInfo,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httputil.ResultRedirect{
+		URL: h.DeviceURLs.DeviceSuccessURL().String(),
+	}, nil
+}
+
+func (h *AuthorizationHandler) getConsentRequest(code string) (*oauth.ConsentRequest, *config.OAuthClientConfig, error) {
+	consentRequest, err := h.ConsentRequests.GetConsentRequest(oauth.HashToken(code))
+	if errors.Is(err, oauth.ErrGrantNotFound) {
+		return nil, nil, oauth.ErrInvalidConsentRequest
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if h.Clock.NowUTC().After(consentRequest.ExpireAt) {
+		return nil, nil, oauth.ErrInvalidConsentRequest
+	}
+
+	// The consent request must be answered in the same session.
+	if consentRequest.IDPSessionID != "" {
+		s := session.GetSession(h.Context)
+		if s == nil || s.SessionID() != consentRequest.IDPSessionID {
+			return nil, nil, oauth.ErrInvalidConsentRequest
+		}
+	}
+
+	client, ok := h.Config.GetClient(consentRequest.ClientID)
+	if !ok {
+		return nil, nil, oauth.ErrInvalidConsentRequest
+	}
+
+	return consentRequest, client, nil
+}
@@ -0,0 +1,183 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/handler"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	sessiontest "github.com/authgear/authgear-server/pkg/lib/session/test"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+)
+
+func TestAuthorizationHandlerConsent(t *testing.T) {
+	Convey("Authorization handler consent", t, func() {
+		clock := clock.NewMockClockAt("2020-02-01T00:00:00Z")
+		authzStore := &mockAuthzStore{}
+		codeGrantStore := &mockCodeGrantStore{}
+		consentRequestStore := &mockConsentRequestStore{}
+		authenticationInfoService := &mockAuthenticationInfoService{
+			Entry: &authenticationinfo.Entry{
+				T: authenticationinfo.T{UserID: "user-id"},
+			},
+		}
+
+		isFirstParty := false
+		h := &handler.AuthorizationHandler{
+			Context: sessiontest.NewMockSession().
+				SetUserID("user-id").
+				SetSessionID("session-id").
+				ToContext(context.Background()),
+			AppID: "app-id",
+			Config: &config.OAuthConfig{
+				Clients: []config.OAuthClientConfig{{
+					ClientID:      "client-id",
+					Name:          "Third Party",
+					RedirectURIs:  []string{"https://example.com/"},
+					ResponseTypes: []string{"none"},
+					IsFirstParty:  &isFirstParty,
+				}},
+			},
+			HTTPConfig: &config.HTTPConfig{
+				PublicOrigin: "http://accounts.example.com",
+			},
+
+			Authorizations:            authzStore,
+			CodeGrants:                codeGrantStore,
+			ConsentRequests:           consentRequestStore,
+			OAuthURLs:                 mockURLsProvider{},
+			WebAppURLs:                mockURLsProvider{},
+			ValidateScopes:            func(*config.OAuthClientConfig, []string) error { return nil },
+			CodeGenerator:             func() string { return "consent-code" },
+			Clock:                     clock,
+			AuthenticationInfoService: authenticationInfoService,
+			Cookies:                   &mockCookieManager{},
+		}
+		writeResponse := func(result httputil.Result) *httptest.ResponseRecorder {
+			req, _ := http.NewRequest("GET", "/", nil)
+			resp := httptest.NewRecorder()
+			result.WriteResponse(resp, req)
+			return resp
+		}
+		existingAuthz := oauth.Authorization{
+			ID:        "authz-id",
+			AppID:     "app-id",
+			ClientID:  "client-id",
+			UserID:    "user-id",
+			CreatedAt: time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+			Scopes:    []string{"openid"},
+		}
+
+		Convey("prompt=none requires existing consent", func() {
+			resp := writeResponse(h.Handle(protocol.AuthorizationRequest{
+				"client_id":     "client-id",
+				"response_type": "none",
+				"scope":         "openid",
+				"prompt":        "none",
+			}))
+			So(resp.Body.String(), ShouldContainSubstring, "https://example.com/?error=consent_required")
+			So(authzStore.authzs, ShouldBeEmpty)
+		})
+
+		Convey("prompt=none with granted scopes", func() {
+			authzStore.authzs = []oauth.Authorization{existingAuthz}
+			resp := writeResponse(h.Handle(protocol.AuthorizationRequest{
+				"client_id":     "client-id",
+				"response_type": "none",
+				"scope":         "openid",
+				"prompt":        "none",
+			}))
+			So(resp.Body.String(), ShouldContainSubstring, `content="0;url=https://example.com/"`)
+		})
+
+		Convey("prompt=none with new scopes", func() {
+			authzStore.authzs = []oauth.Authorization{existingAuthz}
+			resp := writeResponse(h.Handle(protocol.AuthorizationRequest{
+				"client_id":     "client-id",
+				"response_type": "none",
+				"scope":         "openid offline_access",
+				"prompt":        "none",
+			}))
+			So(resp.Body.String(), ShouldContainSubstring, "https://example.com/?error=consent_required")
+			So(authzStore.authzs[0].Scopes, ShouldResemble, []string{"openid"})
+		})
+
+		Convey("request consent from web app", func() {
+			authzStore.authzs = []oauth.Authorization{existingAuthz}
+			req, _ := http.NewRequest("GET", "/oauth2/_from_webapp", nil)
+			resp := writeResponse(h.HandleFromWebApp(protocol.AuthorizationRequest{
+				"client_id":     "client-id",
+				"response_type": "none",
+				"scope":         "openid offline_access",
+				"state":         "my-state",
+			}, req))
+			So(resp.Result().StatusCode, ShouldEqual, 302)
+			So(resp.Header().Get("Location"), ShouldEqual, "https://auth/consent?code=consent-code")
+			So(consentRequestStore.requests, ShouldHaveLength, 1)
+			So(authzStore.authzs[0].Scopes, ShouldResemble, []string{"openid"})
+
+			consent, err := h.GetConsent("consent-code")
+			So(err, ShouldBeNil)
+			So(consent, ShouldResemble, &handler.Consent{
+				ClientName:    "Third Party",
+				NewScopes:     []string{"offline_access"},
+				GrantedScopes: []string{"openid"},
+			})
+
+			Convey("grant", func() {
+				result, err := h.HandleConsent("consent-code", true)
+				So(err, ShouldBeNil)
+				resp := writeResponse(result)
+				So(resp.Body.String(), ShouldContainSubstring, `content="0;url=https://example.com/?state=my-state"`)
+				So(authzStore.authzs[0].Scopes, ShouldResemble, []string{"openid", "offline_access"})
+				So(consentRequestStore.requests, ShouldBeEmpty)
+			})
+
+			Convey("deny", func() {
+				result, err := h.HandleConsent("consent-code", false)
+				So(err, ShouldBeNil)
+				resp := writeResponse(result)
+				So(resp.Body.String(), ShouldContainSubstring, "https://example.com/?error=access_denied")
+				So(authzStore.authzs[0].Scopes, ShouldResemble, []string{"openid"})
+				So(consentRequestStore.requests, ShouldBeEmpty)
+			})
+
+			Convey("reject other session", func() {
+				h.Context = sessiontest.NewMockSession().
+					SetUserID("user-id").
+					SetSessionID("other-session-id").
+					ToContext(context.Background())
+				_, err := h.HandleConsent("consent-code", true)
+				So(err, ShouldBeError, "invalid consent request")
+			})
+
+			Convey("reject expired request", func() {
+				clock.AdvanceSeconds(1201)
+				_, err := h.HandleConsent("consent-code", true)
+				So(err, ShouldBeError, "invalid consent request")
+			})
+		})
+
+		Convey("first-party clients are trusted", func() {
+			isFirstParty = true
+			resp := writeResponse(h.Handle(protocol.AuthorizationRequest{
+				"client_id":     "client-id",
+				"response_type": "none",
+				"scope":         "openid",
+				"prompt":        "none",
+			}))
+			So(resp.Body.String(), ShouldContainSubstring, `content="0;url=https://example.com/"`)
+			So(authzStore.authzs, ShouldHaveLength, 1)
+		})
+	})
+}
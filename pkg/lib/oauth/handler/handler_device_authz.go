@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/auth/webapp"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/oidc"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
+	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/duration"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/log"
+	"github.com/authgear/authgear-server/pkg/util/slice"
+)
+
+const DeviceCodeGrantValidDuration = duration.UserInteraction
+
+// DeviceCodePollingInterval is the minimum amount of time that the client
+// should wait between polling requests to the token endpoint.
+const DeviceCodePollingInterval = 5 * time.Second
+
+// deviceUserCodeMaxAttempts is the number of user codes to generate
+// before giving up, in case the generated user code is taken.
+const deviceUserCodeMaxAttempts = 5
+
+type DeviceURLProvider interface {
+	DeviceVerificationURL() *url.URL
+	DeviceVerificationCompleteURL(userCode string) *url.URL
+	DeviceApprovalURL(userCode string) *url.URL
+	DeviceSuccessURL() *url.URL
+	ConsentURL(code string) *url.URL
+}
+
+type DeviceAuthorizationRateLimiter interface {
+	TakeToken(bucket ratelimit.Bucket) error
+}
+
+// DeviceVerification is the pending device code grant to be approved by the end-user.
+type DeviceVerification struct {
+	ClientID   string
+	ClientName string
+	UserCode   string
+}
+
+type DeviceAuthorizationHandlerLogger struct{ *log.Logger }
+
+func NewDeviceAuthorizationHandlerLogger(lf *log.Factory) DeviceAuthorizationHandlerLogger {
+	return DeviceAuthorizationHandlerLogger{lf.New("oauth-device-authz")}
+}
+
+type DeviceAuthorizationHandler struct {
+	Context       context.Context
+	AppID         config.AppID
+	Config        *config.OAuthConfig
+	ClientSecrets *config.OAuthClientSecrets
+	Logger        DeviceAuthorizationHandlerLogger
+	TrustProxy    config.TrustProxy
+
+	Authorizations            oauth.AuthorizationStore
+	DeviceCodeGrants          oauth.DeviceCodeGrantStore
+	ConsentRequests           oauth.ConsentRequestStore
+	DeviceURLs                DeviceURLProvider
+	WebAppURLs                WebAppAuthenticateURLProvider
+	ValidateScopes            ScopesValidator
+	CodeGenerator             TokenGenerator
+	AuthenticationInfoService AuthenticationInfoService
+	Cookies                   CookieManager
+	RateLimiter               DeviceAuthorizationRateLimiter
+	Clock                     clock.Clock
+}
+
+func (h *DeviceAuthorizationHandler) Handle(req *http.Request, r protocol.DeviceAuthorizationRequest) httputil.Result {
+	result, err := h.doHandle(req, r)
+	if err != nil {
+		var oauthError *protocol.OAuthProtocolError
+		resultErr := tokenResultError{}
+		if errors.As(err, &oauthError) {
+			resultErr.StatusCode = oauthError.StatusCode
+			resultErr.Response = oauthError.Response
+		} else {
+			h.Logger.WithError(err).Error("device authz handler failed")
+			resultErr.Response = protocol.NewErrorResponse("server_error", "internal server error")
+			resultErr.InternalError = true
+		}
+		result = resultErr
+	}
+
+	return result
+}
+
+func (h *DeviceAuthorizationHandler) doHandle(req *http.Request, r protocol.DeviceAuthorizationRequest) (httputil.Result, error) {
+	// The client authenticates in the same way as at the token endpoint.
+	// See RFC8628 section 3.1.
+	cred, err := parseClientCredentials(req, protocol.TokenRequest(r))
+	if err != nil {
+		return nil, err
+	}
+
+	client, ok := h.Config.GetClient(r.ClientID())
+	if !ok {
+		return nil, protocol.NewError("invalid_client", "invalid client ID")
+	}
+
+	err = authenticateClient(h.ClientSecrets, client, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slice.ContainsString(client.GrantTypes, oauth.DeviceCodeGrantType) {
+		return nil, protocol.NewError("unauthorized_client", "device code grant is not allowed for this client")
+	}
+
+	err = h.ValidateScopes(client, r.Scope())
+	if err != nil {
+		return nil, err
+	}
+
+	deviceCode := h.CodeGenerator()
+	now := h.Clock.NowUTC()
+	grant := &oauth.DeviceCodeGrant{
+		AppID:    string(h.AppID),
+		ClientID: client.ClientID,
+		Scopes:   r.Scope(),
+
+		CreatedAt:      now,
+		ExpireAt:       now.Add(DeviceCodeGrantValidDuration),
+		DeviceCodeHash: oauth.HashToken(deviceCode),
+
+		Status: oauth.DeviceCodeGrantStatusPending,
+	}
+
+	// User codes are short, so they may collide with outstanding grants.
+	for i := 0; i < deviceUserCodeMaxAttempts; i++ {
+		grant.UserCode = oauth.GenerateUserCode()
+		err = h.DeviceCodeGrants.CreateDeviceCodeGrant(grant)
+		if !errors.Is(err, oauth.ErrUserCodeAlreadyExists) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userCode := oauth.FormatUserCode(grant.UserCode)
+	resp := protocol.DeviceAuthorizationResponse{}
+	resp.DeviceCode(deviceCode)
+	resp.UserCode(userCode)
+	resp.VerificationURI(h.DeviceURLs.DeviceVerificationURL().String())
+	resp.VerificationURIComplete(h.DeviceURLs.DeviceVerificationCompleteURL(userCode).String())
+	resp.ExpiresIn(int(DeviceCodeGrantValidDuration.Seconds()))
+	resp.Interval(int(DeviceCodePollingInterval.Seconds()))
+
+	return tokenResultOK{Response: protocol.TokenResponse(resp)}, nil
+}
+
+// StartVerification checks the user code entered by the end-user,
+// and redirects the end-user to authenticate in the web app.
+func (h *DeviceAuthorizationHandler) StartVerification(req *http.Request, userCode string, uiLocales string) (httputil.Result, error) {
+	ip := httputil.GetIP(req, bool(h.TrustProxy))
+	err := h.RateLimiter.TakeToken(oauth.DeviceVerifyUserCodeRateLimitBucket(ip))
+	if err != nil {
+		return nil, err
+	}
+
+	grant, err := h.getPendingGrant(userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.WebAppURLs.AuthenticateURL(webapp.AuthenticateURLOptions{
+		SessionOptions: webapp.SessionOptions{
+			ClientID:    grant.ClientID,
+			RedirectURI: h.DeviceURLs.DeviceApprovalURL(oauth.FormatUserCode(grant.UserCode)).String(),
+		},
+		UILocales: uiLocales,
+	})
+}
+
+// GetVerification returns the pending device code grant of the user code,
+// so that the end-user can confirm the client before approving it.
+func (h *DeviceAuthorizationHandler) GetVerification(userCode string) (*DeviceVerification, error) {
+	grant, err := h.getPendingGrant(userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	client, ok := h.Config.GetClient(grant.ClientID)
+	if !ok {
+		return nil, oauth.ErrInvalidUserCode
+	}
+
+	verification := &DeviceVerification{
+		ClientID:   client.ClientID,
+		ClientName: client.Name,
+		UserCode:   oauth.FormatUserCode(grant.UserCode),
+	}
+	if verification.ClientName == "" {
+		verification.ClientName = client.ClientID
+	}
+	return verification, nil
+}
+
+// FinishVerification approves the device code grant with the authentication
+// result of the web app, and returns the URL to redirect the end-user to.
+// If the end-user must consent to the client first, the grant is approved
+// after consent instead, and the URL is the consent page.
+// The returned cookie must be written to the response.
+func (h *DeviceAuthorizationHandler) FinishVerification(req *http.Request, userCode string) (*url.URL, *http.Cookie, error) {
+	grant, err := h.getPendingGrant(userCode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, ok := h.Config.GetClient(grant.ClientID)
+	if !ok {
+		return nil, nil, oauth.ErrInvalidUserCode
+	}
+
+	cookie, err := h.Cookies.GetCookie(req, authenticationinfo.CookieDef)
+	if err != nil {
+		return nil, nil, apierrors.NewUnauthorized("authentication required")
+	}
+
+	entry, err := h.AuthenticationInfoService.Consume(cookie.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+	authenticationInfo := entry.T
+	clearCookie := h.Cookies.ClearCookie(authenticationinfo.CookieDef)
+
+	var idpSessionID string
+	var sid string
+	if s := session.GetSession(h.Context); s != nil && s.SessionType() == session.TypeIdentityProvider {
+		idpSessionID = s.SessionID()
+		sid = oidc.EncodeSID(s)
+	}
+
+	consentRequired, err := isConsentRequired(h.Authorizations, client, authenticationInfo.UserID, grant.Scopes, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if consentRequired {
+		consentURL, err := requestConsent(h.ConsentRequests, h.DeviceURLs, h.CodeGenerator, h.Clock.NowUTC(), &oauth.ConsentRequest{
+			AppID:              string(h.AppID),
+			ClientID:           client.ClientID,
+			Parameters:         map[string]string{"client_id": client.ClientID, "scope": strings.Join(grant.Scopes, " ")},
+			IDPSessionID:       idpSessionID,
+			AuthenticationInfo: authenticationInfo,
+			IDTokenHintSID:     sid,
+			DeviceUserCode:     grant.UserCode,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return consentURL, clearCookie, nil
+	}
+
+	err = approveDeviceCodeGrant(
+		h.Authorizations,
+		h.DeviceCodeGrants,
+		h.Clock.NowUTC(),
+		h.AppID,
+		grant,
+		idpSessionID,
+		sid,
+		authenticationInfo,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return h.DeviceURLs.DeviceSuccessURL(), clearCookie, nil
+}
+
+func (h *DeviceAuthorizationHandler) getPendingGrant(userCode string) (*oauth.DeviceCodeGrant, error) {
+	return getPendingDeviceCodeGrant(h.DeviceCodeGrants, h.Clock.NowUTC(), userCode)
+}
+
+func getPendingDeviceCodeGrant(grants oauth.DeviceCodeGrantStore, now time.Time, userCode string) (*oauth.DeviceCodeGrant, error) {
+	grant, err := grants.GetDeviceCodeGrantByUserCode(oauth.NormalizeUserCode(userCode))
+	if errors.Is(err, oauth.ErrGrantNotFound) {
+		return nil, oauth.ErrInvalidUserCode
+	} else if err != nil {
+		return nil, err
+	}
+
+	if now.After(grant.ExpireAt) {
+		return nil, oauth.ErrInvalidUserCode
+	}
+
+	if grant.Status != oauth.DeviceCodeGrantStatusPending {
+		return nil, oauth.ErrInvalidUserCode
+	}
+
+	return grant, nil
+}
+
+// approveDeviceCodeGrant grants the requested scopes to the client,
+// so that the device can obtain the tokens.
+func approveDeviceCodeGrant(
+	authorizations oauth.AuthorizationStore,
+	grants oauth.DeviceCodeGrantStore,
+	now time.Time,
+	appID config.AppID,
+	grant *oauth.DeviceCodeGrant,
+	idpSessionID string,
+	sid string,
+	authenticationInfo authenticationinfo.T,
+) error {
+	authz, err := checkAuthorization(
+		authorizations,
+		now,
+		appID,
+		grant.ClientID,
+		authenticationInfo.UserID,
+		grant.Scopes,
+	)
+	if err != nil {
+		return err
+	}
+
+	grant.Status = oauth.DeviceCodeGrantStatusApproved
+	grant.AuthorizationID = authz.ID
+	grant.IDPSessionID = idpSessionID
+	grant.SID = sid
+	grant.AuthenticationInfo = authenticationInfo
+
+	return grants.UpdateDeviceCodeGrant(grant)
+}
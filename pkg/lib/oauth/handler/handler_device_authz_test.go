@@ -0,0 +1,282 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/handler"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
+	sessiontest "github.com/authgear/authgear-server/pkg/lib/session/test"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+func TestDeviceAuthorizationHandler(t *testing.T) {
+	Convey("Device authorization handler", t, func() {
+		clock := clock.NewMockClockAt("2020-02-01T00:00:00Z")
+		deviceCodeGrantStore := &mockDeviceCodeGrantStore{}
+		rateLimiter := &mockRateLimiter{}
+
+		h := &handler.DeviceAuthorizationHandler{
+			AppID: "app-id",
+			Config: &config.OAuthConfig{
+				Clients: []config.OAuthClientConfig{{
+					ClientID:   "client-id",
+					GrantTypes: []string{oauth.DeviceCodeGrantType},
+				}},
+			},
+			Logger:           handler.DeviceAuthorizationHandlerLogger{log.Null},
+			DeviceCodeGrants: deviceCodeGrantStore,
+			DeviceURLs:       mockURLsProvider{},
+			WebAppURLs:       mockURLsProvider{},
+			ValidateScopes:   func(client *config.OAuthClientConfig, scopes []string) error { return nil },
+			CodeGenerator:    func() string { return "device-code" },
+			RateLimiter:      rateLimiter,
+			Clock:            clock,
+		}
+		handle := func() map[string]interface{} {
+			req, _ := http.NewRequest("POST", "/oauth2/device_authorization", nil)
+			resp := httptest.NewRecorder()
+			result := h.Handle(req, protocol.DeviceAuthorizationRequest{
+				"client_id": "client-id",
+				"scope":     "openid",
+			})
+			result.WriteResponse(resp, req)
+
+			var body map[string]interface{}
+			err := json.Unmarshal(resp.Body.Bytes(), &body)
+			So(err, ShouldBeNil)
+			return body
+		}
+
+		Convey("should create device code grant", func() {
+			body := handle()
+			So(deviceCodeGrantStore.grants, ShouldHaveLength, 1)
+			grant := deviceCodeGrantStore.grants[0]
+			So(grant.DeviceCodeHash, ShouldEqual, oauth.HashToken("device-code"))
+			So(grant.Status, ShouldEqual, oauth.DeviceCodeGrantStatusPending)
+
+			So(body["device_code"], ShouldEqual, "device-code")
+			So(body["user_code"], ShouldEqual, oauth.FormatUserCode(grant.UserCode))
+			So(body["verification_uri"], ShouldEqual, "https://auth/device")
+			So(body["interval"], ShouldEqual, 5)
+		})
+
+		Convey("should retry if user code is taken", func() {
+			deviceCodeGrantStore.conflicts = 2
+			body := handle()
+			So(deviceCodeGrantStore.grants, ShouldHaveLength, 1)
+			So(body["device_code"], ShouldEqual, "device-code")
+		})
+
+		Convey("should fail if user codes are always taken", func() {
+			deviceCodeGrantStore.conflicts = 100
+			body := handle()
+			So(deviceCodeGrantStore.grants, ShouldBeEmpty)
+			So(body["error"], ShouldEqual, "server_error")
+		})
+
+		Convey("should rate limit user code entry by IP", func() {
+			req, _ := http.NewRequest("POST", "/device", nil)
+			req.RemoteAddr = "192.0.2.1:12345"
+			for i := 0; i < 10; i++ {
+				_, err := h.StartVerification(req, "BCDF-GHJK", "")
+				So(err, ShouldBeError, oauth.ErrInvalidUserCode)
+			}
+			_, err := h.StartVerification(req, "BCDF-GHJK", "")
+			So(err, ShouldBeError, ratelimit.ErrTooManyRequests)
+
+			req.RemoteAddr = "192.0.2.2:12345"
+			_, err = h.StartVerification(req, "BCDF-GHJK", "")
+			So(err, ShouldBeError, oauth.ErrInvalidUserCode)
+		})
+
+		Convey("should get pending verification", func() {
+			deviceCodeGrantStore.grants = []oauth.DeviceCodeGrant{{
+				AppID:          "app-id",
+				ClientID:       "client-id",
+				ExpireAt:       clock.NowUTC().Add(handler.DeviceCodeGrantValidDuration),
+				DeviceCodeHash: "device-code-hash",
+				UserCode:       "BCDFGHJK",
+				Status:         oauth.DeviceCodeGrantStatusPending,
+			}}
+
+			req, _ := http.NewRequest("POST", "/device", nil)
+			req.RemoteAddr = "192.0.2.1:12345"
+			result, err := h.StartVerification(req, "bcdf-ghjk", "")
+			So(err, ShouldBeNil)
+			So(result, ShouldResemble, &httputil.ResultRedirect{URL: "https://auth/authenticate"})
+
+			verification, err := h.GetVerification("bcdf-ghjk")
+			So(err, ShouldBeNil)
+			So(verification, ShouldResemble, &handler.DeviceVerification{
+				ClientID:   "client-id",
+				ClientName: "client-id",
+				UserCode:   "BCDF-GHJK",
+			})
+
+			clock.AdvanceSeconds(int(handler.DeviceCodeGrantValidDuration.Seconds()) + 1)
+			_, err = h.GetVerification("bcdf-ghjk")
+			So(err, ShouldBeError, oauth.ErrInvalidUserCode)
+		})
+	})
+}
+
+func TestDeviceAuthorizationHandlerFinishVerification(t *testing.T) {
+	Convey("Device authorization handler FinishVerification", t, func() {
+		clock := clock.NewMockClockAt("2020-02-01T00:00:00Z")
+		authzStore := &mockAuthzStore{}
+		deviceCodeGrantStore := &mockDeviceCodeGrantStore{}
+		consentRequestStore := &mockConsentRequestStore{}
+		authenticationInfoService := &mockAuthenticationInfoService{
+			Entry: &authenticationinfo.Entry{
+				T: authenticationinfo.T{UserID: "user-id"},
+			},
+		}
+
+		isFirstParty := false
+		oauthConfig := &config.OAuthConfig{
+			Clients: []config.OAuthClientConfig{{
+				ClientID:     "client-id",
+				Name:         "Third Party",
+				GrantTypes:   []string{oauth.DeviceCodeGrantType},
+				IsFirstParty: &isFirstParty,
+			}},
+		}
+		ctx := sessiontest.NewMockSession().
+			SetUserID("user-id").
+			SetSessionID("session-id").
+			ToContext(context.Background())
+
+		h := &handler.DeviceAuthorizationHandler{
+			Context:                   ctx,
+			AppID:                     "app-id",
+			Config:                    oauthConfig,
+			Authorizations:            authzStore,
+			DeviceCodeGrants:          deviceCodeGrantStore,
+			ConsentRequests:           consentRequestStore,
+			DeviceURLs:                mockURLsProvider{},
+			WebAppURLs:                mockURLsProvider{},
+			CodeGenerator:             func() string { return "consent-code" },
+			AuthenticationInfoService: authenticationInfoService,
+			Cookies:                   &mockCookieManager{},
+			Clock:                     clock,
+		}
+		authzHandler := &handler.AuthorizationHandler{
+			Context:          ctx,
+			AppID:            "app-id",
+			Config:           oauthConfig,
+			Authorizations:   authzStore,
+			ConsentRequests:  consentRequestStore,
+			DeviceCodeGrants: deviceCodeGrantStore,
+			OAuthURLs:        mockURLsProvider{},
+			DeviceURLs:       mockURLsProvider{},
+			Clock:            clock,
+		}
+		writeResponse := func(result httputil.Result) *httptest.ResponseRecorder {
+			req, _ := http.NewRequest("GET", "/", nil)
+			resp := httptest.NewRecorder()
+			result.WriteResponse(resp, req)
+			return resp
+		}
+
+		deviceCodeGrantStore.grants = []oauth.DeviceCodeGrant{{
+			AppID:          "app-id",
+			ClientID:       "client-id",
+			Scopes:         []string{"openid", "offline_access"},
+			CreatedAt:      clock.NowUTC(),
+			ExpireAt:       clock.NowUTC().Add(handler.DeviceCodeGrantValidDuration),
+			DeviceCodeHash: "device-code-hash",
+			UserCode:       "BCDFGHJK",
+			Status:         oauth.DeviceCodeGrantStatusPending,
+		}}
+		req, _ := http.NewRequest("GET", "/device/approve?user_code=BCDF-GHJK", nil)
+
+		Convey("should approve for first-party client", func() {
+			isFirstParty = true
+
+			redirectURI, _, err := h.FinishVerification(req, "BCDF-GHJK")
+			So(err, ShouldBeNil)
+			So(redirectURI.String(), ShouldEqual, "https://auth/device/success")
+
+			So(deviceCodeGrantStore.grants[0].Status, ShouldEqual, oauth.DeviceCodeGrantStatusApproved)
+			So(authzStore.authzs, ShouldHaveLength, 1)
+			So(authzStore.authzs[0].Scopes, ShouldResemble, []string{"openid", "offline_access"})
+			So(consentRequestStore.requests, ShouldBeEmpty)
+		})
+
+		Convey("should approve for third-party client with authorized scopes", func() {
+			authzStore.authzs = []oauth.Authorization{{
+				ID:        "authz-id",
+				AppID:     "app-id",
+				ClientID:  "client-id",
+				UserID:    "user-id",
+				CreatedAt: time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+				Scopes:    []string{"openid", "offline_access"},
+			}}
+
+			redirectURI, _, err := h.FinishVerification(req, "BCDF-GHJK")
+			So(err, ShouldBeNil)
+			So(redirectURI.String(), ShouldEqual, "https://auth/device/success")
+			So(deviceCodeGrantStore.grants[0].Status, ShouldEqual, oauth.DeviceCodeGrantStatusApproved)
+			So(deviceCodeGrantStore.grants[0].AuthorizationID, ShouldEqual, "authz-id")
+		})
+
+		Convey("should request consent for third-party client", func() {
+			redirectURI, _, err := h.FinishVerification(req, "BCDF-GHJK")
+			So(err, ShouldBeNil)
+			So(redirectURI.String(), ShouldEqual, "https://auth/consent?code=consent-code")
+
+			So(deviceCodeGrantStore.grants[0].Status, ShouldEqual, oauth.DeviceCodeGrantStatusPending)
+			So(authzStore.authzs, ShouldBeEmpty)
+			So(consentRequestStore.requests, ShouldHaveLength, 1)
+			So(consentRequestStore.requests[0].DeviceUserCode, ShouldEqual, "BCDFGHJK")
+
+			consent, err := authzHandler.GetConsent("consent-code")
+			So(err, ShouldBeNil)
+			So(consent.ClientName, ShouldEqual, "Third Party")
+			So(consent.NewScopes, ShouldResemble, []string{"openid", "offline_access"})
+
+			Convey("should approve after consent", func() {
+				result, err := authzHandler.HandleConsent("consent-code", true)
+				So(err, ShouldBeNil)
+				resp := writeResponse(result)
+				So(resp.Header().Get("Location"), ShouldEqual, "https://auth/device/success")
+
+				So(deviceCodeGrantStore.grants[0].Status, ShouldEqual, oauth.DeviceCodeGrantStatusApproved)
+				So(deviceCodeGrantStore.grants[0].AuthenticationInfo.UserID, ShouldEqual, "user-id")
+				So(authzStore.authzs, ShouldHaveLength, 1)
+				So(consentRequestStore.requests, ShouldBeEmpty)
+			})
+
+			Convey("should deny if the end-user denies", func() {
+				result, err := authzHandler.HandleConsent("consent-code", false)
+				So(err, ShouldBeNil)
+				resp := writeResponse(result)
+				So(resp.Header().Get("Location"), ShouldEqual, "https://auth/device")
+
+				So(deviceCodeGrantStore.grants[0].Status, ShouldEqual, oauth.DeviceCodeGrantStatusDenied)
+				So(authzStore.authzs, ShouldBeEmpty)
+			})
+		})
+
+		Convey("should reject used user code", func() {
+			deviceCodeGrantStore.grants[0].Status = oauth.DeviceCodeGrantStatusApproved
+
+			_, _, err := h.FinishVerification(req, "BCDF-GHJK")
+			So(err, ShouldBeError, oauth.ErrInvalidUserCode)
+		})
+	})
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lestrrat-go/jwx/jwt"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/user"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/oidc"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/lib/session/idpsession"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+type IntrospectionHandlerLogger struct{ *log.Logger }
+
+func NewIntrospectionHandlerLogger(lf *log.Factory) IntrospectionHandlerLogger {
+	return IntrospectionHandlerLogger{lf.New("oauth-introspect")}
+}
+
+type IntrospectionAccessTokenDecoder interface {
+	DecodeAccessToken(encodedToken string) (tok string, isHash bool, err error)
+	DecodeClientAccessToken(encodedToken string) (token jwt.Token, ok bool, err error)
+}
+
+type IntrospectionHandler struct {
+	Config        *config.OAuthConfig
+	ClientSecrets *config.OAuthClientSecrets
+	Logger        IntrospectionHandlerLogger
+
+	Authorizations     oauth.AuthorizationStore
+	AccessGrants       oauth.AccessGrantStore
+	OfflineGrants      oauth.OfflineGrantStore
+	Sessions           SessionProvider
+	AccessTokenDecoder IntrospectionAccessTokenDecoder
+	TokenService       TokenService
+	Users              TokenHandlerUserFacade
+	Clock              clock.Clock
+}
+
+func (h *IntrospectionHandler) Handle(req *http.Request, r protocol.IntrospectionRequest) httputil.Result {
+	result, err := h.doHandle(req, r)
+	if err != nil {
+		var oauthError *protocol.OAuthProtocolError
+		resultErr := tokenResultError{}
+		if errors.As(err, &oauthError) {
+			resultErr.StatusCode = oauthError.StatusCode
+			resultErr.Response = oauthError.Response
+		} else {
+			h.Logger.WithError(err).Error("introspection handler failed")
+			resultErr.Response = protocol.NewErrorResponse("server_error", "internal server error")
+			resultErr.InternalError = true
+		}
+		result = resultErr
+	}
+
+	return result
+}
+
+func (h *IntrospectionHandler) doHandle(req *http.Request, r protocol.IntrospectionRequest) (httputil.Result, error) {
+	// Only confidential clients (i.e. resource servers) may introspect tokens.
+	// See RFC7662 section 2.1.
+	cred, err := parseClientCredentials(req, protocol.TokenRequest(r))
+	if err != nil {
+		return nil, err
+	}
+
+	client, ok := h.Config.GetClient(r.ClientID())
+	if !ok || !client.IsConfidential() {
+		return nil, errInvalidClientCredentials
+	}
+
+	err = authenticateClient(h.ClientSecrets, client, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Token() == "" {
+		return nil, protocol.NewError("invalid_request", "token is required")
+	}
+
+	// The token type hint only decides which token type is looked up first.
+	// The other token type is still looked up if the token is not found.
+	// See RFC7662 section 2.1.
+	lookups := []func(token string) (protocol.IntrospectionResponse, error){
+		h.introspectRefreshToken,
+		h.introspectAccessToken,
+	}
+	if r.TokenTypeHint() == "access_token" {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+
+	for _, lookup := range lookups {
+		resp, err := lookup(r.Token())
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil {
+			return tokenResultOK{Response: protocol.TokenResponse(resp)}, nil
+		}
+	}
+
+	return tokenResultOK{Response: protocol.TokenResponse(h.inactive())}, nil
+}
+
+func (h *IntrospectionHandler) inactive() protocol.IntrospectionResponse {
+	resp := protocol.IntrospectionResponse{}
+	resp.Active(false)
+	return resp
+}
+
+// introspectRefreshToken returns nil if the token is not a valid refresh token.
+func (h *IntrospectionHandler) introspectRefreshToken(refreshToken string) (protocol.IntrospectionResponse, error) {
+	authz, offlineGrant, err := h.TokenService.ParseRefreshToken(refreshToken)
+	if errors.Is(err, errInvalidRefreshToken) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	expiry, err := oauth.ComputeOfflineGrantExpiryWithClients(offlineGrant, h.Config)
+	if errors.Is(err, oauth.ErrGrantNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	resp := protocol.IntrospectionResponse{}
+	resp.Active(true)
+	resp.TokenType("refresh_token")
+	resp.Subject(authz.UserID)
+	resp.ClientID(authz.ClientID)
+	resp.Scope(offlineGrant.Scopes)
+	resp.IssuedAt(offlineGrant.CreatedAt.Unix())
+	resp.ExpiresAt(expiry.Unix())
+	resp.SessionID(oidc.EncodeSID(offlineGrant))
+	return resp, nil
+}
+
+// introspectAccessToken returns nil if the token is not a valid access token.
+func (h *IntrospectionHandler) introspectAccessToken(accessToken string) (protocol.IntrospectionResponse, error) {
+	clientToken, ok, err := h.AccessTokenDecoder.DecodeClientAccessToken(accessToken)
+	if err != nil {
+		return nil, nil
+	} else if ok {
+		return h.introspectClientAccessToken(clientToken), nil
+	}
+
+	tok, isHash, err := h.AccessTokenDecoder.DecodeAccessToken(accessToken)
+	if err != nil {
+		return nil, nil
+	}
+
+	tokenHash := tok
+	if !isHash {
+		tokenHash = oauth.HashToken(tok)
+	}
+
+	grant, err := h.AccessGrants.GetAccessGrant(tokenHash)
+	if errors.Is(err, oauth.ErrGrantNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if h.Clock.NowUTC().After(grant.ExpireAt) {
+		return nil, nil
+	}
+
+	authz, err := h.Authorizations.GetByID(grant.AuthorizationID)
+	if errors.Is(err, oauth.ErrAuthorizationNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Check if the user has been disabled or deleted.
+	u, err := h.Users.GetRaw(authz.UserID)
+	if errors.Is(err, user.ErrUserNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if u.CheckStatus() != nil {
+		return nil, nil
+	}
+
+	var sid string
+	switch grant.SessionKind {
+	case oauth.GrantSessionKindSession:
+		s, err := h.Sessions.Get(grant.SessionID)
+		if errors.Is(err, idpsession.ErrSessionNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		sid = oidc.EncodeSID(s)
+	case oauth.GrantSessionKindOffline:
+		g, err := h.OfflineGrants.GetOfflineGrant(grant.SessionID)
+		if errors.Is(err, oauth.ErrGrantNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		sid = oidc.EncodeSID(g)
+	default:
+		return nil, nil
+	}
+
+	resp := protocol.IntrospectionResponse{}
+	resp.Active(true)
+	resp.TokenType("Bearer")
+	resp.Subject(authz.UserID)
+	resp.ClientID(authz.ClientID)
+	resp.Scope(grant.Scopes)
+	resp.IssuedAt(grant.CreatedAt.Unix())
+	resp.ExpiresAt(grant.ExpireAt.Unix())
+	resp.SessionID(sid)
+	return resp, nil
+}
+
+// introspectClientAccessToken introspects an access token issued by the client credentials grant.
+// The token is not tied to any user or session, so there is no sid.
+func (h *IntrospectionHandler) introspectClientAccessToken(token jwt.Token) protocol.IntrospectionResponse {
+	client, ok := h.Config.GetClient(token.Subject())
+	if !ok {
+		return h.inactive()
+	}
+
+	resp := protocol.IntrospectionResponse{}
+	resp.Active(true)
+	resp.TokenType("Bearer")
+	resp.Subject(client.ClientID)
+	resp.ClientID(client.ClientID)
+	if scope, ok := token.Get("scope"); ok {
+		if scope, ok := scope.(string); ok {
+			resp.Scope(strings.Fields(scope))
+		}
+	}
+	resp.IssuedAt(token.IssuedAt().Unix())
+	resp.ExpiresAt(token.Expiration().Unix())
+	return resp
+}
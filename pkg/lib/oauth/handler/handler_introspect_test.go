@@ -0,0 +1,269 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/lib/authn/user"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/handler"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/oidc"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/log"
+	"github.com/authgear/authgear-server/pkg/util/password"
+)
+
+type mockIntrospectionAccessTokenDecoder struct {
+	clientTokens map[string]jwt.Token
+}
+
+func (m *mockIntrospectionAccessTokenDecoder) DecodeAccessToken(encodedToken string) (string, bool, error) {
+	return encodedToken, false, nil
+}
+
+func (m *mockIntrospectionAccessTokenDecoder) DecodeClientAccessToken(encodedToken string) (jwt.Token, bool, error) {
+	token, ok := m.clientTokens[encodedToken]
+	return token, ok, nil
+}
+
+type mockAccessGrantStore struct {
+	oauth.AccessGrantStore
+	grants []oauth.AccessGrant
+}
+
+func (m *mockAccessGrantStore) GetAccessGrant(tokenHash string) (*oauth.AccessGrant, error) {
+	for _, g := range m.grants {
+		if g.TokenHash == tokenHash {
+			return &g, nil
+		}
+	}
+	return nil, oauth.ErrGrantNotFound
+}
+
+type mockOfflineGrantStore struct {
+	oauth.OfflineGrantStore
+	grants []oauth.OfflineGrant
+}
+
+func (m *mockOfflineGrantStore) GetOfflineGrant(id string) (*oauth.OfflineGrant, error) {
+	for _, g := range m.grants {
+		if g.ID == id {
+			return &g, nil
+		}
+	}
+	return nil, oauth.ErrGrantNotFound
+}
+
+type mockUserFacade struct {
+	users map[string]*user.User
+}
+
+func (m *mockUserFacade) GetRaw(id string) (*user.User, error) {
+	u, ok := m.users[id]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func TestIntrospectionHandler(t *testing.T) {
+	Convey("Introspection handler", t, func() {
+		hash, err := password.Hash([]byte("client-secret"))
+		So(err, ShouldBeNil)
+
+		clk := clock.NewMockClockAt("2020-02-01T00:00:00Z")
+		now := clk.NowUTC()
+		idleTimeoutEnabled := false
+
+		oauthConfig := &config.OAuthConfig{
+			Clients: []config.OAuthClientConfig{
+				{
+					ClientID:                "resource-server",
+					GrantTypes:              []string{"client_credentials"},
+					TokenEndpointAuthMethod: config.OAuthClientAuthMethodClientSecretPost,
+				},
+				{
+					ClientID:                       "app-client",
+					RefreshTokenLifetime:           86400,
+					RefreshTokenIdleTimeoutEnabled: &idleTimeoutEnabled,
+				},
+			},
+		}
+
+		offlineGrant := oauth.OfflineGrant{
+			ID:              "grant-id",
+			ClientID:        "app-client",
+			AuthorizationID: "authz-id",
+			CreatedAt:       now,
+			Scopes:          []string{"openid", "offline_access"},
+			TokenHash:       oauth.HashToken("token"),
+		}
+		offlineGrant.Attrs.UserID = "user-id"
+
+		users := &mockUserFacade{
+			users: map[string]*user.User{
+				"user-id": {ID: "user-id"},
+			},
+		}
+		authzs := &mockAuthzStore{
+			authzs: []oauth.Authorization{{
+				ID:       "authz-id",
+				ClientID: "app-client",
+				UserID:   "user-id",
+			}},
+		}
+		offlineGrants := &mockOfflineGrantStore{
+			grants: []oauth.OfflineGrant{offlineGrant},
+		}
+
+		clientToken := jwt.New()
+		_ = clientToken.Set(jwt.SubjectKey, "resource-server")
+		_ = clientToken.Set("scope", "read write")
+		_ = clientToken.Set(jwt.IssuedAtKey, now.Unix())
+		_ = clientToken.Set(jwt.ExpirationKey, now.Add(time.Hour).Unix())
+
+		h := &handler.IntrospectionHandler{
+			Config: oauthConfig,
+			ClientSecrets: &config.OAuthClientSecrets{
+				Items: []config.OAuthClientSecretsItem{{
+					ClientID:           "resource-server",
+					ClientSecretHashes: []string{string(hash)},
+				}},
+			},
+			Logger:         handler.IntrospectionHandlerLogger{log.Null},
+			Authorizations: authzs,
+			AccessGrants: &mockAccessGrantStore{
+				grants: []oauth.AccessGrant{
+					{
+						AuthorizationID: "authz-id",
+						SessionID:       "grant-id",
+						SessionKind:     oauth.GrantSessionKindOffline,
+						CreatedAt:       now,
+						ExpireAt:        now.Add(time.Hour),
+						Scopes:          []string{"openid"},
+						TokenHash:       oauth.HashToken("access-token"),
+					},
+					{
+						// The hash of a string which is also a valid refresh token.
+						AuthorizationID: "authz-id",
+						SessionID:       "grant-id",
+						SessionKind:     oauth.GrantSessionKindOffline,
+						CreatedAt:       now,
+						ExpireAt:        now.Add(time.Hour),
+						Scopes:          []string{"openid"},
+						TokenHash:       oauth.HashToken("grant-id.token"),
+					},
+				},
+			},
+			OfflineGrants: offlineGrants,
+			AccessTokenDecoder: &mockIntrospectionAccessTokenDecoder{
+				clientTokens: map[string]jwt.Token{
+					"client-access-token": clientToken,
+				},
+			},
+			TokenService: handler.TokenService{
+				Config:         oauthConfig,
+				Authorizations: authzs,
+				OfflineGrants:  offlineGrants,
+				Users:          users,
+				Clock:          clk,
+			},
+			Users: users,
+			Clock: clk,
+		}
+
+		introspect := func(token string, hint string) map[string]interface{} {
+			req, _ := http.NewRequest("POST", "/oauth2/introspect", nil)
+			r := protocol.IntrospectionRequest{
+				"client_id":     "resource-server",
+				"client_secret": "client-secret",
+				"token":         token,
+			}
+			if hint != "" {
+				r["token_type_hint"] = hint
+			}
+			rw := httptest.NewRecorder()
+			h.Handle(req, r).WriteResponse(rw, req)
+			So(rw.Code, ShouldEqual, 200)
+
+			var resp map[string]interface{}
+			err := json.Unmarshal(rw.Body.Bytes(), &resp)
+			So(err, ShouldBeNil)
+			return resp
+		}
+
+		sid := oidc.EncodeSID(&offlineGrant)
+
+		Convey("should introspect access token", func() {
+			So(introspect("access-token", ""), ShouldResemble, map[string]interface{}{
+				"active":     true,
+				"token_type": "Bearer",
+				"sub":        "user-id",
+				"client_id":  "app-client",
+				"scope":      "openid",
+				"iat":        float64(now.Unix()),
+				"exp":        float64(now.Add(time.Hour).Unix()),
+				"sid":        sid,
+			})
+		})
+
+		Convey("should introspect refresh token", func() {
+			So(introspect("grant-id.token", ""), ShouldResemble, map[string]interface{}{
+				"active":     true,
+				"token_type": "refresh_token",
+				"sub":        "user-id",
+				"client_id":  "app-client",
+				"scope":      "openid offline_access",
+				"iat":        float64(now.Unix()),
+				"exp":        float64(now.Add(24 * time.Hour).Unix()),
+				"sid":        sid,
+			})
+		})
+
+		Convey("should look up the hinted token type first", func() {
+			So(introspect("grant-id.token", "refresh_token")["token_type"], ShouldEqual, "refresh_token")
+			So(introspect("grant-id.token", "access_token")["token_type"], ShouldEqual, "Bearer")
+		})
+
+		Convey("should fall back to the other token type", func() {
+			So(introspect("access-token", "refresh_token")["active"], ShouldBeTrue)
+			So(introspect("grant-id.token", "unknown")["token_type"], ShouldEqual, "refresh_token")
+		})
+
+		Convey("should introspect client access token", func() {
+			So(introspect("client-access-token", ""), ShouldResemble, map[string]interface{}{
+				"active":     true,
+				"token_type": "Bearer",
+				"sub":        "resource-server",
+				"client_id":  "resource-server",
+				"scope":      "read write",
+				"iat":        float64(now.Unix()),
+				"exp":        float64(now.Add(time.Hour).Unix()),
+			})
+		})
+
+		Convey("should report disabled user as inactive", func() {
+			users.users["user-id"].IsDisabled = true
+			So(introspect("access-token", ""), ShouldResemble, map[string]interface{}{"active": false})
+			So(introspect("grant-id.token", ""), ShouldResemble, map[string]interface{}{"active": false})
+		})
+
+		Convey("should report deleted user as inactive", func() {
+			delete(users.users, "user-id")
+			So(introspect("access-token", ""), ShouldResemble, map[string]interface{}{"active": false})
+			So(introspect("grant-id.token", ""), ShouldResemble, map[string]interface{}{"active": false})
+		})
+
+		Convey("should report unknown token as inactive", func() {
+			So(introspect("unknown", ""), ShouldResemble, map[string]interface{}{"active": false})
+		})
+	})
+}
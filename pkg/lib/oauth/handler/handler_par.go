@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/duration"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+// PushedAuthorizationRequestValidDuration is the lifetime of request_uri.
+// The client is expected to use request_uri immediately after pushing the request.
+const PushedAuthorizationRequestValidDuration = duration.Short
+
+// pushedAuthorizationRequestUsedValidDuration is the lifetime of request_uri
+// after it is used at the authorization endpoint.
+// The request is resolved again after the end-user finishes the interaction.
+const pushedAuthorizationRequestUsedValidDuration = duration.UserInteraction
+
+type PushedAuthorizationHandlerLogger struct{ *log.Logger }
+
+func NewPushedAuthorizationHandlerLogger(lf *log.Factory) PushedAuthorizationHandlerLogger {
+	return PushedAuthorizationHandlerLogger{lf.New("oauth-par")}
+}
+
+type PushedAuthorizationHandler struct {
+	AppID         config.AppID
+	Config        *config.OAuthConfig
+	ClientSecrets *config.OAuthClientSecrets
+	HTTPConfig    *config.HTTPConfig
+	Logger        PushedAuthorizationHandlerLogger
+
+	PushedAuthorizationRequests oauth.PushedAuthorizationRequestStore
+	ValidateScopes              ScopesValidator
+	RequestURIGenerator         TokenGenerator
+	Clock                       clock.Clock
+}
+
+func (h *PushedAuthorizationHandler) Handle(req *http.Request, r protocol.AuthorizationRequest) httputil.Result {
+	result, err := h.doHandle(req, r)
+	if err != nil {
+		var oauthError *protocol.OAuthProtocolError
+		resultErr := tokenResultError{}
+		if errors.As(err, &oauthError) {
+			resultErr.StatusCode = oauthError.StatusCode
+			resultErr.Response = oauthError.Response
+		} else {
+			h.Logger.WithError(err).Error("pushed authz handler failed")
+			resultErr.Response = protocol.NewErrorResponse("server_error", "internal server error")
+			resultErr.InternalError = true
+		}
+		result = resultErr
+	}
+
+	return result
+}
+
+func (h *PushedAuthorizationHandler) doHandle(req *http.Request, r protocol.AuthorizationRequest) (httputil.Result, error) {
+	// The client authenticates in the same way as at the token endpoint.
+	// See RFC9126 section 2.1.
+	cred, err := parseClientCredentials(req, protocol.TokenRequest(r))
+	if err != nil {
+		return nil, err
+	}
+
+	client, ok := h.Config.GetClient(r.ClientID())
+	if !ok {
+		return nil, protocol.NewError("invalid_client", "invalid client ID")
+	}
+
+	err = authenticateClient(h.ClientSecrets, client, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.RequestURI() != "" {
+		return nil, protocol.NewError("invalid_request", "request_uri must not be pushed")
+	}
+
+	_, errResp := parseRedirectURI(client, h.HTTPConfig, r)
+	if errResp != nil {
+		return nil, &protocol.OAuthProtocolError{Response: errResp}
+	}
+
+	err = validateAuthorizationRequest(client, r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.ValidateScopes(client, r.Scope())
+	if err != nil {
+		return nil, err
+	}
+
+	// Client credentials must not be kept.
+	parameters := make(map[string]string)
+	for k, v := range r {
+		if k == "client_secret" {
+			continue
+		}
+		parameters[k] = v
+	}
+
+	requestURI := oauth.RequestURIPrefix + h.RequestURIGenerator()
+	now := h.Clock.NowUTC()
+	par := &oauth.PushedAuthorizationRequest{
+		AppID:    string(h.AppID),
+		ClientID: client.ClientID,
+
+		CreatedAt:      now,
+		ExpireAt:       now.Add(PushedAuthorizationRequestValidDuration),
+		RequestURIHash: oauth.HashToken(requestURI),
+
+		Parameters: parameters,
+	}
+
+	err = h.PushedAuthorizationRequests.CreatePushedAuthorizationRequest(par)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := protocol.PushedAuthorizationResponse{}
+	resp.RequestURI(requestURI)
+	resp.ExpiresIn(int(PushedAuthorizationRequestValidDuration.Seconds()))
+
+	return tokenResultOK{
+		StatusCode: http.StatusCreated,
+		Response:   protocol.TokenResponse(resp),
+	}, nil
+}
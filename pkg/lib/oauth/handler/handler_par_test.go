@@ -0,0 +1,121 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/handler"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+func TestPushedAuthorizationHandler(t *testing.T) {
+	Convey("Pushed authorization handler", t, func() {
+		clock := clock.NewMockClockAt("2020-02-01T00:00:00Z")
+		parStore := &mockPushedAuthorizationRequestStore{}
+
+		h := &handler.PushedAuthorizationHandler{
+			AppID: "app-id",
+			Config: &config.OAuthConfig{
+				Clients: []config.OAuthClientConfig{{
+					ClientID:     "client-id",
+					RedirectURIs: []string{"https://example.com/"},
+				}},
+			},
+			HTTPConfig: &config.HTTPConfig{
+				PublicOrigin: "http://accounts.example.com",
+			},
+			PushedAuthorizationRequests: parStore,
+			ValidateScopes:              func(*config.OAuthClientConfig, []string) error { return nil },
+			RequestURIGenerator:         func() string { return "request-uri" },
+			Clock:                       clock,
+		}
+		handle := func(r protocol.AuthorizationRequest) *httptest.ResponseRecorder {
+			form := url.Values{}
+			for k, v := range r {
+				form.Set(k, v)
+			}
+			req, _ := http.NewRequest("POST", "/oauth2/par", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			result := h.Handle(req, r)
+			resp := httptest.NewRecorder()
+			result.WriteResponse(resp, req)
+			return resp
+		}
+
+		Convey("should store the request", func() {
+			resp := handle(protocol.AuthorizationRequest{
+				"client_id":             "client-id",
+				"response_type":         "code",
+				"scope":                 "openid",
+				"code_challenge_method": "S256",
+				"code_challenge":        "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			})
+			So(resp.Result().StatusCode, ShouldEqual, 201)
+			So(resp.Body.String(), ShouldEqualJSON, `{
+				"request_uri": "urn:ietf:params:oauth:request_uri:request-uri",
+				"expires_in": 300
+			}`)
+
+			So(parStore.requests, ShouldResemble, []oauth.PushedAuthorizationRequest{{
+				AppID:          "app-id",
+				ClientID:       "client-id",
+				CreatedAt:      time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+				ExpireAt:       time.Date(2020, 2, 1, 0, 5, 0, 0, time.UTC),
+				RequestURIHash: oauth.HashToken("urn:ietf:params:oauth:request_uri:request-uri"),
+				Parameters: map[string]string{
+					"client_id":             "client-id",
+					"response_type":         "code",
+					"scope":                 "openid",
+					"code_challenge_method": "S256",
+					"code_challenge":        "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+				},
+			}})
+		})
+
+		Convey("should reject invalid request", func() {
+			resp := handle(protocol.AuthorizationRequest{
+				"client_id":     "client-id",
+				"response_type": "code",
+				"scope":         "openid",
+			})
+			So(resp.Result().StatusCode, ShouldEqual, 400)
+			So(resp.Body.String(), ShouldEqualJSON, `{
+				"error": "invalid_request",
+				"error_description": "PKCE code challenge is required"
+			}`)
+			So(parStore.requests, ShouldBeEmpty)
+		})
+
+		Convey("should reject request_uri", func() {
+			resp := handle(protocol.AuthorizationRequest{
+				"client_id":   "client-id",
+				"request_uri": "urn:ietf:params:oauth:request_uri:abc",
+			})
+			So(resp.Result().StatusCode, ShouldEqual, 400)
+			So(resp.Body.String(), ShouldEqualJSON, `{
+				"error": "invalid_request",
+				"error_description": "request_uri must not be pushed"
+			}`)
+		})
+
+		Convey("should reject unknown client", func() {
+			resp := handle(protocol.AuthorizationRequest{
+				"client_id": "unknown",
+			})
+			So(resp.Result().StatusCode, ShouldEqual, 400)
+			So(resp.Body.String(), ShouldEqualJSON, `{
+				"error": "invalid_client",
+				"error_description": "invalid client ID"
+			}`)
+		})
+	})
+}
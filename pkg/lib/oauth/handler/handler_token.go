@@ -7,24 +7,31 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/blocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
 	identitybiometric "github.com/authgear/authgear-server/pkg/lib/authn/identity/biometric"
 	"github.com/authgear/authgear-server/pkg/lib/authn/user"
 	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
 	"github.com/authgear/authgear-server/pkg/lib/interaction"
 	interactionintents "github.com/authgear/authgear-server/pkg/lib/interaction/intents"
 	"github.com/authgear/authgear-server/pkg/lib/interaction/nodes"
 	"github.com/authgear/authgear-server/pkg/lib/oauth"
 	"github.com/authgear/authgear-server/pkg/lib/oauth/oidc"
 	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
 	"github.com/authgear/authgear-server/pkg/lib/session"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/duration"
 	"github.com/authgear/authgear-server/pkg/util/httputil"
 	"github.com/authgear/authgear-server/pkg/util/jwtutil"
 	"github.com/authgear/authgear-server/pkg/util/log"
+	"github.com/authgear/authgear-server/pkg/util/slice"
 )
 
 const AnonymousRequestGrantType = "urn:authgear:params:oauth:grant-type:anonymous-request"
@@ -48,13 +55,30 @@ type IDTokenIssuer interface {
 }
 
 type AccessTokenIssuer interface {
-	EncodeAccessToken(client *config.OAuthClientConfig, grant *oauth.AccessGrant, userID string, token string) (string, error)
+	EncodeAccessToken(client *config.OAuthClientConfig, grant *oauth.AccessGrant, userID string, token string, customClaims map[string]interface{}) (string, error)
+	EncodeClientAccessToken(client *config.OAuthClientConfig, scopes []string, issuedAt time.Time, expireAt time.Time) (string, error)
+}
+
+type TokenHandlerRateLimiter interface {
+	TakeToken(bucket ratelimit.Bucket) error
 }
 
 type TokenHandlerUserFacade interface {
 	GetRaw(id string) (*user.User, error)
 }
 
+type CustomClaimsResolver interface {
+	ResolveCustomClaims(client *config.OAuthClientConfig, userID string, scopes []string) (map[string]interface{}, error)
+}
+
+type EventService interface {
+	DispatchEvent(payload event.Payload) error
+}
+
+type TokenHandlerHooks interface {
+	WillDeliverBlockingEvent(eventType event.Type) bool
+}
+
 type TokenHandlerLogger struct{ *log.Logger }
 
 func NewTokenHandlerLogger(lf *log.Factory) TokenHandlerLogger {
@@ -62,35 +86,38 @@ func NewTokenHandlerLogger(lf *log.Factory) TokenHandlerLogger {
 }
 
 type TokenHandler struct {
-	AppID  config.AppID
-	Config *config.OAuthConfig
-	Logger TokenHandlerLogger
+	AppID         config.AppID
+	Config        *config.OAuthConfig
+	ClientSecrets *config.OAuthClientSecrets
+	Logger        TokenHandlerLogger
 
 	Authorizations   oauth.AuthorizationStore
 	CodeGrants       oauth.CodeGrantStore
+	DeviceCodeGrants oauth.DeviceCodeGrantStore
 	OfflineGrants    oauth.OfflineGrantStore
 	AppSessionTokens oauth.AppSessionTokenStore
 	Graphs           GraphService
 	IDTokenIssuer    IDTokenIssuer
 	Clock            clock.Clock
 	TokenService     TokenService
+	RateLimiter      TokenHandlerRateLimiter
+	Events           EventService
+	Hooks            TokenHandlerHooks
+	CustomClaims     CustomClaimsResolver
 }
 
 func (h *TokenHandler) Handle(rw http.ResponseWriter, req *http.Request, r protocol.TokenRequest) httputil.Result {
-	client := resolveClient(h.Config, r)
-	if client == nil {
-		return tokenResultError{
-			Response: protocol.NewErrorResponse("invalid_client", "invalid client ID"),
-		}
-	}
-
-	result, err := h.doHandle(rw, req, client, r)
+	result, err := h.authenticateAndHandle(rw, req, r)
 	if err != nil {
 		var oauthError *protocol.OAuthProtocolError
 		resultErr := tokenResultError{}
 		if errors.As(err, &oauthError) {
 			resultErr.StatusCode = oauthError.StatusCode
 			resultErr.Response = oauthError.Response
+		} else if apierrors.IsKind(err, hook.WebHookDisallowed) {
+			resultErr.Response = protocol.NewErrorResponse("access_denied", "disallowed by web-hook event handler")
+			// Roll back the changes made in this request, e.g. the newly created anonymous user.
+			resultErr.InternalError = true
 		} else {
 			h.Logger.WithError(err).Error("authz handler failed")
 			resultErr.Response = protocol.NewErrorResponse("server_error", "internal server error")
@@ -102,6 +129,29 @@ func (h *TokenHandler) Handle(rw http.ResponseWriter, req *http.Request, r proto
 	return result
 }
 
+func (h *TokenHandler) authenticateAndHandle(
+	rw http.ResponseWriter,
+	req *http.Request,
+	r protocol.TokenRequest,
+) (httputil.Result, error) {
+	cred, err := parseClientCredentials(req, r)
+	if err != nil {
+		return nil, err
+	}
+
+	client := resolveClient(h.Config, r)
+	if client == nil {
+		return nil, protocol.NewError("invalid_client", "invalid client ID")
+	}
+
+	err = authenticateClient(h.ClientSecrets, client, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.doHandle(rw, req, client, r)
+}
+
 func (h *TokenHandler) doHandle(
 	rw http.ResponseWriter,
 	req *http.Request,
@@ -144,6 +194,10 @@ func (h *TokenHandler) doHandle(
 		return h.handleBiometricRequest(rw, req, client, r)
 	case IDTokenGrantType:
 		return h.handleIDToken(rw, req, client, r)
+	case "client_credentials":
+		return h.handleClientCredentials(client, r)
+	case oauth.DeviceCodeGrantType:
+		return h.handleDeviceCode(client, r)
 	default:
 		panic("oauth: unexpected grant type")
 	}
@@ -172,6 +226,12 @@ func (h *TokenHandler) validateRequest(r protocol.TokenRequest) error {
 		}
 	case IDTokenGrantType:
 		break
+	case "client_credentials":
+		break
+	case oauth.DeviceCodeGrantType:
+		if r.DeviceCode() == "" {
+			return protocol.NewError("invalid_request", "device code is required")
+		}
 	default:
 		return protocol.NewError("unsupported_grant_type", "grant type is not supported")
 	}
@@ -217,6 +277,11 @@ func (h *TokenHandler) handleAuthorizationCode(
 		return nil, err
 	}
 
+	err = h.dispatchPreTokenIssueEvent(client, r.GrantType(), authz.UserID, codeGrant.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
 	resp, err := h.issueTokensForAuthorizationCode(client, codeGrant, authz, deviceInfo)
 	if err != nil {
 		return nil, err
@@ -244,6 +309,11 @@ func (h *TokenHandler) handleRefreshToken(
 		return nil, err
 	}
 
+	err = h.dispatchPreTokenIssueEvent(client, r.GrantType(), authz.UserID, offlineGrant.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
 	resp, err := h.issueTokensForRefreshToken(client, offlineGrant, authz)
 	if err != nil {
 		return nil, err
@@ -351,6 +421,16 @@ func (h *TokenHandler) handleAnonymousRequest(
 		return nil, err
 	}
 
+	err = h.dispatchPreTokenIssueEvent(client, r.GrantType(), authz.UserID, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	customClaims, err := h.resolveCustomClaims(client, authz.UserID, scopes, false)
+	if err != nil {
+		return nil, err
+	}
+
 	resp := protocol.TokenResponse{}
 
 	opts := IssueOfflineGrantOptions{
@@ -365,7 +445,7 @@ func (h *TokenHandler) handleAnonymousRequest(
 	}
 
 	err = h.TokenService.IssueAccessGrant(client, scopes, authz.ID, authz.UserID,
-		offlineGrant.ID, oauth.GrantSessionKindOffline, resp)
+		offlineGrant.ID, oauth.GrantSessionKindOffline, customClaims, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -534,6 +614,11 @@ func (h *TokenHandler) handleBiometricAuthenticate(
 		return nil, err
 	}
 
+	err = h.dispatchPreTokenIssueEvent(client, r.GrantType(), authz.UserID, scopes)
+	if err != nil {
+		return nil, err
+	}
+
 	// Clean up any offline grants that were issued with the same identity.
 	offlineGrants, err := h.OfflineGrants.ListOfflineGrants(authz.UserID)
 	if err != nil {
@@ -548,6 +633,11 @@ func (h *TokenHandler) handleBiometricAuthenticate(
 		}
 	}
 
+	customClaims, err := h.resolveCustomClaims(client, authz.UserID, scopes, true)
+	if err != nil {
+		return nil, err
+	}
+
 	resp := protocol.TokenResponse{}
 
 	opts := IssueOfflineGrantOptions{
@@ -563,7 +653,7 @@ func (h *TokenHandler) handleBiometricAuthenticate(
 	}
 
 	err = h.TokenService.IssueAccessGrant(client, scopes, authz.ID, authz.UserID,
-		offlineGrant.ID, oauth.GrantSessionKindOffline, resp)
+		offlineGrant.ID, oauth.GrantSessionKindOffline, customClaims, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -575,6 +665,7 @@ func (h *TokenHandler) handleBiometricAuthenticate(
 		ClientID:           client.ClientID,
 		SID:                oidc.EncodeSID(offlineGrant),
 		AuthenticationInfo: offlineGrant.GetAuthenticationInfo(),
+		CustomClaims:       customClaims,
 	})
 	if err != nil {
 		return nil, err
@@ -594,10 +685,15 @@ func (h *TokenHandler) handleIDToken(
 	if s == nil {
 		return nil, protocol.NewErrorStatusCode("invalid_request", "valid session is required", http.StatusUnauthorized)
 	}
+	customClaims, err := h.resolveCustomClaims(client, s.GetAuthenticationInfo().UserID, nil, true)
+	if err != nil {
+		return nil, err
+	}
 	idToken, err := h.IDTokenIssuer.IssueIDToken(oidc.IssueIDTokenOptions{
 		ClientID:           client.ClientID,
 		SID:                oidc.EncodeSID(s),
 		AuthenticationInfo: s.GetAuthenticationInfo(),
+		CustomClaims:       customClaims,
 	})
 	if err != nil {
 		return nil, err
@@ -607,6 +703,129 @@ func (h *TokenHandler) handleIDToken(
 	return tokenResultOK{Response: resp}, nil
 }
 
+func (h *TokenHandler) handleClientCredentials(
+	client *config.OAuthClientConfig,
+	r protocol.TokenRequest,
+) (httputil.Result, error) {
+	// The client must have been authenticated at this point,
+	// but check again to avoid issuing tokens to public clients by accident.
+	if !client.IsConfidential() {
+		return nil, protocol.NewError(
+			"unauthorized_client",
+			"public clients may not use client credentials grant",
+		)
+	}
+
+	// The requested scopes must be configured for the client.
+	// All configured scopes are granted if no scope is requested.
+	// See RFC6749 section 3.3.
+	scopes := r.Scope()
+	if len(scopes) == 0 {
+		scopes = append([]string{}, client.ClientCredentialsScopes...)
+	}
+	for _, scope := range scopes {
+		if !slice.ContainsString(client.ClientCredentialsScopes, scope) {
+			return nil, protocol.NewError("invalid_scope", "scope is not allowed for this client")
+		}
+	}
+
+	// The tokens are issued to the client itself, not to any user.
+	err := h.dispatchPreTokenIssueEvent(client, r.GrantType(), "", scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := protocol.TokenResponse{}
+	err = h.TokenService.IssueClientAccessToken(client, scopes, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenResultOK{Response: resp}, nil
+}
+
+var errInvalidDeviceCode = protocol.NewError("invalid_grant", "invalid device code")
+
+func (h *TokenHandler) handleDeviceCode(
+	client *config.OAuthClientConfig,
+	r protocol.TokenRequest,
+) (httputil.Result, error) {
+	deviceInfo, err := r.DeviceInfo()
+	if err != nil {
+		return nil, protocol.NewError("invalid_request", err.Error())
+	}
+
+	deviceCodeHash := oauth.HashToken(r.DeviceCode())
+	grant, err := h.DeviceCodeGrants.GetDeviceCodeGrant(deviceCodeHash)
+	if errors.Is(err, oauth.ErrGrantNotFound) {
+		return nil, errInvalidDeviceCode
+	} else if err != nil {
+		return nil, err
+	}
+
+	if grant.ClientID != client.ClientID {
+		return nil, errInvalidDeviceCode
+	}
+
+	if h.Clock.NowUTC().After(grant.ExpireAt) {
+		return nil, protocol.NewError("expired_token", "device code is expired")
+	}
+
+	err = h.RateLimiter.TakeToken(oauth.DeviceCodePollRateLimitBucket(deviceCodeHash, DeviceCodePollingInterval))
+	if errors.Is(err, ratelimit.ErrTooManyRequests) {
+		return nil, protocol.NewError("slow_down", "polling too frequently")
+	} else if err != nil {
+		return nil, err
+	}
+
+	switch grant.Status {
+	case oauth.DeviceCodeGrantStatusApproved:
+		break
+	case oauth.DeviceCodeGrantStatusDenied:
+		err = h.DeviceCodeGrants.DeleteDeviceCodeGrant(grant)
+		if err != nil {
+			h.Logger.WithError(err).Error("failed to invalidate device code grant")
+		}
+		return nil, protocol.NewError("access_denied", "the end-user denied the authorization request")
+	default:
+		return nil, protocol.NewError("authorization_pending", "authorization is pending")
+	}
+
+	authz, err := h.Authorizations.GetByID(grant.AuthorizationID)
+	if errors.Is(err, oauth.ErrAuthorizationNotFound) {
+		return nil, errInvalidDeviceCode
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Tokens are issued in the same way as the authorization code grant,
+	// with the session of the verification as the ID token hint.
+	codeGrant := &oauth.CodeGrant{
+		AppID:              grant.AppID,
+		AuthorizationID:    grant.AuthorizationID,
+		IDPSessionID:       grant.IDPSessionID,
+		AuthenticationInfo: grant.AuthenticationInfo,
+		IDTokenHintSID:     grant.SID,
+		Scopes:             grant.Scopes,
+	}
+	err = h.dispatchPreTokenIssueEvent(client, r.GrantType(), authz.UserID, codeGrant.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.issueTokensForAuthorizationCode(client, codeGrant, authz, deviceInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.DeviceCodeGrants.DeleteDeviceCodeGrant(grant)
+	if err != nil {
+		h.Logger.WithError(err).Error("failed to invalidate device code grant")
+	}
+
+	return tokenResultOK{Response: resp}, nil
+}
+
 func (h *TokenHandler) issueTokensForAuthorizationCode(
 	client *config.OAuthClientConfig,
 	code *oauth.CodeGrant,
@@ -700,7 +919,12 @@ func (h *TokenHandler) issueTokensForAuthorizationCode(
 		return nil, protocol.NewError("invalid_request", "cannot issue access token")
 	}
 
-	err := h.TokenService.IssueAccessGrant(client, code.Scopes, authz.ID, authz.UserID, accessTokenSessionID, accessTokenSessionKind, resp)
+	customClaims, err := h.resolveCustomClaims(client, authz.UserID, code.Scopes, issueIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.TokenService.IssueAccessGrant(client, code.Scopes, authz.ID, authz.UserID, accessTokenSessionID, accessTokenSessionKind, customClaims, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -717,6 +941,7 @@ func (h *TokenHandler) issueTokensForAuthorizationCode(
 			SID:                sid,
 			Nonce:              code.OIDCNonce,
 			AuthenticationInfo: info,
+			CustomClaims:       customClaims,
 		})
 		if err != nil {
 			return nil, err
@@ -740,6 +965,11 @@ func (h *TokenHandler) issueTokensForRefreshToken(
 		}
 	}
 
+	customClaims, err := h.resolveCustomClaims(client, authz.UserID, offlineGrant.Scopes, issueIDToken)
+	if err != nil {
+		return nil, err
+	}
+
 	resp := protocol.TokenResponse{}
 
 	if issueIDToken {
@@ -750,6 +980,7 @@ func (h *TokenHandler) issueTokensForRefreshToken(
 			ClientID:           client.ClientID,
 			SID:                oidc.EncodeSID(offlineGrant),
 			AuthenticationInfo: offlineGrant.GetAuthenticationInfo(),
+			CustomClaims:       customClaims,
 		})
 		if err != nil {
 			return nil, err
@@ -757,8 +988,8 @@ func (h *TokenHandler) issueTokensForRefreshToken(
 		resp.IDToken(idToken)
 	}
 
-	err := h.TokenService.IssueAccessGrant(client, offlineGrant.Scopes,
-		authz.ID, authz.UserID, offlineGrant.ID, oauth.GrantSessionKindOffline, resp)
+	err = h.TokenService.IssueAccessGrant(client, offlineGrant.Scopes,
+		authz.ID, authz.UserID, offlineGrant.ID, oauth.GrantSessionKindOffline, customClaims, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -766,6 +997,43 @@ func (h *TokenHandler) issueTokensForRefreshToken(
 	return resp, nil
 }
 
+func (h *TokenHandler) dispatchPreTokenIssueEvent(
+	client *config.OAuthClientConfig,
+	grantType string,
+	userID string,
+	scopes []string,
+) error {
+	// Skip resolving the user if the event is not handled.
+	if !h.Hooks.WillDeliverBlockingEvent(blocking.OAuthPreTokenIssue) {
+		return nil
+	}
+
+	return h.Events.DispatchEvent(&blocking.OAuthPreTokenIssueBlockingEventPayload{
+		UserRef: model.UserRef{
+			Meta: model.Meta{
+				ID: userID,
+			},
+		},
+		ClientID:  client.ClientID,
+		GrantType: grantType,
+		Scopes:    scopes,
+	})
+}
+
+// resolveCustomClaims resolves the custom claims if any JWT is going to be issued.
+func (h *TokenHandler) resolveCustomClaims(
+	client *config.OAuthClientConfig,
+	userID string,
+	scopes []string,
+	issueIDToken bool,
+) (map[string]interface{}, error) {
+	if !issueIDToken && !client.IssueJWTAccessToken {
+		return nil, nil
+	}
+
+	return h.CustomClaims.ResolveCustomClaims(client, userID, scopes)
+}
+
 type IssueOfflineGrantOptions struct {
 	AuthenticationInfo authenticationinfo.T
 	Scopes             []string
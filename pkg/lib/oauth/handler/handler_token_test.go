@@ -0,0 +1,137 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/blocking"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
+	"github.com/authgear/authgear-server/pkg/lib/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/handler"
+	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/log"
+	"github.com/authgear/authgear-server/pkg/util/password"
+)
+
+type mockAccessTokenIssuer struct{}
+
+func (mockAccessTokenIssuer) EncodeAccessToken(client *config.OAuthClientConfig, grant *oauth.AccessGrant, userID string, token string, customClaims map[string]interface{}) (string, error) {
+	return "", errors.New("not supported")
+}
+
+func (mockAccessTokenIssuer) EncodeClientAccessToken(client *config.OAuthClientConfig, scopes []string, issuedAt time.Time, expireAt time.Time) (string, error) {
+	return "client-access-token", nil
+}
+
+func TestTokenHandlerClientCredentials(t *testing.T) {
+	Convey("Token handler client_credentials grant", t, func() {
+		hash, err := password.Hash([]byte("client-secret"))
+		So(err, ShouldBeNil)
+
+		events := &mockEventService{}
+		hooks := &mockHooks{blockingEvents: []event.Type{blocking.OAuthPreTokenIssue}}
+		h := &handler.TokenHandler{
+			Config: &config.OAuthConfig{
+				Clients: []config.OAuthClientConfig{{
+					ClientID:                "client-id",
+					GrantTypes:              []string{"client_credentials"},
+					ClientCredentialsScopes: []string{"read", "write"},
+					TokenEndpointAuthMethod: config.OAuthClientAuthMethodClientSecretPost,
+					AccessTokenLifetime:     1800,
+				}},
+			},
+			ClientSecrets: &config.OAuthClientSecrets{
+				Items: []config.OAuthClientSecretsItem{{
+					ClientID:           "client-id",
+					ClientSecretHashes: []string{string(hash)},
+				}},
+			},
+			Logger: handler.TokenHandlerLogger{log.Null},
+			TokenService: handler.TokenService{
+				AccessTokenIssuer: mockAccessTokenIssuer{},
+				Clock:             clock.NewMockClockAt("2020-02-01T00:00:00Z"),
+			},
+			Events: events,
+			Hooks:  hooks,
+		}
+
+		handle := func(scope string) *httptest.ResponseRecorder {
+			req, _ := http.NewRequest("POST", "/oauth2/token", strings.NewReader(url.Values{}.Encode()))
+			r := protocol.TokenRequest{
+				"grant_type":    "client_credentials",
+				"client_id":     "client-id",
+				"client_secret": "client-secret",
+			}
+			if scope != "" {
+				r["scope"] = scope
+			}
+			rw := httptest.NewRecorder()
+			h.Handle(rw, req, r).WriteResponse(rw, req)
+			return rw
+		}
+
+		Convey("should dispatch oauth.pre_token_issue without user", func() {
+			rw := handle("read")
+			So(rw.Code, ShouldEqual, 200)
+			So(rw.Body.String(), ShouldContainSubstring, `"access_token":"client-access-token"`)
+			So(rw.Body.String(), ShouldContainSubstring, `"scope":"read"`)
+
+			So(events.payloads, ShouldResemble, []event.Payload{
+				&blocking.OAuthPreTokenIssueBlockingEventPayload{
+					ClientID:  "client-id",
+					GrantType: "client_credentials",
+					Scopes:    []string{"read"},
+				},
+			})
+		})
+
+		Convey("should grant all configured scopes if no scope is requested", func() {
+			rw := handle("")
+			So(rw.Code, ShouldEqual, 200)
+			So(rw.Body.String(), ShouldContainSubstring, `"scope":"read write"`)
+
+			So(events.payloads, ShouldResemble, []event.Payload{
+				&blocking.OAuthPreTokenIssueBlockingEventPayload{
+					ClientID:  "client-id",
+					GrantType: "client_credentials",
+					Scopes:    []string{"read", "write"},
+				},
+			})
+		})
+
+		Convey("should not dispatch oauth.pre_token_issue if it is not handled", func() {
+			hooks.blockingEvents = nil
+
+			rw := handle("read")
+			So(rw.Code, ShouldEqual, 200)
+			So(rw.Body.String(), ShouldContainSubstring, `"access_token":"client-access-token"`)
+			So(events.payloads, ShouldBeEmpty)
+		})
+
+		Convey("should reject scope not configured for the client", func() {
+			rw := handle("read admin")
+			So(rw.Code, ShouldEqual, 400)
+			So(rw.Body.String(), ShouldContainSubstring, `"error":"invalid_scope"`)
+			So(events.payloads, ShouldBeEmpty)
+		})
+
+		Convey("should not issue token if disallowed", func() {
+			events.err = hook.WebHookDisallowed.New("disallowed")
+
+			rw := handle("")
+			So(rw.Body.String(), ShouldContainSubstring, `"error":"access_denied"`)
+			So(rw.Body.String(), ShouldNotContainSubstring, "client-access-token")
+			So(events.payloads, ShouldHaveLength, 1)
+		})
+	})
+}
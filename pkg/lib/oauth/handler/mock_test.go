@@ -4,10 +4,12 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/authgear/authgear-server/pkg/api/event"
 	"github.com/authgear/authgear-server/pkg/auth/webapp"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticationinfo"
 	"github.com/authgear/authgear-server/pkg/lib/oauth"
 	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
+	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
 	"github.com/authgear/authgear-server/pkg/util/httputil"
 )
 
@@ -23,10 +25,35 @@ func (mockURLsProvider) FromWebAppURL(r protocol.AuthorizationRequest) *url.URL
 	return u
 }
 
+func (mockURLsProvider) ConsentURL(code string) *url.URL {
+	u, _ := url.Parse("https://auth/consent?code=" + code)
+	return u
+}
+
 func (mockURLsProvider) AuthenticateURL(opts webapp.AuthenticateURLOptions) (httputil.Result, error) {
 	return &httputil.ResultRedirect{URL: "https://auth/authenticate"}, nil
 }
 
+func (mockURLsProvider) DeviceVerificationURL() *url.URL {
+	u, _ := url.Parse("https://auth/device")
+	return u
+}
+
+func (mockURLsProvider) DeviceVerificationCompleteURL(userCode string) *url.URL {
+	u, _ := url.Parse("https://auth/device?user_code=" + userCode)
+	return u
+}
+
+func (mockURLsProvider) DeviceApprovalURL(userCode string) *url.URL {
+	u, _ := url.Parse("https://auth/device/approve?user_code=" + userCode)
+	return u
+}
+
+func (mockURLsProvider) DeviceSuccessURL() *url.URL {
+	u, _ := url.Parse("https://auth/device/success")
+	return u
+}
+
 type mockAuthzStore struct {
 	authzs []oauth.Authorization
 }
@@ -49,6 +76,16 @@ func (m *mockAuthzStore) GetByID(id string) (*oauth.Authorization, error) {
 	return nil, oauth.ErrAuthorizationNotFound
 }
 
+func (m *mockAuthzStore) ListByUserID(userID string) ([]*oauth.Authorization, error) {
+	var authzs []*oauth.Authorization
+	for i, a := range m.authzs {
+		if a.UserID == userID {
+			authzs = append(authzs, &m.authzs[i])
+		}
+	}
+	return authzs, nil
+}
+
 func (m *mockAuthzStore) Create(authz *oauth.Authorization) error {
 	m.authzs = append(m.authzs, *authz)
 	return nil
@@ -119,6 +156,129 @@ func (m *mockCodeGrantStore) DeleteCodeGrant(grant *oauth.CodeGrant) error {
 	return nil
 }
 
+type mockPushedAuthorizationRequestStore struct {
+	requests []oauth.PushedAuthorizationRequest
+}
+
+func (m *mockPushedAuthorizationRequestStore) GetPushedAuthorizationRequest(requestURIHash string) (*oauth.PushedAuthorizationRequest, error) {
+	for _, r := range m.requests {
+		if r.RequestURIHash == requestURIHash {
+			return &r, nil
+		}
+	}
+	return nil, oauth.ErrGrantNotFound
+}
+
+func (m *mockPushedAuthorizationRequestStore) CreatePushedAuthorizationRequest(r *oauth.PushedAuthorizationRequest) error {
+	m.requests = append(m.requests, *r)
+	return nil
+}
+
+func (m *mockPushedAuthorizationRequestStore) UpdatePushedAuthorizationRequest(r *oauth.PushedAuthorizationRequest) error {
+	for i, rr := range m.requests {
+		if rr.RequestURIHash == r.RequestURIHash {
+			m.requests[i] = *r
+		}
+	}
+	return nil
+}
+
+func (m *mockPushedAuthorizationRequestStore) DeletePushedAuthorizationRequest(r *oauth.PushedAuthorizationRequest) error {
+	n := 0
+	for _, rr := range m.requests {
+		if rr.RequestURIHash != r.RequestURIHash {
+			m.requests[n] = rr
+			n++
+		}
+	}
+	m.requests = m.requests[:n]
+	return nil
+}
+
+type mockConsentRequestStore struct {
+	requests []oauth.ConsentRequest
+}
+
+func (m *mockConsentRequestStore) GetConsentRequest(codeHash string) (*oauth.ConsentRequest, error) {
+	for _, r := range m.requests {
+		if r.CodeHash == codeHash {
+			return &r, nil
+		}
+	}
+	return nil, oauth.ErrGrantNotFound
+}
+
+func (m *mockConsentRequestStore) CreateConsentRequest(r *oauth.ConsentRequest) error {
+	m.requests = append(m.requests, *r)
+	return nil
+}
+
+func (m *mockConsentRequestStore) DeleteConsentRequest(r *oauth.ConsentRequest) error {
+	n := 0
+	for _, rr := range m.requests {
+		if rr.CodeHash != r.CodeHash {
+			m.requests[n] = rr
+			n++
+		}
+	}
+	m.requests = m.requests[:n]
+	return nil
+}
+
+type mockDeviceCodeGrantStore struct {
+	grants []oauth.DeviceCodeGrant
+	// conflicts is the number of user codes to be rejected as taken.
+	conflicts int
+}
+
+func (m *mockDeviceCodeGrantStore) GetDeviceCodeGrant(deviceCodeHash string) (*oauth.DeviceCodeGrant, error) {
+	for _, g := range m.grants {
+		if g.DeviceCodeHash == deviceCodeHash {
+			return &g, nil
+		}
+	}
+	return nil, oauth.ErrGrantNotFound
+}
+
+func (m *mockDeviceCodeGrantStore) GetDeviceCodeGrantByUserCode(userCode string) (*oauth.DeviceCodeGrant, error) {
+	for _, g := range m.grants {
+		if g.UserCode == userCode {
+			return &g, nil
+		}
+	}
+	return nil, oauth.ErrGrantNotFound
+}
+
+func (m *mockDeviceCodeGrantStore) CreateDeviceCodeGrant(grant *oauth.DeviceCodeGrant) error {
+	if m.conflicts > 0 {
+		m.conflicts--
+		return oauth.ErrUserCodeAlreadyExists
+	}
+	m.grants = append(m.grants, *grant)
+	return nil
+}
+
+func (m *mockDeviceCodeGrantStore) UpdateDeviceCodeGrant(grant *oauth.DeviceCodeGrant) error {
+	for i, g := range m.grants {
+		if g.DeviceCodeHash == grant.DeviceCodeHash {
+			m.grants[i] = *grant
+		}
+	}
+	return nil
+}
+
+func (m *mockDeviceCodeGrantStore) DeleteDeviceCodeGrant(grant *oauth.DeviceCodeGrant) error {
+	n := 0
+	for _, g := range m.grants {
+		if g.DeviceCodeHash != grant.DeviceCodeHash {
+			m.grants[n] = g
+			n++
+		}
+	}
+	m.grants = m.grants[:n]
+	return nil
+}
+
 type mockAuthenticationInfoService struct {
 	Entry *authenticationinfo.Entry
 }
@@ -136,3 +296,48 @@ func (m *mockCookieManager) GetCookie(r *http.Request, def *httputil.CookieDef)
 func (m *mockCookieManager) ClearCookie(def *httputil.CookieDef) *http.Cookie {
 	return &http.Cookie{}
 }
+
+type mockEventService struct {
+	mutations *event.Mutations
+	err       error
+	payloads  []event.Payload
+}
+
+func (m *mockEventService) DispatchEvent(payload event.Payload) error {
+	m.payloads = append(m.payloads, payload)
+	if m.err != nil {
+		return m.err
+	}
+	if m.mutations != nil {
+		payload.(event.BlockingPayload).ApplyMutations(*m.mutations)
+	}
+	return nil
+}
+
+type mockHooks struct {
+	blockingEvents []event.Type
+}
+
+func (m *mockHooks) WillDeliverBlockingEvent(eventType event.Type) bool {
+	for _, t := range m.blockingEvents {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type mockRateLimiter struct {
+	taken map[string]int
+}
+
+func (m *mockRateLimiter) TakeToken(bucket ratelimit.Bucket) error {
+	if m.taken == nil {
+		m.taken = map[string]int{}
+	}
+	if m.taken[bucket.Key] >= bucket.Size {
+		return ratelimit.ErrTooManyRequests
+	}
+	m.taken[bucket.Key]++
+	return nil
+}
@@ -9,7 +9,8 @@ import (
 
 type (
 	tokenResultOK struct {
-		Response protocol.TokenResponse
+		StatusCode int
+		Response   protocol.TokenResponse
 	}
 	tokenResultError struct {
 		StatusCode    int
@@ -23,7 +24,11 @@ func (t tokenResultOK) WriteResponse(rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Set("Content-Type", "application/json")
 	rw.Header().Set("Cache-Control", "no-store")
 	rw.Header().Set("Pragma", "no-cache")
-	rw.WriteHeader(http.StatusOK)
+	if t.StatusCode == 0 {
+		rw.WriteHeader(http.StatusOK)
+	} else {
+		rw.WriteHeader(t.StatusCode)
+	}
 
 	encoder := json.NewEncoder(rw)
 	err := encoder.Encode(t.Response)
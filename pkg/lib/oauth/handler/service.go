@@ -4,7 +4,11 @@ import (
 	"crypto/subtle"
 	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn/user"
 	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/oauth"
 	"github.com/authgear/authgear-server/pkg/lib/oauth/protocol"
@@ -30,6 +34,7 @@ type TokenService struct {
 	GenerateToken     TokenGenerator
 	Clock             clock.Clock
 	Users             TokenHandlerUserFacade
+	Events            EventService
 }
 
 func (s *TokenService) IssueOfflineGrant(
@@ -74,6 +79,18 @@ func (s *TokenService) IssueOfflineGrant(
 		return nil, err
 	}
 
+	err = s.Events.DispatchEvent(&nonblocking.SessionCreatedEventPayload{
+		UserRef: model.UserRef{
+			Meta: model.Meta{
+				ID: opts.AuthenticationInfo.UserID,
+			},
+		},
+		Session: *offlineGrant.ToAPIModel(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	resp.RefreshToken(oauth.EncodeRefreshToken(token, offlineGrant.ID))
 	return offlineGrant, nil
 }
@@ -85,6 +102,7 @@ func (s *TokenService) IssueAccessGrant(
 	userID string,
 	sessionID string,
 	sessionKind oauth.GrantSessionKind,
+	customClaims map[string]interface{},
 	resp protocol.TokenResponse,
 ) error {
 	token := s.GenerateToken()
@@ -105,7 +123,28 @@ func (s *TokenService) IssueAccessGrant(
 		return err
 	}
 
-	at, err := s.AccessTokenIssuer.EncodeAccessToken(client, accessGrant, userID, token)
+	at, err := s.AccessTokenIssuer.EncodeAccessToken(client, accessGrant, userID, token, customClaims)
+	if err != nil {
+		return err
+	}
+
+	resp.TokenType("Bearer")
+	resp.AccessToken(at)
+	resp.ExpiresIn(int(client.AccessTokenLifetime))
+	return nil
+}
+
+// IssueClientAccessToken issues a JWT access token for the client itself.
+// It is not tied to any user session so no access grant is created.
+func (s *TokenService) IssueClientAccessToken(
+	client *config.OAuthClientConfig,
+	scopes []string,
+	resp protocol.TokenResponse,
+) error {
+	now := s.Clock.NowUTC()
+	expireAt := now.Add(client.AccessTokenLifetime.Duration())
+
+	at, err := s.AccessTokenIssuer.EncodeClientAccessToken(client, scopes, now, expireAt)
 	if err != nil {
 		return err
 	}
@@ -113,6 +152,7 @@ func (s *TokenService) IssueAccessGrant(
 	resp.TokenType("Bearer")
 	resp.AccessToken(at)
 	resp.ExpiresIn(int(client.AccessTokenLifetime))
+	resp.Scope(strings.Join(scopes, " "))
 	return nil
 }
 
@@ -152,9 +192,11 @@ func (s *TokenService) ParseRefreshToken(token string) (*oauth.Authorization, *o
 		return nil, nil, err
 	}
 
-	// Check if the user has been disabled.
+	// Check if the user has been disabled or deleted.
 	u, err := s.Users.GetRaw(offlineGrant.Attrs.UserID)
-	if err != nil {
+	if errors.Is(err, user.ErrUserNotFound) {
+		return nil, nil, errInvalidRefreshToken
+	} else if err != nil {
 		return nil, nil, err
 	}
 
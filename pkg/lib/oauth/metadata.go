@@ -9,10 +9,16 @@ func (p *MetadataProvider) PopulateMetadata(meta map[string]interface{}) {
 	meta["token_endpoint"] = p.Endpoints.TokenEndpointURL().String()
 	meta["response_types_supported"] = []string{"code", "none"}
 	meta["response_modes_supported"] = []string{"query", "fragment", "form_post"}
-	meta["grant_types_supported"] = []string{"authorization_code", "refresh_token"}
+	meta["grant_types_supported"] = []string{"authorization_code", "refresh_token", "client_credentials", DeviceCodeGrantType}
 	meta["code_challenge_methods_supported"] = []string{"S256"}
+	meta["device_authorization_endpoint"] = p.Endpoints.DeviceAuthorizationEndpointURL().String()
 	meta["revocation_endpoint"] = p.Endpoints.RevokeEndpointURL().String()
-	// We do not support confidential client (i.e. client_secret) at the moment,
-	// so we do not perform authentication at all at the token endpoint.
-	meta["token_endpoint_auth_methods_supported"] = []string{}
+	meta["introspection_endpoint"] = p.Endpoints.IntrospectionEndpointURL().String()
+	meta["introspection_endpoint_auth_methods_supported"] = []string{"client_secret_basic", "client_secret_post"}
+	meta["pushed_authorization_request_endpoint"] = p.Endpoints.PushedAuthorizationRequestEndpointURL().String()
+	// Individual clients may still be configured to require pushed authorization requests.
+	meta["require_pushed_authorization_requests"] = false
+	// Public clients do not authenticate at the token endpoint,
+	// while confidential clients authenticate with client_secret.
+	meta["token_endpoint_auth_methods_supported"] = []string{"none", "client_secret_basic", "client_secret_post"}
 }
@@ -112,6 +112,7 @@ type IssueIDTokenOptions struct {
 	SID                string
 	Nonce              string
 	AuthenticationInfo authenticationinfo.T
+	CustomClaims       map[string]interface{}
 }
 
 func (ti *IDTokenIssuer) IssueIDToken(opts IssueIDTokenOptions) (string, error) {
@@ -146,6 +147,10 @@ func (ti *IDTokenIssuer) IssueIDToken(opts IssueIDTokenOptions) (string, error)
 		_ = claims.Set("nonce", nonce)
 	}
 
+	for name, value := range opts.CustomClaims {
+		_ = claims.Set(name, value)
+	}
+
 	// Sign the token.
 	signed, err := ti.sign(claims)
 	if err != nil {
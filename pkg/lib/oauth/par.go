@@ -0,0 +1,26 @@
+package oauth
+
+import (
+	"time"
+)
+
+// RequestURIPrefix is the prefix of request_uri issued by the pushed authorization request endpoint.
+// See RFC9126 section 2.2.
+// nolint: gosec
+const RequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+type PushedAuthorizationRequest struct {
+	AppID    string `json:"app_id"`
+	ClientID string `json:"client_id"`
+
+	CreatedAt      time.Time `json:"created_at"`
+	ExpireAt       time.Time `json:"expire_at"`
+	RequestURIHash string    `json:"request_uri_hash"`
+
+	Parameters map[string]string `json:"parameters"`
+
+	// Used indicates the request_uri has been used at the authorization endpoint.
+	// A used request is kept until the end-user finishes the interaction,
+	// but it cannot be used at the authorization endpoint again.
+	Used bool `json:"used"`
+}
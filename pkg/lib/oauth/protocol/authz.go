@@ -15,6 +15,9 @@ func (r AuthorizationRequest) ResponseMode() string { return r["response_mode"]
 func (r AuthorizationRequest) Scope() []string      { return parseSpaceDelimitedString(r["scope"]) }
 func (r AuthorizationRequest) State() string        { return r["state"] }
 
+// Pushed authorization request extension
+func (r AuthorizationRequest) RequestURI() string { return r["request_uri"] }
+
 // OIDC extension
 func (r AuthorizationRequest) Prompt() []string    { return parseSpaceDelimitedString(r["prompt"]) }
 func (r AuthorizationRequest) Nonce() string       { return r["nonce"] }
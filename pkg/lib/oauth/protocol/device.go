@@ -0,0 +1,18 @@
+package protocol
+
+type DeviceAuthorizationRequest map[string]string
+type DeviceAuthorizationResponse map[string]interface{}
+
+// OAuth 2.0 Device Authorization Grant
+
+func (r DeviceAuthorizationRequest) ClientID() string { return r["client_id"] }
+func (r DeviceAuthorizationRequest) Scope() []string  { return parseSpaceDelimitedString(r["scope"]) }
+
+func (r DeviceAuthorizationResponse) DeviceCode(v string)      { r["device_code"] = v }
+func (r DeviceAuthorizationResponse) UserCode(v string)        { r["user_code"] = v }
+func (r DeviceAuthorizationResponse) VerificationURI(v string) { r["verification_uri"] = v }
+func (r DeviceAuthorizationResponse) VerificationURIComplete(v string) {
+	r["verification_uri_complete"] = v
+}
+func (r DeviceAuthorizationResponse) ExpiresIn(v int) { r["expires_in"] = v }
+func (r DeviceAuthorizationResponse) Interval(v int)  { r["interval"] = v }
@@ -0,0 +1,21 @@
+package protocol
+
+import "strings"
+
+type IntrospectionRequest map[string]string
+type IntrospectionResponse map[string]interface{}
+
+// OAuth 2.0 Token Introspection
+
+func (r IntrospectionRequest) ClientID() string      { return r["client_id"] }
+func (r IntrospectionRequest) Token() string         { return r["token"] }
+func (r IntrospectionRequest) TokenTypeHint() string { return r["token_type_hint"] }
+
+func (r IntrospectionResponse) Active(v bool)      { r["active"] = v }
+func (r IntrospectionResponse) Subject(v string)   { r["sub"] = v }
+func (r IntrospectionResponse) ClientID(v string)  { r["client_id"] = v }
+func (r IntrospectionResponse) Scope(v []string)   { r["scope"] = strings.Join(v, " ") }
+func (r IntrospectionResponse) TokenType(v string) { r["token_type"] = v }
+func (r IntrospectionResponse) ExpiresAt(v int64)  { r["exp"] = v }
+func (r IntrospectionResponse) IssuedAt(v int64)   { r["iat"] = v }
+func (r IntrospectionResponse) SessionID(v string) { r["sid"] = v }
@@ -0,0 +1,8 @@
+package protocol
+
+type PushedAuthorizationResponse map[string]interface{}
+
+// OAuth 2.0 Pushed Authorization Requests
+
+func (r PushedAuthorizationResponse) RequestURI(v string) { r["request_uri"] = v }
+func (r PushedAuthorizationResponse) ExpiresIn(v int)     { r["expires_in"] = v }
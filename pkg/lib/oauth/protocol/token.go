@@ -17,6 +17,8 @@ func (r TokenRequest) GrantType() string    { return r["grant_type"] }
 func (r TokenRequest) Code() string         { return r["code"] }
 func (r TokenRequest) RedirectURI() string  { return r["redirect_uri"] }
 func (r TokenRequest) ClientID() string     { return r["client_id"] }
+func (r TokenRequest) ClientSecret() string { return r["client_secret"] }
+func (r TokenRequest) Scope() []string      { return parseSpaceDelimitedString(r["scope"]) }
 func (r TokenRequest) RefreshToken() string { return r["refresh_token"] }
 func (r TokenRequest) JWT() string          { return r["jwt"] }
 
@@ -34,6 +36,10 @@ func (r TokenResponse) IDToken(v string) { r["id_token"] = v }
 
 func (r TokenRequest) CodeVerifier() string { return r["code_verifier"] }
 
+// Device Authorization Grant extension
+
+func (r TokenRequest) DeviceCode() string { return r["device_code"] }
+
 // Proprietary
 func (r TokenRequest) DeviceInfo() (map[string]interface{}, error) {
 	encoded := r["x_device_info"]
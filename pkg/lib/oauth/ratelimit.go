@@ -0,0 +1,31 @@
+package oauth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
+	"github.com/authgear/authgear-server/pkg/util/duration"
+)
+
+// DeviceCodePollRateLimitBucket limits the client to poll the token endpoint
+// at most once per interval with the same device code.
+func DeviceCodePollRateLimitBucket(deviceCodeHash string, interval time.Duration) ratelimit.Bucket {
+	return ratelimit.Bucket{
+		Key:         fmt.Sprintf("device-code-poll:%s", deviceCodeHash),
+		Size:        1,
+		ResetPeriod: interval,
+	}
+}
+
+// DeviceVerifyUserCodeRateLimitBucket limits the attempts to enter a user code,
+// so that user codes cannot be guessed.
+func DeviceVerifyUserCodeRateLimitBucket(ip string) ratelimit.Bucket {
+	return ratelimit.Bucket{
+		Name:        config.RateLimitDeviceVerifyUserCode,
+		Key:         fmt.Sprintf("device-verify-user-code:%s", ip),
+		Size:        10,
+		ResetPeriod: duration.PerMinute,
+	}
+}
@@ -6,6 +6,22 @@ func codeGrantKey(appID, codeHash string) string {
 	return fmt.Sprintf("app:%s:code-grant:%s", appID, codeHash)
 }
 
+func deviceCodeGrantKey(appID, deviceCodeHash string) string {
+	return fmt.Sprintf("app:%s:device-code-grant:%s", appID, deviceCodeHash)
+}
+
+func deviceUserCodeKey(appID, userCode string) string {
+	return fmt.Sprintf("app:%s:device-user-code:%s", appID, userCode)
+}
+
+func pushedAuthorizationRequestKey(appID, requestURIHash string) string {
+	return fmt.Sprintf("app:%s:pushed-authz-request:%s", appID, requestURIHash)
+}
+
+func consentRequestKey(appID, codeHash string) string {
+	return fmt.Sprintf("app:%s:consent-request:%s", appID, codeHash)
+}
+
 func accessGrantKey(appID, tokenHash string) string {
 	return fmt.Sprintf("app:%s:access-grant:%s", appID, tokenHash)
 }
@@ -55,6 +55,33 @@ func (s *Store) unmarshalCodeGrant(data []byte) (*oauth.CodeGrant, error) {
 	return &g, nil
 }
 
+func (s *Store) unmarshalDeviceCodeGrant(data []byte) (*oauth.DeviceCodeGrant, error) {
+	var g oauth.DeviceCodeGrant
+	err := json.Unmarshal(data, &g)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (s *Store) unmarshalPushedAuthorizationRequest(data []byte) (*oauth.PushedAuthorizationRequest, error) {
+	var r oauth.PushedAuthorizationRequest
+	err := json.Unmarshal(data, &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *Store) unmarshalConsentRequest(data []byte) (*oauth.ConsentRequest, error) {
+	var r oauth.ConsentRequest
+	err := json.Unmarshal(data, &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
 func (s *Store) unmarshalAccessGrant(data []byte) (*oauth.AccessGrant, error) {
 	var g oauth.AccessGrant
 	err := json.Unmarshal(data, &g)
@@ -94,6 +121,8 @@ func (s *Store) unmarshalAppSessionToken(data []byte) (*oauth.AppSessionToken, e
 	return &t, nil
 }
 
+var errGrantAlreadyExists = errors.New("grant already exist")
+
 func (s *Store) save(conn *goredis.Conn, key string, value interface{}, expireAt time.Time, ifNotExists bool) error {
 	ctx := context.Background()
 	data, err := json.Marshal(value)
@@ -109,7 +138,7 @@ func (s *Store) save(conn *goredis.Conn, key string, value interface{}, expireAt
 	}
 	if errors.Is(err, goredis.Nil) {
 		if ifNotExists {
-			return errors.New("grant already exist")
+			return errGrantAlreadyExists
 		}
 		return oauth.ErrGrantNotFound
 	} else if err != nil {
@@ -157,6 +186,156 @@ func (s *Store) DeleteCodeGrant(grant *oauth.CodeGrant) error {
 	})
 }
 
+func (s *Store) GetDeviceCodeGrant(deviceCodeHash string) (*oauth.DeviceCodeGrant, error) {
+	var g *oauth.DeviceCodeGrant
+	err := s.Redis.WithConn(func(conn *goredis.Conn) error {
+		data, err := s.loadData(conn, deviceCodeGrantKey(string(s.AppID), deviceCodeHash))
+		if err != nil {
+			return err
+		}
+		g, err = s.unmarshalDeviceCodeGrant(data)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (s *Store) GetDeviceCodeGrantByUserCode(userCode string) (*oauth.DeviceCodeGrant, error) {
+	var g *oauth.DeviceCodeGrant
+	err := s.Redis.WithConn(func(conn *goredis.Conn) error {
+		data, err := s.loadData(conn, deviceUserCodeKey(string(s.AppID), userCode))
+		if err != nil {
+			return err
+		}
+		var deviceCodeHash string
+		err = json.Unmarshal(data, &deviceCodeHash)
+		if err != nil {
+			return err
+		}
+
+		data, err = s.loadData(conn, deviceCodeGrantKey(string(s.AppID), deviceCodeHash))
+		if err != nil {
+			return err
+		}
+		g, err = s.unmarshalDeviceCodeGrant(data)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (s *Store) CreateDeviceCodeGrant(grant *oauth.DeviceCodeGrant) error {
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		// Reserve the user code first so that user codes are unique among
+		// the outstanding device code grants.
+		err := s.save(conn, deviceUserCodeKey(grant.AppID, grant.UserCode), grant.DeviceCodeHash, grant.ExpireAt, true)
+		if errors.Is(err, errGrantAlreadyExists) {
+			return oauth.ErrUserCodeAlreadyExists
+		} else if err != nil {
+			return err
+		}
+		return s.save(conn, deviceCodeGrantKey(grant.AppID, grant.DeviceCodeHash), grant, grant.ExpireAt, true)
+	})
+}
+
+func (s *Store) UpdateDeviceCodeGrant(grant *oauth.DeviceCodeGrant) error {
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		return s.save(conn, deviceCodeGrantKey(grant.AppID, grant.DeviceCodeHash), grant, grant.ExpireAt, false)
+	})
+}
+
+func (s *Store) DeleteDeviceCodeGrant(grant *oauth.DeviceCodeGrant) error {
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		err := s.del(conn, deviceUserCodeKey(grant.AppID, grant.UserCode))
+		if err != nil {
+			return err
+		}
+		return s.del(conn, deviceCodeGrantKey(grant.AppID, grant.DeviceCodeHash))
+	})
+}
+
+func (s *Store) GetPushedAuthorizationRequest(requestURIHash string) (*oauth.PushedAuthorizationRequest, error) {
+	var r *oauth.PushedAuthorizationRequest
+	err := s.Redis.WithConn(func(conn *goredis.Conn) error {
+		data, err := s.loadData(conn, pushedAuthorizationRequestKey(string(s.AppID), requestURIHash))
+		if err != nil {
+			return err
+		}
+		r, err = s.unmarshalPushedAuthorizationRequest(data)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *Store) CreatePushedAuthorizationRequest(r *oauth.PushedAuthorizationRequest) error {
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		return s.save(conn, pushedAuthorizationRequestKey(r.AppID, r.RequestURIHash), r, r.ExpireAt, true)
+	})
+}
+
+func (s *Store) UpdatePushedAuthorizationRequest(r *oauth.PushedAuthorizationRequest) error {
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		return s.save(conn, pushedAuthorizationRequestKey(r.AppID, r.RequestURIHash), r, r.ExpireAt, false)
+	})
+}
+
+func (s *Store) DeletePushedAuthorizationRequest(r *oauth.PushedAuthorizationRequest) error {
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		return s.del(conn, pushedAuthorizationRequestKey(r.AppID, r.RequestURIHash))
+	})
+}
+
+func (s *Store) GetConsentRequest(codeHash string) (*oauth.ConsentRequest, error) {
+	var r *oauth.ConsentRequest
+	err := s.Redis.WithConn(func(conn *goredis.Conn) error {
+		data, err := s.loadData(conn, consentRequestKey(string(s.AppID), codeHash))
+		if err != nil {
+			return err
+		}
+		r, err = s.unmarshalConsentRequest(data)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *Store) CreateConsentRequest(r *oauth.ConsentRequest) error {
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		return s.save(conn, consentRequestKey(r.AppID, r.CodeHash), r, r.ExpireAt, true)
+	})
+}
+
+func (s *Store) DeleteConsentRequest(r *oauth.ConsentRequest) error {
+	return s.Redis.WithConn(func(conn *goredis.Conn) error {
+		return s.del(conn, consentRequestKey(r.AppID, r.CodeHash))
+	})
+}
+
 func (s *Store) GetAccessGrant(tokenHash string) (*oauth.AccessGrant, error) {
 	var g *oauth.AccessGrant
 	err := s.Redis.WithConn(func(conn *goredis.Conn) error {
@@ -12,6 +12,27 @@ type CodeGrantStore interface {
 	DeleteCodeGrant(*CodeGrant) error
 }
 
+type DeviceCodeGrantStore interface {
+	GetDeviceCodeGrant(deviceCodeHash string) (*DeviceCodeGrant, error)
+	GetDeviceCodeGrantByUserCode(userCode string) (*DeviceCodeGrant, error)
+	CreateDeviceCodeGrant(*DeviceCodeGrant) error
+	UpdateDeviceCodeGrant(*DeviceCodeGrant) error
+	DeleteDeviceCodeGrant(*DeviceCodeGrant) error
+}
+
+type ConsentRequestStore interface {
+	GetConsentRequest(codeHash string) (*ConsentRequest, error)
+	CreateConsentRequest(*ConsentRequest) error
+	DeleteConsentRequest(*ConsentRequest) error
+}
+
+type PushedAuthorizationRequestStore interface {
+	GetPushedAuthorizationRequest(requestURIHash string) (*PushedAuthorizationRequest, error)
+	CreatePushedAuthorizationRequest(*PushedAuthorizationRequest) error
+	UpdatePushedAuthorizationRequest(*PushedAuthorizationRequest) error
+	DeletePushedAuthorizationRequest(*PushedAuthorizationRequest) error
+}
+
 type OfflineGrantStore interface {
 	GetOfflineGrant(id string) (*OfflineGrant, error)
 	CreateOfflineGrant(offlineGrant *OfflineGrant, expireAt time.Time) error
@@ -15,6 +15,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/jwtutil"
+	"github.com/authgear/authgear-server/pkg/util/uuid"
 )
 
 type UserClaimsProvider interface {
@@ -32,7 +33,7 @@ type AccessTokenEncoding struct {
 	BaseURL    BaseURLProvider
 }
 
-func (e *AccessTokenEncoding) EncodeAccessToken(client *config.OAuthClientConfig, grant *AccessGrant, userID string, token string) (string, error) {
+func (e *AccessTokenEncoding) EncodeAccessToken(client *config.OAuthClientConfig, grant *AccessGrant, userID string, token string, customClaims map[string]interface{}) (string, error) {
 	if !client.IssueJWTAccessToken {
 		return token, nil
 	}
@@ -53,6 +54,39 @@ func (e *AccessTokenEncoding) EncodeAccessToken(client *config.OAuthClientConfig
 	// verified JWT.
 	_ = claims.Set(jwt.JwtIDKey, grant.TokenHash)
 
+	for name, value := range customClaims {
+		_ = claims.Set(name, value)
+	}
+
+	jwk, _ := e.Secrets.Set.Get(0)
+
+	hdr := jws.NewHeaders()
+	_ = hdr.Set("typ", "at+jwt")
+
+	signed, err := jwtutil.SignWithHeader(claims, hdr, jwa.RS256, jwk)
+	if err != nil {
+		return "", err
+	}
+
+	return string(signed), nil
+}
+
+// EncodeClientAccessToken encodes a JWT access token issued to the client itself,
+// i.e. the client credentials grant. The token is not tied to any user or session,
+// so there is no access grant backing it.
+func (e *AccessTokenEncoding) EncodeClientAccessToken(client *config.OAuthClientConfig, scopes []string, issuedAt time.Time, expireAt time.Time) (string, error) {
+	claims := jwt.New()
+
+	_ = claims.Set(jwt.IssuerKey, e.BaseURL.BaseURL().String())
+	_ = claims.Set(jwt.SubjectKey, client.ClientID)
+	_ = claims.Set(jwt.AudienceKey, e.BaseURL.BaseURL().String())
+	_ = claims.Set(jwt.IssuedAtKey, issuedAt.Unix())
+	_ = claims.Set(jwt.ExpirationKey, expireAt.Unix())
+	_ = claims.Set(jwt.JwtIDKey, uuid.New())
+	_ = claims.Set("client_id", client.ClientID)
+	// See RFC9068 section 2.2.3.
+	_ = claims.Set("scope", strings.Join(scopes, " "))
+
 	jwk, _ := e.Secrets.Set.Get(0)
 
 	hdr := jws.NewHeaders()
@@ -94,6 +128,41 @@ func (e *AccessTokenEncoding) DecodeAccessToken(encodedToken string) (tok string
 	return token.JwtID(), true, nil
 }
 
+// DecodeClientAccessToken decodes a JWT access token encoded by EncodeClientAccessToken.
+// ok is false if the token is not a client access token.
+func (e *AccessTokenEncoding) DecodeClientAccessToken(encodedToken string) (token jwt.Token, ok bool, err error) {
+	if !strings.HasPrefix(encodedToken, "eyJ") {
+		return nil, false, nil
+	}
+
+	keys, err := jwk.PublicSetOf(e.Secrets.Set)
+	if err != nil {
+		return nil, false, err
+	}
+
+	token, err = jwt.ParseString(encodedToken, jwt.WithKeySet(keys))
+	if err != nil {
+		return nil, false, nil
+	}
+
+	// A client access token is issued to the client itself,
+	// so its subject is the client ID.
+	clientID, _ := token.Get("client_id")
+	if token.Issuer() != e.BaseURL.BaseURL().String() || clientID != token.Subject() {
+		return nil, false, nil
+	}
+
+	err = jwt.Validate(token,
+		jwt.WithClock(&jwtClock{e.Clock}),
+		jwt.WithAudience(e.BaseURL.BaseURL().String()),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return token, true, nil
+}
+
 type jwtClock struct {
 	Clock clock.Clock
 }
@@ -12,5 +12,42 @@ type URLProvider struct {
 }
 
 func (p *URLProvider) FromWebAppURL(r protocol.AuthorizationRequest) *url.URL {
+	// Pushed authorization requests are resolved from request_uri again,
+	// so that the parameters are not exposed in the URL.
+	if requestURI := r.RequestURI(); requestURI != "" {
+		return urlutil.WithQueryParamsAdded(p.Endpoints.FromWebAppEndpointURL(), map[string]string{
+			"client_id":   r.ClientID(),
+			"request_uri": requestURI,
+		})
+	}
 	return urlutil.WithQueryParamsAdded(p.Endpoints.FromWebAppEndpointURL(), r)
 }
+
+func (p *URLProvider) ConsentURL(code string) *url.URL {
+	return urlutil.WithQueryParamsAdded(
+		p.Endpoints.ConsentEndpointURL(),
+		map[string]string{"code": code},
+	)
+}
+
+func (p *URLProvider) DeviceVerificationURL() *url.URL {
+	return p.Endpoints.DeviceVerificationEndpointURL()
+}
+
+func (p *URLProvider) DeviceVerificationCompleteURL(userCode string) *url.URL {
+	return urlutil.WithQueryParamsAdded(
+		p.Endpoints.DeviceVerificationEndpointURL(),
+		map[string]string{"user_code": userCode},
+	)
+}
+
+func (p *URLProvider) DeviceApprovalURL(userCode string) *url.URL {
+	return urlutil.WithQueryParamsAdded(
+		p.Endpoints.DeviceApprovalEndpointURL(),
+		map[string]string{"user_code": userCode},
+	)
+}
+
+func (p *URLProvider) DeviceSuccessURL() *url.URL {
+	return p.Endpoints.DeviceSuccessEndpointURL()
+}
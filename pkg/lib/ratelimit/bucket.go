@@ -1,9 +1,20 @@
 package ratelimit
 
-import "time"
+import (
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+)
 
 type Bucket struct {
+	// Name is the name of the policy the bucket belongs to.
+	// Size, ResetPeriod and SlidingWindow are the built-in values of the policy,
+	// which can be overridden by the app config.
+	Name        config.RateLimitName
 	Key         string
 	Size        int
 	ResetPeriod time.Duration
+	// SlidingWindow makes a token available again after ResetPeriod since it is taken,
+	// instead of refilling all tokens at once after ResetPeriod.
+	SlidingWindow bool
 }
@@ -3,6 +3,7 @@ package ratelimit
 import (
 	"time"
 
+	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/log"
 )
@@ -16,16 +17,34 @@ func NewLogger(lf *log.Factory) Logger {
 // Limiter implements rate limiting using a simple token bucket algorithm.
 // Consumers take token from a bucket every operation, and tokens are refilled
 // periodically.
+//
+// The built-in policy of a bucket can be overridden by the app config,
+// within the ceiling set by the feature config.
 type Limiter struct {
-	Logger  Logger
-	Storage Storage
-	Clock   clock.Clock
+	Logger        Logger
+	Storage       Storage
+	Clock         clock.Clock
+	Config        *config.RateLimitsConfig
+	FeatureConfig *config.RateLimitsFeatureConfig
 }
 
 func (l *Limiter) TakeToken(bucket Bucket) error {
+	bucket, enabled := l.resolve(bucket)
+	if !enabled {
+		return nil
+	}
+
 	return l.Storage.WithConn(func(conn StorageConn) error {
 		now := l.Clock.NowUTC()
 
+		if bucket.SlidingWindow {
+			tokens, err := conn.TakeSlidingWindowToken(bucket, now)
+			if err != nil {
+				return err
+			}
+			return l.checkTokens(bucket, tokens)
+		}
+
 		// Check if we should refill the bucket.
 		resetTime, err := conn.GetResetTime(bucket, now)
 		if err != nil {
@@ -45,27 +64,51 @@ func (l *Limiter) TakeToken(bucket Bucket) error {
 			return err
 		}
 
-		pass := tokens >= 0
-		l.Logger.
-			WithField("key", bucket.Key).
-			WithField("tokens", tokens).
-			WithField("pass", pass).
-			Debug("check rate limit")
+		return l.checkTokens(bucket, tokens)
+	})
+}
 
-		if !pass {
-			// Exhausted tokens, rate limit the request.
-			return ErrTooManyRequests
-		}
+func (l *Limiter) checkTokens(bucket Bucket, tokens int) error {
+	pass := tokens >= 0
+	l.Logger.
+		WithField("key", bucket.Key).
+		WithField("tokens", tokens).
+		WithField("pass", pass).
+		Debug("check rate limit")
 
-		return nil
-	})
+	if !pass {
+		// Exhausted tokens, rate limit the request.
+		return ErrTooManyRequests
+	}
+
+	return nil
 }
 
-// CheckToken return resetDuration and pass based on the given bucket
+// CheckToken return resetDuration and pass based on the given bucket.
+// For sliding window bucket, resetDuration is the duration until the
+// earliest taken token becomes available again.
 func (l *Limiter) CheckToken(bucket Bucket) (pass bool, resetDuration time.Duration, err error) {
+	bucket, enabled := l.resolve(bucket)
+	if !enabled {
+		pass = true
+		return
+	}
+
 	err = l.Storage.WithConn(func(conn StorageConn) error {
 		now := l.Clock.NowUTC()
 
+		if bucket.SlidingWindow {
+			tokens, resetTime, err := conn.CheckSlidingWindowToken(bucket, now)
+			if err != nil {
+				return err
+			}
+			if resetTime.After(now) {
+				resetDuration = resetTime.Sub(now)
+			}
+			pass = tokens >= 1
+			return nil
+		}
+
 		resetTime, err := conn.GetResetTime(bucket, now)
 		if err != nil {
 			return err
@@ -90,3 +133,29 @@ func (l *Limiter) CheckToken(bucket Bucket) (pass bool, resetDuration time.Durat
 
 	return
 }
+
+// resolve applies the app config and the feature config to the built-in policy of bucket.
+// It returns false if the policy is disabled.
+func (l *Limiter) resolve(bucket Bucket) (Bucket, bool) {
+	if bucket.Name == "" {
+		return bucket, true
+	}
+
+	enabled := true
+	if c := l.Config.Get(bucket.Name); c != nil {
+		enabled = c.IsEnabled()
+		bucket.Size, bucket.ResetPeriod = c.Rate(bucket.Size, bucket.ResetPeriod)
+		bucket.SlidingWindow = bucket.SlidingWindow || c.SlidingWindow
+	}
+
+	if fc := l.FeatureConfig.Get(bucket.Name); fc != nil && fc.IsEnabled() {
+		// The app cannot disable the policy, nor loosen it beyond the ceiling.
+		if !enabled || fc.Exceeds(bucket.Size, bucket.ResetPeriod) {
+			enabled = true
+			bucket.Size = fc.Size
+			bucket.ResetPeriod = fc.Period.Duration()
+		}
+	}
+
+	return bucket, enabled
+}
@@ -6,6 +6,7 @@ import (
 
 	. "github.com/smartystreets/goconvey/convey"
 
+	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/ratelimit"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/log"
@@ -17,7 +18,8 @@ type storageMemoryItem struct {
 }
 
 type storageMemory struct {
-	items map[string]*storageMemoryItem
+	items        map[string]*storageMemoryItem
+	slidingItems map[string][]time.Time
 }
 
 func (s *storageMemory) WithConn(f func(ratelimit.StorageConn) error) error {
@@ -64,14 +66,46 @@ func (s *storageMemory) Reset(bucket ratelimit.Bucket, now time.Time) error {
 	return nil
 }
 
+func (s *storageMemory) removeExpiredSlidingWindowTokens(bucket ratelimit.Bucket, now time.Time) []time.Time {
+	var takenAt []time.Time
+	for _, t := range s.slidingItems[bucket.Key] {
+		if t.After(now.Add(-bucket.ResetPeriod)) {
+			takenAt = append(takenAt, t)
+		}
+	}
+	s.slidingItems[bucket.Key] = takenAt
+	return takenAt
+}
+
+func (s *storageMemory) TakeSlidingWindowToken(bucket ratelimit.Bucket, now time.Time) (int, error) {
+	takenAt := s.removeExpiredSlidingWindowTokens(bucket, now)
+	tokens := bucket.Size - len(takenAt) - 1
+	if tokens >= 0 {
+		s.slidingItems[bucket.Key] = append(takenAt, now)
+	}
+	return tokens, nil
+}
+
+func (s *storageMemory) CheckSlidingWindowToken(bucket ratelimit.Bucket, now time.Time) (int, time.Time, error) {
+	takenAt := s.removeExpiredSlidingWindowTokens(bucket, now)
+	resetTime := now
+	if len(takenAt) > 0 {
+		resetTime = takenAt[0].Add(bucket.ResetPeriod)
+	}
+	return bucket.Size - len(takenAt), resetTime, nil
+}
+
 func TestLimiter(t *testing.T) {
 	Convey("Limiter", t, func() {
 		b := ratelimit.Bucket{Key: "bucket", Size: 3, ResetPeriod: 5 * time.Second}
 		c := clock.NewMockClock()
 		limiter := &ratelimit.Limiter{
-			Logger:  ratelimit.Logger{log.Null},
-			Storage: &storageMemory{items: make(map[string]*storageMemoryItem)},
-			Clock:   c,
+			Logger: ratelimit.Logger{log.Null},
+			Storage: &storageMemory{
+				items:        make(map[string]*storageMemoryItem),
+				slidingItems: make(map[string][]time.Time),
+			},
+			Clock: c,
 		}
 
 		Convey("TakeToken", func() {
@@ -252,5 +286,136 @@ func TestLimiter(t *testing.T) {
 			So(pass, ShouldBeFalse)
 			So(err, ShouldBeNil)
 		})
+
+		Convey("sliding window", func() {
+			b := ratelimit.Bucket{Key: "bucket", Size: 3, ResetPeriod: 5 * time.Second, SlidingWindow: true}
+
+			So(limiter.TakeToken(b), ShouldBeNil)
+			c.AdvanceSeconds(1)
+			So(limiter.TakeToken(b), ShouldBeNil)
+			c.AdvanceSeconds(1)
+			So(limiter.TakeToken(b), ShouldBeNil)
+			So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+
+			pass, resetDuration, err := limiter.CheckToken(b)
+			So(err, ShouldBeNil)
+			So(pass, ShouldBeFalse)
+			So(resetDuration, ShouldEqual, 3*time.Second)
+
+			// Only the first token becomes available again.
+			c.AdvanceSeconds(3)
+			pass, _, err = limiter.CheckToken(b)
+			So(err, ShouldBeNil)
+			So(pass, ShouldBeTrue)
+			So(limiter.TakeToken(b), ShouldBeNil)
+			So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+
+			c.AdvanceSeconds(1)
+			So(limiter.TakeToken(b), ShouldBeNil)
+			So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+		})
+
+		Convey("config", func() {
+			b := ratelimit.Bucket{
+				Name:        config.RateLimitSignup,
+				Key:         "signup",
+				Size:        1,
+				ResetPeriod: 5 * time.Second,
+			}
+			disabled := false
+
+			Convey("should override built-in policy", func() {
+				limiter.Config = &config.RateLimitsConfig{
+					Signup: &config.RateLimitConfig{Size: 2, Period: 10},
+				}
+
+				So(limiter.TakeToken(b), ShouldBeNil)
+				So(limiter.TakeToken(b), ShouldBeNil)
+				So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+
+				c.AdvanceSeconds(5)
+				So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+
+				c.AdvanceSeconds(5)
+				So(limiter.TakeToken(b), ShouldBeNil)
+			})
+
+			Convey("should not affect other policies", func() {
+				limiter.Config = &config.RateLimitsConfig{
+					SMSMessage: &config.RateLimitConfig{Size: 2},
+				}
+
+				So(limiter.TakeToken(b), ShouldBeNil)
+				So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+			})
+
+			Convey("should enable sliding window", func() {
+				limiter.Config = &config.RateLimitsConfig{
+					Signup: &config.RateLimitConfig{SlidingWindow: true},
+				}
+
+				So(limiter.TakeToken(b), ShouldBeNil)
+				So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+
+				pass, resetDuration, err := limiter.CheckToken(b)
+				So(err, ShouldBeNil)
+				So(pass, ShouldBeFalse)
+				So(resetDuration, ShouldEqual, 5*time.Second)
+			})
+
+			Convey("should pass if policy is disabled", func() {
+				limiter.Config = &config.RateLimitsConfig{
+					Signup: &config.RateLimitConfig{Enabled: &disabled},
+				}
+
+				for i := 0; i < 10; i++ {
+					So(limiter.TakeToken(b), ShouldBeNil)
+				}
+				pass, _, err := limiter.CheckToken(b)
+				So(err, ShouldBeNil)
+				So(pass, ShouldBeTrue)
+			})
+
+			Convey("should enforce feature config ceiling", func() {
+				limiter.Config = &config.RateLimitsConfig{
+					Signup: &config.RateLimitConfig{Size: 100, Period: 1},
+				}
+				limiter.FeatureConfig = &config.RateLimitsFeatureConfig{
+					Signup: &config.RateLimitFeatureConfig{Size: 2, Period: 5},
+				}
+
+				So(limiter.TakeToken(b), ShouldBeNil)
+				So(limiter.TakeToken(b), ShouldBeNil)
+				So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+			})
+
+			Convey("should enforce feature config ceiling if policy is disabled", func() {
+				limiter.Config = &config.RateLimitsConfig{
+					Signup: &config.RateLimitConfig{Enabled: &disabled},
+				}
+				limiter.FeatureConfig = &config.RateLimitsFeatureConfig{
+					Signup: &config.RateLimitFeatureConfig{Size: 2, Period: 5},
+				}
+
+				So(limiter.TakeToken(b), ShouldBeNil)
+				So(limiter.TakeToken(b), ShouldBeNil)
+				So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+			})
+
+			Convey("should allow policy within feature config ceiling", func() {
+				limiter.Config = &config.RateLimitsConfig{
+					Signup: &config.RateLimitConfig{Size: 1, Period: 60},
+				}
+				limiter.FeatureConfig = &config.RateLimitsFeatureConfig{
+					Signup: &config.RateLimitFeatureConfig{Size: 2, Period: 5},
+				}
+
+				So(limiter.TakeToken(b), ShouldBeNil)
+				So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+
+				c.AdvanceSeconds(5)
+				So(limiter.TakeToken(b), ShouldBeError, ratelimit.ErrTooManyRequests)
+			})
+		})
 	})
 }
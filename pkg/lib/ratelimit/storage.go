@@ -11,4 +11,12 @@ type StorageConn interface {
 	CheckToken(bucket Bucket) (int, error)
 	GetResetTime(bucket Bucket, now time.Time) (time.Time, error)
 	Reset(bucket Bucket, now time.Time) error
+
+	// TakeSlidingWindowToken takes one token if less than bucket.Size tokens
+	// are taken within bucket.ResetPeriod before now.
+	// It returns the number of tokens left, which is negative if no token is taken.
+	TakeSlidingWindowToken(bucket Bucket, now time.Time) (int, error)
+	// CheckSlidingWindowToken returns the number of tokens left,
+	// and the time when the earliest taken token becomes available again.
+	CheckSlidingWindowToken(bucket Bucket, now time.Time) (int, time.Time, error)
 }
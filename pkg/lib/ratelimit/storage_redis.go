@@ -11,6 +11,7 @@ import (
 
 	"github.com/authgear/authgear-server/pkg/lib/config"
 	"github.com/authgear/authgear-server/pkg/lib/infra/redis/appredis"
+	"github.com/authgear/authgear-server/pkg/util/rand"
 )
 
 type StorageRedis struct {
@@ -91,6 +92,74 @@ func (s storageRedisConn) Reset(bucket Bucket, now time.Time) error {
 	return nil
 }
 
+func (s storageRedisConn) TakeSlidingWindowToken(bucket Bucket, now time.Time) (int, error) {
+	ctx := context.Background()
+	key := redisSlidingWindowKey(s.AppID, bucket)
+	member := fmt.Sprintf("%d:%d", now.UnixNano(), rand.SecureRand.Int63())
+
+	// Take the token optimistically, and give it back if the bucket is exhausted.
+	// So concurrent requests never take more than bucket.Size tokens.
+	var card *goredis.IntCmd
+	_, err := s.Conn.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(redisScore(now.Add(-bucket.ResetPeriod)), 10))
+		pipe.ZAdd(ctx, key, &goredis.Z{Score: float64(redisScore(now)), Member: member})
+		card = pipe.ZCard(ctx, key)
+		pipe.PExpire(ctx, key, bucket.ResetPeriod)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	tokens := bucket.Size - int(card.Val())
+	if tokens < 0 {
+		_, err = s.Conn.ZRem(ctx, key, member).Result()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return tokens, nil
+}
+
+func (s storageRedisConn) CheckSlidingWindowToken(bucket Bucket, now time.Time) (int, time.Time, error) {
+	ctx := context.Background()
+	key := redisSlidingWindowKey(s.AppID, bucket)
+	// Exclusive, as tokens taken exactly ResetPeriod ago are available again.
+	min := "(" + strconv.FormatInt(redisScore(now.Add(-bucket.ResetPeriod)), 10)
+
+	count, err := s.Conn.ZCount(ctx, key, min, "+inf").Result()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	resetTime := now
+	oldest, err := s.Conn.ZRangeByScoreWithScores(ctx, key, &goredis.ZRangeBy{
+		Min:   min,
+		Max:   "+inf",
+		Count: 1,
+	}).Result()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if len(oldest) > 0 {
+		takenAt := time.Unix(0, int64(oldest[0].Score)*int64(time.Millisecond)).UTC()
+		resetTime = takenAt.Add(bucket.ResetPeriod)
+	}
+
+	return bucket.Size - int(count), resetTime, nil
+}
+
 func redisBucketKey(appID config.AppID, bucket Bucket) string {
 	return fmt.Sprintf("app:%s:rate-limit:%s", appID, bucket.Key)
 }
+
+func redisSlidingWindowKey(appID config.AppID, bucket Bucket) string {
+	return fmt.Sprintf("app:%s:rate-limit-sliding:%s", appID, bucket.Key)
+}
+
+// redisScore is the score of a token taken at t in the sorted set.
+// Millisecond is used so that the score can be represented by float64 exactly.
+func redisScore(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
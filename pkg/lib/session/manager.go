@@ -51,33 +51,30 @@ func (m *Manager) resolveManagementProvider(session Session) ManagementService {
 	}
 }
 
-func (m *Manager) invalidate(session Session, reason DeleteReason, isAdminAPI bool) (ManagementService, error) {
-	sessionModel := session.ToAPIModel()
-
+func (m *Manager) invalidate(session Session, reason DeleteReason) (ManagementService, error) {
 	provider := m.resolveManagementProvider(session)
 	err := provider.Delete(session)
 	if err != nil {
 		return nil, err
 	}
 
+	return provider, nil
+}
+
+func (m *Manager) Logout(session Session, rw http.ResponseWriter) error {
+	provider, err := m.invalidate(session, DeleteReasonLogout)
+	if err != nil {
+		return err
+	}
+
 	err = m.Events.DispatchEvent(&nonblocking.UserSignedOutEventPayload{
 		UserRef: model.UserRef{
 			Meta: model.Meta{
 				ID: session.GetAuthenticationInfo().UserID,
 			},
 		},
-		Session:  *sessionModel,
-		AdminAPI: isAdminAPI,
+		Session: *session.ToAPIModel(),
 	})
-	if err != nil {
-		return nil, err
-	}
-
-	return provider, nil
-}
-
-func (m *Manager) Logout(session Session, rw http.ResponseWriter) error {
-	provider, err := m.invalidate(session, DeleteReasonLogout, false)
 	if err != nil {
 		return err
 	}
@@ -90,7 +87,21 @@ func (m *Manager) Logout(session Session, rw http.ResponseWriter) error {
 }
 
 func (m *Manager) Revoke(session Session, isAdminAPI bool) error {
-	_, err := m.invalidate(session, DeleteReasonRevoke, isAdminAPI)
+	_, err := m.invalidate(session, DeleteReasonRevoke)
+	if err != nil {
+		return err
+	}
+
+	// Revocation is not signing out, so only session.revoked is dispatched.
+	err = m.Events.DispatchEvent(&nonblocking.SessionRevokedEventPayload{
+		UserRef: model.UserRef{
+			Meta: model.Meta{
+				ID: session.GetAuthenticationInfo().UserID,
+			},
+		},
+		Session:  *session.ToAPIModel(),
+		AdminAPI: isAdminAPI,
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,85 @@
+package session_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/api/event/nonblocking"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/session"
+	"github.com/authgear/authgear-server/pkg/lib/session/idpsession"
+)
+
+type fakeManagementService struct {
+	session.ManagementService
+	deleted []session.Session
+}
+
+func (s *fakeManagementService) Delete(ss session.Session) error {
+	s.deleted = append(s.deleted, ss)
+	return nil
+}
+
+func (s *fakeManagementService) ClearCookie() []*http.Cookie {
+	return nil
+}
+
+type fakeEventService struct {
+	payloads []event.Payload
+}
+
+func (s *fakeEventService) DispatchEvent(payload event.Payload) error {
+	s.payloads = append(s.payloads, payload)
+	return nil
+}
+
+func TestManager(t *testing.T) {
+	Convey("Manager", t, func() {
+		idpSessions := &fakeManagementService{}
+		events := &fakeEventService{}
+		m := &session.Manager{
+			IDPSessions:         idpSessions,
+			AccessTokenSessions: &fakeManagementService{},
+			Events:              events,
+		}
+
+		s := &idpsession.IDPSession{
+			ID:    "session-id",
+			Attrs: *session.NewAttrs("user-id"),
+		}
+		userRef := model.UserRef{
+			Meta: model.Meta{
+				ID: "user-id",
+			},
+		}
+
+		Convey("should dispatch user.signed_out on logout", func() {
+			err := m.Logout(s, httptest.NewRecorder())
+			So(err, ShouldBeNil)
+			So(idpSessions.deleted, ShouldResemble, []session.Session{s})
+			So(events.payloads, ShouldResemble, []event.Payload{
+				&nonblocking.UserSignedOutEventPayload{
+					UserRef: userRef,
+					Session: *s.ToAPIModel(),
+				},
+			})
+		})
+
+		Convey("should dispatch session.revoked only on revocation", func() {
+			err := m.Revoke(s, true)
+			So(err, ShouldBeNil)
+			So(idpSessions.deleted, ShouldResemble, []session.Session{s})
+			So(events.payloads, ShouldResemble, []event.Payload{
+				&nonblocking.SessionRevokedEventPayload{
+					UserRef:  userRef,
+					Session:  *s.ToAPIModel(),
+					AdminAPI: true,
+				},
+			})
+		})
+	})
+}
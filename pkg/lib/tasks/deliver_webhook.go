@@ -0,0 +1,11 @@
+package tasks
+
+const DeliverWebhook = "DeliverWebhook"
+
+type DeliverWebhookParam struct {
+	DeliveryID string
+}
+
+func (p *DeliverWebhookParam) TaskName() string {
+	return DeliverWebhook
+}
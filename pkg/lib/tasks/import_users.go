@@ -0,0 +1,11 @@
+package tasks
+
+const ImportUsers = "ImportUsers"
+
+type ImportUsersParam struct {
+	JobID string
+}
+
+func (p *ImportUsersParam) TaskName() string {
+	return ImportUsers
+}
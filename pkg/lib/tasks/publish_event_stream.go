@@ -0,0 +1,9 @@
+package tasks
+
+const PublishEventStream = "PublishEventStream"
+
+type PublishEventStreamParam struct{}
+
+func (p *PublishEventStreamParam) TaskName() string {
+	return PublishEventStream
+}
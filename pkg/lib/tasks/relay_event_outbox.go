@@ -0,0 +1,9 @@
+package tasks
+
+const RelayEventOutbox = "RelayEventOutbox"
+
+type RelayEventOutboxParam struct{}
+
+func (p *RelayEventOutboxParam) TaskName() string {
+	return RelayEventOutbox
+}
@@ -0,0 +1,9 @@
+package tasks
+
+const ResumeEventDeliveries = "ResumeEventDeliveries"
+
+type ResumeEventDeliveriesParam struct{}
+
+func (p *ResumeEventDeliveriesParam) TaskName() string {
+	return ResumeEventDeliveries
+}
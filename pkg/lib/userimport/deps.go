@@ -0,0 +1,16 @@
+package userimport
+
+import (
+	"github.com/google/wire"
+)
+
+var DependencySet = wire.NewSet(
+	NewRunnerLogger,
+	wire.Struct(new(Store), "*"),
+	wire.Struct(new(Importer), "*"),
+	wire.Struct(new(Runner), "*"),
+	wire.Struct(new(Service), "*"),
+	wire.Bind(new(RunnerStore), new(*Store)),
+	wire.Bind(new(ServiceStore), new(*Store)),
+	wire.Bind(new(RecordImporter), new(*Importer)),
+)
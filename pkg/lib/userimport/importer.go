@@ -0,0 +1,214 @@
+package userimport
+
+import (
+	"errors"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity"
+	"github.com/authgear/authgear-server/pkg/lib/authn/user"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/feature/verification"
+	"github.com/authgear/authgear-server/pkg/util/accesscontrol"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/uuid"
+)
+
+//go:generate mockgen -source=importer.go -destination=importer_mock_test.go -package userimport
+
+var InvalidRecord = apierrors.Invalid.WithReason("InvalidUserImportRecord")
+var DuplicatedIdentity = apierrors.Invalid.WithReason("DuplicatedIdentity")
+
+type UserStore interface {
+	Create(u *user.User) error
+}
+
+type IdentityService interface {
+	New(userID string, spec *identity.Spec, options identity.NewIdentityOptions) (*identity.Info, error)
+	Create(is *identity.Info) error
+	CheckDuplicated(info *identity.Info) (*identity.Info, error)
+}
+
+type PasswordAuthenticatorProvider interface {
+	NewWithHash(userID string, passwordHash []byte, isDefault bool, kind string) (*password.Authenticator, error)
+	Create(*password.Authenticator) error
+}
+
+type VerifiedClaimStore interface {
+	Create(claim *verification.Claim) error
+}
+
+type StandardAttributesService interface {
+	UpdateStandardAttributes(role accesscontrol.Role, userID string, stdAttrs map[string]interface{}) error
+	PopulateIdentityAwareStandardAttributes(userID string) error
+}
+
+type CustomAttributesService interface {
+	UpdateAllCustomAttributes(role accesscontrol.Role, userID string, reprForm map[string]interface{}) error
+}
+
+// Importer creates a user of a record.
+// Hooks are not triggered and the user is not reindexed for search,
+// so that a large amount of users can be imported efficiently.
+type Importer struct {
+	LoginIDConfig      *config.LoginIDConfig
+	OAuthConfig        *config.OAuthSSOConfig
+	Clock              clock.Clock
+	Users              UserStore
+	Identities         IdentityService
+	Passwords          PasswordAuthenticatorProvider
+	VerifiedClaims     VerifiedClaimStore
+	StandardAttributes StandardAttributesService
+	CustomAttributes   CustomAttributesService
+}
+
+// ImportRecord must be called in a transaction,
+// which must be rolled back if an error is returned.
+func (i *Importer) ImportRecord(r *Record) (userID string, err error) {
+	now := i.Clock.NowUTC()
+	userID = uuid.New()
+	// Welcome messages are not sent to imported users.
+	err = i.Users.Create(&user.User{
+		ID:                 userID,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+		StandardAttributes: make(map[string]interface{}),
+	})
+	if err != nil {
+		return
+	}
+
+	var identities []*identity.Info
+	for _, ir := range r.Identities {
+		var info *identity.Info
+		info, err = i.createIdentity(userID, ir)
+		if err != nil {
+			return
+		}
+		identities = append(identities, info)
+	}
+
+	for _, c := range r.VerifiedClaims {
+		if !isClaimOwned(identities, c) {
+			err = InvalidRecord.Errorf("verified claim does not belong to any identity: %s", c.Name)
+			return
+		}
+		err = i.VerifiedClaims.Create(&verification.Claim{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Name:      string(c.Name),
+			Value:     c.Value,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	if r.PasswordHash != "" {
+		var a *password.Authenticator
+		a, err = i.Passwords.NewWithHash(userID, []byte(r.PasswordHash), false, string(authenticator.KindPrimary))
+		if err != nil {
+			return
+		}
+		err = i.Passwords.Create(a)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(r.StandardAttributes) > 0 {
+		err = i.StandardAttributes.UpdateStandardAttributes(accesscontrol.RoleGreatest, userID, r.StandardAttributes)
+	} else {
+		err = i.StandardAttributes.PopulateIdentityAwareStandardAttributes(userID)
+	}
+	if err != nil {
+		return
+	}
+
+	if len(r.CustomAttributes) > 0 {
+		err = i.CustomAttributes.UpdateAllCustomAttributes(accesscontrol.RoleGreatest, userID, r.CustomAttributes)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (i *Importer) createIdentity(userID string, ir IdentityRecord) (*identity.Info, error) {
+	spec, err := i.identitySpec(ir)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := i.Identities.New(userID, spec, identity.NewIdentityOptions{
+		// Imported users are trusted as users created by Admin API.
+		LoginIDEmailByPassBlocklistAllowlist: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = i.Identities.CheckDuplicated(info)
+	if errors.Is(err, identity.ErrIdentityAlreadyExists) {
+		return nil, info.FillDetails(DuplicatedIdentity.New("identity already exists"))
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = i.Identities.Create(info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (i *Importer) identitySpec(ir IdentityRecord) (*identity.Spec, error) {
+	switch ir.Type {
+	case model.IdentityTypeLoginID:
+		keyConfig, ok := i.LoginIDConfig.GetKeyConfig(ir.LoginIDKey)
+		if !ok {
+			return nil, InvalidRecord.Errorf("invalid login ID key: %s", ir.LoginIDKey)
+		}
+		return &identity.Spec{
+			Type: model.IdentityTypeLoginID,
+			Claims: map[string]interface{}{
+				identity.IdentityClaimLoginIDKey:   ir.LoginIDKey,
+				identity.IdentityClaimLoginIDType:  string(keyConfig.Type),
+				identity.IdentityClaimLoginIDValue: ir.LoginID,
+			},
+		}, nil
+	case model.IdentityTypeOAuth:
+		providerConfig, ok := i.OAuthConfig.GetProviderConfig(ir.ProviderAlias)
+		if !ok {
+			return nil, InvalidRecord.Errorf("invalid OAuth provider alias: %s", ir.ProviderAlias)
+		}
+		claims := ir.Claims
+		if claims == nil {
+			claims = map[string]interface{}{}
+		}
+		return &identity.Spec{
+			Type: model.IdentityTypeOAuth,
+			Claims: map[string]interface{}{
+				identity.IdentityClaimOAuthProviderKeys: providerConfig.ProviderID().Claims(),
+				identity.IdentityClaimOAuthSubjectID:    ir.SubjectID,
+				identity.IdentityClaimOAuthProfile:      map[string]interface{}{},
+				identity.IdentityClaimOAuthClaims:       claims,
+			},
+		}, nil
+	default:
+		return nil, InvalidRecord.Errorf("invalid identity type: %s", ir.Type)
+	}
+}
+
+func isClaimOwned(identities []*identity.Info, c VerifiedClaimRecord) bool {
+	for _, info := range identities {
+		if value, ok := info.StandardClaims()[c.Name]; ok && value == c.Value {
+			return true
+		}
+	}
+	return false
+}
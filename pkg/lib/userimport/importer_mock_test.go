@@ -0,0 +1,297 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: importer.go
+
+// Package userimport is a generated GoMock package.
+package userimport
+
+import (
+	reflect "reflect"
+
+	password "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
+	identity "github.com/authgear/authgear-server/pkg/lib/authn/identity"
+	user "github.com/authgear/authgear-server/pkg/lib/authn/user"
+	verification "github.com/authgear/authgear-server/pkg/lib/feature/verification"
+	accesscontrol "github.com/authgear/authgear-server/pkg/util/accesscontrol"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUserStore is a mock of UserStore interface.
+type MockUserStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserStoreMockRecorder
+}
+
+// MockUserStoreMockRecorder is the mock recorder for MockUserStore.
+type MockUserStoreMockRecorder struct {
+	mock *MockUserStore
+}
+
+// NewMockUserStore creates a new mock instance.
+func NewMockUserStore(ctrl *gomock.Controller) *MockUserStore {
+	mock := &MockUserStore{ctrl: ctrl}
+	mock.recorder = &MockUserStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserStore) EXPECT() *MockUserStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUserStore) Create(u *user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", u)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserStoreMockRecorder) Create(u interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserStore)(nil).Create), u)
+}
+
+// MockIdentityService is a mock of IdentityService interface.
+type MockIdentityService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityServiceMockRecorder
+}
+
+// MockIdentityServiceMockRecorder is the mock recorder for MockIdentityService.
+type MockIdentityServiceMockRecorder struct {
+	mock *MockIdentityService
+}
+
+// NewMockIdentityService creates a new mock instance.
+func NewMockIdentityService(ctrl *gomock.Controller) *MockIdentityService {
+	mock := &MockIdentityService{ctrl: ctrl}
+	mock.recorder = &MockIdentityServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityService) EXPECT() *MockIdentityServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckDuplicated mocks base method.
+func (m *MockIdentityService) CheckDuplicated(info *identity.Info) (*identity.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckDuplicated", info)
+	ret0, _ := ret[0].(*identity.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckDuplicated indicates an expected call of CheckDuplicated.
+func (mr *MockIdentityServiceMockRecorder) CheckDuplicated(info interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDuplicated", reflect.TypeOf((*MockIdentityService)(nil).CheckDuplicated), info)
+}
+
+// Create mocks base method.
+func (m *MockIdentityService) Create(is *identity.Info) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", is)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIdentityServiceMockRecorder) Create(is interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIdentityService)(nil).Create), is)
+}
+
+// New mocks base method.
+func (m *MockIdentityService) New(userID string, spec *identity.Spec, options identity.NewIdentityOptions) (*identity.Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "New", userID, spec, options)
+	ret0, _ := ret[0].(*identity.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// New indicates an expected call of New.
+func (mr *MockIdentityServiceMockRecorder) New(userID, spec, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockIdentityService)(nil).New), userID, spec, options)
+}
+
+// MockPasswordAuthenticatorProvider is a mock of PasswordAuthenticatorProvider interface.
+type MockPasswordAuthenticatorProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordAuthenticatorProviderMockRecorder
+}
+
+// MockPasswordAuthenticatorProviderMockRecorder is the mock recorder for MockPasswordAuthenticatorProvider.
+type MockPasswordAuthenticatorProviderMockRecorder struct {
+	mock *MockPasswordAuthenticatorProvider
+}
+
+// NewMockPasswordAuthenticatorProvider creates a new mock instance.
+func NewMockPasswordAuthenticatorProvider(ctrl *gomock.Controller) *MockPasswordAuthenticatorProvider {
+	mock := &MockPasswordAuthenticatorProvider{ctrl: ctrl}
+	mock.recorder = &MockPasswordAuthenticatorProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordAuthenticatorProvider) EXPECT() *MockPasswordAuthenticatorProviderMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPasswordAuthenticatorProvider) Create(arg0 *password.Authenticator) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPasswordAuthenticatorProviderMockRecorder) Create(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPasswordAuthenticatorProvider)(nil).Create), arg0)
+}
+
+// NewWithHash mocks base method.
+func (m *MockPasswordAuthenticatorProvider) NewWithHash(userID string, passwordHash []byte, isDefault bool, kind string) (*password.Authenticator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewWithHash", userID, passwordHash, isDefault, kind)
+	ret0, _ := ret[0].(*password.Authenticator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewWithHash indicates an expected call of NewWithHash.
+func (mr *MockPasswordAuthenticatorProviderMockRecorder) NewWithHash(userID, passwordHash, isDefault, kind interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewWithHash", reflect.TypeOf((*MockPasswordAuthenticatorProvider)(nil).NewWithHash), userID, passwordHash, isDefault, kind)
+}
+
+// MockVerifiedClaimStore is a mock of VerifiedClaimStore interface.
+type MockVerifiedClaimStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockVerifiedClaimStoreMockRecorder
+}
+
+// MockVerifiedClaimStoreMockRecorder is the mock recorder for MockVerifiedClaimStore.
+type MockVerifiedClaimStoreMockRecorder struct {
+	mock *MockVerifiedClaimStore
+}
+
+// NewMockVerifiedClaimStore creates a new mock instance.
+func NewMockVerifiedClaimStore(ctrl *gomock.Controller) *MockVerifiedClaimStore {
+	mock := &MockVerifiedClaimStore{ctrl: ctrl}
+	mock.recorder = &MockVerifiedClaimStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVerifiedClaimStore) EXPECT() *MockVerifiedClaimStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockVerifiedClaimStore) Create(claim *verification.Claim) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", claim)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockVerifiedClaimStoreMockRecorder) Create(claim interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVerifiedClaimStore)(nil).Create), claim)
+}
+
+// MockStandardAttributesService is a mock of StandardAttributesService interface.
+type MockStandardAttributesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStandardAttributesServiceMockRecorder
+}
+
+// MockStandardAttributesServiceMockRecorder is the mock recorder for MockStandardAttributesService.
+type MockStandardAttributesServiceMockRecorder struct {
+	mock *MockStandardAttributesService
+}
+
+// NewMockStandardAttributesService creates a new mock instance.
+func NewMockStandardAttributesService(ctrl *gomock.Controller) *MockStandardAttributesService {
+	mock := &MockStandardAttributesService{ctrl: ctrl}
+	mock.recorder = &MockStandardAttributesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStandardAttributesService) EXPECT() *MockStandardAttributesServiceMockRecorder {
+	return m.recorder
+}
+
+// PopulateIdentityAwareStandardAttributes mocks base method.
+func (m *MockStandardAttributesService) PopulateIdentityAwareStandardAttributes(userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PopulateIdentityAwareStandardAttributes", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PopulateIdentityAwareStandardAttributes indicates an expected call of PopulateIdentityAwareStandardAttributes.
+func (mr *MockStandardAttributesServiceMockRecorder) PopulateIdentityAwareStandardAttributes(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PopulateIdentityAwareStandardAttributes", reflect.TypeOf((*MockStandardAttributesService)(nil).PopulateIdentityAwareStandardAttributes), userID)
+}
+
+// UpdateStandardAttributes mocks base method.
+func (m *MockStandardAttributesService) UpdateStandardAttributes(role accesscontrol.Role, userID string, stdAttrs map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStandardAttributes", role, userID, stdAttrs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStandardAttributes indicates an expected call of UpdateStandardAttributes.
+func (mr *MockStandardAttributesServiceMockRecorder) UpdateStandardAttributes(role, userID, stdAttrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStandardAttributes", reflect.TypeOf((*MockStandardAttributesService)(nil).UpdateStandardAttributes), role, userID, stdAttrs)
+}
+
+// MockCustomAttributesService is a mock of CustomAttributesService interface.
+type MockCustomAttributesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomAttributesServiceMockRecorder
+}
+
+// MockCustomAttributesServiceMockRecorder is the mock recorder for MockCustomAttributesService.
+type MockCustomAttributesServiceMockRecorder struct {
+	mock *MockCustomAttributesService
+}
+
+// NewMockCustomAttributesService creates a new mock instance.
+func NewMockCustomAttributesService(ctrl *gomock.Controller) *MockCustomAttributesService {
+	mock := &MockCustomAttributesService{ctrl: ctrl}
+	mock.recorder = &MockCustomAttributesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomAttributesService) EXPECT() *MockCustomAttributesServiceMockRecorder {
+	return m.recorder
+}
+
+// UpdateAllCustomAttributes mocks base method.
+func (m *MockCustomAttributesService) UpdateAllCustomAttributes(role accesscontrol.Role, userID string, reprForm map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAllCustomAttributes", role, userID, reprForm)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAllCustomAttributes indicates an expected call of UpdateAllCustomAttributes.
+func (mr *MockCustomAttributesServiceMockRecorder) UpdateAllCustomAttributes(role, userID, reprForm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAllCustomAttributes", reflect.TypeOf((*MockCustomAttributesService)(nil).UpdateAllCustomAttributes), role, userID, reprForm)
+}
@@ -0,0 +1,179 @@
+package userimport
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity"
+	"github.com/authgear/authgear-server/pkg/lib/authn/user"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/feature/verification"
+	"github.com/authgear/authgear-server/pkg/util/accesscontrol"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestImporter(t *testing.T) {
+	Convey("Importer", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := NewMockUserStore(ctrl)
+		identities := NewMockIdentityService(ctrl)
+		passwords := NewMockPasswordAuthenticatorProvider(ctrl)
+		verifiedClaims := NewMockVerifiedClaimStore(ctrl)
+		stdAttrs := NewMockStandardAttributesService(ctrl)
+		customAttrs := NewMockCustomAttributesService(ctrl)
+
+		clk := clock.NewMockClockAt("2006-01-02T15:04:05Z")
+		now := clk.NowUTC()
+		importer := &Importer{
+			LoginIDConfig: &config.LoginIDConfig{
+				Keys: []config.LoginIDKeyConfig{
+					{Key: "email", Type: config.LoginIDKeyTypeEmail},
+				},
+			},
+			OAuthConfig: &config.OAuthSSOConfig{
+				Providers: []config.OAuthSSOProviderConfig{
+					{Alias: "google", Type: config.OAuthSSOProviderTypeGoogle},
+				},
+			},
+			Clock:              clk,
+			Users:              users,
+			Identities:         identities,
+			Passwords:          passwords,
+			VerifiedClaims:     verifiedClaims,
+			StandardAttributes: stdAttrs,
+			CustomAttributes:   customAttrs,
+		}
+
+		loginIDInfo := func(userID string) *identity.Info {
+			return &identity.Info{
+				ID:     "identity-id",
+				UserID: userID,
+				Type:   model.IdentityTypeLoginID,
+				Claims: map[string]interface{}{
+					identity.IdentityClaimLoginIDType:          "email",
+					identity.IdentityClaimLoginIDOriginalValue: "user@example.com",
+				},
+			}
+		}
+
+		record := &Record{
+			Identities: []IdentityRecord{
+				{Type: model.IdentityTypeLoginID, LoginIDKey: "email", LoginID: "user@example.com"},
+			},
+		}
+
+		Convey("should import user with identities, claims, password and attributes", func() {
+			var userID string
+			users.EXPECT().Create(gomock.Any()).DoAndReturn(func(u *user.User) error {
+				userID = u.ID
+				So(u.CreatedAt, ShouldEqual, now)
+				return nil
+			})
+			identities.EXPECT().New(gomock.Any(), &identity.Spec{
+				Type: model.IdentityTypeLoginID,
+				Claims: map[string]interface{}{
+					identity.IdentityClaimLoginIDKey:   "email",
+					identity.IdentityClaimLoginIDType:  "email",
+					identity.IdentityClaimLoginIDValue: "user@example.com",
+				},
+			}, identity.NewIdentityOptions{
+				LoginIDEmailByPassBlocklistAllowlist: true,
+			}).DoAndReturn(func(userID string, spec *identity.Spec, options identity.NewIdentityOptions) (*identity.Info, error) {
+				return loginIDInfo(userID), nil
+			})
+			identities.EXPECT().CheckDuplicated(gomock.Any()).Return(nil, nil)
+			identities.EXPECT().Create(gomock.Any()).Return(nil)
+			verifiedClaims.EXPECT().Create(gomock.Any()).DoAndReturn(func(c *verification.Claim) error {
+				So(c.UserID, ShouldEqual, userID)
+				So(c.Name, ShouldEqual, "email")
+				So(c.Value, ShouldEqual, "user@example.com")
+				return nil
+			})
+			a := &password.Authenticator{ID: "authenticator-id"}
+			passwords.EXPECT().NewWithHash(gomock.Any(), []byte("$pbkdf2-sha256$i=1000$c2FsdA$a2V5"), false, "primary").Return(a, nil)
+			passwords.EXPECT().Create(a).Return(nil)
+			stdAttrs.EXPECT().UpdateStandardAttributes(accesscontrol.RoleGreatest, gomock.Any(), map[string]interface{}{"name": "John"}).Return(nil)
+			customAttrs.EXPECT().UpdateAllCustomAttributes(accesscontrol.RoleGreatest, gomock.Any(), map[string]interface{}{"hobby": "reading"}).Return(nil)
+
+			record.VerifiedClaims = []VerifiedClaimRecord{{Name: model.ClaimEmail, Value: "user@example.com"}}
+			record.PasswordHash = "$pbkdf2-sha256$i=1000$c2FsdA$a2V5"
+			record.StandardAttributes = map[string]interface{}{"name": "John"}
+			record.CustomAttributes = map[string]interface{}{"hobby": "reading"}
+
+			id, err := importer.ImportRecord(record)
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, userID)
+		})
+
+		Convey("should populate standard attributes from identities", func() {
+			users.EXPECT().Create(gomock.Any()).Return(nil)
+			identities.EXPECT().New(gomock.Any(), gomock.Any(), gomock.Any()).Return(loginIDInfo("user-id"), nil)
+			identities.EXPECT().CheckDuplicated(gomock.Any()).Return(nil, nil)
+			identities.EXPECT().Create(gomock.Any()).Return(nil)
+			stdAttrs.EXPECT().PopulateIdentityAwareStandardAttributes(gomock.Any()).Return(nil)
+
+			_, err := importer.ImportRecord(record)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should reject duplicated identity", func() {
+			info := loginIDInfo("user-id")
+			users.EXPECT().Create(gomock.Any()).Return(nil)
+			identities.EXPECT().New(gomock.Any(), gomock.Any(), gomock.Any()).Return(info, nil)
+			identities.EXPECT().CheckDuplicated(info).Return(&identity.Info{}, identity.ErrIdentityAlreadyExists)
+
+			_, err := importer.ImportRecord(record)
+			So(apierrors.AsAPIError(err).Reason, ShouldEqual, "DuplicatedIdentity")
+		})
+
+		Convey("should reject unknown login ID key and OAuth provider", func() {
+			users.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+
+			_, err := importer.ImportRecord(&Record{
+				Identities: []IdentityRecord{
+					{Type: model.IdentityTypeLoginID, LoginIDKey: "phone", LoginID: "+85298765432"},
+				},
+			})
+			So(err, ShouldBeError, "invalid login ID key: phone")
+
+			_, err = importer.ImportRecord(&Record{
+				Identities: []IdentityRecord{
+					{Type: model.IdentityTypeOAuth, ProviderAlias: "facebook", SubjectID: "facebook-user"},
+				},
+			})
+			So(err, ShouldBeError, "invalid OAuth provider alias: facebook")
+		})
+
+		Convey("should reject verified claim not belonging to identities", func() {
+			users.EXPECT().Create(gomock.Any()).Return(nil)
+			identities.EXPECT().New(gomock.Any(), gomock.Any(), gomock.Any()).Return(loginIDInfo("user-id"), nil)
+			identities.EXPECT().CheckDuplicated(gomock.Any()).Return(nil, nil)
+			identities.EXPECT().Create(gomock.Any()).Return(nil)
+
+			record.VerifiedClaims = []VerifiedClaimRecord{{Name: model.ClaimEmail, Value: "other@example.com"}}
+			_, err := importer.ImportRecord(record)
+			So(err, ShouldBeError, "verified claim does not belong to any identity: email")
+		})
+
+		Convey("should reject invalid password hash", func() {
+			users.EXPECT().Create(gomock.Any()).Return(nil)
+			identities.EXPECT().New(gomock.Any(), gomock.Any(), gomock.Any()).Return(loginIDInfo("user-id"), nil)
+			identities.EXPECT().CheckDuplicated(gomock.Any()).Return(nil, nil)
+			identities.EXPECT().Create(gomock.Any()).Return(nil)
+			passwords.EXPECT().NewWithHash(gomock.Any(), []byte("plaintext"), false, "primary").
+				Return(nil, password.InvalidPasswordHash.New("invalid password hash"))
+
+			record.PasswordHash = "plaintext"
+			_, err := importer.ImportRecord(record)
+			So(apierrors.AsAPIError(err).Reason, ShouldEqual, "InvalidPasswordHash")
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package userimport
+
+import (
+	"errors"
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+)
+
+type JobStatus string
+
+const (
+	// JobStatusUploading means batches of records can be added to the job.
+	JobStatusUploading JobStatus = "uploading"
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	// JobStatusFailed means the job is stopped by an error other than record errors.
+	// The job can be resumed.
+	JobStatusFailed JobStatus = "failed"
+)
+
+var ErrJobNotFound = apierrors.NotFound.WithReason("UserImportJobNotFound").New("user import job not found")
+var ErrJobInProgress = apierrors.Invalid.WithReason("UserImportJobInProgress").New("user import job is in progress")
+var ErrJobCompleted = apierrors.Invalid.WithReason("UserImportJobCompleted").New("user import job is completed")
+var ErrJobSubmitted = apierrors.Invalid.WithReason("UserImportJobSubmitted").New("user import job is submitted")
+var ErrJobNotSubmitted = apierrors.Invalid.WithReason("UserImportJobNotSubmitted").New("user import job is not submitted")
+var ErrBatchTooLarge = apierrors.Invalid.WithReason("UserImportBatchTooLarge").New("user import batch is too large")
+
+var errInputChunkNotFound = errors.New("user import input chunk not found")
+
+// Job is an import of NDJSON records.
+// The progress is saved after every record, so that an interrupted job can be resumed
+// without importing any record twice.
+type Job struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Status    JobStatus `json:"status"`
+	// ProcessedCount is the number of lines processed, including blank lines.
+	ProcessedCount int `json:"processedCount"`
+	ImportedCount  int `json:"importedCount"`
+	FailedCount    int `json:"failedCount"`
+}
+
+// InputChunk is a batch of records added to the job.
+// The input of the job is the chunks in the order of Seq.
+type InputChunk struct {
+	ID    string
+	JobID string
+	Seq   int
+	Data  string
+}
+
+// RecordError is the error of importing the record at a line of the input.
+type RecordError struct {
+	ID      string `json:"id"`
+	JobID   string `json:"jobID"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
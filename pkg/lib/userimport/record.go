@@ -0,0 +1,106 @@
+package userimport
+
+import (
+	"bytes"
+
+	"github.com/authgear/authgear-server/pkg/api/model"
+	"github.com/authgear/authgear-server/pkg/util/validation"
+)
+
+// MaxRecordSize is the maximum size in bytes of a record, i.e. a line of the input.
+const MaxRecordSize = 1024 * 1024
+
+var RecordSchema = validation.NewSimpleSchema(`
+{
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"identities": {
+			"type": "array",
+			"minItems": 1,
+			"items": { "$ref": "#/$defs/Identity" }
+		},
+		"verified_claims": {
+			"type": "array",
+			"items": { "$ref": "#/$defs/VerifiedClaim" }
+		},
+		"standard_attributes": { "type": "object" },
+		"custom_attributes": { "type": "object" },
+		"password_hash": { "type": "string", "minLength": 1 }
+	},
+	"required": ["identities"],
+	"$defs": {
+		"Identity": {
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"type": { "type": "string", "enum": ["login_id", "oauth"] },
+				"login_id_key": { "type": "string", "minLength": 1 },
+				"login_id": { "type": "string", "minLength": 1 },
+				"provider_alias": { "type": "string", "minLength": 1 },
+				"subject_id": { "type": "string", "minLength": 1 },
+				"claims": { "type": "object" }
+			},
+			"required": ["type"],
+			"allOf": [
+				{
+					"if": { "properties": { "type": { "const": "login_id" } } },
+					"then": { "required": ["login_id_key", "login_id"] }
+				},
+				{
+					"if": { "properties": { "type": { "const": "oauth" } } },
+					"then": { "required": ["provider_alias", "subject_id"] }
+				}
+			]
+		},
+		"VerifiedClaim": {
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"name": { "type": "string", "enum": ["email", "phone_number"] },
+				"value": { "type": "string", "minLength": 1 }
+			},
+			"required": ["name", "value"]
+		}
+	}
+}
+`)
+
+// Record is a user to be imported, encoded as a line of NDJSON.
+type Record struct {
+	Identities         []IdentityRecord       `json:"identities"`
+	VerifiedClaims     []VerifiedClaimRecord  `json:"verified_claims,omitempty"`
+	StandardAttributes map[string]interface{} `json:"standard_attributes,omitempty"`
+	CustomAttributes   map[string]interface{} `json:"custom_attributes,omitempty"`
+	// PasswordHash is the hash exported from other systems.
+	// See the supported formats in pkg/util/password.
+	PasswordHash string `json:"password_hash,omitempty"`
+}
+
+type IdentityRecord struct {
+	Type model.IdentityType `json:"type"`
+
+	// LoginIDKey and LoginID are for login ID identities.
+	LoginIDKey string `json:"login_id_key,omitempty"`
+	LoginID    string `json:"login_id,omitempty"`
+
+	// ProviderAlias, SubjectID and Claims are for OAuth identities.
+	// Claims are the OIDC standard claims of the user at the provider.
+	ProviderAlias string                 `json:"provider_alias,omitempty"`
+	SubjectID     string                 `json:"subject_id,omitempty"`
+	Claims        map[string]interface{} `json:"claims,omitempty"`
+}
+
+type VerifiedClaimRecord struct {
+	Name  model.ClaimName `json:"name"`
+	Value string          `json:"value"`
+}
+
+func ParseRecord(data []byte) (*Record, error) {
+	var r Record
+	err := RecordSchema.Validator().Parse(bytes.NewReader(data), &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,65 @@
+package userimport
+
+import (
+	"testing"
+
+	"github.com/authgear/authgear-server/pkg/api/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseRecord(t *testing.T) {
+	Convey("ParseRecord", t, func() {
+		Convey("should parse record", func() {
+			r, err := ParseRecord([]byte(`{
+				"identities": [
+					{ "type": "login_id", "login_id_key": "email", "login_id": "user@example.com" },
+					{ "type": "oauth", "provider_alias": "google", "subject_id": "google-user", "claims": { "email": "user@example.com" } }
+				],
+				"verified_claims": [{ "name": "email", "value": "user@example.com" }],
+				"standard_attributes": { "name": "John" },
+				"custom_attributes": { "hobby": "reading" },
+				"password_hash": "$2a$10$abc"
+			}`))
+			So(err, ShouldBeNil)
+			So(r, ShouldResemble, &Record{
+				Identities: []IdentityRecord{
+					{Type: model.IdentityTypeLoginID, LoginIDKey: "email", LoginID: "user@example.com"},
+					{
+						Type:          model.IdentityTypeOAuth,
+						ProviderAlias: "google",
+						SubjectID:     "google-user",
+						Claims:        map[string]interface{}{"email": "user@example.com"},
+					},
+				},
+				VerifiedClaims:     []VerifiedClaimRecord{{Name: model.ClaimEmail, Value: "user@example.com"}},
+				StandardAttributes: map[string]interface{}{"name": "John"},
+				CustomAttributes:   map[string]interface{}{"hobby": "reading"},
+				PasswordHash:       "$2a$10$abc",
+			})
+		})
+
+		Convey("should reject invalid record", func() {
+			test := func(data string, errorString string) {
+				_, err := ParseRecord([]byte(data))
+				So(err, ShouldBeError, errorString)
+			}
+
+			test(`{}`, `invalid value:
+<root>: required
+  map[actual:<nil> expected:[identities] missing:[identities]]`)
+			test(`{ "identities": [{ "type": "login_id", "login_id": "user@example.com" }] }`, `invalid value:
+/identities/0: required
+  map[actual:[login_id type] expected:[login_id login_id_key] missing:[login_id_key]]`)
+			test(`{ "identities": [{ "type": "oauth", "provider_alias": "google" }] }`, `invalid value:
+/identities/0: required
+  map[actual:[provider_alias type] expected:[provider_alias subject_id] missing:[subject_id]]`)
+			test(`{ "identities": [{ "type": "anonymous" }] }`, `invalid value:
+/identities/0/type: enum
+  map[actual:anonymous expected:[login_id oauth]]`)
+			test(`{ "identities": [{ "type": "login_id", "login_id_key": "email", "login_id": "user@example.com" }], "verified_claims": [{ "name": "name", "value": "John" }] }`, `invalid value:
+/verified_claims/0/name: enum
+  map[actual:name expected:[email phone_number]]`)
+		})
+	})
+}
@@ -0,0 +1,265 @@
+package userimport
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/log"
+	"github.com/authgear/authgear-server/pkg/util/uuid"
+)
+
+//go:generate mockgen -source=runner.go -destination=runner_mock_test.go -package userimport
+
+type RunnerDatabase interface {
+	WithTx(do func() error) error
+}
+
+type RunnerStore interface {
+	GetJobForUpdate(id string) (*Job, error)
+	GetInputChunk(jobID string, seq int) (*InputChunk, error)
+	UpdateJob(j *Job) error
+	CreateRecordError(e *RecordError) error
+}
+
+type RecordImporter interface {
+	ImportRecord(r *Record) (userID string, err error)
+}
+
+type SearchService interface {
+	ReindexUser(userID string, isDelete bool) error
+}
+
+type RunnerLogger struct{ *log.Logger }
+
+func NewRunnerLogger(lf *log.Factory) RunnerLogger { return RunnerLogger{lf.New("user-import")} }
+
+// Runner runs a job line by line.
+//
+// Every record is imported in its own transaction, together with the progress of the job.
+// Therefore, when a job is resumed after an interruption, it continues with the first
+// unprocessed line, and no record is imported twice.
+// Every imported user is reindexed for search in the same transaction.
+type Runner struct {
+	Database      RunnerDatabase
+	Store         RunnerStore
+	Importer      RecordImporter
+	SearchService SearchService
+	Clock         clock.Clock
+	Logger        RunnerLogger
+}
+
+// RunJob runs the job with the records added to it.
+// The records are read one chunk at a time.
+func (r *Runner) RunJob(jobID string) error {
+	_, err := r.Run(jobID, &inputReader{runner: r, jobID: jobID}, nil)
+	return err
+}
+
+// Run imports the records of input, skipping the lines processed by the previous runs of the job.
+// onRecordError is called with every record error, if it is not nil.
+func (r *Runner) Run(jobID string, input io.Reader, onRecordError func(e *RecordError)) (*Job, error) {
+	job, err := r.start(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.run(job, input, onRecordError)
+	if errors.Is(err, ErrJobInProgress) {
+		// The job is being run by others.
+		return nil, err
+	} else if err != nil {
+		if _, markErr := r.finish(jobID, JobStatusFailed); markErr != nil {
+			r.Logger.WithError(markErr).WithField("job_id", jobID).
+				Error("failed to mark user import job as failed")
+		}
+		return nil, err
+	}
+
+	return r.finish(jobID, JobStatusCompleted)
+}
+
+func (r *Runner) start(jobID string) (job *Job, err error) {
+	err = r.Database.WithTx(func() error {
+		job, err = r.Store.GetJobForUpdate(jobID)
+		if err != nil {
+			return err
+		}
+		switch job.Status {
+		case JobStatusUploading:
+			return ErrJobNotSubmitted
+		case JobStatusCompleted:
+			return ErrJobCompleted
+		}
+
+		job.Status = JobStatusRunning
+		job.UpdatedAt = r.Clock.NowUTC()
+		return r.Store.UpdateJob(job)
+	})
+	return
+}
+
+func (r *Runner) finish(jobID string, status JobStatus) (job *Job, err error) {
+	err = r.Database.WithTx(func() error {
+		job, err = r.Store.GetJobForUpdate(jobID)
+		if err != nil {
+			return err
+		}
+
+		job.Status = status
+		job.UpdatedAt = r.Clock.NowUTC()
+		return r.Store.UpdateJob(job)
+	})
+	return
+}
+
+func (r *Runner) run(job *Job, input io.Reader, onRecordError func(e *RecordError)) error {
+	reader := bufio.NewReader(input)
+	line := 0
+	for {
+		data, readErr := reader.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return readErr
+		}
+		if errors.Is(readErr, io.EOF) && len(data) == 0 {
+			return nil
+		}
+
+		line++
+		if line > job.ProcessedCount {
+			recordErr, err := r.processLine(job.ID, line, bytes.TrimSpace(data))
+			if err != nil {
+				return err
+			}
+			if recordErr != nil && onRecordError != nil {
+				onRecordError(recordErr)
+			}
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			return nil
+		}
+	}
+}
+
+func (r *Runner) processLine(jobID string, line int, data []byte) (*RecordError, error) {
+	var importErr error
+	err := r.Database.WithTx(func() error {
+		job, err := r.lockJob(jobID, line)
+		if err != nil {
+			return err
+		}
+
+		job.ProcessedCount++
+		// Blank lines are skipped.
+		if len(data) > 0 {
+			var userID string
+			userID, importErr = r.importRecord(data)
+			if importErr != nil {
+				// Roll back the partially imported user.
+				return importErr
+			}
+			err = r.SearchService.ReindexUser(userID, false)
+			if err != nil {
+				return err
+			}
+			job.ImportedCount++
+		}
+
+		job.UpdatedAt = r.Clock.NowUTC()
+		return r.Store.UpdateJob(job)
+	})
+	if importErr == nil {
+		return nil, err
+	}
+
+	recordErr := &RecordError{
+		ID:      uuid.New(),
+		JobID:   jobID,
+		Line:    line,
+		Message: importErr.Error(),
+	}
+	err = r.Database.WithTx(func() error {
+		job, err := r.lockJob(jobID, line)
+		if err != nil {
+			return err
+		}
+
+		job.ProcessedCount++
+		job.FailedCount++
+		job.UpdatedAt = r.Clock.NowUTC()
+		err = r.Store.UpdateJob(job)
+		if err != nil {
+			return err
+		}
+
+		return r.Store.CreateRecordError(recordErr)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recordErr, nil
+}
+
+// lockJob locks the job, and ensures the line is not processed by others.
+func (r *Runner) lockJob(jobID string, line int) (*Job, error) {
+	job, err := r.Store.GetJobForUpdate(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != JobStatusRunning || job.ProcessedCount != line-1 {
+		return nil, ErrJobInProgress
+	}
+	return job, nil
+}
+
+func (r *Runner) importRecord(data []byte) (userID string, err error) {
+	if len(data) > MaxRecordSize {
+		return "", InvalidRecord.New(fmt.Sprintf("record is larger than %d bytes", MaxRecordSize))
+	}
+
+	record, err := ParseRecord(data)
+	if err != nil {
+		return "", err
+	}
+
+	return r.Importer.ImportRecord(record)
+}
+
+// inputReader reads the input chunks of a job in order, loading one chunk at a time.
+// A chunk always ends a line, so a record never spans two chunks.
+type inputReader struct {
+	runner  *Runner
+	jobID   string
+	seq     int
+	current *strings.Reader
+}
+
+func (i *inputReader) Read(p []byte) (int, error) {
+	for i.current == nil || i.current.Len() == 0 {
+		var chunk *InputChunk
+		err := i.runner.Database.WithTx(func() (err error) {
+			chunk, err = i.runner.Store.GetInputChunk(i.jobID, i.seq)
+			return
+		})
+		if errors.Is(err, errInputChunkNotFound) {
+			return 0, io.EOF
+		} else if err != nil {
+			return 0, err
+		}
+
+		i.seq++
+		data := chunk.Data
+		if data != "" && !strings.HasSuffix(data, "\n") {
+			data += "\n"
+		}
+		i.current = strings.NewReader(data)
+	}
+
+	return i.current.Read(p)
+}
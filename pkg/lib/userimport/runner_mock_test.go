@@ -0,0 +1,204 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: runner.go
+
+// Package userimport is a generated GoMock package.
+package userimport
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRunnerDatabase is a mock of RunnerDatabase interface.
+type MockRunnerDatabase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRunnerDatabaseMockRecorder
+}
+
+// MockRunnerDatabaseMockRecorder is the mock recorder for MockRunnerDatabase.
+type MockRunnerDatabaseMockRecorder struct {
+	mock *MockRunnerDatabase
+}
+
+// NewMockRunnerDatabase creates a new mock instance.
+func NewMockRunnerDatabase(ctrl *gomock.Controller) *MockRunnerDatabase {
+	mock := &MockRunnerDatabase{ctrl: ctrl}
+	mock.recorder = &MockRunnerDatabaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRunnerDatabase) EXPECT() *MockRunnerDatabaseMockRecorder {
+	return m.recorder
+}
+
+// WithTx mocks base method.
+func (m *MockRunnerDatabase) WithTx(do func() error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", do)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRunnerDatabaseMockRecorder) WithTx(do interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRunnerDatabase)(nil).WithTx), do)
+}
+
+// MockRunnerStore is a mock of RunnerStore interface.
+type MockRunnerStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRunnerStoreMockRecorder
+}
+
+// MockRunnerStoreMockRecorder is the mock recorder for MockRunnerStore.
+type MockRunnerStoreMockRecorder struct {
+	mock *MockRunnerStore
+}
+
+// NewMockRunnerStore creates a new mock instance.
+func NewMockRunnerStore(ctrl *gomock.Controller) *MockRunnerStore {
+	mock := &MockRunnerStore{ctrl: ctrl}
+	mock.recorder = &MockRunnerStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRunnerStore) EXPECT() *MockRunnerStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateRecordError mocks base method.
+func (m *MockRunnerStore) CreateRecordError(e *RecordError) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecordError", e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecordError indicates an expected call of CreateRecordError.
+func (mr *MockRunnerStoreMockRecorder) CreateRecordError(e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecordError", reflect.TypeOf((*MockRunnerStore)(nil).CreateRecordError), e)
+}
+
+// GetInputChunk mocks base method.
+func (m *MockRunnerStore) GetInputChunk(jobID string, seq int) (*InputChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInputChunk", jobID, seq)
+	ret0, _ := ret[0].(*InputChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInputChunk indicates an expected call of GetInputChunk.
+func (mr *MockRunnerStoreMockRecorder) GetInputChunk(jobID, seq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInputChunk", reflect.TypeOf((*MockRunnerStore)(nil).GetInputChunk), jobID, seq)
+}
+
+// GetJobForUpdate mocks base method.
+func (m *MockRunnerStore) GetJobForUpdate(id string) (*Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobForUpdate", id)
+	ret0, _ := ret[0].(*Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobForUpdate indicates an expected call of GetJobForUpdate.
+func (mr *MockRunnerStoreMockRecorder) GetJobForUpdate(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobForUpdate", reflect.TypeOf((*MockRunnerStore)(nil).GetJobForUpdate), id)
+}
+
+// UpdateJob mocks base method.
+func (m *MockRunnerStore) UpdateJob(j *Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", j)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockRunnerStoreMockRecorder) UpdateJob(j interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockRunnerStore)(nil).UpdateJob), j)
+}
+
+// MockRecordImporter is a mock of RecordImporter interface.
+type MockRecordImporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecordImporterMockRecorder
+}
+
+// MockRecordImporterMockRecorder is the mock recorder for MockRecordImporter.
+type MockRecordImporterMockRecorder struct {
+	mock *MockRecordImporter
+}
+
+// NewMockRecordImporter creates a new mock instance.
+func NewMockRecordImporter(ctrl *gomock.Controller) *MockRecordImporter {
+	mock := &MockRecordImporter{ctrl: ctrl}
+	mock.recorder = &MockRecordImporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecordImporter) EXPECT() *MockRecordImporterMockRecorder {
+	return m.recorder
+}
+
+// ImportRecord mocks base method.
+func (m *MockRecordImporter) ImportRecord(r *Record) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportRecord", r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportRecord indicates an expected call of ImportRecord.
+func (mr *MockRecordImporterMockRecorder) ImportRecord(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportRecord", reflect.TypeOf((*MockRecordImporter)(nil).ImportRecord), r)
+}
+
+// MockSearchService is a mock of SearchService interface.
+type MockSearchService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchServiceMockRecorder
+}
+
+// MockSearchServiceMockRecorder is the mock recorder for MockSearchService.
+type MockSearchServiceMockRecorder struct {
+	mock *MockSearchService
+}
+
+// NewMockSearchService creates a new mock instance.
+func NewMockSearchService(ctrl *gomock.Controller) *MockSearchService {
+	mock := &MockSearchService{ctrl: ctrl}
+	mock.recorder = &MockSearchServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchService) EXPECT() *MockSearchServiceMockRecorder {
+	return m.recorder
+}
+
+// ReindexUser mocks base method.
+func (m *MockSearchService) ReindexUser(userID string, isDelete bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReindexUser", userID, isDelete)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReindexUser indicates an expected call of ReindexUser.
+func (mr *MockSearchServiceMockRecorder) ReindexUser(userID, isDelete interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReindexUser", reflect.TypeOf((*MockSearchService)(nil).ReindexUser), userID, isDelete)
+}
@@ -0,0 +1,218 @@
+package userimport
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/log"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeRunnerStore struct {
+	Jobs        map[string]*Job
+	Chunks      []string
+	Errors      []*RecordError
+	AfterUpdate func(j *Job)
+}
+
+func (s *fakeRunnerStore) GetJobForUpdate(id string) (*Job, error) {
+	j, ok := s.Jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	jj := *j
+	return &jj, nil
+}
+
+func (s *fakeRunnerStore) GetInputChunk(jobID string, seq int) (*InputChunk, error) {
+	if seq >= len(s.Chunks) {
+		return nil, errInputChunkNotFound
+	}
+	return &InputChunk{JobID: jobID, Seq: seq, Data: s.Chunks[seq]}, nil
+}
+
+func (s *fakeRunnerStore) UpdateJob(j *Job) error {
+	jj := *j
+	s.Jobs[j.ID] = &jj
+	if s.AfterUpdate != nil {
+		s.AfterUpdate(&jj)
+	}
+	return nil
+}
+
+func (s *fakeRunnerStore) CreateRecordError(e *RecordError) error {
+	s.Errors = append(s.Errors, e)
+	return nil
+}
+
+func TestRunner(t *testing.T) {
+	Convey("Runner", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		database := NewMockRunnerDatabase(ctrl)
+		importer := NewMockRecordImporter(ctrl)
+		searchService := NewMockSearchService(ctrl)
+		store := &fakeRunnerStore{
+			Jobs: map[string]*Job{
+				"job-id": {ID: "job-id", Status: JobStatusPending},
+			},
+		}
+
+		runner := &Runner{
+			Database:      database,
+			Store:         store,
+			Importer:      importer,
+			SearchService: searchService,
+			Clock:         clock.NewMockClockAt("2006-01-02T15:04:05Z"),
+			Logger:        RunnerLogger{log.Null},
+		}
+
+		database.EXPECT().WithTx(gomock.Any()).AnyTimes().DoAndReturn(func(do func() error) error {
+			return do()
+		})
+
+		importLoginID := func(loginID string, err error) *gomock.Call {
+			if err == nil {
+				searchService.EXPECT().ReindexUser("user-id:"+loginID, false).Return(nil)
+			}
+			return importer.EXPECT().ImportRecord(gomock.Any()).DoAndReturn(func(r *Record) (string, error) {
+				So(r.Identities[0].LoginID, ShouldEqual, loginID)
+				return "user-id:" + loginID, err
+			})
+		}
+		record := func(loginID string) string {
+			return `{ "identities": [{ "type": "login_id", "login_id_key": "email", "login_id": "` + loginID + `" }] }`
+		}
+
+		Convey("should import records and record errors", func() {
+			gomock.InOrder(
+				importLoginID("user1@example.com", nil),
+				importLoginID("user2@example.com", DuplicatedIdentity.New("identity already exists")),
+			)
+
+			input := strings.Join([]string{
+				record("user1@example.com"),
+				"",
+				"{}",
+				record("user2@example.com"),
+			}, "\n")
+
+			var recordErrs []*RecordError
+			job, err := runner.Run("job-id", strings.NewReader(input), func(e *RecordError) {
+				recordErrs = append(recordErrs, e)
+			})
+			So(err, ShouldBeNil)
+			So(job.Status, ShouldEqual, JobStatusCompleted)
+			So(job.ProcessedCount, ShouldEqual, 4)
+			So(job.ImportedCount, ShouldEqual, 1)
+			So(job.FailedCount, ShouldEqual, 2)
+
+			So(recordErrs, ShouldResemble, store.Errors)
+			So(store.Errors, ShouldHaveLength, 2)
+			So(store.Errors[0].Line, ShouldEqual, 3)
+			So(store.Errors[0].Message, ShouldStartWith, "invalid value:")
+			So(store.Errors[1].Line, ShouldEqual, 4)
+			So(store.Errors[1].Message, ShouldEqual, "identity already exists")
+		})
+
+		Convey("should resume from the first unprocessed line", func() {
+			store.Jobs["job-id"] = &Job{
+				ID:             "job-id",
+				Status:         JobStatusFailed,
+				ProcessedCount: 2,
+				ImportedCount:  2,
+			}
+			importLoginID("user3@example.com", nil)
+
+			input := strings.Join([]string{
+				record("user1@example.com"),
+				record("user2@example.com"),
+				record("user3@example.com"),
+			}, "\n") + "\n"
+
+			job, err := runner.Run("job-id", strings.NewReader(input), nil)
+			So(err, ShouldBeNil)
+			So(job.Status, ShouldEqual, JobStatusCompleted)
+			So(job.ProcessedCount, ShouldEqual, 3)
+			So(job.ImportedCount, ShouldEqual, 3)
+		})
+
+		Convey("should run job with the records added to it chunk by chunk", func() {
+			store.Chunks = []string{
+				record("user1@example.com") + "\n" + record("user2@example.com"),
+				"",
+				record("user3@example.com") + "\n",
+			}
+			gomock.InOrder(
+				importLoginID("user1@example.com", nil),
+				importLoginID("user2@example.com", nil),
+				importLoginID("user3@example.com", nil),
+			)
+
+			err := runner.RunJob("job-id")
+			So(err, ShouldBeNil)
+			So(store.Jobs["job-id"].Status, ShouldEqual, JobStatusCompleted)
+			So(store.Jobs["job-id"].ProcessedCount, ShouldEqual, 3)
+			So(store.Jobs["job-id"].ImportedCount, ShouldEqual, 3)
+		})
+
+		Convey("should roll back the record if the user cannot be reindexed", func() {
+			reindexErr := errors.New("reindex error")
+			importer.EXPECT().ImportRecord(gomock.Any()).Return("user-id", nil)
+			searchService.EXPECT().ReindexUser("user-id", false).Return(reindexErr)
+
+			_, err := runner.Run("job-id", strings.NewReader(record("user1@example.com")), nil)
+			So(err, ShouldBeError, reindexErr)
+			So(store.Jobs["job-id"].Status, ShouldEqual, JobStatusFailed)
+			So(store.Jobs["job-id"].ProcessedCount, ShouldEqual, 0)
+		})
+
+		Convey("should reject job which is not submitted", func() {
+			store.Jobs["job-id"].Status = JobStatusUploading
+
+			err := runner.RunJob("job-id")
+			So(err, ShouldBeError, ErrJobNotSubmitted)
+		})
+
+		Convey("should reject completed job", func() {
+			store.Jobs["job-id"].Status = JobStatusCompleted
+
+			_, err := runner.Run("job-id", strings.NewReader(record("user1@example.com")), nil)
+			So(err, ShouldBeError, ErrJobCompleted)
+		})
+
+		Convey("should mark job as failed if input cannot be read", func() {
+			readErr := errors.New("read error")
+
+			_, err := runner.Run("job-id", iotest.ErrReader(readErr), nil)
+			So(err, ShouldBeError, readErr)
+			So(store.Jobs["job-id"].Status, ShouldEqual, JobStatusFailed)
+		})
+
+		Convey("should stop if the job is run by others", func() {
+			importLoginID("user1@example.com", nil)
+			store.AfterUpdate = func(j *Job) {
+				if j.ProcessedCount == 1 {
+					// Another runner processes the next line concurrently.
+					j.ProcessedCount = 2
+				}
+			}
+
+			input := strings.Join([]string{
+				record("user1@example.com"),
+				record("user2@example.com"),
+			}, "\n")
+
+			_, err := runner.Run("job-id", strings.NewReader(input), nil)
+			So(err, ShouldBeError, ErrJobInProgress)
+			So(store.Jobs["job-id"].Status, ShouldEqual, JobStatusRunning)
+		})
+	})
+}
@@ -0,0 +1,146 @@
+package userimport
+
+import (
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/uuid"
+)
+
+// MaxRecordErrors is the maximum number of record errors listed for a job.
+const MaxRecordErrors = 1000
+
+// MaxBatchSize is the maximum size of a batch of records added to a job.
+const MaxBatchSize = 1024 * 1024
+
+type ServiceStore interface {
+	GetJob(id string) (*Job, error)
+	GetJobForUpdate(id string) (*Job, error)
+	CreateJob(j *Job) error
+	UpdateJob(j *Job) error
+	CountInputChunks(jobID string) (int, error)
+	CreateInputChunk(c *InputChunk) error
+	ListRecordErrors(jobID string, limit uint64) ([]*RecordError, error)
+}
+
+type Service struct {
+	Clock     clock.Clock
+	Store     ServiceStore
+	TaskQueue task.Queue
+}
+
+// CreateJob creates a job to be run with an input read from elsewhere, e.g. a file given to the CLI.
+func (s *Service) CreateJob() (*Job, error) {
+	return s.createJob(JobStatusPending)
+}
+
+// CreateUploadJob creates a job with the first batch of records.
+// More batches can be added with AddRecords, until the job is submitted with SubmitJob.
+func (s *Service) CreateUploadJob(records string) (*Job, error) {
+	j, err := s.createJob(JobStatusUploading)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.AddRecords(j.ID, records)
+}
+
+// AddRecords appends a batch of records to the input of the job.
+// The end of the batch is the end of a line.
+func (s *Service) AddRecords(jobID string, records string) (*Job, error) {
+	if len(records) > MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+
+	j, err := s.Store.GetJobForUpdate(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if j.Status != JobStatusUploading {
+		return nil, ErrJobSubmitted
+	}
+
+	seq, err := s.Store.CountInputChunks(j.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Store.CreateInputChunk(&InputChunk{
+		ID:    uuid.New(),
+		JobID: j.ID,
+		Seq:   seq,
+		Data:  records,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	j.UpdatedAt = s.Clock.NowUTC()
+	err = s.Store.UpdateJob(j)
+	if err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// SubmitJob runs the job with the added records in background.
+func (s *Service) SubmitJob(jobID string) (*Job, error) {
+	j, err := s.Store.GetJobForUpdate(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if j.Status != JobStatusUploading {
+		return nil, ErrJobSubmitted
+	}
+
+	j.Status = JobStatusPending
+	j.UpdatedAt = s.Clock.NowUTC()
+	err = s.Store.UpdateJob(j)
+	if err != nil {
+		return nil, err
+	}
+
+	s.TaskQueue.Enqueue(&tasks.ImportUsersParam{JobID: j.ID})
+	return j, nil
+}
+
+func (s *Service) Get(id string) (*Job, error) {
+	return s.Store.GetJob(id)
+}
+
+// Resume runs a submitted job in background from the first unprocessed line.
+func (s *Service) Resume(id string) (*Job, error) {
+	j, err := s.Store.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	switch j.Status {
+	case JobStatusUploading:
+		return nil, ErrJobNotSubmitted
+	case JobStatusCompleted:
+		return nil, ErrJobCompleted
+	}
+
+	s.TaskQueue.Enqueue(&tasks.ImportUsersParam{JobID: j.ID})
+	return j, nil
+}
+
+func (s *Service) ListRecordErrors(jobID string) ([]*RecordError, error) {
+	return s.Store.ListRecordErrors(jobID, MaxRecordErrors)
+}
+
+func (s *Service) createJob(status JobStatus) (*Job, error) {
+	now := s.Clock.NowUTC()
+	j := &Job{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Status:    status,
+	}
+	err := s.Store.CreateJob(j)
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
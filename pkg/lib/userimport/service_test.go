@@ -0,0 +1,133 @@
+package userimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type mockTaskQueue struct {
+	params []task.Param
+}
+
+func (q *mockTaskQueue) Enqueue(param task.Param) {
+	q.params = append(q.params, param)
+}
+
+type fakeServiceStore struct {
+	Jobs   map[string]*Job
+	Chunks []*InputChunk
+}
+
+func (s *fakeServiceStore) GetJob(id string) (*Job, error) {
+	j, ok := s.Jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	jj := *j
+	return &jj, nil
+}
+
+func (s *fakeServiceStore) GetJobForUpdate(id string) (*Job, error) {
+	return s.GetJob(id)
+}
+
+func (s *fakeServiceStore) CreateJob(j *Job) error {
+	jj := *j
+	s.Jobs[j.ID] = &jj
+	return nil
+}
+
+func (s *fakeServiceStore) UpdateJob(j *Job) error {
+	jj := *j
+	s.Jobs[j.ID] = &jj
+	return nil
+}
+
+func (s *fakeServiceStore) CountInputChunks(jobID string) (int, error) {
+	count := 0
+	for _, c := range s.Chunks {
+		if c.JobID == jobID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *fakeServiceStore) CreateInputChunk(c *InputChunk) error {
+	s.Chunks = append(s.Chunks, c)
+	return nil
+}
+
+func (s *fakeServiceStore) ListRecordErrors(jobID string, limit uint64) ([]*RecordError, error) {
+	return nil, nil
+}
+
+func TestService(t *testing.T) {
+	Convey("Service", t, func() {
+		store := &fakeServiceStore{Jobs: map[string]*Job{}}
+		queue := &mockTaskQueue{}
+		service := &Service{
+			Clock:     clock.NewMockClockAt("2006-01-02T15:04:05Z"),
+			Store:     store,
+			TaskQueue: queue,
+		}
+
+		Convey("should run job after all batches are added", func() {
+			job, err := service.CreateUploadJob("line 1\n")
+			So(err, ShouldBeNil)
+			So(job.Status, ShouldEqual, JobStatusUploading)
+
+			_, err = service.AddRecords(job.ID, "line 2\n")
+			So(err, ShouldBeNil)
+			So(queue.params, ShouldBeEmpty)
+
+			job, err = service.SubmitJob(job.ID)
+			So(err, ShouldBeNil)
+			So(job.Status, ShouldEqual, JobStatusPending)
+			So(queue.params, ShouldResemble, []task.Param{
+				&tasks.ImportUsersParam{JobID: job.ID},
+			})
+
+			So(store.Chunks, ShouldHaveLength, 2)
+			So(store.Chunks[0].Seq, ShouldEqual, 0)
+			So(store.Chunks[0].Data, ShouldEqual, "line 1\n")
+			So(store.Chunks[1].Seq, ShouldEqual, 1)
+			So(store.Chunks[1].Data, ShouldEqual, "line 2\n")
+		})
+
+		Convey("should reject batches after the job is submitted", func() {
+			job, err := service.CreateUploadJob("line 1\n")
+			So(err, ShouldBeNil)
+			_, err = service.SubmitJob(job.ID)
+			So(err, ShouldBeNil)
+
+			_, err = service.AddRecords(job.ID, "line 2\n")
+			So(err, ShouldBeError, ErrJobSubmitted)
+			_, err = service.SubmitJob(job.ID)
+			So(err, ShouldBeError, ErrJobSubmitted)
+		})
+
+		Convey("should reject batch which is too large", func() {
+			job, err := service.CreateUploadJob("")
+			So(err, ShouldBeNil)
+
+			_, err = service.AddRecords(job.ID, strings.Repeat("a", MaxBatchSize+1))
+			So(err, ShouldBeError, ErrBatchTooLarge)
+		})
+
+		Convey("should not resume job which is not submitted", func() {
+			job, err := service.CreateUploadJob("line 1\n")
+			So(err, ShouldBeNil)
+
+			_, err = service.Resume(job.ID)
+			So(err, ShouldBeError, ErrJobNotSubmitted)
+			So(queue.params, ShouldBeEmpty)
+		})
+	})
+}
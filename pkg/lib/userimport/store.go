@@ -0,0 +1,260 @@
+package userimport
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/db"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
+)
+
+type Store struct {
+	SQLBuilder  *appdb.SQLBuilderApp
+	SQLExecutor *appdb.SQLExecutor
+}
+
+func (s *Store) selectJobQuery() db.SelectBuilder {
+	return s.SQLBuilder.
+		Select(
+			"id",
+			"created_at",
+			"updated_at",
+			"status",
+			"processed_count",
+			"imported_count",
+			"failed_count",
+		).
+		From(s.SQLBuilder.TableName("_auth_user_import_job"))
+}
+
+func (s *Store) scanJob(scn db.Scanner) (*Job, error) {
+	j := &Job{}
+
+	var status string
+	err := scn.Scan(
+		&j.ID,
+		&j.CreatedAt,
+		&j.UpdatedAt,
+		&status,
+		&j.ProcessedCount,
+		&j.ImportedCount,
+		&j.FailedCount,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrJobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	j.Status = JobStatus(status)
+
+	return j, nil
+}
+
+func (s *Store) GetJob(id string) (*Job, error) {
+	q := s.selectJobQuery().Where("id = ?", id)
+
+	scanner, err := s.SQLExecutor.QueryRowWith(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scanJob(scanner)
+}
+
+// GetJobForUpdate locks the job until the end of the transaction.
+func (s *Store) GetJobForUpdate(id string) (*Job, error) {
+	q := s.selectJobQuery().
+		Where("id = ?", id).
+		Suffix("FOR UPDATE")
+
+	scanner, err := s.SQLExecutor.QueryRowWith(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scanJob(scanner)
+}
+
+func (s *Store) CreateJob(j *Job) error {
+	q := s.SQLBuilder.
+		Insert(s.SQLBuilder.TableName("_auth_user_import_job")).
+		Columns(
+			"id",
+			"created_at",
+			"updated_at",
+			"status",
+			"processed_count",
+			"imported_count",
+			"failed_count",
+		).
+		Values(
+			j.ID,
+			j.CreatedAt,
+			j.UpdatedAt,
+			string(j.Status),
+			j.ProcessedCount,
+			j.ImportedCount,
+			j.FailedCount,
+		)
+
+	_, err := s.SQLExecutor.ExecWith(q)
+	return err
+}
+
+func (s *Store) UpdateJob(j *Job) error {
+	q := s.SQLBuilder.
+		Update(s.SQLBuilder.TableName("_auth_user_import_job")).
+		Set("updated_at", j.UpdatedAt).
+		Set("status", string(j.Status)).
+		Set("processed_count", j.ProcessedCount).
+		Set("imported_count", j.ImportedCount).
+		Set("failed_count", j.FailedCount).
+		Where("id = ?", j.ID)
+
+	result, err := s.SQLExecutor.ExecWith(q)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
+
+func (s *Store) CreateInputChunk(c *InputChunk) error {
+	q := s.SQLBuilder.
+		Insert(s.SQLBuilder.TableName("_auth_user_import_chunk")).
+		Columns(
+			"id",
+			"job_id",
+			"seq",
+			"data",
+		).
+		Values(
+			c.ID,
+			c.JobID,
+			c.Seq,
+			c.Data,
+		)
+
+	_, err := s.SQLExecutor.ExecWith(q)
+	return err
+}
+
+func (s *Store) CountInputChunks(jobID string) (int, error) {
+	q := s.SQLBuilder.
+		Select("count(*)").
+		From(s.SQLBuilder.TableName("_auth_user_import_chunk")).
+		Where("job_id = ?", jobID)
+
+	scanner, err := s.SQLExecutor.QueryRowWith(q)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = scanner.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetInputChunk returns the chunk of the job at seq, or errInputChunkNotFound after the last chunk.
+func (s *Store) GetInputChunk(jobID string, seq int) (*InputChunk, error) {
+	q := s.SQLBuilder.
+		Select(
+			"id",
+			"job_id",
+			"seq",
+			"data",
+		).
+		From(s.SQLBuilder.TableName("_auth_user_import_chunk")).
+		Where("job_id = ? AND seq = ?", jobID, seq)
+
+	scanner, err := s.SQLExecutor.QueryRowWith(q)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &InputChunk{}
+	err = scanner.Scan(
+		&c.ID,
+		&c.JobID,
+		&c.Seq,
+		&c.Data,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errInputChunkNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (s *Store) CreateRecordError(e *RecordError) error {
+	q := s.SQLBuilder.
+		Insert(s.SQLBuilder.TableName("_auth_user_import_error")).
+		Columns(
+			"id",
+			"job_id",
+			"line",
+			"message",
+		).
+		Values(
+			e.ID,
+			e.JobID,
+			e.Line,
+			e.Message,
+		)
+
+	_, err := s.SQLExecutor.ExecWith(q)
+	return err
+}
+
+// ListRecordErrors lists the earliest record errors of the job in the order of line.
+func (s *Store) ListRecordErrors(jobID string, limit uint64) ([]*RecordError, error) {
+	q := s.SQLBuilder.
+		Select(
+			"id",
+			"job_id",
+			"line",
+			"message",
+		).
+		From(s.SQLBuilder.TableName("_auth_user_import_error")).
+		Where("job_id = ?", jobID).
+		OrderBy("line ASC").
+		Limit(limit)
+
+	rows, err := s.SQLExecutor.QueryWith(q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var errs []*RecordError
+	for rows.Next() {
+		e := &RecordError{}
+		err := rows.Scan(
+			&e.ID,
+			&e.JobID,
+			&e.Line,
+			&e.Message,
+		)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, e)
+	}
+
+	return errs, nil
+}
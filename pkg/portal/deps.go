@@ -18,6 +18,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/portal/smtp"
 	"github.com/authgear/authgear-server/pkg/portal/task"
 	"github.com/authgear/authgear-server/pkg/portal/transport"
+	"github.com/authgear/authgear-server/pkg/portal/webhook"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/resource"
 	"github.com/authgear/authgear-server/pkg/util/template"
@@ -45,6 +46,7 @@ var DependencySet = wire.NewSet(
 	endpoint.DependencySet,
 
 	smtp.DependencySet,
+	webhook.DependencySet,
 
 	auditdb.NewReadHandle,
 	auditdb.DependencySet,
@@ -76,6 +78,7 @@ var DependencySet = wire.NewSet(
 	wire.Bind(new(graphql.DomainService), new(*service.DomainService)),
 	wire.Bind(new(graphql.CollaboratorService), new(*service.CollaboratorService)),
 	wire.Bind(new(graphql.SMTPService), new(*smtp.Service)),
+	wire.Bind(new(graphql.WebhookService), new(*webhook.Service)),
 	wire.Bind(new(graphql.AppResourceManagerFactory), new(*appresource.ManagerFactory)),
 	wire.Bind(new(graphql.AnalyticChartService), new(*analytic.ChartService)),
 
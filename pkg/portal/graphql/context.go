@@ -7,9 +7,11 @@ import (
 	apimodel "github.com/authgear/authgear-server/pkg/api/model"
 	"github.com/authgear/authgear-server/pkg/lib/analytic"
 	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
 	"github.com/authgear/authgear-server/pkg/portal/appresource"
 	"github.com/authgear/authgear-server/pkg/portal/model"
 	"github.com/authgear/authgear-server/pkg/portal/smtp"
+	"github.com/authgear/authgear-server/pkg/portal/webhook"
 	"github.com/authgear/authgear-server/pkg/util/graphqlutil"
 	"github.com/authgear/authgear-server/pkg/util/log"
 )
@@ -75,6 +77,10 @@ type SMTPService interface {
 	SendTestEmail(app *model.App, options smtp.SendTestEmailOptions) (err error)
 }
 
+type WebhookService interface {
+	TestDeliver(app *model.App, options webhook.TestDeliverOptions) (*hook.TestDeliveryResult, error)
+}
+
 type AppResourceManagerFactory interface {
 	NewManagerWithAppContext(appContext *config.AppContext) *appresource.Manager
 }
@@ -104,6 +110,7 @@ type Context struct {
 	DomainService        DomainService
 	CollaboratorService  CollaboratorService
 	SMTPService          SMTPService
+	WebhookService       WebhookService
 	AppResMgrFactory     AppResourceManagerFactory
 	AnalyticChartService AnalyticChartService
 }
@@ -11,3 +11,13 @@ var FeatureConfig = graphqlutil.NewJSONObjectScalar(
 	"FeatureConfig",
 	"The `FeatureConfig` scalar type represents an feature config JSON object",
 )
+
+var HookResponse = graphqlutil.NewJSONObjectScalar(
+	"HookResponse",
+	"The `HookResponse` scalar type represents a hook response JSON object",
+)
+
+var APIError = graphqlutil.NewJSONObjectScalar(
+	"APIError",
+	"The `APIError` scalar type represents an API error JSON object",
+)
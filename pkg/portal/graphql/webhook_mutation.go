@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	relay "github.com/authgear/graphql-go-relay"
+	"github.com/graphql-go/graphql"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/portal/webhook"
+)
+
+var testWebhookInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "TestWebhookInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"appID": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "App ID to test.",
+		},
+		"url": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "URL of the hook handler. Unless it is a configured handler, it must not be a private address.",
+		},
+		"eventType": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Type of the sample event, e.g. user.pre_create.",
+		},
+	},
+})
+
+var testWebhookPayload = graphql.NewObject(graphql.ObjectConfig{
+	Name: "TestWebhookPayload",
+	Fields: graphql.Fields{
+		"statusCode": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "HTTP status code of the response. It is null if no response is received, or the hook handler is a hook script.",
+		},
+		"durationMs": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Time taken to deliver the event in milliseconds.",
+		},
+		"response": &graphql.Field{
+			Type:        HookResponse,
+			Description: "The parsed hook response. It is null for non-blocking events, or if the delivery failed.",
+		},
+		"error": &graphql.Field{
+			Type:        APIError,
+			Description: "The reason of the failed delivery, e.g. invalid hook response.",
+		},
+	},
+})
+
+var _ = registerMutationField(
+	"testWebhook",
+	&graphql.Field{
+		Description: "Deliver a sample event to a hook handler",
+		Type:        graphql.NewNonNull(testWebhookPayload),
+		Args: graphql.FieldConfigArgument{
+			"input": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(testWebhookInput),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			input := p.Args["input"].(map[string]interface{})
+			appNodeID := input["appID"].(string)
+			url := input["url"].(string)
+			eventType := input["eventType"].(string)
+
+			resolvedNodeID := relay.FromGlobalID(appNodeID)
+			if resolvedNodeID.Type != typeApp {
+				return nil, apierrors.NewInvalid("invalid app ID")
+			}
+			appID := resolvedNodeID.ID
+
+			gqlCtx := GQLContext(p.Context)
+
+			// Access control: collaborator.
+			_, err := gqlCtx.AuthzService.CheckAccessOfViewer(appID)
+			if err != nil {
+				return nil, err
+			}
+
+			app, err := gqlCtx.AppService.Get(appID)
+			if err != nil {
+				return nil, err
+			}
+
+			result, err := gqlCtx.WebhookService.TestDeliver(app, webhook.TestDeliverOptions{
+				URL:       url,
+				EventType: eventType,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			payload := map[string]interface{}{
+				"durationMs": result.Duration.Milliseconds(),
+			}
+			if result.StatusCode != 0 {
+				payload["statusCode"] = result.StatusCode
+			}
+			if result.Response != nil {
+				payload["response"] = result.Response
+			}
+			if result.Error != nil {
+				payload["error"] = apierrors.AsAPIError(result.Error)
+			}
+
+			return payload, nil
+		},
+	},
+)
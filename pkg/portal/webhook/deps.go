@@ -0,0 +1,9 @@
+package webhook
+
+import (
+	"github.com/google/wire"
+)
+
+var DependencySet = wire.NewSet(
+	wire.Struct(new(Service), "*"),
+)
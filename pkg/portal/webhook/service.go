@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/authgear/authgear-server/pkg/api/apierrors"
+	"github.com/authgear/authgear-server/pkg/api/event"
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
+	"github.com/authgear/authgear-server/pkg/portal/model"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+)
+
+type TestDeliverOptions struct {
+	URL       string
+	EventType string
+}
+
+type Service struct {
+	Clock clock.Clock
+}
+
+// TestDeliver delivers a sample event to the hook handler at options.URL,
+// signed with the webhook key materials of app.
+func (s *Service) TestDeliver(app *model.App, options TestDeliverOptions) (*hook.TestDeliveryResult, error) {
+	u, err := url.Parse(options.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != config.HookScriptURLScheme) {
+		return nil, apierrors.NewInvalid("invalid hook handler URL")
+	}
+
+	cfg := app.Context.Config
+	secret, ok := cfg.SecretConfig.LookupData(config.WebhookKeyMaterialsKey).(*config.WebhookKeyMaterials)
+	if !ok {
+		return nil, apierrors.NewInvalid("webhook signing key is not configured")
+	}
+
+	e, err := hook.NewSampleEvent(event.Type(options.EventType), s.Clock.NowUTC())
+	if err != nil {
+		return nil, err
+	}
+
+	deliverer := &hook.Deliverer{
+		Config:    cfg.AppConfig.Hook,
+		Secret:    secret,
+		Clock:     s.Clock,
+		SyncHTTP:  hook.NewSyncHTTPClient(cfg.AppConfig.Hook),
+		Resources: app.Context.Resources,
+	}
+
+	// A configured handler is delivered to as the real events are.
+	// Otherwise, the URL is given by the developer, and the portal server
+	// must not be used to reach private addresses, or to send the handler credentials.
+	if u.Scheme == config.HookScriptURLScheme || isConfiguredHandler(cfg.AppConfig.Hook, options.URL) {
+		deliverer.Credentials, _ = cfg.SecretConfig.LookupData(config.WebhookHandlerCredentialsKey).(*config.WebhookHandlerCredentials)
+	} else {
+		deliverer.SyncHTTP = hook.SyncHTTPClient{
+			Client: httputil.NewExternalClientWithOptions(cfg.AppConfig.Hook.SyncTimeout.Duration(), httputil.ExternalClientOptions{
+				DenyPrivateAddress: true,
+			}),
+		}
+	}
+
+	result, err := deliverer.TestDeliver(options.URL, e)
+	if err != nil {
+		return nil, err
+	}
+	if errors.Is(result.Error, httputil.ErrPrivateAddress) {
+		return nil, apierrors.NewInvalid("hook handler URL must be configured to deliver to private address")
+	}
+	return result, nil
+}
+
+func isConfiguredHandler(cfg *config.HookConfig, urlStr string) bool {
+	for _, h := range cfg.BlockingHandlers {
+		if h.URL == urlStr {
+			return true
+		}
+	}
+	for _, h := range cfg.NonBlockingHandlers {
+		if h.URL == urlStr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/jwk"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/portal/model"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+)
+
+func TestServiceTestDeliver(t *testing.T) {
+	Convey("Service.TestDeliver", t, func() {
+		var requests []*http.Request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		key, err := jwk.New([]byte("aG9vay1zZWNyZXQ"))
+		So(err, ShouldBeNil)
+		set := jwk.NewSet()
+		_ = set.Add(key)
+
+		configuredURL := server.URL + "/configured"
+		hookConfig := &config.HookConfig{
+			SyncTimeout:      5,
+			SyncTotalTimeout: 10,
+			NonBlockingHandlers: []config.NonBlockingHandlersConfig{
+				{Events: []string{"*"}, URL: configuredURL},
+			},
+		}
+		app := &model.App{
+			ID: "app-id",
+			Context: &config.AppContext{
+				Config: &config.Config{
+					AppConfig: &config.AppConfig{Hook: hookConfig},
+					SecretConfig: &config.SecretConfig{
+						Secrets: []config.SecretItem{
+							{
+								Key:  config.WebhookKeyMaterialsKey,
+								Data: &config.WebhookKeyMaterials{Set: set},
+							},
+							{
+								Key: config.WebhookHandlerCredentialsKey,
+								Data: &config.WebhookHandlerCredentials{
+									Items: []config.WebhookHandlerCredentialsItem{
+										{URL: configuredURL, Headers: map[string]string{"X-Secret": "secret"}},
+										{URL: server.URL + "/unconfigured", Headers: map[string]string{"X-Secret": "secret"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		s := &Service{Clock: clock.NewMockClockAt("2006-01-02T15:04:05Z")}
+
+		Convey("should deliver to configured handler with credentials", func() {
+			result, err := s.TestDeliver(app, TestDeliverOptions{
+				URL:       configuredURL,
+				EventType: "user.created",
+			})
+			So(err, ShouldBeNil)
+			So(result.Error, ShouldBeNil)
+			So(result.StatusCode, ShouldEqual, http.StatusOK)
+			So(requests, ShouldHaveLength, 1)
+			So(requests[0].Header.Get("X-Secret"), ShouldEqual, "secret")
+		})
+
+		Convey("should not deliver to private address of unconfigured URL", func() {
+			_, err := s.TestDeliver(app, TestDeliverOptions{
+				URL:       server.URL + "/unconfigured",
+				EventType: "user.created",
+			})
+			So(err, ShouldBeError, "hook handler URL must be configured to deliver to private address")
+			So(requests, ShouldHaveLength, 0)
+		})
+
+		Convey("should reject invalid URL", func() {
+			_, err := s.TestDeliver(app, TestDeliverOptions{
+				URL:       "file:///etc/passwd",
+				EventType: "user.created",
+			})
+			So(err, ShouldBeError, "invalid hook handler URL")
+		})
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/portal/task"
 	"github.com/authgear/authgear-server/pkg/portal/task/tasks"
 	"github.com/authgear/authgear-server/pkg/portal/transport"
+	"github.com/authgear/authgear-server/pkg/portal/webhook"
 	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/util/httproute"
 	"github.com/authgear/authgear-server/pkg/util/intl"
@@ -219,6 +220,9 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 	smtpService := &smtp.Service{
 		Context: context,
 	}
+	webhookService := &webhook.Service{
+		Clock: clock,
+	}
 	databaseConfig := deps.ProvideDatabaseConfig(databaseEnvironmentConfig)
 	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(environmentConfig)
 	readHandle := auditdb.NewReadHandle(context, pool, databaseConfig, auditDatabaseCredentials, logFactory)
@@ -247,6 +251,7 @@ func newGraphQLHandler(p *deps.RequestProvider) http.Handler {
 		DomainService:           domainService,
 		CollaboratorService:     collaboratorService,
 		SMTPService:             smtpService,
+		WebhookService:          webhookService,
 		AppResMgrFactory:        managerFactory,
 		AnalyticChartService:    chartService,
 	}
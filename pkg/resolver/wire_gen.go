@@ -10,6 +10,7 @@ import (
 	"context"
 	"github.com/authgear/authgear-server/pkg/lib/analytic"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/oob"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/passkey"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
 	service2 "github.com/authgear/authgear-server/pkg/lib/authn/authenticator/service"
 	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/totp"
@@ -18,6 +19,7 @@ import (
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/loginid"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/oauth"
 	"github.com/authgear/authgear-server/pkg/lib/authn/identity/service"
+	"github.com/authgear/authgear-server/pkg/lib/authn/lockout"
 	"github.com/authgear/authgear-server/pkg/lib/authn/user"
 	"github.com/authgear/authgear-server/pkg/lib/deps"
 	"github.com/authgear/authgear-server/pkg/lib/feature/customattrs"
@@ -251,7 +253,9 @@ func newSessionMiddleware(p *deps.RequestProvider) httproute.Middleware {
 		SQLBuilder:  sqlBuilderApp,
 		SQLExecutor: sqlExecutor,
 	}
-	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore)
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, passwordLogger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
 	housekeeperLogger := password.NewHousekeeperLogger(factory)
 	housekeeper := &password.Housekeeper{
 		Store:  historyStore,
@@ -265,6 +269,7 @@ func newSessionMiddleware(p *deps.RequestProvider) httproute.Middleware {
 		Logger:          passwordLogger,
 		PasswordHistory: historyStore,
 		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
 		Housekeeper:     housekeeper,
 	}
 	totpStore := &totp.Store{
@@ -295,22 +300,55 @@ func newSessionMiddleware(p *deps.RequestProvider) httproute.Middleware {
 		Clock:     clock,
 		Logger:    oobLogger,
 	}
+	passkeyStore := &passkey.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	challengeStoreRedis := &passkey.ChallengeStoreRedis{
+		Redis: handle,
+		AppID: appID,
+		Clock: clock,
+	}
+	authenticatorPasskeyConfig := authenticatorConfig.Passkey
+	passkeyProvider := &passkey.Provider{
+		Store:          passkeyStore,
+		ChallengeStore: challengeStoreRedis,
+		Config:         authenticatorPasskeyConfig,
+		HTTPConfig:     httpConfig,
+		Clock:          clock,
+	}
 	ratelimitLogger := ratelimit.NewLogger(factory)
 	storageRedis := &ratelimit.StorageRedis{
 		AppID: appID,
 		Redis: handle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
+	}
+	authenticationLockoutConfig := authenticationConfig.Lockout
+	lockoutStorageRedis := &lockout.StorageRedis{
+		AppID: appID,
+		Redis: handle,
+	}
+	lockoutService := &lockout.Service{
+		Config:  authenticationLockoutConfig,
 		Clock:   clock,
+		Storage: lockoutStorageRedis,
 	}
 	service3 := &service2.Service{
 		Store:       store2,
 		Password:    passwordProvider,
 		TOTP:        totpProvider,
 		OOBOTP:      oobProvider,
+		Passkey:     passkeyProvider,
 		RateLimiter: limiter,
+		Lockout:     lockoutService,
 	}
 	verificationLogger := verification.NewLogger(factory)
 	verificationConfig := appConfig.Verification
@@ -513,10 +551,14 @@ func newSessionResolveHandler(p *deps.RequestProvider) http.Handler {
 		AppID: appID,
 		Redis: appredisHandle,
 	}
+	rateLimitsConfig := appConfig.RateLimits
+	rateLimitsFeatureConfig := featureConfig.RateLimits
 	limiter := &ratelimit.Limiter{
-		Logger:  ratelimitLogger,
-		Storage: storageRedis,
-		Clock:   clockClock,
+		Logger:        ratelimitLogger,
+		Storage:       storageRedis,
+		Clock:         clockClock,
+		Config:        rateLimitsConfig,
+		FeatureConfig: rateLimitsFeatureConfig,
 	}
 	verificationService := &verification.Service{
 		Request:           request,
@@ -0,0 +1,72 @@
+// Package backoff schedules the attempts of jobs retried with exponential back-off.
+package backoff
+
+import (
+	"time"
+)
+
+// Exponential is an exponential back-off.
+// The delay before the first retry is Base, and is doubled for each subsequent retry, up to Max.
+type Exponential struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay returns the delay before the next attempt after attempts failed attempts.
+func (e Exponential) Delay(attempts int) time.Duration {
+	delay := e.Base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= e.Max {
+			return e.Max
+		}
+	}
+	return delay
+}
+
+// State is the attempt state of a job.
+//
+// An attempt claims the job until the claim expires,
+// so that the job is not attempted concurrently,
+// and is attempted again if the attempt is lost, e.g. the process stops.
+type State struct {
+	// Attempts is the number of finished attempts.
+	Attempts int `json:"attempts,omitempty"`
+	// NextAttemptAt is the time of the next attempt.
+	// The job is due immediately if it is nil.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	// ClaimedUntil is set when the job is claimed by an attempt in progress.
+	ClaimedUntil *time.Time `json:"claimed_until,omitempty"`
+}
+
+// IsClaimed reports whether the job is claimed by an attempt in progress.
+func (s *State) IsClaimed(now time.Time) bool {
+	return s.ClaimedUntil != nil && s.ClaimedUntil.After(now)
+}
+
+// IsDue reports whether the job can be attempted now.
+func (s *State) IsDue(now time.Time) bool {
+	return !s.IsClaimed(now) && (s.NextAttemptAt == nil || !s.NextAttemptAt.After(now))
+}
+
+// Claim claims the job for an attempt for the duration of lease.
+func (s *State) Claim(now time.Time, lease time.Duration) {
+	claimedUntil := now.Add(lease)
+	s.ClaimedUntil = &claimedUntil
+}
+
+// Succeed records a successful attempt.
+func (s *State) Succeed() {
+	s.Attempts++
+	s.NextAttemptAt = nil
+	s.ClaimedUntil = nil
+}
+
+// Fail records a failed attempt, and schedules the next attempt after the back-off.
+func (s *State) Fail(now time.Time, backoff Exponential) time.Time {
+	s.Attempts++
+	s.ClaimedUntil = nil
+	nextAttemptAt := now.Add(backoff.Delay(s.Attempts))
+	s.NextAttemptAt = &nextAttemptAt
+	return nextAttemptAt
+}
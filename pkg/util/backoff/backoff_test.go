@@ -0,0 +1,67 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExponential(t *testing.T) {
+	Convey("Exponential", t, func() {
+		e := Exponential{Base: 1 * time.Minute, Max: 6 * time.Hour}
+
+		So(e.Delay(1), ShouldEqual, 1*time.Minute)
+		So(e.Delay(2), ShouldEqual, 2*time.Minute)
+		So(e.Delay(3), ShouldEqual, 4*time.Minute)
+		So(e.Delay(9), ShouldEqual, 256*time.Minute)
+		So(e.Delay(10), ShouldEqual, 6*time.Hour)
+		So(e.Delay(1000), ShouldEqual, 6*time.Hour)
+	})
+}
+
+func TestState(t *testing.T) {
+	Convey("State", t, func() {
+		now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		e := Exponential{Base: 10 * time.Second, Max: 10 * time.Minute}
+
+		Convey("should be due initially", func() {
+			s := &State{}
+			So(s.IsDue(now), ShouldBeTrue)
+		})
+
+		Convey("should not be due when claimed", func() {
+			s := &State{}
+			s.Claim(now, 2*time.Minute)
+			So(s.IsClaimed(now), ShouldBeTrue)
+			So(s.IsDue(now), ShouldBeFalse)
+			So(s.IsDue(now.Add(2*time.Minute)), ShouldBeTrue)
+		})
+
+		Convey("should back off after failed attempts", func() {
+			s := &State{}
+			s.Claim(now, 2*time.Minute)
+
+			So(s.Fail(now, e), ShouldEqual, now.Add(10*time.Second))
+			So(s.Attempts, ShouldEqual, 1)
+			So(s.ClaimedUntil, ShouldBeNil)
+			So(s.IsDue(now), ShouldBeFalse)
+			So(s.IsDue(now.Add(10*time.Second)), ShouldBeTrue)
+
+			So(s.Fail(now, e), ShouldEqual, now.Add(20*time.Second))
+			So(s.Attempts, ShouldEqual, 2)
+		})
+
+		Convey("should be due after successful attempt", func() {
+			next := now.Add(time.Minute)
+			s := &State{Attempts: 1, NextAttemptAt: &next}
+			s.Claim(now, 2*time.Minute)
+
+			s.Succeed()
+			So(s.Attempts, ShouldEqual, 2)
+			So(s.NextAttemptAt, ShouldBeNil)
+			So(s.ClaimedUntil, ShouldBeNil)
+			So(s.IsDue(now), ShouldBeTrue)
+		})
+	})
+}
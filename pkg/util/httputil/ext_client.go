@@ -1,12 +1,23 @@
 package httputil
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"syscall"
 	"time"
 )
 
+// ErrPrivateAddress is returned when a client with DenyPrivateAddress connects to a private address.
+var ErrPrivateAddress = errors.New("connecting to private address is not allowed")
+
 type ExternalClientOptions struct {
 	FollowRedirect bool
+	// DenyPrivateAddress denies connecting to loopback, private and link-local addresses.
+	// The address is checked when the connection is made, so it also applies to redirects
+	// and hosts resolving to different addresses.
+	DenyPrivateAddress bool
 }
 
 func NewExternalClient(timeout time.Duration) *http.Client {
@@ -19,6 +30,18 @@ func NewExternalClientWithOptions(timeout time.Duration, opts ExternalClientOpti
 	if !opts.FollowRedirect {
 		client.CheckRedirect = noFollowRedirectPolicy
 	}
+	if opts.DenyPrivateAddress {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   denyPrivateAddress,
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		// A proxy would connect to the address on behalf of the client.
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+		client.Transport = transport
+	}
 	client.Timeout = timeout
 	return client
 }
@@ -26,3 +49,33 @@ func NewExternalClientWithOptions(timeout time.Duration, opts ExternalClientOpti
 func noFollowRedirectPolicy(*http.Request, []*http.Request) error {
 	return http.ErrUseLastResponse
 }
+
+func denyPrivateAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || IsPrivateIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, address)
+	}
+	return nil
+}
+
+// IsPrivateIP reports whether ip is not a public unicast address.
+func IsPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598.
+var sharedAddressSpace = &net.IPNet{
+	IP:   net.IPv4(100, 64, 0, 0),
+	Mask: net.CIDRMask(10, 32),
+}
@@ -0,0 +1,38 @@
+package httputil_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/authgear/authgear-server/pkg/util/httputil"
+)
+
+func TestIsPrivateIP(t *testing.T) {
+	Convey("IsPrivateIP", t, func() {
+		for _, ip := range []string{
+			"127.0.0.1",
+			"10.0.0.1",
+			"172.16.0.1",
+			"192.168.1.1",
+			"169.254.169.254",
+			"100.64.0.1",
+			"0.0.0.0",
+			"::1",
+			"fd00::1",
+			"fe80::1",
+			"::ffff:127.0.0.1",
+		} {
+			So(httputil.IsPrivateIP(net.ParseIP(ip)), ShouldBeTrue)
+		}
+
+		for _, ip := range []string{
+			"8.8.8.8",
+			"1.1.1.1",
+			"2001:4860:4860::8888",
+		} {
+			So(httputil.IsPrivateIP(net.ParseIP(ip)), ShouldBeFalse)
+		}
+	})
+}
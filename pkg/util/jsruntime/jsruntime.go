@@ -0,0 +1,172 @@
+// Package jsruntime runs untrusted JavaScript in a sandboxed QuickJS runtime
+// with execution time and memory limits.
+package jsruntime
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+#include <time.h>
+
+// The following are declared in quickjs.h and are linked from github.com/lithdew/quickjs,
+// which does not expose them.
+typedef struct JSRuntime JSRuntime;
+typedef int JSInterruptHandler(JSRuntime *rt, void *opaque);
+void JS_SetMemoryLimit(JSRuntime *rt, size_t limit);
+void JS_SetMaxStackSize(JSRuntime *rt, size_t stack_size);
+void JS_SetInterruptHandler(JSRuntime *rt, JSInterruptHandler *cb, void *opaque);
+
+static int64_t jsruntime_now_ms() {
+	struct timespec ts;
+	clock_gettime(CLOCK_MONOTONIC, &ts);
+	return (int64_t)ts.tv_sec * 1000 + ts.tv_nsec / 1000000;
+}
+
+static int jsruntime_interrupt_handler(JSRuntime *rt, void *opaque) {
+	int64_t *deadline = opaque;
+	return jsruntime_now_ms() > *deadline;
+}
+
+static int64_t *jsruntime_set_deadline(JSRuntime *rt, int64_t timeout_ms) {
+	int64_t *deadline = malloc(sizeof(int64_t));
+	*deadline = jsruntime_now_ms() + timeout_ms;
+	JS_SetInterruptHandler(rt, jsruntime_interrupt_handler, deadline);
+	return deadline;
+}
+
+static int jsruntime_deadline_exceeded(int64_t *deadline) {
+	return jsruntime_now_ms() > *deadline;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"github.com/lithdew/quickjs"
+)
+
+func init() {
+	// quickjs.Runtime does not expose the JSRuntime it wraps, so runtimeRef reads
+	// it from the only field of the struct. Fail loudly if the layout changes.
+	if unsafe.Sizeof(quickjs.Runtime{}) != unsafe.Sizeof(uintptr(0)) {
+		panic("jsruntime: unexpected layout of quickjs.Runtime")
+	}
+}
+
+func runtimeRef(rt *quickjs.Runtime) *C.JSRuntime {
+	return *(**C.JSRuntime)(unsafe.Pointer(rt))
+}
+
+var ErrTimeout = errors.New("jsruntime: script execution timeout")
+var ErrNoResult = errors.New("jsruntime: function did not return a result")
+
+// ScriptError is the error thrown by the script.
+type ScriptError struct {
+	Message string
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("jsruntime: script error: %s", e.Message)
+}
+
+type RunOptions struct {
+	// Filename is the filename of the script shown in stack trace.
+	Filename string
+	// Script is the script defining a global function named by Function.
+	Script string
+	// Function is the name of the global function to invoke.
+	Function string
+	// Input is the JSON passed to the function as the only argument.
+	Input []byte
+	// Timeout is the limit of the total execution time.
+	Timeout time.Duration
+	// MemoryLimit is the limit of memory allocated by the runtime in bytes.
+	MemoryLimit uint64
+}
+
+// Run evaluates the script, and invokes the function with the input.
+// The return value of the function is returned in JSON.
+// The function may return a promise, which is awaited.
+func Run(opts RunOptions) (output []byte, err error) {
+	// QuickJS checks stack overflow with the stack pointer,
+	// so the runtime must not be moved to another thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	rt := quickjs.NewRuntime()
+	defer rt.Free()
+
+	rtRef := runtimeRef(&rt)
+	if opts.MemoryLimit > 0 {
+		C.JS_SetMemoryLimit(rtRef, C.size_t(opts.MemoryLimit))
+	}
+	deadline := C.jsruntime_set_deadline(rtRef, C.int64_t(opts.Timeout.Milliseconds()))
+	defer C.free(unsafe.Pointer(deadline))
+
+	ctx := rt.NewContext()
+	defer ctx.Free()
+
+	defer func() {
+		if err != nil && C.jsruntime_deadline_exceeded(deadline) != 0 {
+			err = ErrTimeout
+		}
+	}()
+
+	globals := ctx.Globals()
+	globals.Set("__input", ctx.String(string(opts.Input)))
+
+	script, err := ctx.EvalFile(opts.Script, opts.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("jsruntime: failed to evaluate script: %w", err)
+	}
+	script.Free()
+
+	invoke := fmt.Sprintf(`
+		var __output;
+		new Promise(function (resolve) { resolve(%s(JSON.parse(__input))); }).then(
+			function (value) { __output = { value: JSON.stringify(value) }; },
+			function (error) { __output = { error: String(error) }; }
+		);
+	`, opts.Function)
+	result, err := ctx.Eval(invoke)
+	if err != nil {
+		return nil, fmt.Errorf("jsruntime: failed to invoke function: %w", err)
+	}
+	result.Free()
+
+	for {
+		_, err := rt.ExecutePendingJob()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("jsruntime: failed to execute pending job: %w", err)
+		}
+	}
+
+	out := globals.Get("__output")
+	defer out.Free()
+	if !out.IsObject() {
+		return nil, ErrNoResult
+	}
+
+	if errValue := out.Get("error"); !errValue.IsUndefined() {
+		defer errValue.Free()
+		return nil, &ScriptError{Message: errValue.String()}
+	} else {
+		errValue.Free()
+	}
+
+	value := out.Get("value")
+	defer value.Free()
+	if !value.IsString() {
+		// JSON.stringify returns undefined for undefined and functions.
+		return nil, ErrNoResult
+	}
+
+	return []byte(value.String()), nil
+}
@@ -0,0 +1,67 @@
+package jsruntime
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRun(t *testing.T) {
+	Convey("Run", t, func() {
+		run := func(script string) ([]byte, error) {
+			return Run(RunOptions{
+				Filename:    "test.js",
+				Script:      script,
+				Function:    "handler",
+				Input:       []byte(`{"name":"John"}`),
+				Timeout:     500 * time.Millisecond,
+				MemoryLimit: 16 * 1024 * 1024,
+			})
+		}
+
+		Convey("should return the result in JSON", func() {
+			output, err := run(`function handler(e) { return { greeting: "Hello, " + e.name }; }`)
+			So(err, ShouldBeNil)
+			So(string(output), ShouldEqual, `{"greeting":"Hello, John"}`)
+		})
+
+		Convey("should await promise", func() {
+			output, err := run(`async function handler(e) { return await Promise.resolve(e.name); }`)
+			So(err, ShouldBeNil)
+			So(string(output), ShouldEqual, `"John"`)
+		})
+
+		Convey("should return error thrown by the script", func() {
+			_, err := run(`function handler(e) { throw new Error("boom"); }`)
+			So(err, ShouldBeError, "jsruntime: script error: Error: boom")
+
+			_, err = run(`async function handler(e) { throw new Error("boom"); }`)
+			So(err, ShouldBeError, "jsruntime: script error: Error: boom")
+		})
+
+		Convey("should return error if no result", func() {
+			_, err := run(`function handler(e) {}`)
+			So(err, ShouldEqual, ErrNoResult)
+		})
+
+		Convey("should return error if the function is not defined", func() {
+			_, err := run(`var a = 1;`)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should stop script exceeding timeout", func() {
+			_, err := run(`function handler(e) { for (;;) {} }`)
+			So(err, ShouldEqual, ErrTimeout)
+
+			_, err = run(`for (;;) {}`)
+			So(err, ShouldEqual, ErrTimeout)
+		})
+
+		Convey("should stop script exceeding memory limit", func() {
+			_, err := run(`function handler(e) { var s = "x"; for (;;) { s = s + s; } }`)
+			So(err, ShouldNotBeNil)
+			So(err, ShouldNotEqual, ErrTimeout)
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package password
+
+import (
+	"crypto/subtle"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2iPassword has the same encoding as argon2idPassword.
+// It is supported for verifying imported hashes only.
+type argon2iPassword struct{}
+
+var _ passwordFormat = argon2iPassword{}
+
+func (argon2iPassword) ID() string {
+	return "argon2i"
+}
+
+func (argon2iPassword) Hash(password []byte) ([]byte, error) {
+	return nil, errVerifyOnlyFormat
+}
+
+func (argon2iPassword) Compare(password, hash []byte) error {
+	params, salt, key, err := argon2idPassword{}.parse(hash)
+	if err != nil {
+		return err
+	}
+	actual := argon2.Key(password, salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return errMismatchedHashAndPassword
+	}
+	return nil
+}
+
+func (argon2iPassword) Validate(hash []byte) error {
+	_, _, _, err := argon2idPassword{}.parse(hash)
+	return err
+}
@@ -0,0 +1,24 @@
+package password
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestArgon2i(t *testing.T) {
+	Convey("argon2i", t, func() {
+		// The example of https://github.com/P-H-C/phc-winner-argon2
+		h := []byte("$argon2i$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG")
+
+		Convey("should compare as expected", func() {
+			So(argon2iPassword{}.Compare([]byte("password"), h), ShouldBeNil)
+			So(argon2iPassword{}.Compare([]byte("Password"), h), ShouldBeError)
+			So(Compare([]byte("password"), h), ShouldBeNil)
+		})
+		Convey("should validate hash", func() {
+			So(argon2iPassword{}.Validate(h), ShouldBeNil)
+			So(argon2iPassword{}.Validate([]byte("$argon2i$v=19$m=65536,t=0,p=4$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG")), ShouldBeError)
+		})
+	})
+}
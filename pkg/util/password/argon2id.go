@@ -0,0 +1,121 @@
+package password
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/authgear/authgear-server/pkg/util/rand"
+)
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+
+	// The cost parameters of a hash are bounded by the maxima of the configuration,
+	// so that a crafted hash cannot exhaust the resources.
+	argon2MaxMemory      = 1048576
+	argon2MaxIterations  = 16
+	argon2MaxParallelism = 16
+)
+
+// argon2idPassword encodes the hash in the PHC string format, i.e.
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+type argon2idPassword struct {
+	// Memory is in KiB.
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+var _ costFormat = argon2idPassword{}
+
+func (p argon2idPassword) ID() string {
+	return "argon2id"
+}
+
+func (p argon2idPassword) Hash(password []byte) ([]byte, error) {
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.SecureRand.Read(salt); err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey(password, salt, p.Iterations, p.Memory, p.Parallelism, argon2idKeyLength)
+	data := fmt.Sprintf("v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		p.Memory,
+		p.Iterations,
+		p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+	return constructPasswordFormat([]byte(p.ID()), []byte(data)), nil
+}
+
+func (p argon2idPassword) Compare(password, hash []byte) error {
+	params, salt, key, err := p.parse(hash)
+	if err != nil {
+		return err
+	}
+	actual := argon2.IDKey(password, salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return errMismatchedHashAndPassword
+	}
+	return nil
+}
+
+func (p argon2idPassword) NeedsRehash(hash []byte) bool {
+	params, _, _, err := p.parse(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory < p.Memory ||
+		params.Iterations < p.Iterations ||
+		params.Parallelism < p.Parallelism
+}
+
+func (p argon2idPassword) Validate(hash []byte) error {
+	_, _, _, err := p.parse(hash)
+	return err
+}
+
+func (p argon2idPassword) parse(hash []byte) (params argon2idPassword, salt []byte, key []byte, err error) {
+	_, data, err := parsePasswordFormat(hash)
+	if err != nil {
+		return
+	}
+
+	var version int
+	parts := splitPasswordFormatData(data)
+	if len(parts) != 4 {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if _, err = fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if _, err = fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if params.Iterations < 1 || params.Iterations > argon2MaxIterations ||
+		params.Parallelism < 1 || params.Parallelism > argon2MaxParallelism ||
+		// Argon2 requires at least 8 KiB of memory per lane.
+		params.Memory < 8*uint32(params.Parallelism) || params.Memory > argon2MaxMemory {
+		err = errInvalidPasswordFormat
+		return
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[3]); err != nil {
+		err = errInvalidPasswordFormat
+		return
+	}
+	err = checkSaltAndKey(salt, key)
+	return
+}
@@ -0,0 +1,57 @@
+package password
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestArgon2id(t *testing.T) {
+	Convey("Argon2id", t, func() {
+		argon2id := argon2idPassword{Memory: 1024, Iterations: 1, Parallelism: 1}
+		Convey("should hash as expected", func() {
+			h, err := argon2id.Hash([]byte("password"))
+			So(err, ShouldBeNil)
+			So(string(h), ShouldStartWith, "$argon2id$v=19$m=1024,t=1,p=1$")
+			So(argon2id.Compare([]byte("password"), h), ShouldBeNil)
+		})
+		Convey("should compare as expected", func() {
+			h := []byte("$argon2id$v=19$m=1024,t=1,p=1$MECvAX/PlY0gtHz8/6AHgg$i1vMd2ovMj07hbeixG72HUvd3COJ0N57SLHhq64nZdY")
+			So(argon2id.Compare([]byte("password"), h), ShouldBeNil)
+			So(argon2id.Compare([]byte("Password"), h), ShouldBeError)
+
+			// Parameters are read from the hash.
+			So(argon2idPassword{}.Compare([]byte("password"), h), ShouldBeNil)
+
+			So(argon2id.Compare([]byte("password"), []byte("$argon2id$v=19$m=1024,t=1,p=1$invalid")), ShouldBeError)
+		})
+		Convey("should reject malformed hash", func() {
+			salt := "MECvAX/PlY0gtHz8/6AHgg"
+			key := "i1vMd2ovMj07hbeixG72HUvd3COJ0N57SLHhq64nZdY"
+			for _, h := range []string{
+				// Empty or truncated key would match any password.
+				"$argon2id$v=19$m=1024,t=1,p=1$" + salt + "$",
+				"$argon2id$v=19$m=1024,t=1,p=1$" + salt + "$i1vMd2ovMj07hbei",
+				"$argon2id$v=19$m=1024,t=1,p=1$$" + key,
+				// Cost parameters are bounded.
+				"$argon2id$v=19$m=7,t=1,p=1$" + salt + "$" + key,
+				"$argon2id$v=19$m=1048577,t=1,p=1$" + salt + "$" + key,
+				"$argon2id$v=19$m=1024,t=0,p=1$" + salt + "$" + key,
+				"$argon2id$v=19$m=1024,t=17,p=1$" + salt + "$" + key,
+				"$argon2id$v=19$m=1024,t=1,p=0$" + salt + "$" + key,
+				"$argon2id$v=19$m=1024,t=1,p=17$" + salt + "$" + key,
+			} {
+				So(argon2id.Validate([]byte(h)), ShouldBeError)
+				So(argon2id.Compare([]byte("password"), []byte(h)), ShouldBeError)
+			}
+		})
+		Convey("should tell if rehash is needed", func() {
+			h := []byte("$argon2id$v=19$m=1024,t=1,p=1$MECvAX/PlY0gtHz8/6AHgg$i1vMd2ovMj07hbeixG72HUvd3COJ0N57SLHhq64nZdY")
+			So(argon2id.NeedsRehash(h), ShouldBeFalse)
+			So(argon2idPassword{Memory: 512, Iterations: 1, Parallelism: 1}.NeedsRehash(h), ShouldBeFalse)
+			So(argon2idPassword{Memory: 2048, Iterations: 1, Parallelism: 1}.NeedsRehash(h), ShouldBeTrue)
+			So(argon2idPassword{Memory: 1024, Iterations: 2, Parallelism: 1}.NeedsRehash(h), ShouldBeTrue)
+			So(argon2idPassword{Memory: 1024, Iterations: 1, Parallelism: 2}.NeedsRehash(h), ShouldBeTrue)
+		})
+	})
+}
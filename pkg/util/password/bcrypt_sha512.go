@@ -6,9 +6,12 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-type bcryptSHA512Password struct{}
+type bcryptSHA512Password struct {
+	// Cost is bcrypt.DefaultCost if it is zero.
+	Cost int
+}
 
-var _ passwordFormat = bcryptSHA512Password{}
+var _ costFormat = bcryptSHA512Password{}
 
 func (p bcryptSHA512Password) ID() string {
 	return "bcrypt-sha512"
@@ -16,7 +19,7 @@ func (p bcryptSHA512Password) ID() string {
 
 func (p bcryptSHA512Password) Hash(password []byte) ([]byte, error) {
 	shaHash := sha512.Sum512(password)
-	h, err := bcrypt.GenerateFromPassword(shaHash[:], bcrypt.DefaultCost)
+	h, err := bcrypt.GenerateFromPassword(shaHash[:], p.cost())
 	if err != nil {
 		return nil, err
 	}
@@ -31,3 +34,31 @@ func (p bcryptSHA512Password) Compare(password, hash []byte) error {
 	shaHash := sha512.Sum512(password)
 	return bcrypt.CompareHashAndPassword(data, shaHash[:])
 }
+
+func (p bcryptSHA512Password) NeedsRehash(hash []byte) bool {
+	_, data, err := parsePasswordFormat(hash)
+	if err != nil {
+		return true
+	}
+	cost, err := bcrypt.Cost(data)
+	if err != nil {
+		return true
+	}
+	return cost < p.cost()
+}
+
+func (p bcryptSHA512Password) Validate(hash []byte) error {
+	_, data, err := parsePasswordFormat(hash)
+	if err != nil {
+		return err
+	}
+	_, err = bcrypt.Cost(data)
+	return err
+}
+
+func (p bcryptSHA512Password) cost() int {
+	if p.Cost == 0 {
+		return bcrypt.DefaultCost
+	}
+	return p.Cost
+}
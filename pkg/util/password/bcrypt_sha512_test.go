@@ -27,5 +27,11 @@ func TestBCryptSHA512(t *testing.T) {
 			// should NOT truncate passwords
 			So(bcrypt.Compare([]byte("password12345678password12345678password12345678password12345678password12345670"), h), ShouldBeError)
 		})
+		Convey("should tell if rehash is needed", func() {
+			h := []byte("$bcrypt-sha512$$2a$10$qQybX3kAJNT2YqYRVmbfjO5EhgWm6vV4cWmXo2ZATAuBmCyJM2fKu")
+			So(bcrypt.NeedsRehash(h), ShouldBeFalse)
+			So(bcryptSHA512Password{Cost: 10}.NeedsRehash(h), ShouldBeFalse)
+			So(bcryptSHA512Password{Cost: 12}.NeedsRehash(h), ShouldBeTrue)
+		})
 	})
 }
@@ -16,6 +16,14 @@ func init() {
 	supportedFormats = map[string]passwordFormat{}
 	for _, fmt := range []passwordFormat{
 		bcryptSHA512Password{},
+		argon2idPassword{},
+		scryptPassword{},
+		// Formats of hashes imported from other systems.
+		argon2iPassword{},
+		pbkdf2SHA1Password,
+		pbkdf2SHA256Password,
+		pbkdf2SHA512Password,
+		firebaseScryptPassword{},
 	} {
 		supportedFormats[fmt.ID()] = fmt
 	}
@@ -35,12 +43,7 @@ func resolveFormat(hash []byte) (passwordFormat, error) {
 }
 
 func Hash(password []byte) ([]byte, error) {
-	// Reject if new password is too long
-	if len(password) > MaxLength {
-		return nil, ErrTooLong
-	}
-
-	return latestFormat.Hash(password)
+	return hash(latestFormat, password)
 }
 
 func Compare(password, hash []byte) error {
@@ -54,7 +57,69 @@ func Compare(password, hash []byte) error {
 	return fmt.Compare(password, hash)
 }
 
+// ValidateHash checks whether hash is well-formed in one of the supported formats,
+// so that a hash imported from other systems can be verified later.
+func ValidateHash(hash []byte) error {
+	fmt, err := resolveFormat(hash)
+	if err != nil {
+		return err
+	}
+	if f, ok := fmt.(validatingFormat); ok {
+		return f.Validate(hash)
+	}
+	return nil
+}
+
 func TryMigrate(password []byte, hash *[]byte) (migrated bool, err error) {
+	return tryMigrate(latestFormat, password, hash)
+}
+
+// Hasher hashes new passwords with a format of specific cost parameters,
+// and migrates existing hashes of other formats or lower cost parameters.
+type Hasher struct {
+	format passwordFormat
+}
+
+func NewBcryptSHA512Hasher(cost int) *Hasher {
+	return &Hasher{format: bcryptSHA512Password{Cost: cost}}
+}
+
+// NewArgon2idHasher returns a Hasher of Argon2id. memory is in KiB.
+func NewArgon2idHasher(memory uint32, iterations uint32, parallelism uint8) *Hasher {
+	return &Hasher{format: argon2idPassword{
+		Memory:      memory,
+		Iterations:  iterations,
+		Parallelism: parallelism,
+	}}
+}
+
+// NewScryptHasher returns a Hasher of scrypt. logN is the base 2 logarithm of N.
+func NewScryptHasher(logN int, blockSize int, parallelism int) *Hasher {
+	return &Hasher{format: scryptPassword{
+		LogN:        logN,
+		BlockSize:   blockSize,
+		Parallelism: parallelism,
+	}}
+}
+
+func (h *Hasher) Hash(password []byte) ([]byte, error) {
+	return hash(h.format, password)
+}
+
+func (h *Hasher) TryMigrate(password []byte, hash *[]byte) (migrated bool, err error) {
+	return tryMigrate(h.format, password, hash)
+}
+
+func hash(latest passwordFormat, password []byte) ([]byte, error) {
+	// Reject if new password is too long
+	if len(password) > MaxLength {
+		return nil, ErrTooLong
+	}
+
+	return latest.Hash(password)
+}
+
+func tryMigrate(latest passwordFormat, password []byte, hash *[]byte) (migrated bool, err error) {
 	// Do not enforce password length limit: migration of old password should
 	// not fail due to length limit
 
@@ -62,10 +127,14 @@ func TryMigrate(password []byte, hash *[]byte) (migrated bool, err error) {
 	if err != nil {
 		return
 	}
-	if fmt.ID() == latestFormat.ID() {
-		return
+	if fmt.ID() == latest.ID() {
+		// Rehash only if the cost parameters are raised.
+		f, ok := latest.(costFormat)
+		if !ok || !f.NeedsRehash(*hash) {
+			return
+		}
 	}
-	newHash, err := latestFormat.Hash(password)
+	newHash, err := latest.Hash(password)
 	if err != nil {
 		return
 	}
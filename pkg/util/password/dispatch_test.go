@@ -74,3 +74,108 @@ func TestDispatch(t *testing.T) {
 		})
 	})
 }
+
+func TestHasher(t *testing.T) {
+	Convey("Hasher", t, func() {
+		hasher := NewArgon2idHasher(1024, 1, 1)
+		password := []byte("password")
+
+		Convey("should hash with the configured format", func() {
+			h, err := hasher.Hash(password)
+			So(err, ShouldBeNil)
+			So(string(h), ShouldStartWith, "$argon2id$v=19$m=1024,t=1,p=1$")
+			So(Compare(password, h), ShouldBeNil)
+		})
+
+		Convey("should migrate weaker algorithm", func() {
+			h := []byte("$2a$10$4yzWhYLTp56Aire5CaS2EuUQjs0TiDa83faJe095mUeajNJUyrJDK")
+			migrated, err := hasher.TryMigrate(password, &h)
+			So(err, ShouldBeNil)
+			So(migrated, ShouldBeTrue)
+			So(string(h), ShouldStartWith, "$argon2id$v=19$m=1024,t=1,p=1$")
+			So(Compare(password, h), ShouldBeNil)
+		})
+
+		Convey("should migrate lower cost", func() {
+			h, err := NewArgon2idHasher(512, 1, 1).Hash(password)
+			So(err, ShouldBeNil)
+			migrated, err := hasher.TryMigrate(password, &h)
+			So(err, ShouldBeNil)
+			So(migrated, ShouldBeTrue)
+			So(string(h), ShouldStartWith, "$argon2id$v=19$m=1024,t=1,p=1$")
+		})
+
+		Convey("should not migrate same or higher cost", func() {
+			h, err := NewArgon2idHasher(2048, 1, 1).Hash(password)
+			So(err, ShouldBeNil)
+			original := string(h)
+			migrated, err := hasher.TryMigrate(password, &h)
+			So(err, ShouldBeNil)
+			So(migrated, ShouldBeFalse)
+			So(string(h), ShouldEqual, original)
+		})
+
+		Convey("should migrate between algorithms", func() {
+			h, err := hasher.Hash(password)
+			So(err, ShouldBeNil)
+			migrated, err := NewScryptHasher(10, 8, 1).TryMigrate(password, &h)
+			So(err, ShouldBeNil)
+			So(migrated, ShouldBeTrue)
+			So(string(h), ShouldStartWith, "$scrypt$ln=10,r=8,p=1$")
+			So(Compare(password, h), ShouldBeNil)
+		})
+	})
+}
+
+func TestImportedHash(t *testing.T) {
+	Convey("Imported hash", t, func() {
+		password := []byte("password")
+
+		Convey("should validate hash", func() {
+			So(ValidateHash([]byte("$2a$10$4yzWhYLTp56Aire5CaS2EuUQjs0TiDa83faJe095mUeajNJUyrJDK")), ShouldBeNil)
+			So(ValidateHash([]byte("$2y$10$4yzWhYLTp56Aire5CaS2EuUQjs0TiDa83faJe095mUeajNJUyrJDK")), ShouldBeNil)
+			So(ValidateHash([]byte("$pbkdf2-sha256$i=1000$c2FsdHNhbHRzYWx0c2FsdA$8nX7hwFEzIB8aPajJTYK8weHQc5Ngz0pFVAKvSu4jQA")), ShouldBeNil)
+			So(ValidateHash([]byte("password")), ShouldBeError)
+			So(ValidateHash([]byte("$2a$10$short")), ShouldBeError)
+			So(ValidateHash([]byte("$md5$whatever")), ShouldBeError)
+		})
+
+		Convey("should reject empty keys and oversize parameters of every format", func() {
+			for _, h := range []string{
+				// bcrypt
+				"$2a$10$",
+				"$2a$32$4yzWhYLTp56Aire5CaS2EuUQjs0TiDa83faJe095mUeajNJUyrJDK",
+				// Argon2id
+				"$argon2id$v=19$m=1024,t=1,p=1$MECvAX/PlY0gtHz8/6AHgg$",
+				"$argon2id$v=19$m=4194304,t=1,p=1$MECvAX/PlY0gtHz8/6AHgg$i1vMd2ovMj07hbeixG72HUvd3COJ0N57SLHhq64nZdY",
+				// Argon2i
+				"$argon2i$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$",
+				"$argon2i$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$RdescudvJCsg",
+				"$argon2i$v=19$m=65536,t=100,p=4$c29tZXNhbHQ$RdescudvJCsgt3ub+b+dWRWJTmaaJObG",
+				// scrypt
+				"$scrypt$ln=15,r=8,p=1$c2FsdA$",
+				"$scrypt$ln=30,r=8,p=1$chkihucgP2T31mvG0q23KA$hKWIK/ROAgh4LCvsckXTqNueNvp+Ki+oOKrGk+WTtTs",
+				// PBKDF2
+				"$pbkdf2-sha1$i=1000$c2FsdHNhbHRzYWx0c2FsdA$",
+				"$pbkdf2-sha512$i=100000000$c2FsdHNhbHRzYWx0c2FsdA$715rqIr5dXOVPpBhqqsugl037zT5bWJTWYmZtIcK8hA",
+				// Firebase scrypt
+				"$firebase-scrypt$ln=14,r=8$$Bw==$42xEC+ixf3L2lw==$",
+				"$firebase-scrypt$ln=14,r=8$c2lnbmVya2V5c2lnbmVya2V5$Bw==$$c2lnbmVya2V5c2lnbmVya2V5",
+				"$firebase-scrypt$ln=30,r=8$c2lnbmVya2V5c2lnbmVya2V5$Bw==$42xEC+ixf3L2lw==$c2lnbmVya2V5c2lnbmVya2V5",
+				"$firebase-scrypt$ln=14,r=0$c2lnbmVya2V5c2lnbmVya2V5$Bw==$42xEC+ixf3L2lw==$c2lnbmVya2V5c2lnbmVya2V5",
+			} {
+				So(ValidateHash([]byte(h)), ShouldBeError)
+				So(Compare(password, []byte(h)), ShouldBeError)
+			}
+		})
+
+		Convey("should migrate to the hasher format", func() {
+			h := []byte("$pbkdf2-sha256$i=1000$c2FsdHNhbHRzYWx0c2FsdA$8nX7hwFEzIB8aPajJTYK8weHQc5Ngz0pFVAKvSu4jQA")
+			migrated, err := NewScryptHasher(10, 8, 1).TryMigrate(password, &h)
+			So(err, ShouldBeNil)
+			So(migrated, ShouldBeTrue)
+			So(string(h), ShouldStartWith, "$scrypt$ln=10,r=8,p=1$")
+			So(Compare(password, h), ShouldBeNil)
+		})
+	})
+}
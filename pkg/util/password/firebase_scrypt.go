@@ -0,0 +1,104 @@
+package password
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// firebaseScryptPassword is the modified scrypt of Firebase Authentication.
+// The hash is encoded as
+// $firebase-scrypt$ln=<mem_cost>,r=<rounds>$<signer_key>$<salt_separator>$<salt>$<hash>
+// where the binary values are in standard base64, as exported by Firebase.
+// It is supported for verifying imported hashes only.
+type firebaseScryptPassword struct{}
+
+var _ passwordFormat = firebaseScryptPassword{}
+
+func (firebaseScryptPassword) ID() string {
+	return "firebase-scrypt"
+}
+
+func (firebaseScryptPassword) Hash(password []byte) ([]byte, error) {
+	return nil, errVerifyOnlyFormat
+}
+
+func (p firebaseScryptPassword) Compare(password, hash []byte) error {
+	params, err := p.parse(hash)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 0, len(params.salt)+len(params.saltSeparator))
+	salt = append(salt, params.salt...)
+	salt = append(salt, params.saltSeparator...)
+	derivedKey, err := scrypt.Key(password, salt, 1<<params.memCost, params.rounds, 1, 32)
+	if err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return err
+	}
+	actual := make([]byte, len(params.signerKey))
+	cipher.NewCTR(block, make([]byte, aes.BlockSize)).XORKeyStream(actual, params.signerKey)
+
+	if subtle.ConstantTimeCompare(actual, params.key) != 1 {
+		return errMismatchedHashAndPassword
+	}
+	return nil
+}
+
+func (p firebaseScryptPassword) Validate(hash []byte) error {
+	_, err := p.parse(hash)
+	return err
+}
+
+type firebaseScryptParams struct {
+	memCost       int
+	rounds        int
+	signerKey     []byte
+	saltSeparator []byte
+	salt          []byte
+	key           []byte
+}
+
+func (firebaseScryptPassword) parse(hash []byte) (params firebaseScryptParams, err error) {
+	_, data, err := parsePasswordFormat(hash)
+	if err != nil {
+		return
+	}
+
+	parts := splitPasswordFormatData(data)
+	if len(parts) != 5 {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if _, err = fmt.Sscanf(parts[0], "ln=%d,r=%d", &params.memCost, &params.rounds); err != nil {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if params.memCost < 1 || params.memCost > scryptMaxLogN ||
+		params.rounds < 1 || params.rounds > scryptMaxBlockSize {
+		err = errInvalidPasswordFormat
+		return
+	}
+
+	for i, v := range []*[]byte{&params.signerKey, &params.saltSeparator, &params.salt, &params.key} {
+		if *v, err = base64.StdEncoding.DecodeString(parts[i+1]); err != nil {
+			err = errInvalidPasswordFormat
+			return
+		}
+	}
+	if len(params.key) != len(params.signerKey) || len(params.saltSeparator) > maxSaltLength {
+		err = errInvalidPasswordFormat
+		return
+	}
+	err = checkSaltAndKey(params.salt, params.key)
+	return
+}
@@ -0,0 +1,31 @@
+package password
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFirebaseScrypt(t *testing.T) {
+	Convey("firebase-scrypt", t, func() {
+		// The test vector of https://github.com/firebase/scrypt
+		h := []byte("$firebase-scrypt$ln=14,r=8" +
+			"$jxspr8Ki0RYycVU8zykbdLGjFQ3McFUH0uiiTvC8pVMXAn210wjLNmdZJzxUECKbm0QsEmYUSDzZvpjeJ9WmXA==" +
+			"$Bw==" +
+			"$42xEC+ixf3L2lw==" +
+			"$lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ==")
+		format := firebaseScryptPassword{}
+
+		Convey("should compare as expected", func() {
+			So(format.Compare([]byte("user1password"), h), ShouldBeNil)
+			So(format.Compare([]byte("user2password"), h), ShouldBeError)
+			So(Compare([]byte("user1password"), h), ShouldBeNil)
+		})
+		Convey("should validate hash", func() {
+			So(format.Validate(h), ShouldBeNil)
+			So(format.Validate([]byte("$firebase-scrypt$ln=14,r=8$c2lnbmVya2V5c2lnbmVya2V5$Bw==$42xEC+ixf3L2lw==$c2lnbmVya2V5c2lnbmVya2V5")), ShouldBeNil)
+			So(format.Validate([]byte("$firebase-scrypt$ln=14,r=8$Bw==$Bw==$Bw==")), ShouldBeError)
+			So(format.Validate([]byte("$firebase-scrypt$ln=99,r=8$Bw==$Bw==$Bw==$Bw==")), ShouldBeError)
+		})
+	})
+}
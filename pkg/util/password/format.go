@@ -3,6 +3,7 @@ package password
 import (
 	"bytes"
 	"errors"
+	"strings"
 )
 
 type passwordFormat interface {
@@ -11,7 +12,30 @@ type passwordFormat interface {
 	Compare(password, hash []byte) error
 }
 
+// costFormat is a passwordFormat with tunable cost parameters.
+type costFormat interface {
+	passwordFormat
+	// NeedsRehash reports whether hash is created with lower cost parameters.
+	NeedsRehash(hash []byte) bool
+}
+
+// validatingFormat is a passwordFormat that can check whether a hash is well-formed,
+// without the cost of verifying a password.
+type validatingFormat interface {
+	passwordFormat
+	Validate(hash []byte) error
+}
+
+const (
+	// minKeyLength rejects truncated keys, which would match too many passwords.
+	minKeyLength  = 16
+	maxKeyLength  = 128
+	maxSaltLength = 128
+)
+
 var errInvalidPasswordFormat = errors.New("invalid password format")
+var errMismatchedHashAndPassword = errors.New("hash and password mismatch")
+var errVerifyOnlyFormat = errors.New("password format is supported for verification only")
 
 func parsePasswordFormat(h []byte) (id []byte, data []byte, err error) {
 	i := bytes.IndexByte(h, '$')
@@ -40,3 +64,17 @@ func constructPasswordFormat(id []byte, data []byte) []byte {
 	copy(h[len(id)+2:], data)
 	return h
 }
+
+func splitPasswordFormatData(data []byte) []string {
+	return strings.Split(string(data), "$")
+}
+
+func checkSaltAndKey(salt []byte, key []byte) error {
+	if len(salt) == 0 || len(salt) > maxSaltLength {
+		return errInvalidPasswordFormat
+	}
+	if len(key) < minKeyLength || len(key) > maxKeyLength {
+		return errInvalidPasswordFormat
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package password
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	stdhash "hash"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// pbkdf2Password encodes the hash in the PHC string format, i.e.
+// $pbkdf2-<digest>$i=<iterations>$<salt>$<key>
+// It is supported for verifying imported hashes only.
+type pbkdf2Password struct {
+	Digest  string
+	newHash func() stdhash.Hash
+}
+
+var _ passwordFormat = pbkdf2Password{}
+
+// pbkdf2MaxIterations bounds the cost of verifying a crafted hash.
+const pbkdf2MaxIterations = 10000000
+
+var (
+	pbkdf2SHA1Password   = pbkdf2Password{Digest: "sha1", newHash: sha1.New}
+	pbkdf2SHA256Password = pbkdf2Password{Digest: "sha256", newHash: sha256.New}
+	pbkdf2SHA512Password = pbkdf2Password{Digest: "sha512", newHash: sha512.New}
+)
+
+func (p pbkdf2Password) ID() string {
+	return "pbkdf2-" + p.Digest
+}
+
+func (p pbkdf2Password) Hash(password []byte) ([]byte, error) {
+	return nil, errVerifyOnlyFormat
+}
+
+func (p pbkdf2Password) Compare(password, hash []byte) error {
+	iterations, salt, key, err := p.parse(hash)
+	if err != nil {
+		return err
+	}
+	actual := pbkdf2.Key(password, salt, iterations, len(key), p.newHash)
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return errMismatchedHashAndPassword
+	}
+	return nil
+}
+
+func (p pbkdf2Password) Validate(hash []byte) error {
+	_, _, _, err := p.parse(hash)
+	return err
+}
+
+func (p pbkdf2Password) parse(hash []byte) (iterations int, salt []byte, key []byte, err error) {
+	_, data, err := parsePasswordFormat(hash)
+	if err != nil {
+		return
+	}
+
+	parts := splitPasswordFormatData(data)
+	if len(parts) != 3 {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if _, err = fmt.Sscanf(parts[0], "i=%d", &iterations); err != nil || iterations < 1 || iterations > pbkdf2MaxIterations {
+		err = errInvalidPasswordFormat
+		return
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		err = errInvalidPasswordFormat
+		return
+	}
+	err = checkSaltAndKey(salt, key)
+	return
+}
@@ -0,0 +1,39 @@
+package password
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPBKDF2(t *testing.T) {
+	Convey("pbkdf2", t, func() {
+		Convey("should compare as expected", func() {
+			cases := []struct {
+				format passwordFormat
+				hash   string
+			}{
+				{pbkdf2SHA1Password, "$pbkdf2-sha1$i=1000$c2FsdHNhbHRzYWx0c2FsdA$2FWw/oC7TQkskizC+81lWlmFAMPzfuUU9jSdPALS95I"},
+				{pbkdf2SHA256Password, "$pbkdf2-sha256$i=1000$c2FsdHNhbHRzYWx0c2FsdA$8nX7hwFEzIB8aPajJTYK8weHQc5Ngz0pFVAKvSu4jQA"},
+				{pbkdf2SHA512Password, "$pbkdf2-sha512$i=1000$c2FsdHNhbHRzYWx0c2FsdA$715rqIr5dXOVPpBhqqsugl037zT5bWJTWYmZtIcK8hA"},
+			}
+			for _, c := range cases {
+				So(c.format.Compare([]byte("password"), []byte(c.hash)), ShouldBeNil)
+				So(c.format.Compare([]byte("Password"), []byte(c.hash)), ShouldBeError)
+				So(Compare([]byte("password"), []byte(c.hash)), ShouldBeNil)
+			}
+		})
+		Convey("should validate hash", func() {
+			key := "8nX7hwFEzIB8aPajJTYK8weHQc5Ngz0pFVAKvSu4jQA"
+			So(pbkdf2SHA256Password.Validate([]byte("$pbkdf2-sha256$i=1000$c2FsdA$"+key)), ShouldBeNil)
+			So(pbkdf2SHA256Password.Validate([]byte("$pbkdf2-sha256$i=0$c2FsdA$"+key)), ShouldBeError)
+			So(pbkdf2SHA256Password.Validate([]byte("$pbkdf2-sha256$i=10000001$c2FsdA$"+key)), ShouldBeError)
+			So(pbkdf2SHA256Password.Validate([]byte("$pbkdf2-sha256$c2FsdA$"+key)), ShouldBeError)
+			So(pbkdf2SHA256Password.Validate([]byte("$pbkdf2-sha256$i=1000$c2FsdA$8nX7hw")), ShouldBeError)
+		})
+		Convey("should not hash", func() {
+			_, err := pbkdf2SHA256Password.Hash([]byte("password"))
+			So(err, ShouldBeError)
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package password
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/authgear/authgear-server/pkg/util/rand"
+)
+
+const (
+	scryptSaltLength = 16
+	scryptKeyLength  = 32
+
+	// The cost parameters of a hash are bounded by the maxima of the configuration,
+	// so that a crafted hash cannot exhaust the resources.
+	scryptMaxLogN        = 20
+	scryptMaxBlockSize   = 32
+	scryptMaxParallelism = 16
+)
+
+// scryptPassword encodes the hash in the PHC string format, i.e.
+// $scrypt$ln=<log2 of N>,r=<block size>,p=<parallelism>$<salt>$<key>
+type scryptPassword struct {
+	// LogN is the base 2 logarithm of the CPU/memory cost parameter N.
+	LogN        int
+	BlockSize   int
+	Parallelism int
+}
+
+var _ costFormat = scryptPassword{}
+
+func (p scryptPassword) ID() string {
+	return "scrypt"
+}
+
+func (p scryptPassword) Hash(password []byte) ([]byte, error) {
+	salt := make([]byte, scryptSaltLength)
+	if _, err := rand.SecureRand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(password, salt, 1<<p.LogN, p.BlockSize, p.Parallelism, scryptKeyLength)
+	if err != nil {
+		return nil, err
+	}
+	data := fmt.Sprintf("ln=%d,r=%d,p=%d$%s$%s",
+		p.LogN,
+		p.BlockSize,
+		p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+	return constructPasswordFormat([]byte(p.ID()), []byte(data)), nil
+}
+
+func (p scryptPassword) Compare(password, hash []byte) error {
+	params, salt, key, err := p.parse(hash)
+	if err != nil {
+		return err
+	}
+	actual, err := scrypt.Key(password, salt, 1<<params.LogN, params.BlockSize, params.Parallelism, len(key))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return errMismatchedHashAndPassword
+	}
+	return nil
+}
+
+func (p scryptPassword) NeedsRehash(hash []byte) bool {
+	params, _, _, err := p.parse(hash)
+	if err != nil {
+		return true
+	}
+	return params.LogN < p.LogN ||
+		params.BlockSize < p.BlockSize ||
+		params.Parallelism < p.Parallelism
+}
+
+func (p scryptPassword) Validate(hash []byte) error {
+	_, _, _, err := p.parse(hash)
+	return err
+}
+
+func (p scryptPassword) parse(hash []byte) (params scryptPassword, salt []byte, key []byte, err error) {
+	_, data, err := parsePasswordFormat(hash)
+	if err != nil {
+		return
+	}
+
+	parts := splitPasswordFormatData(data)
+	if len(parts) != 3 {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if _, err = fmt.Sscanf(parts[0], "ln=%d,r=%d,p=%d", &params.LogN, &params.BlockSize, &params.Parallelism); err != nil {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if params.LogN < 1 || params.LogN > scryptMaxLogN ||
+		params.BlockSize < 1 || params.BlockSize > scryptMaxBlockSize ||
+		params.Parallelism < 1 || params.Parallelism > scryptMaxParallelism {
+		err = errInvalidPasswordFormat
+		return
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+		err = errInvalidPasswordFormat
+		return
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		err = errInvalidPasswordFormat
+		return
+	}
+	err = checkSaltAndKey(salt, key)
+	return
+}
@@ -0,0 +1,55 @@
+package password
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrypt(t *testing.T) {
+	Convey("scrypt", t, func() {
+		scrypt := scryptPassword{LogN: 10, BlockSize: 8, Parallelism: 1}
+		Convey("should hash as expected", func() {
+			h, err := scrypt.Hash([]byte("password"))
+			So(err, ShouldBeNil)
+			So(string(h), ShouldStartWith, "$scrypt$ln=10,r=8,p=1$")
+			So(scrypt.Compare([]byte("password"), h), ShouldBeNil)
+		})
+		Convey("should compare as expected", func() {
+			h := []byte("$scrypt$ln=10,r=8,p=1$chkihucgP2T31mvG0q23KA$hKWIK/ROAgh4LCvsckXTqNueNvp+Ki+oOKrGk+WTtTs")
+			So(scrypt.Compare([]byte("password"), h), ShouldBeNil)
+			So(scrypt.Compare([]byte("Password"), h), ShouldBeError)
+
+			// Parameters are read from the hash.
+			So(scryptPassword{}.Compare([]byte("password"), h), ShouldBeNil)
+
+			So(scrypt.Compare([]byte("password"), []byte("$scrypt$ln=99,r=8,p=1$chkihucgP2T31mvG0q23KA$hKWIK")), ShouldBeError)
+		})
+		Convey("should reject malformed hash", func() {
+			salt := "chkihucgP2T31mvG0q23KA"
+			key := "hKWIK/ROAgh4LCvsckXTqNueNvp+Ki+oOKrGk+WTtTs"
+			for _, h := range []string{
+				// Empty or truncated key would match any password.
+				"$scrypt$ln=15,r=8,p=1$c2FsdA$",
+				"$scrypt$ln=10,r=8,p=1$" + salt + "$hKWIK",
+				"$scrypt$ln=10,r=8,p=1$$" + key,
+				// Cost parameters are bounded.
+				"$scrypt$ln=0,r=8,p=1$" + salt + "$" + key,
+				"$scrypt$ln=21,r=8,p=1$" + salt + "$" + key,
+				"$scrypt$ln=10,r=0,p=1$" + salt + "$" + key,
+				"$scrypt$ln=10,r=33,p=1$" + salt + "$" + key,
+				"$scrypt$ln=10,r=8,p=0$" + salt + "$" + key,
+				"$scrypt$ln=10,r=8,p=17$" + salt + "$" + key,
+			} {
+				So(scrypt.Validate([]byte(h)), ShouldBeError)
+				So(scrypt.Compare([]byte("password"), []byte(h)), ShouldBeError)
+			}
+		})
+		Convey("should tell if rehash is needed", func() {
+			h := []byte("$scrypt$ln=10,r=8,p=1$chkihucgP2T31mvG0q23KA$hKWIK/ROAgh4LCvsckXTqNueNvp+Ki+oOKrGk+WTtTs")
+			So(scrypt.NeedsRehash(h), ShouldBeFalse)
+			So(scryptPassword{LogN: 11, BlockSize: 8, Parallelism: 1}.NeedsRehash(h), ShouldBeTrue)
+			So(scryptPassword{LogN: 10, BlockSize: 8, Parallelism: 2}.NeedsRehash(h), ShouldBeTrue)
+		})
+	})
+}
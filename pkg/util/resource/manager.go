@@ -1,6 +1,9 @@
 package resource
 
 import (
+	"errors"
+	"sync"
+
 	"github.com/spf13/afero"
 
 	"github.com/authgear/authgear-server/pkg/api/apierrors"
@@ -12,6 +15,15 @@ var ErrResourceNotFound = apierrors.NotFound.WithReason("ResourceNotFound").
 type Manager struct {
 	Registry *Registry
 	Fs       []Fs
+
+	cache sync.Map
+}
+
+type cachedResource struct {
+	mutex  sync.Mutex
+	loaded bool
+	value  interface{}
+	err    error
 }
 
 func NewManager(registry *Registry, fs []Fs) *Manager {
@@ -75,6 +87,29 @@ func (m *Manager) Read(desc Descriptor, view View) (interface{}, error) {
 	return desc.ViewResources(files, view)
 }
 
+// ReadEffectiveResourceCached is Read with EffectiveResource view,
+// except that the result is cached in the manager.
+// It is intended for resources that are expensive to parse and do not depend on language tags.
+// A new manager is created whenever the resources of an app change,
+// so the cached result is discarded with the version of the resources it was read from.
+// desc must be comparable, e.g. a pointer.
+func (m *Manager) ReadEffectiveResourceCached(desc Descriptor) (interface{}, error) {
+	entry, _ := m.cache.LoadOrStore(desc, &cachedResource{})
+	c := entry.(*cachedResource)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.loaded {
+		value, err := m.Read(desc, EffectiveResource{})
+		if err != nil && !errors.Is(err, ErrResourceNotFound) {
+			// Do not cache transient errors.
+			return nil, err
+		}
+		c.loaded, c.value, c.err = true, value, err
+	}
+	return c.value, c.err
+}
+
 func (m *Manager) Resolve(path string) (Descriptor, bool) {
 	for _, desc := range m.Registry.Descriptors {
 		if _, ok := desc.MatchResource(path); ok {
@@ -65,5 +65,41 @@ func TestResourceManager(t *testing.T) {
 			So(ok, ShouldBeTrue)
 			So(desc, ShouldResemble, resourceC)
 		})
+
+		Convey("it should cache effective resource in the manager", func() {
+			parsed := 0
+			resourceD := r.Register(&resource.NewlineJoinedDescriptor{
+				Path: "resourceD.txt",
+				Parse: func(data []byte) (interface{}, error) {
+					parsed++
+					return string(data), nil
+				},
+			})
+
+			_, err := manager.ReadEffectiveResourceCached(resourceD)
+			So(err, ShouldBeError, "specified resource is not configured")
+
+			// Absence of the resource is cached too.
+			_ = afero.WriteFile(fsB, "resourceD.txt", []byte("version 1"), 0666)
+			_, err = manager.ReadEffectiveResourceCached(resourceD)
+			So(err, ShouldBeError, "specified resource is not configured")
+
+			manager = resource.NewManager(r, manager.Fs)
+			data, err := manager.ReadEffectiveResourceCached(resourceD)
+			So(err, ShouldBeNil)
+			So(data, ShouldEqual, "version 1\n")
+
+			_ = afero.WriteFile(fsB, "resourceD.txt", []byte("version 2"), 0666)
+			data, err = manager.ReadEffectiveResourceCached(resourceD)
+			So(err, ShouldBeNil)
+			So(data, ShouldEqual, "version 1\n")
+			So(parsed, ShouldEqual, 1)
+
+			manager = resource.NewManager(r, manager.Fs)
+			data, err = manager.ReadEffectiveResourceCached(resourceD)
+			So(err, ShouldBeNil)
+			So(data, ShouldEqual, "version 2\n")
+			So(parsed, ShouldEqual, 2)
+		})
 	})
 }
@@ -1,7 +1,11 @@
 package secrets
 
 import (
+	cryptorand "crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
 	mathrand "math/rand"
 	"time"
 
@@ -53,3 +57,22 @@ func GenerateRSAKey(createdAt time.Time, rng *mathrand.Rand) jwk.Key {
 
 	return jwkKey
 }
+
+// SelfSignCertificate creates a self-signed certificate of the RSA key.
+// The certificate is derived from the key deterministically, so that it is stable
+// across processes loading the same key.
+func SelfSignCertificate(key *rsa.PrivateKey, commonName string) (*x509.Certificate, error) {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	// RSA PKCS #1 v1.5 signatures are deterministic, so the certificate is too.
+	der, err := x509.CreateCertificate(cryptorand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
@@ -0,0 +1,106 @@
+package webauthn
+
+import (
+	"crypto/rand"
+)
+
+// User verification requirements.
+const (
+	UserVerificationRequired    = "required"
+	UserVerificationPreferred   = "preferred"
+	UserVerificationDiscouraged = "discouraged"
+)
+
+// The following types are the JSON form of the options passed to
+// navigator.credentials.create() and navigator.credentials.get().
+// Binary fields are encoded in base64url and are to be decoded by the client.
+
+type RPEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type CredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey,omitempty"`
+	UserVerification string `json:"userVerification,omitempty"`
+}
+
+type CreationOptions struct {
+	RP                     RPEntity               `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	Challenge              string                 `json:"challenge"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int                    `json:"timeout,omitempty"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int                    `json:"timeout,omitempty"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                 `json:"userVerification,omitempty"`
+}
+
+// NewChallenge generates a random challenge, encoded in base64url.
+// The encoded form is what appears in the client data.
+func NewChallenge() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return EncodeBase64URL(b)
+}
+
+// CreationOptions returns the options of the registration ceremony.
+// excludeCredentialIDs prevents registering the same authenticator twice.
+func (rp *RelyingParty) CreationOptions(challenge string, user UserEntity, excludeCredentialIDs [][]byte, userVerification string) *CreationOptions {
+	o := &CreationOptions{
+		RP:        RPEntity{ID: rp.ID, Name: rp.Name},
+		User:      user,
+		Challenge: challenge,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: userVerification,
+		},
+		Attestation: AttestationFormatNone,
+	}
+	for _, alg := range SupportedAlgorithms {
+		o.PubKeyCredParams = append(o.PubKeyCredParams, CredentialParameter{Type: "public-key", Alg: alg})
+	}
+	for _, id := range excludeCredentialIDs {
+		o.ExcludeCredentials = append(o.ExcludeCredentials, CredentialDescriptor{Type: "public-key", ID: EncodeBase64URL(id)})
+	}
+	return o
+}
+
+// RequestOptions returns the options of the authentication ceremony.
+func (rp *RelyingParty) RequestOptions(challenge string, allowCredentialIDs [][]byte, userVerification string) *RequestOptions {
+	o := &RequestOptions{
+		Challenge:        challenge,
+		RPID:             rp.ID,
+		UserVerification: userVerification,
+	}
+	for _, id := range allowCredentialIDs {
+		o.AllowCredentials = append(o.AllowCredentials, CredentialDescriptor{Type: "public-key", ID: EncodeBase64URL(id)})
+	}
+	return o
+}
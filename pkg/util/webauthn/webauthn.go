@@ -0,0 +1,260 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+const (
+	ClientDataTypeCreate = "webauthn.create"
+	ClientDataTypeGet    = "webauthn.get"
+)
+
+// AttestationFormatNone is the attestation conveyance preference of the registration ceremony.
+// Attestation statements of the formats defined in the spec are verified if they are present anyway.
+// See https://www.w3.org/TR/webauthn-2/#sctn-defined-attestation-formats
+const AttestationFormatNone = "none"
+
+// COSE algorithm identifiers.
+// See https://www.iana.org/assignments/cose/cose.xhtml#algorithms
+const (
+	AlgorithmES256 int64 = -7
+	AlgorithmEdDSA int64 = -8
+	AlgorithmRS256 int64 = -257
+)
+
+// SupportedAlgorithms are the COSE algorithms accepted for credential public keys,
+// in the order of preference.
+var SupportedAlgorithms = []int64{
+	AlgorithmES256,
+	AlgorithmEdDSA,
+	AlgorithmRS256,
+}
+
+var ErrInvalidResponse = errors.New("webauthn: invalid response")
+
+// ErrSignCountNotIncreased means the signature counter did not increase.
+// It is a signal that the authenticator may have been cloned.
+var ErrSignCountNotIncreased = errors.New("webauthn: sign count is not increased")
+
+func errInvalidResponse(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidResponse, fmt.Sprintf(format, args...))
+}
+
+// errVerification converts the error of go-webauthn, which carries the reason in its details.
+func errVerification(err error) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) {
+		return errInvalidResponse("%s: %s", protocolErr.Details, protocolErr.DevInfo)
+	}
+	return errInvalidResponse("%v", err)
+}
+
+// RelyingParty verifies WebAuthn ceremonies.
+type RelyingParty struct {
+	// ID is the RP ID, which is usually the host of the origin.
+	ID string
+	// Name is the human-palatable name of the RP.
+	Name string
+	// Origins are the allowed origins of the client.
+	Origins []string
+}
+
+type ClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin,omitempty"`
+}
+
+// Credential is a registered public key credential.
+type Credential struct {
+	ID []byte
+	// PublicKey is in COSE_Key format.
+	PublicKey []byte
+	SignCount uint32
+	AAGUID    []byte
+}
+
+// AttestationResponse is the result of navigator.credentials.create().
+type AttestationResponse struct {
+	CredentialID []byte
+	ClientData   ClientData
+
+	parsed *protocol.ParsedCredentialCreationData
+}
+
+// AssertionResponse is the result of navigator.credentials.get().
+type AssertionResponse struct {
+	CredentialID []byte
+	ClientData   ClientData
+
+	parsed *protocol.ParsedCredentialAssertionData
+}
+
+// ParseAttestationResponse parses the JSON form of the result of navigator.credentials.create(),
+// with binary fields encoded in base64url.
+func ParseAttestationResponse(b []byte) (*AttestationResponse, error) {
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(b))
+	if err != nil {
+		return nil, errVerification(err)
+	}
+
+	r := &AttestationResponse{
+		CredentialID: parsed.RawID,
+		parsed:       parsed,
+	}
+	if len(r.CredentialID) == 0 {
+		return nil, errInvalidResponse("malformed rawId")
+	}
+	// go-webauthn does not keep crossOrigin in the parsed client data.
+	if err := json.Unmarshal(parsed.Raw.AttestationResponse.ClientDataJSON, &r.ClientData); err != nil {
+		return nil, errInvalidResponse("malformed client data: %v", err)
+	}
+	return r, nil
+}
+
+// ParseAssertionResponse parses the JSON form of the result of navigator.credentials.get(),
+// with binary fields encoded in base64url.
+func ParseAssertionResponse(b []byte) (*AssertionResponse, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(b))
+	if err != nil {
+		return nil, errVerification(err)
+	}
+
+	r := &AssertionResponse{
+		CredentialID: parsed.RawID,
+		parsed:       parsed,
+	}
+	if len(r.CredentialID) == 0 {
+		return nil, errInvalidResponse("malformed rawId")
+	}
+	if err := json.Unmarshal(parsed.Raw.AssertionResponse.ClientDataJSON, &r.ClientData); err != nil {
+		return nil, errInvalidResponse("malformed client data: %v", err)
+	}
+	return r, nil
+}
+
+// VerifyRegistration verifies the registration ceremony.
+// See https://www.w3.org/TR/webauthn-2/#sctn-registering-a-new-credential
+func (rp *RelyingParty) VerifyRegistration(r *AttestationResponse, challenge string, requireUserVerification bool) (*Credential, error) {
+	origin, err := rp.checkClientData(r.ClientData, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.parsed.Verify(challenge, requireUserVerification, rp.ID, origin)
+	if err != nil {
+		return nil, errVerification(err)
+	}
+
+	authData := r.parsed.Response.AttestationObject.AuthData
+	if subtle.ConstantTimeCompare(authData.AttData.CredentialID, r.CredentialID) != 1 {
+		return nil, errInvalidResponse("credential ID mismatch")
+	}
+	if err := checkPublicKey(authData.AttData.CredentialPublicKey); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:        authData.AttData.CredentialID,
+		PublicKey: authData.AttData.CredentialPublicKey,
+		SignCount: authData.Counter,
+		AAGUID:    authData.AttData.AAGUID,
+	}, nil
+}
+
+// VerifyAssertion verifies the authentication ceremony against credential.
+// The new sign count is returned.
+// ErrSignCountNotIncreased is returned if the signature counter indicates the authenticator may have been cloned.
+// See https://www.w3.org/TR/webauthn-2/#sctn-verifying-assertion
+func (rp *RelyingParty) VerifyAssertion(r *AssertionResponse, challenge string, credential *Credential, requireUserVerification bool) (uint32, error) {
+	if subtle.ConstantTimeCompare(r.CredentialID, credential.ID) != 1 {
+		return 0, errInvalidResponse("credential ID mismatch")
+	}
+
+	origin, err := rp.checkClientData(r.ClientData, challenge)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.parsed.Verify(challenge, rp.ID, origin, "", requireUserVerification, credential.PublicKey)
+	if err != nil {
+		return 0, errVerification(err)
+	}
+
+	// Authenticators not supporting signature counter always return 0.
+	signCount := r.parsed.Response.AuthenticatorData.Counter
+	if signCount != 0 || credential.SignCount != 0 {
+		if signCount <= credential.SignCount {
+			return 0, ErrSignCountNotIncreased
+		}
+	}
+
+	return signCount, nil
+}
+
+// checkClientData checks what go-webauthn does not, and returns the origin of the client.
+// go-webauthn verifies the client data against a single origin.
+func (rp *RelyingParty) checkClientData(c ClientData, challenge string) (string, error) {
+	// An empty challenge would match the client data without challenge.
+	if challenge == "" {
+		return "", errInvalidResponse("challenge mismatch")
+	}
+	if c.CrossOrigin {
+		return "", errInvalidResponse("cross origin is not allowed")
+	}
+	for _, origin := range rp.Origins {
+		if c.Origin == origin {
+			return origin, nil
+		}
+	}
+	return "", errInvalidResponse("unexpected origin: %s", c.Origin)
+}
+
+// checkPublicKey rejects keys of algorithms not offered in the creation options.
+// The key is otherwise parsed only when it is used in an assertion.
+func checkPublicKey(coseKey []byte) error {
+	key, err := webauthncose.ParsePublicKey(coseKey)
+	if err != nil {
+		return errVerification(err)
+	}
+
+	var alg int64
+	switch key := key.(type) {
+	case webauthncose.EC2PublicKeyData:
+		alg = key.Algorithm
+	case webauthncose.OKPPublicKeyData:
+		alg = key.Algorithm
+	case webauthncose.RSAPublicKeyData:
+		alg = key.Algorithm
+	default:
+		return errInvalidResponse("unsupported public key")
+	}
+
+	for _, supported := range SupportedAlgorithms {
+		if alg == supported {
+			return nil
+		}
+	}
+	return errInvalidResponse("unsupported algorithm: %d", alg)
+}
+
+func DecodeBase64URL(s string) ([]byte, error) {
+	// Browsers produce unpadded base64url, but tolerate padding.
+	for len(s) > 0 && s[len(s)-1] == '=' {
+		s = s[:len(s)-1]
+	}
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func EncodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,423 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const attestationFormatPacked = "packed"
+
+// Flags of authenticator data.
+const (
+	flagUserPresent            byte = 0x01
+	flagUserVerified           byte = 0x04
+	flagAttestedCredentialData byte = 0x40
+)
+
+// oidFIDOGenCEAAGUID is id-fido-gen-ce-aaguid.
+var oidFIDOGenCEAAGUID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}
+
+func encodeCBOR(v interface{}) []byte {
+	b, err := webauthncbor.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// softwareAuthenticator is an authenticator backed by an in-memory P-256 key.
+type softwareAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	aaguid       []byte
+	signCount    uint32
+	flags        byte
+}
+
+func newSoftwareAuthenticator() *softwareAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	credentialID := make([]byte, 16)
+	_, _ = rand.Read(credentialID)
+	return &softwareAuthenticator{
+		key:          key,
+		credentialID: credentialID,
+		aaguid:       make([]byte, 16),
+		flags:        flagUserPresent | flagUserVerified,
+	}
+}
+
+func (a *softwareAuthenticator) coseKey() []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+	return encodeCBOR(map[interface{}]interface{}{
+		// kty: EC2, alg: ES256, crv: P-256
+		int64(1):  int64(2),
+		int64(3):  AlgorithmES256,
+		int64(-1): int64(1),
+		int64(-2): x,
+		int64(-3): y,
+	})
+}
+
+func (a *softwareAuthenticator) authData(rpID string, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	flags := a.flags
+	if attested {
+		flags |= flagAttestedCredentialData
+	}
+	b := append([]byte(nil), rpIDHash[:]...)
+	b = append(b, flags)
+	counter := make([]byte, 4)
+	binary.BigEndian.PutUint32(counter, a.signCount)
+	b = append(b, counter...)
+	if attested {
+		b = append(b, a.aaguid...)
+		idLength := make([]byte, 2)
+		binary.BigEndian.PutUint16(idLength, uint16(len(a.credentialID)))
+		b = append(b, idLength...)
+		b = append(b, a.credentialID...)
+		b = append(b, a.coseKey()...)
+	}
+	return b
+}
+
+func (a *softwareAuthenticator) sign(key *ecdsa.PrivateKey, data []byte) []byte {
+	digest := sha256.Sum256(data)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return sig
+}
+
+func clientDataJSON(typ string, challenge string, origin string) []byte {
+	b, err := json.Marshal(ClientData{Type: typ, Challenge: challenge, Origin: origin})
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// create performs the registration ceremony.
+// attStmt is built by makeAttStmt, which receives the data to be signed.
+func (a *softwareAuthenticator) create(rpID string, clientData []byte, format string, makeAttStmt func(signed []byte) map[interface{}]interface{}) *AttestationResponse {
+	authData := a.authData(rpID, true)
+	clientDataHash := sha256.Sum256(clientData)
+	signed := append(append([]byte(nil), authData...), clientDataHash[:]...)
+
+	attStmt := map[interface{}]interface{}{}
+	if makeAttStmt != nil {
+		attStmt = makeAttStmt(signed)
+	}
+	attestationObject := encodeCBOR(map[interface{}]interface{}{
+		"fmt":      format,
+		"attStmt":  attStmt,
+		"authData": authData,
+	})
+
+	b, err := json.Marshal(map[string]interface{}{
+		"id":    EncodeBase64URL(a.credentialID),
+		"rawId": EncodeBase64URL(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    EncodeBase64URL(clientData),
+			"attestationObject": EncodeBase64URL(attestationObject),
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	parsed, err := ParseAttestationResponse(b)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// get performs the authentication ceremony.
+func (a *softwareAuthenticator) get(rpID string, clientData []byte) *AssertionResponse {
+	a.signCount++
+	authData := a.authData(rpID, false)
+	clientDataHash := sha256.Sum256(clientData)
+	signed := append(append([]byte(nil), authData...), clientDataHash[:]...)
+
+	b, err := json.Marshal(map[string]interface{}{
+		"id":    EncodeBase64URL(a.credentialID),
+		"rawId": EncodeBase64URL(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    EncodeBase64URL(clientData),
+			"authenticatorData": EncodeBase64URL(authData),
+			"signature":         EncodeBase64URL(a.sign(a.key, signed)),
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	parsed, err := ParseAssertionResponse(b)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func newAttestationCertificate(aaguid []byte) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	aaguidExt, err := asn1.Marshal(aaguid)
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			Country:            []string{"US"},
+			Organization:       []string{"Example"},
+			CommonName:         "Software Authenticator",
+			OrganizationalUnit: []string{"Authenticator Attestation"},
+		},
+		NotBefore:             time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		NotAfter:              time.Date(2106, 1, 2, 15, 4, 5, 0, time.UTC),
+		BasicConstraintsValid: true,
+		ExtraExtensions: []pkix.Extension{
+			{Id: oidFIDOGenCEAAGUID, Value: aaguidExt},
+		},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+	return key, der
+}
+
+func TestRelyingParty(t *testing.T) {
+	rp := &RelyingParty{
+		ID:      "auth.example.com",
+		Name:    "Example",
+		Origins: []string{"https://auth.example.com"},
+	}
+	const origin = "https://auth.example.com"
+
+	Convey("VerifyRegistration", t, func() {
+		a := newSoftwareAuthenticator()
+		challenge := NewChallenge()
+
+		Convey("should accept none attestation", func() {
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, origin), AttestationFormatNone, nil)
+			cred, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldBeNil)
+			So(cred.ID, ShouldResemble, a.credentialID)
+			So(cred.PublicKey, ShouldResemble, a.coseKey())
+			So(cred.SignCount, ShouldEqual, 0)
+		})
+
+		Convey("should accept packed self attestation", func() {
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, origin), attestationFormatPacked, func(signed []byte) map[interface{}]interface{} {
+				return map[interface{}]interface{}{
+					"alg": AlgorithmES256,
+					"sig": a.sign(a.key, signed),
+				}
+			})
+			_, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should reject packed self attestation signed by another key", func() {
+			other := newSoftwareAuthenticator()
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, origin), attestationFormatPacked, func(signed []byte) map[interface{}]interface{} {
+				return map[interface{}]interface{}{
+					"alg": AlgorithmES256,
+					"sig": a.sign(other.key, signed),
+				}
+			})
+			_, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+
+		Convey("should accept packed attestation with certificate", func() {
+			a.aaguid = []byte("0123456789abcdef")
+			key, der := newAttestationCertificate(a.aaguid)
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, origin), attestationFormatPacked, func(signed []byte) map[interface{}]interface{} {
+				return map[interface{}]interface{}{
+					"alg": AlgorithmES256,
+					"sig": a.sign(key, signed),
+					"x5c": []interface{}{der},
+				}
+			})
+			cred, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldBeNil)
+			So(cred.AAGUID, ShouldResemble, a.aaguid)
+		})
+
+		Convey("should reject packed attestation with mismatched AAGUID", func() {
+			key, der := newAttestationCertificate([]byte("fedcba9876543210"))
+			a.aaguid = []byte("0123456789abcdef")
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, origin), attestationFormatPacked, func(signed []byte) map[interface{}]interface{} {
+				return map[interface{}]interface{}{
+					"alg": AlgorithmES256,
+					"sig": a.sign(key, signed),
+					"x5c": []interface{}{der},
+				}
+			})
+			_, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+
+		Convey("should accept fido-u2f attestation", func() {
+			key, der := newAttestationCertificate(a.aaguid)
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, origin), "fido-u2f", func(signed []byte) map[interface{}]interface{} {
+				rpIDHash := sha256.Sum256([]byte(rp.ID))
+				clientDataHash := signed[len(signed)-sha256.Size:]
+				data := []byte{0x00}
+				data = append(data, rpIDHash[:]...)
+				data = append(data, clientDataHash...)
+				data = append(data, a.credentialID...)
+				data = append(data, elliptic.Marshal(elliptic.P256(), a.key.X, a.key.Y)...)
+				return map[interface{}]interface{}{
+					"sig": a.sign(key, data),
+					"x5c": []interface{}{der},
+				}
+			})
+			cred, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldBeNil)
+			So(cred.ID, ShouldResemble, a.credentialID)
+		})
+
+		Convey("should reject unsupported attestation format", func() {
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, origin), "unknown", nil)
+			_, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+
+		Convey("should reject mismatched challenge", func() {
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, NewChallenge(), origin), AttestationFormatNone, nil)
+			_, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+
+		Convey("should reject unexpected origin", func() {
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, "https://evil.example.com"), AttestationFormatNone, nil)
+			_, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+
+		Convey("should reject unexpected client data type", func() {
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeGet, challenge, origin), AttestationFormatNone, nil)
+			_, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+
+		Convey("should reject mismatched RP ID", func() {
+			r := a.create("evil.example.com", clientDataJSON(ClientDataTypeCreate, challenge, origin), AttestationFormatNone, nil)
+			_, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+
+		Convey("should require user verification if requested", func() {
+			a.flags = flagUserPresent
+			r := a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, origin), AttestationFormatNone, nil)
+			_, err := rp.VerifyRegistration(r, challenge, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+
+			_, err = rp.VerifyRegistration(r, challenge, false)
+			So(err, ShouldBeNil)
+		})
+	})
+
+	Convey("VerifyAssertion", t, func() {
+		a := newSoftwareAuthenticator()
+		challenge := NewChallenge()
+		cred, err := rp.VerifyRegistration(
+			a.create(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, origin), AttestationFormatNone, nil),
+			challenge,
+			true,
+		)
+		So(err, ShouldBeNil)
+
+		Convey("should verify assertion and increase sign count", func() {
+			challenge := NewChallenge()
+			r := a.get(rp.ID, clientDataJSON(ClientDataTypeGet, challenge, origin))
+			signCount, err := rp.VerifyAssertion(r, challenge, cred, true)
+			So(err, ShouldBeNil)
+			So(signCount, ShouldEqual, 1)
+
+			cred.SignCount = signCount
+			challenge = NewChallenge()
+			r = a.get(rp.ID, clientDataJSON(ClientDataTypeGet, challenge, origin))
+			signCount, err = rp.VerifyAssertion(r, challenge, cred, true)
+			So(err, ShouldBeNil)
+			So(signCount, ShouldEqual, 2)
+		})
+
+		Convey("should reject sign count not increased", func() {
+			cred.SignCount = 5
+			a.signCount = 4
+			challenge := NewChallenge()
+			r := a.get(rp.ID, clientDataJSON(ClientDataTypeGet, challenge, origin))
+			_, err := rp.VerifyAssertion(r, challenge, cred, true)
+			So(err, ShouldEqual, ErrSignCountNotIncreased)
+		})
+
+		Convey("should accept authenticator without sign counter", func() {
+			// The counter is incremented before signing.
+			a.signCount = ^uint32(0)
+			challenge := NewChallenge()
+			r := a.get(rp.ID, clientDataJSON(ClientDataTypeGet, challenge, origin))
+			signCount, err := rp.VerifyAssertion(r, challenge, cred, true)
+			So(err, ShouldBeNil)
+			So(signCount, ShouldEqual, 0)
+		})
+
+		Convey("should reject assertion of another credential", func() {
+			other := newSoftwareAuthenticator()
+			challenge := NewChallenge()
+			r := other.get(rp.ID, clientDataJSON(ClientDataTypeGet, challenge, origin))
+			_, err := rp.VerifyAssertion(r, challenge, cred, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+
+		Convey("should reject assertion signed by another key", func() {
+			other := newSoftwareAuthenticator()
+			other.credentialID = a.credentialID
+			challenge := NewChallenge()
+			r := other.get(rp.ID, clientDataJSON(ClientDataTypeGet, challenge, origin))
+			_, err := rp.VerifyAssertion(r, challenge, cred, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+
+		Convey("should reject mismatched challenge", func() {
+			r := a.get(rp.ID, clientDataJSON(ClientDataTypeGet, NewChallenge(), origin))
+			_, err := rp.VerifyAssertion(r, challenge, cred, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+
+		Convey("should reject registration response type", func() {
+			challenge := NewChallenge()
+			r := a.get(rp.ID, clientDataJSON(ClientDataTypeCreate, challenge, origin))
+			_, err := rp.VerifyAssertion(r, challenge, cred, true)
+			So(err, ShouldWrap, ErrInvalidResponse)
+		})
+	})
+}
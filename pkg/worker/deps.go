@@ -4,8 +4,13 @@ import (
 	"github.com/google/wire"
 
 	"github.com/authgear/authgear-server/pkg/lib/deps"
+	libes "github.com/authgear/authgear-server/pkg/lib/elasticsearch"
+	"github.com/authgear/authgear-server/pkg/lib/event"
+	"github.com/authgear/authgear-server/pkg/lib/eventstream"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
 	"github.com/authgear/authgear-server/pkg/lib/infra/mail"
 	"github.com/authgear/authgear-server/pkg/lib/infra/sms"
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
 	"github.com/authgear/authgear-server/pkg/worker/tasks"
 )
 
@@ -19,4 +24,10 @@ var DependencySet = wire.NewSet(
 	tasks.DependencySet,
 	wire.Bind(new(tasks.MailSender), new(*mail.Sender)),
 	wire.Bind(new(tasks.SMSClient), new(*sms.Client)),
+	wire.Bind(new(tasks.WebhookDeliveryService), new(*hook.DeliveryService)),
+	wire.Bind(new(tasks.EventStreamPublisher), new(*eventstream.Publisher)),
+	wire.Bind(new(tasks.EventOutboxRelay), new(*event.Relay)),
+	wire.Bind(new(tasks.WebhookDeliveryResumer), new(*hook.DeliveryService)),
+	wire.Bind(new(tasks.UserImportRunner), new(*userimport.Runner)),
+	wire.Bind(new(userimport.SearchService), new(*libes.Service)),
 )
@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/authgear/authgear-server/pkg/lib/config"
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+	"github.com/authgear/authgear-server/pkg/util/clock"
+	"github.com/authgear/authgear-server/pkg/util/log"
+)
+
+// ScheduleInterval is the interval between two runs of the periodic tasks.
+const ScheduleInterval = 1 * time.Minute
+
+type SchedulerApps interface {
+	ResolveContext(appID string) (*config.AppContext, error)
+}
+
+type SchedulerDatabase interface {
+	ReadOnly(do func() error) error
+}
+
+type SchedulerLogger struct{ *log.Logger }
+
+func NewSchedulerLogger(lf *log.Factory) SchedulerLogger {
+	return SchedulerLogger{lf.New("worker-scheduler")}
+}
+
+// Scheduler runs the periodic tasks of the apps with due event deliveries.
+//
+// The retries of event deliveries are persisted in the app database,
+// and are resumed by the periodic tasks after they are due,
+// so they are not lost when the server restarts.
+//
+// Only the worker holding the scheduler lock runs the periodic tasks,
+// the others take over when the lock is released.
+type Scheduler struct {
+	Logger   SchedulerLogger
+	Clock    clock.Clock
+	Database SchedulerDatabase
+	Store    *SchedulerStore
+	Apps     SchedulerApps
+	Worker   *Worker
+}
+
+// Run runs the periodic tasks every ScheduleInterval until done is closed.
+func (s *Scheduler) Run(done <-chan struct{}) {
+	defer func() {
+		if err := s.Store.Unlock(); err != nil {
+			s.Logger.WithError(err).Error("failed to release scheduler lock")
+		}
+	}()
+
+	ticker := time.NewTicker(ScheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		s.schedule()
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) schedule() {
+	locked, err := s.Store.TryLock()
+	if err != nil {
+		s.Logger.WithError(err).Error("failed to acquire scheduler lock")
+		return
+	}
+	if !locked {
+		// Another worker runs the periodic tasks.
+		return
+	}
+
+	var appIDs []string
+	err = s.Database.ReadOnly(func() (err error) {
+		appIDs, err = s.Store.ListDueAppIDs(s.Clock.NowUTC())
+		return
+	})
+	if err != nil {
+		s.Logger.WithError(err).Error("failed to list apps with due event deliveries")
+		return
+	}
+
+	for _, appID := range appIDs {
+		appCtx, err := s.Apps.ResolveContext(appID)
+		if err != nil {
+			s.Logger.WithError(err).WithField("app_id", appID).Error("failed to resolve app")
+			continue
+		}
+
+		s.Worker.Executor.Run(
+			&task.Context{Config: appCtx.Config},
+			&tasks.ResumeEventDeliveriesParam{},
+		)
+	}
+}
@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/authgear/authgear-server/pkg/lib/hook"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/globaldb"
+)
+
+// schedulerLockClass is the class ID of the advisory lock held by the worker running the scheduler.
+const schedulerLockClass = 4
+
+// SchedulerStore queries the event deliveries of all apps.
+// The apps share the database configured by the environment.
+type SchedulerStore struct {
+	Context     context.Context
+	Handle      *globaldb.Handle
+	SQLBuilder  *globaldb.SQLBuilder
+	SQLExecutor *globaldb.SQLExecutor
+
+	lockConn *sql.Conn `wire:"-"`
+}
+
+// TryLock reports whether the scheduler lock is held by this worker, acquiring it if not.
+//
+// The lock is a session-level advisory lock held by a dedicated connection,
+// so it is held until Unlock is called, or the connection is lost, e.g. the worker stops.
+func (s *SchedulerStore) TryLock() (bool, error) {
+	if s.lockConn != nil {
+		if err := s.lockConn.PingContext(s.Context); err == nil {
+			return true, nil
+		}
+		// The lock is released with the lost connection.
+		_ = s.lockConn.Close()
+		s.lockConn = nil
+	}
+
+	database, err := s.Handle.Pool.Open(s.Handle.ConnectionOptions)
+	if err != nil {
+		return false, err
+	}
+	conn, err := database.Conn(s.Context)
+	if err != nil {
+		return false, err
+	}
+
+	var locked bool
+	err = conn.QueryRowContext(s.Context, "SELECT pg_try_advisory_lock($1, 0)", schedulerLockClass).Scan(&locked)
+	if err != nil || !locked {
+		_ = conn.Close()
+		return false, err
+	}
+
+	s.lockConn = conn
+	return true, nil
+}
+
+// Unlock releases the scheduler lock if it is held by this worker.
+func (s *SchedulerStore) Unlock() error {
+	if s.lockConn == nil {
+		return nil
+	}
+	defer func() {
+		// The connection is returned to the pool.
+		_ = s.lockConn.Close()
+		s.lockConn = nil
+	}()
+
+	_, err := s.lockConn.ExecContext(s.Context, "SELECT pg_advisory_unlock($1, 0)", schedulerLockClass)
+	return err
+}
+
+// ListDueAppIDs lists the apps having webhook deliveries, outbox records
+// or event stream records to be attempted at now.
+func (s *SchedulerStore) ListDueAppIDs(now time.Time) ([]string, error) {
+	deliveries := s.SQLBuilder.
+		Select("DISTINCT app_id").
+		From(s.SQLBuilder.TableName("_auth_webhook_delivery")).
+		Where("status = ? AND next_attempt_at <= ?", string(hook.DeliveryStatusPending), now)
+	outbox := s.SQLBuilder.
+		Select("DISTINCT app_id").
+		From(s.SQLBuilder.TableName("_auth_event_outbox")).
+		Where("dead_lettered_at IS NULL")
+	stream := s.SQLBuilder.
+		Select("DISTINCT e.app_id").
+		From(s.SQLBuilder.TableName("_auth_event_stream")+" e").
+		LeftJoin(s.SQLBuilder.TableName("_auth_event_stream_publisher")+" p ON p.app_id = e.app_id").
+		Where("p.next_attempt_at IS NULL OR p.next_attempt_at <= ?", now)
+
+	seen := make(map[string]struct{})
+	var appIDs []string
+	for _, q := range []sq.SelectBuilder{deliveries, outbox, stream} {
+		rows, err := s.SQLExecutor.QueryWith(q)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var appID string
+			if err := rows.Scan(&appID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if _, ok := seen[appID]; !ok {
+				seen[appID] = struct{}{}
+				appIDs = append(appIDs, appID)
+			}
+		}
+		rows.Close()
+	}
+
+	return appIDs, nil
+}
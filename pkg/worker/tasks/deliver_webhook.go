@@ -0,0 +1,28 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+)
+
+func ConfigureDeliverWebhookTask(registry task.Registry, t task.Task) {
+	registry.Register(tasks.DeliverWebhook, t)
+}
+
+type WebhookDeliveryService interface {
+	Attempt(id string) error
+}
+
+type DeliverWebhookTask struct {
+	Deliveries WebhookDeliveryService
+}
+
+func (t *DeliverWebhookTask) Run(ctx context.Context, param task.Param) (err error) {
+	taskParam := param.(*tasks.DeliverWebhookParam)
+
+	// The delivery service manages its own transactions,
+	// so that the request is not made in a transaction.
+	return t.Deliveries.Attempt(taskParam.DeliveryID)
+}
@@ -10,4 +10,14 @@ var DependencySet = wire.NewSet(
 
 	NewReindexUserLogger,
 	wire.Struct(new(ReindexUserTask), "*"),
+
+	wire.Struct(new(DeliverWebhookTask), "*"),
+
+	wire.Struct(new(PublishEventStreamTask), "*"),
+
+	wire.Struct(new(RelayEventOutboxTask), "*"),
+
+	wire.Struct(new(ResumeEventDeliveriesTask), "*"),
+
+	wire.Struct(new(ImportUsersTask), "*"),
 )
@@ -0,0 +1,27 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+)
+
+func ConfigureImportUsersTask(registry task.Registry, t task.Task) {
+	registry.Register(tasks.ImportUsers, t)
+}
+
+type UserImportRunner interface {
+	RunJob(jobID string) error
+}
+
+type ImportUsersTask struct {
+	Runner UserImportRunner
+}
+
+func (t *ImportUsersTask) Run(ctx context.Context, param task.Param) (err error) {
+	taskParam := param.(*tasks.ImportUsersParam)
+
+	// The runner manages its own transactions.
+	return t.Runner.RunJob(taskParam.JobID)
+}
@@ -0,0 +1,26 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+)
+
+func ConfigurePublishEventStreamTask(registry task.Registry, t task.Task) {
+	registry.Register(tasks.PublishEventStream, t)
+}
+
+type EventStreamPublisher interface {
+	Publish() error
+}
+
+type PublishEventStreamTask struct {
+	Publisher EventStreamPublisher
+}
+
+func (t *PublishEventStreamTask) Run(ctx context.Context, param task.Param) (err error) {
+	// The publisher manages its own transactions,
+	// so that the records are not produced in a transaction.
+	return t.Publisher.Publish()
+}
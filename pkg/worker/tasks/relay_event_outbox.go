@@ -0,0 +1,26 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+)
+
+func ConfigureRelayEventOutboxTask(registry task.Registry, t task.Task) {
+	registry.Register(tasks.RelayEventOutbox, t)
+}
+
+type EventOutboxRelay interface {
+	Relay() error
+}
+
+type RelayEventOutboxTask struct {
+	Relay EventOutboxRelay
+}
+
+func (t *RelayEventOutboxTask) Run(ctx context.Context, param task.Param) (err error) {
+	// The relay manages its own transactions,
+	// and the attempts of each sink are tracked in the outbox.
+	return t.Relay.Relay()
+}
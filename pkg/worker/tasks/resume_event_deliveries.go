@@ -0,0 +1,38 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/authgear/authgear-server/pkg/lib/infra/task"
+	"github.com/authgear/authgear-server/pkg/lib/tasks"
+)
+
+func ConfigureResumeEventDeliveriesTask(registry task.Registry, t task.Task) {
+	registry.Register(tasks.ResumeEventDeliveries, t)
+}
+
+type WebhookDeliveryResumer interface {
+	ResumeDue() error
+}
+
+// ResumeEventDeliveriesTask resumes the deliveries whose next attempt is due.
+// It is run periodically for every app by the scheduler.
+type ResumeEventDeliveriesTask struct {
+	Deliveries WebhookDeliveryResumer
+	Relay      EventOutboxRelay
+	Publisher  EventStreamPublisher
+}
+
+func (t *ResumeEventDeliveriesTask) Run(ctx context.Context, param task.Param) (err error) {
+	err = t.Relay.Relay()
+	if err != nil {
+		return
+	}
+
+	err = t.Deliveries.ResumeDue()
+	if err != nil {
+		return
+	}
+
+	return t.Publisher.Publish()
+}
@@ -4,9 +4,12 @@
 package worker
 
 import (
+	"context"
+
 	"github.com/google/wire"
 
 	"github.com/authgear/authgear-server/pkg/lib/deps"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/globaldb"
 	"github.com/authgear/authgear-server/pkg/lib/infra/task"
 	"github.com/authgear/authgear-server/pkg/lib/infra/task/executor"
 	workertasks "github.com/authgear/authgear-server/pkg/worker/tasks"
@@ -19,6 +22,19 @@ func newInProcessExecutor(p *deps.RootProvider) *executor.InProcessExecutor {
 	))
 }
 
+func NewScheduler(p *deps.RootProvider, ctx context.Context, worker *Worker, apps SchedulerApps) *Scheduler {
+	panic(wire.Build(
+		deps.RootDependencySet,
+		wire.FieldsOf(new(*deps.RootProvider),
+			"DatabasePool",
+		),
+		NewSchedulerLogger,
+		wire.Struct(new(SchedulerStore), "*"),
+		wire.Struct(new(Scheduler), "*"),
+		wire.Bind(new(SchedulerDatabase), new(*globaldb.Handle)),
+	))
+}
+
 func newSendMessagesTask(p *deps.TaskProvider) task.Task {
 	panic(wire.Build(
 		DependencySet,
@@ -32,3 +48,38 @@ func newReindexUserTask(p *deps.TaskProvider) task.Task {
 		wire.Bind(new(task.Task), new(*workertasks.ReindexUserTask)),
 	))
 }
+
+func newDeliverWebhookTask(p *deps.TaskProvider) task.Task {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(task.Task), new(*workertasks.DeliverWebhookTask)),
+	))
+}
+
+func newPublishEventStreamTask(p *deps.TaskProvider) task.Task {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(task.Task), new(*workertasks.PublishEventStreamTask)),
+	))
+}
+
+func newRelayEventOutboxTask(p *deps.TaskProvider) task.Task {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(task.Task), new(*workertasks.RelayEventOutboxTask)),
+	))
+}
+
+func newResumeEventDeliveriesTask(p *deps.TaskProvider) task.Task {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(task.Task), new(*workertasks.ResumeEventDeliveriesTask)),
+	))
+}
+
+func newImportUsersTask(p *deps.TaskProvider) task.Task {
+	panic(wire.Build(
+		DependencySet,
+		wire.Bind(new(task.Task), new(*workertasks.ImportUsersTask)),
+	))
+}
@@ -7,12 +7,32 @@
 package worker
 
 import (
+	"context"
+	"github.com/authgear/authgear-server/pkg/lib/audit"
+	"github.com/authgear/authgear-server/pkg/lib/authn/authenticator/password"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity/anonymous"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity/biometric"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity/loginid"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity/oauth"
+	"github.com/authgear/authgear-server/pkg/lib/authn/identity/service"
+	"github.com/authgear/authgear-server/pkg/lib/authn/user"
 	"github.com/authgear/authgear-server/pkg/lib/deps"
 	"github.com/authgear/authgear-server/pkg/lib/elasticsearch"
+	"github.com/authgear/authgear-server/pkg/lib/event"
+	"github.com/authgear/authgear-server/pkg/lib/eventstream"
+	"github.com/authgear/authgear-server/pkg/lib/feature/customattrs"
+	"github.com/authgear/authgear-server/pkg/lib/feature/stdattrs"
+	"github.com/authgear/authgear-server/pkg/lib/feature/verification"
+	"github.com/authgear/authgear-server/pkg/lib/hook"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/appdb"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/auditdb"
+	"github.com/authgear/authgear-server/pkg/lib/infra/db/globaldb"
 	"github.com/authgear/authgear-server/pkg/lib/infra/mail"
 	"github.com/authgear/authgear-server/pkg/lib/infra/sms"
 	"github.com/authgear/authgear-server/pkg/lib/infra/task"
 	"github.com/authgear/authgear-server/pkg/lib/infra/task/executor"
+	"github.com/authgear/authgear-server/pkg/lib/userimport"
+	"github.com/authgear/authgear-server/pkg/util/clock"
 	"github.com/authgear/authgear-server/pkg/worker/tasks"
 )
 
@@ -29,6 +49,37 @@ func newInProcessExecutor(p *deps.RootProvider) *executor.InProcessExecutor {
 	return inProcessExecutor
 }
 
+func NewScheduler(p *deps.RootProvider, ctx context.Context, worker *Worker, apps SchedulerApps) *Scheduler {
+	factory := p.LoggerFactory
+	schedulerLogger := NewSchedulerLogger(factory)
+	clock := _wireSystemClockValue
+	pool := p.DatabasePool
+	environmentConfig := p.EnvironmentConfig
+	databaseEnvironmentConfig := &environmentConfig.Database
+	handle := globaldb.NewHandle(ctx, pool, databaseEnvironmentConfig, factory)
+	sqlBuilder := globaldb.NewSQLBuilder(databaseEnvironmentConfig)
+	sqlExecutor := globaldb.NewSQLExecutor(ctx, handle)
+	schedulerStore := &SchedulerStore{
+		Context:     ctx,
+		Handle:      handle,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	scheduler := &Scheduler{
+		Logger:   schedulerLogger,
+		Clock:    clock,
+		Database: handle,
+		Store:    schedulerStore,
+		Apps:     apps,
+		Worker:   worker,
+	}
+	return scheduler
+}
+
+var (
+	_wireSystemClockValue = clock.NewSystemClock()
+)
+
 func newSendMessagesTask(p *deps.TaskProvider) task.Task {
 	appProvider := p.AppProvider
 	factory := appProvider.LoggerFactory
@@ -82,3 +133,632 @@ func newReindexUserTask(p *deps.TaskProvider) task.Task {
 	}
 	return reindexUserTask
 }
+
+func newDeliverWebhookTask(p *deps.TaskProvider) task.Task {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	clockClock := _wireSystemClockValue
+	config := appProvider.Config
+	secretConfig := config.SecretConfig
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	handle := appProvider.AppDatabase
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	contextContext := p.Context
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	queue := appProvider.TaskQueue
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	deliverWebhookTask := &tasks.DeliverWebhookTask{
+		Deliveries: deliveryService,
+	}
+	return deliverWebhookTask
+}
+
+func newPublishEventStreamTask(p *deps.TaskProvider) task.Task {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	publisherLogger := eventstream.NewPublisherLogger(factory)
+	clockClock := _wireSystemClockValue
+	handle := appProvider.AppDatabase
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	contextContext := p.Context
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	store := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamredisHandle := appProvider.EventStreamRedis
+	httpClient := eventstream.NewHTTPClient()
+	producer := eventstream.NewProducer(appID, eventStreamCredentials, eventstreamredisHandle, httpClient)
+	queue := appProvider.TaskQueue
+	publisher := &eventstream.Publisher{
+		Logger:    publisherLogger,
+		Clock:     clockClock,
+		Database:  handle,
+		Store:     store,
+		Producer:  producer,
+		TaskQueue: queue,
+	}
+	publishEventStreamTask := &tasks.PublishEventStreamTask{
+		Publisher: publisher,
+	}
+	return publishEventStreamTask
+}
+
+func newRelayEventOutboxTask(p *deps.TaskProvider) task.Task {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	relayLogger := event.NewRelayLogger(factory)
+	clockClock := _wireSystemClockValue
+	handle := appProvider.AppDatabase
+	config := appProvider.Config
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	contextContext := p.Context
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	logger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	queue := appProvider.TaskQueue
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	userProfileConfig := appConfig.UserProfile
+	authenticationConfig := appConfig.Authentication
+	identityConfig := appConfig.Identity
+	featureConfig := config.FeatureConfig
+	identityFeatureConfig := featureConfig.Identity
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    logger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	relay := event.NewRelay(relayLogger, clockClock, handle, storeImpl, sink, auditSink, eventstreamSink, queue)
+	relayEventOutboxTask := &tasks.RelayEventOutboxTask{
+		Relay: relay,
+	}
+	return relayEventOutboxTask
+}
+
+func newResumeEventDeliveriesTask(p *deps.TaskProvider) task.Task {
+	appProvider := p.AppProvider
+	factory := appProvider.LoggerFactory
+	deliveryServiceLogger := hook.NewDeliveryServiceLogger(factory)
+	clockClock := _wireSystemClockValue
+	config := appProvider.Config
+	secretConfig := config.SecretConfig
+	webhookKeyMaterials := deps.ProvideWebhookKeyMaterials(secretConfig)
+	asyncHTTPClient := hook.NewAsyncHTTPClient()
+	webhookHandlerCredentials := deps.ProvideWebhookHandlerCredentials(secretConfig)
+	handle := appProvider.AppDatabase
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	contextContext := p.Context
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	deliveryStore := &hook.DeliveryStore{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	queue := appProvider.TaskQueue
+	deliveryService := &hook.DeliveryService{
+		Logger:      deliveryServiceLogger,
+		Clock:       clockClock,
+		Secret:      webhookKeyMaterials,
+		AsyncHTTP:   asyncHTTPClient,
+		Credentials: webhookHandlerCredentials,
+		Database:    handle,
+		Store:       deliveryStore,
+		TaskQueue:   queue,
+	}
+	relayLogger := event.NewRelayLogger(factory)
+	sqlBuilder := appdb.NewSQLBuilder(databaseCredentials)
+	storeImpl := &event.StoreImpl{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	logger := hook.NewLogger(factory)
+	hookConfig := appConfig.Hook
+	syncHTTPClient := hook.NewSyncHTTPClient(hookConfig)
+	userProfileConfig := appConfig.UserProfile
+	authenticationConfig := appConfig.Authentication
+	identityConfig := appConfig.Identity
+	featureConfig := config.FeatureConfig
+	identityFeatureConfig := featureConfig.Identity
+	store := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginIDConfig := identityConfig.LoginID
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 store,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	deliverer := &hook.Deliverer{
+		Config:             hookConfig,
+		Secret:             webhookKeyMaterials,
+		Clock:              clockClock,
+		SyncHTTP:           syncHTTPClient,
+		Deliveries:         deliveryService,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+		Resources:          manager,
+		Credentials:        webhookHandlerCredentials,
+	}
+	sink := &hook.Sink{
+		Logger:    logger,
+		Deliverer: deliverer,
+	}
+	auditLogger := audit.NewLogger(factory)
+	writeHandle := appProvider.AuditWriteDatabase
+	auditDatabaseCredentials := deps.ProvideAuditDatabaseCredentials(secretConfig)
+	auditdbSQLBuilderApp := auditdb.NewSQLBuilderApp(auditDatabaseCredentials, appID)
+	writeSQLExecutor := auditdb.NewWriteSQLExecutor(contextContext, writeHandle)
+	writeStore := &audit.WriteStore{
+		SQLBuilder:  auditdbSQLBuilderApp,
+		SQLExecutor: writeSQLExecutor,
+	}
+	auditSink := &audit.Sink{
+		Logger:   auditLogger,
+		Database: writeHandle,
+		Store:    writeStore,
+	}
+	eventStreamCredentials := deps.ProvideEventStreamCredentials(secretConfig)
+	eventstreamStore := &eventstream.Store{
+		AppID:       appID,
+		SQLBuilder:  sqlBuilder,
+		SQLExecutor: sqlExecutor,
+	}
+	eventstreamSink := &eventstream.Sink{
+		Credentials: eventStreamCredentials,
+		Clock:       clockClock,
+		Store:       eventstreamStore,
+		TaskQueue:   queue,
+	}
+	relay := event.NewRelay(relayLogger, clockClock, handle, storeImpl, sink, auditSink, eventstreamSink, queue)
+	publisherLogger := eventstream.NewPublisherLogger(factory)
+	eventstreamredisHandle := appProvider.EventStreamRedis
+	httpClient := eventstream.NewHTTPClient()
+	producer := eventstream.NewProducer(appID, eventStreamCredentials, eventstreamredisHandle, httpClient)
+	publisher := &eventstream.Publisher{
+		Logger:    publisherLogger,
+		Clock:     clockClock,
+		Database:  handle,
+		Store:     eventstreamStore,
+		Producer:  producer,
+		TaskQueue: queue,
+	}
+	resumeEventDeliveriesTask := &tasks.ResumeEventDeliveriesTask{
+		Deliveries: deliveryService,
+		Relay:      relay,
+		Publisher:  publisher,
+	}
+	return resumeEventDeliveriesTask
+}
+
+func newImportUsersTask(p *deps.TaskProvider) task.Task {
+	appProvider := p.AppProvider
+	handle := appProvider.AppDatabase
+	config := appProvider.Config
+	secretConfig := config.SecretConfig
+	databaseCredentials := deps.ProvideDatabaseCredentials(secretConfig)
+	appConfig := config.AppConfig
+	appID := appConfig.ID
+	sqlBuilderApp := appdb.NewSQLBuilderApp(databaseCredentials, appID)
+	contextContext := p.Context
+	sqlExecutor := appdb.NewSQLExecutor(contextContext, handle)
+	store := &userimport.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	identityConfig := appConfig.Identity
+	loginIDConfig := identityConfig.LoginID
+	oAuthSSOConfig := identityConfig.OAuth
+	clockClock := _wireSystemClockValue
+	userStore := &user.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+		Clock:       clockClock,
+	}
+	authenticationConfig := appConfig.Authentication
+	featureConfig := config.FeatureConfig
+	identityFeatureConfig := featureConfig.Identity
+	serviceStore := &service.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	loginidStore := &loginid.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	manager := appProvider.Resources
+	typeCheckerFactory := &loginid.TypeCheckerFactory{
+		Config:    loginIDConfig,
+		Resources: manager,
+	}
+	checker := &loginid.Checker{
+		Config:             loginIDConfig,
+		TypeCheckerFactory: typeCheckerFactory,
+	}
+	normalizerFactory := &loginid.NormalizerFactory{
+		Config: loginIDConfig,
+	}
+	provider := &loginid.Provider{
+		Store:             loginidStore,
+		Config:            loginIDConfig,
+		Checker:           checker,
+		NormalizerFactory: normalizerFactory,
+		Clock:             clockClock,
+	}
+	oauthStore := &oauth.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	oauthProvider := &oauth.Provider{
+		Store: oauthStore,
+		Clock: clockClock,
+	}
+	anonymousStore := &anonymous.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	anonymousProvider := &anonymous.Provider{
+		Store: anonymousStore,
+		Clock: clockClock,
+	}
+	biometricStore := &biometric.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	biometricProvider := &biometric.Provider{
+		Store: biometricStore,
+		Clock: clockClock,
+	}
+	serviceService := &service.Service{
+		Authentication:        authenticationConfig,
+		Identity:              identityConfig,
+		IdentityFeatureConfig: identityFeatureConfig,
+		Store:                 serviceStore,
+		LoginID:               provider,
+		OAuth:                 oauthProvider,
+		Anonymous:             anonymousProvider,
+		Biometric:             biometricProvider,
+	}
+	passwordStore := &password.Store{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	authenticatorConfig := appConfig.Authenticator
+	authenticatorPasswordConfig := authenticatorConfig.Password
+	factory := appProvider.LoggerFactory
+	logger := password.NewLogger(factory)
+	historyStore := &password.HistoryStore{
+		Clock:       clockClock,
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	breachedPasswordHTTPClient := password.NewBreachedPasswordHTTPClient()
+	passwordChecker := password.ProvideChecker(authenticatorPasswordConfig, historyStore, manager, breachedPasswordHTTPClient, logger)
+	hasher := password.ProvideHasher(authenticatorPasswordConfig)
+	housekeeperLogger := password.NewHousekeeperLogger(factory)
+	housekeeper := &password.Housekeeper{
+		Store:  historyStore,
+		Logger: housekeeperLogger,
+		Config: authenticatorPasswordConfig,
+	}
+	passwordProvider := &password.Provider{
+		Store:           passwordStore,
+		Config:          authenticatorPasswordConfig,
+		Clock:           clockClock,
+		Logger:          logger,
+		PasswordHistory: historyStore,
+		PasswordChecker: passwordChecker,
+		PasswordHasher:  hasher,
+		Housekeeper:     housekeeper,
+	}
+	storePQ := &verification.StorePQ{
+		SQLBuilder:  sqlBuilderApp,
+		SQLExecutor: sqlExecutor,
+	}
+	userProfileConfig := appConfig.UserProfile
+	rawQueries := &user.RawQueries{
+		Store: userStore,
+	}
+	serviceNoEvent := &stdattrs.ServiceNoEvent{
+		UserProfileConfig: userProfileConfig,
+		Identities:        serviceService,
+		UserQueries:       rawQueries,
+		UserStore:         userStore,
+		ClaimStore:        storePQ,
+	}
+	customattrsServiceNoEvent := &customattrs.ServiceNoEvent{
+		Config:      userProfileConfig,
+		UserQueries: rawQueries,
+		UserStore:   userStore,
+	}
+	importer := &userimport.Importer{
+		LoginIDConfig:      loginIDConfig,
+		OAuthConfig:        oAuthSSOConfig,
+		Clock:              clockClock,
+		Users:              userStore,
+		Identities:         serviceService,
+		Passwords:          passwordProvider,
+		VerifiedClaims:     storePQ,
+		StandardAttributes: serviceNoEvent,
+		CustomAttributes:   customattrsServiceNoEvent,
+	}
+	elasticsearchCredentials := deps.ProvideElasticsearchCredentials(secretConfig)
+	client := elasticsearch.NewClient(elasticsearchCredentials)
+	queue := appProvider.TaskQueue
+	elasticsearchService := &elasticsearch.Service{
+		AppID:     appID,
+		Client:    client,
+		Users:     userStore,
+		OAuth:     oauthStore,
+		LoginID:   loginidStore,
+		TaskQueue: queue,
+	}
+	runnerLogger := userimport.NewRunnerLogger(factory)
+	runner := &userimport.Runner{
+		Database:      handle,
+		Store:         store,
+		Importer:      importer,
+		SearchService: elasticsearchService,
+		Clock:         clockClock,
+		Logger:        runnerLogger,
+	}
+	importUsersTask := &tasks.ImportUsersTask{
+		Runner: runner,
+	}
+	return importUsersTask
+}
@@ -14,5 +14,10 @@ func NewWorker(provider *deps.RootProvider) *Worker {
 	executor := newInProcessExecutor(provider)
 	tasks.ConfigureSendMessagesTask(executor, provider.Task(newSendMessagesTask))
 	tasks.ConfigureReindexUserTask(executor, provider.Task(newReindexUserTask))
+	tasks.ConfigureDeliverWebhookTask(executor, provider.Task(newDeliverWebhookTask))
+	tasks.ConfigurePublishEventStreamTask(executor, provider.Task(newPublishEventStreamTask))
+	tasks.ConfigureRelayEventOutboxTask(executor, provider.Task(newRelayEventOutboxTask))
+	tasks.ConfigureResumeEventDeliveriesTask(executor, provider.Task(newResumeEventDeliveriesTask))
+	tasks.ConfigureImportUsersTask(executor, provider.Task(newImportUsersTask))
 	return &Worker{Executor: executor}
 }
@@ -26,3 +26,8 @@ declare type GQL_UserStandardAttributes = import("./types").StandardAttributes;
 
 // eslint-disable-next-line no-undef
 declare type GQL_UserCustomAttributes = import("./types").CustomAttributes;
+
+// eslint-disable-next-line no-undef
+declare type GQL_APIError = import("./error/error").APIError;
+
+declare type GQL_HookResponse = Record<string, unknown>;
@@ -13,9 +13,11 @@ export enum AuditLogActivityType {
   AUTHENTICATION_IDENTITY_LOGIN_ID_FAILED = "AUTHENTICATION_IDENTITY_LOGIN_ID_FAILED",
   AUTHENTICATION_PRIMARY_OOB_OTP_EMAIL_FAILED = "AUTHENTICATION_PRIMARY_OOB_OTP_EMAIL_FAILED",
   AUTHENTICATION_PRIMARY_OOB_OTP_SMS_FAILED = "AUTHENTICATION_PRIMARY_OOB_OTP_SMS_FAILED",
+  AUTHENTICATION_PRIMARY_PASSKEY_FAILED = "AUTHENTICATION_PRIMARY_PASSKEY_FAILED",
   AUTHENTICATION_PRIMARY_PASSWORD_FAILED = "AUTHENTICATION_PRIMARY_PASSWORD_FAILED",
   AUTHENTICATION_SECONDARY_OOB_OTP_EMAIL_FAILED = "AUTHENTICATION_SECONDARY_OOB_OTP_EMAIL_FAILED",
   AUTHENTICATION_SECONDARY_OOB_OTP_SMS_FAILED = "AUTHENTICATION_SECONDARY_OOB_OTP_SMS_FAILED",
+  AUTHENTICATION_SECONDARY_PASSKEY_FAILED = "AUTHENTICATION_SECONDARY_PASSKEY_FAILED",
   AUTHENTICATION_SECONDARY_PASSWORD_FAILED = "AUTHENTICATION_SECONDARY_PASSWORD_FAILED",
   AUTHENTICATION_SECONDARY_RECOVERY_CODE_FAILED = "AUTHENTICATION_SECONDARY_RECOVERY_CODE_FAILED",
   AUTHENTICATION_SECONDARY_TOTP_FAILED = "AUTHENTICATION_SECONDARY_TOTP_FAILED",
@@ -47,6 +49,7 @@ export enum AuthenticatorKind {
 export enum AuthenticatorType {
   OOB_OTP_EMAIL = "OOB_OTP_EMAIL",
   OOB_OTP_SMS = "OOB_OTP_SMS",
+  PASSKEY = "PASSKEY",
   PASSWORD = "PASSWORD",
   TOTP = "TOTP",
 }
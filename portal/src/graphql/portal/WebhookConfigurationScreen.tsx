@@ -100,6 +100,11 @@ function constructConfig(
 const blockingEventTypes: IDropdownOption[] = [
   "user.pre_create",
   "user.profile.pre_update",
+  "user.pre_authenticate",
+  "identity.pre_create",
+  "identity.pre_delete",
+  "oauth.pre_token_issue",
+  "oidc.jwt.pre_create",
 ].map((type): IDropdownOption => ({ key: type, text: type }));
 
 interface BlockingHandlerItemEditProps {
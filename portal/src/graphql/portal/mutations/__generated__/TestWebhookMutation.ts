@@ -0,0 +1,41 @@
+/* tslint:disable */
+/* eslint-disable */
+// @generated
+// This file was automatically generated and should not be edited.
+
+// ====================================================
+// GraphQL mutation operation: TestWebhookMutation
+// ====================================================
+
+export interface TestWebhookMutation_testWebhook {
+  __typename: "TestWebhookPayload";
+  /**
+   * HTTP status code of the response. It is null if no response is received, or the hook handler is a hook script.
+   */
+  statusCode: number | null;
+  /**
+   * Time taken to deliver the event in milliseconds.
+   */
+  durationMs: number;
+  /**
+   * The parsed hook response. It is null for non-blocking events, or if the delivery failed.
+   */
+  response: GQL_HookResponse | null;
+  /**
+   * The reason of the failed delivery, e.g. invalid hook response.
+   */
+  error: GQL_APIError | null;
+}
+
+export interface TestWebhookMutation {
+  /**
+   * Deliver a sample event to a hook handler
+   */
+  testWebhook: TestWebhookMutation_testWebhook;
+}
+
+export interface TestWebhookMutationVariables {
+  appID: string;
+  url: string;
+  eventType: string;
+}
@@ -0,0 +1,55 @@
+import { useCallback } from "react";
+import { gql, useMutation } from "@apollo/client";
+import { client } from "../../portal/apollo";
+import {
+  TestWebhookMutation,
+  TestWebhookMutation_testWebhook,
+} from "./__generated__/TestWebhookMutation";
+
+const testWebhookMutation = gql`
+  mutation TestWebhookMutation(
+    $appID: ID!
+    $url: String!
+    $eventType: String!
+  ) {
+    testWebhook(input: { appID: $appID, url: $url, eventType: $eventType }) {
+      statusCode
+      durationMs
+      response
+      error
+    }
+  }
+`;
+
+export interface TestWebhookOptions {
+  url: string;
+  eventType: string;
+}
+
+export interface UseTestWebhookMutationReturnType {
+  testWebhook: (
+    opts: TestWebhookOptions
+  ) => Promise<TestWebhookMutation_testWebhook | null>;
+  loading: boolean;
+  error: unknown;
+}
+
+export function useTestWebhookMutation(
+  appID: string
+): UseTestWebhookMutationReturnType {
+  const [mutationFunction, { error, loading }] =
+    useMutation<TestWebhookMutation>(testWebhookMutation, { client });
+  const testWebhook = useCallback(
+    async (options: TestWebhookOptions) => {
+      const result = await mutationFunction({
+        variables: {
+          ...options,
+          appID,
+        },
+      });
+      return result.data?.testWebhook ?? null;
+    },
+    [mutationFunction, appID]
+  );
+  return { testWebhook, error, loading };
+}
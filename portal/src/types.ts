@@ -169,11 +169,42 @@ export interface PasswordPolicyConfig {
   excluded_keywords?: string[];
   history_size?: number;
   history_days?: number;
+  breached_password_check?: BreachedPasswordCheckConfig;
+}
+
+export type BreachedPasswordCheckFailureMode = "open" | "closed";
+
+export interface BreachedPasswordCheckConfig {
+  enabled?: boolean;
+  api_enabled?: boolean;
+  api_endpoint?: string;
+  failure_mode?: BreachedPasswordCheckFailureMode;
+  force_change_on_login?: boolean;
+}
+
+export type PasswordHashingAlgorithm = "bcrypt_sha512" | "argon2id" | "scrypt";
+
+export interface PasswordHashingConfig {
+  algorithm?: PasswordHashingAlgorithm;
+  bcrypt_sha512?: {
+    cost?: number;
+  };
+  argon2id?: {
+    memory_kib?: number;
+    iterations?: number;
+    parallelism?: number;
+  };
+  scrypt?: {
+    log_n?: number;
+    block_size?: number;
+    parallelism?: number;
+  };
 }
 
 export interface AuthenticatorPasswordConfig {
   force_change?: boolean;
   policy?: PasswordPolicyConfig;
+  hashing?: PasswordHashingConfig;
 }
 
 export interface AuthenticatorConfig {